@@ -1,14 +1,33 @@
 package main
 
 import (
+	"context"
 	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
 
 	_ "github.com/garoque/backend-code-challenge-snapfi/docs"
 	"github.com/garoque/backend-code-challenge-snapfi/internal/api"
+	apiaudit "github.com/garoque/backend-code-challenge-snapfi/internal/api/audit"
 	"github.com/garoque/backend-code-challenge-snapfi/internal/app"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/app/account"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/app/audit"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/app/balance"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/app/fee"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/app/idempotency"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/app/ledger"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/app/limit"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/app/outbox"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/app/standingorder"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/app/stream"
 	"github.com/garoque/backend-code-challenge-snapfi/internal/app/transaction"
 	"github.com/garoque/backend-code-challenge-snapfi/internal/app/user"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/app/webhook"
 	"github.com/garoque/backend-code-challenge-snapfi/internal/database"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/publisher"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/scheduler"
 	"github.com/garoque/backend-code-challenge-snapfi/pkg/validator"
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/jmoiron/sqlx"
@@ -41,13 +60,140 @@ func main() {
 	e.Validator = validator.NewValidator()
 	e.Use(middleware.Logger())
 	e.Use(middleware.Recover())
+	e.Use(middleware.RequestID())
+	e.Use(apiaudit.Middleware())
 
 	db := database.New(connDb)
 
-	api.Register(e.Group("/v1"), &app.Container{
-		User:        user.NewAppUser(db),
-		Transaction: transaction.NewAppTransaction(db),
+	retryPolicy := standingorder.RetryPolicy{
+		MaxRetries: intFromEnv("STANDING_ORDER_MAX_RETRIES", standingorder.DefaultRetryPolicy.MaxRetries),
+		Interval:   durationFromEnv("STANDING_ORDER_RETRY_INTERVAL", standingorder.DefaultRetryPolicy.Interval),
+	}
+
+	webhookApp := webhook.NewAppWebhook(db, &http.Client{Timeout: durationFromEnv("WEBHOOK_TIMEOUT", 10*time.Second)}, webhook.RetryPolicy{
+		MaxAttempts: intFromEnv("WEBHOOK_MAX_ATTEMPTS", webhook.DefaultRetryPolicy.MaxAttempts),
+		BaseDelay:   durationFromEnv("WEBHOOK_RETRY_BASE_DELAY", webhook.DefaultRetryPolicy.BaseDelay),
+		MaxDelay:    durationFromEnv("WEBHOOK_RETRY_MAX_DELAY", webhook.DefaultRetryPolicy.MaxDelay),
 	})
 
+	// Events are queued for the webhooks subscribed to them and, when
+	// OUTBOX_FILE names a file, appended to it as NATS messages.
+	eventPublisher := publisher.NewMemory()
+	eventPublisher.Subscribe(webhookApp.Enqueue)
+	if path, ok := os.LookupEnv("OUTBOX_FILE"); ok {
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		defer file.Close()
+
+		eventPublisher.Subscribe(publisher.NewNATS(file).Publish)
+	}
+
+	appContainer := &app.Container{
+		User:          user.NewAppUser(db),
+		Account:       account.NewAppAccount(db),
+		Transaction:   transaction.NewAppTransaction(db),
+		Idempotency:   idempotency.NewAppIdempotency(db, durationFromEnv("IDEMPOTENCY_KEY_TTL", idempotency.DefaultTTL), durationFromEnv("IDEMPOTENCY_KEY_LEASE", idempotency.DefaultLease)),
+		Ledger:        ledger.NewAppLedger(db),
+		StandingOrder: standingorder.NewAppStandingOrder(db, retryPolicy),
+		Fee:           fee.NewAppFee(db),
+		Limit:         limit.NewAppLimit(db),
+		Balance:       balance.NewAppBalance(db),
+		Outbox:        outbox.NewAppOutbox(db, eventPublisher),
+		Webhook:       webhookApp,
+		Stream:        stream.NewAppStream(db, durationFromEnv("STREAM_HEARTBEAT_INTERVAL", stream.DefaultHeartbeat)),
+		Audit:         audit.NewAppAudit(db),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	jobs := scheduler.New(
+		scheduler.Job{
+			Name:     "ExpireAuthorizations",
+			Interval: durationFromEnv("AUTHORIZATION_EXPIRY_INTERVAL", time.Minute),
+			Run:      appContainer.Transaction.ExpireAuthorizations,
+		},
+		scheduler.Job{
+			Name:     "ExecuteScheduled",
+			Interval: durationFromEnv("SCHEDULED_TRANSFERS_INTERVAL", time.Minute),
+			Run:      appContainer.Transaction.ExecuteScheduled,
+		},
+		scheduler.Job{
+			Name:     "ExecuteStandingOrders",
+			Interval: durationFromEnv("STANDING_ORDERS_INTERVAL", time.Minute),
+			Run:      appContainer.StandingOrder.Execute,
+		},
+		scheduler.Job{
+			Name:     "AccrueInterest",
+			Interval: durationFromEnv("INTEREST_ACCRUAL_INTERVAL", time.Hour),
+			Run:      appContainer.Transaction.AccrueInterest,
+		},
+		scheduler.Job{
+			Name:     "TakeBalanceSnapshots",
+			Interval: durationFromEnv("BALANCE_SNAPSHOT_INTERVAL", time.Hour),
+			Run:      appContainer.Balance.TakeSnapshots,
+		},
+		scheduler.Job{
+			Name:     "RelayOutbox",
+			Interval: durationFromEnv("OUTBOX_RELAY_INTERVAL", time.Second),
+			Run:      appContainer.Outbox.Relay,
+		},
+		scheduler.Job{
+			Name:     "DeliverWebhooks",
+			Interval: durationFromEnv("WEBHOOK_DELIVERY_INTERVAL", 5*time.Second),
+			Run:      appContainer.Webhook.Deliver,
+		},
+		scheduler.Job{
+			Name:     "PollEventStreams",
+			Interval: durationFromEnv("STREAM_POLL_INTERVAL", 500*time.Millisecond),
+			Run:      appContainer.Stream.Poll,
+		},
+		scheduler.Job{
+			Name:     "ReconcileBalances",
+			Interval: durationFromEnv("RECONCILIATION_INTERVAL", 24*time.Hour),
+			Run: func(ctx context.Context) error {
+				_, err := appContainer.Ledger.Reconcile(ctx)
+				return err
+			},
+		},
+	)
+	jobs.Start(ctx)
+
+	api.Register(e.Group("/v1"), appContainer)
+
 	e.Logger.Fatal(e.Start(":1323"))
 }
+
+// durationFromEnv reads a duration such as "24h" from the environment,
+// falling back to def when the variable is unset.
+func durationFromEnv(name string, def time.Duration) time.Duration {
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return def
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		log.Fatalf("Invalid %s: %s", name, err.Error())
+	}
+
+	return duration
+}
+
+// intFromEnv reads an integer from the environment, falling back to def when
+// the variable is unset.
+func intFromEnv(name string, def int) int {
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return def
+	}
+
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		log.Fatalf("Invalid %s: %s", name, err.Error())
+	}
+
+	return number
+}
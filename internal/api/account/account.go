@@ -0,0 +1,78 @@
+package account
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/garoque/backend-code-challenge-snapfi/internal/api/dto"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/app"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	"github.com/labstack/echo/v4"
+)
+
+func Register(router *echo.Group, app *app.Container) {
+	h := &handler{app}
+
+	router.POST("", h.create)
+	router.GET("/:id", h.readOne)
+}
+
+type handler struct {
+	app *app.Container
+}
+
+// Create account godoc
+// @Summary Create account
+// @Description Open another account for the user, a PERSONAL one in BRL unless accountType and currency say otherwise. Closed users can't open accounts.
+// @Tags account
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateAccount true "account request"
+// @Success 201 {object} entity.Account
+// @Failure 400 {object} error
+// @Failure 404 {object} error
+// @Failure 409 {object} error
+// @Failure 500 {object} error
+// @Router /account [post]
+func (h *handler) create(c echo.Context) error {
+	var request dto.CreateAccount
+	if err := c.Bind(&request); err != nil {
+		return echo.ErrInternalServerError
+	}
+
+	if err := c.Validate(&request); err != nil {
+		return echo.ErrBadRequest
+	}
+
+	account, err := h.app.Account.Create(c.Request().Context(), *entity.NewAccount(request))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, dto.Response{Data: account})
+}
+
+// Read one account godoc
+// @Summary Read one account
+// @Description Read one account, the default account of a user sharing their ID
+// @Tags account
+// @Accept json
+// @Produce json
+// @Param id path string true "account ID" Format(uuid)
+// @Success 200 {object} entity.Account
+// @Failure 400 {object} error
+// @Failure 404 {object} error
+// @Router /account/{id} [get]
+func (h *handler) readOne(c echo.Context) error {
+	accountId := c.Param("id")
+	if strings.Trim(accountId, " ") == "" {
+		return echo.NewHTTPError(echo.ErrBadRequest.Code, "The provided ID is empty")
+	}
+
+	account, err := h.app.Account.ReadOneById(c.Request().Context(), accountId)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, dto.Response{Data: account})
+}
@@ -0,0 +1,168 @@
+package account
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/garoque/backend-code-challenge-snapfi/internal/api/dto"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/app"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/mocks"
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/money"
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/validator"
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+var account = &entity.Account{
+	ID:                "account-id",
+	UserId:            "user-id",
+	Name:              "MEI",
+	AccountTypeString: "BUSINESS",
+	Currency:          "USD",
+	Balance:           money.New(0),
+	AvailableBalance:  money.New(0),
+}
+
+func assertResponse(t *testing.T, rec *httptest.ResponseRecorder, data interface{}) {
+	expectedResultJSON, err := json.Marshal(dto.Response{Data: data})
+	assert.NoError(t, err)
+
+	assert.JSONEq(t, string(expectedResultJSON), rec.Body.String())
+}
+
+func TestCreate(t *testing.T) {
+	cases := map[string]struct {
+		InputRequest string
+		ExpectedErr  error
+		PrepareMock  func(mockAccountApp *mocks.MockAppAccountInterface)
+	}{
+		"deve retornar sucesso": {
+			InputRequest: `{"userId": "user-id", "name": "MEI", "accountType": "BUSINESS", "currency": "USD"}`,
+			ExpectedErr:  nil,
+			PrepareMock: func(mockAccountApp *mocks.MockAppAccountInterface) {
+				mockAccountApp.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).
+					Do(func(ctx context.Context, account entity.Account) {
+						if account.UserId != "user-id" || account.Name != "MEI" || account.AccountType != entity.BUSINESS || account.Currency != "USD" {
+							t.Errorf("unexpected account %+v", account)
+						}
+					}).Return(account, nil)
+			},
+		},
+		"deve retornar erro: moeda invalida": {
+			InputRequest: `{"userId": "user-id", "name": "MEI", "currency": "usd"}`,
+			ExpectedErr:  echo.ErrBadRequest,
+			PrepareMock:  func(mockAccountApp *mocks.MockAppAccountInterface) {},
+		},
+		"deve retornar erro: sem nome": {
+			InputRequest: `{"userId": "user-id"}`,
+			ExpectedErr:  echo.ErrBadRequest,
+			PrepareMock:  func(mockAccountApp *mocks.MockAppAccountInterface) {},
+		},
+		"deve retornar erro": {
+			InputRequest: `{"userId": "user-id", "name": "MEI"}`,
+			ExpectedErr:  echo.ErrNotFound,
+			PrepareMock: func(mockAccountApp *mocks.MockAppAccountInterface) {
+				mockAccountApp.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil, echo.ErrNotFound)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			mockAccountApp := mocks.NewMockAppAccountInterface(ctrl)
+			cs.PrepareMock(mockAccountApp)
+
+			api := handler{
+				app: &app.Container{Account: mockAccountApp},
+			}
+
+			e := echo.New()
+			e.Validator = validator.NewValidator()
+
+			endpoint := "/v1/account"
+
+			req := httptest.NewRequest(http.MethodPost, endpoint, strings.NewReader(cs.InputRequest)).WithContext(ctx)
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetPath(endpoint)
+
+			err := api.create(c)
+			assert.Equal(t, cs.ExpectedErr, err)
+
+			if err == nil {
+				assert.Equal(t, http.StatusCreated, rec.Code)
+				assertResponse(t, rec, account)
+			}
+		})
+	}
+}
+
+func TestReadOne(t *testing.T) {
+	cases := map[string]struct {
+		InputAccountId string
+		ExpectedErr    error
+		PrepareMock    func(mockAccountApp *mocks.MockAppAccountInterface)
+	}{
+		"deve retornar sucesso": {
+			InputAccountId: "account-id",
+			ExpectedErr:    nil,
+			PrepareMock: func(mockAccountApp *mocks.MockAppAccountInterface) {
+				mockAccountApp.EXPECT().ReadOneById(gomock.Any(), "account-id").Times(1).Return(account, nil)
+			},
+		},
+		"deve retornar erro": {
+			InputAccountId: "account-id",
+			ExpectedErr:    echo.ErrNotFound,
+			PrepareMock: func(mockAccountApp *mocks.MockAppAccountInterface) {
+				mockAccountApp.EXPECT().ReadOneById(gomock.Any(), "account-id").Times(1).Return(nil, echo.ErrNotFound)
+			},
+		},
+		"deve retornar erro: 'The provided ID is empty'": {
+			InputAccountId: "",
+			ExpectedErr:    echo.NewHTTPError(echo.ErrBadRequest.Code, "The provided ID is empty"),
+			PrepareMock:    func(mockAccountApp *mocks.MockAppAccountInterface) {},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			mockAccountApp := mocks.NewMockAppAccountInterface(ctrl)
+			cs.PrepareMock(mockAccountApp)
+
+			api := handler{
+				app: &app.Container{Account: mockAccountApp},
+			}
+
+			e := echo.New()
+
+			endpoint := "/v1/account/:id"
+			req := httptest.NewRequest(http.MethodGet, endpoint, nil).WithContext(ctx)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetPath(endpoint)
+			c.SetParamNames("id")
+			c.SetParamValues(cs.InputAccountId)
+
+			err := api.readOne(c)
+			assert.Equal(t, cs.ExpectedErr, err)
+
+			if err == nil {
+				assertResponse(t, rec, account)
+			}
+		})
+	}
+}
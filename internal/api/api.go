@@ -1,15 +1,29 @@
 package api
 
 import (
+	"github.com/garoque/backend-code-challenge-snapfi/internal/api/account"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/api/audit"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/api/fee"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/api/ledger"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/api/limit"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/api/standingorder"
 	"github.com/garoque/backend-code-challenge-snapfi/internal/api/swagger"
 	"github.com/garoque/backend-code-challenge-snapfi/internal/api/transaction"
 	"github.com/garoque/backend-code-challenge-snapfi/internal/api/user"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/api/webhook"
 	"github.com/garoque/backend-code-challenge-snapfi/internal/app"
 	"github.com/labstack/echo/v4"
 )
 
 func Register(router *echo.Group, app *app.Container) {
 	user.Register(router.Group("/user"), app)
+	account.Register(router.Group("/account"), app)
 	transaction.Register(router.Group("/transaction"), app)
+	ledger.Register(router.Group("/ledger"), app)
+	standingorder.Register(router.Group("/standing-order"), app)
+	fee.Register(router.Group("/fee-rule"), app)
+	limit.Register(router.Group("/limit"), app)
+	webhook.Register(router.Group("/webhook"), app)
+	audit.Register(router.Group("/audit"), app)
 	swagger.Register(router.Group("/swagger"))
 }
@@ -0,0 +1,147 @@
+package audit
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/garoque/backend-code-challenge-snapfi/internal/api/dto"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/app"
+	appaudit "github.com/garoque/backend-code-challenge-snapfi/internal/app/audit"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	"github.com/labstack/echo/v4"
+)
+
+// HeaderActor names who sends the request, such as the operator or the service
+// acting on behalf of a user.
+const HeaderActor = "X-Actor"
+
+const maxActorLength = 255
+
+func Register(router *echo.Group, app *app.Container) {
+	h := &handler{app}
+
+	router.GET("", h.readAll)
+}
+
+// Middleware puts who sent the request in its context, so the changes made
+// for it are recorded in the audit log under their name: the X-Actor header,
+// the request ID set by the RequestID middleware and the client IP. Requests
+// without the header are recorded as anonymous.
+func Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			actor := c.Request().Header.Get(HeaderActor)
+			if actor == "" {
+				actor = entity.AUDIT_ANONYMOUS_ACTOR
+			}
+
+			if len(actor) > maxActorLength {
+				return echo.NewHTTPError(echo.ErrBadRequest.Code, "X-Actor is too long")
+			}
+
+			requestId := c.Response().Header().Get(echo.HeaderXRequestID)
+			if requestId == "" {
+				requestId = c.Request().Header.Get(echo.HeaderXRequestID)
+			}
+
+			ctx := appaudit.WithRequest(c.Request().Context(), entity.AuditRequest{
+				Actor:     actor,
+				RequestId: requestId,
+				ClientIP:  c.RealIP(),
+			})
+			c.SetRequest(c.Request().WithContext(ctx))
+
+			return next(c)
+		}
+	}
+}
+
+type handler struct {
+	app *app.Container
+}
+
+// Read audit log godoc
+// @Summary Read audit log
+// @Description Read who changed what and when, newest first: the actor, request ID and client IP of every change to users, accounts, balances, transactions, fee rules, limits, standing orders, webhooks and reconciliations, with the entity before and after it.
+// @Tags audit
+// @Accept json
+// @Produce json
+// @Param actor query string false "only the changes made by the actor" example(jane.doe)
+// @Param entityType query string false "only the changes to entities of the type" example(user)
+// @Param entityId query string false "only the changes to the entity"
+// @Param from query string false "RFC 3339 timestamp or date, included" example(2023-05-01)
+// @Param to query string false "RFC 3339 timestamp or date, included" example(2023-05-31)
+// @Param limit query int false "how many entries to read, 100 by default and 1000 at most"
+// @Success 200 {array} entity.AuditEntry
+// @Failure 400 {object} error
+// @Failure 500 {object} error
+// @Router /audit [get]
+func (h *handler) readAll(c echo.Context) error {
+	filter := entity.AuditFilter{
+		Actor:      c.QueryParam("actor"),
+		EntityType: c.QueryParam("entityType"),
+		EntityId:   c.QueryParam("entityId"),
+	}
+
+	if value := c.QueryParam("from"); value != "" {
+		from, err := parseTime(value)
+		if err != nil {
+			return echo.NewHTTPError(echo.ErrBadRequest.Code, "The provided from is not a valid timestamp")
+		}
+		filter.From = &from
+	}
+
+	if value := c.QueryParam("to"); value != "" {
+		to, err := parseEnd(value)
+		if err != nil {
+			return echo.NewHTTPError(echo.ErrBadRequest.Code, "The provided to is not a valid timestamp")
+		}
+		filter.To = &to
+	}
+
+	if filter.From != nil && filter.To != nil && !filter.To.After(*filter.From) {
+		return echo.NewHTTPError(echo.ErrBadRequest.Code, "The to date must not be before the from date")
+	}
+
+	if value := c.QueryParam("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > appaudit.MaxLimit {
+			return echo.NewHTTPError(echo.ErrBadRequest.Code, "The limit must be between 1 and "+strconv.Itoa(appaudit.MaxLimit))
+		}
+		filter.Limit = limit
+	}
+
+	entries, err := h.app.Audit.ReadAll(c.Request().Context(), filter)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, dto.Response{Data: entries})
+}
+
+// parseTime reads an RFC 3339 timestamp or a date, taken as its midnight in
+// the server's time zone.
+func parseTime(value string) (time.Time, error) {
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+
+	return time.Parse(time.RFC3339, value)
+}
+
+// parseEnd reads the end of a period as parseTime does, returning the instant
+// right after it: the next midnight for a date, the next second for a
+// timestamp, since entries are stored to the second.
+func parseEnd(value string) (time.Time, error) {
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t.AddDate(0, 0, 1), nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return t.Truncate(time.Second).Add(time.Second), nil
+}
@@ -0,0 +1,161 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/garoque/backend-code-challenge-snapfi/internal/api/dto"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/app"
+	appaudit "github.com/garoque/backend-code-challenge-snapfi/internal/app/audit"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+var entries = []entity.AuditEntry{{
+	ID:         "entry-id",
+	Actor:      "jane.doe",
+	RequestId:  "request-id",
+	ClientIP:   "203.0.113.7",
+	Operation:  entity.AUDIT_USER_CREATED,
+	EntityType: entity.AGGREGATE_USER,
+	EntityId:   "user-id",
+	CreatedAt:  time.Date(2023, 5, 23, 9, 0, 0, 0, time.UTC),
+}}
+
+func TestReadAll(t *testing.T) {
+	from := time.Date(2023, 5, 1, 0, 0, 0, 0, time.Local)
+	to := time.Date(2023, 6, 1, 0, 0, 0, 0, time.Local)
+
+	cases := map[string]struct {
+		Query       string
+		ExpectedErr error
+		PrepareMock func(mockAuditApp *mocks.MockAppAuditInterface)
+	}{
+		"deve retornar sucesso": {
+			Query:       "",
+			ExpectedErr: nil,
+			PrepareMock: func(mockAuditApp *mocks.MockAppAuditInterface) {
+				mockAuditApp.EXPECT().ReadAll(gomock.Any(), entity.AuditFilter{}).Times(1).Return(entries, nil)
+			},
+		},
+		"deve retornar sucesso: com filtros": {
+			Query:       "?actor=jane.doe&entityType=user&entityId=user-id&from=2023-05-01&to=2023-05-31&limit=10",
+			ExpectedErr: nil,
+			PrepareMock: func(mockAuditApp *mocks.MockAppAuditInterface) {
+				filter := entity.AuditFilter{Actor: "jane.doe", EntityType: "user", EntityId: "user-id", From: &from, To: &to, Limit: 10}
+				mockAuditApp.EXPECT().ReadAll(gomock.Any(), filter).Times(1).Return(entries, nil)
+			},
+		},
+		"deve retornar erro: from invalido": {
+			Query:       "?from=yesterday",
+			ExpectedErr: echo.NewHTTPError(echo.ErrBadRequest.Code, "The provided from is not a valid timestamp"),
+			PrepareMock: func(mockAuditApp *mocks.MockAppAuditInterface) {},
+		},
+		"deve retornar erro: to antes de from": {
+			Query:       "?from=2023-05-31&to=2023-05-01",
+			ExpectedErr: echo.NewHTTPError(echo.ErrBadRequest.Code, "The to date must not be before the from date"),
+			PrepareMock: func(mockAuditApp *mocks.MockAppAuditInterface) {},
+		},
+		"deve retornar erro: limite invalido": {
+			Query:       "?limit=5000",
+			ExpectedErr: echo.NewHTTPError(echo.ErrBadRequest.Code, "The limit must be between 1 and 1000"),
+			PrepareMock: func(mockAuditApp *mocks.MockAppAuditInterface) {},
+		},
+		"deve retornar erro": {
+			Query:       "?actor=jane.doe",
+			ExpectedErr: echo.ErrInternalServerError,
+			PrepareMock: func(mockAuditApp *mocks.MockAppAuditInterface) {
+				mockAuditApp.EXPECT().ReadAll(gomock.Any(), entity.AuditFilter{Actor: "jane.doe"}).Times(1).Return(nil, echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			mockAuditApp := mocks.NewMockAppAuditInterface(ctrl)
+			cs.PrepareMock(mockAuditApp)
+
+			api := handler{
+				app: &app.Container{Audit: mockAuditApp},
+			}
+
+			e := echo.New()
+
+			endpoint := "/v1/audit"
+
+			req := httptest.NewRequest(http.MethodGet, endpoint+cs.Query, nil).WithContext(ctx)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetPath(endpoint)
+
+			err := api.readAll(c)
+			assert.Equal(t, cs.ExpectedErr, err)
+
+			if err == nil {
+				var result dto.Response
+				json.NewDecoder(rec.Body).Decode(&result)
+				assert.Len(t, result.Data, 1)
+			}
+		})
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	cases := map[string]struct {
+		Headers         map[string]string
+		ExpectedRequest entity.AuditRequest
+		ExpectedErr     error
+	}{
+		"deve retornar sucesso": {
+			Headers: map[string]string{
+				HeaderActor:              "jane.doe",
+				echo.HeaderXRequestID:    "request-id",
+				echo.HeaderXForwardedFor: "203.0.113.7",
+			},
+			ExpectedRequest: entity.AuditRequest{Actor: "jane.doe", RequestId: "request-id", ClientIP: "203.0.113.7"},
+		},
+		"deve retornar sucesso: sem actor": {
+			Headers:         map[string]string{echo.HeaderXForwardedFor: "203.0.113.7"},
+			ExpectedRequest: entity.AuditRequest{Actor: entity.AUDIT_ANONYMOUS_ACTOR, ClientIP: "203.0.113.7"},
+		},
+		"deve retornar erro: actor muito longo": {
+			Headers:     map[string]string{HeaderActor: string(make([]byte, 256))},
+			ExpectedErr: echo.NewHTTPError(echo.ErrBadRequest.Code, "X-Actor is too long"),
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := echo.New()
+
+			req := httptest.NewRequest(http.MethodPost, "/v1/user", nil)
+			for key, value := range cs.Headers {
+				req.Header.Set(key, value)
+			}
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+
+			var request entity.AuditRequest
+			err := Middleware()(func(c echo.Context) error {
+				request = appaudit.RequestFromContext(c.Request().Context())
+				return nil
+			})(c)
+			assert.Equal(t, cs.ExpectedErr, err)
+
+			if err == nil {
+				assert.Equal(t, cs.ExpectedRequest, request)
+			}
+		})
+	}
+}
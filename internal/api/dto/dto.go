@@ -1,21 +1,196 @@
 package dto
 
+import (
+	"time"
+
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/money"
+)
+
 type Response struct {
 	Data interface{} `json:"data,omitempty"`
 	Err  error       `json:"error,omitempty"`
 }
 
+// CreateUser creates a PERSONAL account unless AccountType says otherwise.
 type CreateUser struct {
-	Name string `json:"name" validate:"required"`
+	Name        string `json:"name" validate:"required"`
+	AccountType string `json:"accountType,omitempty" validate:"omitempty,oneof=PERSONAL BUSINESS" example:"PERSONAL"`
 }
 
+// CreateAccount opens another account for the user, a PERSONAL one in BRL
+// unless AccountType and Currency say otherwise.
+type CreateAccount struct {
+	UserId      string `json:"userId" validate:"required"`
+	Name        string `json:"name" validate:"required,max=80" example:"MEI"`
+	AccountType string `json:"accountType,omitempty" validate:"omitempty,oneof=PERSONAL BUSINESS" example:"BUSINESS"`
+	Currency    string `json:"currency,omitempty" validate:"omitempty,len=3,alpha,uppercase" example:"BRL"`
+}
+
+// CreateTransaction books the transfer right away, or schedules it when
+// ExecuteAt is set. Each side is either an account or a user, standing for
+// their default account.
 type CreateTransaction struct {
-	SourceUserId      string  `json:"sourceUserId" validate:"required"`
-	DestinationUserId string  `json:"destinationUserId" validate:"required"`
-	Amount            float64 `json:"amount" validate:"required"`
+	SourceUserId         string      `json:"sourceUserId,omitempty" validate:"required_without=SourceAccountId,excluded_with=SourceAccountId"`
+	SourceAccountId      string      `json:"sourceAccountId,omitempty"`
+	DestinationUserId    string      `json:"destinationUserId,omitempty" validate:"required_without=DestinationAccountId,excluded_with=DestinationAccountId"`
+	DestinationAccountId string      `json:"destinationAccountId,omitempty"`
+	Amount               money.Money `json:"amount" swaggertype:"string" example:"100.10"`
+	ExecuteAt            *time.Time  `json:"executeAt,omitempty"`
+}
+
+// CreateBatch books up to 100 transfers at once. An atomic batch books all of
+// them or none, otherwise each transfer succeeds or fails on its own.
+type CreateBatch struct {
+	Atomic       bool                `json:"atomic"`
+	Transactions []CreateTransaction `json:"transactions" validate:"required,min=1,max=100,dive"`
+}
+
+// CreateSplit splits one payment of the source account among several
+// destinations. Each rule takes either a fixed Amount or a Percentage of what
+// is left once the fixed amounts are paid. Like in CreateTransaction, each
+// side is either an account or a user, standing for their default account.
+type CreateSplit struct {
+	SourceUserId    string      `json:"sourceUserId,omitempty" validate:"required_without=SourceAccountId,excluded_with=SourceAccountId"`
+	SourceAccountId string      `json:"sourceAccountId,omitempty"`
+	Amount          money.Money `json:"amount" swaggertype:"string" example:"100.00"`
+	Rules           []SplitRule `json:"rules" validate:"required,min=1,max=20,dive"`
+}
+
+type SplitRule struct {
+	DestinationUserId    string       `json:"destinationUserId,omitempty" validate:"required_without=DestinationAccountId,excluded_with=DestinationAccountId"`
+	DestinationAccountId string       `json:"destinationAccountId,omitempty"`
+	Amount               *money.Money `json:"amount,omitempty" swaggertype:"string" example:"10.00"`
+	Percentage           *float64     `json:"percentage,omitempty" validate:"omitempty,gt=0,lte=100" example:"90"`
+}
+
+// CreateAuthorization holds the amount on the source account until it is
+// captured or voided. Without ExpiresAt the hold lasts for a default period.
+// Each side is either an account or a user, as in CreateTransaction.
+type CreateAuthorization struct {
+	SourceUserId         string      `json:"sourceUserId,omitempty" validate:"required_without=SourceAccountId,excluded_with=SourceAccountId"`
+	SourceAccountId      string      `json:"sourceAccountId,omitempty"`
+	DestinationUserId    string      `json:"destinationUserId,omitempty" validate:"required_without=DestinationAccountId,excluded_with=DestinationAccountId"`
+	DestinationAccountId string      `json:"destinationAccountId,omitempty"`
+	Amount               money.Money `json:"amount" swaggertype:"string" example:"100.10"`
+	ExpiresAt            *time.Time  `json:"expiresAt,omitempty"`
+}
+
+// CaptureAuthorization captures the whole authorized amount when Amount is
+// omitted. Whatever isn't captured is released.
+type CaptureAuthorization struct {
+	Amount *money.Money `json:"amount,omitempty" swaggertype:"string" example:"100.10"`
+}
+
+// Withdraw takes money out of the account, or out of the default account of
+// the user.
+type Withdraw struct {
+	UserId    string      `json:"userId,omitempty" validate:"required_without=AccountId,excluded_with=AccountId"`
+	AccountId string      `json:"accountId,omitempty"`
+	Amount    money.Money `json:"amount" swaggertype:"string" example:"100.10"`
+}
+
+// ReverseTransaction reverses the whole remaining amount when Amount is
+// omitted.
+type ReverseTransaction struct {
+	Amount *money.Money `json:"amount,omitempty" swaggertype:"string" example:"100.10"`
+}
+
+// CreateStandingOrder repeats the transfer with the given frequency, DAILY,
+// WEEKLY or MONTHLY, from StartAt until EndAt or Count occurrences. Without
+// both it repeats until cancelled. Each side is either an account or a user,
+// as in CreateTransaction.
+type CreateStandingOrder struct {
+	SourceUserId         string      `json:"sourceUserId,omitempty" validate:"required_without=SourceAccountId,excluded_with=SourceAccountId"`
+	SourceAccountId      string      `json:"sourceAccountId,omitempty"`
+	DestinationUserId    string      `json:"destinationUserId,omitempty" validate:"required_without=DestinationAccountId,excluded_with=DestinationAccountId"`
+	DestinationAccountId string      `json:"destinationAccountId,omitempty"`
+	Amount               money.Money `json:"amount" swaggertype:"string" example:"150.00"`
+	Frequency            string      `json:"frequency" validate:"required,oneof=DAILY WEEKLY MONTHLY" example:"MONTHLY"`
+	StartAt              time.Time   `json:"startAt" validate:"required"`
+	EndAt                *time.Time  `json:"endAt,omitempty"`
+	Count                *int        `json:"count,omitempty" validate:"omitempty,min=1"`
+}
+
+// UpdateStandingOrder changes an active standing order. Omitted fields are
+// left as they are.
+type UpdateStandingOrder struct {
+	Amount *money.Money `json:"amount,omitempty" swaggertype:"string" example:"150.00"`
+	EndAt  *time.Time   `json:"endAt,omitempty"`
+	Count  *int         `json:"count,omitempty" validate:"omitempty,min=1"`
+}
+
+// CreateFeeRule charges a FLAT amount, a PERCENTAGE in basis points (150 is
+// 1.5%) or a TIERED fee picked by the transfer amount, optionally capped by
+// MinAmount and MaxAmount. Kind and AccountType restrict the transactions it
+// applies to; when omitted the rule applies to any of them.
+type CreateFeeRule struct {
+	Name        string       `json:"name" validate:"required"`
+	Type        string       `json:"type" validate:"required,oneof=FLAT PERCENTAGE TIERED" example:"PERCENTAGE"`
+	Kind        string       `json:"kind,omitempty" validate:"omitempty,oneof=TRANSFER DEPOSIT" example:"TRANSFER"`
+	AccountType string       `json:"accountType,omitempty" validate:"omitempty,oneof=PERSONAL BUSINESS" example:"BUSINESS"`
+	FlatAmount  *money.Money `json:"flatAmount,omitempty" swaggertype:"string" example:"1.00"`
+	BasisPoints *int64       `json:"basisPoints,omitempty" validate:"omitempty,min=1,max=10000" example:"150"`
+	Tiers       []FeeTier    `json:"tiers,omitempty" validate:"omitempty,max=20,dive"`
+	MinAmount   *money.Money `json:"minAmount,omitempty" swaggertype:"string" example:"0.50"`
+	MaxAmount   *money.Money `json:"maxAmount,omitempty" swaggertype:"string" example:"10.00"`
+}
+
+// FeeTier applies to amounts up to UpTo, the last tier having no UpTo.
+type FeeTier struct {
+	UpTo        *money.Money `json:"upTo,omitempty" swaggertype:"string" example:"1000.00"`
+	FlatAmount  money.Money  `json:"flatAmount" swaggertype:"string" example:"0.00"`
+	BasisPoints int64        `json:"basisPoints" validate:"min=0,max=10000" example:"100"`
+}
+
+// UpdateLimits replaces the limits of a user, or the default ones. An omitted
+// limit falls back to the default one for a user and is unbounded for the
+// defaults.
+type UpdateLimits struct {
+	PerTransaction      *money.Money `json:"perTransaction,omitempty" swaggertype:"string" example:"5000.00"`
+	NightPerTransaction *money.Money `json:"nightPerTransaction,omitempty" swaggertype:"string" example:"1000.00"`
+	Daily               *money.Money `json:"daily,omitempty" swaggertype:"string" example:"10000.00"`
+	Monthly             *money.Money `json:"monthly,omitempty" swaggertype:"string" example:"50000.00"`
+}
+
+// UpdateCreditLine sets how far below zero the user can take their balance and
+// the monthly interest charged on a negative balance, in basis points (800 is
+// 8% a month).
+type UpdateCreditLine struct {
+	CreditLimit  money.Money `json:"creditLimit" swaggertype:"string" example:"500.00"`
+	InterestRate int64       `json:"interestRate" validate:"min=0,max=10000" example:"800"`
+}
+
+// UpdateUserStatus says why a user is frozen or unfrozen.
+type UpdateUserStatus struct {
+	Reason string `json:"reason" validate:"required,max=255" example:"suspected account takeover"`
+}
+
+// CloseUser says why a user is closed and, when their accounts still have a
+// balance, the user whose default account it is swept to.
+type CloseUser struct {
+	Reason        string `json:"reason" validate:"required,max=255" example:"customer request"`
+	SweepToUserId string `json:"sweepToUserId,omitempty" example:"d6b1b0a4-2a51-4c3c-9e0f-6c1f7e1f3b2a"`
+}
+
+// ApproveCorrections lets an operator correct the drifted balances of a
+// reconciliation, only those of UserIds when it is given.
+type ApproveCorrections struct {
+	ApprovedBy string   `json:"approvedBy" validate:"required" example:"jane.doe"`
+	UserIds    []string `json:"userIds,omitempty" validate:"omitempty,max=100"`
+}
+
+// CreateWebhook subscribes URL to the events of EventTypes. A secret is
+// generated when none is given.
+type CreateWebhook struct {
+	URL        string   `json:"url" validate:"required,url" example:"https://partner.example.com/webhooks/snapfi"`
+	EventTypes []string `json:"eventTypes" validate:"required,min=1,dive,oneof=transaction.booked transaction.failed transaction.reversed transaction.partially_reversed deposit.booked" example:"transaction.booked,deposit.booked"`
+	Secret     string   `json:"secret,omitempty" validate:"omitempty,min=16,max=255"`
 }
 
+// IncreaseBalanceUser deposits into the account, or into the default account
+// of the user.
 type IncreaseBalanceUser struct {
-	UserId string  `json:"userId" validate:"required"`
-	Value  float64 `json:"value" validate:"required"`
+	UserId    string      `json:"userId,omitempty" validate:"required_without=AccountId,excluded_with=AccountId"`
+	AccountId string      `json:"accountId,omitempty"`
+	Value     money.Money `json:"value" swaggertype:"string" example:"100.10"`
 }
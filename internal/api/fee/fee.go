@@ -0,0 +1,112 @@
+package fee
+
+import (
+	"net/http"
+
+	"github.com/garoque/backend-code-challenge-snapfi/internal/api/dto"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/api/idempotency"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/app"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	"github.com/labstack/echo/v4"
+)
+
+func Register(router *echo.Group, app *app.Container) {
+	h := &handler{app}
+
+	router.POST("", h.create, idempotency.Middleware(app))
+	router.GET("", h.readAll)
+	router.GET("/:id", h.readOne)
+	router.DELETE("/:id", h.deactivate)
+}
+
+type handler struct {
+	app *app.Container
+}
+
+// Create fee rule godoc
+// @Summary Create fee rule
+// @Description Charge a flat, percentage or tiered fee on transfers or deposits, optionally only for one account type
+// @Tags fee-rule
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateFeeRule true "fee rule request"
+// @Param Idempotency-Key header string false "key used to safely retry the request"
+// @Success 201 {object} entity.FeeRule
+// @Failure 400 {object} error
+// @Failure 409 {object} error
+// @Failure 500 {object} error
+// @Router /fee-rule [post]
+func (h *handler) create(c echo.Context) error {
+	var request dto.CreateFeeRule
+	if err := c.Bind(&request); err != nil {
+		return err
+	}
+
+	if err := c.Validate(&request); err != nil {
+		return echo.ErrBadRequest
+	}
+
+	rule, err := h.app.Fee.Create(c.Request().Context(), entity.NewFeeRule(request))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, dto.Response{Data: rule})
+}
+
+// Read all fee rules godoc
+// @Summary Read all fee rules
+// @Description Read all fee rules, active or not
+// @Tags fee-rule
+// @Accept json
+// @Produce json
+// @Success 200 {array} entity.FeeRule
+// @Failure 500 {object} error
+// @Router /fee-rule [get]
+func (h *handler) readAll(c echo.Context) error {
+	rules, err := h.app.Fee.ReadAll(c.Request().Context())
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, dto.Response{Data: rules})
+}
+
+// Read fee rule godoc
+// @Summary Read fee rule
+// @Description Read fee rule
+// @Tags fee-rule
+// @Accept json
+// @Produce json
+// @Param id path string true "fee rule id"
+// @Success 200 {object} entity.FeeRule
+// @Failure 404 {object} error
+// @Router /fee-rule/{id} [get]
+func (h *handler) readOne(c echo.Context) error {
+	rule, err := h.app.Fee.ReadOneById(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, dto.Response{Data: rule})
+}
+
+// Deactivate fee rule godoc
+// @Summary Deactivate fee rule
+// @Description Stop charging a fee rule, the fees already charged are kept
+// @Tags fee-rule
+// @Accept json
+// @Produce json
+// @Param id path string true "fee rule id"
+// @Success 200 {object} entity.FeeRule
+// @Failure 404 {object} error
+// @Failure 500 {object} error
+// @Router /fee-rule/{id} [delete]
+func (h *handler) deactivate(c echo.Context) error {
+	rule, err := h.app.Fee.Deactivate(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, dto.Response{Data: rule})
+}
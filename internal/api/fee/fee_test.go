@@ -0,0 +1,221 @@
+package fee
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/garoque/backend-code-challenge-snapfi/internal/api/dto"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/app"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/mocks"
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/validator"
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+var basisPoints = int64(150)
+
+var rule = &entity.FeeRule{
+	ID:          "fee-rule-id",
+	Name:        "transfers",
+	Type:        entity.FEE_PERCENTAGE,
+	TypeString:  "PERCENTAGE",
+	BasisPoints: &basisPoints,
+	Active:      true,
+}
+
+func assertResponse(t *testing.T, rec *httptest.ResponseRecorder, data interface{}) {
+	expectedResultJSON, err := json.Marshal(dto.Response{Data: data})
+	assert.NoError(t, err)
+
+	var expectedResult dto.Response
+	err = json.Unmarshal(expectedResultJSON, &expectedResult)
+	assert.NoError(t, err)
+
+	var currentResult dto.Response
+	json.NewDecoder(rec.Body).Decode(&currentResult)
+
+	assert.Equal(t, expectedResult, currentResult)
+}
+
+func TestCreate(t *testing.T) {
+	request := dto.CreateFeeRule{
+		Name:        "transfers",
+		Type:        "PERCENTAGE",
+		Kind:        "TRANSFER",
+		BasisPoints: &basisPoints,
+	}
+
+	invalidType := request
+	invalidType.Type = "PROGRESSIVE"
+
+	invalidBasisPoints := request
+	tooManyBasisPoints := int64(10001)
+	invalidBasisPoints.BasisPoints = &tooManyBasisPoints
+
+	cases := map[string]struct {
+		InputRule   dto.CreateFeeRule
+		ExpectedErr error
+		PrepareMock func(mockFeeApp *mocks.MockAppFeeInterface)
+	}{
+		"deve retornar sucesso": {
+			InputRule:   request,
+			ExpectedErr: nil,
+			PrepareMock: func(mockFeeApp *mocks.MockAppFeeInterface) {
+				mockFeeApp.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(rule, nil)
+			},
+		},
+		"deve retornar erro: tipo invalido": {
+			InputRule:   invalidType,
+			ExpectedErr: echo.ErrBadRequest,
+			PrepareMock: func(mockFeeApp *mocks.MockAppFeeInterface) {},
+		},
+		"deve retornar erro: percentual acima de 100%": {
+			InputRule:   invalidBasisPoints,
+			ExpectedErr: echo.ErrBadRequest,
+			PrepareMock: func(mockFeeApp *mocks.MockAppFeeInterface) {},
+		},
+		"deve retornar erro": {
+			InputRule:   request,
+			ExpectedErr: echo.ErrInternalServerError,
+			PrepareMock: func(mockFeeApp *mocks.MockAppFeeInterface) {
+				mockFeeApp.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil, echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			mockFeeApp := mocks.NewMockAppFeeInterface(ctrl)
+			cs.PrepareMock(mockFeeApp)
+
+			api := handler{
+				app: &app.Container{Fee: mockFeeApp},
+			}
+
+			e := echo.New()
+			e.Validator = validator.NewValidator()
+
+			endpoint := "/v1/fee-rule"
+
+			requestBytes, _ := json.Marshal(cs.InputRule)
+			req := httptest.NewRequest(http.MethodPost, endpoint, bytes.NewReader(requestBytes)).WithContext(ctx)
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetPath(endpoint)
+
+			err := api.create(c)
+			assert.Equal(t, cs.ExpectedErr, err)
+
+			if err == nil {
+				assertResponse(t, rec, rule)
+			}
+		})
+	}
+}
+
+func TestRead(t *testing.T) {
+	cases := map[string]struct {
+		Path           string
+		Method         string
+		Handler        func(h *handler) echo.HandlerFunc
+		ExpectedResult interface{}
+		ExpectedErr    error
+		PrepareMock    func(mockFeeApp *mocks.MockAppFeeInterface)
+	}{
+		"deve retornar sucesso: todas": {
+			Path:           "/v1/fee-rule",
+			Method:         http.MethodGet,
+			Handler:        func(h *handler) echo.HandlerFunc { return h.readAll },
+			ExpectedResult: []entity.FeeRule{*rule},
+			PrepareMock: func(mockFeeApp *mocks.MockAppFeeInterface) {
+				mockFeeApp.EXPECT().ReadAll(gomock.Any()).Times(1).Return([]entity.FeeRule{*rule}, nil)
+			},
+		},
+		"deve retornar erro: todas": {
+			Path:        "/v1/fee-rule",
+			Method:      http.MethodGet,
+			Handler:     func(h *handler) echo.HandlerFunc { return h.readAll },
+			ExpectedErr: echo.ErrInternalServerError,
+			PrepareMock: func(mockFeeApp *mocks.MockAppFeeInterface) {
+				mockFeeApp.EXPECT().ReadAll(gomock.Any()).Times(1).Return(nil, echo.ErrInternalServerError)
+			},
+		},
+		"deve retornar sucesso: uma": {
+			Path:           "/v1/fee-rule/:id",
+			Method:         http.MethodGet,
+			Handler:        func(h *handler) echo.HandlerFunc { return h.readOne },
+			ExpectedResult: rule,
+			PrepareMock: func(mockFeeApp *mocks.MockAppFeeInterface) {
+				mockFeeApp.EXPECT().ReadOneById(gomock.Any(), "fee-rule-id").Times(1).Return(rule, nil)
+			},
+		},
+		"deve retornar erro: uma": {
+			Path:        "/v1/fee-rule/:id",
+			Method:      http.MethodGet,
+			Handler:     func(h *handler) echo.HandlerFunc { return h.readOne },
+			ExpectedErr: echo.ErrNotFound,
+			PrepareMock: func(mockFeeApp *mocks.MockAppFeeInterface) {
+				mockFeeApp.EXPECT().ReadOneById(gomock.Any(), "fee-rule-id").Times(1).Return(nil, echo.ErrNotFound)
+			},
+		},
+		"deve retornar sucesso: desativar": {
+			Path:           "/v1/fee-rule/:id",
+			Method:         http.MethodDelete,
+			Handler:        func(h *handler) echo.HandlerFunc { return h.deactivate },
+			ExpectedResult: rule,
+			PrepareMock: func(mockFeeApp *mocks.MockAppFeeInterface) {
+				mockFeeApp.EXPECT().Deactivate(gomock.Any(), "fee-rule-id").Times(1).Return(rule, nil)
+			},
+		},
+		"deve retornar erro: desativar": {
+			Path:        "/v1/fee-rule/:id",
+			Method:      http.MethodDelete,
+			Handler:     func(h *handler) echo.HandlerFunc { return h.deactivate },
+			ExpectedErr: echo.ErrNotFound,
+			PrepareMock: func(mockFeeApp *mocks.MockAppFeeInterface) {
+				mockFeeApp.EXPECT().Deactivate(gomock.Any(), "fee-rule-id").Times(1).Return(nil, echo.ErrNotFound)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			mockFeeApp := mocks.NewMockAppFeeInterface(ctrl)
+			cs.PrepareMock(mockFeeApp)
+
+			api := &handler{
+				app: &app.Container{Fee: mockFeeApp},
+			}
+
+			e := echo.New()
+
+			req := httptest.NewRequest(cs.Method, "/v1/fee-rule/fee-rule-id", nil).WithContext(ctx)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetPath(cs.Path)
+			c.SetParamNames("id")
+			c.SetParamValues("fee-rule-id")
+
+			err := cs.Handler(api)(c)
+			assert.Equal(t, cs.ExpectedErr, err)
+
+			if err == nil {
+				assertResponse(t, rec, cs.ExpectedResult)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package idempotency
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/garoque/backend-code-challenge-snapfi/internal/app"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	HeaderIdempotencyKey     = "Idempotency-Key"
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
+
+	maxKeyLength = 255
+	// maxScopeLength is the size of the scope column. The scope is built from
+	// the path the client sent, so longer ones are rejected instead of cut.
+	maxScopeLength = 255
+)
+
+// Middleware makes a route safe to retry. Requests carrying an Idempotency-Key
+// header are processed once; replays with the same key and body get the
+// stored response back, and replays with a different body are rejected.
+// Requests without the header are processed as usual.
+func Middleware(app *app.Container) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			key := c.Request().Header.Get(HeaderIdempotencyKey)
+			if key == "" {
+				return next(c)
+			}
+
+			if len(key) > maxKeyLength {
+				return echo.NewHTTPError(echo.ErrBadRequest.Code, "Idempotency-Key is too long")
+			}
+
+			body, err := io.ReadAll(c.Request().Body)
+			if err != nil {
+				return echo.ErrBadRequest
+			}
+			c.Request().Body = io.NopCloser(bytes.NewReader(body))
+
+			hash := sha256.Sum256(body)
+			// The actual path, not the route template, so the same key can be
+			// used once per resource on routes such as /:id/reverse.
+			scope := c.Request().Method + " " + c.Request().URL.Path
+			if len(scope) > maxScopeLength {
+				return echo.NewHTTPError(echo.ErrBadRequest.Code, "The request path is too long to be used with an Idempotency-Key")
+			}
+			ctx := c.Request().Context()
+
+			stored, err := app.Idempotency.Begin(ctx, scope, key, hex.EncodeToString(hash[:]))
+			if err != nil {
+				return err
+			}
+
+			if stored != nil {
+				c.Response().Header().Set(HeaderIdempotentReplayed, "true")
+				return c.JSONBlob(*stored.StatusCode, stored.ResponseBody)
+			}
+
+			recorder := &responseRecorder{ResponseWriter: c.Response().Writer}
+			c.Response().Writer = recorder
+
+			if err := next(c); err != nil {
+				c.Error(err)
+			}
+
+			status := c.Response().Status
+			if status >= http.StatusInternalServerError {
+				if err := app.Idempotency.Release(ctx, scope, key); err != nil {
+					log.Println("Error api.Idempotency.Middleware.Release: ", err.Error())
+				}
+				return nil
+			}
+
+			if err := app.Idempotency.Complete(ctx, scope, key, status, recorder.body.Bytes()); err != nil {
+				log.Println("Error api.Idempotency.Middleware.Complete: ", err.Error())
+			}
+
+			return nil
+		}
+	}
+}
+
+// responseRecorder keeps a copy of the body written to the client.
+type responseRecorder struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
@@ -0,0 +1,162 @@
+package idempotency
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/garoque/backend-code-challenge-snapfi/internal/api/dto"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/app"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware(t *testing.T) {
+	scope := "POST /v1/transaction/transaction-id/reverse"
+	body := `{"amount":"10.00"}`
+	sum := sha256.Sum256([]byte(body))
+	hash := hex.EncodeToString(sum[:])
+
+	statusCode := http.StatusCreated
+	storedKey := &entity.IdempotencyKey{
+		Scope:        scope,
+		Key:          "key",
+		RequestHash:  hash,
+		StatusCode:   &statusCode,
+		ResponseBody: []byte(`{"data":"stored"}`),
+	}
+
+	cases := map[string]struct {
+		InputKey           string
+		InputId            string
+		InputHandlerErr    error
+		ExpectedStatus     int
+		ExpectedBody       string
+		ExpectedReplayed   string
+		ExpectedHandlerRun bool
+		PrepareMock        func(mockIdempotencyApp *mocks.MockAppIdempotencyInterface)
+	}{
+		"deve retornar sucesso: sem chave": {
+			InputKey:           "",
+			ExpectedStatus:     http.StatusCreated,
+			ExpectedBody:       `{"data":"new"}`,
+			ExpectedHandlerRun: true,
+			PrepareMock:        func(mockIdempotencyApp *mocks.MockAppIdempotencyInterface) {},
+		},
+		"deve retornar sucesso: nova chave": {
+			InputKey:           "key",
+			ExpectedStatus:     http.StatusCreated,
+			ExpectedBody:       `{"data":"new"}`,
+			ExpectedHandlerRun: true,
+			PrepareMock: func(mockIdempotencyApp *mocks.MockAppIdempotencyInterface) {
+				mockIdempotencyApp.EXPECT().Begin(gomock.Any(), scope, "key", hash).Times(1).Return(nil, nil)
+				mockIdempotencyApp.EXPECT().Complete(gomock.Any(), scope, "key", http.StatusCreated, []byte("{\"data\":\"new\"}\n")).Times(1).Return(nil)
+			},
+		},
+		"deve retornar sucesso: resposta armazenada": {
+			InputKey:           "key",
+			ExpectedStatus:     http.StatusCreated,
+			ExpectedBody:       `{"data":"stored"}`,
+			ExpectedReplayed:   "true",
+			ExpectedHandlerRun: false,
+			PrepareMock: func(mockIdempotencyApp *mocks.MockAppIdempotencyInterface) {
+				mockIdempotencyApp.EXPECT().Begin(gomock.Any(), scope, "key", hash).Times(1).Return(storedKey, nil)
+			},
+		},
+		"deve retornar sucesso: erro do handler armazenado": {
+			InputKey:           "key",
+			InputHandlerErr:    echo.NewHTTPError(echo.ErrBadRequest.Code, "Insufficient balance"),
+			ExpectedStatus:     http.StatusBadRequest,
+			ExpectedBody:       `{"message":"Insufficient balance"}`,
+			ExpectedHandlerRun: true,
+			PrepareMock: func(mockIdempotencyApp *mocks.MockAppIdempotencyInterface) {
+				mockIdempotencyApp.EXPECT().Begin(gomock.Any(), scope, "key", hash).Times(1).Return(nil, nil)
+				mockIdempotencyApp.EXPECT().Complete(gomock.Any(), scope, "key", http.StatusBadRequest, gomock.Any()).Times(1).Return(nil)
+			},
+		},
+		"deve liberar a chave: erro interno do handler": {
+			InputKey:           "key",
+			InputHandlerErr:    echo.ErrInternalServerError,
+			ExpectedStatus:     http.StatusInternalServerError,
+			ExpectedBody:       `{"message":"Internal Server Error"}`,
+			ExpectedHandlerRun: true,
+			PrepareMock: func(mockIdempotencyApp *mocks.MockAppIdempotencyInterface) {
+				mockIdempotencyApp.EXPECT().Begin(gomock.Any(), scope, "key", hash).Times(1).Return(nil, nil)
+				mockIdempotencyApp.EXPECT().Release(gomock.Any(), scope, "key").Times(1).Return(nil)
+			},
+		},
+		"deve retornar erro: chave reutilizada": {
+			InputKey:           "key",
+			ExpectedStatus:     http.StatusConflict,
+			ExpectedBody:       `{"message":"Idempotency-Key was already used with a different request"}`,
+			ExpectedHandlerRun: false,
+			PrepareMock: func(mockIdempotencyApp *mocks.MockAppIdempotencyInterface) {
+				mockIdempotencyApp.EXPECT().Begin(gomock.Any(), scope, "key", hash).Times(1).
+					Return(nil, echo.NewHTTPError(echo.ErrConflict.Code, "Idempotency-Key was already used with a different request"))
+			},
+		},
+		"deve retornar erro: caminho muito longo": {
+			InputKey:           "key",
+			InputId:            strings.Repeat("a", 250),
+			ExpectedStatus:     http.StatusBadRequest,
+			ExpectedBody:       `{"message":"The request path is too long to be used with an Idempotency-Key"}`,
+			ExpectedHandlerRun: false,
+			PrepareMock:        func(mockIdempotencyApp *mocks.MockAppIdempotencyInterface) {},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			mockIdempotencyApp := mocks.NewMockAppIdempotencyInterface(ctrl)
+			cs.PrepareMock(mockIdempotencyApp)
+
+			handlerRun := false
+			handler := func(c echo.Context) error {
+				handlerRun = true
+
+				request := map[string]string{}
+				if err := c.Bind(&request); err != nil {
+					return err
+				}
+				assert.Equal(t, "10.00", request["amount"])
+
+				if cs.InputHandlerErr != nil {
+					return cs.InputHandlerErr
+				}
+
+				return c.JSON(http.StatusCreated, dto.Response{Data: "new"})
+			}
+
+			e := echo.New()
+			e.POST("/v1/transaction/:id/reverse", handler, Middleware(&app.Container{Idempotency: mockIdempotencyApp}))
+
+			id := "transaction-id"
+			if cs.InputId != "" {
+				id = cs.InputId
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/v1/transaction/"+id+"/reverse", strings.NewReader(body)).WithContext(ctx)
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			if cs.InputKey != "" {
+				req.Header.Set(HeaderIdempotencyKey, cs.InputKey)
+			}
+
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, cs.ExpectedStatus, rec.Code)
+			assert.JSONEq(t, cs.ExpectedBody, rec.Body.String())
+			assert.Equal(t, cs.ExpectedReplayed, rec.Header().Get(HeaderIdempotentReplayed))
+			assert.Equal(t, cs.ExpectedHandlerRun, handlerRun)
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package ledger
+
+import (
+	"net/http"
+
+	"github.com/garoque/backend-code-challenge-snapfi/internal/api/dto"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/app"
+	"github.com/labstack/echo/v4"
+)
+
+func Register(router *echo.Group, app *app.Container) {
+	h := &handler{app}
+
+	router.GET("/check", h.check)
+	router.POST("/reconciliation", h.reconcile)
+	router.GET("/reconciliation", h.readReconciliations)
+	router.GET("/reconciliation/:id", h.readReconciliation)
+	router.POST("/reconciliation/:id/approve", h.approveCorrections)
+}
+
+type handler struct {
+	app *app.Container
+}
+
+// Check ledger godoc
+// @Summary Check ledger invariants
+// @Description Verifies that all postings sum to zero, per transaction and overall, and that every user balance matches its postings
+// @Tags ledger
+// @Accept json
+// @Produce json
+// @Success 200 {object} entity.LedgerCheck
+// @Failure 500 {object} error
+// @Router /ledger/check [get]
+func (h *handler) check(c echo.Context) error {
+	check, err := h.app.Ledger.Check(c.Request().Context())
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, dto.Response{Data: check})
+}
+
+// Reconcile godoc
+// @Summary Reconcile balances
+// @Description Recompute the balance of every user from their ledger postings and store a report of the balances that drifted from it
+// @Tags ledger
+// @Accept json
+// @Produce json
+// @Success 201 {object} entity.Reconciliation
+// @Failure 500 {object} error
+// @Router /ledger/reconciliation [post]
+func (h *handler) reconcile(c echo.Context) error {
+	reconciliation, err := h.app.Ledger.Reconcile(c.Request().Context())
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, dto.Response{Data: reconciliation})
+}
+
+// Read all reconciliations godoc
+// @Summary Read all reconciliations
+// @Description Read all reconciliation reports, the latest first, without their differences
+// @Tags ledger
+// @Accept json
+// @Produce json
+// @Success 200 {array} entity.Reconciliation
+// @Failure 500 {object} error
+// @Router /ledger/reconciliation [get]
+func (h *handler) readReconciliations(c echo.Context) error {
+	reconciliations, err := h.app.Ledger.ReadReconciliations(c.Request().Context())
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, dto.Response{Data: reconciliations})
+}
+
+// Read reconciliation godoc
+// @Summary Read reconciliation
+// @Description Read a reconciliation report with the balances that drifted and whether they were corrected
+// @Tags ledger
+// @Accept json
+// @Produce json
+// @Param id path string true "reconciliation id"
+// @Success 200 {object} entity.Reconciliation
+// @Failure 404 {object} error
+// @Failure 500 {object} error
+// @Router /ledger/reconciliation/{id} [get]
+func (h *handler) readReconciliation(c echo.Context) error {
+	reconciliation, err := h.app.Ledger.ReadReconciliation(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, dto.Response{Data: reconciliation})
+}
+
+// Approve corrections godoc
+// @Summary Approve reconciliation corrections
+// @Description Set the drifted balances of the reconciliation, or only those of the given users, to the balances recomputed from their postings
+// @Tags ledger
+// @Accept json
+// @Produce json
+// @Param id path string true "reconciliation id"
+// @Param request body dto.ApproveCorrections true "approval"
+// @Success 200 {object} entity.Reconciliation
+// @Failure 400 {object} error
+// @Failure 404 {object} error
+// @Failure 409 {object} error
+// @Failure 500 {object} error
+// @Router /ledger/reconciliation/{id}/approve [post]
+func (h *handler) approveCorrections(c echo.Context) error {
+	var request dto.ApproveCorrections
+	if err := c.Bind(&request); err != nil {
+		return err
+	}
+
+	if err := c.Validate(&request); err != nil {
+		return echo.ErrBadRequest
+	}
+
+	reconciliation, err := h.app.Ledger.ApproveCorrections(c.Request().Context(), c.Param("id"), request)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, dto.Response{Data: reconciliation})
+}
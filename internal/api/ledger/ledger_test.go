@@ -0,0 +1,238 @@
+package ledger
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/garoque/backend-code-challenge-snapfi/internal/api/dto"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/app"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/mocks"
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/money"
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/validator"
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheck(t *testing.T) {
+	check := &entity.LedgerCheck{
+		Total:                  money.New(0),
+		Balanced:               true,
+		UnbalancedTransactions: []string{},
+		BalanceMismatches:      []entity.BalanceMismatch{},
+	}
+
+	cases := map[string]struct {
+		ExpectedResult *entity.LedgerCheck
+		ExpectedErr    error
+		PrepareMock    func(mockLedgerApp *mocks.MockAppLedgerInterface)
+	}{
+		"deve retornar sucesso": {
+			ExpectedResult: check,
+			ExpectedErr:    nil,
+			PrepareMock: func(mockLedgerApp *mocks.MockAppLedgerInterface) {
+				mockLedgerApp.EXPECT().Check(gomock.Any()).Times(1).Return(check, nil)
+			},
+		},
+		"deve retornar erro": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrInternalServerError,
+			PrepareMock: func(mockLedgerApp *mocks.MockAppLedgerInterface) {
+				mockLedgerApp.EXPECT().Check(gomock.Any()).Times(1).Return(nil, echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			mockLedgerApp := mocks.NewMockAppLedgerInterface(ctrl)
+			cs.PrepareMock(mockLedgerApp)
+
+			api := handler{
+				app: &app.Container{Ledger: mockLedgerApp},
+			}
+
+			e := echo.New()
+
+			endpoint := "/v1/ledger/check"
+			req := httptest.NewRequest(http.MethodGet, endpoint, nil).WithContext(ctx)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetPath(endpoint)
+
+			err := api.check(c)
+			assert.Equal(t, cs.ExpectedErr, err)
+
+			if err == nil {
+				expectedResultJSON, err := json.Marshal(dto.Response{Data: cs.ExpectedResult})
+				assert.NoError(t, err)
+
+				var expectedResult dto.Response
+				err = json.Unmarshal(expectedResultJSON, &expectedResult)
+				assert.NoError(t, err)
+
+				var currentResult dto.Response
+				json.NewDecoder(rec.Body).Decode(&currentResult)
+
+				assert.Equal(t, expectedResult, currentResult)
+			}
+		})
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	reconciliation := &entity.Reconciliation{
+		ID:           "reconciliation-id",
+		DriftedUsers: 1,
+		Differences: []entity.ReconciliationDifference{
+			{ID: 1, UserId: "user-id", Balance: money.New(10010), ExpectedBalance: money.New(9010), Difference: money.New(-1000)},
+		},
+	}
+
+	cases := map[string]struct {
+		ExpectedResult *entity.Reconciliation
+		ExpectedErr    error
+		PrepareMock    func(mockLedgerApp *mocks.MockAppLedgerInterface)
+	}{
+		"deve retornar sucesso": {
+			ExpectedResult: reconciliation,
+			ExpectedErr:    nil,
+			PrepareMock: func(mockLedgerApp *mocks.MockAppLedgerInterface) {
+				mockLedgerApp.EXPECT().Reconcile(gomock.Any()).Times(1).Return(reconciliation, nil)
+			},
+		},
+		"deve retornar erro": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrInternalServerError,
+			PrepareMock: func(mockLedgerApp *mocks.MockAppLedgerInterface) {
+				mockLedgerApp.EXPECT().Reconcile(gomock.Any()).Times(1).Return(nil, echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			mockLedgerApp := mocks.NewMockAppLedgerInterface(ctrl)
+			cs.PrepareMock(mockLedgerApp)
+
+			api := handler{
+				app: &app.Container{Ledger: mockLedgerApp},
+			}
+
+			e := echo.New()
+
+			endpoint := "/v1/ledger/reconciliation"
+			req := httptest.NewRequest(http.MethodPost, endpoint, nil).WithContext(ctx)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetPath(endpoint)
+
+			err := api.reconcile(c)
+			assert.Equal(t, cs.ExpectedErr, err)
+
+			if err == nil {
+				assert.Equal(t, http.StatusCreated, rec.Code)
+
+				expectedResultJSON, err := json.Marshal(dto.Response{Data: cs.ExpectedResult})
+				assert.NoError(t, err)
+
+				var expectedResult dto.Response
+				err = json.Unmarshal(expectedResultJSON, &expectedResult)
+				assert.NoError(t, err)
+
+				var currentResult dto.Response
+				json.NewDecoder(rec.Body).Decode(&currentResult)
+
+				assert.Equal(t, expectedResult, currentResult)
+			}
+		})
+	}
+}
+
+func TestApproveCorrections(t *testing.T) {
+	reconciliation := &entity.Reconciliation{ID: "reconciliation-id", DriftedUsers: 1}
+
+	cases := map[string]struct {
+		InputBody      string
+		ExpectedResult *entity.Reconciliation
+		ExpectedErr    error
+		PrepareMock    func(mockLedgerApp *mocks.MockAppLedgerInterface)
+	}{
+		"deve retornar sucesso": {
+			InputBody:      `{"approvedBy": "operator", "userIds": ["user-id"]}`,
+			ExpectedResult: reconciliation,
+			ExpectedErr:    nil,
+			PrepareMock: func(mockLedgerApp *mocks.MockAppLedgerInterface) {
+				mockLedgerApp.EXPECT().ApproveCorrections(gomock.Any(), "reconciliation-id", dto.ApproveCorrections{ApprovedBy: "operator", UserIds: []string{"user-id"}}).
+					Times(1).Return(reconciliation, nil)
+			},
+		},
+		"deve retornar erro: sem aprovador": {
+			InputBody:   `{"userIds": ["user-id"]}`,
+			ExpectedErr: echo.ErrBadRequest,
+			PrepareMock: func(mockLedgerApp *mocks.MockAppLedgerInterface) {},
+		},
+		"deve retornar erro": {
+			InputBody:   `{"approvedBy": "operator"}`,
+			ExpectedErr: echo.ErrNotFound,
+			PrepareMock: func(mockLedgerApp *mocks.MockAppLedgerInterface) {
+				mockLedgerApp.EXPECT().ApproveCorrections(gomock.Any(), "reconciliation-id", dto.ApproveCorrections{ApprovedBy: "operator"}).
+					Times(1).Return(nil, echo.ErrNotFound)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			mockLedgerApp := mocks.NewMockAppLedgerInterface(ctrl)
+			cs.PrepareMock(mockLedgerApp)
+
+			api := handler{
+				app: &app.Container{Ledger: mockLedgerApp},
+			}
+
+			e := echo.New()
+			e.Validator = validator.NewValidator()
+
+			endpoint := "/v1/ledger/reconciliation/:id/approve"
+			req := httptest.NewRequest(http.MethodPost, endpoint, strings.NewReader(cs.InputBody)).WithContext(ctx)
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetPath(endpoint)
+			c.SetParamNames("id")
+			c.SetParamValues("reconciliation-id")
+
+			err := api.approveCorrections(c)
+			assert.Equal(t, cs.ExpectedErr, err)
+
+			if err == nil {
+				expectedResultJSON, err := json.Marshal(dto.Response{Data: cs.ExpectedResult})
+				assert.NoError(t, err)
+
+				var expectedResult dto.Response
+				err = json.Unmarshal(expectedResultJSON, &expectedResult)
+				assert.NoError(t, err)
+
+				var currentResult dto.Response
+				json.NewDecoder(rec.Body).Decode(&currentResult)
+
+				assert.Equal(t, expectedResult, currentResult)
+			}
+		})
+	}
+}
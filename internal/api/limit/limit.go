@@ -0,0 +1,150 @@
+package limit
+
+import (
+	"net/http"
+
+	"github.com/garoque/backend-code-challenge-snapfi/internal/api/dto"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/app"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/money"
+	"github.com/labstack/echo/v4"
+)
+
+func Register(router *echo.Group, app *app.Container) {
+	h := &handler{app}
+
+	router.GET("/default", h.readDefault)
+	router.PUT("/default", h.updateDefault)
+	router.GET("/user/:id", h.readUser)
+	router.PUT("/user/:id", h.updateUser)
+	router.DELETE("/user/:id", h.deleteUser)
+}
+
+type handler struct {
+	app *app.Container
+}
+
+// Read default limits godoc
+// @Summary Read default limits
+// @Description Read the limits of the users without limits of their own
+// @Tags limit
+// @Accept json
+// @Produce json
+// @Success 200 {object} entity.Limits
+// @Failure 404 {object} error
+// @Router /limit/default [get]
+func (h *handler) readDefault(c echo.Context) error {
+	limits, err := h.app.Limit.ReadDefault(c.Request().Context())
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, dto.Response{Data: limits})
+}
+
+// Update default limits godoc
+// @Summary Update default limits
+// @Description Replace the default limits, an omitted limit doesn't bound anything
+// @Tags limit
+// @Accept json
+// @Produce json
+// @Param request body dto.UpdateLimits true "limits request"
+// @Success 200 {object} entity.Limits
+// @Failure 400 {object} error
+// @Failure 500 {object} error
+// @Router /limit/default [put]
+func (h *handler) updateDefault(c echo.Context) error {
+	request, err := bindLimits(c)
+	if err != nil {
+		return err
+	}
+
+	limits, err := h.app.Limit.UpdateDefault(c.Request().Context(), entity.NewLimits(entity.DefaultLimitsId, request))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, dto.Response{Data: limits})
+}
+
+// Read user limits godoc
+// @Summary Read user limits
+// @Description Read the limits the transfers of the user are checked against
+// @Tags limit
+// @Accept json
+// @Produce json
+// @Param id path string true "user id"
+// @Success 200 {object} entity.Limits
+// @Failure 404 {object} error
+// @Failure 500 {object} error
+// @Router /limit/user/{id} [get]
+func (h *handler) readUser(c echo.Context) error {
+	limits, err := h.app.Limit.ReadByUser(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, dto.Response{Data: limits})
+}
+
+// Update user limits godoc
+// @Summary Update user limits
+// @Description Replace the limits of the user, an omitted limit falls back to the default one
+// @Tags limit
+// @Accept json
+// @Produce json
+// @Param id path string true "user id"
+// @Param request body dto.UpdateLimits true "limits request"
+// @Success 200 {object} entity.Limits
+// @Failure 400 {object} error
+// @Failure 404 {object} error
+// @Failure 500 {object} error
+// @Router /limit/user/{id} [put]
+func (h *handler) updateUser(c echo.Context) error {
+	request, err := bindLimits(c)
+	if err != nil {
+		return err
+	}
+
+	limits, err := h.app.Limit.UpdateUser(c.Request().Context(), entity.NewLimits(c.Param("id"), request))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, dto.Response{Data: limits})
+}
+
+// Delete user limits godoc
+// @Summary Delete user limits
+// @Description Remove the limits of the user, who goes back to the default ones
+// @Tags limit
+// @Accept json
+// @Produce json
+// @Param id path string true "user id"
+// @Success 200 {object} entity.Limits
+// @Failure 404 {object} error
+// @Failure 500 {object} error
+// @Router /limit/user/{id} [delete]
+func (h *handler) deleteUser(c echo.Context) error {
+	limits, err := h.app.Limit.DeleteUser(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, dto.Response{Data: limits})
+}
+
+func bindLimits(c echo.Context) (dto.UpdateLimits, error) {
+	var request dto.UpdateLimits
+	if err := c.Bind(&request); err != nil {
+		return request, err
+	}
+
+	for _, limit := range []*money.Money{request.PerTransaction, request.NightPerTransaction, request.Daily, request.Monthly} {
+		if limit != nil && limit.IsNegative() {
+			return request, echo.NewHTTPError(echo.ErrBadRequest.Code, "Limits can't be negative")
+		}
+	}
+
+	return request, nil
+}
@@ -0,0 +1,208 @@
+package limit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/garoque/backend-code-challenge-snapfi/internal/api/dto"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/app"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/mocks"
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/money"
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	daily  = money.New(100000)
+	limits = &entity.Limits{UserId: "user-id", Daily: &daily}
+)
+
+func assertResponse(t *testing.T, rec *httptest.ResponseRecorder, data interface{}) {
+	expectedResultJSON, err := json.Marshal(dto.Response{Data: data})
+	assert.NoError(t, err)
+
+	var expectedResult dto.Response
+	err = json.Unmarshal(expectedResultJSON, &expectedResult)
+	assert.NoError(t, err)
+
+	var currentResult dto.Response
+	json.NewDecoder(rec.Body).Decode(&currentResult)
+
+	assert.Equal(t, expectedResult, currentResult)
+}
+
+func TestUpdate(t *testing.T) {
+	negative := money.New(-100)
+
+	cases := map[string]struct {
+		Path         string
+		Handler      func(h *handler) echo.HandlerFunc
+		InputRequest dto.UpdateLimits
+		ExpectedErr  error
+		PrepareMock  func(mockLimitApp *mocks.MockAppLimitInterface)
+	}{
+		"deve retornar sucesso: padrao": {
+			Path:         "/v1/limit/default",
+			Handler:      func(h *handler) echo.HandlerFunc { return h.updateDefault },
+			InputRequest: dto.UpdateLimits{Daily: &daily},
+			PrepareMock: func(mockLimitApp *mocks.MockAppLimitInterface) {
+				mockLimitApp.EXPECT().UpdateDefault(gomock.Any(), &entity.Limits{UserId: entity.DefaultLimitsId, Daily: &daily}).Times(1).Return(limits, nil)
+			},
+		},
+		"deve retornar sucesso: usuario": {
+			Path:         "/v1/limit/user/:id",
+			Handler:      func(h *handler) echo.HandlerFunc { return h.updateUser },
+			InputRequest: dto.UpdateLimits{Daily: &daily},
+			PrepareMock: func(mockLimitApp *mocks.MockAppLimitInterface) {
+				mockLimitApp.EXPECT().UpdateUser(gomock.Any(), &entity.Limits{UserId: "user-id", Daily: &daily}).Times(1).Return(limits, nil)
+			},
+		},
+		"deve retornar erro: limite negativo": {
+			Path:         "/v1/limit/user/:id",
+			Handler:      func(h *handler) echo.HandlerFunc { return h.updateUser },
+			InputRequest: dto.UpdateLimits{Monthly: &negative},
+			ExpectedErr:  echo.NewHTTPError(echo.ErrBadRequest.Code, "Limits can't be negative"),
+			PrepareMock:  func(mockLimitApp *mocks.MockAppLimitInterface) {},
+		},
+		"deve retornar erro: usuario": {
+			Path:         "/v1/limit/user/:id",
+			Handler:      func(h *handler) echo.HandlerFunc { return h.updateUser },
+			InputRequest: dto.UpdateLimits{Daily: &daily},
+			ExpectedErr:  echo.ErrNotFound,
+			PrepareMock: func(mockLimitApp *mocks.MockAppLimitInterface) {
+				mockLimitApp.EXPECT().UpdateUser(gomock.Any(), gomock.Any()).Times(1).Return(nil, echo.ErrNotFound)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			mockLimitApp := mocks.NewMockAppLimitInterface(ctrl)
+			cs.PrepareMock(mockLimitApp)
+
+			api := &handler{
+				app: &app.Container{Limit: mockLimitApp},
+			}
+
+			e := echo.New()
+
+			requestBytes, _ := json.Marshal(cs.InputRequest)
+			req := httptest.NewRequest(http.MethodPut, "/v1/limit/user/user-id", bytes.NewReader(requestBytes)).WithContext(ctx)
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetPath(cs.Path)
+			c.SetParamNames("id")
+			c.SetParamValues("user-id")
+
+			err := cs.Handler(api)(c)
+			assert.Equal(t, cs.ExpectedErr, err)
+
+			if err == nil {
+				assertResponse(t, rec, limits)
+			}
+		})
+	}
+}
+
+func TestRead(t *testing.T) {
+	cases := map[string]struct {
+		Path        string
+		Method      string
+		Handler     func(h *handler) echo.HandlerFunc
+		ExpectedErr error
+		PrepareMock func(mockLimitApp *mocks.MockAppLimitInterface)
+	}{
+		"deve retornar sucesso: padrao": {
+			Path:    "/v1/limit/default",
+			Method:  http.MethodGet,
+			Handler: func(h *handler) echo.HandlerFunc { return h.readDefault },
+			PrepareMock: func(mockLimitApp *mocks.MockAppLimitInterface) {
+				mockLimitApp.EXPECT().ReadDefault(gomock.Any()).Times(1).Return(limits, nil)
+			},
+		},
+		"deve retornar erro: padrao": {
+			Path:        "/v1/limit/default",
+			Method:      http.MethodGet,
+			Handler:     func(h *handler) echo.HandlerFunc { return h.readDefault },
+			ExpectedErr: echo.ErrNotFound,
+			PrepareMock: func(mockLimitApp *mocks.MockAppLimitInterface) {
+				mockLimitApp.EXPECT().ReadDefault(gomock.Any()).Times(1).Return(nil, echo.ErrNotFound)
+			},
+		},
+		"deve retornar sucesso: usuario": {
+			Path:    "/v1/limit/user/:id",
+			Method:  http.MethodGet,
+			Handler: func(h *handler) echo.HandlerFunc { return h.readUser },
+			PrepareMock: func(mockLimitApp *mocks.MockAppLimitInterface) {
+				mockLimitApp.EXPECT().ReadByUser(gomock.Any(), "user-id").Times(1).Return(limits, nil)
+			},
+		},
+		"deve retornar erro: usuario": {
+			Path:        "/v1/limit/user/:id",
+			Method:      http.MethodGet,
+			Handler:     func(h *handler) echo.HandlerFunc { return h.readUser },
+			ExpectedErr: echo.ErrNotFound,
+			PrepareMock: func(mockLimitApp *mocks.MockAppLimitInterface) {
+				mockLimitApp.EXPECT().ReadByUser(gomock.Any(), "user-id").Times(1).Return(nil, echo.ErrNotFound)
+			},
+		},
+		"deve retornar sucesso: remover": {
+			Path:    "/v1/limit/user/:id",
+			Method:  http.MethodDelete,
+			Handler: func(h *handler) echo.HandlerFunc { return h.deleteUser },
+			PrepareMock: func(mockLimitApp *mocks.MockAppLimitInterface) {
+				mockLimitApp.EXPECT().DeleteUser(gomock.Any(), "user-id").Times(1).Return(limits, nil)
+			},
+		},
+		"deve retornar erro: remover": {
+			Path:        "/v1/limit/user/:id",
+			Method:      http.MethodDelete,
+			Handler:     func(h *handler) echo.HandlerFunc { return h.deleteUser },
+			ExpectedErr: echo.ErrNotFound,
+			PrepareMock: func(mockLimitApp *mocks.MockAppLimitInterface) {
+				mockLimitApp.EXPECT().DeleteUser(gomock.Any(), "user-id").Times(1).Return(nil, echo.ErrNotFound)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			mockLimitApp := mocks.NewMockAppLimitInterface(ctrl)
+			cs.PrepareMock(mockLimitApp)
+
+			api := &handler{
+				app: &app.Container{Limit: mockLimitApp},
+			}
+
+			e := echo.New()
+
+			req := httptest.NewRequest(cs.Method, "/v1/limit/user/user-id", nil).WithContext(ctx)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetPath(cs.Path)
+			c.SetParamNames("id")
+			c.SetParamValues("user-id")
+
+			err := cs.Handler(api)(c)
+			assert.Equal(t, cs.ExpectedErr, err)
+
+			if err == nil {
+				assertResponse(t, rec, limits)
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package standingorder
+
+import (
+	"net/http"
+
+	"github.com/garoque/backend-code-challenge-snapfi/internal/api/dto"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/api/idempotency"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/app"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	"github.com/labstack/echo/v4"
+)
+
+func Register(router *echo.Group, app *app.Container) {
+	h := &handler{app}
+
+	router.POST("", h.create, idempotency.Middleware(app))
+	router.GET("", h.readAll)
+	router.GET("/:id", h.readOne)
+	router.PUT("/:id", h.update, idempotency.Middleware(app))
+	router.DELETE("/:id", h.cancel)
+	router.GET("/:id/occurrences", h.readOccurrences)
+}
+
+type handler struct {
+	app *app.Container
+}
+
+// Create standing order godoc
+// @Summary Create standing order
+// @Description Repeat a transfer daily, weekly or monthly until an end date or a number of occurrences
+// @Tags standing-order
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateStandingOrder true "standing order request"
+// @Param Idempotency-Key header string false "key used to safely retry the request"
+// @Success 201 {object} entity.StandingOrder
+// @Failure 400 {object} error
+// @Failure 404 {object} error
+// @Failure 409 {object} error
+// @Failure 500 {object} error
+// @Router /standing-order [post]
+func (h *handler) create(c echo.Context) error {
+	var request dto.CreateStandingOrder
+	if err := c.Bind(&request); err != nil {
+		return err
+	}
+
+	if err := c.Validate(&request); err != nil {
+		return echo.ErrBadRequest
+	}
+
+	if !request.Amount.IsPositive() {
+		return echo.NewHTTPError(echo.ErrBadRequest.Code, "The provided value is zero or negative")
+	}
+
+	order, err := h.app.StandingOrder.Create(c.Request().Context(), entity.NewStandingOrder(request))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, dto.Response{Data: order})
+}
+
+// Read all standing orders godoc
+// @Summary Read all standing orders
+// @Description Read all standing orders
+// @Tags standing-order
+// @Accept json
+// @Produce json
+// @Success 200 {array} entity.StandingOrder
+// @Failure 500 {object} error
+// @Router /standing-order [get]
+func (h *handler) readAll(c echo.Context) error {
+	orders, err := h.app.StandingOrder.ReadAll(c.Request().Context())
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, dto.Response{Data: orders})
+}
+
+// Read standing order godoc
+// @Summary Read standing order
+// @Description Read standing order
+// @Tags standing-order
+// @Accept json
+// @Produce json
+// @Param id path string true "standing order id"
+// @Success 200 {object} entity.StandingOrder
+// @Failure 404 {object} error
+// @Router /standing-order/{id} [get]
+func (h *handler) readOne(c echo.Context) error {
+	order, err := h.app.StandingOrder.ReadOneById(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, dto.Response{Data: order})
+}
+
+// Update standing order godoc
+// @Summary Update standing order
+// @Description Change the amount, end date or count of an active standing order
+// @Tags standing-order
+// @Accept json
+// @Produce json
+// @Param id path string true "standing order id"
+// @Param request body dto.UpdateStandingOrder true "standing order changes"
+// @Param Idempotency-Key header string false "key used to safely retry the request"
+// @Success 200 {object} entity.StandingOrder
+// @Failure 400 {object} error
+// @Failure 404 {object} error
+// @Failure 409 {object} error
+// @Failure 500 {object} error
+// @Router /standing-order/{id} [put]
+func (h *handler) update(c echo.Context) error {
+	var request dto.UpdateStandingOrder
+	if err := c.Bind(&request); err != nil {
+		return err
+	}
+
+	if err := c.Validate(&request); err != nil {
+		return echo.ErrBadRequest
+	}
+
+	if request.Amount != nil && !request.Amount.IsPositive() {
+		return echo.NewHTTPError(echo.ErrBadRequest.Code, "The provided value is zero or negative")
+	}
+
+	order, err := h.app.StandingOrder.Update(c.Request().Context(), c.Param("id"), entity.NewStandingOrderChanges(request))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, dto.Response{Data: order})
+}
+
+// Cancel standing order godoc
+// @Summary Cancel standing order
+// @Description Stop an active standing order, its occurrences stay available
+// @Tags standing-order
+// @Accept json
+// @Produce json
+// @Param id path string true "standing order id"
+// @Success 200 {object} entity.StandingOrder
+// @Failure 404 {object} error
+// @Failure 409 {object} error
+// @Failure 500 {object} error
+// @Router /standing-order/{id} [delete]
+func (h *handler) cancel(c echo.Context) error {
+	order, err := h.app.StandingOrder.Cancel(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, dto.Response{Data: order})
+}
+
+// Read standing order occurrences godoc
+// @Summary Read standing order occurrences
+// @Description Read the executed occurrences of a standing order, booked or failed
+// @Tags standing-order
+// @Accept json
+// @Produce json
+// @Param id path string true "standing order id"
+// @Success 200 {array} entity.StandingOrderOccurrence
+// @Failure 404 {object} error
+// @Failure 500 {object} error
+// @Router /standing-order/{id}/occurrences [get]
+func (h *handler) readOccurrences(c echo.Context) error {
+	occurrences, err := h.app.StandingOrder.ReadOccurrences(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, dto.Response{Data: occurrences})
+}
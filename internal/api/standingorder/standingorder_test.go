@@ -0,0 +1,341 @@
+package standingorder
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/garoque/backend-code-challenge-snapfi/internal/api/dto"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/app"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/mocks"
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/money"
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/validator"
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+var order = &entity.StandingOrder{
+	ID:              "standing-order-id",
+	SourceId:        "source-user-id",
+	DestinationId:   "destination-user-id",
+	Amount:          money.New(15000),
+	Frequency:       entity.MONTHLY,
+	FrequencyString: "MONTHLY",
+	StartAt:         time.Date(2023, 5, 10, 12, 0, 0, 0, time.UTC),
+	State:           entity.ORDER_ACTIVE,
+	StateString:     "ACTIVE",
+}
+
+func assertResponse(t *testing.T, rec *httptest.ResponseRecorder, data interface{}) {
+	expectedResultJSON, err := json.Marshal(dto.Response{Data: data})
+	assert.NoError(t, err)
+
+	var expectedResult dto.Response
+	err = json.Unmarshal(expectedResultJSON, &expectedResult)
+	assert.NoError(t, err)
+
+	var currentResult dto.Response
+	json.NewDecoder(rec.Body).Decode(&currentResult)
+
+	assert.Equal(t, expectedResult, currentResult)
+}
+
+func TestCreate(t *testing.T) {
+	request := dto.CreateStandingOrder{
+		SourceUserId:      "source-user-id",
+		DestinationUserId: "destination-user-id",
+		Amount:            money.New(15000),
+		Frequency:         "MONTHLY",
+		StartAt:           time.Date(2023, 5, 10, 12, 0, 0, 0, time.UTC),
+	}
+
+	invalidFrequency := request
+	invalidFrequency.Frequency = "YEARLY"
+
+	zeroAmount := request
+	zeroAmount.Amount = money.New(0)
+
+	cases := map[string]struct {
+		InputOrder  dto.CreateStandingOrder
+		ExpectedErr error
+		PrepareMock func(mockStandingOrderApp *mocks.MockAppStandingOrderInterface)
+	}{
+		"deve retornar sucesso": {
+			InputOrder:  request,
+			ExpectedErr: nil,
+			PrepareMock: func(mockStandingOrderApp *mocks.MockAppStandingOrderInterface) {
+				mockStandingOrderApp.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(order, nil)
+			},
+		},
+		"deve retornar erro: frequencia invalida": {
+			InputOrder:  invalidFrequency,
+			ExpectedErr: echo.ErrBadRequest,
+			PrepareMock: func(mockStandingOrderApp *mocks.MockAppStandingOrderInterface) {},
+		},
+		"deve retornar erro: valor zerado": {
+			InputOrder:  zeroAmount,
+			ExpectedErr: echo.NewHTTPError(echo.ErrBadRequest.Code, "The provided value is zero or negative"),
+			PrepareMock: func(mockStandingOrderApp *mocks.MockAppStandingOrderInterface) {},
+		},
+		"deve retornar erro": {
+			InputOrder:  request,
+			ExpectedErr: echo.ErrInternalServerError,
+			PrepareMock: func(mockStandingOrderApp *mocks.MockAppStandingOrderInterface) {
+				mockStandingOrderApp.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil, echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			mockStandingOrderApp := mocks.NewMockAppStandingOrderInterface(ctrl)
+			cs.PrepareMock(mockStandingOrderApp)
+
+			api := handler{
+				app: &app.Container{StandingOrder: mockStandingOrderApp},
+			}
+
+			e := echo.New()
+			e.Validator = validator.NewValidator()
+
+			endpoint := "/v1/standing-order"
+
+			requestBytes, _ := json.Marshal(cs.InputOrder)
+			req := httptest.NewRequest(http.MethodPost, endpoint, bytes.NewReader(requestBytes)).WithContext(ctx)
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetPath(endpoint)
+
+			err := api.create(c)
+			assert.Equal(t, cs.ExpectedErr, err)
+
+			if err == nil {
+				assertResponse(t, rec, order)
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	amount := money.New(20000)
+	count := 0
+
+	cases := map[string]struct {
+		InputChanges dto.UpdateStandingOrder
+		ExpectedErr  error
+		PrepareMock  func(mockStandingOrderApp *mocks.MockAppStandingOrderInterface)
+	}{
+		"deve retornar sucesso": {
+			InputChanges: dto.UpdateStandingOrder{Amount: &amount},
+			ExpectedErr:  nil,
+			PrepareMock: func(mockStandingOrderApp *mocks.MockAppStandingOrderInterface) {
+				mockStandingOrderApp.EXPECT().Update(gomock.Any(), "standing-order-id", &entity.StandingOrderChanges{Amount: &amount}).Times(1).Return(order, nil)
+			},
+		},
+		"deve retornar erro: count invalido": {
+			InputChanges: dto.UpdateStandingOrder{Count: &count},
+			ExpectedErr:  echo.ErrBadRequest,
+			PrepareMock:  func(mockStandingOrderApp *mocks.MockAppStandingOrderInterface) {},
+		},
+		"deve retornar erro": {
+			InputChanges: dto.UpdateStandingOrder{Amount: &amount},
+			ExpectedErr:  echo.ErrNotFound,
+			PrepareMock: func(mockStandingOrderApp *mocks.MockAppStandingOrderInterface) {
+				mockStandingOrderApp.EXPECT().Update(gomock.Any(), "standing-order-id", gomock.Any()).Times(1).Return(nil, echo.ErrNotFound)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			mockStandingOrderApp := mocks.NewMockAppStandingOrderInterface(ctrl)
+			cs.PrepareMock(mockStandingOrderApp)
+
+			api := handler{
+				app: &app.Container{StandingOrder: mockStandingOrderApp},
+			}
+
+			e := echo.New()
+			e.Validator = validator.NewValidator()
+
+			requestBytes, _ := json.Marshal(cs.InputChanges)
+			req := httptest.NewRequest(http.MethodPut, "/v1/standing-order/standing-order-id", bytes.NewReader(requestBytes)).WithContext(ctx)
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetPath("/v1/standing-order/:id")
+			c.SetParamNames("id")
+			c.SetParamValues("standing-order-id")
+
+			err := api.update(c)
+			assert.Equal(t, cs.ExpectedErr, err)
+
+			if err == nil {
+				assertResponse(t, rec, order)
+			}
+		})
+	}
+}
+
+func TestCancel(t *testing.T) {
+	cases := map[string]struct {
+		ExpectedErr error
+		PrepareMock func(mockStandingOrderApp *mocks.MockAppStandingOrderInterface)
+	}{
+		"deve retornar sucesso": {
+			ExpectedErr: nil,
+			PrepareMock: func(mockStandingOrderApp *mocks.MockAppStandingOrderInterface) {
+				mockStandingOrderApp.EXPECT().Cancel(gomock.Any(), "standing-order-id").Times(1).Return(order, nil)
+			},
+		},
+		"deve retornar erro": {
+			ExpectedErr: echo.ErrNotFound,
+			PrepareMock: func(mockStandingOrderApp *mocks.MockAppStandingOrderInterface) {
+				mockStandingOrderApp.EXPECT().Cancel(gomock.Any(), "standing-order-id").Times(1).Return(nil, echo.ErrNotFound)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			mockStandingOrderApp := mocks.NewMockAppStandingOrderInterface(ctrl)
+			cs.PrepareMock(mockStandingOrderApp)
+
+			api := handler{
+				app: &app.Container{StandingOrder: mockStandingOrderApp},
+			}
+
+			e := echo.New()
+
+			req := httptest.NewRequest(http.MethodDelete, "/v1/standing-order/standing-order-id", nil).WithContext(ctx)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetPath("/v1/standing-order/:id")
+			c.SetParamNames("id")
+			c.SetParamValues("standing-order-id")
+
+			err := api.cancel(c)
+			assert.Equal(t, cs.ExpectedErr, err)
+
+			if err == nil {
+				assertResponse(t, rec, order)
+			}
+		})
+	}
+}
+
+func TestRead(t *testing.T) {
+	occurrences := []entity.StandingOrderOccurrence{{
+		StandingOrderId: "standing-order-id",
+		Sequence:        1,
+		TransactionId:   "transaction-id",
+		State:           entity.BOOKED,
+		StateString:     "BOOKED",
+		Attempts:        1,
+	}}
+
+	cases := map[string]struct {
+		Path           string
+		Handler        func(h *handler) echo.HandlerFunc
+		ExpectedResult interface{}
+		ExpectedErr    error
+		PrepareMock    func(mockStandingOrderApp *mocks.MockAppStandingOrderInterface)
+	}{
+		"deve retornar sucesso: todas": {
+			Path:           "/v1/standing-order",
+			Handler:        func(h *handler) echo.HandlerFunc { return h.readAll },
+			ExpectedResult: []entity.StandingOrder{*order},
+			PrepareMock: func(mockStandingOrderApp *mocks.MockAppStandingOrderInterface) {
+				mockStandingOrderApp.EXPECT().ReadAll(gomock.Any()).Times(1).Return([]entity.StandingOrder{*order}, nil)
+			},
+		},
+		"deve retornar erro: todas": {
+			Path:        "/v1/standing-order",
+			Handler:     func(h *handler) echo.HandlerFunc { return h.readAll },
+			ExpectedErr: echo.ErrInternalServerError,
+			PrepareMock: func(mockStandingOrderApp *mocks.MockAppStandingOrderInterface) {
+				mockStandingOrderApp.EXPECT().ReadAll(gomock.Any()).Times(1).Return(nil, echo.ErrInternalServerError)
+			},
+		},
+		"deve retornar sucesso: uma": {
+			Path:           "/v1/standing-order/:id",
+			Handler:        func(h *handler) echo.HandlerFunc { return h.readOne },
+			ExpectedResult: order,
+			PrepareMock: func(mockStandingOrderApp *mocks.MockAppStandingOrderInterface) {
+				mockStandingOrderApp.EXPECT().ReadOneById(gomock.Any(), "standing-order-id").Times(1).Return(order, nil)
+			},
+		},
+		"deve retornar erro: uma": {
+			Path:        "/v1/standing-order/:id",
+			Handler:     func(h *handler) echo.HandlerFunc { return h.readOne },
+			ExpectedErr: echo.ErrNotFound,
+			PrepareMock: func(mockStandingOrderApp *mocks.MockAppStandingOrderInterface) {
+				mockStandingOrderApp.EXPECT().ReadOneById(gomock.Any(), "standing-order-id").Times(1).Return(nil, echo.ErrNotFound)
+			},
+		},
+		"deve retornar sucesso: ocorrencias": {
+			Path:           "/v1/standing-order/:id/occurrences",
+			Handler:        func(h *handler) echo.HandlerFunc { return h.readOccurrences },
+			ExpectedResult: occurrences,
+			PrepareMock: func(mockStandingOrderApp *mocks.MockAppStandingOrderInterface) {
+				mockStandingOrderApp.EXPECT().ReadOccurrences(gomock.Any(), "standing-order-id").Times(1).Return(occurrences, nil)
+			},
+		},
+		"deve retornar erro: ocorrencias": {
+			Path:        "/v1/standing-order/:id/occurrences",
+			Handler:     func(h *handler) echo.HandlerFunc { return h.readOccurrences },
+			ExpectedErr: echo.ErrNotFound,
+			PrepareMock: func(mockStandingOrderApp *mocks.MockAppStandingOrderInterface) {
+				mockStandingOrderApp.EXPECT().ReadOccurrences(gomock.Any(), "standing-order-id").Times(1).Return(nil, echo.ErrNotFound)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			mockStandingOrderApp := mocks.NewMockAppStandingOrderInterface(ctrl)
+			cs.PrepareMock(mockStandingOrderApp)
+
+			api := &handler{
+				app: &app.Container{StandingOrder: mockStandingOrderApp},
+			}
+
+			e := echo.New()
+
+			req := httptest.NewRequest(http.MethodGet, "/v1/standing-order/standing-order-id", nil).WithContext(ctx)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetPath(cs.Path)
+			c.SetParamNames("id")
+			c.SetParamValues("standing-order-id")
+
+			err := cs.Handler(api)(c)
+			assert.Equal(t, cs.ExpectedErr, err)
+
+			if err == nil {
+				assertResponse(t, rec, cs.ExpectedResult)
+			}
+		})
+	}
+}
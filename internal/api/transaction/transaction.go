@@ -1,21 +1,34 @@
 package transaction
 
 import (
-	"math"
 	"net/http"
 
 	"github.com/garoque/backend-code-challenge-snapfi/internal/api/dto"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/api/idempotency"
 	"github.com/garoque/backend-code-challenge-snapfi/internal/app"
 	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/money"
 	"github.com/labstack/echo/v4"
 )
 
 func Register(router *echo.Group, app *app.Container) {
 	h := &handler{app}
 
-	router.POST("", h.create)
-	router.PUT("/increase-balance", h.increaseBalance)
+	router.POST("", h.create, idempotency.Middleware(app))
+	router.POST("/batch", h.createBatch, idempotency.Middleware(app))
+	router.POST("/split", h.createSplit, idempotency.Middleware(app))
+	router.PUT("/increase-balance", h.increaseBalance, idempotency.Middleware(app))
+	router.POST("/withdraw", h.withdraw, idempotency.Middleware(app))
+	router.POST("/:id/reverse", h.reverse, idempotency.Middleware(app))
+	router.POST("/authorize", h.authorize, idempotency.Middleware(app))
+	router.POST("/:id/capture", h.capture, idempotency.Middleware(app))
+	router.POST("/:id/void", h.void, idempotency.Middleware(app))
+	router.POST("/:id/cancel", h.cancelScheduled, idempotency.Middleware(app))
 	router.GET("", h.readAll)
+	router.GET("/scheduled", h.readScheduled)
+	router.GET("/batch/:id", h.readBatch)
+	router.GET("/split/:id", h.readSplit)
+	router.GET("/:id/history", h.readHistory)
 }
 
 type handler struct {
@@ -29,21 +42,23 @@ type handler struct {
 // @Accept json
 // @Produce json
 // @Param request body dto.CreateTransaction true "transaction request"
+// @Param Idempotency-Key header string false "key used to safely retry the request"
 // @Success 201 {object} entity.Transaction
 // @Failure 400 {object} error
+// @Failure 409 {object} error
 // @Failure 500 {object} error
 // @Router /transaction [post]
 func (h *handler) create(c echo.Context) error {
 	var transaction dto.CreateTransaction
 	if err := c.Bind(&transaction); err != nil {
-		return echo.ErrInternalServerError
+		return err
 	}
 
 	if err := c.Validate(&transaction); err != nil {
 		return echo.ErrBadRequest
 	}
 
-	if math.Signbit(transaction.Amount) {
+	if !transaction.Amount.IsPositive() {
 		return echo.NewHTTPError(echo.ErrBadRequest.Code, "The provided value is zero or negative")
 	}
 
@@ -55,6 +70,118 @@ func (h *handler) create(c echo.Context) error {
 	return c.JSON(http.StatusCreated, dto.Response{Data: balance})
 }
 
+// Create batch godoc
+// @Summary Create batch
+// @Description Book up to 100 transfers at once, all or nothing when the batch is atomic
+// @Tags transaction
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateBatch true "batch request"
+// @Param Idempotency-Key header string false "key used to safely retry the request"
+// @Success 201 {object} entity.Batch
+// @Failure 400 {object} error
+// @Failure 409 {object} error
+// @Failure 500 {object} error
+// @Router /transaction/batch [post]
+func (h *handler) createBatch(c echo.Context) error {
+	var request dto.CreateBatch
+	if err := c.Bind(&request); err != nil {
+		return err
+	}
+
+	if err := c.Validate(&request); err != nil {
+		return echo.ErrBadRequest
+	}
+
+	for _, transaction := range request.Transactions {
+		if !transaction.Amount.IsPositive() {
+			return echo.NewHTTPError(echo.ErrBadRequest.Code, "The provided value is zero or negative")
+		}
+	}
+
+	batch, err := h.app.Transaction.CreateBatch(c.Request().Context(), entity.NewBatch(request))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, dto.Response{Data: batch})
+}
+
+// Read batch godoc
+// @Summary Read batch
+// @Description Read a batch and how each of its transfers ended
+// @Tags transaction
+// @Accept json
+// @Produce json
+// @Param id path string true "batch id"
+// @Success 200 {object} entity.Batch
+// @Failure 404 {object} error
+// @Failure 500 {object} error
+// @Router /transaction/batch/{id} [get]
+func (h *handler) readBatch(c echo.Context) error {
+	batch, err := h.app.Transaction.ReadBatch(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, dto.Response{Data: batch})
+}
+
+// Create split godoc
+// @Summary Create split
+// @Description Split one payment among several destinations by fixed amount or percentage
+// @Tags transaction
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateSplit true "split request"
+// @Param Idempotency-Key header string false "key used to safely retry the request"
+// @Success 201 {object} entity.Split
+// @Failure 400 {object} error
+// @Failure 409 {object} error
+// @Failure 500 {object} error
+// @Router /transaction/split [post]
+func (h *handler) createSplit(c echo.Context) error {
+	var request dto.CreateSplit
+	if err := c.Bind(&request); err != nil {
+		return err
+	}
+
+	if err := c.Validate(&request); err != nil {
+		return echo.ErrBadRequest
+	}
+
+	if !request.Amount.IsPositive() {
+		return echo.NewHTTPError(echo.ErrBadRequest.Code, "The provided value is zero or negative")
+	}
+
+	split, err := h.app.Transaction.CreateSplit(c.Request().Context(), entity.NewSplit(request))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, dto.Response{Data: split})
+}
+
+// Read split godoc
+// @Summary Read split
+// @Description Read the transactions of a split payment
+// @Tags transaction
+// @Accept json
+// @Produce json
+// @Param id path string true "split id"
+// @Success 200 {object} entity.Split
+// @Failure 404 {object} error
+// @Failure 500 {object} error
+// @Router /transaction/split/{id} [get]
+func (h *handler) readSplit(c echo.Context) error {
+	split, err := h.app.Transaction.ReadSplit(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, dto.Response{Data: split})
+}
+
 // Increase balance user godoc
 // @Summary Increase balance user
 // @Description Increase balance user
@@ -62,21 +189,23 @@ func (h *handler) create(c echo.Context) error {
 // @Accept json
 // @Produce json
 // @Param request body dto.IncreaseBalanceUser true "increase balance request"
-// @Success 200 {object} float64
+// @Param Idempotency-Key header string false "key used to safely retry the request"
+// @Success 200 {object} string
 // @Failure 400 {object} error
+// @Failure 409 {object} error
 // @Failure 500 {object} error
 // @Router /transaction/increase-balance [put]
 func (h *handler) increaseBalance(c echo.Context) error {
 	var transaction dto.IncreaseBalanceUser
 	if err := c.Bind(&transaction); err != nil {
-		return echo.ErrInternalServerError
+		return err
 	}
 
 	if err := c.Validate(&transaction); err != nil {
 		return echo.ErrBadRequest
 	}
 
-	if math.Signbit(transaction.Value) {
+	if !transaction.Value.IsPositive() {
 		return echo.NewHTTPError(echo.ErrBadRequest.Code, "The provided value is zero or negative")
 	}
 
@@ -88,6 +217,194 @@ func (h *handler) increaseBalance(c echo.Context) error {
 	return c.JSON(http.StatusOK, dto.Response{Data: balance})
 }
 
+// Withdraw godoc
+// @Summary Withdraw
+// @Description Move money out of a user balance
+// @Tags transaction
+// @Accept json
+// @Produce json
+// @Param request body dto.Withdraw true "withdraw request"
+// @Param Idempotency-Key header string false "key used to safely retry the request"
+// @Success 201 {object} entity.Transaction
+// @Failure 400 {object} error
+// @Failure 409 {object} error
+// @Failure 500 {object} error
+// @Router /transaction/withdraw [post]
+func (h *handler) withdraw(c echo.Context) error {
+	var request dto.Withdraw
+	if err := c.Bind(&request); err != nil {
+		return err
+	}
+
+	if err := c.Validate(&request); err != nil {
+		return echo.ErrBadRequest
+	}
+
+	if !request.Amount.IsPositive() {
+		return echo.NewHTTPError(echo.ErrBadRequest.Code, "The provided value is zero or negative")
+	}
+
+	transaction, err := h.app.Transaction.Withdraw(c.Request().Context(), entity.NewWithdrawal(request))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, dto.Response{Data: transaction})
+}
+
+// Reverse transaction godoc
+// @Summary Reverse transaction
+// @Description Fully or partially refund a booked transaction through a linked reversal
+// @Tags transaction
+// @Accept json
+// @Produce json
+// @Param id path string true "transaction id"
+// @Param request body dto.ReverseTransaction false "reverse request, omit the amount to reverse what is left"
+// @Param Idempotency-Key header string false "key used to safely retry the request"
+// @Success 201 {object} entity.Transaction
+// @Failure 400 {object} error
+// @Failure 404 {object} error
+// @Failure 409 {object} error
+// @Failure 500 {object} error
+// @Router /transaction/{id}/reverse [post]
+func (h *handler) reverse(c echo.Context) error {
+	var request dto.ReverseTransaction
+	if err := c.Bind(&request); err != nil {
+		return err
+	}
+
+	var amount money.Money
+	if request.Amount != nil {
+		if !request.Amount.IsPositive() {
+			return echo.NewHTTPError(echo.ErrBadRequest.Code, "The provided value is zero or negative")
+		}
+		amount = *request.Amount
+	}
+
+	reversal, err := h.app.Transaction.Reverse(c.Request().Context(), c.Param("id"), amount)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, dto.Response{Data: reversal})
+}
+
+// Authorize transaction godoc
+// @Summary Authorize transaction
+// @Description Hold funds on the source user until the authorization is captured, voided or expires
+// @Tags transaction
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateAuthorization true "authorization request"
+// @Param Idempotency-Key header string false "key used to safely retry the request"
+// @Success 201 {object} entity.Transaction
+// @Failure 400 {object} error
+// @Failure 409 {object} error
+// @Failure 500 {object} error
+// @Router /transaction/authorize [post]
+func (h *handler) authorize(c echo.Context) error {
+	var request dto.CreateAuthorization
+	if err := c.Bind(&request); err != nil {
+		return err
+	}
+
+	if err := c.Validate(&request); err != nil {
+		return echo.ErrBadRequest
+	}
+
+	if !request.Amount.IsPositive() {
+		return echo.NewHTTPError(echo.ErrBadRequest.Code, "The provided value is zero or negative")
+	}
+
+	transaction, err := h.app.Transaction.Authorize(c.Request().Context(), entity.NewAuthorization(request))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, dto.Response{Data: transaction})
+}
+
+// Capture transaction godoc
+// @Summary Capture transaction
+// @Description Book an authorized transaction, fully or partially
+// @Tags transaction
+// @Accept json
+// @Produce json
+// @Param id path string true "transaction id"
+// @Param request body dto.CaptureAuthorization false "capture request, omit the amount to capture everything authorized"
+// @Param Idempotency-Key header string false "key used to safely retry the request"
+// @Success 200 {object} entity.Transaction
+// @Failure 400 {object} error
+// @Failure 404 {object} error
+// @Failure 409 {object} error
+// @Failure 500 {object} error
+// @Router /transaction/{id}/capture [post]
+func (h *handler) capture(c echo.Context) error {
+	var request dto.CaptureAuthorization
+	if err := c.Bind(&request); err != nil {
+		return err
+	}
+
+	var amount money.Money
+	if request.Amount != nil {
+		if !request.Amount.IsPositive() {
+			return echo.NewHTTPError(echo.ErrBadRequest.Code, "The provided value is zero or negative")
+		}
+		amount = *request.Amount
+	}
+
+	transaction, err := h.app.Transaction.Capture(c.Request().Context(), c.Param("id"), amount)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, dto.Response{Data: transaction})
+}
+
+// Void transaction godoc
+// @Summary Void transaction
+// @Description Cancel an authorized transaction and release its funds
+// @Tags transaction
+// @Accept json
+// @Produce json
+// @Param id path string true "transaction id"
+// @Param Idempotency-Key header string false "key used to safely retry the request"
+// @Success 200 {object} entity.Transaction
+// @Failure 404 {object} error
+// @Failure 409 {object} error
+// @Failure 500 {object} error
+// @Router /transaction/{id}/void [post]
+func (h *handler) void(c echo.Context) error {
+	transaction, err := h.app.Transaction.Void(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, dto.Response{Data: transaction})
+}
+
+// Cancel scheduled transaction godoc
+// @Summary Cancel scheduled transaction
+// @Description Cancel a scheduled transaction before it is executed
+// @Tags transaction
+// @Accept json
+// @Produce json
+// @Param id path string true "transaction id"
+// @Param Idempotency-Key header string false "key used to safely retry the request"
+// @Success 200 {object} entity.Transaction
+// @Failure 404 {object} error
+// @Failure 409 {object} error
+// @Failure 500 {object} error
+// @Router /transaction/{id}/cancel [post]
+func (h *handler) cancelScheduled(c echo.Context) error {
+	transaction, err := h.app.Transaction.CancelScheduled(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, dto.Response{Data: transaction})
+}
+
 // Read all transactions godoc
 // @Summary Read all transactions
 // @Description Read all transactions
@@ -105,3 +422,41 @@ func (h *handler) readAll(c echo.Context) error {
 
 	return c.JSON(http.StatusOK, dto.Response{Data: transactions})
 }
+
+// Read scheduled transactions godoc
+// @Summary Read scheduled transactions
+// @Description Read transactions waiting for their execution date
+// @Tags transaction
+// @Accept json
+// @Produce json
+// @Success 200 {array} entity.Transaction
+// @Failure 500 {object} error
+// @Router /transaction/scheduled [get]
+func (h *handler) readScheduled(c echo.Context) error {
+	transactions, err := h.app.Transaction.ReadScheduled(c.Request().Context())
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, dto.Response{Data: transactions})
+}
+
+// Read transaction history godoc
+// @Summary Read transaction history
+// @Description Read every state a transaction went through
+// @Tags transaction
+// @Accept json
+// @Produce json
+// @Param id path string true "transaction id"
+// @Success 200 {array} entity.StateTransition
+// @Failure 404 {object} error
+// @Failure 500 {object} error
+// @Router /transaction/{id}/history [get]
+func (h *handler) readHistory(c echo.Context) error {
+	history, err := h.app.Transaction.ReadHistory(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, dto.Response{Data: history})
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/garoque/backend-code-challenge-snapfi/internal/app"
 	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
 	"github.com/garoque/backend-code-challenge-snapfi/internal/mocks"
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/money"
 	"github.com/garoque/backend-code-challenge-snapfi/pkg/uuid"
 	"github.com/garoque/backend-code-challenge-snapfi/pkg/validator"
 	"github.com/golang/mock/gomock"
@@ -23,7 +24,7 @@ func TestCreate(t *testing.T) {
 	request := dto.CreateTransaction{
 		SourceUserId:      "1234",
 		DestinationUserId: "5678",
-		Amount:            100.0,
+		Amount:            money.New(10000),
 	}
 
 	transaction := &entity.Transaction{
@@ -105,14 +106,14 @@ func TestCreate(t *testing.T) {
 func TestIncreaseBalance(t *testing.T) {
 	transaction := dto.IncreaseBalanceUser{
 		UserId: "user-id",
-		Value:  100.10,
+		Value:  money.New(10010),
 	}
 
 	balance := transaction.Value
 
 	cases := map[string]struct {
 		InputTransaction dto.IncreaseBalanceUser
-		ExpectedResult   float64
+		ExpectedResult   money.Money
 		ExpectedErr      error
 		PrepareMock      func(mockTransactionApp *mocks.MockAppTransactionInterface)
 	}{
@@ -126,10 +127,10 @@ func TestIncreaseBalance(t *testing.T) {
 		},
 		"deve retornar erro": {
 			InputTransaction: transaction,
-			ExpectedResult:   0,
+			ExpectedResult:   money.Money{},
 			ExpectedErr:      echo.ErrInternalServerError,
 			PrepareMock: func(mockTransactionApp *mocks.MockAppTransactionInterface) {
-				mockTransactionApp.EXPECT().IncreaseBalanceUser(gomock.Any(), gomock.Any()).Times(1).Return(float64(0), echo.ErrInternalServerError)
+				mockTransactionApp.EXPECT().IncreaseBalanceUser(gomock.Any(), gomock.Any()).Times(1).Return(money.Money{}, echo.ErrInternalServerError)
 			},
 		},
 	}
@@ -179,11 +180,194 @@ func TestIncreaseBalance(t *testing.T) {
 	}
 }
 
+func TestWithdraw(t *testing.T) {
+	request := dto.Withdraw{
+		UserId: "user-id",
+		Amount: money.New(10010),
+	}
+
+	transaction := &entity.Transaction{
+		ID:            uuid.NewId(),
+		SourceId:      request.UserId,
+		DestinationId: entity.WithdrawalAccountId,
+		Amount:        request.Amount,
+		Kind:          entity.WITHDRAWAL,
+	}
+
+	cases := map[string]struct {
+		InputRequest dto.Withdraw
+		ExpectedErr  error
+		PrepareMock  func(mockTransactionApp *mocks.MockAppTransactionInterface)
+	}{
+		"deve retornar sucesso": {
+			InputRequest: request,
+			ExpectedErr:  nil,
+			PrepareMock: func(mockTransactionApp *mocks.MockAppTransactionInterface) {
+				mockTransactionApp.EXPECT().Withdraw(gomock.Any(), gomock.Any()).Times(1).Return(transaction, nil)
+			},
+		},
+		"deve retornar erro": {
+			InputRequest: request,
+			ExpectedErr:  echo.ErrInternalServerError,
+			PrepareMock: func(mockTransactionApp *mocks.MockAppTransactionInterface) {
+				mockTransactionApp.EXPECT().Withdraw(gomock.Any(), gomock.Any()).Times(1).Return(transaction, echo.ErrInternalServerError)
+			},
+		},
+		"deve retornar erro: usuario e conta": {
+			InputRequest: dto.Withdraw{UserId: "user-id", AccountId: "account-id", Amount: money.New(10010)},
+			ExpectedErr:  echo.ErrBadRequest,
+			PrepareMock:  func(mockTransactionApp *mocks.MockAppTransactionInterface) {},
+		},
+		"deve retornar erro: valor invalido": {
+			InputRequest: dto.Withdraw{UserId: "user-id", Amount: money.New(0)},
+			ExpectedErr:  echo.NewHTTPError(echo.ErrBadRequest.Code, "The provided value is zero or negative"),
+			PrepareMock:  func(mockTransactionApp *mocks.MockAppTransactionInterface) {},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			mockTransactionApp := mocks.NewMockAppTransactionInterface(ctrl)
+			cs.PrepareMock(mockTransactionApp)
+
+			api := handler{
+				app: &app.Container{Transaction: mockTransactionApp},
+			}
+
+			e := echo.New()
+			e.Validator = validator.NewValidator()
+
+			endpoint := "/v1/transaction/withdraw"
+
+			requestBytes, _ := json.Marshal(cs.InputRequest)
+			req := httptest.NewRequest(http.MethodPost, endpoint, bytes.NewReader(requestBytes)).WithContext(ctx)
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetPath(endpoint)
+
+			err := api.withdraw(c)
+			assert.Equal(t, cs.ExpectedErr, err)
+
+			if err == nil {
+				expectedResultJSON, err := json.Marshal(dto.Response{Data: transaction})
+				assert.NoError(t, err)
+
+				var expectedResult dto.Response
+				err = json.Unmarshal(expectedResultJSON, &expectedResult)
+				assert.NoError(t, err)
+
+				var currentResult dto.Response
+				json.NewDecoder(rec.Body).Decode(&currentResult)
+
+				assert.Equal(t, expectedResult, currentResult)
+			}
+		})
+	}
+}
+
+func TestReverse(t *testing.T) {
+	amount := money.New(3000)
+	original := "original-id"
+
+	reversal := &entity.Transaction{
+		ID:            uuid.NewId(),
+		SourceId:      "destination-user-id",
+		DestinationId: "source-user-id",
+		Amount:        amount,
+		Kind:          entity.REVERSAL,
+		OriginalId:    &original,
+	}
+
+	cases := map[string]struct {
+		InputRequest dto.ReverseTransaction
+		ExpectedErr  error
+		PrepareMock  func(mockTransactionApp *mocks.MockAppTransactionInterface)
+	}{
+		"deve retornar sucesso: estorno parcial": {
+			InputRequest: dto.ReverseTransaction{Amount: &amount},
+			ExpectedErr:  nil,
+			PrepareMock: func(mockTransactionApp *mocks.MockAppTransactionInterface) {
+				mockTransactionApp.EXPECT().Reverse(gomock.Any(), original, amount).Times(1).Return(reversal, nil)
+			},
+		},
+		"deve retornar sucesso: estorno total": {
+			InputRequest: dto.ReverseTransaction{},
+			ExpectedErr:  nil,
+			PrepareMock: func(mockTransactionApp *mocks.MockAppTransactionInterface) {
+				mockTransactionApp.EXPECT().Reverse(gomock.Any(), original, money.Money{}).Times(1).Return(reversal, nil)
+			},
+		},
+		"deve retornar erro": {
+			InputRequest: dto.ReverseTransaction{},
+			ExpectedErr:  echo.ErrNotFound,
+			PrepareMock: func(mockTransactionApp *mocks.MockAppTransactionInterface) {
+				mockTransactionApp.EXPECT().Reverse(gomock.Any(), original, money.Money{}).Times(1).Return(nil, echo.ErrNotFound)
+			},
+		},
+		"deve retornar erro: valor invalido": {
+			InputRequest: dto.ReverseTransaction{Amount: &money.Money{}},
+			ExpectedErr:  echo.NewHTTPError(echo.ErrBadRequest.Code, "The provided value is zero or negative"),
+			PrepareMock:  func(mockTransactionApp *mocks.MockAppTransactionInterface) {},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			mockTransactionApp := mocks.NewMockAppTransactionInterface(ctrl)
+			cs.PrepareMock(mockTransactionApp)
+
+			api := handler{
+				app: &app.Container{Transaction: mockTransactionApp},
+			}
+
+			e := echo.New()
+			e.Validator = validator.NewValidator()
+
+			endpoint := "/v1/transaction/:id/reverse"
+
+			requestBytes, _ := json.Marshal(cs.InputRequest)
+			req := httptest.NewRequest(http.MethodPost, "/v1/transaction/"+original+"/reverse", bytes.NewReader(requestBytes)).WithContext(ctx)
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetPath(endpoint)
+			c.SetParamNames("id")
+			c.SetParamValues(original)
+
+			err := api.reverse(c)
+			assert.Equal(t, cs.ExpectedErr, err)
+
+			if err == nil {
+				expectedResultJSON, err := json.Marshal(dto.Response{Data: reversal})
+				assert.NoError(t, err)
+
+				var expectedResult dto.Response
+				err = json.Unmarshal(expectedResultJSON, &expectedResult)
+				assert.NoError(t, err)
+
+				var currentResult dto.Response
+				json.NewDecoder(rec.Body).Decode(&currentResult)
+
+				assert.Equal(t, expectedResult, currentResult)
+			}
+		})
+	}
+}
+
 func TestReadAll(t *testing.T) {
 	transaction := entity.NewTransaction(dto.CreateTransaction{
 		SourceUserId:      "source-user-id",
 		DestinationUserId: "destination-user-id",
-		Amount:            100.10,
+		Amount:            money.New(10010),
 	})
 	transactions := []entity.Transaction{{
 		ID:            transaction.ID,
@@ -252,3 +436,767 @@ func TestReadAll(t *testing.T) {
 		})
 	}
 }
+
+func TestReadHistory(t *testing.T) {
+	history := []entity.StateTransition{{
+		TransactionId: "transaction-id",
+		From:          entity.PENDING,
+		FromString:    "PENDING",
+		To:            entity.BOOKED,
+		ToString:      "BOOKED",
+		Reason:        "transfer booked",
+	}}
+
+	cases := map[string]struct {
+		ExpectedErr error
+		PrepareMock func(mockTransactionApp *mocks.MockAppTransactionInterface)
+	}{
+		"deve retornar sucesso": {
+			ExpectedErr: nil,
+			PrepareMock: func(mockTransactionApp *mocks.MockAppTransactionInterface) {
+				mockTransactionApp.EXPECT().ReadHistory(gomock.Any(), "transaction-id").Times(1).Return(history, nil)
+			},
+		},
+		"deve retornar erro": {
+			ExpectedErr: echo.ErrNotFound,
+			PrepareMock: func(mockTransactionApp *mocks.MockAppTransactionInterface) {
+				mockTransactionApp.EXPECT().ReadHistory(gomock.Any(), "transaction-id").Times(1).Return(nil, echo.ErrNotFound)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			mockTransactionApp := mocks.NewMockAppTransactionInterface(ctrl)
+			cs.PrepareMock(mockTransactionApp)
+
+			api := handler{
+				app: &app.Container{Transaction: mockTransactionApp},
+			}
+
+			e := echo.New()
+
+			endpoint := "/v1/transaction/:id/history"
+			req := httptest.NewRequest(http.MethodGet, "/v1/transaction/transaction-id/history", nil).WithContext(ctx)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetPath(endpoint)
+			c.SetParamNames("id")
+			c.SetParamValues("transaction-id")
+
+			err := api.readHistory(c)
+			assert.Equal(t, cs.ExpectedErr, err)
+
+			if err == nil {
+				expectedResultJSON, err := json.Marshal(dto.Response{Data: history})
+				assert.NoError(t, err)
+
+				var expectedResult dto.Response
+				err = json.Unmarshal(expectedResultJSON, &expectedResult)
+				assert.NoError(t, err)
+
+				var currentResult dto.Response
+				json.NewDecoder(rec.Body).Decode(&currentResult)
+
+				assert.Equal(t, expectedResult, currentResult)
+			}
+		})
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	request := dto.CreateAuthorization{
+		SourceUserId:      "1234",
+		DestinationUserId: "5678",
+		Amount:            money.New(10000),
+	}
+
+	transaction := &entity.Transaction{
+		ID:            uuid.NewId(),
+		SourceId:      request.SourceUserId,
+		DestinationId: request.DestinationUserId,
+		Amount:        request.Amount,
+		State:         entity.AUTHORIZED,
+		StateString:   entity.AUTHORIZED.String(),
+	}
+
+	cases := map[string]struct {
+		InputRequest dto.CreateAuthorization
+		ExpectedErr  error
+		PrepareMock  func(mockTransactionApp *mocks.MockAppTransactionInterface)
+	}{
+		"deve retornar sucesso": {
+			InputRequest: request,
+			ExpectedErr:  nil,
+			PrepareMock: func(mockTransactionApp *mocks.MockAppTransactionInterface) {
+				mockTransactionApp.EXPECT().Authorize(gomock.Any(), gomock.Any()).Times(1).Return(transaction, nil)
+			},
+		},
+		"deve retornar erro": {
+			InputRequest: request,
+			ExpectedErr:  echo.ErrInternalServerError,
+			PrepareMock: func(mockTransactionApp *mocks.MockAppTransactionInterface) {
+				mockTransactionApp.EXPECT().Authorize(gomock.Any(), gomock.Any()).Times(1).Return(nil, echo.ErrInternalServerError)
+			},
+		},
+		"deve retornar erro: valor invalido": {
+			InputRequest: dto.CreateAuthorization{SourceUserId: "1234", DestinationUserId: "5678", Amount: money.New(0)},
+			ExpectedErr:  echo.NewHTTPError(echo.ErrBadRequest.Code, "The provided value is zero or negative"),
+			PrepareMock:  func(mockTransactionApp *mocks.MockAppTransactionInterface) {},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			mockTransactionApp := mocks.NewMockAppTransactionInterface(ctrl)
+			cs.PrepareMock(mockTransactionApp)
+
+			api := handler{
+				app: &app.Container{Transaction: mockTransactionApp},
+			}
+
+			e := echo.New()
+			e.Validator = validator.NewValidator()
+
+			endpoint := "/v1/transaction/authorize"
+
+			requestBytes, _ := json.Marshal(cs.InputRequest)
+			req := httptest.NewRequest(http.MethodPost, endpoint, bytes.NewReader(requestBytes)).WithContext(ctx)
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetPath(endpoint)
+
+			err := api.authorize(c)
+			assert.Equal(t, cs.ExpectedErr, err)
+
+			if err == nil {
+				assert.Equal(t, http.StatusCreated, rec.Code)
+			}
+		})
+	}
+}
+
+func TestCapture(t *testing.T) {
+	amount := money.New(3000)
+
+	transaction := &entity.Transaction{
+		ID:          "transaction-id",
+		Amount:      amount,
+		State:       entity.BOOKED,
+		StateString: entity.BOOKED.String(),
+	}
+
+	cases := map[string]struct {
+		InputRequest dto.CaptureAuthorization
+		ExpectedErr  error
+		PrepareMock  func(mockTransactionApp *mocks.MockAppTransactionInterface)
+	}{
+		"deve retornar sucesso: captura parcial": {
+			InputRequest: dto.CaptureAuthorization{Amount: &amount},
+			ExpectedErr:  nil,
+			PrepareMock: func(mockTransactionApp *mocks.MockAppTransactionInterface) {
+				mockTransactionApp.EXPECT().Capture(gomock.Any(), "transaction-id", amount).Times(1).Return(transaction, nil)
+			},
+		},
+		"deve retornar sucesso: captura total": {
+			InputRequest: dto.CaptureAuthorization{},
+			ExpectedErr:  nil,
+			PrepareMock: func(mockTransactionApp *mocks.MockAppTransactionInterface) {
+				mockTransactionApp.EXPECT().Capture(gomock.Any(), "transaction-id", money.Money{}).Times(1).Return(transaction, nil)
+			},
+		},
+		"deve retornar erro": {
+			InputRequest: dto.CaptureAuthorization{},
+			ExpectedErr:  echo.ErrNotFound,
+			PrepareMock: func(mockTransactionApp *mocks.MockAppTransactionInterface) {
+				mockTransactionApp.EXPECT().Capture(gomock.Any(), "transaction-id", money.Money{}).Times(1).Return(nil, echo.ErrNotFound)
+			},
+		},
+		"deve retornar erro: valor invalido": {
+			InputRequest: dto.CaptureAuthorization{Amount: &money.Money{}},
+			ExpectedErr:  echo.NewHTTPError(echo.ErrBadRequest.Code, "The provided value is zero or negative"),
+			PrepareMock:  func(mockTransactionApp *mocks.MockAppTransactionInterface) {},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			mockTransactionApp := mocks.NewMockAppTransactionInterface(ctrl)
+			cs.PrepareMock(mockTransactionApp)
+
+			api := handler{
+				app: &app.Container{Transaction: mockTransactionApp},
+			}
+
+			e := echo.New()
+
+			requestBytes, _ := json.Marshal(cs.InputRequest)
+			req := httptest.NewRequest(http.MethodPost, "/v1/transaction/transaction-id/capture", bytes.NewReader(requestBytes)).WithContext(ctx)
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetPath("/v1/transaction/:id/capture")
+			c.SetParamNames("id")
+			c.SetParamValues("transaction-id")
+
+			err := api.capture(c)
+			assert.Equal(t, cs.ExpectedErr, err)
+
+			if err == nil {
+				assert.Equal(t, http.StatusOK, rec.Code)
+			}
+		})
+	}
+}
+
+func TestVoid(t *testing.T) {
+	transaction := &entity.Transaction{
+		ID:          "transaction-id",
+		State:       entity.CANCELLED,
+		StateString: entity.CANCELLED.String(),
+	}
+
+	cases := map[string]struct {
+		ExpectedErr error
+		PrepareMock func(mockTransactionApp *mocks.MockAppTransactionInterface)
+	}{
+		"deve retornar sucesso": {
+			ExpectedErr: nil,
+			PrepareMock: func(mockTransactionApp *mocks.MockAppTransactionInterface) {
+				mockTransactionApp.EXPECT().Void(gomock.Any(), "transaction-id").Times(1).Return(transaction, nil)
+			},
+		},
+		"deve retornar erro": {
+			ExpectedErr: echo.ErrNotFound,
+			PrepareMock: func(mockTransactionApp *mocks.MockAppTransactionInterface) {
+				mockTransactionApp.EXPECT().Void(gomock.Any(), "transaction-id").Times(1).Return(nil, echo.ErrNotFound)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			mockTransactionApp := mocks.NewMockAppTransactionInterface(ctrl)
+			cs.PrepareMock(mockTransactionApp)
+
+			api := handler{
+				app: &app.Container{Transaction: mockTransactionApp},
+			}
+
+			e := echo.New()
+
+			req := httptest.NewRequest(http.MethodPost, "/v1/transaction/transaction-id/void", nil).WithContext(ctx)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetPath("/v1/transaction/:id/void")
+			c.SetParamNames("id")
+			c.SetParamValues("transaction-id")
+
+			err := api.void(c)
+			assert.Equal(t, cs.ExpectedErr, err)
+
+			if err == nil {
+				expectedResultJSON, err := json.Marshal(dto.Response{Data: transaction})
+				assert.NoError(t, err)
+
+				var expectedResult dto.Response
+				err = json.Unmarshal(expectedResultJSON, &expectedResult)
+				assert.NoError(t, err)
+
+				var currentResult dto.Response
+				json.NewDecoder(rec.Body).Decode(&currentResult)
+
+				assert.Equal(t, expectedResult, currentResult)
+			}
+		})
+	}
+}
+
+func TestCancelScheduled(t *testing.T) {
+	transaction := &entity.Transaction{
+		ID:          "transaction-id",
+		State:       entity.CANCELLED,
+		StateString: entity.CANCELLED.String(),
+	}
+
+	cases := map[string]struct {
+		ExpectedErr error
+		PrepareMock func(mockTransactionApp *mocks.MockAppTransactionInterface)
+	}{
+		"deve retornar sucesso": {
+			ExpectedErr: nil,
+			PrepareMock: func(mockTransactionApp *mocks.MockAppTransactionInterface) {
+				mockTransactionApp.EXPECT().CancelScheduled(gomock.Any(), "transaction-id").Times(1).Return(transaction, nil)
+			},
+		},
+		"deve retornar erro": {
+			ExpectedErr: echo.ErrNotFound,
+			PrepareMock: func(mockTransactionApp *mocks.MockAppTransactionInterface) {
+				mockTransactionApp.EXPECT().CancelScheduled(gomock.Any(), "transaction-id").Times(1).Return(nil, echo.ErrNotFound)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			mockTransactionApp := mocks.NewMockAppTransactionInterface(ctrl)
+			cs.PrepareMock(mockTransactionApp)
+
+			api := handler{
+				app: &app.Container{Transaction: mockTransactionApp},
+			}
+
+			e := echo.New()
+
+			req := httptest.NewRequest(http.MethodPost, "/v1/transaction/transaction-id/cancel", nil).WithContext(ctx)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetPath("/v1/transaction/:id/cancel")
+			c.SetParamNames("id")
+			c.SetParamValues("transaction-id")
+
+			err := api.cancelScheduled(c)
+			assert.Equal(t, cs.ExpectedErr, err)
+
+			if err == nil {
+				expectedResultJSON, err := json.Marshal(dto.Response{Data: transaction})
+				assert.NoError(t, err)
+
+				var expectedResult dto.Response
+				err = json.Unmarshal(expectedResultJSON, &expectedResult)
+				assert.NoError(t, err)
+
+				var currentResult dto.Response
+				json.NewDecoder(rec.Body).Decode(&currentResult)
+
+				assert.Equal(t, expectedResult, currentResult)
+			}
+		})
+	}
+}
+
+func TestReadScheduled(t *testing.T) {
+	transactions := []entity.Transaction{{
+		ID:          "transaction-id",
+		Amount:      money.New(10010),
+		State:       entity.SCHEDULED,
+		StateString: entity.SCHEDULED.String(),
+	}}
+
+	cases := map[string]struct {
+		ExpectedErr error
+		PrepareMock func(mockTransactionApp *mocks.MockAppTransactionInterface)
+	}{
+		"deve retornar sucesso": {
+			ExpectedErr: nil,
+			PrepareMock: func(mockTransactionApp *mocks.MockAppTransactionInterface) {
+				mockTransactionApp.EXPECT().ReadScheduled(gomock.Any()).Times(1).Return(transactions, nil)
+			},
+		},
+		"deve retornar erro": {
+			ExpectedErr: echo.ErrInternalServerError,
+			PrepareMock: func(mockTransactionApp *mocks.MockAppTransactionInterface) {
+				mockTransactionApp.EXPECT().ReadScheduled(gomock.Any()).Times(1).Return(nil, echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			mockTransactionApp := mocks.NewMockAppTransactionInterface(ctrl)
+			cs.PrepareMock(mockTransactionApp)
+
+			api := handler{
+				app: &app.Container{Transaction: mockTransactionApp},
+			}
+
+			e := echo.New()
+
+			endpoint := "/v1/transaction/scheduled"
+			req := httptest.NewRequest(http.MethodGet, endpoint, nil).WithContext(ctx)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetPath(endpoint)
+
+			err := api.readScheduled(c)
+			assert.Equal(t, cs.ExpectedErr, err)
+
+			if err == nil {
+				expectedResultJSON, err := json.Marshal(dto.Response{Data: transactions})
+				assert.NoError(t, err)
+
+				var expectedResult dto.Response
+				err = json.Unmarshal(expectedResultJSON, &expectedResult)
+				assert.NoError(t, err)
+
+				var currentResult dto.Response
+				json.NewDecoder(rec.Body).Decode(&currentResult)
+
+				assert.Equal(t, expectedResult, currentResult)
+			}
+		})
+	}
+}
+
+func TestCreateBatch(t *testing.T) {
+	request := dto.CreateBatch{
+		Atomic: true,
+		Transactions: []dto.CreateTransaction{
+			{SourceUserId: "1234", DestinationUserId: "5678", Amount: money.New(10000)},
+			{SourceUserId: "1234", DestinationUserId: "9012", Amount: money.New(5000)},
+		},
+	}
+
+	batch := &entity.Batch{
+		ID:          "batch-id",
+		Atomic:      true,
+		State:       entity.BATCH_BOOKED,
+		StateString: entity.BATCH_BOOKED.String(),
+		Items: []entity.BatchItem{
+			{Position: 0, TransactionId: "transaction-id-1", State: entity.BOOKED, StateString: entity.BOOKED.String()},
+			{Position: 1, TransactionId: "transaction-id-2", State: entity.BOOKED, StateString: entity.BOOKED.String()},
+		},
+	}
+
+	cases := map[string]struct {
+		InputBatch  dto.CreateBatch
+		ExpectedErr error
+		PrepareMock func(mockTransactionApp *mocks.MockAppTransactionInterface)
+	}{
+		"deve retornar sucesso": {
+			InputBatch:  request,
+			ExpectedErr: nil,
+			PrepareMock: func(mockTransactionApp *mocks.MockAppTransactionInterface) {
+				mockTransactionApp.EXPECT().CreateBatch(gomock.Any(), gomock.Any()).Times(1).Return(batch, nil)
+			},
+		},
+		"deve retornar erro: lote vazio": {
+			InputBatch:  dto.CreateBatch{Atomic: true},
+			ExpectedErr: echo.ErrBadRequest,
+			PrepareMock: func(mockTransactionApp *mocks.MockAppTransactionInterface) {},
+		},
+		"deve retornar erro: valor zerado": {
+			InputBatch: dto.CreateBatch{
+				Transactions: []dto.CreateTransaction{{SourceUserId: "1234", DestinationUserId: "5678", Amount: money.New(0)}},
+			},
+			ExpectedErr: echo.NewHTTPError(echo.ErrBadRequest.Code, "The provided value is zero or negative"),
+			PrepareMock: func(mockTransactionApp *mocks.MockAppTransactionInterface) {},
+		},
+		"deve retornar erro": {
+			InputBatch:  request,
+			ExpectedErr: echo.ErrInternalServerError,
+			PrepareMock: func(mockTransactionApp *mocks.MockAppTransactionInterface) {
+				mockTransactionApp.EXPECT().CreateBatch(gomock.Any(), gomock.Any()).Times(1).Return(nil, echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			mockTransactionApp := mocks.NewMockAppTransactionInterface(ctrl)
+			cs.PrepareMock(mockTransactionApp)
+
+			api := handler{
+				app: &app.Container{Transaction: mockTransactionApp},
+			}
+
+			e := echo.New()
+			e.Validator = validator.NewValidator()
+
+			endpoint := "/v1/transaction/batch"
+
+			requestBytes, _ := json.Marshal(cs.InputBatch)
+			req := httptest.NewRequest(http.MethodPost, endpoint, bytes.NewReader(requestBytes)).WithContext(ctx)
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetPath(endpoint)
+
+			err := api.createBatch(c)
+			assert.Equal(t, cs.ExpectedErr, err)
+
+			if err == nil {
+				assert.Equal(t, http.StatusCreated, rec.Code)
+
+				expectedResultJSON, err := json.Marshal(dto.Response{Data: batch})
+				assert.NoError(t, err)
+
+				var expectedResult dto.Response
+				err = json.Unmarshal(expectedResultJSON, &expectedResult)
+				assert.NoError(t, err)
+
+				var currentResult dto.Response
+				json.NewDecoder(rec.Body).Decode(&currentResult)
+
+				assert.Equal(t, expectedResult, currentResult)
+			}
+		})
+	}
+}
+
+func TestReadBatch(t *testing.T) {
+	batch := &entity.Batch{
+		ID:          "batch-id",
+		State:       entity.BATCH_PARTIALLY_BOOKED,
+		StateString: entity.BATCH_PARTIALLY_BOOKED.String(),
+		Items: []entity.BatchItem{
+			{Position: 0, TransactionId: "transaction-id-1", State: entity.BOOKED, StateString: entity.BOOKED.String()},
+			{Position: 1, TransactionId: "transaction-id-2", State: entity.FAILED, StateString: entity.FAILED.String(), Error: "Insufficient balance"},
+		},
+	}
+
+	cases := map[string]struct {
+		ExpectedErr error
+		PrepareMock func(mockTransactionApp *mocks.MockAppTransactionInterface)
+	}{
+		"deve retornar sucesso": {
+			ExpectedErr: nil,
+			PrepareMock: func(mockTransactionApp *mocks.MockAppTransactionInterface) {
+				mockTransactionApp.EXPECT().ReadBatch(gomock.Any(), "batch-id").Times(1).Return(batch, nil)
+			},
+		},
+		"deve retornar erro": {
+			ExpectedErr: echo.ErrNotFound,
+			PrepareMock: func(mockTransactionApp *mocks.MockAppTransactionInterface) {
+				mockTransactionApp.EXPECT().ReadBatch(gomock.Any(), "batch-id").Times(1).Return(nil, echo.ErrNotFound)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			mockTransactionApp := mocks.NewMockAppTransactionInterface(ctrl)
+			cs.PrepareMock(mockTransactionApp)
+
+			api := handler{
+				app: &app.Container{Transaction: mockTransactionApp},
+			}
+
+			e := echo.New()
+
+			req := httptest.NewRequest(http.MethodGet, "/v1/transaction/batch/batch-id", nil).WithContext(ctx)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetPath("/v1/transaction/batch/:id")
+			c.SetParamNames("id")
+			c.SetParamValues("batch-id")
+
+			err := api.readBatch(c)
+			assert.Equal(t, cs.ExpectedErr, err)
+
+			if err == nil {
+				expectedResultJSON, err := json.Marshal(dto.Response{Data: batch})
+				assert.NoError(t, err)
+
+				var expectedResult dto.Response
+				err = json.Unmarshal(expectedResultJSON, &expectedResult)
+				assert.NoError(t, err)
+
+				var currentResult dto.Response
+				json.NewDecoder(rec.Body).Decode(&currentResult)
+
+				assert.Equal(t, expectedResult, currentResult)
+			}
+		})
+	}
+}
+
+func TestCreateSplit(t *testing.T) {
+	percentage := 90.0
+	rest := 10.0
+
+	request := dto.CreateSplit{
+		SourceUserId: "1234",
+		Amount:       money.New(10000),
+		Rules: []dto.SplitRule{
+			{DestinationUserId: "5678", Percentage: &percentage},
+			{DestinationUserId: "9012", Percentage: &rest},
+		},
+	}
+
+	parentId := "split-id"
+	split := &entity.Split{
+		ID:       parentId,
+		SourceId: "1234",
+		Amount:   money.New(10000),
+		Transactions: []*entity.Transaction{
+			{ID: "transaction-id-1", SourceId: "1234", DestinationId: "5678", Amount: money.New(9000), ParentId: &parentId},
+			{ID: "transaction-id-2", SourceId: "1234", DestinationId: "9012", Amount: money.New(1000), ParentId: &parentId},
+		},
+	}
+
+	cases := map[string]struct {
+		InputSplit  dto.CreateSplit
+		ExpectedErr error
+		PrepareMock func(mockTransactionApp *mocks.MockAppTransactionInterface)
+	}{
+		"deve retornar sucesso": {
+			InputSplit:  request,
+			ExpectedErr: nil,
+			PrepareMock: func(mockTransactionApp *mocks.MockAppTransactionInterface) {
+				mockTransactionApp.EXPECT().CreateSplit(gomock.Any(), gomock.Any()).Times(1).Return(split, nil)
+			},
+		},
+		"deve retornar erro: sem regras": {
+			InputSplit:  dto.CreateSplit{SourceUserId: "1234", Amount: money.New(10000)},
+			ExpectedErr: echo.ErrBadRequest,
+			PrepareMock: func(mockTransactionApp *mocks.MockAppTransactionInterface) {},
+		},
+		"deve retornar erro: valor zerado": {
+			InputSplit:  dto.CreateSplit{SourceUserId: "1234", Amount: money.New(0), Rules: request.Rules},
+			ExpectedErr: echo.NewHTTPError(echo.ErrBadRequest.Code, "The provided value is zero or negative"),
+			PrepareMock: func(mockTransactionApp *mocks.MockAppTransactionInterface) {},
+		},
+		"deve retornar erro": {
+			InputSplit:  request,
+			ExpectedErr: echo.ErrInternalServerError,
+			PrepareMock: func(mockTransactionApp *mocks.MockAppTransactionInterface) {
+				mockTransactionApp.EXPECT().CreateSplit(gomock.Any(), gomock.Any()).Times(1).Return(nil, echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			mockTransactionApp := mocks.NewMockAppTransactionInterface(ctrl)
+			cs.PrepareMock(mockTransactionApp)
+
+			api := handler{
+				app: &app.Container{Transaction: mockTransactionApp},
+			}
+
+			e := echo.New()
+			e.Validator = validator.NewValidator()
+
+			endpoint := "/v1/transaction/split"
+
+			requestBytes, _ := json.Marshal(cs.InputSplit)
+			req := httptest.NewRequest(http.MethodPost, endpoint, bytes.NewReader(requestBytes)).WithContext(ctx)
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetPath(endpoint)
+
+			err := api.createSplit(c)
+			assert.Equal(t, cs.ExpectedErr, err)
+
+			if err == nil {
+				assert.Equal(t, http.StatusCreated, rec.Code)
+
+				expectedResultJSON, err := json.Marshal(dto.Response{Data: split})
+				assert.NoError(t, err)
+
+				var expectedResult dto.Response
+				err = json.Unmarshal(expectedResultJSON, &expectedResult)
+				assert.NoError(t, err)
+
+				var currentResult dto.Response
+				json.NewDecoder(rec.Body).Decode(&currentResult)
+
+				assert.Equal(t, expectedResult, currentResult)
+			}
+		})
+	}
+}
+
+func TestReadSplit(t *testing.T) {
+	parentId := "split-id"
+	split := &entity.Split{
+		ID:       parentId,
+		SourceId: "1234",
+		Amount:   money.New(10000),
+		Transactions: []*entity.Transaction{
+			{ID: "transaction-id", SourceId: "1234", DestinationId: "5678", Amount: money.New(10000), ParentId: &parentId, StateString: "BOOKED"},
+		},
+	}
+
+	cases := map[string]struct {
+		ExpectedErr error
+		PrepareMock func(mockTransactionApp *mocks.MockAppTransactionInterface)
+	}{
+		"deve retornar sucesso": {
+			ExpectedErr: nil,
+			PrepareMock: func(mockTransactionApp *mocks.MockAppTransactionInterface) {
+				mockTransactionApp.EXPECT().ReadSplit(gomock.Any(), parentId).Times(1).Return(split, nil)
+			},
+		},
+		"deve retornar erro": {
+			ExpectedErr: echo.ErrNotFound,
+			PrepareMock: func(mockTransactionApp *mocks.MockAppTransactionInterface) {
+				mockTransactionApp.EXPECT().ReadSplit(gomock.Any(), parentId).Times(1).Return(nil, echo.ErrNotFound)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			mockTransactionApp := mocks.NewMockAppTransactionInterface(ctrl)
+			cs.PrepareMock(mockTransactionApp)
+
+			api := handler{
+				app: &app.Container{Transaction: mockTransactionApp},
+			}
+
+			e := echo.New()
+
+			req := httptest.NewRequest(http.MethodGet, "/v1/transaction/split/split-id", nil).WithContext(ctx)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetPath("/v1/transaction/split/:id")
+			c.SetParamNames("id")
+			c.SetParamValues(parentId)
+
+			err := api.readSplit(c)
+			assert.Equal(t, cs.ExpectedErr, err)
+
+			if err == nil {
+				expectedResultJSON, err := json.Marshal(dto.Response{Data: split})
+				assert.NoError(t, err)
+
+				var expectedResult dto.Response
+				err = json.Unmarshal(expectedResultJSON, &expectedResult)
+				assert.NoError(t, err)
+
+				var currentResult dto.Response
+				json.NewDecoder(rec.Body).Decode(&currentResult)
+
+				assert.Equal(t, expectedResult, currentResult)
+			}
+		})
+	}
+}
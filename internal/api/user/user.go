@@ -1,12 +1,18 @@
 package user
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/garoque/backend-code-challenge-snapfi/internal/api/dto"
 	"github.com/garoque/backend-code-challenge-snapfi/internal/app"
 	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/export"
 	"github.com/labstack/echo/v4"
 )
 
@@ -16,6 +22,16 @@ func Register(router *echo.Group, app *app.Container) {
 	router.GET("", h.readAll)
 	router.GET("/:id", h.readOne)
 	router.POST("", h.create)
+	router.PUT("/:id/credit-line", h.updateCreditLine)
+	router.POST("/:id/freeze", h.freeze)
+	router.POST("/:id/unfreeze", h.unfreeze)
+	router.POST("/:id/close", h.close)
+	router.GET("/:id/status/history", h.readStatusHistory)
+	router.GET("/:id/accounts", h.readAccounts)
+	router.GET("/:id/balance", h.readBalance)
+	router.GET("/:id/balance/history", h.readBalanceHistory)
+	router.GET("/:id/statement", h.readStatement)
+	router.GET("/:id/events", h.streamEvents)
 }
 
 type handler struct {
@@ -93,3 +109,449 @@ func (h *handler) readAll(c echo.Context) error {
 
 	return c.JSON(http.StatusOK, dto.Response{Data: users})
 }
+
+// Update credit line godoc
+// @Summary Update credit line
+// @Description Set the credit limit the balance of the default account of the user can go negative down to and the monthly interest rate, in basis points, charged on a negative balance
+// @Tags user
+// @Accept json
+// @Produce json
+// @Param id path string true "user ID" Format(uuid)
+// @Param request body dto.UpdateCreditLine true "credit line request"
+// @Success 200 {object} entity.User
+// @Failure 400 {object} error
+// @Failure 404 {object} error
+// @Failure 500 {object} error
+// @Router /user/{id}/credit-line [put]
+func (h *handler) updateCreditLine(c echo.Context) error {
+	var request dto.UpdateCreditLine
+	if err := c.Bind(&request); err != nil {
+		return echo.ErrInternalServerError
+	}
+
+	if err := c.Validate(&request); err != nil {
+		return echo.ErrBadRequest
+	}
+
+	if request.CreditLimit.IsNegative() {
+		return echo.NewHTTPError(echo.ErrBadRequest.Code, "The credit limit can't be negative")
+	}
+
+	user, err := h.app.User.UpdateCreditLine(c.Request().Context(), c.Param("id"), request)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, dto.Response{Data: user})
+}
+
+// Freeze user godoc
+// @Summary Freeze user
+// @Description Block the user from sending, receiving and depositing money, for instance while a compromised account is investigated. Reversals, fees and interest still reach a frozen user
+// @Tags user
+// @Accept json
+// @Produce json
+// @Param id path string true "user ID" Format(uuid)
+// @Param request body dto.UpdateUserStatus true "status request"
+// @Success 200 {object} entity.User
+// @Failure 400 {object} error
+// @Failure 404 {object} error
+// @Failure 409 {object} error
+// @Failure 500 {object} error
+// @Router /user/{id}/freeze [post]
+func (h *handler) freeze(c echo.Context) error {
+	var request dto.UpdateUserStatus
+	if err := c.Bind(&request); err != nil {
+		return echo.ErrInternalServerError
+	}
+
+	if err := c.Validate(&request); err != nil {
+		return echo.ErrBadRequest
+	}
+
+	user, err := h.app.User.Freeze(c.Request().Context(), c.Param("id"), request)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, dto.Response{Data: user})
+}
+
+// Unfreeze user godoc
+// @Summary Unfreeze user
+// @Description Let a frozen user move money again
+// @Tags user
+// @Accept json
+// @Produce json
+// @Param id path string true "user ID" Format(uuid)
+// @Param request body dto.UpdateUserStatus true "status request"
+// @Success 200 {object} entity.User
+// @Failure 400 {object} error
+// @Failure 404 {object} error
+// @Failure 409 {object} error
+// @Failure 500 {object} error
+// @Router /user/{id}/unfreeze [post]
+func (h *handler) unfreeze(c echo.Context) error {
+	var request dto.UpdateUserStatus
+	if err := c.Bind(&request); err != nil {
+		return echo.ErrInternalServerError
+	}
+
+	if err := c.Validate(&request); err != nil {
+		return echo.ErrBadRequest
+	}
+
+	user, err := h.app.User.Unfreeze(c.Request().Context(), c.Param("id"), request)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, dto.Response{Data: user})
+}
+
+// Close user godoc
+// @Summary Close user
+// @Description Close the user and their accounts for good. Every balance must be zero, unless sweepToUserId names the user whose default account the remaining balances are swept to, and no funds may be held by authorizations
+// @Tags user
+// @Accept json
+// @Produce json
+// @Param id path string true "user ID" Format(uuid)
+// @Param request body dto.CloseUser true "close request"
+// @Success 200 {object} entity.User
+// @Failure 400 {object} error
+// @Failure 404 {object} error
+// @Failure 409 {object} error
+// @Failure 500 {object} error
+// @Router /user/{id}/close [post]
+func (h *handler) close(c echo.Context) error {
+	var request dto.CloseUser
+	if err := c.Bind(&request); err != nil {
+		return echo.ErrInternalServerError
+	}
+
+	if err := c.Validate(&request); err != nil {
+		return echo.ErrBadRequest
+	}
+
+	user, err := h.app.User.Close(c.Request().Context(), c.Param("id"), request)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, dto.Response{Data: user})
+}
+
+// Read status history godoc
+// @Summary Read status history
+// @Description Read the status changes of the user, oldest first, with the reason of each one
+// @Tags user
+// @Accept json
+// @Produce json
+// @Param id path string true "user ID" Format(uuid)
+// @Success 200 {array} entity.StatusChange
+// @Failure 404 {object} error
+// @Failure 500 {object} error
+// @Router /user/{id}/status/history [get]
+func (h *handler) readStatusHistory(c echo.Context) error {
+	changes, err := h.app.User.ReadStatusHistory(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, dto.Response{Data: changes})
+}
+
+// Read user accounts godoc
+// @Summary Read user accounts
+// @Description Read the accounts of the user, their default account first
+// @Tags user
+// @Accept json
+// @Produce json
+// @Param id path string true "user ID" Format(uuid)
+// @Success 200 {array} entity.Account
+// @Failure 404 {object} error
+// @Failure 500 {object} error
+// @Router /user/{id}/accounts [get]
+func (h *handler) readAccounts(c echo.Context) error {
+	accounts, err := h.app.Account.ReadAllByUserId(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, dto.Response{Data: accounts})
+}
+
+// Read balance godoc
+// @Summary Read balance
+// @Description Read the ledger balance of an account of the user as of a past instant, computed from the booked transactions
+// @Tags user
+// @Accept json
+// @Produce json
+// @Param id path string true "user ID" Format(uuid)
+// @Param accountId query string false "account of the user, their default account when omitted" Format(uuid)
+// @Param at query string false "RFC 3339 timestamp or date, now when omitted" example(2023-05-01T12:00:00-03:00)
+// @Success 200 {object} entity.BalanceAt
+// @Failure 400 {object} error
+// @Failure 404 {object} error
+// @Failure 500 {object} error
+// @Router /user/{id}/balance [get]
+func (h *handler) readBalance(c echo.Context) error {
+	at := time.Now()
+	if value := c.QueryParam("at"); value != "" {
+		var err error
+		at, err = parseTime(value)
+		if err != nil {
+			return echo.NewHTTPError(echo.ErrBadRequest.Code, "The provided at is not a valid timestamp")
+		}
+	}
+
+	balance, err := h.app.Balance.ReadAt(c.Request().Context(), c.Param("id"), c.QueryParam("accountId"), at)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, dto.Response{Data: balance})
+}
+
+// Read balance history godoc
+// @Summary Read balance history
+// @Description Read the balance of an account of the user at the end of every day or hour between two dates, both included
+// @Tags user
+// @Accept json
+// @Produce json
+// @Param id path string true "user ID" Format(uuid)
+// @Param accountId query string false "account of the user, their default account when omitted" Format(uuid)
+// @Param from query string true "RFC 3339 timestamp or date" example(2023-05-01)
+// @Param to query string true "RFC 3339 timestamp or date" example(2023-05-31)
+// @Param interval query string false "DAILY (default) or HOURLY" Enums(DAILY, HOURLY)
+// @Success 200 {object} entity.BalanceHistory
+// @Failure 400 {object} error
+// @Failure 404 {object} error
+// @Failure 500 {object} error
+// @Router /user/{id}/balance/history [get]
+func (h *handler) readBalanceHistory(c echo.Context) error {
+	from, err := parseTime(c.QueryParam("from"))
+	if err != nil {
+		return echo.NewHTTPError(echo.ErrBadRequest.Code, "The provided from is not a valid timestamp")
+	}
+
+	to, err := parseTime(c.QueryParam("to"))
+	if err != nil {
+		return echo.NewHTTPError(echo.ErrBadRequest.Code, "The provided to is not a valid timestamp")
+	}
+
+	if to.Before(from) {
+		return echo.NewHTTPError(echo.ErrBadRequest.Code, "The to date must not be before the from date")
+	}
+
+	interval := c.QueryParam("interval")
+	switch interval {
+	case "":
+		interval = entity.BALANCE_DAILY
+	case entity.BALANCE_DAILY, entity.BALANCE_HOURLY:
+	default:
+		return echo.NewHTTPError(echo.ErrBadRequest.Code, "The interval must be DAILY or HOURLY")
+	}
+
+	history, err := h.app.Balance.ReadHistory(c.Request().Context(), c.Param("id"), c.QueryParam("accountId"), from, to, interval)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, dto.Response{Data: history})
+}
+
+// Read statement godoc
+// @Summary Read statement
+// @Description Read the movements booked on an account of the user between two dates, both included, with the opening balance, a running balance, the totals in and out and the closing balance. The statement is downloaded as CSV, OFX or CAMT.053 when the format parameter or the Accept header asks for it.
+// @Tags user
+// @Accept json
+// @Produce json,text/csv,application/x-ofx,application/xml
+// @Param id path string true "user ID" Format(uuid)
+// @Param accountId query string false "account of the user, their default account when omitted" Format(uuid)
+// @Param from query string true "RFC 3339 timestamp or date" example(2023-05-01)
+// @Param to query string true "RFC 3339 timestamp or date" example(2023-05-31)
+// @Param format query string false "overrides the Accept header" Enums(json, csv, ofx, camt053)
+// @Success 200 {object} entity.Statement
+// @Failure 400 {object} error
+// @Failure 404 {object} error
+// @Failure 500 {object} error
+// @Router /user/{id}/statement [get]
+func (h *handler) readStatement(c echo.Context) error {
+	start, err := parseTime(c.QueryParam("from"))
+	if err != nil {
+		return echo.NewHTTPError(echo.ErrBadRequest.Code, "The provided from is not a valid timestamp")
+	}
+
+	end, err := parseEnd(c.QueryParam("to"))
+	if err != nil {
+		return echo.NewHTTPError(echo.ErrBadRequest.Code, "The provided to is not a valid timestamp")
+	}
+
+	if !start.Before(end) {
+		return echo.NewHTTPError(echo.ErrBadRequest.Code, "The to date must not be before the from date")
+	}
+
+	format, err := statementFormat(c)
+	if err != nil {
+		return err
+	}
+
+	if format != nil {
+		accountId := entity.AccountId(c.QueryParam("accountId"), c.Param("id"))
+		filename := fmt.Sprintf("statement-%s-%s.%s", accountId, start.Format("20060102"), format.Extension)
+		writer := format.NewWriter(&attachment{c: c, contentType: format.ContentType(), filename: filename})
+
+		return h.app.Balance.ExportStatement(c.Request().Context(), c.Param("id"), c.QueryParam("accountId"), start, end, writer)
+	}
+
+	statement, err := h.app.Balance.ReadStatement(c.Request().Context(), c.Param("id"), c.QueryParam("accountId"), start, end)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, dto.Response{Data: statement})
+}
+
+// statementFormat picks the format of the statement from the format query
+// parameter or else from the Accept header, returning nil for JSON.
+func statementFormat(c echo.Context) (*export.Format, error) {
+	if name := c.QueryParam("format"); name != "" {
+		if strings.EqualFold(name, "json") {
+			return nil, nil
+		}
+
+		format, ok := export.FormatByName(name)
+		if !ok {
+			return nil, echo.NewHTTPError(echo.ErrBadRequest.Code, "The format must be json, csv, ofx or camt053")
+		}
+
+		return format, nil
+	}
+
+	format, _ := export.FormatByAccept(c.Request().Header.Get(echo.HeaderAccept))
+	return format, nil
+}
+
+// attachment sends what is written to it as a file download. The headers go
+// out with the first write, so an error before it still gets a JSON response.
+type attachment struct {
+	c           echo.Context
+	contentType string
+	filename    string
+}
+
+func (a *attachment) Write(p []byte) (int, error) {
+	response := a.c.Response()
+	if !response.Committed {
+		response.Header().Set(echo.HeaderContentType, a.contentType)
+		response.Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", a.filename))
+		response.WriteHeader(http.StatusOK)
+	}
+
+	return response.Write(p)
+}
+
+// Stream user events godoc
+// @Summary Stream user events
+// @Description Push the state changes of the transactions of the user, each followed by their balance when it changed, as Server-Sent Events. The stream starts with the current balance, and the Last-Event-ID header resumes it after an event
+// @Tags user
+// @Produce text/event-stream
+// @Param id path string true "user id"
+// @Param Last-Event-ID header string false "id of the last event received"
+// @Success 200 {string} string
+// @Failure 400 {object} error
+// @Failure 404 {object} error
+// @Failure 500 {object} error
+// @Router /user/{id}/events [get]
+func (h *handler) streamEvents(c echo.Context) error {
+	var lastEventId *int64
+	if value := c.Request().Header.Get(HeaderLastEventID); value != "" {
+		id, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return echo.NewHTTPError(echo.ErrBadRequest.Code, "The Last-Event-ID must be the id of an event")
+		}
+		lastEventId = &id
+	}
+
+	stream := &eventStream{c: c}
+
+	return h.app.Stream.Stream(c.Request().Context(), c.Param("id"), lastEventId, stream.send)
+}
+
+// HeaderLastEventID is sent by clients reconnecting to an event stream.
+const HeaderLastEventID = "Last-Event-ID"
+
+// eventStream sends messages as Server-Sent Events, flushing each one. The
+// headers go out with the first message, so an error before it still gets a
+// JSON response.
+type eventStream struct {
+	c echo.Context
+}
+
+func (s *eventStream) send(message entity.StreamMessage) error {
+	response := s.c.Response()
+	if !response.Committed {
+		response.Header().Set(echo.HeaderContentType, "text/event-stream")
+		response.Header().Set("Cache-Control", "no-cache")
+		response.Header().Set("Connection", "keep-alive")
+		response.Header().Set("X-Accel-Buffering", "no")
+		response.WriteHeader(http.StatusOK)
+	}
+
+	var buffer bytes.Buffer
+	if message.Comment != "" {
+		fmt.Fprintf(&buffer, ": %s\n", message.Comment)
+	}
+
+	if message.ID != "" {
+		fmt.Fprintf(&buffer, "id: %s\n", message.ID)
+	}
+
+	if message.Event != "" {
+		fmt.Fprintf(&buffer, "event: %s\n", message.Event)
+	}
+
+	if message.Data != nil {
+		data, err := json.Marshal(message.Data)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(&buffer, "data: %s\n", data)
+	}
+	buffer.WriteString("\n")
+
+	if _, err := response.Write(buffer.Bytes()); err != nil {
+		return err
+	}
+	response.Flush()
+
+	return nil
+}
+
+// parseTime reads an RFC 3339 timestamp or a date, taken as its midnight in
+// the server's time zone.
+func parseTime(value string) (time.Time, error) {
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+
+	return time.Parse(time.RFC3339, value)
+}
+
+// parseEnd reads the end of a period as parseTime does, returning the instant
+// right after it: the next midnight for a date, the next second for a
+// timestamp, since postings are stored to the second.
+func parseEnd(value string) (time.Time, error) {
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t.AddDate(0, 0, 1), nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return t.Truncate(time.Second).Add(time.Second), nil
+}
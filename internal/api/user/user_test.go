@@ -7,11 +7,14 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/garoque/backend-code-challenge-snapfi/internal/api/dto"
 	"github.com/garoque/backend-code-challenge-snapfi/internal/app"
 	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/export"
 	"github.com/garoque/backend-code-challenge-snapfi/internal/mocks"
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/money"
 	"github.com/garoque/backend-code-challenge-snapfi/pkg/validator"
 	"github.com/golang/mock/gomock"
 	"github.com/labstack/echo/v4"
@@ -211,3 +214,794 @@ func TestReadAll(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdateCreditLine(t *testing.T) {
+	user := entity.NewUser(dto.CreateUser{Name: "Gabriel"})
+	user.CreditLimit = money.New(50000)
+	user.InterestRate = 800
+	user.SetCredit()
+
+	cases := map[string]struct {
+		InputBody   string
+		ExpectedErr error
+		PrepareMock func(mockUserApp *mocks.MockAppUserInterface)
+	}{
+		"deve retornar sucesso": {
+			InputBody:   `{"creditLimit": "500.00", "interestRate": 800}`,
+			ExpectedErr: nil,
+			PrepareMock: func(mockUserApp *mocks.MockAppUserInterface) {
+				mockUserApp.EXPECT().UpdateCreditLine(gomock.Any(), user.ID, dto.UpdateCreditLine{CreditLimit: money.New(50000), InterestRate: 800}).Times(1).Return(user, nil)
+			},
+		},
+		"deve retornar erro: limite negativo": {
+			InputBody:   `{"creditLimit": "-500.00", "interestRate": 800}`,
+			ExpectedErr: echo.NewHTTPError(echo.ErrBadRequest.Code, "The credit limit can't be negative"),
+			PrepareMock: func(mockUserApp *mocks.MockAppUserInterface) {},
+		},
+		"deve retornar erro: taxa invalida": {
+			InputBody:   `{"creditLimit": "500.00", "interestRate": -1}`,
+			ExpectedErr: echo.ErrBadRequest,
+			PrepareMock: func(mockUserApp *mocks.MockAppUserInterface) {},
+		},
+		"deve retornar erro": {
+			InputBody:   `{"creditLimit": "500.00", "interestRate": 800}`,
+			ExpectedErr: echo.ErrNotFound,
+			PrepareMock: func(mockUserApp *mocks.MockAppUserInterface) {
+				mockUserApp.EXPECT().UpdateCreditLine(gomock.Any(), user.ID, gomock.Any()).Times(1).Return(nil, echo.ErrNotFound)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			mockUserApp := mocks.NewMockAppUserInterface(ctrl)
+			cs.PrepareMock(mockUserApp)
+
+			api := handler{
+				app: &app.Container{User: mockUserApp},
+			}
+
+			e := echo.New()
+			e.Validator = validator.NewValidator()
+
+			endpoint := "/v1/user/:id/credit-line"
+			req := httptest.NewRequest(http.MethodPut, endpoint, strings.NewReader(cs.InputBody)).WithContext(ctx)
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetPath(endpoint)
+			c.SetParamNames("id")
+			c.SetParamValues(user.ID)
+
+			err := api.updateCreditLine(c)
+			assert.Equal(t, cs.ExpectedErr, err)
+
+			if err == nil {
+				expectedResultJSON, err := json.Marshal(dto.Response{Data: user})
+				assert.NoError(t, err)
+
+				var expectedResult dto.Response
+				err = json.Unmarshal(expectedResultJSON, &expectedResult)
+				assert.NoError(t, err)
+
+				var currentResult dto.Response
+				json.NewDecoder(rec.Body).Decode(&currentResult)
+
+				assert.Equal(t, expectedResult, currentResult)
+			}
+		})
+	}
+}
+
+func TestFreeze(t *testing.T) {
+	user := entity.NewUser(dto.CreateUser{Name: "Gabriel"})
+	user.Status = entity.FROZEN
+	user.StatusString = "FROZEN"
+
+	cases := map[string]struct {
+		InputBody   string
+		ExpectedErr error
+		PrepareMock func(mockUserApp *mocks.MockAppUserInterface)
+	}{
+		"deve retornar sucesso": {
+			InputBody:   `{"reason": "suspected account takeover"}`,
+			ExpectedErr: nil,
+			PrepareMock: func(mockUserApp *mocks.MockAppUserInterface) {
+				mockUserApp.EXPECT().Freeze(gomock.Any(), user.ID, dto.UpdateUserStatus{Reason: "suspected account takeover"}).Times(1).Return(user, nil)
+			},
+		},
+		"deve retornar erro: sem motivo": {
+			InputBody:   `{}`,
+			ExpectedErr: echo.ErrBadRequest,
+			PrepareMock: func(mockUserApp *mocks.MockAppUserInterface) {},
+		},
+		"deve retornar erro": {
+			InputBody:   `{"reason": "suspected account takeover"}`,
+			ExpectedErr: echo.ErrNotFound,
+			PrepareMock: func(mockUserApp *mocks.MockAppUserInterface) {
+				mockUserApp.EXPECT().Freeze(gomock.Any(), user.ID, gomock.Any()).Times(1).Return(nil, echo.ErrNotFound)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			mockUserApp := mocks.NewMockAppUserInterface(ctrl)
+			cs.PrepareMock(mockUserApp)
+
+			api := handler{
+				app: &app.Container{User: mockUserApp},
+			}
+
+			e := echo.New()
+			e.Validator = validator.NewValidator()
+
+			endpoint := "/v1/user/:id/freeze"
+			req := httptest.NewRequest(http.MethodPost, endpoint, strings.NewReader(cs.InputBody)).WithContext(ctx)
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetPath(endpoint)
+			c.SetParamNames("id")
+			c.SetParamValues(user.ID)
+
+			err := api.freeze(c)
+			assert.Equal(t, cs.ExpectedErr, err)
+
+			if err == nil {
+				var currentResult struct {
+					Data entity.User `json:"data"`
+				}
+				json.NewDecoder(rec.Body).Decode(&currentResult)
+
+				assert.Equal(t, "FROZEN", currentResult.Data.StatusString)
+			}
+		})
+	}
+}
+
+func TestClose(t *testing.T) {
+	user := entity.NewUser(dto.CreateUser{Name: "Gabriel"})
+	user.Status = entity.CLOSED
+	user.StatusString = "CLOSED"
+
+	cases := map[string]struct {
+		InputBody   string
+		ExpectedErr error
+		PrepareMock func(mockUserApp *mocks.MockAppUserInterface)
+	}{
+		"deve retornar sucesso": {
+			InputBody:   `{"reason": "customer request", "sweepToUserId": "destination-user-id"}`,
+			ExpectedErr: nil,
+			PrepareMock: func(mockUserApp *mocks.MockAppUserInterface) {
+				mockUserApp.EXPECT().Close(gomock.Any(), user.ID, dto.CloseUser{Reason: "customer request", SweepToUserId: "destination-user-id"}).Times(1).Return(user, nil)
+			},
+		},
+		"deve retornar erro: sem motivo": {
+			InputBody:   `{"sweepToUserId": "destination-user-id"}`,
+			ExpectedErr: echo.ErrBadRequest,
+			PrepareMock: func(mockUserApp *mocks.MockAppUserInterface) {},
+		},
+		"deve retornar erro": {
+			InputBody:   `{"reason": "customer request"}`,
+			ExpectedErr: echo.NewHTTPError(http.StatusConflict, "The balance must be zero to close the account, or swept to another account with sweepToUserId"),
+			PrepareMock: func(mockUserApp *mocks.MockAppUserInterface) {
+				mockUserApp.EXPECT().Close(gomock.Any(), user.ID, dto.CloseUser{Reason: "customer request"}).Times(1).
+					Return(nil, echo.NewHTTPError(http.StatusConflict, "The balance must be zero to close the account, or swept to another account with sweepToUserId"))
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			mockUserApp := mocks.NewMockAppUserInterface(ctrl)
+			cs.PrepareMock(mockUserApp)
+
+			api := handler{
+				app: &app.Container{User: mockUserApp},
+			}
+
+			e := echo.New()
+			e.Validator = validator.NewValidator()
+
+			endpoint := "/v1/user/:id/close"
+			req := httptest.NewRequest(http.MethodPost, endpoint, strings.NewReader(cs.InputBody)).WithContext(ctx)
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetPath(endpoint)
+			c.SetParamNames("id")
+			c.SetParamValues(user.ID)
+
+			err := api.close(c)
+			assert.Equal(t, cs.ExpectedErr, err)
+
+			if err == nil {
+				var currentResult struct {
+					Data entity.User `json:"data"`
+				}
+				json.NewDecoder(rec.Body).Decode(&currentResult)
+
+				assert.Equal(t, "CLOSED", currentResult.Data.StatusString)
+			}
+		})
+	}
+}
+
+func TestReadStatusHistory(t *testing.T) {
+	changes := []entity.StatusChange{{UserId: "user-id", FromString: "ACTIVE", ToString: "FROZEN", Reason: "suspected account takeover"}}
+
+	cases := map[string]struct {
+		ExpectedErr error
+		PrepareMock func(mockUserApp *mocks.MockAppUserInterface)
+	}{
+		"deve retornar sucesso": {
+			ExpectedErr: nil,
+			PrepareMock: func(mockUserApp *mocks.MockAppUserInterface) {
+				mockUserApp.EXPECT().ReadStatusHistory(gomock.Any(), "user-id").Times(1).Return(changes, nil)
+			},
+		},
+		"deve retornar erro": {
+			ExpectedErr: echo.ErrNotFound,
+			PrepareMock: func(mockUserApp *mocks.MockAppUserInterface) {
+				mockUserApp.EXPECT().ReadStatusHistory(gomock.Any(), "user-id").Times(1).Return(nil, echo.ErrNotFound)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			mockUserApp := mocks.NewMockAppUserInterface(ctrl)
+			cs.PrepareMock(mockUserApp)
+
+			api := handler{
+				app: &app.Container{User: mockUserApp},
+			}
+
+			e := echo.New()
+
+			endpoint := "/v1/user/:id/status/history"
+			req := httptest.NewRequest(http.MethodGet, endpoint, nil).WithContext(ctx)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetPath(endpoint)
+			c.SetParamNames("id")
+			c.SetParamValues("user-id")
+
+			err := api.readStatusHistory(c)
+			assert.Equal(t, cs.ExpectedErr, err)
+
+			if err == nil {
+				assert.JSONEq(t, `{"data": [{"userId": "user-id", "from": "ACTIVE", "to": "FROZEN", "reason": "suspected account takeover", "createdAt": null}]}`, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestReadAccounts(t *testing.T) {
+	accounts := []entity.Account{{ID: "user-id", UserId: "user-id", AccountTypeString: "PERSONAL", Currency: "BRL"}}
+
+	cases := map[string]struct {
+		ExpectedErr error
+		PrepareMock func(mockAccountApp *mocks.MockAppAccountInterface)
+	}{
+		"deve retornar sucesso": {
+			ExpectedErr: nil,
+			PrepareMock: func(mockAccountApp *mocks.MockAppAccountInterface) {
+				mockAccountApp.EXPECT().ReadAllByUserId(gomock.Any(), "user-id").Times(1).Return(accounts, nil)
+			},
+		},
+		"deve retornar erro": {
+			ExpectedErr: echo.ErrNotFound,
+			PrepareMock: func(mockAccountApp *mocks.MockAppAccountInterface) {
+				mockAccountApp.EXPECT().ReadAllByUserId(gomock.Any(), "user-id").Times(1).Return(nil, echo.ErrNotFound)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			mockAccountApp := mocks.NewMockAppAccountInterface(ctrl)
+			cs.PrepareMock(mockAccountApp)
+
+			api := handler{
+				app: &app.Container{Account: mockAccountApp},
+			}
+
+			e := echo.New()
+
+			endpoint := "/v1/user/:id/accounts"
+			req := httptest.NewRequest(http.MethodGet, endpoint, nil).WithContext(ctx)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetPath(endpoint)
+			c.SetParamNames("id")
+			c.SetParamValues("user-id")
+
+			err := api.readAccounts(c)
+			assert.Equal(t, cs.ExpectedErr, err)
+
+			if err == nil {
+				expectedResultJSON, err := json.Marshal(dto.Response{Data: accounts})
+				assert.NoError(t, err)
+
+				assert.JSONEq(t, string(expectedResultJSON), rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestReadBalance(t *testing.T) {
+	at := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	balance := &entity.BalanceAt{UserId: "user-id", At: at, Balance: money.New(10000)}
+
+	cases := map[string]struct {
+		InputAt     string
+		ExpectedErr error
+		PrepareMock func(mockBalanceApp *mocks.MockAppBalanceInterface)
+	}{
+		"deve retornar sucesso": {
+			InputAt:     "2023-05-01T12:00:00Z",
+			ExpectedErr: nil,
+			PrepareMock: func(mockBalanceApp *mocks.MockAppBalanceInterface) {
+				mockBalanceApp.EXPECT().ReadAt(gomock.Any(), "user-id", "", at).Times(1).Return(balance, nil)
+			},
+		},
+		"deve retornar erro: data invalida": {
+			InputAt:     "ontem",
+			ExpectedErr: echo.NewHTTPError(echo.ErrBadRequest.Code, "The provided at is not a valid timestamp"),
+			PrepareMock: func(mockBalanceApp *mocks.MockAppBalanceInterface) {},
+		},
+		"deve retornar erro": {
+			InputAt:     "2023-05-01T12:00:00Z",
+			ExpectedErr: echo.ErrNotFound,
+			PrepareMock: func(mockBalanceApp *mocks.MockAppBalanceInterface) {
+				mockBalanceApp.EXPECT().ReadAt(gomock.Any(), "user-id", "", at).Times(1).Return(nil, echo.ErrNotFound)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			mockBalanceApp := mocks.NewMockAppBalanceInterface(ctrl)
+			cs.PrepareMock(mockBalanceApp)
+
+			api := handler{
+				app: &app.Container{Balance: mockBalanceApp},
+			}
+
+			e := echo.New()
+
+			endpoint := "/v1/user/:id/balance"
+			req := httptest.NewRequest(http.MethodGet, endpoint+"?at="+cs.InputAt, nil).WithContext(ctx)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetPath(endpoint)
+			c.SetParamNames("id")
+			c.SetParamValues("user-id")
+
+			err := api.readBalance(c)
+			assert.Equal(t, cs.ExpectedErr, err)
+
+			if err == nil {
+				expectedResultJSON, err := json.Marshal(dto.Response{Data: balance})
+				assert.NoError(t, err)
+
+				var expectedResult dto.Response
+				err = json.Unmarshal(expectedResultJSON, &expectedResult)
+				assert.NoError(t, err)
+
+				var currentResult dto.Response
+				json.NewDecoder(rec.Body).Decode(&currentResult)
+
+				assert.Equal(t, expectedResult, currentResult)
+			}
+		})
+	}
+}
+
+func TestReadBalanceHistory(t *testing.T) {
+	from := time.Date(2023, 5, 1, 0, 0, 0, 0, time.Local)
+	to := time.Date(2023, 5, 2, 0, 0, 0, 0, time.Local)
+	history := &entity.BalanceHistory{
+		UserId:   "user-id",
+		Interval: entity.BALANCE_DAILY,
+		Periods: []entity.BalancePeriod{
+			{Start: from, End: to, Balance: money.New(10000)},
+			{Start: to, End: to.AddDate(0, 0, 1), Balance: money.New(12500)},
+		},
+	}
+
+	cases := map[string]struct {
+		InputQuery  string
+		ExpectedErr error
+		PrepareMock func(mockBalanceApp *mocks.MockAppBalanceInterface)
+	}{
+		"deve retornar sucesso": {
+			InputQuery:  "from=2023-05-01&to=2023-05-02",
+			ExpectedErr: nil,
+			PrepareMock: func(mockBalanceApp *mocks.MockAppBalanceInterface) {
+				mockBalanceApp.EXPECT().ReadHistory(gomock.Any(), "user-id", "", from, to, entity.BALANCE_DAILY).Times(1).Return(history, nil)
+			},
+		},
+		"deve retornar erro: from invalido": {
+			InputQuery:  "to=2023-05-02",
+			ExpectedErr: echo.NewHTTPError(echo.ErrBadRequest.Code, "The provided from is not a valid timestamp"),
+			PrepareMock: func(mockBalanceApp *mocks.MockAppBalanceInterface) {},
+		},
+		"deve retornar erro: to antes de from": {
+			InputQuery:  "from=2023-05-02&to=2023-05-01",
+			ExpectedErr: echo.NewHTTPError(echo.ErrBadRequest.Code, "The to date must not be before the from date"),
+			PrepareMock: func(mockBalanceApp *mocks.MockAppBalanceInterface) {},
+		},
+		"deve retornar erro: intervalo invalido": {
+			InputQuery:  "from=2023-05-01&to=2023-05-02&interval=WEEKLY",
+			ExpectedErr: echo.NewHTTPError(echo.ErrBadRequest.Code, "The interval must be DAILY or HOURLY"),
+			PrepareMock: func(mockBalanceApp *mocks.MockAppBalanceInterface) {},
+		},
+		"deve retornar erro": {
+			InputQuery:  "from=2023-05-01&to=2023-05-02&interval=HOURLY",
+			ExpectedErr: echo.ErrNotFound,
+			PrepareMock: func(mockBalanceApp *mocks.MockAppBalanceInterface) {
+				mockBalanceApp.EXPECT().ReadHistory(gomock.Any(), "user-id", "", from, to, entity.BALANCE_HOURLY).Times(1).Return(nil, echo.ErrNotFound)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			mockBalanceApp := mocks.NewMockAppBalanceInterface(ctrl)
+			cs.PrepareMock(mockBalanceApp)
+
+			api := handler{
+				app: &app.Container{Balance: mockBalanceApp},
+			}
+
+			e := echo.New()
+
+			endpoint := "/v1/user/:id/balance/history"
+			req := httptest.NewRequest(http.MethodGet, endpoint+"?"+cs.InputQuery, nil).WithContext(ctx)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetPath(endpoint)
+			c.SetParamNames("id")
+			c.SetParamValues("user-id")
+
+			err := api.readBalanceHistory(c)
+			assert.Equal(t, cs.ExpectedErr, err)
+
+			if err == nil {
+				expectedResultJSON, err := json.Marshal(dto.Response{Data: history})
+				assert.NoError(t, err)
+
+				var expectedResult dto.Response
+				err = json.Unmarshal(expectedResultJSON, &expectedResult)
+				assert.NoError(t, err)
+
+				var currentResult dto.Response
+				json.NewDecoder(rec.Body).Decode(&currentResult)
+
+				assert.Equal(t, expectedResult, currentResult)
+			}
+		})
+	}
+}
+
+func TestReadStatement(t *testing.T) {
+	start := time.Date(2023, 5, 1, 0, 0, 0, 0, time.Local)
+	end := time.Date(2023, 6, 1, 0, 0, 0, 0, time.Local)
+	statement := &entity.Statement{
+		UserId:         "user-id",
+		Name:           "Gabriel",
+		Start:          start,
+		End:            end,
+		OpeningBalance: money.New(10000),
+		TotalIn:        money.New(0),
+		TotalOut:       money.New(0),
+		ClosingBalance: money.New(10000),
+		Lines:          []entity.StatementLine{},
+	}
+
+	cases := map[string]struct {
+		InputQuery  string
+		ExpectedErr error
+		PrepareMock func(mockBalanceApp *mocks.MockAppBalanceInterface)
+	}{
+		"deve retornar sucesso": {
+			InputQuery:  "from=2023-05-01&to=2023-05-31",
+			ExpectedErr: nil,
+			PrepareMock: func(mockBalanceApp *mocks.MockAppBalanceInterface) {
+				mockBalanceApp.EXPECT().ReadStatement(gomock.Any(), "user-id", "", start, end).Times(1).Return(statement, nil)
+			},
+		},
+		"deve retornar sucesso: outra conta": {
+			InputQuery:  "from=2023-05-01&to=2023-05-31&accountId=savings-id",
+			ExpectedErr: nil,
+			PrepareMock: func(mockBalanceApp *mocks.MockAppBalanceInterface) {
+				mockBalanceApp.EXPECT().ReadStatement(gomock.Any(), "user-id", "savings-id", start, end).Times(1).Return(statement, nil)
+			},
+		},
+		"deve retornar sucesso: timestamps": {
+			InputQuery:  "from=2023-05-01T00:00:00Z&to=2023-05-01T12:00:00Z",
+			ExpectedErr: nil,
+			PrepareMock: func(mockBalanceApp *mocks.MockAppBalanceInterface) {
+				mockBalanceApp.EXPECT().ReadStatement(gomock.Any(), "user-id", "",
+					time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC),
+					time.Date(2023, 5, 1, 12, 0, 1, 0, time.UTC),
+				).Times(1).Return(statement, nil)
+			},
+		},
+		"deve retornar erro: to invalido": {
+			InputQuery:  "from=2023-05-01",
+			ExpectedErr: echo.NewHTTPError(echo.ErrBadRequest.Code, "The provided to is not a valid timestamp"),
+			PrepareMock: func(mockBalanceApp *mocks.MockAppBalanceInterface) {},
+		},
+		"deve retornar erro: to antes de from": {
+			InputQuery:  "from=2023-05-31&to=2023-05-01",
+			ExpectedErr: echo.NewHTTPError(echo.ErrBadRequest.Code, "The to date must not be before the from date"),
+			PrepareMock: func(mockBalanceApp *mocks.MockAppBalanceInterface) {},
+		},
+		"deve retornar erro": {
+			InputQuery:  "from=2023-05-01&to=2023-05-31",
+			ExpectedErr: echo.ErrNotFound,
+			PrepareMock: func(mockBalanceApp *mocks.MockAppBalanceInterface) {
+				mockBalanceApp.EXPECT().ReadStatement(gomock.Any(), "user-id", "", start, end).Times(1).Return(nil, echo.ErrNotFound)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			mockBalanceApp := mocks.NewMockAppBalanceInterface(ctrl)
+			cs.PrepareMock(mockBalanceApp)
+
+			api := handler{
+				app: &app.Container{Balance: mockBalanceApp},
+			}
+
+			e := echo.New()
+
+			endpoint := "/v1/user/:id/statement"
+			req := httptest.NewRequest(http.MethodGet, endpoint+"?"+cs.InputQuery, nil).WithContext(ctx)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetPath(endpoint)
+			c.SetParamNames("id")
+			c.SetParamValues("user-id")
+
+			err := api.readStatement(c)
+			assert.Equal(t, cs.ExpectedErr, err)
+
+			if err == nil {
+				expectedResultJSON, err := json.Marshal(dto.Response{Data: statement})
+				assert.NoError(t, err)
+
+				var expectedResult dto.Response
+				err = json.Unmarshal(expectedResultJSON, &expectedResult)
+				assert.NoError(t, err)
+
+				var currentResult dto.Response
+				json.NewDecoder(rec.Body).Decode(&currentResult)
+
+				assert.Equal(t, expectedResult, currentResult)
+			}
+		})
+	}
+}
+
+func TestExportStatement(t *testing.T) {
+	start := time.Date(2023, 5, 1, 0, 0, 0, 0, time.Local)
+	end := time.Date(2023, 6, 1, 0, 0, 0, 0, time.Local)
+	statement := &entity.Statement{UserId: "user-id", Start: start, End: end}
+
+	writeStatement := func(ctx context.Context, userId, accountId string, start, end time.Time, writer export.Writer) error {
+		if err := writer.WriteHeader(statement); err != nil {
+			return err
+		}
+		return writer.Close()
+	}
+
+	cases := map[string]struct {
+		InputQuery          string
+		InputAccept         string
+		ExpectedErr         error
+		ExpectedContentType string
+		ExpectedBody        string
+		PrepareMock         func(mockBalanceApp *mocks.MockAppBalanceInterface)
+	}{
+		"deve retornar sucesso: csv pelo format": {
+			InputQuery:          "from=2023-05-01&to=2023-05-31&format=csv",
+			InputAccept:         "application/json",
+			ExpectedErr:         nil,
+			ExpectedContentType: "text/csv",
+			ExpectedBody:        "bookedAt,transactionId,kind,counterpartyId,counterpartyName,amount,balance\n",
+			PrepareMock: func(mockBalanceApp *mocks.MockAppBalanceInterface) {
+				mockBalanceApp.EXPECT().ExportStatement(gomock.Any(), "user-id", "", start, end, gomock.Any()).Times(1).DoAndReturn(writeStatement)
+			},
+		},
+		"deve retornar sucesso: ofx pelo accept": {
+			InputQuery:          "from=2023-05-01&to=2023-05-31",
+			InputAccept:         "application/x-ofx",
+			ExpectedErr:         nil,
+			ExpectedContentType: "application/x-ofx",
+			PrepareMock: func(mockBalanceApp *mocks.MockAppBalanceInterface) {
+				mockBalanceApp.EXPECT().ExportStatement(gomock.Any(), "user-id", "", start, end, gomock.Any()).Times(1).DoAndReturn(writeStatement)
+			},
+		},
+		"deve retornar sucesso: json pelo format": {
+			InputQuery:          "from=2023-05-01&to=2023-05-31&format=json",
+			InputAccept:         "text/csv",
+			ExpectedErr:         nil,
+			ExpectedContentType: echo.MIMEApplicationJSONCharsetUTF8,
+			PrepareMock: func(mockBalanceApp *mocks.MockAppBalanceInterface) {
+				mockBalanceApp.EXPECT().ReadStatement(gomock.Any(), "user-id", "", start, end).Times(1).Return(statement, nil)
+			},
+		},
+		"deve retornar erro: format invalido": {
+			InputQuery:  "from=2023-05-01&to=2023-05-31&format=pdf",
+			ExpectedErr: echo.NewHTTPError(echo.ErrBadRequest.Code, "The format must be json, csv, ofx or camt053"),
+			PrepareMock: func(mockBalanceApp *mocks.MockAppBalanceInterface) {},
+		},
+		"deve retornar erro": {
+			InputQuery:  "from=2023-05-01&to=2023-05-31&format=camt053",
+			ExpectedErr: echo.ErrNotFound,
+			PrepareMock: func(mockBalanceApp *mocks.MockAppBalanceInterface) {
+				mockBalanceApp.EXPECT().ExportStatement(gomock.Any(), "user-id", "", start, end, gomock.Any()).Times(1).Return(echo.ErrNotFound)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			mockBalanceApp := mocks.NewMockAppBalanceInterface(ctrl)
+			cs.PrepareMock(mockBalanceApp)
+
+			api := handler{
+				app: &app.Container{Balance: mockBalanceApp},
+			}
+
+			e := echo.New()
+
+			endpoint := "/v1/user/:id/statement"
+			req := httptest.NewRequest(http.MethodGet, endpoint+"?"+cs.InputQuery, nil).WithContext(ctx)
+			req.Header.Set(echo.HeaderAccept, cs.InputAccept)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetPath(endpoint)
+			c.SetParamNames("id")
+			c.SetParamValues("user-id")
+
+			err := api.readStatement(c)
+			assert.Equal(t, cs.ExpectedErr, err)
+
+			if err == nil {
+				assert.Equal(t, http.StatusOK, rec.Code)
+				assert.Equal(t, cs.ExpectedContentType, rec.Header().Get(echo.HeaderContentType))
+
+				if cs.ExpectedContentType != echo.MIMEApplicationJSONCharsetUTF8 {
+					assert.Contains(t, rec.Header().Get(echo.HeaderContentDisposition), `attachment; filename="statement-user-id-20230501.`)
+				}
+				if cs.ExpectedBody != "" {
+					assert.Equal(t, cs.ExpectedBody, rec.Body.String())
+				}
+			} else {
+				assert.False(t, c.Response().Committed)
+			}
+		})
+	}
+}
+
+func TestStreamEvents(t *testing.T) {
+	lastEventId := int64(41)
+	event := entity.Event{Sequence: 42, ID: "event-id", Type: "transaction.booked", AggregateType: "transaction", AggregateId: "transaction-id", Payload: json.RawMessage(`{"to":"BOOKED"}`)}
+	balance := entity.BalanceUpdate{UserId: "user-id", AccountId: "user-id", Balance: money.New(1000), HeldBalance: money.New(0), AvailableBalance: money.New(1000)}
+
+	sendEvents := func(ctx context.Context, userId string, lastEventId *int64, send func(entity.StreamMessage) error) error {
+		for _, message := range []entity.StreamMessage{
+			{ID: "42", Event: entity.STREAM_EVENT_TRANSACTION, Data: event},
+			{Event: entity.STREAM_EVENT_BALANCE, Data: balance},
+			{Comment: "heartbeat"},
+		} {
+			if err := send(message); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	cases := map[string]struct {
+		InputLastEventId string
+		ExpectedErr      error
+		ExpectedBody     string
+		PrepareMock      func(mockStreamApp *mocks.MockAppStreamInterface)
+	}{
+		"deve retornar sucesso": {
+			InputLastEventId: "41",
+			ExpectedErr:      nil,
+			ExpectedBody: "id: 42\nevent: transaction\n" +
+				`data: {"id":"event-id","type":"transaction.booked","aggregateType":"transaction","aggregateId":"transaction-id","payload":{"to":"BOOKED"},"createdAt":"0001-01-01T00:00:00Z"}` + "\n\n" +
+				"event: balance\n" +
+				`data: {"userId":"user-id","accountId":"user-id","balance":"10.00","heldBalance":"0.00","availableBalance":"10.00"}` + "\n\n" +
+				": heartbeat\n\n",
+			PrepareMock: func(mockStreamApp *mocks.MockAppStreamInterface) {
+				mockStreamApp.EXPECT().Stream(gomock.Any(), "user-id", &lastEventId, gomock.Any()).Times(1).DoAndReturn(sendEvents)
+			},
+		},
+		"deve retornar erro: Last-Event-ID invalido": {
+			InputLastEventId: "event-id",
+			ExpectedErr:      echo.NewHTTPError(echo.ErrBadRequest.Code, "The Last-Event-ID must be the id of an event"),
+			PrepareMock:      func(mockStreamApp *mocks.MockAppStreamInterface) {},
+		},
+		"deve retornar erro": {
+			ExpectedErr: echo.ErrNotFound,
+			PrepareMock: func(mockStreamApp *mocks.MockAppStreamInterface) {
+				mockStreamApp.EXPECT().Stream(gomock.Any(), "user-id", nil, gomock.Any()).Times(1).Return(echo.ErrNotFound)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			mockStreamApp := mocks.NewMockAppStreamInterface(ctrl)
+			cs.PrepareMock(mockStreamApp)
+
+			api := handler{
+				app: &app.Container{Stream: mockStreamApp},
+			}
+
+			e := echo.New()
+
+			endpoint := "/v1/user/:id/events"
+			req := httptest.NewRequest(http.MethodGet, endpoint, nil).WithContext(ctx)
+			if cs.InputLastEventId != "" {
+				req.Header.Set(HeaderLastEventID, cs.InputLastEventId)
+			}
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetPath(endpoint)
+			c.SetParamNames("id")
+			c.SetParamValues("user-id")
+
+			err := api.streamEvents(c)
+			assert.Equal(t, cs.ExpectedErr, err)
+
+			if err == nil {
+				assert.Equal(t, http.StatusOK, rec.Code)
+				assert.Equal(t, "text/event-stream", rec.Header().Get(echo.HeaderContentType))
+				assert.Equal(t, cs.ExpectedBody, rec.Body.String())
+			} else {
+				assert.False(t, c.Response().Committed)
+			}
+		})
+	}
+}
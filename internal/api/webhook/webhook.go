@@ -0,0 +1,161 @@
+package webhook
+
+import (
+	"net/http"
+
+	"github.com/garoque/backend-code-challenge-snapfi/internal/api/dto"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/api/idempotency"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/app"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	"github.com/labstack/echo/v4"
+)
+
+func Register(router *echo.Group, app *app.Container) {
+	h := &handler{app}
+
+	router.POST("", h.create, idempotency.Middleware(app))
+	router.GET("", h.readAll)
+	router.GET("/:id", h.readOne)
+	router.DELETE("/:id", h.delete)
+	router.GET("/:id/deliveries", h.readDeliveries)
+	router.POST("/:id/deliveries/:deliveryId/redeliver", h.redeliver)
+}
+
+type handler struct {
+	app *app.Container
+}
+
+// Create webhook godoc
+// @Summary Create webhook
+// @Description Send the events of the given types to a URL, signed with HMAC-SHA256. The secret is only returned here
+// @Tags webhook
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateWebhook true "webhook request"
+// @Param Idempotency-Key header string false "key used to safely retry the request"
+// @Success 201 {object} entity.WebhookSubscription
+// @Failure 400 {object} error
+// @Failure 409 {object} error
+// @Failure 500 {object} error
+// @Router /webhook [post]
+func (h *handler) create(c echo.Context) error {
+	var request dto.CreateWebhook
+	if err := c.Bind(&request); err != nil {
+		return err
+	}
+
+	if err := c.Validate(&request); err != nil {
+		return echo.ErrBadRequest
+	}
+
+	subscription, err := entity.NewWebhookSubscription(request)
+	if err != nil {
+		return err
+	}
+
+	subscription, err = h.app.Webhook.Create(c.Request().Context(), subscription)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, dto.Response{Data: subscription})
+}
+
+// Read all webhooks godoc
+// @Summary Read all webhooks
+// @Description Read all webhooks, active or not
+// @Tags webhook
+// @Accept json
+// @Produce json
+// @Success 200 {array} entity.WebhookSubscription
+// @Failure 500 {object} error
+// @Router /webhook [get]
+func (h *handler) readAll(c echo.Context) error {
+	subscriptions, err := h.app.Webhook.ReadAll(c.Request().Context())
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, dto.Response{Data: subscriptions})
+}
+
+// Read webhook godoc
+// @Summary Read webhook
+// @Description Read webhook
+// @Tags webhook
+// @Accept json
+// @Produce json
+// @Param id path string true "webhook id"
+// @Success 200 {object} entity.WebhookSubscription
+// @Failure 404 {object} error
+// @Router /webhook/{id} [get]
+func (h *handler) readOne(c echo.Context) error {
+	subscription, err := h.app.Webhook.ReadOneById(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, dto.Response{Data: subscription})
+}
+
+// Delete webhook godoc
+// @Summary Delete webhook
+// @Description Stop sending events to a webhook, its delivery log stays available
+// @Tags webhook
+// @Accept json
+// @Produce json
+// @Param id path string true "webhook id"
+// @Success 200 {object} entity.WebhookSubscription
+// @Failure 404 {object} error
+// @Failure 500 {object} error
+// @Router /webhook/{id} [delete]
+func (h *handler) delete(c echo.Context) error {
+	subscription, err := h.app.Webhook.Delete(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, dto.Response{Data: subscription})
+}
+
+// Read webhook deliveries godoc
+// @Summary Read webhook deliveries
+// @Description Read the deliveries of a webhook, newest first, with the outcome of their last attempt
+// @Tags webhook
+// @Accept json
+// @Produce json
+// @Param id path string true "webhook id"
+// @Success 200 {array} entity.WebhookDelivery
+// @Failure 404 {object} error
+// @Failure 500 {object} error
+// @Router /webhook/{id}/deliveries [get]
+func (h *handler) readDeliveries(c echo.Context) error {
+	deliveries, err := h.app.Webhook.ReadDeliveries(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, dto.Response{Data: deliveries})
+}
+
+// Redeliver webhook delivery godoc
+// @Summary Redeliver webhook delivery
+// @Description Send a delivered or dead delivery again, with every attempt anew
+// @Tags webhook
+// @Accept json
+// @Produce json
+// @Param id path string true "webhook id"
+// @Param deliveryId path string true "delivery id"
+// @Success 200 {object} entity.WebhookDelivery
+// @Failure 404 {object} error
+// @Failure 409 {object} error
+// @Failure 500 {object} error
+// @Router /webhook/{id}/deliveries/{deliveryId}/redeliver [post]
+func (h *handler) redeliver(c echo.Context) error {
+	delivery, err := h.app.Webhook.Redeliver(c.Request().Context(), c.Param("id"), c.Param("deliveryId"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, dto.Response{Data: delivery})
+}
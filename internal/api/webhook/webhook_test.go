@@ -0,0 +1,306 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/garoque/backend-code-challenge-snapfi/internal/api/dto"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/app"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/mocks"
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/validator"
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+var subscription = &entity.WebhookSubscription{
+	ID:         "webhook-id",
+	URL:        "https://partner.example.com",
+	EventTypes: entity.WebhookEventTypes{"transaction.booked", "deposit.booked"},
+	Active:     true,
+}
+
+var delivery = &entity.WebhookDelivery{
+	ID:             "delivery-id",
+	SubscriptionId: "webhook-id",
+	EventId:        "event-id",
+	EventType:      "deposit.booked",
+	Payload:        json.RawMessage(`{"id":"event-id"}`),
+	StateString:    "DEAD",
+	Attempts:       10,
+}
+
+func assertResponse(t *testing.T, rec *httptest.ResponseRecorder, data interface{}) {
+	expectedResultJSON, err := json.Marshal(dto.Response{Data: data})
+	assert.NoError(t, err)
+
+	var expectedResult dto.Response
+	err = json.Unmarshal(expectedResultJSON, &expectedResult)
+	assert.NoError(t, err)
+
+	var currentResult dto.Response
+	json.NewDecoder(rec.Body).Decode(&currentResult)
+
+	assert.Equal(t, expectedResult, currentResult)
+}
+
+func TestCreate(t *testing.T) {
+	request := dto.CreateWebhook{
+		URL:        "https://partner.example.com",
+		EventTypes: []string{"transaction.booked", "deposit.booked"},
+	}
+
+	invalidURL := request
+	invalidURL.URL = "partner"
+
+	invalidEventType := request
+	invalidEventType.EventTypes = []string{"user.created"}
+
+	shortSecret := request
+	shortSecret.Secret = "secret"
+
+	cases := map[string]struct {
+		InputWebhook dto.CreateWebhook
+		ExpectedErr  error
+		PrepareMock  func(mockWebhookApp *mocks.MockAppWebhookInterface)
+	}{
+		"deve retornar sucesso": {
+			InputWebhook: request,
+			ExpectedErr:  nil,
+			PrepareMock: func(mockWebhookApp *mocks.MockAppWebhookInterface) {
+				mockWebhookApp.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(ctx context.Context, created *entity.WebhookSubscription) (*entity.WebhookSubscription, error) {
+						assert.Equal(t, "https://partner.example.com", created.URL)
+						assert.NotEmpty(t, created.Secret)
+						return subscription, nil
+					})
+			},
+		},
+		"deve retornar erro: url invalida": {
+			InputWebhook: invalidURL,
+			ExpectedErr:  echo.ErrBadRequest,
+			PrepareMock:  func(mockWebhookApp *mocks.MockAppWebhookInterface) {},
+		},
+		"deve retornar erro: tipo de evento invalido": {
+			InputWebhook: invalidEventType,
+			ExpectedErr:  echo.ErrBadRequest,
+			PrepareMock:  func(mockWebhookApp *mocks.MockAppWebhookInterface) {},
+		},
+		"deve retornar erro: segredo curto": {
+			InputWebhook: shortSecret,
+			ExpectedErr:  echo.ErrBadRequest,
+			PrepareMock:  func(mockWebhookApp *mocks.MockAppWebhookInterface) {},
+		},
+		"deve retornar erro": {
+			InputWebhook: request,
+			ExpectedErr:  echo.ErrInternalServerError,
+			PrepareMock: func(mockWebhookApp *mocks.MockAppWebhookInterface) {
+				mockWebhookApp.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil, echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			mockWebhookApp := mocks.NewMockAppWebhookInterface(ctrl)
+			cs.PrepareMock(mockWebhookApp)
+
+			api := handler{
+				app: &app.Container{Webhook: mockWebhookApp},
+			}
+
+			e := echo.New()
+			e.Validator = validator.NewValidator()
+
+			endpoint := "/v1/webhook"
+
+			requestBytes, _ := json.Marshal(cs.InputWebhook)
+			req := httptest.NewRequest(http.MethodPost, endpoint, bytes.NewReader(requestBytes)).WithContext(ctx)
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetPath(endpoint)
+
+			err := api.create(c)
+			assert.Equal(t, cs.ExpectedErr, err)
+
+			if err == nil {
+				assert.Equal(t, http.StatusCreated, rec.Code)
+				assertResponse(t, rec, subscription)
+			}
+		})
+	}
+}
+
+func TestRead(t *testing.T) {
+	cases := map[string]struct {
+		Path           string
+		Method         string
+		Handler        func(h *handler) echo.HandlerFunc
+		ExpectedResult interface{}
+		ExpectedErr    error
+		PrepareMock    func(mockWebhookApp *mocks.MockAppWebhookInterface)
+	}{
+		"deve retornar sucesso: todos": {
+			Path:           "/v1/webhook",
+			Method:         http.MethodGet,
+			Handler:        func(h *handler) echo.HandlerFunc { return h.readAll },
+			ExpectedResult: []entity.WebhookSubscription{*subscription},
+			PrepareMock: func(mockWebhookApp *mocks.MockAppWebhookInterface) {
+				mockWebhookApp.EXPECT().ReadAll(gomock.Any()).Times(1).Return([]entity.WebhookSubscription{*subscription}, nil)
+			},
+		},
+		"deve retornar erro: todos": {
+			Path:        "/v1/webhook",
+			Method:      http.MethodGet,
+			Handler:     func(h *handler) echo.HandlerFunc { return h.readAll },
+			ExpectedErr: echo.ErrInternalServerError,
+			PrepareMock: func(mockWebhookApp *mocks.MockAppWebhookInterface) {
+				mockWebhookApp.EXPECT().ReadAll(gomock.Any()).Times(1).Return(nil, echo.ErrInternalServerError)
+			},
+		},
+		"deve retornar sucesso: um": {
+			Path:           "/v1/webhook/:id",
+			Method:         http.MethodGet,
+			Handler:        func(h *handler) echo.HandlerFunc { return h.readOne },
+			ExpectedResult: subscription,
+			PrepareMock: func(mockWebhookApp *mocks.MockAppWebhookInterface) {
+				mockWebhookApp.EXPECT().ReadOneById(gomock.Any(), "webhook-id").Times(1).Return(subscription, nil)
+			},
+		},
+		"deve retornar erro: um": {
+			Path:        "/v1/webhook/:id",
+			Method:      http.MethodGet,
+			Handler:     func(h *handler) echo.HandlerFunc { return h.readOne },
+			ExpectedErr: echo.ErrNotFound,
+			PrepareMock: func(mockWebhookApp *mocks.MockAppWebhookInterface) {
+				mockWebhookApp.EXPECT().ReadOneById(gomock.Any(), "webhook-id").Times(1).Return(nil, echo.ErrNotFound)
+			},
+		},
+		"deve retornar sucesso: remover": {
+			Path:           "/v1/webhook/:id",
+			Method:         http.MethodDelete,
+			Handler:        func(h *handler) echo.HandlerFunc { return h.delete },
+			ExpectedResult: subscription,
+			PrepareMock: func(mockWebhookApp *mocks.MockAppWebhookInterface) {
+				mockWebhookApp.EXPECT().Delete(gomock.Any(), "webhook-id").Times(1).Return(subscription, nil)
+			},
+		},
+		"deve retornar erro: remover": {
+			Path:        "/v1/webhook/:id",
+			Method:      http.MethodDelete,
+			Handler:     func(h *handler) echo.HandlerFunc { return h.delete },
+			ExpectedErr: echo.ErrNotFound,
+			PrepareMock: func(mockWebhookApp *mocks.MockAppWebhookInterface) {
+				mockWebhookApp.EXPECT().Delete(gomock.Any(), "webhook-id").Times(1).Return(nil, echo.ErrNotFound)
+			},
+		},
+		"deve retornar sucesso: entregas": {
+			Path:           "/v1/webhook/:id/deliveries",
+			Method:         http.MethodGet,
+			Handler:        func(h *handler) echo.HandlerFunc { return h.readDeliveries },
+			ExpectedResult: []entity.WebhookDelivery{*delivery},
+			PrepareMock: func(mockWebhookApp *mocks.MockAppWebhookInterface) {
+				mockWebhookApp.EXPECT().ReadDeliveries(gomock.Any(), "webhook-id").Times(1).Return([]entity.WebhookDelivery{*delivery}, nil)
+			},
+		},
+		"deve retornar erro: entregas": {
+			Path:        "/v1/webhook/:id/deliveries",
+			Method:      http.MethodGet,
+			Handler:     func(h *handler) echo.HandlerFunc { return h.readDeliveries },
+			ExpectedErr: echo.ErrNotFound,
+			PrepareMock: func(mockWebhookApp *mocks.MockAppWebhookInterface) {
+				mockWebhookApp.EXPECT().ReadDeliveries(gomock.Any(), "webhook-id").Times(1).Return(nil, echo.ErrNotFound)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			mockWebhookApp := mocks.NewMockAppWebhookInterface(ctrl)
+			cs.PrepareMock(mockWebhookApp)
+
+			api := &handler{
+				app: &app.Container{Webhook: mockWebhookApp},
+			}
+
+			e := echo.New()
+
+			req := httptest.NewRequest(cs.Method, "/v1/webhook/webhook-id", nil).WithContext(ctx)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetPath(cs.Path)
+			c.SetParamNames("id")
+			c.SetParamValues("webhook-id")
+
+			err := cs.Handler(api)(c)
+			assert.Equal(t, cs.ExpectedErr, err)
+
+			if err == nil {
+				assertResponse(t, rec, cs.ExpectedResult)
+			}
+		})
+	}
+}
+
+func TestRedeliver(t *testing.T) {
+	cases := map[string]struct {
+		ExpectedErr error
+		PrepareMock func(mockWebhookApp *mocks.MockAppWebhookInterface)
+	}{
+		"deve retornar sucesso": {
+			ExpectedErr: nil,
+			PrepareMock: func(mockWebhookApp *mocks.MockAppWebhookInterface) {
+				mockWebhookApp.EXPECT().Redeliver(gomock.Any(), "webhook-id", "delivery-id").Times(1).Return(delivery, nil)
+			},
+		},
+		"deve retornar erro": {
+			ExpectedErr: echo.NewHTTPError(http.StatusConflict, "The delivery is still pending"),
+			PrepareMock: func(mockWebhookApp *mocks.MockAppWebhookInterface) {
+				mockWebhookApp.EXPECT().Redeliver(gomock.Any(), "webhook-id", "delivery-id").Times(1).Return(nil, echo.NewHTTPError(http.StatusConflict, "The delivery is still pending"))
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			mockWebhookApp := mocks.NewMockAppWebhookInterface(ctrl)
+			cs.PrepareMock(mockWebhookApp)
+
+			api := &handler{
+				app: &app.Container{Webhook: mockWebhookApp},
+			}
+
+			e := echo.New()
+
+			req := httptest.NewRequest(http.MethodPost, "/v1/webhook/webhook-id/deliveries/delivery-id/redeliver", nil).WithContext(ctx)
+			rec := httptest.NewRecorder()
+
+			c := e.NewContext(req, rec)
+			c.SetPath("/v1/webhook/:id/deliveries/:deliveryId/redeliver")
+			c.SetParamNames("id", "deliveryId")
+			c.SetParamValues("webhook-id", "delivery-id")
+
+			err := api.redeliver(c)
+			assert.Equal(t, cs.ExpectedErr, err)
+
+			if err == nil {
+				assertResponse(t, rec, delivery)
+			}
+		})
+	}
+}
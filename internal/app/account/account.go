@@ -0,0 +1,98 @@
+package account
+
+import (
+	"context"
+	"log"
+
+	"github.com/garoque/backend-code-challenge-snapfi/internal/app/audit"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/app/transaction"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/database"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+)
+
+type AppAccountInterface interface {
+	Create(ctx context.Context, account entity.Account) (*entity.Account, error)
+	ReadOneById(ctx context.Context, id string) (*entity.Account, error)
+	ReadAllByUserId(ctx context.Context, userId string) ([]entity.Account, error)
+}
+
+type appAccountImpl struct {
+	db *database.Container
+}
+
+func NewAppAccount(db *database.Container) AppAccountInterface {
+	return &appAccountImpl{db}
+}
+
+// Create opens the account for its owner, who must exist and not be closed.
+// The owner stays locked until the account is stored, so they can't be closed
+// in the meantime.
+func (a *appAccountImpl) Create(ctx context.Context, account entity.Account) (*entity.Account, error) {
+	err := a.db.UnitOfWork.Do(ctx, func(tx *database.Container) error {
+		user, err := tx.User.ReadOneByIdForUpdate(ctx, account.UserId)
+		if err != nil {
+			log.Println("Error app.Account.Create.db.User.ReadOneByIdForUpdate: ", err.Error())
+			return err
+		}
+
+		if user.Status == entity.CLOSED {
+			log.Println("Error app.Account.Create user is closed: ", user.ID)
+			return transaction.ErrUserClosed
+		}
+
+		err = tx.Account.Create(ctx, account)
+		if err != nil {
+			log.Println("Error app.Account.Create.db.Create: ", err.Error())
+			return err
+		}
+
+		err = audit.Record(ctx, tx, entity.AUDIT_ACCOUNT_CREATED, entity.AUDIT_ACCOUNT, account.ID, nil, account)
+		if err != nil {
+			log.Println("Error app.Account.Create.audit.Record: ", err.Error())
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	account.AccountTypeString = account.AccountType.String()
+
+	return account.SetCredit(), nil
+}
+
+func (a *appAccountImpl) ReadOneById(ctx context.Context, id string) (*entity.Account, error) {
+	account, err := a.db.Account.ReadOneById(ctx, id)
+	if err != nil {
+		log.Println("Error app.Account.ReadOneById.db.ReadOneById: ", err.Error())
+		return nil, err
+	}
+
+	account.AccountTypeString = account.AccountType.String()
+
+	return account.SetCredit(), nil
+}
+
+// ReadAllByUserId lists the accounts of the user, the default one first.
+func (a *appAccountImpl) ReadAllByUserId(ctx context.Context, userId string) ([]entity.Account, error) {
+	_, err := a.db.User.ReadOneById(ctx, userId)
+	if err != nil {
+		log.Println("Error app.Account.ReadAllByUserId.db.User.ReadOneById: ", err.Error())
+		return nil, err
+	}
+
+	accounts, err := a.db.Account.ReadAllByUserId(ctx, userId)
+	if err != nil {
+		log.Println("Error app.Account.ReadAllByUserId.db.ReadAllByUserId: ", err.Error())
+		return nil, err
+	}
+
+	for i := range accounts {
+		accounts[i].AccountTypeString = accounts[i].AccountType.String()
+		accounts[i].SetCredit()
+	}
+
+	return accounts, nil
+}
@@ -0,0 +1,227 @@
+package account
+
+import (
+	"context"
+	"testing"
+
+	"github.com/garoque/backend-code-challenge-snapfi/internal/api/dto"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/app/transaction"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/database"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/mocks"
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/money"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+	"github.com/labstack/echo/v4"
+)
+
+func TestCreate(t *testing.T) {
+	userId := "user-id"
+	account := *entity.NewAccount(dto.CreateAccount{UserId: userId, Name: "MEI", AccountType: "BUSINESS", Currency: "USD"})
+
+	expected := account
+	expected.AccountTypeString = "BUSINESS"
+	expected.UsedCredit = money.New(0)
+	expected.AvailableCredit = money.New(0)
+
+	cases := map[string]struct {
+		ExpectedResult *entity.Account
+		ExpectedErr    error
+		PrepareMock    func(mockUserDb *mocks.MockDabataseUserInterface, mockAccountDb *mocks.MockDabataseAccountInterface, mockAuditDb *mocks.MockDabataseAuditInterface)
+	}{
+		"deve retornar sucesso": {
+			ExpectedResult: &expected,
+			ExpectedErr:    nil,
+			PrepareMock: func(mockUserDb *mocks.MockDabataseUserInterface, mockAccountDb *mocks.MockDabataseAccountInterface, mockAuditDb *mocks.MockDabataseAuditInterface) {
+				gomock.InOrder(
+					mockUserDb.EXPECT().ReadOneByIdForUpdate(gomock.Any(), userId).Times(1).Return(&entity.User{ID: userId, Status: entity.FROZEN}, nil),
+					mockAccountDb.EXPECT().Create(gomock.Any(), account).Times(1).Return(nil),
+					mockAuditDb.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).
+						Do(func(ctx context.Context, entry *entity.AuditEntry) {
+							if entry.Operation != entity.AUDIT_ACCOUNT_CREATED || entry.EntityType != entity.AUDIT_ACCOUNT || entry.EntityId != account.ID || entry.Before != nil {
+								t.Errorf("unexpected audit entry %+v", entry)
+							}
+						}).Return(nil),
+				)
+			},
+		},
+		"deve retornar erro: usuario encerrado": {
+			ExpectedResult: nil,
+			ExpectedErr:    transaction.ErrUserClosed,
+			PrepareMock: func(mockUserDb *mocks.MockDabataseUserInterface, mockAccountDb *mocks.MockDabataseAccountInterface, mockAuditDb *mocks.MockDabataseAuditInterface) {
+				mockUserDb.EXPECT().ReadOneByIdForUpdate(gomock.Any(), userId).Times(1).Return(&entity.User{ID: userId, Status: entity.CLOSED}, nil)
+			},
+		},
+		"deve retornar erro: usuario nao encontrado": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrNotFound,
+			PrepareMock: func(mockUserDb *mocks.MockDabataseUserInterface, mockAccountDb *mocks.MockDabataseAccountInterface, mockAuditDb *mocks.MockDabataseAuditInterface) {
+				mockUserDb.EXPECT().ReadOneByIdForUpdate(gomock.Any(), userId).Times(1).Return(nil, echo.ErrNotFound)
+			},
+		},
+		"deve retornar erro": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrInternalServerError,
+			PrepareMock: func(mockUserDb *mocks.MockDabataseUserInterface, mockAccountDb *mocks.MockDabataseAccountInterface, mockAuditDb *mocks.MockDabataseAuditInterface) {
+				gomock.InOrder(
+					mockUserDb.EXPECT().ReadOneByIdForUpdate(gomock.Any(), userId).Times(1).Return(&entity.User{ID: userId}, nil),
+					mockAccountDb.EXPECT().Create(gomock.Any(), account).Times(1).Return(echo.ErrInternalServerError),
+				)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			mockUserDb := mocks.NewMockDabataseUserInterface(ctrl)
+			mockAccountDb := mocks.NewMockDabataseAccountInterface(ctrl)
+			mockAuditDb := mocks.NewMockDabataseAuditInterface(ctrl)
+			cs.PrepareMock(mockUserDb, mockAccountDb, mockAuditDb)
+
+			mockUnitOfWork := mocks.NewMockUnitOfWorkInterface(ctrl)
+			container := &database.Container{User: mockUserDb, Account: mockAccountDb, Audit: mockAuditDb, UnitOfWork: mockUnitOfWork}
+			mockUnitOfWork.EXPECT().Do(gomock.Any(), gomock.Any()).Times(1).
+				DoAndReturn(func(ctx context.Context, fn func(tx *database.Container) error) error {
+					return fn(container)
+				})
+
+			app := NewAppAccount(container)
+
+			result, err := app.Create(ctx, account)
+			if diff := cmp.Diff(result, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestReadOneById(t *testing.T) {
+	cases := map[string]struct {
+		ExpectedResult *entity.Account
+		ExpectedErr    error
+		PrepareMock    func(mockAccountDb *mocks.MockDabataseAccountInterface)
+	}{
+		"deve retornar sucesso": {
+			ExpectedResult: &entity.Account{
+				ID:                "account-id",
+				UserId:            "user-id",
+				AccountTypeString: "PERSONAL",
+				Currency:          "BRL",
+				Balance:           money.New(-2000),
+				AvailableBalance:  money.New(-2000),
+				CreditLimit:       money.New(5000),
+				UsedCredit:        money.New(2000),
+				AvailableCredit:   money.New(3000),
+			},
+			ExpectedErr: nil,
+			PrepareMock: func(mockAccountDb *mocks.MockDabataseAccountInterface) {
+				mockAccountDb.EXPECT().ReadOneById(gomock.Any(), "account-id").Times(1).Return(&entity.Account{
+					ID:               "account-id",
+					UserId:           "user-id",
+					Currency:         "BRL",
+					Balance:          money.New(-2000),
+					AvailableBalance: money.New(-2000),
+					CreditLimit:      money.New(5000),
+				}, nil)
+			},
+		},
+		"deve retornar erro": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrNotFound,
+			PrepareMock: func(mockAccountDb *mocks.MockDabataseAccountInterface) {
+				mockAccountDb.EXPECT().ReadOneById(gomock.Any(), "account-id").Times(1).Return(nil, echo.ErrNotFound)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			mockAccountDb := mocks.NewMockDabataseAccountInterface(ctrl)
+			cs.PrepareMock(mockAccountDb)
+
+			app := NewAppAccount(&database.Container{Account: mockAccountDb})
+
+			account, err := app.ReadOneById(ctx, "account-id")
+			if diff := cmp.Diff(account, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestReadAllByUserId(t *testing.T) {
+	userId := "user-id"
+
+	cases := map[string]struct {
+		ExpectedResult []entity.Account
+		ExpectedErr    error
+		PrepareMock    func(mockUserDb *mocks.MockDabataseUserInterface, mockAccountDb *mocks.MockDabataseAccountInterface)
+	}{
+		"deve retornar sucesso": {
+			ExpectedResult: []entity.Account{
+				{ID: userId, UserId: userId, AccountTypeString: "PERSONAL", UsedCredit: money.New(0), AvailableCredit: money.New(0)},
+				{ID: "account-id", UserId: userId, Name: "MEI", AccountType: entity.BUSINESS, AccountTypeString: "BUSINESS", UsedCredit: money.New(0), AvailableCredit: money.New(0)},
+			},
+			ExpectedErr: nil,
+			PrepareMock: func(mockUserDb *mocks.MockDabataseUserInterface, mockAccountDb *mocks.MockDabataseAccountInterface) {
+				gomock.InOrder(
+					mockUserDb.EXPECT().ReadOneById(gomock.Any(), userId).Times(1).Return(&entity.User{ID: userId}, nil),
+					mockAccountDb.EXPECT().ReadAllByUserId(gomock.Any(), userId).Times(1).Return([]entity.Account{
+						{ID: userId, UserId: userId},
+						{ID: "account-id", UserId: userId, Name: "MEI", AccountType: entity.BUSINESS},
+					}, nil),
+				)
+			},
+		},
+		"deve retornar erro: usuario nao encontrado": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrNotFound,
+			PrepareMock: func(mockUserDb *mocks.MockDabataseUserInterface, mockAccountDb *mocks.MockDabataseAccountInterface) {
+				mockUserDb.EXPECT().ReadOneById(gomock.Any(), userId).Times(1).Return(nil, echo.ErrNotFound)
+			},
+		},
+		"deve retornar erro": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrInternalServerError,
+			PrepareMock: func(mockUserDb *mocks.MockDabataseUserInterface, mockAccountDb *mocks.MockDabataseAccountInterface) {
+				gomock.InOrder(
+					mockUserDb.EXPECT().ReadOneById(gomock.Any(), userId).Times(1).Return(&entity.User{ID: userId}, nil),
+					mockAccountDb.EXPECT().ReadAllByUserId(gomock.Any(), userId).Times(1).Return(nil, echo.ErrInternalServerError),
+				)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			mockUserDb := mocks.NewMockDabataseUserInterface(ctrl)
+			mockAccountDb := mocks.NewMockDabataseAccountInterface(ctrl)
+			cs.PrepareMock(mockUserDb, mockAccountDb)
+
+			app := NewAppAccount(&database.Container{User: mockUserDb, Account: mockAccountDb})
+
+			accounts, err := app.ReadAllByUserId(ctx, userId)
+			if diff := cmp.Diff(accounts, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
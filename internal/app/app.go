@@ -1,19 +1,55 @@
 package app
 
 import (
+	"net/http"
+
+	"github.com/garoque/backend-code-challenge-snapfi/internal/app/account"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/app/audit"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/app/balance"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/app/fee"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/app/idempotency"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/app/ledger"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/app/limit"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/app/outbox"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/app/standingorder"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/app/stream"
 	"github.com/garoque/backend-code-challenge-snapfi/internal/app/transaction"
 	"github.com/garoque/backend-code-challenge-snapfi/internal/app/user"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/app/webhook"
 	"github.com/garoque/backend-code-challenge-snapfi/internal/database"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/publisher"
 )
 
 type Container struct {
-	User        user.AppUserInterface
-	Transaction transaction.AppTransactionInterface
+	User          user.AppUserInterface
+	Account       account.AppAccountInterface
+	Transaction   transaction.AppTransactionInterface
+	Idempotency   idempotency.AppIdempotencyInterface
+	Ledger        ledger.AppLedgerInterface
+	StandingOrder standingorder.AppStandingOrderInterface
+	Fee           fee.AppFeeInterface
+	Limit         limit.AppLimitInterface
+	Balance       balance.AppBalanceInterface
+	Outbox        outbox.AppOutboxInterface
+	Webhook       webhook.AppWebhookInterface
+	Stream        stream.AppStreamInterface
+	Audit         audit.AppAuditInterface
 }
 
-func New(db *database.Container) *Container {
+func New(db *database.Container, publisher publisher.Publisher) *Container {
 	return &Container{
-		User:        user.NewAppUser(db),
-		Transaction: transaction.NewAppTransaction(db),
+		User:          user.NewAppUser(db),
+		Account:       account.NewAppAccount(db),
+		Transaction:   transaction.NewAppTransaction(db),
+		Idempotency:   idempotency.NewAppIdempotency(db, idempotency.DefaultTTL, idempotency.DefaultLease),
+		Ledger:        ledger.NewAppLedger(db),
+		StandingOrder: standingorder.NewAppStandingOrder(db, standingorder.DefaultRetryPolicy),
+		Fee:           fee.NewAppFee(db),
+		Limit:         limit.NewAppLimit(db),
+		Balance:       balance.NewAppBalance(db),
+		Outbox:        outbox.NewAppOutbox(db, publisher),
+		Webhook:       webhook.NewAppWebhook(db, http.DefaultClient, webhook.DefaultRetryPolicy),
+		Stream:        stream.NewAppStream(db, stream.DefaultHeartbeat),
+		Audit:         audit.NewAppAudit(db),
 	}
 }
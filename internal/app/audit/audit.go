@@ -0,0 +1,76 @@
+package audit
+
+import (
+	"context"
+	"log"
+
+	"github.com/garoque/backend-code-challenge-snapfi/internal/database"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+)
+
+// DefaultLimit is how many entries ReadAll returns when the filter doesn't say,
+// and MaxLimit the most it returns at once.
+const (
+	DefaultLimit = 100
+	MaxLimit     = 1000
+)
+
+type AppAuditInterface interface {
+	ReadAll(ctx context.Context, filter entity.AuditFilter) ([]entity.AuditEntry, error)
+}
+
+type appAuditImpl struct {
+	db *database.Container
+}
+
+func NewAppAudit(db *database.Container) AppAuditInterface {
+	return &appAuditImpl{db}
+}
+
+// ReadAll lists the entries of the audit log matching the filter, newest
+// first.
+func (a *appAuditImpl) ReadAll(ctx context.Context, filter entity.AuditFilter) ([]entity.AuditEntry, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultLimit
+	}
+	if filter.Limit > MaxLimit {
+		filter.Limit = MaxLimit
+	}
+
+	entries, err := a.db.Audit.ReadAll(ctx, filter)
+	if err != nil {
+		log.Println("Error app.Audit.ReadAll.db.ReadAll: ", err.Error())
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+type requestKey struct{}
+
+// WithRequest returns a copy of ctx carrying who sent the request, so the
+// changes made for it are recorded under their name.
+func WithRequest(ctx context.Context, request entity.AuditRequest) context.Context {
+	return context.WithValue(ctx, requestKey{}, request)
+}
+
+// RequestFromContext returns the request ctx carries, or the system actor for
+// the changes made outside of a request, such as by the scheduled jobs.
+func RequestFromContext(ctx context.Context) entity.AuditRequest {
+	if request, ok := ctx.Value(requestKey{}).(entity.AuditRequest); ok {
+		return request
+	}
+
+	return entity.AuditRequest{Actor: entity.AUDIT_SYSTEM_ACTOR}
+}
+
+// Record appends the change to the audit log. It's called with the unit of
+// work of the change, so the entry is only kept when the change is.
+func Record(ctx context.Context, tx *database.Container, operation, entityType, entityId string, before, after interface{}) error {
+	entry, err := entity.NewAuditEntry(RequestFromContext(ctx), operation, entityType, entityId, before, after)
+	if err != nil {
+		return err
+	}
+
+	return tx.Audit.Create(ctx, entry)
+}
@@ -0,0 +1,112 @@
+package audit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/garoque/backend-code-challenge-snapfi/internal/database"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+	"github.com/labstack/echo/v4"
+)
+
+func TestReadAll(t *testing.T) {
+	entries := []entity.AuditEntry{{ID: "entry-id", Actor: "jane.doe", Operation: entity.AUDIT_USER_CREATED}}
+
+	cases := map[string]struct {
+		InputFilter    entity.AuditFilter
+		ExpectedResult []entity.AuditEntry
+		ExpectedErr    error
+		PrepareMock    func(mockAuditDb *mocks.MockDabataseAuditInterface)
+	}{
+		"deve retornar sucesso": {
+			InputFilter:    entity.AuditFilter{Actor: "jane.doe"},
+			ExpectedResult: entries,
+			ExpectedErr:    nil,
+			PrepareMock: func(mockAuditDb *mocks.MockDabataseAuditInterface) {
+				mockAuditDb.EXPECT().ReadAll(gomock.Any(), entity.AuditFilter{Actor: "jane.doe", Limit: DefaultLimit}).Times(1).Return(entries, nil)
+			},
+		},
+		"deve retornar sucesso: limite maximo": {
+			InputFilter:    entity.AuditFilter{Limit: 5000},
+			ExpectedResult: entries,
+			ExpectedErr:    nil,
+			PrepareMock: func(mockAuditDb *mocks.MockDabataseAuditInterface) {
+				mockAuditDb.EXPECT().ReadAll(gomock.Any(), entity.AuditFilter{Limit: MaxLimit}).Times(1).Return(entries, nil)
+			},
+		},
+		"deve retornar erro": {
+			InputFilter:    entity.AuditFilter{Limit: 10},
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrInternalServerError,
+			PrepareMock: func(mockAuditDb *mocks.MockDabataseAuditInterface) {
+				mockAuditDb.EXPECT().ReadAll(gomock.Any(), entity.AuditFilter{Limit: 10}).Times(1).Return(nil, echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			mockAuditDb := mocks.NewMockDabataseAuditInterface(ctrl)
+			cs.PrepareMock(mockAuditDb)
+
+			app := NewAppAudit(&database.Container{Audit: mockAuditDb})
+
+			result, err := app.ReadAll(ctx, cs.InputFilter)
+			if diff := cmp.Diff(result, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestRecord(t *testing.T) {
+	request := entity.AuditRequest{Actor: "jane.doe", RequestId: "request-id", ClientIP: "203.0.113.7"}
+
+	cases := map[string]struct {
+		Context         func(ctx context.Context) context.Context
+		ExpectedRequest entity.AuditRequest
+	}{
+		"deve retornar sucesso": {
+			Context:         func(ctx context.Context) context.Context { return WithRequest(ctx, request) },
+			ExpectedRequest: request,
+		},
+		"deve retornar sucesso: fora de uma requisicao": {
+			Context:         func(ctx context.Context) context.Context { return ctx },
+			ExpectedRequest: entity.AuditRequest{Actor: entity.AUDIT_SYSTEM_ACTOR},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			mockAuditDb := mocks.NewMockDabataseAuditInterface(ctrl)
+			mockAuditDb.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).
+				DoAndReturn(func(ctx context.Context, entry *entity.AuditEntry) error {
+					got := entity.AuditRequest{Actor: entry.Actor, RequestId: entry.RequestId, ClientIP: entry.ClientIP}
+					if diff := cmp.Diff(got, cs.ExpectedRequest); diff != "" {
+						t.Error(diff)
+					}
+
+					if entry.Operation != entity.AUDIT_USER_CREATED || entry.EntityId != "user-id" || entry.Before != nil || string(*entry.After) != `{"id":"user-id"}` {
+						t.Errorf("unexpected audit entry %+v", entry)
+					}
+					return nil
+				})
+
+			err := Record(cs.Context(ctx), &database.Container{Audit: mockAuditDb}, entity.AUDIT_USER_CREATED, entity.AGGREGATE_USER, "user-id", nil, map[string]string{"id": "user-id"})
+			if err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,232 @@
+package balance
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/garoque/backend-code-challenge-snapfi/internal/database"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/export"
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/money"
+	"github.com/labstack/echo/v4"
+)
+
+type AppBalanceInterface interface {
+	ReadAt(ctx context.Context, userId, accountId string, at time.Time) (*entity.BalanceAt, error)
+	ReadHistory(ctx context.Context, userId, accountId string, from, to time.Time, interval string) (*entity.BalanceHistory, error)
+	ReadStatement(ctx context.Context, userId, accountId string, start, end time.Time) (*entity.Statement, error)
+	ExportStatement(ctx context.Context, userId, accountId string, start, end time.Time, writer export.Writer) error
+	TakeSnapshots(ctx context.Context) error
+}
+
+type appBalanceImpl struct {
+	db *database.Container
+}
+
+func NewAppBalance(db *database.Container) AppBalanceInterface {
+	return &appBalanceImpl{db}
+}
+
+// ReadAt returns the ledger balance of the account of the user from the
+// transactions booked up to at. Postings are stored to the second, so the ones
+// made during the second of at are included.
+func (b *appBalanceImpl) ReadAt(ctx context.Context, userId, accountId string, at time.Time) (*entity.BalanceAt, error) {
+	_, account, err := readAccount(ctx, b.db, userId, accountId)
+	if err != nil {
+		log.Println("Error app.Balance.ReadAt.readAccount: ", err.Error())
+		return nil, err
+	}
+
+	balance, err := balanceBefore(ctx, b.db, account.ID, at.Truncate(time.Second).Add(time.Second))
+	if err != nil {
+		log.Println("Error app.Balance.ReadAt.balanceBefore: ", err.Error())
+		return nil, err
+	}
+
+	return &entity.BalanceAt{UserId: userId, AccountId: account.ID, At: at, Balance: balance}, nil
+}
+
+// ReadHistory returns the balance of the account of the user at the end of
+// every day or hour from from to to, both included.
+func (b *appBalanceImpl) ReadHistory(ctx context.Context, userId, accountId string, from, to time.Time, interval string) (*entity.BalanceHistory, error) {
+	start, end, err := entity.BalancePeriods(from, to, interval)
+	if err != nil {
+		return nil, echo.NewHTTPError(echo.ErrBadRequest.Code, err.Error())
+	}
+
+	_, account, err := readAccount(ctx, b.db, userId, accountId)
+	if err != nil {
+		log.Println("Error app.Balance.ReadHistory.readAccount: ", err.Error())
+		return nil, err
+	}
+
+	opening, err := balanceBefore(ctx, b.db, account.ID, start)
+	if err != nil {
+		log.Println("Error app.Balance.ReadHistory.balanceBefore: ", err.Error())
+		return nil, err
+	}
+
+	postings, err := b.db.Ledger.ReadAccountPostings(ctx, account.ID, start, end)
+	if err != nil {
+		log.Println("Error app.Balance.ReadHistory.db.ReadAccountPostings: ", err.Error())
+		return nil, err
+	}
+
+	return entity.NewBalanceHistory(account, interval, start, end, opening, postings), nil
+}
+
+// ReadStatement lists the movements booked on the account of the user from
+// start until before end, with the balances they take it from and to.
+func (b *appBalanceImpl) ReadStatement(ctx context.Context, userId, accountId string, start, end time.Time) (*entity.Statement, error) {
+	user, account, err := readAccount(ctx, b.db, userId, accountId)
+	if err != nil {
+		log.Println("Error app.Balance.ReadStatement.readAccount: ", err.Error())
+		return nil, err
+	}
+
+	opening, err := balanceBefore(ctx, b.db, account.ID, start)
+	if err != nil {
+		log.Println("Error app.Balance.ReadStatement.balanceBefore: ", err.Error())
+		return nil, err
+	}
+
+	lines, err := b.db.Transaction.ReadStatementLines(ctx, account.ID, start, end)
+	if err != nil {
+		log.Println("Error app.Balance.ReadStatement.db.ReadStatementLines: ", err.Error())
+		return nil, err
+	}
+
+	return entity.NewStatement(user, account, start, end, opening, lines), nil
+}
+
+// ExportStatement writes the statement ReadStatement would return through the
+// writer, streaming its lines from the database instead of loading them. The
+// totals are read upfront, since some formats carry the closing balance
+// before the lines, and in the same database transaction as the lines so both
+// see the same movements. Once the header is written the response has begun,
+// so later errors can only cut the file short.
+func (b *appBalanceImpl) ExportStatement(ctx context.Context, userId, accountId string, start, end time.Time, writer export.Writer) error {
+	return b.db.UnitOfWork.Do(ctx, func(tx *database.Container) error {
+		user, account, err := readAccount(ctx, tx, userId, accountId)
+		if err != nil {
+			log.Println("Error app.Balance.ExportStatement.readAccount: ", err.Error())
+			return err
+		}
+
+		opening, err := balanceBefore(ctx, tx, account.ID, start)
+		if err != nil {
+			log.Println("Error app.Balance.ExportStatement.balanceBefore: ", err.Error())
+			return err
+		}
+
+		in, out, err := tx.Ledger.ReadAccountTotals(ctx, account.ID, start, end)
+		if err != nil {
+			log.Println("Error app.Balance.ExportStatement.db.ReadAccountTotals: ", err.Error())
+			return err
+		}
+
+		statement := entity.NewStatement(user, account, start, end, opening, nil)
+		statement.TotalIn = in
+		statement.TotalOut = out
+		statement.ClosingBalance = money.New(opening.Amount + in.Amount - out.Amount)
+
+		if err := writer.WriteHeader(statement); err != nil {
+			log.Println("Error app.Balance.ExportStatement.writer.WriteHeader: ", err.Error())
+			return err
+		}
+
+		balance := opening.Amount
+		err = tx.Transaction.StreamStatementLines(ctx, account.ID, start, end, func(line entity.StatementLine) error {
+			balance += line.Amount.Amount
+			line.Balance = money.New(balance)
+			line.KindString = line.Kind.String()
+
+			return writer.WriteLine(line)
+		})
+		if err != nil {
+			log.Println("Error app.Balance.ExportStatement.db.StreamStatementLines: ", err.Error())
+			return err
+		}
+
+		return writer.Close()
+	})
+}
+
+// readAccount returns the user and the account a request addresses: the
+// default account of the user, unless accountId names another one of theirs.
+func readAccount(ctx context.Context, db *database.Container, userId, accountId string) (*entity.User, *entity.Account, error) {
+	user, err := db.User.ReadOneById(ctx, userId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	account, err := db.Account.ReadOneById(ctx, entity.AccountId(accountId, userId))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if account.UserId != userId {
+		return nil, nil, echo.ErrNotFound
+	}
+
+	return user, account, nil
+}
+
+// balanceBefore sums the postings of the account made before until, starting
+// from its latest snapshot so only the postings made since are read.
+func balanceBefore(ctx context.Context, db *database.Container, accountId string, until time.Time) (money.Money, error) {
+	snapshot, err := db.Balance.ReadLatestSnapshot(ctx, accountId, until)
+	if err != nil {
+		return money.Money{}, err
+	}
+
+	opening := money.New(0)
+	var since *time.Time
+	if snapshot != nil {
+		opening = snapshot.Balance
+		since = &snapshot.TakenAt
+	}
+
+	movements, err := db.Ledger.ReadAccountBalance(ctx, accountId, since, until)
+	if err != nil {
+		return money.Money{}, err
+	}
+
+	return opening.Add(movements)
+}
+
+// TakeSnapshots snapshots the balances of every account at each midnight since
+// the last snapshots, up to the last one at least entity.SnapshotDelay ago.
+func (b *appBalanceImpl) TakeSnapshots(ctx context.Context) error {
+	target := entity.StartOfDay(time.Now().Add(-entity.SnapshotDelay))
+
+	last, err := b.db.Balance.ReadLastSnapshotAt(ctx)
+	if err != nil {
+		log.Println("Error app.Balance.TakeSnapshots.db.ReadLastSnapshotAt: ", err.Error())
+		return err
+	}
+
+	if last == nil {
+		err = b.db.Balance.CreateSnapshots(ctx, nil, target)
+		if err != nil {
+			log.Println("Error app.Balance.TakeSnapshots.db.CreateSnapshots: ", err.Error())
+			return err
+		}
+
+		return nil
+	}
+
+	previous := *last
+	for day := previous.AddDate(0, 0, 1); !day.After(target); day = day.AddDate(0, 0, 1) {
+		err = b.db.Balance.CreateSnapshots(ctx, &previous, day)
+		if err != nil {
+			log.Println("Error app.Balance.TakeSnapshots.db.CreateSnapshots: ", err.Error())
+			return err
+		}
+
+		previous = day
+	}
+
+	return nil
+}
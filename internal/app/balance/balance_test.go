@@ -0,0 +1,470 @@
+package balance
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/garoque/backend-code-challenge-snapfi/internal/database"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/export"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/mocks"
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/money"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+	"github.com/labstack/echo/v4"
+)
+
+type databaseMocks struct {
+	User        *mocks.MockDabataseUserInterface
+	Account     *mocks.MockDabataseAccountInterface
+	Transaction *mocks.MockDabataseTransactionInterface
+	Ledger      *mocks.MockDabataseLedgerInterface
+	Balance     *mocks.MockDabataseBalanceInterface
+}
+
+func newDatabaseContainer(ctrl *gomock.Controller) (*database.Container, *databaseMocks) {
+	db := &databaseMocks{
+		User:        mocks.NewMockDabataseUserInterface(ctrl),
+		Account:     mocks.NewMockDabataseAccountInterface(ctrl),
+		Transaction: mocks.NewMockDabataseTransactionInterface(ctrl),
+		Ledger:      mocks.NewMockDabataseLedgerInterface(ctrl),
+		Balance:     mocks.NewMockDabataseBalanceInterface(ctrl),
+	}
+
+	mockUnitOfWork := mocks.NewMockUnitOfWorkInterface(ctrl)
+
+	container := &database.Container{
+		User:        db.User,
+		Account:     db.Account,
+		Transaction: db.Transaction,
+		Ledger:      db.Ledger,
+		Balance:     db.Balance,
+		UnitOfWork:  mockUnitOfWork,
+	}
+	mockUnitOfWork.EXPECT().Do(gomock.Any(), gomock.Any()).AnyTimes().
+		DoAndReturn(func(ctx context.Context, fn func(tx *database.Container) error) error {
+			return fn(container)
+		})
+
+	return container, db
+}
+
+func TestReadAt(t *testing.T) {
+	userId := "user-id"
+	at := time.Date(2023, 5, 2, 12, 30, 15, 500, time.UTC)
+	until := time.Date(2023, 5, 2, 12, 30, 16, 0, time.UTC)
+	snapshot := &entity.BalanceSnapshot{AccountId: userId, TakenAt: time.Date(2023, 5, 2, 0, 0, 0, 0, time.UTC), Balance: money.New(10000)}
+
+	cases := map[string]struct {
+		ExpectedResult *entity.BalanceAt
+		ExpectedErr    error
+		PrepareMock    func(db *databaseMocks)
+	}{
+		"deve retornar sucesso": {
+			ExpectedResult: &entity.BalanceAt{UserId: userId, AccountId: userId, At: at, Balance: money.New(7500)},
+			ExpectedErr:    nil,
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.User.EXPECT().ReadOneById(gomock.Any(), userId).Times(1).Return(&entity.User{ID: userId}, nil),
+					db.Account.EXPECT().ReadOneById(gomock.Any(), userId).Times(1).Return(&entity.Account{ID: userId, UserId: userId}, nil),
+					db.Balance.EXPECT().ReadLatestSnapshot(gomock.Any(), userId, until).Times(1).Return(snapshot, nil),
+					db.Ledger.EXPECT().ReadAccountBalance(gomock.Any(), userId, &snapshot.TakenAt, until).Times(1).Return(money.New(-2500), nil),
+				)
+			},
+		},
+		"deve retornar sucesso: sem snapshot": {
+			ExpectedResult: &entity.BalanceAt{UserId: userId, AccountId: userId, At: at, Balance: money.New(-2500)},
+			ExpectedErr:    nil,
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.User.EXPECT().ReadOneById(gomock.Any(), userId).Times(1).Return(&entity.User{ID: userId}, nil),
+					db.Account.EXPECT().ReadOneById(gomock.Any(), userId).Times(1).Return(&entity.Account{ID: userId, UserId: userId}, nil),
+					db.Balance.EXPECT().ReadLatestSnapshot(gomock.Any(), userId, until).Times(1).Return(nil, nil),
+					db.Ledger.EXPECT().ReadAccountBalance(gomock.Any(), userId, nil, until).Times(1).Return(money.New(-2500), nil),
+				)
+			},
+		},
+		"deve retornar erro: usuario nao encontrado": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrNotFound,
+			PrepareMock: func(db *databaseMocks) {
+				db.User.EXPECT().ReadOneById(gomock.Any(), userId).Times(1).Return(nil, echo.ErrNotFound)
+			},
+		},
+		"deve retornar erro": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrInternalServerError,
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.User.EXPECT().ReadOneById(gomock.Any(), userId).Times(1).Return(&entity.User{ID: userId}, nil),
+					db.Account.EXPECT().ReadOneById(gomock.Any(), userId).Times(1).Return(&entity.Account{ID: userId, UserId: userId}, nil),
+					db.Balance.EXPECT().ReadLatestSnapshot(gomock.Any(), userId, until).Times(1).Return(nil, echo.ErrInternalServerError),
+				)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			container, db := newDatabaseContainer(ctrl)
+			cs.PrepareMock(db)
+
+			app := NewAppBalance(container)
+
+			balance, err := app.ReadAt(ctx, userId, "", at)
+			if diff := cmp.Diff(balance, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestReadHistory(t *testing.T) {
+	userId := "user-id"
+	from := time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC)
+	to := time.Date(2023, 5, 2, 10, 0, 0, 0, time.UTC)
+	start := time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC)
+	middle := time.Date(2023, 5, 2, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2023, 5, 3, 0, 0, 0, 0, time.UTC)
+	createdAt := time.Date(2023, 5, 2, 9, 0, 0, 0, time.UTC)
+	snapshot := &entity.BalanceSnapshot{AccountId: userId, TakenAt: start, Balance: money.New(10000)}
+
+	cases := map[string]struct {
+		InputTo        time.Time
+		ExpectedResult *entity.BalanceHistory
+		ExpectedErr    error
+		PrepareMock    func(db *databaseMocks)
+	}{
+		"deve retornar sucesso": {
+			InputTo: to,
+			ExpectedResult: &entity.BalanceHistory{
+				UserId:    userId,
+				AccountId: userId,
+				Interval:  entity.BALANCE_DAILY,
+				Periods: []entity.BalancePeriod{
+					{Start: start, End: middle, Balance: money.New(10000)},
+					{Start: middle, End: end, Balance: money.New(12500)},
+				},
+			},
+			ExpectedErr: nil,
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.User.EXPECT().ReadOneById(gomock.Any(), userId).Times(1).Return(&entity.User{ID: userId}, nil),
+					db.Account.EXPECT().ReadOneById(gomock.Any(), userId).Times(1).Return(&entity.Account{ID: userId, UserId: userId}, nil),
+					db.Balance.EXPECT().ReadLatestSnapshot(gomock.Any(), userId, start).Times(1).Return(snapshot, nil),
+					db.Ledger.EXPECT().ReadAccountBalance(gomock.Any(), userId, &start, start).Times(1).Return(money.New(0), nil),
+					db.Ledger.EXPECT().ReadAccountPostings(gomock.Any(), userId, start, end).Times(1).Return([]entity.Posting{
+						{AccountId: userId, Amount: money.New(2500), CreatedAt: &createdAt},
+					}, nil),
+				)
+			},
+		},
+		"deve retornar erro: periodo muito longo": {
+			InputTo:        from.AddDate(3, 0, 0),
+			ExpectedResult: nil,
+			ExpectedErr:    echo.NewHTTPError(echo.ErrBadRequest.Code, entity.ErrTooManyBalancePeriods.Error()),
+			PrepareMock:    func(db *databaseMocks) {},
+		},
+		"deve retornar erro: usuario nao encontrado": {
+			InputTo:        to,
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrNotFound,
+			PrepareMock: func(db *databaseMocks) {
+				db.User.EXPECT().ReadOneById(gomock.Any(), userId).Times(1).Return(nil, echo.ErrNotFound)
+			},
+		},
+		"deve retornar erro": {
+			InputTo:        to,
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrInternalServerError,
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.User.EXPECT().ReadOneById(gomock.Any(), userId).Times(1).Return(&entity.User{ID: userId}, nil),
+					db.Account.EXPECT().ReadOneById(gomock.Any(), userId).Times(1).Return(&entity.Account{ID: userId, UserId: userId}, nil),
+					db.Balance.EXPECT().ReadLatestSnapshot(gomock.Any(), userId, start).Times(1).Return(nil, nil),
+					db.Ledger.EXPECT().ReadAccountBalance(gomock.Any(), userId, nil, start).Times(1).Return(money.New(0), nil),
+					db.Ledger.EXPECT().ReadAccountPostings(gomock.Any(), userId, start, end).Times(1).Return(nil, echo.ErrInternalServerError),
+				)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			container, db := newDatabaseContainer(ctrl)
+			cs.PrepareMock(db)
+
+			app := NewAppBalance(container)
+
+			history, err := app.ReadHistory(ctx, userId, "", from, cs.InputTo, entity.BALANCE_DAILY)
+			if diff := cmp.Diff(history, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestReadStatement(t *testing.T) {
+	user := &entity.User{ID: "user-id", Name: "Gabriel"}
+	account := &entity.Account{ID: "savings-id", UserId: "user-id", Currency: "USD"}
+	start := time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	bookedAt := time.Date(2023, 5, 10, 9, 0, 0, 0, time.UTC)
+
+	cases := map[string]struct {
+		ExpectedResult *entity.Statement
+		ExpectedErr    error
+		PrepareMock    func(db *databaseMocks)
+	}{
+		"deve retornar sucesso": {
+			ExpectedResult: &entity.Statement{
+				UserId:         "user-id",
+				AccountId:      "savings-id",
+				Currency:       "USD",
+				Name:           "Gabriel",
+				Start:          start,
+				End:            end,
+				OpeningBalance: money.New(10000),
+				TotalIn:        money.New(0),
+				TotalOut:       money.New(2500),
+				ClosingBalance: money.New(7500),
+				Lines: []entity.StatementLine{{
+					TransactionId:  "transaction-id",
+					Kind:           entity.WITHDRAWAL,
+					KindString:     "WITHDRAWAL",
+					CounterpartyId: entity.WithdrawalAccountId,
+					Amount:         money.New(-2500),
+					Balance:        money.New(7500),
+					BookedAt:       bookedAt,
+				}},
+			},
+			ExpectedErr: nil,
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.User.EXPECT().ReadOneById(gomock.Any(), "user-id").Times(1).Return(user, nil),
+					db.Account.EXPECT().ReadOneById(gomock.Any(), "savings-id").Times(1).Return(account, nil),
+					db.Balance.EXPECT().ReadLatestSnapshot(gomock.Any(), "savings-id", start).Times(1).Return(nil, nil),
+					db.Ledger.EXPECT().ReadAccountBalance(gomock.Any(), "savings-id", nil, start).Times(1).Return(money.New(10000), nil),
+					db.Transaction.EXPECT().ReadStatementLines(gomock.Any(), "savings-id", start, end).Times(1).Return([]entity.StatementLine{{
+						TransactionId:  "transaction-id",
+						Kind:           entity.WITHDRAWAL,
+						CounterpartyId: entity.WithdrawalAccountId,
+						Amount:         money.New(-2500),
+						BookedAt:       bookedAt,
+					}}, nil),
+				)
+			},
+		},
+		"deve retornar erro: usuario nao encontrado": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrNotFound,
+			PrepareMock: func(db *databaseMocks) {
+				db.User.EXPECT().ReadOneById(gomock.Any(), "user-id").Times(1).Return(nil, echo.ErrNotFound)
+			},
+		},
+		"deve retornar erro: conta de outro usuario": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrNotFound,
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.User.EXPECT().ReadOneById(gomock.Any(), "user-id").Times(1).Return(user, nil),
+					db.Account.EXPECT().ReadOneById(gomock.Any(), "savings-id").Times(1).Return(&entity.Account{ID: "savings-id", UserId: "other-user-id"}, nil),
+				)
+			},
+		},
+		"deve retornar erro": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrInternalServerError,
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.User.EXPECT().ReadOneById(gomock.Any(), "user-id").Times(1).Return(user, nil),
+					db.Account.EXPECT().ReadOneById(gomock.Any(), "savings-id").Times(1).Return(account, nil),
+					db.Balance.EXPECT().ReadLatestSnapshot(gomock.Any(), "savings-id", start).Times(1).Return(nil, nil),
+					db.Ledger.EXPECT().ReadAccountBalance(gomock.Any(), "savings-id", nil, start).Times(1).Return(money.New(10000), nil),
+					db.Transaction.EXPECT().ReadStatementLines(gomock.Any(), "savings-id", start, end).Times(1).Return(nil, echo.ErrInternalServerError),
+				)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			container, db := newDatabaseContainer(ctrl)
+			cs.PrepareMock(db)
+
+			app := NewAppBalance(container)
+
+			statement, err := app.ReadStatement(ctx, "user-id", "savings-id", start, end)
+			if diff := cmp.Diff(statement, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestExportStatement(t *testing.T) {
+	user := &entity.User{ID: "user-id", Name: "Gabriel"}
+	account := &entity.Account{ID: "user-id", UserId: "user-id", Currency: "BRL"}
+	start := time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	bookedAt := time.Date(2023, 5, 10, 9, 0, 0, 0, time.UTC)
+
+	streamLines := func(ctx context.Context, userId string, start, end time.Time, fn func(line entity.StatementLine) error) error {
+		for _, line := range []entity.StatementLine{
+			{TransactionId: "deposit-id", Kind: entity.DEPOSIT, CounterpartyId: entity.FundingAccountId, Amount: money.New(5000), BookedAt: bookedAt},
+			{TransactionId: "withdrawal-id", Kind: entity.WITHDRAWAL, CounterpartyId: entity.WithdrawalAccountId, Amount: money.New(-2500), BookedAt: bookedAt},
+		} {
+			if err := fn(line); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	cases := map[string]struct {
+		ExpectedResult string
+		ExpectedErr    error
+		PrepareMock    func(db *databaseMocks)
+	}{
+		"deve retornar sucesso": {
+			ExpectedResult: "bookedAt,transactionId,kind,counterpartyId,counterpartyName,amount,balance\n" +
+				"2023-05-10T09:00:00Z,deposit-id,DEPOSIT,system:funding,,50.00,150.00\n" +
+				"2023-05-10T09:00:00Z,withdrawal-id,WITHDRAWAL,system:withdrawals,,-25.00,125.00\n",
+			ExpectedErr: nil,
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.User.EXPECT().ReadOneById(gomock.Any(), "user-id").Times(1).Return(user, nil),
+					db.Account.EXPECT().ReadOneById(gomock.Any(), "user-id").Times(1).Return(account, nil),
+					db.Balance.EXPECT().ReadLatestSnapshot(gomock.Any(), "user-id", start).Times(1).Return(nil, nil),
+					db.Ledger.EXPECT().ReadAccountBalance(gomock.Any(), "user-id", nil, start).Times(1).Return(money.New(10000), nil),
+					db.Ledger.EXPECT().ReadAccountTotals(gomock.Any(), "user-id", start, end).Times(1).Return(money.New(5000), money.New(2500), nil),
+					db.Transaction.EXPECT().StreamStatementLines(gomock.Any(), "user-id", start, end, gomock.Any()).Times(1).DoAndReturn(streamLines),
+				)
+			},
+		},
+		"deve retornar erro: usuario nao encontrado": {
+			ExpectedResult: "",
+			ExpectedErr:    echo.ErrNotFound,
+			PrepareMock: func(db *databaseMocks) {
+				db.User.EXPECT().ReadOneById(gomock.Any(), "user-id").Times(1).Return(nil, echo.ErrNotFound)
+			},
+		},
+		"deve retornar erro": {
+			ExpectedResult: "",
+			ExpectedErr:    echo.ErrInternalServerError,
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.User.EXPECT().ReadOneById(gomock.Any(), "user-id").Times(1).Return(user, nil),
+					db.Account.EXPECT().ReadOneById(gomock.Any(), "user-id").Times(1).Return(account, nil),
+					db.Balance.EXPECT().ReadLatestSnapshot(gomock.Any(), "user-id", start).Times(1).Return(nil, nil),
+					db.Ledger.EXPECT().ReadAccountBalance(gomock.Any(), "user-id", nil, start).Times(1).Return(money.New(10000), nil),
+					db.Ledger.EXPECT().ReadAccountTotals(gomock.Any(), "user-id", start, end).Times(1).Return(money.Money{}, money.Money{}, echo.ErrInternalServerError),
+				)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			container, db := newDatabaseContainer(ctrl)
+			cs.PrepareMock(db)
+
+			app := NewAppBalance(container)
+
+			var buf bytes.Buffer
+			err := app.ExportStatement(ctx, "user-id", "", start, end, export.NewCSVWriter(&buf))
+			if diff := cmp.Diff(buf.String(), cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestTakeSnapshots(t *testing.T) {
+	target := entity.StartOfDay(time.Now().Add(-entity.SnapshotDelay))
+	twoDaysBefore := target.AddDate(0, 0, -2)
+	dayBefore := target.AddDate(0, 0, -1)
+
+	cases := map[string]struct {
+		ExpectedErr error
+		PrepareMock func(db *databaseMocks)
+	}{
+		"deve retornar sucesso: primeiro snapshot": {
+			ExpectedErr: nil,
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.Balance.EXPECT().ReadLastSnapshotAt(gomock.Any()).Times(1).Return(nil, nil),
+					db.Balance.EXPECT().CreateSnapshots(gomock.Any(), nil, target).Times(1).Return(nil),
+				)
+			},
+		},
+		"deve retornar sucesso: dias pendentes": {
+			ExpectedErr: nil,
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.Balance.EXPECT().ReadLastSnapshotAt(gomock.Any()).Times(1).Return(&twoDaysBefore, nil),
+					db.Balance.EXPECT().CreateSnapshots(gomock.Any(), &twoDaysBefore, dayBefore).Times(1).Return(nil),
+					db.Balance.EXPECT().CreateSnapshots(gomock.Any(), &dayBefore, target).Times(1).Return(nil),
+				)
+			},
+		},
+		"deve retornar sucesso: nada pendente": {
+			ExpectedErr: nil,
+			PrepareMock: func(db *databaseMocks) {
+				db.Balance.EXPECT().ReadLastSnapshotAt(gomock.Any()).Times(1).Return(&target, nil)
+			},
+		},
+		"deve retornar erro": {
+			ExpectedErr: echo.ErrInternalServerError,
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.Balance.EXPECT().ReadLastSnapshotAt(gomock.Any()).Times(1).Return(&dayBefore, nil),
+					db.Balance.EXPECT().CreateSnapshots(gomock.Any(), &dayBefore, target).Times(1).Return(echo.ErrInternalServerError),
+				)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			container, db := newDatabaseContainer(ctrl)
+			cs.PrepareMock(db)
+
+			app := NewAppBalance(container)
+
+			err := app.TakeSnapshots(ctx)
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
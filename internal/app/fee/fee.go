@@ -0,0 +1,127 @@
+package fee
+
+import (
+	"context"
+	"log"
+
+	"github.com/garoque/backend-code-challenge-snapfi/internal/app/audit"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/database"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	"github.com/labstack/echo/v4"
+)
+
+type AppFeeInterface interface {
+	Create(ctx context.Context, rule *entity.FeeRule) (*entity.FeeRule, error)
+	ReadAll(ctx context.Context) ([]entity.FeeRule, error)
+	ReadOneById(ctx context.Context, id string) (*entity.FeeRule, error)
+	Deactivate(ctx context.Context, id string) (*entity.FeeRule, error)
+}
+
+type appFeeImpl struct {
+	db *database.Container
+}
+
+func NewAppFee(db *database.Container) AppFeeInterface {
+	return &appFeeImpl{db}
+}
+
+func (f *appFeeImpl) Create(ctx context.Context, rule *entity.FeeRule) (*entity.FeeRule, error) {
+	err := rule.Validate()
+	if err != nil {
+		return nil, echo.NewHTTPError(echo.ErrBadRequest.Code, err.Error())
+	}
+
+	err = f.db.UnitOfWork.Do(ctx, func(tx *database.Container) error {
+		err := tx.Fee.Create(ctx, rule)
+		if err != nil {
+			log.Println("Error app.Fee.Create.db.Create: ", err.Error())
+			return err
+		}
+
+		err = audit.Record(ctx, tx, entity.AUDIT_FEE_RULE_CREATED, entity.AUDIT_FEE_RULE, rule.ID, nil, withStrings(rule))
+		if err != nil {
+			log.Println("Error app.Fee.Create.audit.Record: ", err.Error())
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return withStrings(rule), nil
+}
+
+func (f *appFeeImpl) ReadAll(ctx context.Context) ([]entity.FeeRule, error) {
+	rules, err := f.db.Fee.ReadAll(ctx)
+	if err != nil {
+		log.Println("Error app.Fee.ReadAll.db.ReadAll: ", err.Error())
+		return nil, err
+	}
+
+	for i := range rules {
+		withStrings(&rules[i])
+	}
+
+	return rules, nil
+}
+
+func (f *appFeeImpl) ReadOneById(ctx context.Context, id string) (*entity.FeeRule, error) {
+	rule, err := f.db.Fee.ReadOneById(ctx, id)
+	if err != nil {
+		log.Println("Error app.Fee.ReadOneById.db.ReadOneById: ", err.Error())
+		return nil, err
+	}
+
+	return withStrings(rule), nil
+}
+
+// Deactivate stops charging the rule. Rules are kept so the fees already
+// charged can still be traced back to them.
+func (f *appFeeImpl) Deactivate(ctx context.Context, id string) (*entity.FeeRule, error) {
+	var rule *entity.FeeRule
+	err := f.db.UnitOfWork.Do(ctx, func(tx *database.Container) error {
+		var err error
+		rule, err = tx.Fee.ReadOneById(ctx, id)
+		if err != nil {
+			log.Println("Error app.Fee.Deactivate.db.ReadOneById: ", err.Error())
+			return err
+		}
+
+		err = tx.Fee.Deactivate(ctx, id)
+		if err != nil {
+			log.Println("Error app.Fee.Deactivate.db.Deactivate: ", err.Error())
+			return err
+		}
+
+		before := *withStrings(rule)
+		rule.Active = false
+
+		err = audit.Record(ctx, tx, entity.AUDIT_FEE_RULE_DEACTIVATED, entity.AUDIT_FEE_RULE, id, before, rule)
+		if err != nil {
+			log.Println("Error app.Fee.Deactivate.audit.Record: ", err.Error())
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return rule, nil
+}
+
+func withStrings(rule *entity.FeeRule) *entity.FeeRule {
+	rule.TypeString = rule.Type.String()
+	if rule.Kind != nil {
+		rule.KindString = rule.Kind.String()
+	}
+
+	if rule.AccountType != nil {
+		rule.AccountTypeString = rule.AccountType.String()
+	}
+
+	return rule
+}
@@ -0,0 +1,268 @@
+package fee
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/garoque/backend-code-challenge-snapfi/internal/database"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/mocks"
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/money"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+	"github.com/labstack/echo/v4"
+)
+
+func newRule() *entity.FeeRule {
+	kind := entity.TRANSFER
+	accountType := entity.BUSINESS
+	flatAmount := money.New(100)
+
+	return &entity.FeeRule{
+		ID:          "rule-id",
+		Name:        "business transfers",
+		Type:        entity.FEE_FLAT,
+		Kind:        &kind,
+		AccountType: &accountType,
+		FlatAmount:  &flatAmount,
+		Active:      true,
+	}
+}
+
+func withStringsSet(rule *entity.FeeRule) *entity.FeeRule {
+	rule.TypeString = "FLAT"
+	rule.KindString = "TRANSFER"
+	rule.AccountTypeString = "BUSINESS"
+	return rule
+}
+
+func TestCreate(t *testing.T) {
+	cases := map[string]struct {
+		InputRule      *entity.FeeRule
+		ExpectedResult *entity.FeeRule
+		ExpectedErr    error
+		PrepareMock    func(mockFeeDb *mocks.MockDabataseFeeInterface, mockAuditDb *mocks.MockDabataseAuditInterface)
+	}{
+		"deve retornar sucesso": {
+			InputRule:      newRule(),
+			ExpectedResult: withStringsSet(newRule()),
+			ExpectedErr:    nil,
+			PrepareMock: func(mockFeeDb *mocks.MockDabataseFeeInterface, mockAuditDb *mocks.MockDabataseAuditInterface) {
+				gomock.InOrder(
+					mockFeeDb.EXPECT().Create(gomock.Any(), newRule()).Times(1).Return(nil),
+					mockAuditDb.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).
+						Do(func(ctx context.Context, entry *entity.AuditEntry) {
+							if entry.Operation != entity.AUDIT_FEE_RULE_CREATED || entry.EntityId != "rule-id" || entry.Before != nil {
+								t.Errorf("unexpected audit entry %+v", entry)
+							}
+						}).Return(nil),
+				)
+			},
+		},
+		"deve retornar erro: regra invalida": {
+			InputRule:      &entity.FeeRule{ID: "rule-id", Type: entity.FEE_FLAT},
+			ExpectedResult: nil,
+			ExpectedErr:    echo.NewHTTPError(echo.ErrBadRequest.Code, entity.ErrFeeFlatAmount.Error()),
+			PrepareMock:    func(mockFeeDb *mocks.MockDabataseFeeInterface, mockAuditDb *mocks.MockDabataseAuditInterface) {},
+		},
+		"deve retornar erro": {
+			InputRule:      newRule(),
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrInternalServerError,
+			PrepareMock: func(mockFeeDb *mocks.MockDabataseFeeInterface, mockAuditDb *mocks.MockDabataseAuditInterface) {
+				mockFeeDb.EXPECT().Create(gomock.Any(), newRule()).Times(1).Return(echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			mockFeeDb := mocks.NewMockDabataseFeeInterface(ctrl)
+			mockAuditDb := mocks.NewMockDabataseAuditInterface(ctrl)
+			cs.PrepareMock(mockFeeDb, mockAuditDb)
+
+			mockUnitOfWork := mocks.NewMockUnitOfWorkInterface(ctrl)
+			container := &database.Container{Fee: mockFeeDb, Audit: mockAuditDb, UnitOfWork: mockUnitOfWork}
+			mockUnitOfWork.EXPECT().Do(gomock.Any(), gomock.Any()).AnyTimes().
+				DoAndReturn(func(ctx context.Context, fn func(tx *database.Container) error) error {
+					return fn(container)
+				})
+
+			app := NewAppFee(container)
+
+			rule, err := app.Create(ctx, cs.InputRule)
+			if diff := cmp.Diff(rule, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestReadAll(t *testing.T) {
+	cases := map[string]struct {
+		ExpectedResult []entity.FeeRule
+		ExpectedErr    error
+		PrepareMock    func(mockFeeDb *mocks.MockDabataseFeeInterface)
+	}{
+		"deve retornar sucesso": {
+			ExpectedResult: []entity.FeeRule{*withStringsSet(newRule()), {ID: "any-rule-id", Type: entity.FEE_PERCENTAGE, TypeString: "PERCENTAGE"}},
+			ExpectedErr:    nil,
+			PrepareMock: func(mockFeeDb *mocks.MockDabataseFeeInterface) {
+				mockFeeDb.EXPECT().ReadAll(gomock.Any()).Times(1).Return([]entity.FeeRule{*newRule(), {ID: "any-rule-id", Type: entity.FEE_PERCENTAGE}}, nil)
+			},
+		},
+		"deve retornar erro": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrInternalServerError,
+			PrepareMock: func(mockFeeDb *mocks.MockDabataseFeeInterface) {
+				mockFeeDb.EXPECT().ReadAll(gomock.Any()).Times(1).Return(nil, echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			mockFeeDb := mocks.NewMockDabataseFeeInterface(ctrl)
+			cs.PrepareMock(mockFeeDb)
+
+			app := NewAppFee(&database.Container{Fee: mockFeeDb})
+
+			rules, err := app.ReadAll(ctx)
+			if diff := cmp.Diff(rules, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestReadOneById(t *testing.T) {
+	cases := map[string]struct {
+		ExpectedResult *entity.FeeRule
+		ExpectedErr    error
+		PrepareMock    func(mockFeeDb *mocks.MockDabataseFeeInterface)
+	}{
+		"deve retornar sucesso": {
+			ExpectedResult: withStringsSet(newRule()),
+			ExpectedErr:    nil,
+			PrepareMock: func(mockFeeDb *mocks.MockDabataseFeeInterface) {
+				mockFeeDb.EXPECT().ReadOneById(gomock.Any(), "rule-id").Times(1).Return(newRule(), nil)
+			},
+		},
+		"deve retornar erro": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrNotFound,
+			PrepareMock: func(mockFeeDb *mocks.MockDabataseFeeInterface) {
+				mockFeeDb.EXPECT().ReadOneById(gomock.Any(), "rule-id").Times(1).Return(nil, echo.ErrNotFound)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			mockFeeDb := mocks.NewMockDabataseFeeInterface(ctrl)
+			cs.PrepareMock(mockFeeDb)
+
+			app := NewAppFee(&database.Container{Fee: mockFeeDb})
+
+			rule, err := app.ReadOneById(ctx, "rule-id")
+			if diff := cmp.Diff(rule, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestDeactivate(t *testing.T) {
+	deactivated := withStringsSet(newRule())
+	deactivated.Active = false
+
+	cases := map[string]struct {
+		ExpectedResult *entity.FeeRule
+		ExpectedErr    error
+		PrepareMock    func(mockFeeDb *mocks.MockDabataseFeeInterface, mockAuditDb *mocks.MockDabataseAuditInterface)
+	}{
+		"deve retornar sucesso": {
+			ExpectedResult: deactivated,
+			ExpectedErr:    nil,
+			PrepareMock: func(mockFeeDb *mocks.MockDabataseFeeInterface, mockAuditDb *mocks.MockDabataseAuditInterface) {
+				gomock.InOrder(
+					mockFeeDb.EXPECT().ReadOneById(gomock.Any(), "rule-id").Times(1).Return(newRule(), nil),
+					mockFeeDb.EXPECT().Deactivate(gomock.Any(), "rule-id").Times(1).Return(nil),
+					mockAuditDb.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).
+						Do(func(ctx context.Context, entry *entity.AuditEntry) {
+							var before, after entity.FeeRule
+							json.Unmarshal(*entry.Before, &before)
+							json.Unmarshal(*entry.After, &after)
+							if entry.Operation != entity.AUDIT_FEE_RULE_DEACTIVATED || !before.Active || after.Active {
+								t.Errorf("unexpected audit entry %+v", entry)
+							}
+						}).Return(nil),
+				)
+			},
+		},
+		"deve retornar erro: regra nao encontrada": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrNotFound,
+			PrepareMock: func(mockFeeDb *mocks.MockDabataseFeeInterface, mockAuditDb *mocks.MockDabataseAuditInterface) {
+				mockFeeDb.EXPECT().ReadOneById(gomock.Any(), "rule-id").Times(1).Return(nil, echo.ErrNotFound)
+			},
+		},
+		"deve retornar erro": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrInternalServerError,
+			PrepareMock: func(mockFeeDb *mocks.MockDabataseFeeInterface, mockAuditDb *mocks.MockDabataseAuditInterface) {
+				gomock.InOrder(
+					mockFeeDb.EXPECT().ReadOneById(gomock.Any(), "rule-id").Times(1).Return(newRule(), nil),
+					mockFeeDb.EXPECT().Deactivate(gomock.Any(), "rule-id").Times(1).Return(echo.ErrInternalServerError),
+				)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			mockFeeDb := mocks.NewMockDabataseFeeInterface(ctrl)
+			mockAuditDb := mocks.NewMockDabataseAuditInterface(ctrl)
+			cs.PrepareMock(mockFeeDb, mockAuditDb)
+
+			mockUnitOfWork := mocks.NewMockUnitOfWorkInterface(ctrl)
+			container := &database.Container{Fee: mockFeeDb, Audit: mockAuditDb, UnitOfWork: mockUnitOfWork}
+			mockUnitOfWork.EXPECT().Do(gomock.Any(), gomock.Any()).AnyTimes().
+				DoAndReturn(func(ctx context.Context, fn func(tx *database.Container) error) error {
+					return fn(container)
+				})
+
+			app := NewAppFee(container)
+
+			rule, err := app.Deactivate(ctx, "rule-id")
+			if diff := cmp.Diff(rule, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package idempotency
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/garoque/backend-code-challenge-snapfi/internal/database"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// DefaultTTL is how long a stored response can be replayed when no other
+	// window is configured.
+	DefaultTTL = 24 * time.Hour
+	// DefaultLease is how long a request may hold its key before a retry can
+	// take it over, when no other lease is configured. It must outlast the
+	// slowest request, or a retry may run alongside it.
+	DefaultLease = time.Minute
+)
+
+// errInProgress is returned while another request holds the key.
+var errInProgress = echo.NewHTTPError(echo.ErrConflict.Code, "A request with this Idempotency-Key is still being processed")
+
+type AppIdempotencyInterface interface {
+	Begin(ctx context.Context, scope, key, requestHash string) (*entity.IdempotencyKey, error)
+	Complete(ctx context.Context, scope, key string, statusCode int, body []byte) error
+	Release(ctx context.Context, scope, key string) error
+}
+
+type appIdempotencyImpl struct {
+	db    *database.Container
+	ttl   time.Duration
+	lease time.Duration
+}
+
+func NewAppIdempotency(db *database.Container, ttl, lease time.Duration) AppIdempotencyInterface {
+	return &appIdempotencyImpl{db, ttl, lease}
+}
+
+// Begin reserves the key for a new request. When the key was already used for
+// the same request body, the stored key is returned so its response can be
+// replayed; a nil key means the caller must process the request and then call
+// Complete or Release. A key still in progress past its lease was left behind
+// by a server that died mid-request, and is taken over by the retry.
+func (i *appIdempotencyImpl) Begin(ctx context.Context, scope, key, requestHash string) (*entity.IdempotencyKey, error) {
+	stored, err := i.db.Idempotency.ReadOne(ctx, scope, key)
+	if err != nil && err != echo.ErrNotFound {
+		log.Println("Error app.Idempotency.Begin.db.ReadOne: ", err.Error())
+		return nil, err
+	}
+
+	now := time.Now()
+	if stored != nil && stored.IsExpired(now) {
+		if err := i.db.Idempotency.Delete(ctx, scope, key); err != nil {
+			log.Println("Error app.Idempotency.Begin.db.Delete: ", err.Error())
+			return nil, err
+		}
+		stored = nil
+	}
+
+	if stored != nil {
+		if stored.RequestHash != requestHash {
+			return nil, echo.NewHTTPError(echo.ErrConflict.Code, "Idempotency-Key was already used with a different request")
+		}
+
+		if stored.IsAbandoned(now, i.lease) {
+			return nil, i.takeOver(ctx, scope, key, requestHash)
+		}
+
+		if !stored.IsCompleted() {
+			return nil, errInProgress
+		}
+
+		return stored, nil
+	}
+
+	err = i.db.Idempotency.Create(ctx, entity.NewIdempotencyKey(scope, key, requestHash, i.ttl))
+	if err == echo.ErrConflict {
+		return nil, errInProgress
+	}
+
+	if err != nil {
+		log.Println("Error app.Idempotency.Begin.db.Create: ", err.Error())
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// takeOver reserves the abandoned key again for the retry. The key is locked
+// while it is checked and replaced, so when several retries race for it only
+// the first one takes it over.
+func (i *appIdempotencyImpl) takeOver(ctx context.Context, scope, key, requestHash string) error {
+	return i.db.UnitOfWork.Do(ctx, func(tx *database.Container) error {
+		stored, err := tx.Idempotency.ReadOneForUpdate(ctx, scope, key)
+		if err != nil && err != echo.ErrNotFound {
+			log.Println("Error app.Idempotency.takeOver.db.ReadOneForUpdate: ", err.Error())
+			return err
+		}
+
+		if stored != nil {
+			if !stored.IsAbandoned(time.Now(), i.lease) {
+				return errInProgress
+			}
+
+			if err := tx.Idempotency.Delete(ctx, scope, key); err != nil {
+				log.Println("Error app.Idempotency.takeOver.db.Delete: ", err.Error())
+				return err
+			}
+		}
+
+		err = tx.Idempotency.Create(ctx, entity.NewIdempotencyKey(scope, key, requestHash, i.ttl))
+		if err == echo.ErrConflict {
+			return errInProgress
+		}
+
+		if err != nil {
+			log.Println("Error app.Idempotency.takeOver.db.Create: ", err.Error())
+			return err
+		}
+
+		return nil
+	})
+}
+
+func (i *appIdempotencyImpl) Complete(ctx context.Context, scope, key string, statusCode int, body []byte) error {
+	err := i.db.Idempotency.UpdateResponse(ctx, scope, key, statusCode, body)
+	if err != nil {
+		log.Println("Error app.Idempotency.Complete.db.UpdateResponse: ", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// Release forgets the key so the client can retry a request that could not be
+// processed, e.g. after an internal error.
+func (i *appIdempotencyImpl) Release(ctx context.Context, scope, key string) error {
+	err := i.db.Idempotency.Delete(ctx, scope, key)
+	if err != nil {
+		log.Println("Error app.Idempotency.Release.db.Delete: ", err.Error())
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,249 @@
+package idempotency
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/garoque/backend-code-challenge-snapfi/internal/database"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+	"github.com/labstack/echo/v4"
+)
+
+func TestBegin(t *testing.T) {
+	scope := "POST /v1/transaction"
+	key := "key"
+	hash := "hash"
+
+	statusCode := 201
+	completedKey := &entity.IdempotencyKey{
+		Scope:        scope,
+		Key:          key,
+		RequestHash:  hash,
+		StatusCode:   &statusCode,
+		ResponseBody: []byte(`{"data":{}}`),
+		ExpiresAt:    time.Now().Add(time.Hour),
+	}
+
+	pendingKey := &entity.IdempotencyKey{
+		Scope:       scope,
+		Key:         key,
+		RequestHash: hash,
+		CreatedAt:   time.Now(),
+		ExpiresAt:   time.Now().Add(time.Hour),
+	}
+
+	abandonedKey := &entity.IdempotencyKey{
+		Scope:       scope,
+		Key:         key,
+		RequestHash: hash,
+		CreatedAt:   time.Now().Add(-2 * time.Minute),
+		ExpiresAt:   time.Now().Add(time.Hour),
+	}
+
+	expiredKey := &entity.IdempotencyKey{
+		Scope:        scope,
+		Key:          key,
+		RequestHash:  "other-hash",
+		StatusCode:   &statusCode,
+		ResponseBody: []byte(`{"data":{}}`),
+		ExpiresAt:    time.Now().Add(-time.Minute),
+	}
+
+	cases := map[string]struct {
+		ExpectedResult *entity.IdempotencyKey
+		ExpectedErr    error
+		PrepareMock    func(mockIdempotencyDb *mocks.MockDabataseIdempotencyInterface)
+	}{
+		"deve retornar sucesso: nova chave": {
+			ExpectedResult: nil,
+			ExpectedErr:    nil,
+			PrepareMock: func(mockIdempotencyDb *mocks.MockDabataseIdempotencyInterface) {
+				mockIdempotencyDb.EXPECT().ReadOne(gomock.Any(), scope, key).Times(1).Return(nil, echo.ErrNotFound)
+				mockIdempotencyDb.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil)
+			},
+		},
+		"deve retornar sucesso: resposta armazenada": {
+			ExpectedResult: completedKey,
+			ExpectedErr:    nil,
+			PrepareMock: func(mockIdempotencyDb *mocks.MockDabataseIdempotencyInterface) {
+				mockIdempotencyDb.EXPECT().ReadOne(gomock.Any(), scope, key).Times(1).Return(completedKey, nil)
+			},
+		},
+		"deve retornar sucesso: chave expirada": {
+			ExpectedResult: nil,
+			ExpectedErr:    nil,
+			PrepareMock: func(mockIdempotencyDb *mocks.MockDabataseIdempotencyInterface) {
+				mockIdempotencyDb.EXPECT().ReadOne(gomock.Any(), scope, key).Times(1).Return(expiredKey, nil)
+				mockIdempotencyDb.EXPECT().Delete(gomock.Any(), scope, key).Times(1).Return(nil)
+				mockIdempotencyDb.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil)
+			},
+		},
+		"deve retornar sucesso: chave abandonada": {
+			ExpectedResult: nil,
+			ExpectedErr:    nil,
+			PrepareMock: func(mockIdempotencyDb *mocks.MockDabataseIdempotencyInterface) {
+				gomock.InOrder(
+					mockIdempotencyDb.EXPECT().ReadOne(gomock.Any(), scope, key).Times(1).Return(abandonedKey, nil),
+					mockIdempotencyDb.EXPECT().ReadOneForUpdate(gomock.Any(), scope, key).Times(1).Return(abandonedKey, nil),
+					mockIdempotencyDb.EXPECT().Delete(gomock.Any(), scope, key).Times(1).Return(nil),
+					mockIdempotencyDb.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+				)
+			},
+		},
+		"deve retornar erro: chave abandonada já retomada": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.NewHTTPError(echo.ErrConflict.Code, "A request with this Idempotency-Key is still being processed"),
+			PrepareMock: func(mockIdempotencyDb *mocks.MockDabataseIdempotencyInterface) {
+				mockIdempotencyDb.EXPECT().ReadOne(gomock.Any(), scope, key).Times(1).Return(abandonedKey, nil)
+				mockIdempotencyDb.EXPECT().ReadOneForUpdate(gomock.Any(), scope, key).Times(1).Return(pendingKey, nil)
+			},
+		},
+		"deve retornar erro: ao retomar chave abandonada": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrInternalServerError,
+			PrepareMock: func(mockIdempotencyDb *mocks.MockDabataseIdempotencyInterface) {
+				mockIdempotencyDb.EXPECT().ReadOne(gomock.Any(), scope, key).Times(1).Return(abandonedKey, nil)
+				mockIdempotencyDb.EXPECT().ReadOneForUpdate(gomock.Any(), scope, key).Times(1).Return(nil, echo.ErrInternalServerError)
+			},
+		},
+		"deve retornar erro: corpo diferente": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.NewHTTPError(echo.ErrConflict.Code, "Idempotency-Key was already used with a different request"),
+			PrepareMock: func(mockIdempotencyDb *mocks.MockDabataseIdempotencyInterface) {
+				mockIdempotencyDb.EXPECT().ReadOne(gomock.Any(), scope, key).Times(1).Return(&entity.IdempotencyKey{
+					Scope:       scope,
+					Key:         key,
+					RequestHash: "other-hash",
+					ExpiresAt:   time.Now().Add(time.Hour),
+				}, nil)
+			},
+		},
+		"deve retornar erro: requisição em andamento": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.NewHTTPError(echo.ErrConflict.Code, "A request with this Idempotency-Key is still being processed"),
+			PrepareMock: func(mockIdempotencyDb *mocks.MockDabataseIdempotencyInterface) {
+				mockIdempotencyDb.EXPECT().ReadOne(gomock.Any(), scope, key).Times(1).Return(pendingKey, nil)
+			},
+		},
+		"deve retornar erro: chave criada em paralelo": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.NewHTTPError(echo.ErrConflict.Code, "A request with this Idempotency-Key is still being processed"),
+			PrepareMock: func(mockIdempotencyDb *mocks.MockDabataseIdempotencyInterface) {
+				mockIdempotencyDb.EXPECT().ReadOne(gomock.Any(), scope, key).Times(1).Return(nil, echo.ErrNotFound)
+				mockIdempotencyDb.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(echo.ErrConflict)
+			},
+		},
+		"deve retornar erro: ao ler a chave": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrInternalServerError,
+			PrepareMock: func(mockIdempotencyDb *mocks.MockDabataseIdempotencyInterface) {
+				mockIdempotencyDb.EXPECT().ReadOne(gomock.Any(), scope, key).Times(1).Return(nil, echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			mockIdempotencyDb := mocks.NewMockDabataseIdempotencyInterface(ctrl)
+			cs.PrepareMock(mockIdempotencyDb)
+
+			mockUnitOfWork := mocks.NewMockUnitOfWorkInterface(ctrl)
+			container := &database.Container{Idempotency: mockIdempotencyDb, UnitOfWork: mockUnitOfWork}
+			mockUnitOfWork.EXPECT().Do(gomock.Any(), gomock.Any()).AnyTimes().
+				DoAndReturn(func(ctx context.Context, fn func(tx *database.Container) error) error {
+					return fn(container)
+				})
+
+			app := NewAppIdempotency(container, time.Hour, time.Minute)
+
+			result, err := app.Begin(ctx, scope, key, hash)
+			if diff := cmp.Diff(result, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestComplete(t *testing.T) {
+	body := []byte(`{"data":{}}`)
+
+	cases := map[string]struct {
+		ExpectedErr error
+		PrepareMock func(mockIdempotencyDb *mocks.MockDabataseIdempotencyInterface)
+	}{
+		"deve retornar sucesso": {
+			ExpectedErr: nil,
+			PrepareMock: func(mockIdempotencyDb *mocks.MockDabataseIdempotencyInterface) {
+				mockIdempotencyDb.EXPECT().UpdateResponse(gomock.Any(), "scope", "key", 201, body).Times(1).Return(nil)
+			},
+		},
+		"deve retornar erro": {
+			ExpectedErr: echo.ErrInternalServerError,
+			PrepareMock: func(mockIdempotencyDb *mocks.MockDabataseIdempotencyInterface) {
+				mockIdempotencyDb.EXPECT().UpdateResponse(gomock.Any(), "scope", "key", 201, body).Times(1).Return(echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			mockIdempotencyDb := mocks.NewMockDabataseIdempotencyInterface(ctrl)
+			cs.PrepareMock(mockIdempotencyDb)
+
+			app := NewAppIdempotency(&database.Container{Idempotency: mockIdempotencyDb}, time.Hour, time.Minute)
+
+			err := app.Complete(ctx, "scope", "key", 201, body)
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestRelease(t *testing.T) {
+	cases := map[string]struct {
+		ExpectedErr error
+		PrepareMock func(mockIdempotencyDb *mocks.MockDabataseIdempotencyInterface)
+	}{
+		"deve retornar sucesso": {
+			ExpectedErr: nil,
+			PrepareMock: func(mockIdempotencyDb *mocks.MockDabataseIdempotencyInterface) {
+				mockIdempotencyDb.EXPECT().Delete(gomock.Any(), "scope", "key").Times(1).Return(nil)
+			},
+		},
+		"deve retornar erro": {
+			ExpectedErr: echo.ErrInternalServerError,
+			PrepareMock: func(mockIdempotencyDb *mocks.MockDabataseIdempotencyInterface) {
+				mockIdempotencyDb.EXPECT().Delete(gomock.Any(), "scope", "key").Times(1).Return(echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			mockIdempotencyDb := mocks.NewMockDabataseIdempotencyInterface(ctrl)
+			cs.PrepareMock(mockIdempotencyDb)
+
+			app := NewAppIdempotency(&database.Container{Idempotency: mockIdempotencyDb}, time.Hour, time.Minute)
+
+			err := app.Release(ctx, "scope", "key")
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,242 @@
+package ledger
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/garoque/backend-code-challenge-snapfi/internal/api/dto"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/app/audit"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/database"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	"github.com/labstack/echo/v4"
+)
+
+type AppLedgerInterface interface {
+	Check(ctx context.Context) (*entity.LedgerCheck, error)
+	Reconcile(ctx context.Context) (*entity.Reconciliation, error)
+	ReadReconciliations(ctx context.Context) ([]entity.Reconciliation, error)
+	ReadReconciliation(ctx context.Context, id string) (*entity.Reconciliation, error)
+	ApproveCorrections(ctx context.Context, id string, approval dto.ApproveCorrections) (*entity.Reconciliation, error)
+}
+
+type appLedgerImpl struct {
+	db *database.Container
+}
+
+func NewAppLedger(db *database.Container) AppLedgerInterface {
+	return &appLedgerImpl{db}
+}
+
+// Check verifies that all postings sum to zero and that accounts.balance
+// agrees with the postings of every account.
+func (l *appLedgerImpl) Check(ctx context.Context) (*entity.LedgerCheck, error) {
+	total, err := l.db.Ledger.ReadTotal(ctx)
+	if err != nil {
+		log.Println("Error app.Ledger.Check.db.ReadTotal: ", err.Error())
+		return nil, err
+	}
+
+	unbalanced, err := l.db.Ledger.ReadUnbalancedTransactions(ctx)
+	if err != nil {
+		log.Println("Error app.Ledger.Check.db.ReadUnbalancedTransactions: ", err.Error())
+		return nil, err
+	}
+
+	mismatches, err := l.db.Ledger.ReadBalanceMismatches(ctx)
+	if err != nil {
+		log.Println("Error app.Ledger.Check.db.ReadBalanceMismatches: ", err.Error())
+		return nil, err
+	}
+
+	return &entity.LedgerCheck{
+		Total:                  total,
+		Balanced:               total.IsZero() && len(unbalanced) == 0 && len(mismatches) == 0,
+		UnbalancedTransactions: unbalanced,
+		BalanceMismatches:      mismatches,
+	}, nil
+}
+
+// Reconcile recomputes the balance of every account from its postings, the
+// record of its booked transactions, and stores a report of the accounts whose
+// balance drifted from it.
+// Nothing is corrected until an operator approves it.
+func (l *appLedgerImpl) Reconcile(ctx context.Context) (*entity.Reconciliation, error) {
+	drifts, err := l.db.Reconciliation.ReadDrifts(ctx)
+	if err != nil {
+		log.Println("Error app.Ledger.Reconcile.db.ReadDrifts: ", err.Error())
+		return nil, err
+	}
+
+	reconciliation := entity.NewReconciliation(drifts)
+	err = l.db.UnitOfWork.Do(ctx, func(tx *database.Container) error {
+		err := tx.Reconciliation.Create(ctx, reconciliation)
+		if err != nil {
+			return err
+		}
+
+		return audit.Record(ctx, tx, entity.AUDIT_RECONCILIATION_CREATED, entity.AUDIT_RECONCILIATION, reconciliation.ID, nil, reconciliation)
+	})
+	if err != nil {
+		log.Println("Error app.Ledger.Reconcile.db.Create: ", err.Error())
+		return nil, err
+	}
+
+	if reconciliation.DriftedUsers > 0 {
+		log.Printf("Reconciliation %s found %d drifted balances", reconciliation.ID, reconciliation.DriftedUsers)
+	}
+
+	return reconciliation, nil
+}
+
+func (l *appLedgerImpl) ReadReconciliations(ctx context.Context) ([]entity.Reconciliation, error) {
+	reconciliations, err := l.db.Reconciliation.ReadAll(ctx)
+	if err != nil {
+		log.Println("Error app.Ledger.ReadReconciliations.db.ReadAll: ", err.Error())
+		return nil, err
+	}
+
+	return reconciliations, nil
+}
+
+func (l *appLedgerImpl) ReadReconciliation(ctx context.Context, id string) (*entity.Reconciliation, error) {
+	return readReconciliation(ctx, l.db, id)
+}
+
+func readReconciliation(ctx context.Context, db *database.Container, id string) (*entity.Reconciliation, error) {
+	reconciliation, err := db.Reconciliation.ReadOneById(ctx, id)
+	if err != nil {
+		log.Println("Error app.Ledger.readReconciliation.db.ReadOneById: ", err.Error())
+		return nil, err
+	}
+
+	reconciliation.Differences, err = db.Reconciliation.ReadDifferences(ctx, id)
+	if err != nil {
+		log.Println("Error app.Ledger.readReconciliation.db.ReadDifferences: ", err.Error())
+		return nil, err
+	}
+
+	return reconciliation, nil
+}
+
+// ApproveCorrections sets the drifted balances of the reconciliation that
+// weren't corrected yet to the balances recomputed from the postings, all
+// of them or only those of the given users, and records who approved it.
+func (l *appLedgerImpl) ApproveCorrections(ctx context.Context, id string, approval dto.ApproveCorrections) (*entity.Reconciliation, error) {
+	var reconciliation *entity.Reconciliation
+	err := l.db.UnitOfWork.Do(ctx, func(tx *database.Container) error {
+		var err error
+		reconciliation, err = readReconciliation(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		pending, err := pendingDifferences(reconciliation, approval.UserIds)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		for _, difference := range pending {
+			err = correct(ctx, tx, difference, approval.ApprovedBy, now)
+			if err != nil {
+				return err
+			}
+		}
+
+		err = audit.Record(ctx, tx, entity.AUDIT_RECONCILIATION_APPROVED, entity.AUDIT_RECONCILIATION, id, nil, pending)
+		if err != nil {
+			log.Println("Error app.Ledger.ApproveCorrections.audit.Record: ", err.Error())
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return reconciliation, nil
+}
+
+// pendingDifferences picks the differences of the reconciliation not yet
+// corrected, only those of userIds when any is given.
+func pendingDifferences(reconciliation *entity.Reconciliation, userIds []string) ([]*entity.ReconciliationDifference, error) {
+	pending := make([]*entity.ReconciliationDifference, 0, len(reconciliation.Differences))
+	byUser := make(map[string]*entity.ReconciliationDifference, len(reconciliation.Differences))
+	for i := range reconciliation.Differences {
+		difference := &reconciliation.Differences[i]
+		if !difference.IsCorrected() {
+			pending = append(pending, difference)
+			byUser[difference.UserId] = difference
+		}
+	}
+
+	if len(userIds) == 0 {
+		return pending, nil
+	}
+
+	selected := make([]*entity.ReconciliationDifference, 0, len(userIds))
+	for _, userId := range userIds {
+		difference, ok := byUser[userId]
+		if !ok {
+			return nil, echo.NewHTTPError(echo.ErrBadRequest.Code, fmt.Sprintf("The user %s has no pending difference in the reconciliation", userId))
+		}
+
+		selected = append(selected, difference)
+		delete(byUser, userId)
+	}
+
+	return selected, nil
+}
+
+// correct sets the balance of the account to the one recomputed from its
+// postings. The drift is in the stored balance, not in the postings, so no
+// money moves and nothing is posted; the balance is brought back to the ledger
+// and the ledger check agrees with it again. Transactions booked since the
+// reconciliation move both by the same amount, so it refuses only when the
+// drift itself changed.
+func correct(ctx context.Context, tx *database.Container, difference *entity.ReconciliationDifference, approvedBy string, now time.Time) error {
+	account, err := tx.Account.ReadOneByIdForUpdate(ctx, difference.UserId)
+	if err != nil {
+		log.Println("Error app.Ledger.correct.db.ReadOneByIdForUpdate: ", err.Error())
+		return err
+	}
+
+	expected, err := tx.Reconciliation.ReadExpectedBalance(ctx, difference.UserId)
+	if err != nil {
+		log.Println("Error app.Ledger.correct.db.ReadExpectedBalance: ", err.Error())
+		return err
+	}
+
+	if expected.Amount-account.Balance.Amount != difference.Difference.Amount {
+		return echo.NewHTTPError(http.StatusConflict, fmt.Sprintf("The balance of the account %s changed its drift since the reconciliation, run a new one", difference.UserId))
+	}
+
+	err = tx.Account.UpdateBalance(ctx, difference.UserId, expected)
+	if err != nil {
+		log.Println("Error app.Ledger.correct.db.UpdateBalance: ", err.Error())
+		return err
+	}
+
+	before := entity.AuditBalance{Balance: account.Balance, HeldBalance: account.HeldBalance}
+	after := entity.AuditBalance{Balance: expected, HeldBalance: account.HeldBalance}
+	err = audit.Record(ctx, tx, entity.AUDIT_ACCOUNT_BALANCE_UPDATED, entity.AUDIT_ACCOUNT, difference.UserId, before, after)
+	if err != nil {
+		log.Println("Error app.Ledger.correct.audit.Record: ", err.Error())
+		return err
+	}
+
+	err = tx.Reconciliation.UpdateCorrection(ctx, difference.ID, approvedBy, now)
+	if err != nil {
+		log.Println("Error app.Ledger.correct.db.UpdateCorrection: ", err.Error())
+		return err
+	}
+
+	difference.ApprovedBy = &approvedBy
+	difference.CorrectedAt = &now
+
+	return nil
+}
@@ -0,0 +1,431 @@
+package ledger
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/garoque/backend-code-challenge-snapfi/internal/api/dto"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/database"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/mocks"
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/money"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheck(t *testing.T) {
+	mismatches := []entity.BalanceMismatch{{
+		AccountId:       "user-id",
+		Balance:         money.New(10010),
+		PostingsBalance: money.New(10000),
+	}}
+
+	cases := map[string]struct {
+		ExpectedResult *entity.LedgerCheck
+		ExpectedErr    error
+		PrepareMock    func(mockLedgerDb *mocks.MockDabataseLedgerInterface)
+	}{
+		"deve retornar sucesso: ledger balanceado": {
+			ExpectedResult: &entity.LedgerCheck{
+				Total:                  money.New(0),
+				Balanced:               true,
+				UnbalancedTransactions: []string{},
+				BalanceMismatches:      []entity.BalanceMismatch{},
+			},
+			ExpectedErr: nil,
+			PrepareMock: func(mockLedgerDb *mocks.MockDabataseLedgerInterface) {
+				mockLedgerDb.EXPECT().ReadTotal(gomock.Any()).Times(1).Return(money.New(0), nil)
+				mockLedgerDb.EXPECT().ReadUnbalancedTransactions(gomock.Any()).Times(1).Return([]string{}, nil)
+				mockLedgerDb.EXPECT().ReadBalanceMismatches(gomock.Any()).Times(1).Return([]entity.BalanceMismatch{}, nil)
+			},
+		},
+		"deve retornar sucesso: saldo divergente": {
+			ExpectedResult: &entity.LedgerCheck{
+				Total:                  money.New(0),
+				Balanced:               false,
+				UnbalancedTransactions: []string{},
+				BalanceMismatches:      mismatches,
+			},
+			ExpectedErr: nil,
+			PrepareMock: func(mockLedgerDb *mocks.MockDabataseLedgerInterface) {
+				mockLedgerDb.EXPECT().ReadTotal(gomock.Any()).Times(1).Return(money.New(0), nil)
+				mockLedgerDb.EXPECT().ReadUnbalancedTransactions(gomock.Any()).Times(1).Return([]string{}, nil)
+				mockLedgerDb.EXPECT().ReadBalanceMismatches(gomock.Any()).Times(1).Return(mismatches, nil)
+			},
+		},
+		"deve retornar sucesso: total diferente de zero": {
+			ExpectedResult: &entity.LedgerCheck{
+				Total:                  money.New(10),
+				Balanced:               false,
+				UnbalancedTransactions: []string{"transaction-id"},
+				BalanceMismatches:      []entity.BalanceMismatch{},
+			},
+			ExpectedErr: nil,
+			PrepareMock: func(mockLedgerDb *mocks.MockDabataseLedgerInterface) {
+				mockLedgerDb.EXPECT().ReadTotal(gomock.Any()).Times(1).Return(money.New(10), nil)
+				mockLedgerDb.EXPECT().ReadUnbalancedTransactions(gomock.Any()).Times(1).Return([]string{"transaction-id"}, nil)
+				mockLedgerDb.EXPECT().ReadBalanceMismatches(gomock.Any()).Times(1).Return([]entity.BalanceMismatch{}, nil)
+			},
+		},
+		"deve retornar erro: ao ler o total": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrInternalServerError,
+			PrepareMock: func(mockLedgerDb *mocks.MockDabataseLedgerInterface) {
+				mockLedgerDb.EXPECT().ReadTotal(gomock.Any()).Times(1).Return(money.Money{}, echo.ErrInternalServerError)
+			},
+		},
+		"deve retornar erro: ao ler transactions desbalanceadas": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrInternalServerError,
+			PrepareMock: func(mockLedgerDb *mocks.MockDabataseLedgerInterface) {
+				mockLedgerDb.EXPECT().ReadTotal(gomock.Any()).Times(1).Return(money.New(0), nil)
+				mockLedgerDb.EXPECT().ReadUnbalancedTransactions(gomock.Any()).Times(1).Return(nil, echo.ErrInternalServerError)
+			},
+		},
+		"deve retornar erro: ao ler saldos divergentes": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrInternalServerError,
+			PrepareMock: func(mockLedgerDb *mocks.MockDabataseLedgerInterface) {
+				mockLedgerDb.EXPECT().ReadTotal(gomock.Any()).Times(1).Return(money.New(0), nil)
+				mockLedgerDb.EXPECT().ReadUnbalancedTransactions(gomock.Any()).Times(1).Return([]string{}, nil)
+				mockLedgerDb.EXPECT().ReadBalanceMismatches(gomock.Any()).Times(1).Return(nil, echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			mockLedgerDb := mocks.NewMockDabataseLedgerInterface(ctrl)
+			cs.PrepareMock(mockLedgerDb)
+
+			app := NewAppLedger(&database.Container{Ledger: mockLedgerDb})
+
+			result, err := app.Check(ctx)
+			if diff := cmp.Diff(result, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+type databaseMocks struct {
+	Account        *mocks.MockDabataseAccountInterface
+	Transaction    *mocks.MockDabataseTransactionInterface
+	Ledger         *mocks.MockDabataseLedgerInterface
+	StateHistory   *mocks.MockDabataseStateHistoryInterface
+	Outbox         *mocks.MockDabataseOutboxInterface
+	Reconciliation *mocks.MockDabataseReconciliationInterface
+	Audit          *mocks.MockDabataseAuditInterface
+}
+
+func newDatabaseContainer(ctrl *gomock.Controller) (*database.Container, *databaseMocks) {
+	db := &databaseMocks{
+		Account:        mocks.NewMockDabataseAccountInterface(ctrl),
+		Transaction:    mocks.NewMockDabataseTransactionInterface(ctrl),
+		Ledger:         mocks.NewMockDabataseLedgerInterface(ctrl),
+		StateHistory:   mocks.NewMockDabataseStateHistoryInterface(ctrl),
+		Outbox:         mocks.NewMockDabataseOutboxInterface(ctrl),
+		Reconciliation: mocks.NewMockDabataseReconciliationInterface(ctrl),
+		Audit:          mocks.NewMockDabataseAuditInterface(ctrl),
+	}
+
+	mockUnitOfWork := mocks.NewMockUnitOfWorkInterface(ctrl)
+
+	container := &database.Container{
+		Account:        db.Account,
+		Transaction:    db.Transaction,
+		Ledger:         db.Ledger,
+		StateHistory:   db.StateHistory,
+		Outbox:         db.Outbox,
+		Reconciliation: db.Reconciliation,
+		Audit:          db.Audit,
+		UnitOfWork:     mockUnitOfWork,
+	}
+	mockUnitOfWork.EXPECT().Do(gomock.Any(), gomock.Any()).AnyTimes().
+		DoAndReturn(func(ctx context.Context, fn func(tx *database.Container) error) error {
+			return fn(container)
+		})
+
+	return container, db
+}
+
+func TestReconcile(t *testing.T) {
+	drifts := []entity.ReconciliationDifference{
+		{UserId: "user-id", Balance: money.New(10010), ExpectedBalance: money.New(9010)},
+	}
+
+	cases := map[string]struct {
+		ExpectedDifferences []entity.ReconciliationDifference
+		ExpectedErr         error
+		PrepareMock         func(db *databaseMocks)
+	}{
+		"deve retornar sucesso": {
+			ExpectedDifferences: []entity.ReconciliationDifference{
+				{UserId: "user-id", Balance: money.New(10010), ExpectedBalance: money.New(9010), Difference: money.New(-1000)},
+			},
+			ExpectedErr: nil,
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.Reconciliation.EXPECT().ReadDrifts(gomock.Any()).Times(1).Return(drifts, nil),
+					db.Reconciliation.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Audit.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).
+						Do(func(ctx context.Context, entry *entity.AuditEntry) {
+							if entry.Operation != entity.AUDIT_RECONCILIATION_CREATED {
+								t.Errorf("unexpected audit entry %+v", entry)
+							}
+						}).Return(nil),
+				)
+			},
+		},
+		"deve retornar erro: ao salvar o relatorio": {
+			ExpectedErr: echo.ErrInternalServerError,
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.Reconciliation.EXPECT().ReadDrifts(gomock.Any()).Times(1).Return([]entity.ReconciliationDifference{}, nil),
+					db.Reconciliation.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(echo.ErrInternalServerError),
+				)
+			},
+		},
+		"deve retornar erro": {
+			ExpectedErr: echo.ErrInternalServerError,
+			PrepareMock: func(db *databaseMocks) {
+				db.Reconciliation.EXPECT().ReadDrifts(gomock.Any()).Times(1).Return(nil, echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			container, db := newDatabaseContainer(ctrl)
+			cs.PrepareMock(db)
+
+			app := NewAppLedger(container)
+
+			result, err := app.Reconcile(ctx)
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+
+			if err == nil {
+				differences := cmp.Diff(result.Differences, cs.ExpectedDifferences, cmpopts.IgnoreFields(entity.ReconciliationDifference{}, "ReconciliationId"))
+				if differences != "" {
+					t.Error(differences)
+				}
+			}
+		})
+	}
+}
+
+func TestApproveCorrections(t *testing.T) {
+	correctedAt := time.Date(2023, 5, 17, 10, 0, 0, 0, time.UTC)
+	approvedBy := "operator"
+	reconciliation := func() *entity.Reconciliation {
+		return &entity.Reconciliation{ID: "reconciliation-id", DriftedUsers: 2}
+	}
+	differences := func() []entity.ReconciliationDifference {
+		return []entity.ReconciliationDifference{
+			{ID: 1, ReconciliationId: "reconciliation-id", UserId: "user-id", Balance: money.New(10010), ExpectedBalance: money.New(9010), Difference: money.New(-1000)},
+			{ID: 2, ReconciliationId: "reconciliation-id", UserId: "other-user-id", Balance: money.New(0), ExpectedBalance: money.New(500), Difference: money.New(500), ApprovedBy: &approvedBy, CorrectedAt: &correctedAt},
+		}
+	}
+
+	cases := map[string]struct {
+		Input       dto.ApproveCorrections
+		ExpectedErr error
+		PrepareMock func(db *databaseMocks)
+	}{
+		"deve retornar sucesso": {
+			Input:       dto.ApproveCorrections{ApprovedBy: "operator"},
+			ExpectedErr: nil,
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.Reconciliation.EXPECT().ReadOneById(gomock.Any(), "reconciliation-id").Times(1).Return(reconciliation(), nil),
+					db.Reconciliation.EXPECT().ReadDifferences(gomock.Any(), "reconciliation-id").Times(1).Return(differences(), nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), "user-id").Times(1).Return(&entity.Account{ID: "user-id", Balance: money.New(12010), Status: entity.FROZEN}, nil),
+					db.Reconciliation.EXPECT().ReadExpectedBalance(gomock.Any(), "user-id").Times(1).Return(money.New(11010), nil),
+					db.Account.EXPECT().UpdateBalance(gomock.Any(), "user-id", money.New(11010)).Times(1).Return(nil),
+					db.Audit.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).
+						Do(func(ctx context.Context, entry *entity.AuditEntry) {
+							if entry.Operation != entity.AUDIT_ACCOUNT_BALANCE_UPDATED || string(*entry.Before) != `{"balance":"120.10","heldBalance":"0.00"}` || string(*entry.After) != `{"balance":"110.10","heldBalance":"0.00"}` {
+								t.Errorf("unexpected audit entry %+v", entry)
+							}
+						}).Return(nil),
+					db.Reconciliation.EXPECT().UpdateCorrection(gomock.Any(), int64(1), "operator", gomock.Any()).Times(1).Return(nil),
+					db.Audit.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).
+						Do(func(ctx context.Context, entry *entity.AuditEntry) {
+							if entry.Operation != entity.AUDIT_RECONCILIATION_APPROVED || entry.EntityId != "reconciliation-id" {
+								t.Errorf("unexpected audit entry %+v", entry)
+							}
+						}).Return(nil),
+				)
+			},
+		},
+		"deve retornar erro: usuario sem diferenca pendente": {
+			Input:       dto.ApproveCorrections{ApprovedBy: "operator", UserIds: []string{"other-user-id"}},
+			ExpectedErr: echo.NewHTTPError(echo.ErrBadRequest.Code, "The user other-user-id has no pending difference in the reconciliation"),
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.Reconciliation.EXPECT().ReadOneById(gomock.Any(), "reconciliation-id").Times(1).Return(reconciliation(), nil),
+					db.Reconciliation.EXPECT().ReadDifferences(gomock.Any(), "reconciliation-id").Times(1).Return(differences(), nil),
+				)
+			},
+		},
+		"deve retornar erro: divergencia mudou": {
+			Input:       dto.ApproveCorrections{ApprovedBy: "operator", UserIds: []string{"user-id"}},
+			ExpectedErr: echo.NewHTTPError(http.StatusConflict, "The balance of the account user-id changed its drift since the reconciliation, run a new one"),
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.Reconciliation.EXPECT().ReadOneById(gomock.Any(), "reconciliation-id").Times(1).Return(reconciliation(), nil),
+					db.Reconciliation.EXPECT().ReadDifferences(gomock.Any(), "reconciliation-id").Times(1).Return(differences(), nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), "user-id").Times(1).Return(&entity.Account{ID: "user-id", Balance: money.New(9010)}, nil),
+					db.Reconciliation.EXPECT().ReadExpectedBalance(gomock.Any(), "user-id").Times(1).Return(money.New(9010), nil),
+				)
+			},
+		},
+		"deve retornar erro": {
+			Input:       dto.ApproveCorrections{ApprovedBy: "operator"},
+			ExpectedErr: echo.ErrNotFound,
+			PrepareMock: func(db *databaseMocks) {
+				db.Reconciliation.EXPECT().ReadOneById(gomock.Any(), "reconciliation-id").Times(1).Return(nil, echo.ErrNotFound)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			container, db := newDatabaseContainer(ctrl)
+			cs.PrepareMock(db)
+
+			app := NewAppLedger(container)
+
+			result, err := app.ApproveCorrections(ctx, "reconciliation-id", cs.Input)
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+
+			if err == nil {
+				corrected := result.Differences[0]
+				if !corrected.IsCorrected() || *corrected.ApprovedBy != "operator" {
+					t.Errorf("expected the difference of user-id to be corrected, got %+v", corrected)
+				}
+			}
+		})
+	}
+}
+
+// TestCorrectionClearsBothChecks runs the reconciliation and the ledger check
+// against the same accounts and postings, before and after the correction is
+// approved. Both sum the postings of each account, like their queries do.
+func TestCorrectionClearsBothChecks(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+	container, db := newDatabaseContainer(ctrl)
+
+	balances := map[string]money.Money{"user-id": money.New(12010), "other-user-id": money.New(500)}
+	postings := []entity.Posting{
+		{TransactionId: "deposit-id", AccountId: entity.FundingAccountId, Amount: money.New(-11510)},
+		{TransactionId: "deposit-id", AccountId: "user-id", Amount: money.New(11510)},
+		{TransactionId: "transfer-id", AccountId: "user-id", Amount: money.New(-500)},
+		{TransactionId: "transfer-id", AccountId: "other-user-id", Amount: money.New(500)},
+	}
+	postingsBalance := func(accountId string) money.Money {
+		balance := money.New(0)
+		for _, posting := range postings {
+			if posting.AccountId == accountId {
+				balance = money.New(balance.Amount + posting.Amount.Amount)
+			}
+		}
+		return balance
+	}
+
+	var stored *entity.Reconciliation
+	db.Reconciliation.EXPECT().ReadDrifts(gomock.Any()).AnyTimes().
+		DoAndReturn(func(ctx context.Context) ([]entity.ReconciliationDifference, error) {
+			drifts := make([]entity.ReconciliationDifference, 0)
+			for _, accountId := range []string{"other-user-id", "user-id"} {
+				if expected := postingsBalance(accountId); expected != balances[accountId] {
+					drifts = append(drifts, entity.ReconciliationDifference{ID: int64(len(drifts) + 1), UserId: accountId, Balance: balances[accountId], ExpectedBalance: expected})
+				}
+			}
+			return drifts, nil
+		})
+	db.Reconciliation.EXPECT().Create(gomock.Any(), gomock.Any()).AnyTimes().
+		Do(func(ctx context.Context, reconciliation *entity.Reconciliation) { stored = reconciliation }).Return(nil)
+	db.Reconciliation.EXPECT().ReadOneById(gomock.Any(), gomock.Any()).AnyTimes().
+		DoAndReturn(func(ctx context.Context, id string) (*entity.Reconciliation, error) {
+			return &entity.Reconciliation{ID: stored.ID}, nil
+		})
+	db.Reconciliation.EXPECT().ReadDifferences(gomock.Any(), gomock.Any()).AnyTimes().
+		DoAndReturn(func(ctx context.Context, id string) ([]entity.ReconciliationDifference, error) {
+			return stored.Differences, nil
+		})
+	db.Reconciliation.EXPECT().ReadExpectedBalance(gomock.Any(), gomock.Any()).AnyTimes().
+		DoAndReturn(func(ctx context.Context, accountId string) (money.Money, error) {
+			return postingsBalance(accountId), nil
+		})
+	db.Reconciliation.EXPECT().UpdateCorrection(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
+	db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), gomock.Any()).AnyTimes().
+		DoAndReturn(func(ctx context.Context, id string) (*entity.Account, error) {
+			return &entity.Account{ID: id, Balance: balances[id]}, nil
+		})
+	db.Account.EXPECT().UpdateBalance(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().
+		Do(func(ctx context.Context, id string, balance money.Money) { balances[id] = balance }).Return(nil)
+	db.Ledger.EXPECT().CreatePostings(gomock.Any(), gomock.Any()).AnyTimes().
+		Do(func(ctx context.Context, created []entity.Posting) { postings = append(postings, created...) }).Return(nil)
+	db.Ledger.EXPECT().ReadTotal(gomock.Any()).AnyTimes().
+		DoAndReturn(func(ctx context.Context) (money.Money, error) {
+			total := money.New(0)
+			for _, posting := range postings {
+				total = money.New(total.Amount + posting.Amount.Amount)
+			}
+			return total, nil
+		})
+	db.Ledger.EXPECT().ReadUnbalancedTransactions(gomock.Any()).AnyTimes().Return([]string{}, nil)
+	db.Ledger.EXPECT().ReadBalanceMismatches(gomock.Any()).AnyTimes().
+		DoAndReturn(func(ctx context.Context) ([]entity.BalanceMismatch, error) {
+			mismatches := make([]entity.BalanceMismatch, 0)
+			for accountId, balance := range balances {
+				if postingsBalance(accountId) != balance {
+					mismatches = append(mismatches, entity.BalanceMismatch{AccountId: accountId, Balance: balance, PostingsBalance: postingsBalance(accountId)})
+				}
+			}
+			return mismatches, nil
+		})
+	db.Audit.EXPECT().Create(gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
+
+	app := NewAppLedger(container)
+
+	check, err := app.Check(ctx)
+	assert.NoError(t, err)
+	assert.False(t, check.Balanced)
+
+	reconciliation, err := app.Reconcile(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, reconciliation.DriftedUsers)
+
+	_, err = app.ApproveCorrections(ctx, reconciliation.ID, dto.ApproveCorrections{ApprovedBy: "operator"})
+	assert.NoError(t, err)
+
+	check, err = app.Check(ctx)
+	assert.NoError(t, err)
+	assert.True(t, check.Balanced)
+	assert.Empty(t, check.BalanceMismatches)
+
+	reconciliation, err = app.Reconcile(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, reconciliation.DriftedUsers)
+}
@@ -0,0 +1,148 @@
+package limit
+
+import (
+	"context"
+	"log"
+
+	"github.com/garoque/backend-code-challenge-snapfi/internal/app/audit"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/database"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+)
+
+type AppLimitInterface interface {
+	ReadDefault(ctx context.Context) (*entity.Limits, error)
+	UpdateDefault(ctx context.Context, limits *entity.Limits) (*entity.Limits, error)
+	ReadByUser(ctx context.Context, userId string) (*entity.Limits, error)
+	UpdateUser(ctx context.Context, limits *entity.Limits) (*entity.Limits, error)
+	DeleteUser(ctx context.Context, userId string) (*entity.Limits, error)
+}
+
+type appLimitImpl struct {
+	db *database.Container
+}
+
+func NewAppLimit(db *database.Container) AppLimitInterface {
+	return &appLimitImpl{db}
+}
+
+func (l *appLimitImpl) ReadDefault(ctx context.Context) (*entity.Limits, error) {
+	limits, err := l.db.Limit.ReadOneByUserId(ctx, entity.DefaultLimitsId)
+	if err != nil {
+		log.Println("Error app.Limit.ReadDefault.db.ReadOneByUserId: ", err.Error())
+		return nil, err
+	}
+
+	return limits, nil
+}
+
+func (l *appLimitImpl) UpdateDefault(ctx context.Context, limits *entity.Limits) (*entity.Limits, error) {
+	limits.UserId = entity.DefaultLimitsId
+
+	err := l.save(ctx, limits)
+	if err != nil {
+		log.Println("Error app.Limit.UpdateDefault.save: ", err.Error())
+		return nil, err
+	}
+
+	return limits, nil
+}
+
+// ReadByUser returns the limits the transfers of the user are checked
+// against, their override falling back to the default limits.
+func (l *appLimitImpl) ReadByUser(ctx context.Context, userId string) (*entity.Limits, error) {
+	_, err := l.db.User.ReadOneById(ctx, userId)
+	if err != nil {
+		log.Println("Error app.Limit.ReadByUser.db.User.ReadOneById: ", err.Error())
+		return nil, err
+	}
+
+	stored, err := l.db.Limit.ReadWithDefault(ctx, userId)
+	if err != nil {
+		log.Println("Error app.Limit.ReadByUser.db.ReadWithDefault: ", err.Error())
+		return nil, err
+	}
+
+	return entity.ResolveLimits(userId, stored), nil
+}
+
+// UpdateUser replaces the override of the user and returns their resulting
+// limits.
+func (l *appLimitImpl) UpdateUser(ctx context.Context, limits *entity.Limits) (*entity.Limits, error) {
+	_, err := l.db.User.ReadOneById(ctx, limits.UserId)
+	if err != nil {
+		log.Println("Error app.Limit.UpdateUser.db.User.ReadOneById: ", err.Error())
+		return nil, err
+	}
+
+	err = l.save(ctx, limits)
+	if err != nil {
+		log.Println("Error app.Limit.UpdateUser.save: ", err.Error())
+		return nil, err
+	}
+
+	return l.ReadByUser(ctx, limits.UserId)
+}
+
+// DeleteUser removes the override of the user, who goes back to the default
+// limits, and returns them.
+func (l *appLimitImpl) DeleteUser(ctx context.Context, userId string) (*entity.Limits, error) {
+	_, err := l.db.User.ReadOneById(ctx, userId)
+	if err != nil {
+		log.Println("Error app.Limit.DeleteUser.db.User.ReadOneById: ", err.Error())
+		return nil, err
+	}
+
+	err = l.db.UnitOfWork.Do(ctx, func(tx *database.Container) error {
+		before, err := readStored(ctx, tx, userId)
+		if err != nil {
+			return err
+		}
+
+		err = tx.Limit.Delete(ctx, userId)
+		if err != nil {
+			return err
+		}
+
+		return audit.Record(ctx, tx, entity.AUDIT_LIMITS_DELETED, entity.AUDIT_LIMITS, userId, before, nil)
+	})
+	if err != nil {
+		log.Println("Error app.Limit.DeleteUser.db.Delete: ", err.Error())
+		return nil, err
+	}
+
+	return l.ReadByUser(ctx, userId)
+}
+
+// save stores the limits and records the change in the audit log.
+func (l *appLimitImpl) save(ctx context.Context, limits *entity.Limits) error {
+	return l.db.UnitOfWork.Do(ctx, func(tx *database.Container) error {
+		before, err := readStored(ctx, tx, limits.UserId)
+		if err != nil {
+			return err
+		}
+
+		err = tx.Limit.Save(ctx, limits)
+		if err != nil {
+			return err
+		}
+
+		return audit.Record(ctx, tx, entity.AUDIT_LIMITS_UPDATED, entity.AUDIT_LIMITS, limits.UserId, before, limits)
+	})
+}
+
+// readStored returns the limits stored under the id, the default limits or the
+// override of a user, or nil when there are none.
+func readStored(ctx context.Context, tx *database.Container, id string) (*entity.Limits, error) {
+	stored, err := tx.Limit.ReadWithDefault(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range stored {
+		if stored[i].UserId == id {
+			return &stored[i], nil
+		}
+	}
+
+	return nil, nil
+}
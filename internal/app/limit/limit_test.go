@@ -0,0 +1,262 @@
+package limit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/garoque/backend-code-challenge-snapfi/internal/database"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/mocks"
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/money"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+	"github.com/labstack/echo/v4"
+)
+
+var (
+	perTransaction = money.New(50000)
+	daily          = money.New(100000)
+	userDaily      = money.New(300000)
+
+	defaultLimits = entity.Limits{UserId: entity.DefaultLimitsId, PerTransaction: &perTransaction, Daily: &daily}
+	userLimits    = entity.Limits{UserId: "user-id", Daily: &userDaily}
+)
+
+func TestReadDefault(t *testing.T) {
+	cases := map[string]struct {
+		ExpectedResult *entity.Limits
+		ExpectedErr    error
+		PrepareMock    func(mockLimitDb *mocks.MockDabataseLimitInterface)
+	}{
+		"deve retornar sucesso": {
+			ExpectedResult: &defaultLimits,
+			ExpectedErr:    nil,
+			PrepareMock: func(mockLimitDb *mocks.MockDabataseLimitInterface) {
+				mockLimitDb.EXPECT().ReadOneByUserId(gomock.Any(), entity.DefaultLimitsId).Times(1).Return(&defaultLimits, nil)
+			},
+		},
+		"deve retornar erro": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrNotFound,
+			PrepareMock: func(mockLimitDb *mocks.MockDabataseLimitInterface) {
+				mockLimitDb.EXPECT().ReadOneByUserId(gomock.Any(), entity.DefaultLimitsId).Times(1).Return(nil, echo.ErrNotFound)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			mockLimitDb := mocks.NewMockDabataseLimitInterface(ctrl)
+			cs.PrepareMock(mockLimitDb)
+
+			app := NewAppLimit(&database.Container{Limit: mockLimitDb})
+
+			limits, err := app.ReadDefault(ctx)
+			if diff := cmp.Diff(limits, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestUpdateDefault(t *testing.T) {
+	cases := map[string]struct {
+		ExpectedResult *entity.Limits
+		ExpectedErr    error
+		PrepareMock    func(mockLimitDb *mocks.MockDabataseLimitInterface, mockAuditDb *mocks.MockDabataseAuditInterface)
+	}{
+		"deve retornar sucesso": {
+			ExpectedResult: &defaultLimits,
+			ExpectedErr:    nil,
+			PrepareMock: func(mockLimitDb *mocks.MockDabataseLimitInterface, mockAuditDb *mocks.MockDabataseAuditInterface) {
+				gomock.InOrder(
+					mockLimitDb.EXPECT().ReadWithDefault(gomock.Any(), entity.DefaultLimitsId).Times(1).Return([]entity.Limits{}, nil),
+					mockLimitDb.EXPECT().Save(gomock.Any(), &defaultLimits).Times(1).Return(nil),
+					mockAuditDb.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).
+						Do(func(ctx context.Context, entry *entity.AuditEntry) {
+							if entry.Operation != entity.AUDIT_LIMITS_UPDATED || entry.EntityId != entity.DefaultLimitsId || entry.Before != nil {
+								t.Errorf("unexpected audit entry %+v", entry)
+							}
+						}).Return(nil),
+				)
+			},
+		},
+		"deve retornar erro": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrInternalServerError,
+			PrepareMock: func(mockLimitDb *mocks.MockDabataseLimitInterface, mockAuditDb *mocks.MockDabataseAuditInterface) {
+				gomock.InOrder(
+					mockLimitDb.EXPECT().ReadWithDefault(gomock.Any(), entity.DefaultLimitsId).Times(1).Return([]entity.Limits{}, nil),
+					mockLimitDb.EXPECT().Save(gomock.Any(), &defaultLimits).Times(1).Return(echo.ErrInternalServerError),
+				)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			mockLimitDb := mocks.NewMockDabataseLimitInterface(ctrl)
+			mockAuditDb := mocks.NewMockDabataseAuditInterface(ctrl)
+			cs.PrepareMock(mockLimitDb, mockAuditDb)
+
+			container := &database.Container{Limit: mockLimitDb, Audit: mockAuditDb}
+			mockUnitOfWork := mocks.NewMockUnitOfWorkInterface(ctrl)
+			container.UnitOfWork = mockUnitOfWork
+			mockUnitOfWork.EXPECT().Do(gomock.Any(), gomock.Any()).AnyTimes().
+				DoAndReturn(func(ctx context.Context, fn func(tx *database.Container) error) error {
+					return fn(container)
+				})
+
+			app := NewAppLimit(container)
+
+			limits, err := app.UpdateDefault(ctx, &entity.Limits{PerTransaction: &perTransaction, Daily: &daily})
+			if diff := cmp.Diff(limits, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestUserLimits(t *testing.T) {
+	resolved := &entity.Limits{UserId: "user-id", PerTransaction: &perTransaction, Daily: &userDaily}
+	defaults := &entity.Limits{UserId: "user-id", PerTransaction: &perTransaction, Daily: &daily}
+
+	cases := map[string]struct {
+		Run            func(ctx context.Context, app AppLimitInterface) (*entity.Limits, error)
+		ExpectedResult *entity.Limits
+		ExpectedErr    error
+		PrepareMock    func(mockUserDb *mocks.MockDabataseUserInterface, mockLimitDb *mocks.MockDabataseLimitInterface, mockAuditDb *mocks.MockDabataseAuditInterface)
+	}{
+		"deve retornar sucesso: ler": {
+			Run: func(ctx context.Context, app AppLimitInterface) (*entity.Limits, error) {
+				return app.ReadByUser(ctx, "user-id")
+			},
+			ExpectedResult: resolved,
+			PrepareMock: func(mockUserDb *mocks.MockDabataseUserInterface, mockLimitDb *mocks.MockDabataseLimitInterface, mockAuditDb *mocks.MockDabataseAuditInterface) {
+				gomock.InOrder(
+					mockUserDb.EXPECT().ReadOneById(gomock.Any(), "user-id").Times(1).Return(&entity.User{ID: "user-id"}, nil),
+					mockLimitDb.EXPECT().ReadWithDefault(gomock.Any(), "user-id").Times(1).Return([]entity.Limits{defaultLimits, userLimits}, nil),
+				)
+			},
+		},
+		"deve retornar erro: ler usuario inexistente": {
+			Run: func(ctx context.Context, app AppLimitInterface) (*entity.Limits, error) {
+				return app.ReadByUser(ctx, "user-id")
+			},
+			ExpectedErr: echo.ErrNotFound,
+			PrepareMock: func(mockUserDb *mocks.MockDabataseUserInterface, mockLimitDb *mocks.MockDabataseLimitInterface, mockAuditDb *mocks.MockDabataseAuditInterface) {
+				mockUserDb.EXPECT().ReadOneById(gomock.Any(), "user-id").Times(1).Return(nil, echo.ErrNotFound)
+			},
+		},
+		"deve retornar sucesso: alterar": {
+			Run: func(ctx context.Context, app AppLimitInterface) (*entity.Limits, error) {
+				return app.UpdateUser(ctx, &userLimits)
+			},
+			ExpectedResult: resolved,
+			PrepareMock: func(mockUserDb *mocks.MockDabataseUserInterface, mockLimitDb *mocks.MockDabataseLimitInterface, mockAuditDb *mocks.MockDabataseAuditInterface) {
+				gomock.InOrder(
+					mockUserDb.EXPECT().ReadOneById(gomock.Any(), "user-id").Times(1).Return(&entity.User{ID: "user-id"}, nil),
+					mockLimitDb.EXPECT().ReadWithDefault(gomock.Any(), "user-id").Times(1).Return([]entity.Limits{defaultLimits}, nil),
+					mockLimitDb.EXPECT().Save(gomock.Any(), &userLimits).Times(1).Return(nil),
+					mockAuditDb.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).
+						Do(func(ctx context.Context, entry *entity.AuditEntry) {
+							if entry.Operation != entity.AUDIT_LIMITS_UPDATED || entry.EntityId != "user-id" || entry.Before != nil || entry.After == nil {
+								t.Errorf("unexpected audit entry %+v", entry)
+							}
+						}).Return(nil),
+					mockUserDb.EXPECT().ReadOneById(gomock.Any(), "user-id").Times(1).Return(&entity.User{ID: "user-id"}, nil),
+					mockLimitDb.EXPECT().ReadWithDefault(gomock.Any(), "user-id").Times(1).Return([]entity.Limits{userLimits, defaultLimits}, nil),
+				)
+			},
+		},
+		"deve retornar erro: alterar": {
+			Run: func(ctx context.Context, app AppLimitInterface) (*entity.Limits, error) {
+				return app.UpdateUser(ctx, &userLimits)
+			},
+			ExpectedErr: echo.ErrInternalServerError,
+			PrepareMock: func(mockUserDb *mocks.MockDabataseUserInterface, mockLimitDb *mocks.MockDabataseLimitInterface, mockAuditDb *mocks.MockDabataseAuditInterface) {
+				gomock.InOrder(
+					mockUserDb.EXPECT().ReadOneById(gomock.Any(), "user-id").Times(1).Return(&entity.User{ID: "user-id"}, nil),
+					mockLimitDb.EXPECT().ReadWithDefault(gomock.Any(), "user-id").Times(1).Return([]entity.Limits{defaultLimits}, nil),
+					mockLimitDb.EXPECT().Save(gomock.Any(), &userLimits).Times(1).Return(echo.ErrInternalServerError),
+				)
+			},
+		},
+		"deve retornar sucesso: remover": {
+			Run: func(ctx context.Context, app AppLimitInterface) (*entity.Limits, error) {
+				return app.DeleteUser(ctx, "user-id")
+			},
+			ExpectedResult: defaults,
+			PrepareMock: func(mockUserDb *mocks.MockDabataseUserInterface, mockLimitDb *mocks.MockDabataseLimitInterface, mockAuditDb *mocks.MockDabataseAuditInterface) {
+				gomock.InOrder(
+					mockUserDb.EXPECT().ReadOneById(gomock.Any(), "user-id").Times(1).Return(&entity.User{ID: "user-id"}, nil),
+					mockLimitDb.EXPECT().ReadWithDefault(gomock.Any(), "user-id").Times(1).Return([]entity.Limits{defaultLimits, userLimits}, nil),
+					mockLimitDb.EXPECT().Delete(gomock.Any(), "user-id").Times(1).Return(nil),
+					mockAuditDb.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).
+						Do(func(ctx context.Context, entry *entity.AuditEntry) {
+							if entry.Operation != entity.AUDIT_LIMITS_DELETED || entry.Before == nil || entry.After != nil {
+								t.Errorf("unexpected audit entry %+v", entry)
+							}
+						}).Return(nil),
+					mockUserDb.EXPECT().ReadOneById(gomock.Any(), "user-id").Times(1).Return(&entity.User{ID: "user-id"}, nil),
+					mockLimitDb.EXPECT().ReadWithDefault(gomock.Any(), "user-id").Times(1).Return([]entity.Limits{defaultLimits}, nil),
+				)
+			},
+		},
+		"deve retornar erro: remover": {
+			Run: func(ctx context.Context, app AppLimitInterface) (*entity.Limits, error) {
+				return app.DeleteUser(ctx, "user-id")
+			},
+			ExpectedErr: echo.ErrInternalServerError,
+			PrepareMock: func(mockUserDb *mocks.MockDabataseUserInterface, mockLimitDb *mocks.MockDabataseLimitInterface, mockAuditDb *mocks.MockDabataseAuditInterface) {
+				gomock.InOrder(
+					mockUserDb.EXPECT().ReadOneById(gomock.Any(), "user-id").Times(1).Return(&entity.User{ID: "user-id"}, nil),
+					mockLimitDb.EXPECT().ReadWithDefault(gomock.Any(), "user-id").Times(1).Return([]entity.Limits{defaultLimits, userLimits}, nil),
+					mockLimitDb.EXPECT().Delete(gomock.Any(), "user-id").Times(1).Return(echo.ErrInternalServerError),
+				)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			mockUserDb := mocks.NewMockDabataseUserInterface(ctrl)
+			mockLimitDb := mocks.NewMockDabataseLimitInterface(ctrl)
+			mockAuditDb := mocks.NewMockDabataseAuditInterface(ctrl)
+			cs.PrepareMock(mockUserDb, mockLimitDb, mockAuditDb)
+
+			container := &database.Container{User: mockUserDb, Limit: mockLimitDb, Audit: mockAuditDb}
+			mockUnitOfWork := mocks.NewMockUnitOfWorkInterface(ctrl)
+			container.UnitOfWork = mockUnitOfWork
+			mockUnitOfWork.EXPECT().Do(gomock.Any(), gomock.Any()).AnyTimes().
+				DoAndReturn(func(ctx context.Context, fn func(tx *database.Container) error) error {
+					return fn(container)
+				})
+
+			app := NewAppLimit(container)
+
+			limits, err := cs.Run(ctx, app)
+			if diff := cmp.Diff(limits, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
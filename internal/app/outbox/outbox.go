@@ -0,0 +1,89 @@
+package outbox
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/garoque/backend-code-challenge-snapfi/internal/database"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/publisher"
+)
+
+// RelayBatchSize is how many events are read from the outbox at a time.
+const RelayBatchSize = 100
+
+type AppOutboxInterface interface {
+	Relay(ctx context.Context) error
+}
+
+type appOutboxImpl struct {
+	db        *database.Container
+	publisher publisher.Publisher
+}
+
+func NewAppOutbox(db *database.Container, publisher publisher.Publisher) AppOutboxInterface {
+	return &appOutboxImpl{db, publisher}
+}
+
+// Relay publishes the events of the outbox in the order they were written,
+// until none is left. It stops at the first event that fails to publish, so
+// no event of an aggregate is published before the ones written ahead of it,
+// and it is retried on the next run. The events published up to then are
+// recorded only after they were, which may publish one of them again.
+func (o *appOutboxImpl) Relay(ctx context.Context) error {
+	for {
+		var read int
+		var publishErr error
+		err := o.db.UnitOfWork.Do(ctx, func(tx *database.Container) error {
+			events, err := tx.Outbox.ReadUnpublished(ctx, RelayBatchSize)
+			if err != nil {
+				log.Println("Error app.Outbox.Relay.db.ReadUnpublished: ", err.Error())
+				return err
+			}
+			read = len(events)
+
+			var published []int64
+			published, publishErr = o.publish(ctx, events)
+			if len(published) == 0 {
+				return nil
+			}
+
+			err = tx.Outbox.UpdatePublished(ctx, published, time.Now())
+			if err != nil {
+				log.Println("Error app.Outbox.Relay.db.UpdatePublished: ", err.Error())
+				return err
+			}
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		if publishErr != nil {
+			return publishErr
+		}
+
+		if read < RelayBatchSize {
+			return nil
+		}
+	}
+}
+
+// publish publishes the events in order and returns the sequences of the ones
+// published before the first failure.
+func (o *appOutboxImpl) publish(ctx context.Context, events []entity.Event) ([]int64, error) {
+	published := make([]int64, 0, len(events))
+	for _, event := range events {
+		err := o.publisher.Publish(ctx, event)
+		if err != nil {
+			log.Println("Error app.Outbox.publish.publisher.Publish: ", err.Error())
+			return published, err
+		}
+
+		published = append(published, event.Sequence)
+	}
+
+	return published, nil
+}
@@ -0,0 +1,114 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/garoque/backend-code-challenge-snapfi/internal/database"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/mocks"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/publisher"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+	"github.com/labstack/echo/v4"
+)
+
+func TestRelay(t *testing.T) {
+	events := []entity.Event{
+		{Sequence: 1, ID: "first-event-id", Type: "transaction.pending", AggregateId: "transaction-id"},
+		{Sequence: 2, ID: "second-event-id", Type: "transaction.booked", AggregateId: "transaction-id"},
+	}
+	fullBatch := make([]entity.Event, RelayBatchSize)
+	fullBatchSequences := make([]int64, RelayBatchSize)
+	for i := range fullBatch {
+		fullBatch[i] = entity.Event{Sequence: int64(i + 1), ID: "event-id"}
+		fullBatchSequences[i] = int64(i + 1)
+	}
+	failure := errors.New("publisher unavailable")
+
+	cases := map[string]struct {
+		Fail              string
+		ExpectedPublished []string
+		ExpectedErr       error
+		PrepareMock       func(mockOutboxDb *mocks.MockDabataseOutboxInterface)
+	}{
+		"deve retornar sucesso": {
+			ExpectedPublished: []string{"first-event-id", "second-event-id"},
+			ExpectedErr:       nil,
+			PrepareMock: func(mockOutboxDb *mocks.MockDabataseOutboxInterface) {
+				gomock.InOrder(
+					mockOutboxDb.EXPECT().ReadUnpublished(gomock.Any(), RelayBatchSize).Times(1).Return(events, nil),
+					mockOutboxDb.EXPECT().UpdatePublished(gomock.Any(), []int64{1, 2}, gomock.Any()).Times(1).Return(nil),
+				)
+			},
+		},
+		"deve retornar sucesso: mais de um lote": {
+			ExpectedErr: nil,
+			PrepareMock: func(mockOutboxDb *mocks.MockDabataseOutboxInterface) {
+				gomock.InOrder(
+					mockOutboxDb.EXPECT().ReadUnpublished(gomock.Any(), RelayBatchSize).Times(1).Return(fullBatch, nil),
+					mockOutboxDb.EXPECT().UpdatePublished(gomock.Any(), fullBatchSequences, gomock.Any()).Times(1).Return(nil),
+					mockOutboxDb.EXPECT().ReadUnpublished(gomock.Any(), RelayBatchSize).Times(1).Return([]entity.Event{}, nil),
+				)
+			},
+		},
+		"deve retornar erro: ao publicar": {
+			Fail:              "second-event-id",
+			ExpectedPublished: []string{"first-event-id"},
+			ExpectedErr:       failure,
+			PrepareMock: func(mockOutboxDb *mocks.MockDabataseOutboxInterface) {
+				gomock.InOrder(
+					mockOutboxDb.EXPECT().ReadUnpublished(gomock.Any(), RelayBatchSize).Times(1).Return(events, nil),
+					mockOutboxDb.EXPECT().UpdatePublished(gomock.Any(), []int64{1}, gomock.Any()).Times(1).Return(nil),
+				)
+			},
+		},
+		"deve retornar erro": {
+			ExpectedErr: echo.ErrInternalServerError,
+			PrepareMock: func(mockOutboxDb *mocks.MockDabataseOutboxInterface) {
+				mockOutboxDb.EXPECT().ReadUnpublished(gomock.Any(), RelayBatchSize).Times(1).Return(nil, echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			mockOutboxDb := mocks.NewMockDabataseOutboxInterface(ctrl)
+			cs.PrepareMock(mockOutboxDb)
+
+			mockUnitOfWork := mocks.NewMockUnitOfWorkInterface(ctrl)
+			container := &database.Container{Outbox: mockOutboxDb, UnitOfWork: mockUnitOfWork}
+			mockUnitOfWork.EXPECT().Do(gomock.Any(), gomock.Any()).AnyTimes().
+				DoAndReturn(func(ctx context.Context, fn func(tx *database.Container) error) error {
+					return fn(container)
+				})
+
+			published := make([]string, 0)
+			memory := publisher.NewMemory()
+			memory.Subscribe(func(ctx context.Context, event entity.Event) error {
+				if event.ID == cs.Fail {
+					return failure
+				}
+
+				published = append(published, event.ID)
+				return nil
+			})
+
+			app := NewAppOutbox(container, memory)
+
+			err := app.Relay(ctx)
+			if diff := cmp.Diff(err, cs.ExpectedErr, cmp.Comparer(func(a, b error) bool { return a == b })); diff != "" {
+				t.Error(diff)
+			}
+
+			if cs.ExpectedPublished != nil {
+				if diff := cmp.Diff(published, cs.ExpectedPublished); diff != "" {
+					t.Error(diff)
+				}
+			}
+		})
+	}
+}
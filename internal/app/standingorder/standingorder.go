@@ -0,0 +1,313 @@
+package standingorder
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/garoque/backend-code-challenge-snapfi/internal/app/audit"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/app/transaction"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/database"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	"github.com/labstack/echo/v4"
+)
+
+// RetryPolicy says how many more times, and how long apart, an occurrence is
+// attempted again when the source user doesn't have enough balance. Other
+// failures aren't retried.
+type RetryPolicy struct {
+	MaxRetries int
+	Interval   time.Duration
+}
+
+var DefaultRetryPolicy = RetryPolicy{MaxRetries: 3, Interval: time.Hour}
+
+type AppStandingOrderInterface interface {
+	Create(ctx context.Context, order *entity.StandingOrder) (*entity.StandingOrder, error)
+	ReadAll(ctx context.Context) ([]entity.StandingOrder, error)
+	ReadOneById(ctx context.Context, id string) (*entity.StandingOrder, error)
+	Update(ctx context.Context, id string, changes *entity.StandingOrderChanges) (*entity.StandingOrder, error)
+	Cancel(ctx context.Context, id string) (*entity.StandingOrder, error)
+	ReadOccurrences(ctx context.Context, id string) ([]entity.StandingOrderOccurrence, error)
+	Execute(ctx context.Context) error
+}
+
+type appStandingOrderImpl struct {
+	db    *database.Container
+	retry RetryPolicy
+}
+
+func NewAppStandingOrder(db *database.Container, retry RetryPolicy) AppStandingOrderInterface {
+	return &appStandingOrderImpl{db, retry}
+}
+
+func (s *appStandingOrderImpl) Create(ctx context.Context, order *entity.StandingOrder) (*entity.StandingOrder, error) {
+	if order.SourceId == order.DestinationId {
+		return nil, echo.NewHTTPError(echo.ErrBadRequest.Code, "Source and destination accounts must be different")
+	}
+
+	if entity.IsSystemAccount(order.SourceId) || entity.IsSystemAccount(order.DestinationId) {
+		return nil, echo.NewHTTPError(echo.ErrBadRequest.Code, "System accounts can't be the source or destination of a standing order")
+	}
+
+	if !order.StartAt.After(time.Now()) {
+		return nil, echo.NewHTTPError(echo.ErrBadRequest.Code, "The start date must be in the future")
+	}
+
+	if order.EndAt != nil && order.EndAt.Before(order.StartAt) {
+		return nil, echo.NewHTTPError(echo.ErrBadRequest.Code, "The end date must be after the start date")
+	}
+
+	for _, accountId := range []string{order.SourceId, order.DestinationId} {
+		_, err := s.db.Account.ReadOneById(ctx, accountId)
+		if err != nil {
+			log.Println("Error app.StandingOrder.Create.db.Account.ReadOneById: ", err.Error())
+			return nil, err
+		}
+	}
+
+	err := s.db.UnitOfWork.Do(ctx, func(tx *database.Container) error {
+		err := tx.StandingOrder.Create(ctx, order)
+		if err != nil {
+			return err
+		}
+
+		return audit.Record(ctx, tx, entity.AUDIT_STANDING_ORDER_CREATED, entity.AUDIT_STANDING_ORDER, order.ID, nil, withStrings(order))
+	})
+	if err != nil {
+		log.Println("Error app.StandingOrder.Create.db.Create: ", err.Error())
+		return nil, err
+	}
+
+	return withStrings(order), nil
+}
+
+func (s *appStandingOrderImpl) ReadAll(ctx context.Context) ([]entity.StandingOrder, error) {
+	orders, err := s.db.StandingOrder.ReadAll(ctx)
+	if err != nil {
+		log.Println("Error app.StandingOrder.ReadAll.db.ReadAll: ", err.Error())
+		return nil, err
+	}
+
+	for i := range orders {
+		withStrings(&orders[i])
+	}
+
+	return orders, nil
+}
+
+func (s *appStandingOrderImpl) ReadOneById(ctx context.Context, id string) (*entity.StandingOrder, error) {
+	order, err := s.db.StandingOrder.ReadOneById(ctx, id)
+	if err != nil {
+		log.Println("Error app.StandingOrder.ReadOneById.db.ReadOneById: ", err.Error())
+		return nil, err
+	}
+
+	return withStrings(order), nil
+}
+
+func (s *appStandingOrderImpl) Update(ctx context.Context, id string, changes *entity.StandingOrderChanges) (*entity.StandingOrder, error) {
+	var order *entity.StandingOrder
+	err := s.db.UnitOfWork.Do(ctx, func(tx *database.Container) error {
+		var err error
+		order, err = readActive(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		before := *withStrings(order)
+		order.Apply(changes)
+
+		err = tx.StandingOrder.Update(ctx, order)
+		if err != nil {
+			return err
+		}
+
+		return audit.Record(ctx, tx, entity.AUDIT_STANDING_ORDER_UPDATED, entity.AUDIT_STANDING_ORDER, order.ID, before, withStrings(order))
+	})
+	if err != nil {
+		log.Println("Error app.StandingOrder.Update: ", err.Error())
+		return nil, err
+	}
+
+	return withStrings(order), nil
+}
+
+func (s *appStandingOrderImpl) Cancel(ctx context.Context, id string) (*entity.StandingOrder, error) {
+	var order *entity.StandingOrder
+	err := s.db.UnitOfWork.Do(ctx, func(tx *database.Container) error {
+		var err error
+		order, err = readActive(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		before := *withStrings(order)
+		order.Cancel()
+
+		err = tx.StandingOrder.Update(ctx, order)
+		if err != nil {
+			return err
+		}
+
+		return audit.Record(ctx, tx, entity.AUDIT_STANDING_ORDER_CANCELLED, entity.AUDIT_STANDING_ORDER, order.ID, before, withStrings(order))
+	})
+	if err != nil {
+		log.Println("Error app.StandingOrder.Cancel: ", err.Error())
+		return nil, err
+	}
+
+	return withStrings(order), nil
+}
+
+func (s *appStandingOrderImpl) ReadOccurrences(ctx context.Context, id string) ([]entity.StandingOrderOccurrence, error) {
+	_, err := s.db.StandingOrder.ReadOneById(ctx, id)
+	if err != nil {
+		log.Println("Error app.StandingOrder.ReadOccurrences.db.ReadOneById: ", err.Error())
+		return nil, err
+	}
+
+	occurrences, err := s.db.StandingOrder.ReadOccurrences(ctx, id)
+	if err != nil {
+		log.Println("Error app.StandingOrder.ReadOccurrences.db.ReadOccurrences: ", err.Error())
+		return nil, err
+	}
+
+	for i := range occurrences {
+		occurrences[i].StateString = occurrences[i].State.String()
+	}
+
+	return occurrences, nil
+}
+
+// Execute runs the due occurrence of every active standing order as an
+// ordinary transfer. The transfer is booked in the unit of work that locks the
+// order and records the occurrence, so API instances running the job at the
+// same time never execute an occurrence twice, and a payment is never booked
+// without the order moving on.
+func (s *appStandingOrderImpl) Execute(ctx context.Context) error {
+	now := time.Now()
+
+	due, err := s.db.StandingOrder.ReadDue(ctx, now)
+	if err != nil {
+		log.Println("Error app.StandingOrder.Execute.db.ReadDue: ", err.Error())
+		return err
+	}
+
+	for _, dueOrder := range due {
+		var payment *entity.Transaction
+		var transferErr error
+		err := s.db.UnitOfWork.Do(ctx, func(tx *database.Container) error {
+			order, err := readDue(ctx, tx, dueOrder.ID, now)
+			if err != nil || order == nil {
+				return err
+			}
+
+			payment = order.NewTransaction()
+			_, transferErr = transaction.Transfer(ctx, tx, payment)
+			if transferErr != nil {
+				return transferErr
+			}
+
+			return completeOccurrence(ctx, tx, order, payment, "")
+		})
+		if err != nil {
+			log.Println("Error app.StandingOrder.Execute.executeOccurrence: ", dueOrder.ID, err.Error())
+		}
+
+		if transferErr != nil {
+			s.failOccurrence(ctx, dueOrder.ID, now, payment, transferErr)
+		}
+	}
+
+	return nil
+}
+
+// failOccurrence records the payment that couldn't be booked as FAILED, once
+// the unit of work that tried to book it has been rolled back, and then either
+// schedules a retry or logs the occurrence and moves on to the next one.
+func (s *appStandingOrderImpl) failOccurrence(ctx context.Context, id string, now time.Time, payment *entity.Transaction, cause error) {
+	err := s.db.UnitOfWork.Do(ctx, func(tx *database.Container) error {
+		order, err := readDue(ctx, tx, id, now)
+		if err != nil || order == nil {
+			return err
+		}
+
+		err = transaction.RecordFailed(ctx, tx, payment, cause)
+		if err != nil {
+			return err
+		}
+
+		if s.shouldRetry(order, cause, now) {
+			order.Retry(now.Add(s.retry.Interval))
+			return tx.StandingOrder.Update(ctx, order)
+		}
+
+		return completeOccurrence(ctx, tx, order, payment, cause.Error())
+	})
+	if err != nil {
+		log.Println("Error app.StandingOrder.failOccurrence: ", id, err.Error())
+	}
+}
+
+// completeOccurrence logs the occurrence paid by the transaction and moves
+// the order on to the next one.
+func completeOccurrence(ctx context.Context, tx *database.Container, order *entity.StandingOrder, payment *entity.Transaction, reason string) error {
+	err := tx.StandingOrder.CreateOccurrence(ctx, order.NewOccurrence(payment, reason))
+	if err != nil {
+		log.Println("Error app.StandingOrder.completeOccurrence.db.CreateOccurrence: ", err.Error())
+		return err
+	}
+
+	order.CompleteOccurrence()
+
+	return tx.StandingOrder.Update(ctx, order)
+}
+
+// shouldRetry reports whether a failed occurrence is attempted again. Retries
+// never run into the following occurrence.
+func (s *appStandingOrderImpl) shouldRetry(order *entity.StandingOrder, err error, now time.Time) bool {
+	if !errors.Is(err, transaction.ErrInsufficientBalance) || order.Attempts >= s.retry.MaxRetries {
+		return false
+	}
+
+	return now.Add(s.retry.Interval).Before(order.OccurrenceAt(order.Occurrences + 1))
+}
+
+// readDue locks the standing order and returns it while its next occurrence
+// is still due, or nil when another run already took care of it.
+func readDue(ctx context.Context, tx *database.Container, id string, now time.Time) (*entity.StandingOrder, error) {
+	order, err := tx.StandingOrder.ReadOneByIdForUpdate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !order.IsDue(now) {
+		return nil, nil
+	}
+
+	return order, nil
+}
+
+// readActive locks the standing order and makes sure it can still be changed.
+func readActive(ctx context.Context, tx *database.Container, id string) (*entity.StandingOrder, error) {
+	order, err := tx.StandingOrder.ReadOneByIdForUpdate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if order.State != entity.ORDER_ACTIVE {
+		return nil, echo.NewHTTPError(http.StatusConflict, "Standing order is not active")
+	}
+
+	return order, nil
+}
+
+func withStrings(order *entity.StandingOrder) *entity.StandingOrder {
+	order.FrequencyString = order.Frequency.String()
+	order.StateString = order.State.String()
+
+	return order
+}
@@ -0,0 +1,664 @@
+package standingorder
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/garoque/backend-code-challenge-snapfi/internal/app/transaction"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/database"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/mocks"
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/money"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+	"github.com/labstack/echo/v4"
+)
+
+type databaseMocks struct {
+	StandingOrder *mocks.MockDabataseStandingOrderInterface
+	User          *mocks.MockDabataseUserInterface
+	Transaction   *mocks.MockDabataseTransactionInterface
+	Account       *mocks.MockDabataseAccountInterface
+	Ledger        *mocks.MockDabataseLedgerInterface
+	StateHistory  *mocks.MockDabataseStateHistoryInterface
+	Limit         *mocks.MockDabataseLimitInterface
+	Fee           *mocks.MockDabataseFeeInterface
+	Outbox        *mocks.MockDabataseOutboxInterface
+	Audit         *mocks.MockDabataseAuditInterface
+}
+
+// newDatabaseContainer returns a container backed by mocks whose unit of work
+// runs the given function against the same mocks.
+func newDatabaseContainer(ctrl *gomock.Controller) (*database.Container, *databaseMocks) {
+	db := &databaseMocks{
+		StandingOrder: mocks.NewMockDabataseStandingOrderInterface(ctrl),
+		User:          mocks.NewMockDabataseUserInterface(ctrl),
+		Transaction:   mocks.NewMockDabataseTransactionInterface(ctrl),
+		Account:       mocks.NewMockDabataseAccountInterface(ctrl),
+		Ledger:        mocks.NewMockDabataseLedgerInterface(ctrl),
+		StateHistory:  mocks.NewMockDabataseStateHistoryInterface(ctrl),
+		Limit:         mocks.NewMockDabataseLimitInterface(ctrl),
+		Fee:           mocks.NewMockDabataseFeeInterface(ctrl),
+		Outbox:        mocks.NewMockDabataseOutboxInterface(ctrl),
+		Audit:         mocks.NewMockDabataseAuditInterface(ctrl),
+	}
+	mockUnitOfWork := mocks.NewMockUnitOfWorkInterface(ctrl)
+
+	container := &database.Container{
+		StandingOrder: db.StandingOrder,
+		User:          db.User,
+		Transaction:   db.Transaction,
+		Account:       db.Account,
+		Ledger:        db.Ledger,
+		StateHistory:  db.StateHistory,
+		Limit:         db.Limit,
+		Fee:           db.Fee,
+		Outbox:        db.Outbox,
+		Audit:         db.Audit,
+		UnitOfWork:    mockUnitOfWork,
+	}
+	mockUnitOfWork.EXPECT().Do(gomock.Any(), gomock.Any()).AnyTimes().
+		DoAndReturn(func(ctx context.Context, fn func(tx *database.Container) error) error {
+			return fn(container)
+		})
+
+	return container, db
+}
+
+var retryPolicy = RetryPolicy{MaxRetries: 2, Interval: time.Hour}
+
+func TestCreate(t *testing.T) {
+	startAt := time.Now().Add(24 * time.Hour)
+	endAt := startAt.AddDate(0, 6, 0)
+
+	order := entity.StandingOrder{
+		ID:            "standing-order-id",
+		SourceId:      "source-user-id",
+		DestinationId: "destination-user-id",
+		Amount:        money.New(15000),
+		Frequency:     entity.MONTHLY,
+		StartAt:       startAt,
+		EndAt:         &endAt,
+		NextRunAt:     &startAt,
+		State:         entity.ORDER_ACTIVE,
+	}
+
+	created := order
+	created.FrequencyString = entity.MONTHLY.String()
+	created.StateString = entity.ORDER_ACTIVE.String()
+
+	selfOrder := order
+	selfOrder.DestinationId = order.SourceId
+
+	systemOrder := order
+	systemOrder.SourceId = entity.FundingAccountId
+
+	pastStartAt := time.Now().Add(-time.Hour)
+	pastOrder := order
+	pastOrder.StartAt = pastStartAt
+
+	invertedEndAt := startAt.Add(-time.Hour)
+	invertedOrder := order
+	invertedOrder.EndAt = &invertedEndAt
+
+	cases := map[string]struct {
+		InputOrder     entity.StandingOrder
+		ExpectedResult *entity.StandingOrder
+		ExpectedErr    error
+		PrepareMock    func(db *databaseMocks)
+	}{
+		"deve retornar sucesso": {
+			InputOrder:     order,
+			ExpectedResult: &created,
+			ExpectedErr:    nil,
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.Account.EXPECT().ReadOneById(gomock.Any(), "source-user-id").Times(1).Return(&entity.Account{}, nil),
+					db.Account.EXPECT().ReadOneById(gomock.Any(), "destination-user-id").Times(1).Return(&entity.Account{}, nil),
+					db.StandingOrder.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Audit.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).
+						Do(func(ctx context.Context, entry *entity.AuditEntry) {
+							if entry.Operation != entity.AUDIT_STANDING_ORDER_CREATED || entry.EntityId != "standing-order-id" {
+								t.Errorf("unexpected audit entry %+v", entry)
+							}
+						}).Return(nil),
+				)
+			},
+		},
+		"deve retornar erro: source e destination iguais": {
+			InputOrder:     selfOrder,
+			ExpectedResult: nil,
+			ExpectedErr:    echo.NewHTTPError(echo.ErrBadRequest.Code, "Source and destination accounts must be different"),
+			PrepareMock:    func(db *databaseMocks) {},
+		},
+		"deve retornar erro: conta de sistema": {
+			InputOrder:     systemOrder,
+			ExpectedResult: nil,
+			ExpectedErr:    echo.NewHTTPError(echo.ErrBadRequest.Code, "System accounts can't be the source or destination of a standing order"),
+			PrepareMock:    func(db *databaseMocks) {},
+		},
+		"deve retornar erro: data de inicio no passado": {
+			InputOrder:     pastOrder,
+			ExpectedResult: nil,
+			ExpectedErr:    echo.NewHTTPError(echo.ErrBadRequest.Code, "The start date must be in the future"),
+			PrepareMock:    func(db *databaseMocks) {},
+		},
+		"deve retornar erro: data final antes do inicio": {
+			InputOrder:     invertedOrder,
+			ExpectedResult: nil,
+			ExpectedErr:    echo.NewHTTPError(echo.ErrBadRequest.Code, "The end date must be after the start date"),
+			PrepareMock:    func(db *databaseMocks) {},
+		},
+		"deve retornar erro: account nao encontrada": {
+			InputOrder:     order,
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrNotFound,
+			PrepareMock: func(db *databaseMocks) {
+				db.Account.EXPECT().ReadOneById(gomock.Any(), "source-user-id").Times(1).Return(nil, echo.ErrNotFound)
+			},
+		},
+		"deve retornar erro: ao registrar standing order": {
+			InputOrder:     order,
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrInternalServerError,
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.Account.EXPECT().ReadOneById(gomock.Any(), "source-user-id").Times(1).Return(&entity.Account{}, nil),
+					db.Account.EXPECT().ReadOneById(gomock.Any(), "destination-user-id").Times(1).Return(&entity.Account{}, nil),
+					db.StandingOrder.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(echo.ErrInternalServerError),
+				)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			container, db := newDatabaseContainer(ctrl)
+			cs.PrepareMock(db)
+
+			app := NewAppStandingOrder(container, retryPolicy)
+
+			input := cs.InputOrder
+			order, err := app.Create(ctx, &input)
+			if diff := cmp.Diff(order, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestReadAll(t *testing.T) {
+	orders := []entity.StandingOrder{{ID: "standing-order-id", Frequency: entity.WEEKLY, State: entity.ORDER_COMPLETED}}
+
+	expected := []entity.StandingOrder{orders[0]}
+	expected[0].FrequencyString = "WEEKLY"
+	expected[0].StateString = "COMPLETED"
+
+	cases := map[string]struct {
+		ExpectedResult []entity.StandingOrder
+		ExpectedErr    error
+		PrepareMock    func(db *databaseMocks)
+	}{
+		"deve retornar sucesso": {
+			ExpectedResult: expected,
+			ExpectedErr:    nil,
+			PrepareMock: func(db *databaseMocks) {
+				db.StandingOrder.EXPECT().ReadAll(gomock.Any()).Times(1).Return(append([]entity.StandingOrder(nil), orders...), nil)
+			},
+		},
+		"deve retornar erro": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrInternalServerError,
+			PrepareMock: func(db *databaseMocks) {
+				db.StandingOrder.EXPECT().ReadAll(gomock.Any()).Times(1).Return(nil, echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			container, db := newDatabaseContainer(ctrl)
+			cs.PrepareMock(db)
+
+			app := NewAppStandingOrder(container, retryPolicy)
+
+			orders, err := app.ReadAll(ctx)
+			if diff := cmp.Diff(orders, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	startAt := time.Now().Add(24 * time.Hour)
+	amount := money.New(20000)
+
+	readOrder := func(state entity.StatesStandingOrder) *entity.StandingOrder {
+		return &entity.StandingOrder{
+			ID:        "standing-order-id",
+			Amount:    money.New(15000),
+			Frequency: entity.DAILY,
+			StartAt:   startAt,
+			NextRunAt: &startAt,
+			State:     state,
+		}
+	}
+
+	updated := readOrder(entity.ORDER_ACTIVE)
+	updated.Amount = amount
+	updated.FrequencyString = "DAILY"
+	updated.StateString = "ACTIVE"
+
+	cases := map[string]struct {
+		ExpectedResult *entity.StandingOrder
+		ExpectedErr    error
+		PrepareMock    func(db *databaseMocks)
+	}{
+		"deve retornar sucesso": {
+			ExpectedResult: updated,
+			ExpectedErr:    nil,
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.StandingOrder.EXPECT().ReadOneByIdForUpdate(gomock.Any(), "standing-order-id").Times(1).Return(readOrder(entity.ORDER_ACTIVE), nil),
+					db.StandingOrder.EXPECT().Update(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Audit.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).
+						Do(func(ctx context.Context, entry *entity.AuditEntry) {
+							if entry.Operation != entity.AUDIT_STANDING_ORDER_UPDATED || entry.EntityId != "standing-order-id" {
+								t.Errorf("unexpected audit entry %+v", entry)
+							}
+						}).Return(nil),
+				)
+			},
+		},
+		"deve retornar erro: standing order inativa": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.NewHTTPError(echo.ErrConflict.Code, "Standing order is not active"),
+			PrepareMock: func(db *databaseMocks) {
+				db.StandingOrder.EXPECT().ReadOneByIdForUpdate(gomock.Any(), "standing-order-id").Times(1).Return(readOrder(entity.ORDER_CANCELLED), nil)
+			},
+		},
+		"deve retornar erro: standing order nao encontrada": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrNotFound,
+			PrepareMock: func(db *databaseMocks) {
+				db.StandingOrder.EXPECT().ReadOneByIdForUpdate(gomock.Any(), "standing-order-id").Times(1).Return(nil, echo.ErrNotFound)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			container, db := newDatabaseContainer(ctrl)
+			cs.PrepareMock(db)
+
+			app := NewAppStandingOrder(container, retryPolicy)
+
+			order, err := app.Update(ctx, "standing-order-id", &entity.StandingOrderChanges{Amount: &amount})
+			if diff := cmp.Diff(order, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestCancel(t *testing.T) {
+	startAt := time.Now().Add(24 * time.Hour)
+
+	readOrder := func(state entity.StatesStandingOrder) *entity.StandingOrder {
+		return &entity.StandingOrder{
+			ID:        "standing-order-id",
+			Frequency: entity.DAILY,
+			StartAt:   startAt,
+			NextRunAt: &startAt,
+			State:     state,
+		}
+	}
+
+	cancelled := readOrder(entity.ORDER_CANCELLED)
+	cancelled.NextRunAt = nil
+	cancelled.FrequencyString = "DAILY"
+	cancelled.StateString = "CANCELLED"
+
+	cases := map[string]struct {
+		ExpectedResult *entity.StandingOrder
+		ExpectedErr    error
+		PrepareMock    func(db *databaseMocks)
+	}{
+		"deve retornar sucesso": {
+			ExpectedResult: cancelled,
+			ExpectedErr:    nil,
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.StandingOrder.EXPECT().ReadOneByIdForUpdate(gomock.Any(), "standing-order-id").Times(1).Return(readOrder(entity.ORDER_ACTIVE), nil),
+					db.StandingOrder.EXPECT().Update(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Audit.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).
+						Do(func(ctx context.Context, entry *entity.AuditEntry) {
+							if entry.Operation != entity.AUDIT_STANDING_ORDER_CANCELLED || entry.EntityId != "standing-order-id" {
+								t.Errorf("unexpected audit entry %+v", entry)
+							}
+						}).Return(nil),
+				)
+			},
+		},
+		"deve retornar erro: standing order concluida": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.NewHTTPError(echo.ErrConflict.Code, "Standing order is not active"),
+			PrepareMock: func(db *databaseMocks) {
+				db.StandingOrder.EXPECT().ReadOneByIdForUpdate(gomock.Any(), "standing-order-id").Times(1).Return(readOrder(entity.ORDER_COMPLETED), nil)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			container, db := newDatabaseContainer(ctrl)
+			cs.PrepareMock(db)
+
+			app := NewAppStandingOrder(container, retryPolicy)
+
+			order, err := app.Cancel(ctx, "standing-order-id")
+			if diff := cmp.Diff(order, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestReadOccurrences(t *testing.T) {
+	occurrences := []entity.StandingOrderOccurrence{{
+		StandingOrderId: "standing-order-id",
+		Sequence:        1,
+		TransactionId:   "transaction-id",
+		State:           entity.BOOKED,
+		Attempts:        1,
+	}}
+
+	expected := []entity.StandingOrderOccurrence{occurrences[0]}
+	expected[0].StateString = "BOOKED"
+
+	cases := map[string]struct {
+		ExpectedResult []entity.StandingOrderOccurrence
+		ExpectedErr    error
+		PrepareMock    func(db *databaseMocks)
+	}{
+		"deve retornar sucesso": {
+			ExpectedResult: expected,
+			ExpectedErr:    nil,
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.StandingOrder.EXPECT().ReadOneById(gomock.Any(), "standing-order-id").Times(1).Return(&entity.StandingOrder{}, nil),
+					db.StandingOrder.EXPECT().ReadOccurrences(gomock.Any(), "standing-order-id").Times(1).
+						Return(append([]entity.StandingOrderOccurrence(nil), occurrences...), nil),
+				)
+			},
+		},
+		"deve retornar erro: standing order nao encontrada": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrNotFound,
+			PrepareMock: func(db *databaseMocks) {
+				db.StandingOrder.EXPECT().ReadOneById(gomock.Any(), "standing-order-id").Times(1).Return(nil, echo.ErrNotFound)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			container, db := newDatabaseContainer(ctrl)
+			cs.PrepareMock(db)
+
+			app := NewAppStandingOrder(container, retryPolicy)
+
+			occurrences, err := app.ReadOccurrences(ctx, "standing-order-id")
+			if diff := cmp.Diff(occurrences, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestExecute(t *testing.T) {
+	startAt := time.Now().Add(-time.Minute).Round(0)
+	sourceUserId := "source-user-id"
+	destinationUserId := "destination-user-id"
+
+	readOrder := func(attempts int) *entity.StandingOrder {
+		nextRunAt := startAt
+		return &entity.StandingOrder{
+			ID:            "standing-order-id",
+			SourceId:      sourceUserId,
+			DestinationId: destinationUserId,
+			Amount:        money.New(15000),
+			Frequency:     entity.DAILY,
+			StartAt:       startAt,
+			Attempts:      attempts,
+			NextRunAt:     &nextRunAt,
+			State:         entity.ORDER_ACTIVE,
+		}
+	}
+
+	readAccount := func(id string, balance int64) *entity.Account {
+		return &entity.Account{ID: id, UserId: id, Balance: money.New(balance), AvailableBalance: money.New(balance)}
+	}
+
+	nextOccurrence := startAt.AddDate(0, 0, 1)
+
+	// payment keeps the transaction created for the occurrence, so the
+	// occurrence can be checked against it.
+	var payment *entity.Transaction
+	createPayment := func(ctx context.Context, transaction *entity.Transaction) error {
+		if transaction.SourceId != sourceUserId || transaction.DestinationId != destinationUserId || transaction.Amount != money.New(15000) {
+			t.Errorf("unexpected transaction %+v", transaction)
+		}
+		payment = transaction
+		return nil
+	}
+
+	expectOccurrence := func(state entity.StatesTransaction, attempts int, reason string) func(ctx context.Context, occurrence *entity.StandingOrderOccurrence) error {
+		return func(ctx context.Context, occurrence *entity.StandingOrderOccurrence) error {
+			expected := &entity.StandingOrderOccurrence{
+				StandingOrderId: "standing-order-id",
+				Sequence:        1,
+				TransactionId:   payment.ID,
+				State:           state,
+				StateString:     state.String(),
+				Attempts:        attempts,
+				Reason:          reason,
+				ScheduledAt:     startAt,
+			}
+			if diff := cmp.Diff(occurrence, expected); diff != "" {
+				t.Error(diff)
+			}
+			return nil
+		}
+	}
+
+	cases := map[string]struct {
+		PrepareMock func(db *databaseMocks)
+		ExpectedErr error
+	}{
+		"deve retornar sucesso": {
+			ExpectedErr: nil,
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.StandingOrder.EXPECT().ReadDue(gomock.Any(), gomock.Any()).Times(1).Return([]entity.StandingOrder{*readOrder(0)}, nil),
+					db.StandingOrder.EXPECT().ReadOneByIdForUpdate(gomock.Any(), "standing-order-id").Times(1).Return(readOrder(0), nil),
+					db.Transaction.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(createPayment),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), destinationUserId).Times(1).Return(readAccount(destinationUserId, 0), nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), sourceUserId).Times(1).Return(readAccount(sourceUserId, 20000), nil),
+					db.Account.EXPECT().UpdateBalance(gomock.Any(), sourceUserId, money.New(5000)).Times(1).Return(nil),
+					db.Account.EXPECT().UpdateBalance(gomock.Any(), destinationUserId, money.New(15000)).Times(1).Return(nil),
+					db.Ledger.EXPECT().CreatePostings(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Transaction.EXPECT().UpdateState(gomock.Any(), entity.BOOKED, gomock.Any()).Times(1).Return(nil),
+					db.StateHistory.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Outbox.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Account.EXPECT().ReadOneById(gomock.Any(), sourceUserId).Times(1).Return(readAccount(sourceUserId, 5000), nil),
+					db.Limit.EXPECT().ReadWithDefault(gomock.Any(), sourceUserId).Times(1).Return([]entity.Limits{}, nil),
+					db.Fee.EXPECT().ReadActive(gomock.Any()).Times(1).Return([]entity.FeeRule{}, nil),
+					db.StandingOrder.EXPECT().CreateOccurrence(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(expectOccurrence(entity.BOOKED, 1, "")),
+					db.StandingOrder.EXPECT().Update(gomock.Any(), gomock.Any()).Times(1).
+						DoAndReturn(func(ctx context.Context, order *entity.StandingOrder) error {
+							if order.Occurrences != 1 || !order.NextRunAt.Equal(nextOccurrence) {
+								t.Errorf("unexpected order %+v", order)
+							}
+							return nil
+						}),
+				)
+			},
+		},
+		"deve agendar nova tentativa: 'Insufficient balance'": {
+			ExpectedErr: nil,
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.StandingOrder.EXPECT().ReadDue(gomock.Any(), gomock.Any()).Times(1).Return([]entity.StandingOrder{*readOrder(0)}, nil),
+					db.StandingOrder.EXPECT().ReadOneByIdForUpdate(gomock.Any(), "standing-order-id").Times(1).Return(readOrder(0), nil),
+					db.Transaction.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(createPayment),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), destinationUserId).Times(1).Return(readAccount(destinationUserId, 0), nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), sourceUserId).Times(1).Return(readAccount(sourceUserId, 100), nil),
+					db.StandingOrder.EXPECT().ReadOneByIdForUpdate(gomock.Any(), "standing-order-id").Times(1).Return(readOrder(0), nil),
+					db.Transaction.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).
+						DoAndReturn(func(ctx context.Context, transaction *entity.Transaction) error {
+							if transaction != payment || transaction.State != entity.FAILED {
+								t.Errorf("unexpected transaction %+v", transaction)
+							}
+							return nil
+						}),
+					db.StateHistory.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Outbox.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.StandingOrder.EXPECT().Update(gomock.Any(), gomock.Any()).Times(1).
+						DoAndReturn(func(ctx context.Context, order *entity.StandingOrder) error {
+							if order.Occurrences != 0 || order.Attempts != 1 || !order.NextRunAt.After(time.Now()) {
+								t.Errorf("unexpected order %+v", order)
+							}
+							return nil
+						}),
+				)
+			},
+		},
+		"deve registrar falha: tentativas esgotadas": {
+			ExpectedErr: nil,
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.StandingOrder.EXPECT().ReadDue(gomock.Any(), gomock.Any()).Times(1).Return([]entity.StandingOrder{*readOrder(2)}, nil),
+					db.StandingOrder.EXPECT().ReadOneByIdForUpdate(gomock.Any(), "standing-order-id").Times(1).Return(readOrder(2), nil),
+					db.Transaction.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(createPayment),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), destinationUserId).Times(1).Return(readAccount(destinationUserId, 0), nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), sourceUserId).Times(1).Return(readAccount(sourceUserId, 100), nil),
+					db.StandingOrder.EXPECT().ReadOneByIdForUpdate(gomock.Any(), "standing-order-id").Times(1).Return(readOrder(2), nil),
+					db.Transaction.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.StateHistory.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Outbox.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.StandingOrder.EXPECT().CreateOccurrence(gomock.Any(), gomock.Any()).Times(1).
+						DoAndReturn(expectOccurrence(entity.FAILED, 3, transaction.ErrInsufficientBalance.Error())),
+					db.StandingOrder.EXPECT().Update(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+				)
+			},
+		},
+		"deve registrar falha: erro sem nova tentativa": {
+			ExpectedErr: nil,
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.StandingOrder.EXPECT().ReadDue(gomock.Any(), gomock.Any()).Times(1).Return([]entity.StandingOrder{*readOrder(0)}, nil),
+					db.StandingOrder.EXPECT().ReadOneByIdForUpdate(gomock.Any(), "standing-order-id").Times(1).Return(readOrder(0), nil),
+					db.Transaction.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(createPayment),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), destinationUserId).Times(1).Return(nil, echo.ErrNotFound),
+					db.StandingOrder.EXPECT().ReadOneByIdForUpdate(gomock.Any(), "standing-order-id").Times(1).Return(readOrder(0), nil),
+					db.Transaction.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.StateHistory.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Outbox.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.StandingOrder.EXPECT().CreateOccurrence(gomock.Any(), gomock.Any()).Times(1).
+						DoAndReturn(expectOccurrence(entity.FAILED, 1, echo.ErrNotFound.Error())),
+					db.StandingOrder.EXPECT().Update(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+				)
+			},
+		},
+		"deve desfazer o pagamento: erro ao registrar a ocorrencia": {
+			ExpectedErr: nil,
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.StandingOrder.EXPECT().ReadDue(gomock.Any(), gomock.Any()).Times(1).Return([]entity.StandingOrder{*readOrder(0)}, nil),
+					db.StandingOrder.EXPECT().ReadOneByIdForUpdate(gomock.Any(), "standing-order-id").Times(1).Return(readOrder(0), nil),
+					db.Transaction.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(createPayment),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), destinationUserId).Times(1).Return(readAccount(destinationUserId, 0), nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), sourceUserId).Times(1).Return(readAccount(sourceUserId, 20000), nil),
+					db.Account.EXPECT().UpdateBalance(gomock.Any(), sourceUserId, money.New(5000)).Times(1).Return(nil),
+					db.Account.EXPECT().UpdateBalance(gomock.Any(), destinationUserId, money.New(15000)).Times(1).Return(nil),
+					db.Ledger.EXPECT().CreatePostings(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Transaction.EXPECT().UpdateState(gomock.Any(), entity.BOOKED, gomock.Any()).Times(1).Return(nil),
+					db.StateHistory.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Outbox.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Account.EXPECT().ReadOneById(gomock.Any(), sourceUserId).Times(1).Return(readAccount(sourceUserId, 5000), nil),
+					db.Limit.EXPECT().ReadWithDefault(gomock.Any(), sourceUserId).Times(1).Return([]entity.Limits{}, nil),
+					db.Fee.EXPECT().ReadActive(gomock.Any()).Times(1).Return([]entity.FeeRule{}, nil),
+					db.StandingOrder.EXPECT().CreateOccurrence(gomock.Any(), gomock.Any()).Times(1).Return(echo.ErrInternalServerError),
+				)
+			},
+		},
+		"deve ignorar standing order ja executada": {
+			ExpectedErr: nil,
+			PrepareMock: func(db *databaseMocks) {
+				executed := readOrder(0)
+				executed.NextRunAt = &nextOccurrence
+
+				gomock.InOrder(
+					db.StandingOrder.EXPECT().ReadDue(gomock.Any(), gomock.Any()).Times(1).Return([]entity.StandingOrder{*readOrder(0)}, nil),
+					db.StandingOrder.EXPECT().ReadOneByIdForUpdate(gomock.Any(), "standing-order-id").Times(1).Return(executed, nil),
+				)
+			},
+		},
+		"deve retornar erro": {
+			ExpectedErr: echo.ErrInternalServerError,
+			PrepareMock: func(db *databaseMocks) {
+				db.StandingOrder.EXPECT().ReadDue(gomock.Any(), gomock.Any()).Times(1).Return(nil, echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			container, db := newDatabaseContainer(ctrl)
+			db.Audit.EXPECT().Create(gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
+			cs.PrepareMock(db)
+
+			app := NewAppStandingOrder(container, retryPolicy)
+
+			err := app.Execute(ctx)
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,351 @@
+package stream
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/garoque/backend-code-challenge-snapfi/internal/database"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// BatchSize is how many events are read from the outbox at a time.
+	BatchSize = 100
+	// SubscriberBuffer is how many events a stream may fall behind before it
+	// is closed, so a slow client can't hold the others back.
+	SubscriberBuffer = 64
+	// GapTimeout is how long an event skipped in the outbox sequence is waited
+	// for. Sequences are taken when events are written but the events are
+	// only read once their transaction commits, and rolled back transactions
+	// leave gaps for good.
+	GapTimeout = time.Minute
+	// MaxGaps is how many skipped sequences are waited for at a time.
+	MaxGaps = 1000
+)
+
+// DefaultHeartbeat is how often an idle stream sends a comment, so proxies
+// and clients don't take the connection for dead.
+const DefaultHeartbeat = 15 * time.Second
+
+// ErrStreamLagging closes a stream that fell SubscriberBuffer events behind.
+// The client resumes it from the last event it received.
+var ErrStreamLagging = errors.New("the stream fell behind the events")
+
+type AppStreamInterface interface {
+	Stream(ctx context.Context, userId string, lastEventId *int64, send func(entity.StreamMessage) error) error
+	Poll(ctx context.Context) error
+}
+
+type subscriber struct {
+	events chan entity.Event
+}
+
+type appStreamImpl struct {
+	db        *database.Container
+	heartbeat time.Duration
+
+	mu          sync.Mutex
+	subscribers map[string]map[*subscriber]struct{}
+
+	tail *tail
+	// owners caches the user owning each account the events concern, since
+	// accounts never change hands, or "" for accounts that don't exist. Only
+	// Poll reads and writes it.
+	owners map[string]string
+}
+
+func NewAppStream(db *database.Container, heartbeat time.Duration) AppStreamInterface {
+	return &appStreamImpl{
+		db:          db,
+		heartbeat:   heartbeat,
+		subscribers: make(map[string]map[*subscriber]struct{}),
+		owners:      make(map[string]string),
+	}
+}
+
+// Stream sends the events of the transactions of the accounts of the user,
+// each followed by the balances of the accounts that changed, until ctx is
+// done. The stream starts with the events written after lastEventId, when
+// given, and the current balance of every account.
+// Events may be sent more than once, so clients must deduplicate them by ID.
+func (s *appStreamImpl) Stream(ctx context.Context, userId string, lastEventId *int64, send func(entity.StreamMessage) error) error {
+	_, err := s.db.User.ReadOneById(ctx, userId)
+	if err != nil {
+		log.Println("Error app.Stream.Stream.db.ReadOneById: ", err.Error())
+		return err
+	}
+
+	// Subscribing before reading the missed events leaves no window for an
+	// event to be in neither, at the cost of some being in both.
+	sub := s.subscribe(userId)
+	defer s.unsubscribe(userId, sub)
+
+	sent := make(map[int64]bool)
+	if lastEventId != nil {
+		after := *lastEventId
+		for {
+			events, err := s.db.Outbox.ReadUserEventsAfter(ctx, userId, after, BatchSize)
+			if err != nil {
+				log.Println("Error app.Stream.Stream.db.ReadUserEventsAfter: ", err.Error())
+				return err
+			}
+
+			for _, event := range events {
+				if err := send(eventMessage(event)); err != nil {
+					return err
+				}
+				sent[event.Sequence] = true
+			}
+
+			if len(events) < BatchSize {
+				break
+			}
+			after = events[len(events)-1].Sequence
+		}
+	}
+
+	balances := make(map[string]entity.BalanceUpdate)
+	if err := s.sendBalance(ctx, userId, balances, send); err != nil {
+		return err
+	}
+
+	heartbeat := time.NewTicker(s.heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-heartbeat.C:
+			if err := send(entity.StreamMessage{Comment: "heartbeat"}); err != nil {
+				return err
+			}
+		case event, ok := <-sub.events:
+			if !ok {
+				log.Println("Error app.Stream.Stream: ", userId, ErrStreamLagging.Error())
+				return ErrStreamLagging
+			}
+
+			if sent[event.Sequence] {
+				continue
+			}
+
+			if err := send(eventMessage(event)); err != nil {
+				return err
+			}
+
+			if err := s.sendBalance(ctx, userId, balances, send); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// sendBalance sends the balance of each account of the user unless it is the
+// last one sent for that account.
+func (s *appStreamImpl) sendBalance(ctx context.Context, userId string, last map[string]entity.BalanceUpdate, send func(entity.StreamMessage) error) error {
+	accounts, err := s.db.Account.ReadAllByUserId(ctx, userId)
+	if err != nil {
+		log.Println("Error app.Stream.sendBalance.db.Account.ReadAllByUserId: ", err.Error())
+		return err
+	}
+
+	for i := range accounts {
+		balance := entity.NewBalanceUpdate(&accounts[i])
+		if sent, ok := last[balance.AccountId]; ok && sent == balance {
+			continue
+		}
+		last[balance.AccountId] = balance
+
+		if err := send(entity.StreamMessage{Event: entity.STREAM_EVENT_BALANCE, Data: balance}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func eventMessage(event entity.Event) entity.StreamMessage {
+	return entity.StreamMessage{
+		ID:    strconv.FormatInt(event.Sequence, 10),
+		Event: entity.STREAM_EVENT_TRANSACTION,
+		Data:  event,
+	}
+}
+
+func (s *appStreamImpl) subscribe(userId string) *subscriber {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sub := &subscriber{events: make(chan entity.Event, SubscriberBuffer)}
+	if s.subscribers[userId] == nil {
+		s.subscribers[userId] = make(map[*subscriber]struct{})
+	}
+	s.subscribers[userId][sub] = struct{}{}
+
+	return sub
+}
+
+func (s *appStreamImpl) unsubscribe(userId string, sub *subscriber) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.subscribers[userId], sub)
+	if len(s.subscribers[userId]) == 0 {
+		delete(s.subscribers, userId)
+	}
+}
+
+// dispatch hands the event to the streams of the owners of the accounts it
+// concerns, closing the ones that fell behind. The owners must have been
+// resolved first.
+func (s *appStreamImpl) dispatch(event entity.Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	userIds := make(map[string]bool)
+	for _, accountId := range entity.EventAccountIds(event) {
+		userId, ok := s.owners[accountId]
+		if !ok || userId == "" || userIds[userId] {
+			continue
+		}
+		userIds[userId] = true
+
+		for sub := range s.subscribers[userId] {
+			select {
+			case sub.events <- event:
+			default:
+				close(sub.events)
+				delete(s.subscribers[userId], sub)
+			}
+		}
+	}
+}
+
+// Poll reads the events written to the outbox since the last run and hands
+// them to the streams of this instance. Every instance follows the outbox
+// itself, so the streams see the events written through any of them. The
+// first run starts from the last event written, since streams read the
+// events they missed themselves.
+func (s *appStreamImpl) Poll(ctx context.Context) error {
+	if s.tail == nil {
+		sequence, err := s.db.Outbox.ReadLastSequence(ctx)
+		if err != nil {
+			log.Println("Error app.Stream.Poll.db.ReadLastSequence: ", err.Error())
+			return err
+		}
+
+		s.tail = newTail(sequence)
+		return nil
+	}
+
+	after := s.tail.from()
+	for {
+		events, err := s.db.Outbox.ReadAfter(ctx, after, BatchSize)
+		if err != nil {
+			log.Println("Error app.Stream.Poll.db.ReadAfter: ", err.Error())
+			return err
+		}
+
+		// Resolving the owners before advancing lets a failed lookup read
+		// the events again on the next run.
+		if err := s.resolveOwners(ctx, events); err != nil {
+			return err
+		}
+
+		for _, event := range s.tail.advance(events, time.Now()) {
+			s.dispatch(event)
+		}
+
+		if len(events) < BatchSize {
+			return nil
+		}
+		after = events[len(events)-1].Sequence
+	}
+}
+
+// resolveOwners caches the owners of the accounts the events concern. Failed
+// transactions may name accounts that don't exist, which have no owner to
+// hand their events to.
+func (s *appStreamImpl) resolveOwners(ctx context.Context, events []entity.Event) error {
+	for _, event := range events {
+		for _, accountId := range entity.EventAccountIds(event) {
+			if _, ok := s.owners[accountId]; ok {
+				continue
+			}
+
+			account, err := s.db.Account.ReadOneById(ctx, accountId)
+			if errors.Is(err, echo.ErrNotFound) {
+				s.owners[accountId] = ""
+				continue
+			}
+
+			if err != nil {
+				log.Println("Error app.Stream.resolveOwners.db.Account.ReadOneById: ", err.Error())
+				return err
+			}
+			s.owners[accountId] = account.UserId
+		}
+	}
+
+	return nil
+}
+
+// tail follows the outbox by sequence. An event may become visible after
+// events written later than it, so the sequences skipped are read again
+// until they show up or GapTimeout passes.
+type tail struct {
+	cursor int64
+	gaps   map[int64]time.Time
+}
+
+func newTail(cursor int64) *tail {
+	return &tail{cursor: cursor, gaps: make(map[int64]time.Time)}
+}
+
+// from returns the sequence to read the outbox after.
+func (t *tail) from() int64 {
+	from := t.cursor
+	for sequence := range t.gaps {
+		if sequence-1 < from {
+			from = sequence - 1
+		}
+	}
+
+	return from
+}
+
+// advance returns the events not returned before, in the order read, and
+// records the sequences skipped up to them.
+func (t *tail) advance(events []entity.Event, now time.Time) []entity.Event {
+	unread := make([]entity.Event, 0, len(events))
+	for _, event := range events {
+		if event.Sequence > t.cursor {
+			for sequence := t.cursor + 1; sequence < event.Sequence && len(t.gaps) < MaxGaps; sequence++ {
+				t.gaps[sequence] = now
+			}
+
+			t.cursor = event.Sequence
+			unread = append(unread, event)
+			continue
+		}
+
+		if _, ok := t.gaps[event.Sequence]; ok {
+			delete(t.gaps, event.Sequence)
+			unread = append(unread, event)
+		}
+	}
+
+	for sequence, since := range t.gaps {
+		if now.Sub(since) > GapTimeout {
+			delete(t.gaps, sequence)
+		}
+	}
+
+	return unread
+}
@@ -0,0 +1,261 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/garoque/backend-code-challenge-snapfi/internal/database"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/mocks"
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/money"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func newEvent(sequence int64, sourceId, destinationId string) entity.Event {
+	payload, _ := json.Marshal(entity.TransactionEventPayload{Transaction: entity.Transaction{SourceId: sourceId, DestinationId: destinationId}})
+
+	return entity.Event{
+		Sequence:      sequence,
+		ID:            "event-id",
+		Type:          "transaction.booked",
+		AggregateType: entity.AGGREGATE_TRANSACTION,
+		AggregateId:   "transaction-id",
+		Payload:       payload,
+	}
+}
+
+func sequences(events []entity.Event) []int64 {
+	result := make([]int64, 0, len(events))
+	for _, event := range events {
+		result = append(result, event.Sequence)
+	}
+
+	return result
+}
+
+func TestTail(t *testing.T) {
+	now := time.Date(2023, 5, 23, 9, 0, 0, 0, time.UTC)
+	tail := newTail(10)
+
+	unread := tail.advance([]entity.Event{newEvent(11, "a", "b"), newEvent(13, "a", "b"), newEvent(14, "a", "b")}, now)
+	assert.Equal(t, []int64{11, 13, 14}, sequences(unread))
+	assert.Equal(t, int64(11), tail.from())
+
+	unread = tail.advance([]entity.Event{newEvent(12, "a", "b"), newEvent(13, "a", "b"), newEvent(14, "a", "b"), newEvent(15, "a", "b")}, now.Add(time.Second))
+	assert.Equal(t, []int64{12, 15}, sequences(unread))
+	assert.Equal(t, int64(15), tail.from())
+
+	tail.advance([]entity.Event{newEvent(17, "a", "b")}, now.Add(2*time.Second))
+	assert.Equal(t, int64(15), tail.from())
+
+	tail.advance([]entity.Event{newEvent(17, "a", "b")}, now.Add(2*time.Second+GapTimeout+time.Second))
+	assert.Equal(t, int64(17), tail.from())
+}
+
+func TestPoll(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	events := []entity.Event{newEvent(11, "account-id", "other-account-id"), newEvent(12, "other-account-id", "savings-id"), newEvent(13, "account-id", "savings-id")}
+
+	mockOutboxDb := mocks.NewMockDabataseOutboxInterface(ctrl)
+	mockAccountDb := mocks.NewMockDabataseAccountInterface(ctrl)
+	gomock.InOrder(
+		mockOutboxDb.EXPECT().ReadLastSequence(gomock.Any()).Times(1).Return(int64(10), nil),
+		mockOutboxDb.EXPECT().ReadAfter(gomock.Any(), int64(10), BatchSize).Times(1).Return(events, nil),
+		mockAccountDb.EXPECT().ReadOneById(gomock.Any(), "account-id").Times(1).Return(nil, echo.ErrInternalServerError),
+		mockOutboxDb.EXPECT().ReadAfter(gomock.Any(), int64(10), BatchSize).Times(1).Return(events, nil),
+		mockAccountDb.EXPECT().ReadOneById(gomock.Any(), "account-id").Times(1).Return(&entity.Account{ID: "account-id", UserId: "user-id"}, nil),
+		mockAccountDb.EXPECT().ReadOneById(gomock.Any(), "other-account-id").Times(1).Return(&entity.Account{ID: "other-account-id", UserId: "other-id"}, nil),
+		mockAccountDb.EXPECT().ReadOneById(gomock.Any(), "savings-id").Times(1).Return(&entity.Account{ID: "savings-id", UserId: "user-id"}, nil),
+		mockOutboxDb.EXPECT().ReadAfter(gomock.Any(), int64(13), BatchSize).Times(1).Return(nil, echo.ErrInternalServerError),
+	)
+
+	app := NewAppStream(&database.Container{Outbox: mockOutboxDb, Account: mockAccountDb}, DefaultHeartbeat).(*appStreamImpl)
+	sub := app.subscribe("user-id")
+
+	assert.NoError(t, app.Poll(ctx))
+	assert.Equal(t, echo.ErrInternalServerError, app.Poll(ctx))
+	assert.Len(t, sub.events, 0)
+
+	assert.NoError(t, app.Poll(ctx))
+	assert.Equal(t, echo.ErrInternalServerError, app.Poll(ctx))
+
+	assert.Len(t, sub.events, 3)
+	assert.Equal(t, int64(11), (<-sub.events).Sequence)
+	assert.Equal(t, int64(12), (<-sub.events).Sequence)
+	assert.Equal(t, int64(13), (<-sub.events).Sequence)
+}
+
+func TestPollMissingAccount(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	events := []entity.Event{newEvent(11, "account-id", "missing-id"), newEvent(12, "missing-id", "account-id")}
+
+	mockOutboxDb := mocks.NewMockDabataseOutboxInterface(ctrl)
+	mockAccountDb := mocks.NewMockDabataseAccountInterface(ctrl)
+	gomock.InOrder(
+		mockOutboxDb.EXPECT().ReadLastSequence(gomock.Any()).Times(1).Return(int64(10), nil),
+		mockOutboxDb.EXPECT().ReadAfter(gomock.Any(), int64(10), BatchSize).Times(1).Return(events, nil),
+		mockAccountDb.EXPECT().ReadOneById(gomock.Any(), "account-id").Times(1).Return(&entity.Account{ID: "account-id", UserId: "user-id"}, nil),
+		mockAccountDb.EXPECT().ReadOneById(gomock.Any(), "missing-id").Times(1).Return(nil, echo.ErrNotFound),
+		mockOutboxDb.EXPECT().ReadAfter(gomock.Any(), int64(12), BatchSize).Times(1).Return([]entity.Event{newEvent(13, "missing-id", "missing-id")}, nil),
+	)
+
+	app := NewAppStream(&database.Container{Outbox: mockOutboxDb, Account: mockAccountDb}, DefaultHeartbeat).(*appStreamImpl)
+	sub := app.subscribe("user-id")
+
+	assert.NoError(t, app.Poll(ctx))
+	assert.NoError(t, app.Poll(ctx))
+	assert.NoError(t, app.Poll(ctx))
+
+	assert.Len(t, sub.events, 2)
+	assert.Equal(t, int64(11), (<-sub.events).Sequence)
+	assert.Equal(t, int64(12), (<-sub.events).Sequence)
+	assert.Equal(t, int64(13), app.tail.from())
+}
+
+func TestDispatch(t *testing.T) {
+	app := NewAppStream(&database.Container{}, DefaultHeartbeat).(*appStreamImpl)
+	app.owners["account-id"] = "user-id"
+	sub := app.subscribe("user-id")
+
+	for i := 0; i < SubscriberBuffer; i++ {
+		app.dispatch(newEvent(int64(i+1), "account-id", "other-account-id"))
+	}
+	assert.Len(t, app.subscribers["user-id"], 1)
+
+	app.dispatch(newEvent(SubscriberBuffer+1, "account-id", "other-account-id"))
+	assert.Len(t, app.subscribers["user-id"], 0)
+
+	for range sub.events {
+	}
+	app.unsubscribe("user-id", sub)
+	assert.Empty(t, app.subscribers)
+}
+
+func TestStream(t *testing.T) {
+	user := &entity.User{ID: "user-id"}
+	accounts := []entity.Account{
+		{ID: "account-id", UserId: "user-id", Balance: money.New(1000), AvailableBalance: money.New(1000)},
+		{ID: "savings-id", UserId: "user-id", Balance: money.New(500), AvailableBalance: money.New(500)},
+	}
+	credited := []entity.Account{
+		{ID: "account-id", UserId: "user-id", Balance: money.New(1500), AvailableBalance: money.New(1500)},
+		{ID: "savings-id", UserId: "user-id", Balance: money.New(500), AvailableBalance: money.New(500)},
+	}
+	lastEventId := int64(10)
+
+	cases := map[string]struct {
+		LastEventId      *int64
+		Live             []entity.Event
+		ExpectedMessages []string
+		ExpectedErr      error
+		PrepareMock      func(mockUserDb *mocks.MockDabataseUserInterface, mockAccountDb *mocks.MockDabataseAccountInterface, mockOutboxDb *mocks.MockDabataseOutboxInterface)
+	}{
+		"deve retornar sucesso": {
+			LastEventId:      &lastEventId,
+			Live:             []entity.Event{newEvent(11, "other-account-id", "account-id"), newEvent(12, "other-account-id", "account-id")},
+			ExpectedMessages: []string{"transaction:11", "balance:account-id:10.00", "balance:savings-id:5.00", "transaction:12", "balance:account-id:15.00"},
+			PrepareMock: func(mockUserDb *mocks.MockDabataseUserInterface, mockAccountDb *mocks.MockDabataseAccountInterface, mockOutboxDb *mocks.MockDabataseOutboxInterface) {
+				gomock.InOrder(
+					mockUserDb.EXPECT().ReadOneById(gomock.Any(), "user-id").Times(1).Return(user, nil),
+					mockOutboxDb.EXPECT().ReadUserEventsAfter(gomock.Any(), "user-id", int64(10), BatchSize).Times(1).
+						Return([]entity.Event{newEvent(11, "other-account-id", "account-id")}, nil),
+					mockAccountDb.EXPECT().ReadAllByUserId(gomock.Any(), "user-id").Times(1).Return(accounts, nil),
+					mockAccountDb.EXPECT().ReadAllByUserId(gomock.Any(), "user-id").Times(1).Return(credited, nil),
+				)
+			},
+		},
+		"deve retornar sucesso: sem Last-Event-ID": {
+			Live:             []entity.Event{newEvent(11, "account-id", "other-account-id")},
+			ExpectedMessages: []string{"balance:account-id:10.00", "balance:savings-id:5.00", "transaction:11"},
+			PrepareMock: func(mockUserDb *mocks.MockDabataseUserInterface, mockAccountDb *mocks.MockDabataseAccountInterface, mockOutboxDb *mocks.MockDabataseOutboxInterface) {
+				mockUserDb.EXPECT().ReadOneById(gomock.Any(), "user-id").Times(1).Return(user, nil)
+				mockAccountDb.EXPECT().ReadAllByUserId(gomock.Any(), "user-id").Times(2).Return(accounts, nil)
+			},
+		},
+		"deve retornar erro: usuario nao encontrado": {
+			ExpectedErr: echo.ErrNotFound,
+			PrepareMock: func(mockUserDb *mocks.MockDabataseUserInterface, mockAccountDb *mocks.MockDabataseAccountInterface, mockOutboxDb *mocks.MockDabataseOutboxInterface) {
+				mockUserDb.EXPECT().ReadOneById(gomock.Any(), "user-id").Times(1).Return(nil, echo.ErrNotFound)
+			},
+		},
+		"deve retornar erro": {
+			LastEventId: &lastEventId,
+			ExpectedErr: echo.ErrInternalServerError,
+			PrepareMock: func(mockUserDb *mocks.MockDabataseUserInterface, mockAccountDb *mocks.MockDabataseAccountInterface, mockOutboxDb *mocks.MockDabataseOutboxInterface) {
+				gomock.InOrder(
+					mockUserDb.EXPECT().ReadOneById(gomock.Any(), "user-id").Times(1).Return(user, nil),
+					mockOutboxDb.EXPECT().ReadUserEventsAfter(gomock.Any(), "user-id", int64(10), BatchSize).Times(1).Return(nil, echo.ErrInternalServerError),
+				)
+			},
+		},
+		"deve retornar erro: contas": {
+			ExpectedErr: echo.ErrInternalServerError,
+			PrepareMock: func(mockUserDb *mocks.MockDabataseUserInterface, mockAccountDb *mocks.MockDabataseAccountInterface, mockOutboxDb *mocks.MockDabataseOutboxInterface) {
+				gomock.InOrder(
+					mockUserDb.EXPECT().ReadOneById(gomock.Any(), "user-id").Times(1).Return(user, nil),
+					mockAccountDb.EXPECT().ReadAllByUserId(gomock.Any(), "user-id").Times(1).Return(nil, echo.ErrInternalServerError),
+				)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+
+			mockUserDb := mocks.NewMockDabataseUserInterface(ctrl)
+			mockAccountDb := mocks.NewMockDabataseAccountInterface(ctrl)
+			mockOutboxDb := mocks.NewMockDabataseOutboxInterface(ctrl)
+			cs.PrepareMock(mockUserDb, mockAccountDb, mockOutboxDb)
+
+			app := NewAppStream(&database.Container{User: mockUserDb, Account: mockAccountDb, Outbox: mockOutboxDb}, time.Hour).(*appStreamImpl)
+			app.owners["account-id"] = "user-id"
+			app.owners["other-account-id"] = "other-id"
+
+			messages := make([]string, 0)
+			subscribed := false
+			err := app.Stream(ctx, "user-id", cs.LastEventId, func(message entity.StreamMessage) error {
+				switch data := message.Data.(type) {
+				case entity.Event:
+					messages = append(messages, message.Event+":"+message.ID)
+				case entity.BalanceUpdate:
+					messages = append(messages, message.Event+":"+data.AccountId+":"+data.Balance.String())
+				}
+
+				// Live events are dispatched once the stream is subscribed,
+				// which is before it sends its first balance.
+				if !subscribed && message.Event == entity.STREAM_EVENT_BALANCE {
+					subscribed = true
+					go func() {
+						for _, event := range cs.Live {
+							app.dispatch(event)
+						}
+					}()
+				}
+
+				if len(messages) == len(cs.ExpectedMessages) {
+					cancel()
+				}
+				return nil
+			})
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+
+			if cs.ExpectedErr == nil {
+				if diff := cmp.Diff(messages, cs.ExpectedMessages); diff != "" {
+					t.Error(diff)
+				}
+			}
+		})
+	}
+}
@@ -2,20 +2,56 @@ package transaction
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
-	"sync"
+	"net/http"
+	"sort"
+	"time"
 
+	"github.com/garoque/backend-code-challenge-snapfi/internal/app/audit"
 	"github.com/garoque/backend-code-challenge-snapfi/internal/database"
 	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/money"
 	"github.com/labstack/echo/v4"
 )
 
 type AppTransactionInterface interface {
 	Create(ctx context.Context, transaction *entity.Transaction) (*entity.Transaction, error)
-	IncreaseBalanceUser(ctx context.Context, transaction *entity.TransactionIncreaseBalanceUser) (float64, error)
+	IncreaseBalanceUser(ctx context.Context, transaction *entity.TransactionIncreaseBalanceUser) (money.Money, error)
+	Withdraw(ctx context.Context, transaction *entity.Transaction) (*entity.Transaction, error)
+	Reverse(ctx context.Context, id string, amount money.Money) (*entity.Transaction, error)
 	ReadAll(ctx context.Context) ([]entity.Transaction, error)
+	ReadHistory(ctx context.Context, id string) ([]entity.StateTransition, error)
+	Authorize(ctx context.Context, transaction *entity.Transaction) (*entity.Transaction, error)
+	Capture(ctx context.Context, id string, amount money.Money) (*entity.Transaction, error)
+	Void(ctx context.Context, id string) (*entity.Transaction, error)
+	ExpireAuthorizations(ctx context.Context) error
+	ReadScheduled(ctx context.Context) ([]entity.Transaction, error)
+	CancelScheduled(ctx context.Context, id string) (*entity.Transaction, error)
+	ExecuteScheduled(ctx context.Context) error
+	CreateBatch(ctx context.Context, batch *entity.Batch) (*entity.Batch, error)
+	ReadBatch(ctx context.Context, id string) (*entity.Batch, error)
+	CreateSplit(ctx context.Context, split *entity.Split) (*entity.Split, error)
+	ReadSplit(ctx context.Context, id string) (*entity.Split, error)
+	AccrueInterest(ctx context.Context) error
 }
 
+// ErrInsufficientBalance is returned when the available balance of the source
+// account can't cover a transfer.
+var ErrInsufficientBalance = echo.NewHTTPError(echo.ErrBadRequest.Code, "Insufficient balance")
+
+// ErrCurrencyMismatch is returned when money would move between accounts of
+// different currencies.
+var ErrCurrencyMismatch = echo.NewHTTPError(echo.ErrBadRequest.Code, "Source and destination accounts have different currencies")
+
+// ErrUserFrozen and ErrUserClosed are returned when the owner of an account
+// taking part in a transaction is frozen or closed.
+var (
+	ErrUserFrozen = echo.NewHTTPError(http.StatusConflict, "User account is frozen")
+	ErrUserClosed = echo.NewHTTPError(http.StatusConflict, "User account is closed")
+)
+
 type appTransactionImpl struct {
 	db *database.Container
 }
@@ -25,132 +61,745 @@ func NewAppTransaction(db *database.Container) AppTransactionInterface {
 }
 
 func (tr *appTransactionImpl) Create(ctx context.Context, transaction *entity.Transaction) (*entity.Transaction, error) {
-	err := tr.db.Transaction.Create(ctx, transaction)
+	if transaction.SourceId == transaction.DestinationId {
+		return nil, echo.NewHTTPError(echo.ErrBadRequest.Code, "Source and destination accounts must be different")
+	}
+
+	if namesSystemAccount(transaction) {
+		return nil, echo.NewHTTPError(echo.ErrBadRequest.Code, "System accounts can't be the source or destination of a transfer")
+	}
+
+	if transaction.ExecuteAt != nil {
+		return tr.schedule(ctx, transaction)
+	}
+
+	var fee *entity.Transaction
+	err := tr.db.UnitOfWork.Do(ctx, func(tx *database.Container) error {
+		var err error
+		fee, err = Transfer(ctx, tx, transaction)
+		return err
+	})
+	if err != nil {
+		tr.registerFailedTransaction(ctx, transaction, err)
+		return transaction, err
+	}
+
+	transaction.KindString = transaction.Kind.String()
+	transaction.Fee = fee
+
+	return transaction, nil
+}
+
+// Transfer stores and books the transfer, fee included, within the unit of
+// work of the caller, so it commits or rolls back along with whatever else the
+// caller stores. It returns the fee charged, if any.
+func Transfer(ctx context.Context, tx *database.Container, transaction *entity.Transaction) (*entity.Transaction, error) {
+	err := createTransaction(ctx, tx, transaction)
 	if err != nil {
-		log.Println("Error app.Transaction.Create.db.Create: ", err.Error())
+		log.Println("Error app.Transaction.Transfer.createTransaction: ", err.Error())
 		return nil, err
 	}
 
-	sourceUser, err := tr.db.User.ReadOneById(ctx, transaction.SourceId)
+	return bookTransfer(ctx, tx, transaction, "transfer booked")
+}
+
+// book moves the money of a stored transfer and marks it BOOKED.
+func book(ctx context.Context, tx *database.Container, transaction *entity.Transaction, reason string) error {
+	err := transferBalance(ctx, tx, transaction)
 	if err != nil {
-		tr.updateStatusTransaction(ctx, transaction, 2)
+		log.Println("Error app.Transaction.book.transferBalance: ", err.Error())
+		return err
+	}
 
-		log.Println("Error app.Transaction.Create.db.ReadOneById.sourceId: ", err.Error())
-		return transaction, err
+	err = tx.Ledger.CreatePostings(ctx, entity.NewPostings(transaction))
+	if err != nil {
+		log.Println("Error app.Transaction.book.db.CreatePostings: ", err.Error())
+		return err
 	}
 
-	destinationUser, err := tr.db.User.ReadOneById(ctx, transaction.DestinationId)
+	return transitionState(ctx, tx, transaction, entity.BOOKED, reason)
+}
+
+// bookWithinLimits books the transfer and then checks it against the limits
+// of its source account. Checking after book means the row of the source
+// account is already locked, so concurrent transfers of the same account are
+// checked one after the other, and the sums read back include the transfer
+// itself.
+func bookWithinLimits(ctx context.Context, tx *database.Container, transaction *entity.Transaction, reason string) error {
+	err := book(ctx, tx, transaction, reason)
 	if err != nil {
-		tr.updateStatusTransaction(ctx, transaction, 2)
+		return err
+	}
 
-		log.Println("Error app.Transaction.Create.db.ReadOneById.destinationId: ", err.Error())
-		return transaction, err
+	return checkLimits(ctx, tx, transaction, time.Now())
+}
+
+// bookTransfer books the transfer within the limits of its source and charges
+// its fee in the same unit of work, so a transfer costs the same whether it is
+// booked on its own, in a batch or split, or once its schedule is due.
+func bookTransfer(ctx context.Context, tx *database.Container, transaction *entity.Transaction, reason string) (*entity.Transaction, error) {
+	err := bookWithinLimits(ctx, tx, transaction, reason)
+	if err != nil {
+		return nil, err
+	}
+
+	return chargeFee(ctx, tx, transaction, transaction.SourceId)
+}
+
+// checkLimits fails when the booked transfer takes the owner of its source
+// account past one of their limits, saying which one and how much could still
+// be sent. Limits are per user, so the totals add up what all their accounts
+// sent; the source account is locked, so concurrent transfers from it are
+// checked one after the other.
+func checkLimits(ctx context.Context, tx *database.Container, transaction *entity.Transaction, now time.Time) error {
+	source, err := tx.Account.ReadOneById(ctx, transaction.SourceId)
+	if err != nil {
+		log.Println("Error app.Transaction.checkLimits.db.Account.ReadOneById: ", err.Error())
+		return err
+	}
+
+	stored, err := tx.Limit.ReadWithDefault(ctx, source.UserId)
+	if err != nil {
+		log.Println("Error app.Transaction.checkLimits.db.Limit.ReadWithDefault: ", err.Error())
+		return err
+	}
+
+	limits := entity.ResolveLimits(source.UserId, stored)
+
+	sentToday := money.New(0)
+	if limits.Daily != nil {
+		sentToday, err = sentBefore(ctx, tx, source.UserId, transaction, entity.StartOfDay(now))
+		if err != nil {
+			return err
+		}
 	}
 
-	sourceUser.Mutex = &sync.Mutex{}
-	destinationUser.Mutex = &sync.Mutex{}
+	sentThisMonth := money.New(0)
+	if limits.Monthly != nil {
+		sentThisMonth, err = sentBefore(ctx, tx, source.UserId, transaction, entity.StartOfMonth(now))
+		if err != nil {
+			return err
+		}
+	}
 
-	sourceUser.Mutex.Lock()
-	destinationUser.Mutex.Lock()
-	defer sourceUser.Mutex.Unlock()
-	defer destinationUser.Mutex.Unlock()
+	exceeded := limits.Check(transaction.Amount, sentToday, sentThisMonth, now)
+	if exceeded != nil {
+		log.Println("Error app.Transaction.checkLimits: ", exceeded.Error())
+		return echo.NewHTTPError(echo.ErrBadRequest.Code, exceeded.Error())
+	}
 
-	if sourceUser.Balance < transaction.Amount {
-		tr.updateStatusTransaction(ctx, transaction, 2)
+	return nil
+}
 
-		log.Println("Error app.Transaction.Create sourceUser.Balance < transaction.Amount Insufficient balance")
-		return transaction, echo.NewHTTPError(echo.ErrBadRequest.Code, "Insufficient balance")
+// sentBefore returns what the accounts of the user sent since the given time,
+// not counting the transfer being checked.
+func sentBefore(ctx context.Context, tx *database.Container, userId string, transaction *entity.Transaction, since time.Time) (money.Money, error) {
+	sent, err := tx.Transaction.ReadSentAmount(ctx, userId, since)
+	if err != nil {
+		log.Println("Error app.Transaction.sentBefore.db.ReadSentAmount: ", err.Error())
+		return money.Money{}, err
 	}
 
-	sourceUser.Balance -= transaction.Amount
-	err = tr.db.Transaction.UpdateBalanceUser(ctx, sourceUser.ID, sourceUser.Balance)
+	return money.New(sent.Amount - transaction.Amount.Amount), nil
+}
+
+// chargeFee books the fee of the most specific active rule matching the
+// transaction, paid by payerId into the platform fee account, and returns it,
+// or nil when no rule applies. It runs in the unit of work of the transaction
+// so both are booked or rolled back together.
+func chargeFee(ctx context.Context, tx *database.Container, transaction *entity.Transaction, payerId string) (*entity.Transaction, error) {
+	rules, err := tx.Fee.ReadActive(ctx)
 	if err != nil {
-		tr.revertSourceBalanceTransaction(ctx, sourceUser, transaction.Amount)
-		tr.updateStatusTransaction(ctx, transaction, 2)
+		log.Println("Error app.Transaction.chargeFee.db.Fee.ReadActive: ", err.Error())
+		return nil, err
+	}
 
-		log.Println("Error app.Transaction.Create.db.UpdateBalanceUser.sourceUser: ", err.Error())
-		return transaction, err
+	if len(rules) == 0 {
+		return nil, nil
 	}
 
-	destinationUser.Balance += transaction.Amount
-	err = tr.db.Transaction.UpdateBalanceUser(ctx, destinationUser.ID, destinationUser.Balance)
+	payer, err := tx.Account.ReadOneById(ctx, payerId)
 	if err != nil {
-		tr.revertDestinationBalanceTransaction(ctx, destinationUser, transaction.Amount)
-		tr.revertSourceBalanceTransaction(ctx, sourceUser, transaction.Amount)
-		tr.updateStatusTransaction(ctx, transaction, 2)
+		log.Println("Error app.Transaction.chargeFee.db.Account.ReadOneById: ", err.Error())
+		return nil, err
+	}
 
-		log.Println("Error app.Transaction.Create.db.UpdateBalanceUser.destinationUser: ", err.Error())
-		return transaction, err
+	rule := entity.SelectFeeRule(rules, transaction.Kind, payer.AccountType)
+	if rule == nil {
+		return nil, nil
+	}
+
+	amount := rule.Calculate(transaction.Amount)
+	if !amount.IsPositive() {
+		return nil, nil
 	}
 
-	tr.updateStatusTransaction(ctx, transaction, 1)
+	fee := entity.NewFee(transaction, payerId, amount)
+	err = createTransaction(ctx, tx, fee)
+	if err != nil {
+		log.Println("Error app.Transaction.chargeFee.createTransaction: ", err.Error())
+		return nil, err
+	}
+
+	err = book(ctx, tx, fee, "fee charged by rule "+rule.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	fee.KindString = fee.Kind.String()
+
+	return fee, nil
+}
+
+// schedule stores the transfer as SCHEDULED for ExecuteScheduled to book once
+// its execution date is reached. Only the accounts are checked now, the
+// balance is checked when the transfer runs.
+func (tr *appTransactionImpl) schedule(ctx context.Context, transaction *entity.Transaction) (*entity.Transaction, error) {
+	if !transaction.ExecuteAt.After(time.Now()) {
+		return nil, echo.NewHTTPError(echo.ErrBadRequest.Code, "The execution date must be in the future")
+	}
+
+	err := tr.db.UnitOfWork.Do(ctx, func(tx *database.Container) error {
+		for _, accountId := range []string{transaction.SourceId, transaction.DestinationId} {
+			_, err := tx.Account.ReadOneById(ctx, accountId)
+			if err != nil {
+				log.Println("Error app.Transaction.schedule.db.ReadOneById: ", err.Error())
+				return err
+			}
+		}
+
+		err := createTransaction(ctx, tx, transaction)
+		if err != nil {
+			log.Println("Error app.Transaction.schedule.createTransaction: ", err.Error())
+			return err
+		}
+
+		return transitionState(ctx, tx, transaction, entity.SCHEDULED, "scheduled for "+transaction.ExecuteAt.Format(time.RFC3339))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	transaction.KindString = transaction.Kind.String()
 
 	return transaction, nil
 }
 
-func (tr *appTransactionImpl) updateStatusTransaction(ctx context.Context, transaction *entity.Transaction, state entity.StatesTransaction) {
-	transaction.State = state
-	transaction.StateString = transaction.State.String()
-	tr.db.Transaction.UpdateState(ctx, transaction.State, transaction.ID)
+// lockAccounts reads the given accounts with SELECT ... FOR UPDATE. Rows are
+// always locked in ascending ID order so two concurrent transfers between the
+// same pair of accounts can't deadlock each other.
+func lockAccounts(ctx context.Context, tx *database.Container, accountIds ...string) (map[string]*entity.Account, error) {
+	ids := append([]string(nil), accountIds...)
+	sort.Strings(ids)
+
+	accounts := make(map[string]*entity.Account, len(ids))
+	for _, id := range ids {
+		if _, ok := accounts[id]; ok {
+			continue
+		}
+
+		account, err := tx.Account.ReadOneByIdForUpdate(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		accounts[id] = account
+	}
+
+	return accounts, nil
+}
+
+// checkStatus fails when the owner of a locked party of the transaction can't
+// move money. Closed users take part in nothing. Frozen users can't send,
+// receive or deposit, but reversals, fees and interest still reach them, and
+// their remaining balances can be swept out when they are closed.
+func checkStatus(transaction *entity.Transaction, accounts map[string]*entity.Account) error {
+	for _, id := range []string{transaction.SourceId, transaction.DestinationId} {
+		account, ok := accounts[id]
+		if !ok {
+			continue
+		}
+
+		switch account.Status {
+		case entity.CLOSED:
+			log.Println("Error app.Transaction.checkStatus user is closed: ", account.UserId)
+			return ErrUserClosed
+		case entity.FROZEN:
+			if !reachesFrozen(transaction, id) {
+				log.Println("Error app.Transaction.checkStatus user is frozen: ", account.UserId)
+				return ErrUserFrozen
+			}
+		}
+	}
+
+	return nil
+}
+
+func reachesFrozen(transaction *entity.Transaction, accountId string) bool {
+	switch transaction.Kind {
+	case entity.REVERSAL, entity.FEE, entity.INTEREST:
+		return true
+	case entity.SWEEP:
+		return accountId == transaction.SourceId
+	}
+
+	return false
+}
+
+// checkCurrency fails when the locked source and destination accounts of the
+// transaction hold different currencies. System accounts take any currency.
+func checkCurrency(transaction *entity.Transaction, accounts map[string]*entity.Account) error {
+	source, ok := accounts[transaction.SourceId]
+	if !ok {
+		return nil
+	}
+
+	destination, ok := accounts[transaction.DestinationId]
+	if !ok {
+		return nil
+	}
+
+	if source.Currency != destination.Currency {
+		log.Println("Error app.Transaction.checkCurrency: ", source.Currency, destination.Currency)
+		return ErrCurrencyMismatch
+	}
+
+	return nil
+}
+
+// namesSystemAccount reports whether the transaction takes money from or
+// gives it to a system account. Requests only move money between the accounts
+// of users; system accounts are reached through the kind of the transaction.
+func namesSystemAccount(transaction *entity.Transaction) bool {
+	return entity.IsSystemAccount(transaction.SourceId) || entity.IsSystemAccount(transaction.DestinationId)
+}
+
+// partyIds returns the parties of the transaction that have a balance of their
+// own, leaving system accounts out.
+func partyIds(transaction *entity.Transaction) []string {
+	accountIds := make([]string, 0, 2)
+	for _, id := range []string{transaction.SourceId, transaction.DestinationId} {
+		if !entity.IsSystemAccount(id) {
+			accountIds = append(accountIds, id)
+		}
+	}
+
+	return accountIds
+}
+
+// transferBalance moves the transaction amount from its source account to its
+// destination account, failing when the owner of either of them can't move
+// money, when they hold different currencies or when the available balance of
+// the source plus its credit line can't cover it, so funds held by
+// authorizations can't be spent. Interest is charged even past the credit
+// line. System accounts have no balance of their own, only their ledger
+// postings, so they're skipped.
+func transferBalance(ctx context.Context, tx *database.Container, transaction *entity.Transaction) error {
+	accounts, err := lockAccounts(ctx, tx, partyIds(transaction)...)
+	if err != nil {
+		log.Println("Error app.Transaction.transferBalance.lockAccounts: ", err.Error())
+		return err
+	}
+
+	err = checkStatus(transaction, accounts)
+	if err != nil {
+		return err
+	}
+
+	err = checkCurrency(transaction, accounts)
+	if err != nil {
+		return err
+	}
+
+	if sourceAccount, ok := accounts[transaction.SourceId]; ok {
+		if transaction.Kind != entity.INTEREST && !sourceAccount.CanSpend(transaction.Amount) {
+			log.Println("Error app.Transaction.transferBalance sourceAccount.CanSpend(transaction.Amount) Insufficient balance")
+			return ErrInsufficientBalance
+		}
+
+		sourceBalance, err := sourceAccount.Balance.Sub(transaction.Amount)
+		if err != nil {
+			log.Println("Error app.Transaction.transferBalance.sourceAccount.Balance.Sub: ", err.Error())
+			return moneyError(err)
+		}
+
+		err = updateBalance(ctx, tx, sourceAccount, sourceBalance)
+		if err != nil {
+			log.Println("Error app.Transaction.transferBalance.updateBalance.sourceAccount: ", err.Error())
+			return err
+		}
+	}
+
+	if destinationAccount, ok := accounts[transaction.DestinationId]; ok {
+		destinationBalance, err := destinationAccount.Balance.Add(transaction.Amount)
+		if err != nil {
+			log.Println("Error app.Transaction.transferBalance.destinationAccount.Balance.Add: ", err.Error())
+			return moneyError(err)
+		}
+
+		err = updateBalance(ctx, tx, destinationAccount, destinationBalance)
+		if err != nil {
+			log.Println("Error app.Transaction.transferBalance.updateBalance.destinationAccount: ", err.Error())
+			return err
+		}
+	}
+
+	return nil
+}
+
+// transitionState moves the transaction to the next state, stores it and
+// records the change in the transaction's state history.
+func transitionState(ctx context.Context, tx *database.Container, transaction *entity.Transaction, next entity.StatesTransaction, reason string) error {
+	transition, err := transaction.TransitionTo(next, reason)
+	if err != nil {
+		log.Println("Error app.Transaction.transitionState.TransitionTo: ", err.Error())
+		return stateError(err)
+	}
+
+	err = tx.Transaction.UpdateState(ctx, next, transaction.ID)
+	if err != nil {
+		log.Println("Error app.Transaction.transitionState.db.UpdateState: ", err.Error())
+		return err
+	}
+
+	err = tx.StateHistory.Create(ctx, transition)
+	if err != nil {
+		log.Println("Error app.Transaction.transitionState.db.StateHistory.Create: ", err.Error())
+		return err
+	}
+
+	err = publish(ctx, tx, transaction, transition)
+	if err != nil {
+		log.Println("Error app.Transaction.transitionState.publish: ", err.Error())
+		return err
+	}
+
+	before := entity.AuditState{State: transition.From.String()}
+	after := entity.AuditState{State: transition.To.String(), Reason: reason}
+	err = audit.Record(ctx, tx, entity.TransactionEventType(next), entity.AGGREGATE_TRANSACTION, transaction.ID, before, after)
+	if err != nil {
+		log.Println("Error app.Transaction.transitionState.audit.Record: ", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// createTransaction stores the transaction and records its creation in the
+// audit log.
+func createTransaction(ctx context.Context, tx *database.Container, transaction *entity.Transaction) error {
+	err := tx.Transaction.Create(ctx, transaction)
+	if err != nil {
+		return err
+	}
+
+	created := *transaction
+	created.KindString = transaction.Kind.String()
+	created.StateString = transaction.State.String()
+	created.Fee = nil
+
+	return audit.Record(ctx, tx, entity.AUDIT_TRANSACTION_CREATED, entity.AGGREGATE_TRANSACTION, transaction.ID, nil, created)
+}
+
+// updateBalance stores the new balance of the account, which must already be
+// locked, and records the change in the audit log.
+func updateBalance(ctx context.Context, tx *database.Container, account *entity.Account, balance money.Money) error {
+	err := tx.Account.UpdateBalance(ctx, account.ID, balance)
+	if err != nil {
+		return err
+	}
+
+	before := entity.AuditBalance{Balance: account.Balance, HeldBalance: account.HeldBalance}
+	after := entity.AuditBalance{Balance: balance, HeldBalance: account.HeldBalance}
+
+	return audit.Record(ctx, tx, entity.AUDIT_ACCOUNT_BALANCE_UPDATED, entity.AUDIT_ACCOUNT, account.ID, before, after)
+}
+
+// updateHeldBalance is updateBalance for the funds held by authorizations.
+func updateHeldBalance(ctx context.Context, tx *database.Container, account *entity.Account, heldBalance money.Money) error {
+	err := tx.Account.UpdateHeldBalance(ctx, account.ID, heldBalance)
+	if err != nil {
+		return err
+	}
+
+	before := entity.AuditBalance{Balance: account.Balance, HeldBalance: account.HeldBalance}
+	after := entity.AuditBalance{Balance: account.Balance, HeldBalance: heldBalance}
+
+	return audit.Record(ctx, tx, entity.AUDIT_ACCOUNT_BALANCE_UPDATED, entity.AUDIT_ACCOUNT, account.ID, before, after)
+}
+
+// publish writes the event of the state change to the outbox, to be relayed
+// once the unit of work commits.
+func publish(ctx context.Context, tx *database.Container, transaction *entity.Transaction, transition *entity.StateTransition) error {
+	event, err := entity.NewTransactionEvent(transaction, transition)
+	if err != nil {
+		return err
+	}
+
+	return tx.Outbox.Create(ctx, event)
+}
+
+// registerFailedTransaction stores the transaction as FAILED once its unit of
+// work has been rolled back, so the attempt is still visible in ReadAll and
+// its history says why it failed.
+func (tr *appTransactionImpl) registerFailedTransaction(ctx context.Context, transaction *entity.Transaction, cause error) {
+	err := tr.db.UnitOfWork.Do(ctx, func(tx *database.Container) error {
+		return RecordFailed(ctx, tx, transaction, cause)
+	})
+	if err != nil {
+		log.Println("Error app.Transaction.registerFailedTransaction.RecordFailed: ", err.Error())
+	}
 }
 
-func (tr *appTransactionImpl) revertSourceBalanceTransaction(ctx context.Context, user *entity.User, amount float64) {
-	user.Balance += amount
-	if err := tr.db.Transaction.UpdateBalanceUser(ctx, user.ID, user.Balance); err != nil {
-		log.Println("Error app.Transaction.Create.db.UpdateBalanceUser.revertSourceBalanceTransaction: ", err.Error())
+// RecordFailed stores the transaction as FAILED within the unit of work of the
+// caller, once the unit of work that tried to book it has been rolled back.
+func RecordFailed(ctx context.Context, tx *database.Container, transaction *entity.Transaction, cause error) error {
+	// Nothing of the rolled back unit of work was stored, including any state
+	// the transaction reached in memory.
+	transaction.State = entity.PENDING
+
+	transition, err := transaction.TransitionTo(entity.FAILED, cause.Error())
+	if err != nil {
+		log.Println("Error app.Transaction.RecordFailed.TransitionTo: ", err.Error())
+		return err
+	}
+
+	err = createTransaction(ctx, tx, transaction)
+	if err != nil {
+		return err
+	}
+
+	err = tx.StateHistory.Create(ctx, transition)
+	if err != nil {
+		return err
 	}
+
+	return publish(ctx, tx, transaction, transition)
+}
+
+// moneyError turns an arithmetic error from the money package, such as an
+// overflow, into a response the client can act on.
+func moneyError(err error) error {
+	return echo.NewHTTPError(echo.ErrBadRequest.Code, err.Error())
 }
 
-func (tr *appTransactionImpl) revertDestinationBalanceTransaction(ctx context.Context, user *entity.User, amount float64) {
-	user.Balance -= amount
-	if err := tr.db.Transaction.UpdateBalanceUser(ctx, user.ID, user.Balance); err != nil {
-		log.Println("Error app.Transaction.Create.db.UpdateBalanceUser.revertDestinationBalanceTransaction: ", err.Error())
+// stateError reports an illegal state transition as a conflict with the
+// current state of the transaction.
+func stateError(err error) error {
+	var invalidTransition *entity.ErrInvalidTransition
+	if errors.As(err, &invalidTransition) {
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
 	}
+
+	return err
 }
 
-func (tr *appTransactionImpl) IncreaseBalanceUser(ctx context.Context, balance *entity.TransactionIncreaseBalanceUser) (float64, error) {
+// IncreaseBalanceUser deposits the value and returns the new balance of the
+// account, net of the fee charged on the deposit, if any.
+func (tr *appTransactionImpl) IncreaseBalanceUser(ctx context.Context, balance *entity.TransactionIncreaseBalanceUser) (money.Money, error) {
 	transaction := &entity.Transaction{
 		ID:            balance.ID,
-		DestinationId: balance.UserId,
+		SourceId:      entity.FundingAccountId,
+		DestinationId: balance.AccountId,
 		Amount:        balance.Value,
+		Kind:          entity.DEPOSIT,
+	}
+
+	if entity.IsSystemAccount(balance.AccountId) {
+		return money.Money{}, echo.NewHTTPError(echo.ErrBadRequest.Code, "System accounts can't receive deposits")
+	}
+
+	var newBalance money.Money
+	err := tr.db.UnitOfWork.Do(ctx, func(tx *database.Container) error {
+		err := createTransaction(ctx, tx, transaction)
+		if err != nil {
+			log.Println("Error app.Transaction.IncreaseBalanceUser.createTransaction: ", err.Error())
+			return err
+		}
+
+		account, err := tx.Account.ReadOneByIdForUpdate(ctx, balance.AccountId)
+		if err != nil {
+			log.Println("Error app.Transaction.IncreaseBalanceUser.db.ReadOneByIdForUpdate: ", err.Error())
+			return err
+		}
+
+		err = checkStatus(transaction, map[string]*entity.Account{account.ID: account})
+		if err != nil {
+			return err
+		}
+
+		newBalance, err = account.Balance.Add(transaction.Amount)
+		if err != nil {
+			log.Println("Error app.Transaction.IncreaseBalanceUser.account.Balance.Add: ", err.Error())
+			return moneyError(err)
+		}
+
+		err = updateBalance(ctx, tx, account, newBalance)
+		if err != nil {
+			log.Println("Error app.Transaction.IncreaseBalanceUser.updateBalance: ", err.Error())
+			return err
+		}
+
+		err = tx.Ledger.CreatePostings(ctx, entity.NewPostings(transaction))
+		if err != nil {
+			log.Println("Error app.Transaction.IncreaseBalanceUser.db.CreatePostings: ", err.Error())
+			return err
+		}
+
+		err = transitionState(ctx, tx, transaction, entity.BOOKED, "deposit booked")
+		if err != nil {
+			return err
+		}
+
+		fee, err := chargeFee(ctx, tx, transaction, balance.AccountId)
+		if err != nil || fee == nil {
+			return err
+		}
+
+		newBalance, err = newBalance.Sub(fee.Amount)
+		return err
+	})
+	if err != nil {
+		tr.registerFailedTransaction(ctx, transaction, err)
+		return money.Money{}, err
 	}
 
-	err := tr.db.Transaction.Create(ctx, transaction)
+	return newBalance, nil
+}
+
+func (tr *appTransactionImpl) Withdraw(ctx context.Context, transaction *entity.Transaction) (*entity.Transaction, error) {
+	if entity.IsSystemAccount(transaction.SourceId) {
+		return nil, echo.NewHTTPError(echo.ErrBadRequest.Code, "System accounts can't withdraw")
+	}
+
+	err := tr.db.UnitOfWork.Do(ctx, func(tx *database.Container) error {
+		err := createTransaction(ctx, tx, transaction)
+		if err != nil {
+			log.Println("Error app.Transaction.Withdraw.createTransaction: ", err.Error())
+			return err
+		}
+
+		return bookWithinLimits(ctx, tx, transaction, "withdrawal booked")
+	})
 	if err != nil {
-		log.Println("Error app.Transaction.IncreaseBalanceUser.db.Create: ", err.Error())
-		return 0, err
+		tr.registerFailedTransaction(ctx, transaction, err)
+		return transaction, err
 	}
 
-	user, err := tr.db.User.ReadOneById(ctx, balance.UserId)
+	transaction.KindString = transaction.Kind.String()
+
+	return transaction, nil
+}
+
+// Sweep books a SWEEP of the available balance of the account into the
+// destination account within the unit of work tx, so closing the user and
+// emptying their accounts are kept or rolled back together. It returns nil
+// when there is nothing to sweep.
+func Sweep(ctx context.Context, tx *database.Container, accountId, destinationId string) (*entity.Transaction, error) {
+	if accountId == destinationId {
+		return nil, echo.NewHTTPError(echo.ErrBadRequest.Code, "The balance must be swept to another account")
+	}
+
+	if entity.IsSystemAccount(accountId) {
+		return nil, echo.NewHTTPError(echo.ErrBadRequest.Code, "System accounts can't be swept")
+	}
+
+	sweep := entity.NewSweep(accountId, destinationId, money.New(0))
+	accounts, err := lockAccounts(ctx, tx, partyIds(sweep)...)
 	if err != nil {
-		tr.updateStatusTransaction(ctx, transaction, 2)
-		log.Println("Error app.Transaction.IncreaseBalanceUser.db.ReadOneById: ", err.Error())
-		return 0, err
+		log.Println("Error app.Transaction.Sweep.lockAccounts: ", err.Error())
+		return nil, err
 	}
 
-	user.Mutex = &sync.Mutex{}
-	user.Mutex.Lock()
-	defer user.Mutex.Unlock()
+	sweep.Amount = accounts[accountId].AvailableBalance
+	if !sweep.Amount.IsPositive() {
+		return nil, nil
+	}
 
-	user.Balance += transaction.Amount
+	err = createTransaction(ctx, tx, sweep)
+	if err != nil {
+		log.Println("Error app.Transaction.Sweep.createTransaction: ", err.Error())
+		return nil, err
+	}
 
-	err = tr.db.Transaction.UpdateBalanceUser(ctx, user.ID, user.Balance)
+	err = book(ctx, tx, sweep, "account closed")
 	if err != nil {
-		tr.revertDestinationBalanceTransaction(ctx, user, balance.Value)
-		tr.updateStatusTransaction(ctx, transaction, 2)
-		log.Println("Error app.Transaction.UpdateBalanceUser.db.UpdateBalanceUser: ", err.Error())
-		return 0, err
+		return nil, err
 	}
 
-	tr.updateStatusTransaction(ctx, transaction, 1)
+	sweep.KindString = sweep.Kind.String()
 
-	newBalance, err := tr.db.Transaction.ReadBalance(ctx, user.ID)
+	return sweep, nil
+}
+
+// Reverse gives back the amount of a booked transaction through a new
+// REVERSAL transaction linked to it. A zero amount reverses whatever is left.
+// The original row stays locked until the reversal is booked, so concurrent
+// reversals can't give back more than the original amount.
+func (tr *appTransactionImpl) Reverse(ctx context.Context, id string, amount money.Money) (*entity.Transaction, error) {
+	var reversal *entity.Transaction
+	err := tr.db.UnitOfWork.Do(ctx, func(tx *database.Container) error {
+		original, err := tx.Transaction.ReadOneByIdForUpdate(ctx, id)
+		if err != nil {
+			log.Println("Error app.Transaction.Reverse.db.ReadOneByIdForUpdate: ", err.Error())
+			return err
+		}
+
+		if !original.IsReversible() {
+			log.Println("Error app.Transaction.Reverse original.IsReversible Transaction can't be reversed")
+			return echo.NewHTTPError(http.StatusConflict, "Transaction can't be reversed")
+		}
+
+		reversed, err := tx.Transaction.ReadReversedAmount(ctx, original.ID)
+		if err != nil {
+			log.Println("Error app.Transaction.Reverse.db.ReadReversedAmount: ", err.Error())
+			return err
+		}
+
+		remaining, err := original.Amount.Sub(reversed)
+		if err != nil {
+			log.Println("Error app.Transaction.Reverse.original.Amount.Sub: ", err.Error())
+			return moneyError(err)
+		}
+
+		if amount.IsZero() {
+			amount = remaining
+		}
+
+		if remaining.LessThan(amount) {
+			log.Println("Error app.Transaction.Reverse remaining < amount Amount exceeds the reversible amount")
+			return echo.NewHTTPError(echo.ErrBadRequest.Code, "Amount exceeds the reversible amount")
+		}
+
+		reversal = entity.NewReversal(original, amount)
+		err = createTransaction(ctx, tx, reversal)
+		if err != nil {
+			log.Println("Error app.Transaction.Reverse.createTransaction: ", err.Error())
+			return err
+		}
+
+		err = book(ctx, tx, reversal, "reversal of "+original.ID+" booked")
+		if err != nil {
+			log.Println("Error app.Transaction.Reverse.book: ", err.Error())
+			return err
+		}
+
+		originalState := entity.PARTIALLY_REVERSED
+		if !amount.LessThan(remaining) {
+			originalState = entity.REVERSED
+		}
+
+		return transitionState(ctx, tx, original, originalState, "reversed by "+reversal.ID)
+	})
 	if err != nil {
-		log.Println("Error app.Transaction.IncreaseBalanceUser.db.ReadBalance: ", err.Error())
-		return 0, err
+		if reversal != nil {
+			tr.registerFailedTransaction(ctx, reversal, err)
+		}
+		return nil, err
 	}
 
-	return newBalance, nil
+	reversal.KindString = reversal.Kind.String()
+
+	return reversal, nil
 }
 
 func (tr *appTransactionImpl) ReadAll(ctx context.Context) ([]entity.Transaction, error) {
@@ -162,7 +811,610 @@ func (tr *appTransactionImpl) ReadAll(ctx context.Context) ([]entity.Transaction
 
 	for i := range transactions {
 		transactions[i].StateString = transactions[i].State.String()
+		transactions[i].KindString = transactions[i].Kind.String()
 	}
 
 	return transactions, nil
 }
+
+func (tr *appTransactionImpl) ReadHistory(ctx context.Context, id string) ([]entity.StateTransition, error) {
+	_, err := tr.db.Transaction.ReadOneById(ctx, id)
+	if err != nil {
+		log.Println("Error app.transaction.ReadHistory.db.ReadOneById: ", err.Error())
+		return nil, err
+	}
+
+	history, err := tr.db.StateHistory.ReadByTransactionId(ctx, id)
+	if err != nil {
+		log.Println("Error app.transaction.ReadHistory.db.ReadByTransactionId: ", err.Error())
+		return nil, err
+	}
+
+	for i := range history {
+		history[i].FromString = history[i].From.String()
+		history[i].ToString = history[i].To.String()
+	}
+
+	return history, nil
+}
+
+// Authorize holds the transaction amount on the available balance of the
+// source account, within its limits. No money moves until the authorization
+// is captured.
+func (tr *appTransactionImpl) Authorize(ctx context.Context, transaction *entity.Transaction) (*entity.Transaction, error) {
+	if transaction.SourceId == transaction.DestinationId {
+		return nil, echo.NewHTTPError(echo.ErrBadRequest.Code, "Source and destination accounts must be different")
+	}
+
+	if namesSystemAccount(transaction) {
+		return nil, echo.NewHTTPError(echo.ErrBadRequest.Code, "System accounts can't be the source or destination of an authorization")
+	}
+
+	if transaction.ExpiresAt == nil || !transaction.ExpiresAt.After(time.Now()) {
+		return nil, echo.NewHTTPError(echo.ErrBadRequest.Code, "Authorization must expire in the future")
+	}
+
+	err := tr.db.UnitOfWork.Do(ctx, func(tx *database.Container) error {
+		err := createTransaction(ctx, tx, transaction)
+		if err != nil {
+			log.Println("Error app.Transaction.Authorize.createTransaction: ", err.Error())
+			return err
+		}
+
+		accounts, err := lockAccounts(ctx, tx, transaction.SourceId, transaction.DestinationId)
+		if err != nil {
+			log.Println("Error app.Transaction.Authorize.lockAccounts: ", err.Error())
+			return err
+		}
+		sourceAccount := accounts[transaction.SourceId]
+
+		err = checkStatus(transaction, accounts)
+		if err != nil {
+			return err
+		}
+
+		err = checkCurrency(transaction, accounts)
+		if err != nil {
+			return err
+		}
+
+		if !sourceAccount.CanSpend(transaction.Amount) {
+			log.Println("Error app.Transaction.Authorize sourceAccount.CanSpend(transaction.Amount) Insufficient balance")
+			return ErrInsufficientBalance
+		}
+
+		heldBalance, err := sourceAccount.HeldBalance.Add(transaction.Amount)
+		if err != nil {
+			log.Println("Error app.Transaction.Authorize.sourceAccount.HeldBalance.Add: ", err.Error())
+			return moneyError(err)
+		}
+
+		err = updateHeldBalance(ctx, tx, sourceAccount, heldBalance)
+		if err != nil {
+			log.Println("Error app.Transaction.Authorize.updateHeldBalance: ", err.Error())
+			return err
+		}
+
+		err = transitionState(ctx, tx, transaction, entity.AUTHORIZED, "authorized until "+transaction.ExpiresAt.Format(time.RFC3339))
+		if err != nil {
+			return err
+		}
+
+		// The hold already counts as sent, so the limits are checked now
+		// rather than when it is captured.
+		return checkLimits(ctx, tx, transaction, time.Now())
+	})
+	if err != nil {
+		tr.registerFailedTransaction(ctx, transaction, err)
+		return transaction, err
+	}
+
+	transaction.KindString = transaction.Kind.String()
+
+	return transaction, nil
+}
+
+// Capture books an authorization. A zero amount captures the whole authorized
+// amount; whatever isn't captured goes back to the available balance.
+func (tr *appTransactionImpl) Capture(ctx context.Context, id string, amount money.Money) (*entity.Transaction, error) {
+	var transaction *entity.Transaction
+	err := tr.db.UnitOfWork.Do(ctx, func(tx *database.Container) error {
+		var err error
+		transaction, err = readAuthorization(ctx, tx, id)
+		if err != nil {
+			log.Println("Error app.Transaction.Capture.readAuthorization: ", err.Error())
+			return err
+		}
+
+		if transaction.IsExpired(time.Now()) {
+			log.Println("Error app.Transaction.Capture transaction.IsExpired Authorization expired")
+			return echo.NewHTTPError(http.StatusConflict, "Authorization expired")
+		}
+
+		authorized := transaction.Amount
+		if amount.IsZero() {
+			amount = authorized
+		}
+
+		if authorized.LessThan(amount) {
+			log.Println("Error app.Transaction.Capture authorized < amount Amount exceeds the authorized amount")
+			return echo.NewHTTPError(echo.ErrBadRequest.Code, "Amount exceeds the authorized amount")
+		}
+
+		accounts, err := lockAccounts(ctx, tx, transaction.SourceId, transaction.DestinationId)
+		if err != nil {
+			log.Println("Error app.Transaction.Capture.lockAccounts: ", err.Error())
+			return err
+		}
+
+		err = releaseHold(ctx, tx, accounts[transaction.SourceId], transaction)
+		if err != nil {
+			log.Println("Error app.Transaction.Capture.releaseHold: ", err.Error())
+			return err
+		}
+
+		transaction.Amount = amount
+		err = tx.Transaction.UpdateAmount(ctx, transaction.ID, amount)
+		if err != nil {
+			log.Println("Error app.Transaction.Capture.db.UpdateAmount: ", err.Error())
+			return err
+		}
+
+		return book(ctx, tx, transaction, "captured "+amount.String()+" of "+authorized.String())
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	transaction.KindString = transaction.Kind.String()
+
+	return transaction, nil
+}
+
+// Void cancels an authorization and releases the funds it held.
+func (tr *appTransactionImpl) Void(ctx context.Context, id string) (*entity.Transaction, error) {
+	var transaction *entity.Transaction
+	err := tr.db.UnitOfWork.Do(ctx, func(tx *database.Container) error {
+		var err error
+		transaction, err = readAuthorization(ctx, tx, id)
+		if err != nil {
+			log.Println("Error app.Transaction.Void.readAuthorization: ", err.Error())
+			return err
+		}
+
+		return cancelAuthorization(ctx, tx, transaction, "voided")
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	transaction.KindString = transaction.Kind.String()
+
+	return transaction, nil
+}
+
+// ExpireAuthorizations cancels every authorization past its expiry. Each one
+// is cancelled in its own unit of work and re-read under lock, so a capture
+// or another instance running the same job can't release the funds twice.
+func (tr *appTransactionImpl) ExpireAuthorizations(ctx context.Context) error {
+	now := time.Now()
+
+	expired, err := tr.db.Transaction.ReadExpiredAuthorizations(ctx, now)
+	if err != nil {
+		log.Println("Error app.Transaction.ExpireAuthorizations.db.ReadExpiredAuthorizations: ", err.Error())
+		return err
+	}
+
+	for _, authorization := range expired {
+		err := tr.db.UnitOfWork.Do(ctx, func(tx *database.Container) error {
+			transaction, err := tx.Transaction.ReadOneByIdForUpdate(ctx, authorization.ID)
+			if err != nil {
+				return err
+			}
+
+			if transaction.State != entity.AUTHORIZED || !transaction.IsExpired(now) {
+				return nil
+			}
+
+			return cancelAuthorization(ctx, tx, transaction, "authorization expired")
+		})
+		if err != nil {
+			log.Println("Error app.Transaction.ExpireAuthorizations.cancelAuthorization: ", authorization.ID, err.Error())
+		}
+	}
+
+	return nil
+}
+
+// readAuthorization locks the transaction and makes sure it still holds funds.
+func readAuthorization(ctx context.Context, tx *database.Container, id string) (*entity.Transaction, error) {
+	transaction, err := tx.Transaction.ReadOneByIdForUpdate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if transaction.State != entity.AUTHORIZED {
+		return nil, echo.NewHTTPError(http.StatusConflict, "Transaction is not authorized")
+	}
+
+	return transaction, nil
+}
+
+func cancelAuthorization(ctx context.Context, tx *database.Container, transaction *entity.Transaction, reason string) error {
+	sourceAccount, err := tx.Account.ReadOneByIdForUpdate(ctx, transaction.SourceId)
+	if err != nil {
+		log.Println("Error app.Transaction.cancelAuthorization.db.ReadOneByIdForUpdate: ", err.Error())
+		return err
+	}
+
+	err = releaseHold(ctx, tx, sourceAccount, transaction)
+	if err != nil {
+		log.Println("Error app.Transaction.cancelAuthorization.releaseHold: ", err.Error())
+		return err
+	}
+
+	return transitionState(ctx, tx, transaction, entity.CANCELLED, reason)
+}
+
+// releaseHold gives the funds held by the authorization back to the available
+// balance of its source account, which must already be locked.
+func releaseHold(ctx context.Context, tx *database.Container, sourceAccount *entity.Account, transaction *entity.Transaction) error {
+	heldBalance, err := sourceAccount.HeldBalance.Sub(transaction.Amount)
+	if err != nil {
+		return moneyError(err)
+	}
+
+	return updateHeldBalance(ctx, tx, sourceAccount, heldBalance)
+}
+
+func (tr *appTransactionImpl) ReadScheduled(ctx context.Context) ([]entity.Transaction, error) {
+	transactions, err := tr.db.Transaction.ReadAllByState(ctx, entity.SCHEDULED)
+	if err != nil {
+		log.Println("Error app.transaction.ReadScheduled.db.ReadAllByState: ", err.Error())
+		return nil, err
+	}
+
+	for i := range transactions {
+		transactions[i].StateString = transactions[i].State.String()
+		transactions[i].KindString = transactions[i].Kind.String()
+	}
+
+	return transactions, nil
+}
+
+func (tr *appTransactionImpl) CancelScheduled(ctx context.Context, id string) (*entity.Transaction, error) {
+	var transaction *entity.Transaction
+	err := tr.db.UnitOfWork.Do(ctx, func(tx *database.Container) error {
+		var err error
+		transaction, err = tx.Transaction.ReadOneByIdForUpdate(ctx, id)
+		if err != nil {
+			log.Println("Error app.Transaction.CancelScheduled.db.ReadOneByIdForUpdate: ", err.Error())
+			return err
+		}
+
+		if transaction.State != entity.SCHEDULED {
+			log.Println("Error app.Transaction.CancelScheduled transaction.State Transaction is not scheduled")
+			return echo.NewHTTPError(http.StatusConflict, "Transaction is not scheduled")
+		}
+
+		return transitionState(ctx, tx, transaction, entity.CANCELLED, "cancelled")
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	transaction.KindString = transaction.Kind.String()
+
+	return transaction, nil
+}
+
+// ExecuteScheduled books every scheduled transfer that is due. Each transfer
+// is re-read under lock and only booked while it is still SCHEDULED, so API
+// instances running the job at the same time never book it twice.
+func (tr *appTransactionImpl) ExecuteScheduled(ctx context.Context) error {
+	now := time.Now()
+
+	due, err := tr.db.Transaction.ReadDueScheduled(ctx, now)
+	if err != nil {
+		log.Println("Error app.Transaction.ExecuteScheduled.db.ReadDueScheduled: ", err.Error())
+		return err
+	}
+
+	for _, scheduled := range due {
+		err := tr.db.UnitOfWork.Do(ctx, func(tx *database.Container) error {
+			transaction, err := readDueScheduled(ctx, tx, scheduled.ID, now)
+			if err != nil || transaction == nil {
+				return err
+			}
+
+			_, err = bookTransfer(ctx, tx, transaction, "scheduled transfer booked")
+			return err
+		})
+		if err != nil {
+			log.Println("Error app.Transaction.ExecuteScheduled.book: ", scheduled.ID, err.Error())
+			tr.failScheduled(ctx, scheduled.ID, now, err)
+		}
+	}
+
+	return nil
+}
+
+// readDueScheduled locks the transfer and returns it while it is still
+// scheduled and due, or nil when another run already took care of it.
+func readDueScheduled(ctx context.Context, tx *database.Container, id string, now time.Time) (*entity.Transaction, error) {
+	transaction, err := tx.Transaction.ReadOneByIdForUpdate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if transaction.State != entity.SCHEDULED || transaction.ExecuteAt == nil || transaction.ExecuteAt.After(now) {
+		return nil, nil
+	}
+
+	return transaction, nil
+}
+
+// failScheduled marks a scheduled transfer that couldn't be booked as FAILED,
+// once the unit of work that tried to book it has been rolled back.
+func (tr *appTransactionImpl) failScheduled(ctx context.Context, id string, now time.Time, cause error) {
+	err := tr.db.UnitOfWork.Do(ctx, func(tx *database.Container) error {
+		transaction, err := readDueScheduled(ctx, tx, id, now)
+		if err != nil || transaction == nil {
+			return err
+		}
+
+		return transitionState(ctx, tx, transaction, entity.FAILED, cause.Error())
+	})
+	if err != nil {
+		log.Println("Error app.Transaction.failScheduled: ", id, err.Error())
+	}
+}
+
+// CreateBatch books the transfers of the batch, all in a single unit of work
+// when the batch is atomic or each on its own otherwise, and records how every
+// one of them ended.
+func (tr *appTransactionImpl) CreateBatch(ctx context.Context, batch *entity.Batch) (*entity.Batch, error) {
+	for i, transaction := range batch.Transactions {
+		if transaction.SourceId == transaction.DestinationId {
+			return nil, echo.NewHTTPError(echo.ErrBadRequest.Code, fmt.Sprintf("Transaction %d: source and destination accounts must be different", i))
+		}
+
+		if namesSystemAccount(transaction) {
+			return nil, echo.NewHTTPError(echo.ErrBadRequest.Code, fmt.Sprintf("Transaction %d: system accounts can't be the source or destination of a transfer", i))
+		}
+
+		if transaction.ExecuteAt != nil {
+			return nil, echo.NewHTTPError(echo.ErrBadRequest.Code, fmt.Sprintf("Transaction %d: batch transfers can't be scheduled", i))
+		}
+	}
+
+	err := tr.db.Batch.Create(ctx, batch)
+	if err != nil {
+		log.Println("Error app.Transaction.CreateBatch.db.Batch.Create: ", err.Error())
+		return nil, err
+	}
+
+	var errs map[int]error
+	if batch.Atomic {
+		errs, _ = tr.bookAtomically(ctx, batch.Transactions, "batch "+batch.ID)
+	} else {
+		errs = tr.bookBatch(ctx, batch)
+	}
+
+	batch.Complete(errs)
+
+	err = tr.db.UnitOfWork.Do(ctx, func(tx *database.Container) error {
+		err := tx.Batch.CreateItems(ctx, batch.Items)
+		if err != nil {
+			return err
+		}
+
+		return tx.Batch.UpdateState(ctx, batch.State, batch.ID)
+	})
+	if err != nil {
+		log.Println("Error app.Transaction.CreateBatch.db.Batch.CreateItems: ", err.Error())
+		return nil, err
+	}
+
+	return batch, nil
+}
+
+// bookBatch books every transfer on its own, returning the errors of the
+// failed ones by position.
+func (tr *appTransactionImpl) bookBatch(ctx context.Context, batch *entity.Batch) map[int]error {
+	errs := make(map[int]error)
+	for i, transaction := range batch.Transactions {
+		_, err := tr.Create(ctx, transaction)
+		if err != nil {
+			errs[i] = err
+		}
+	}
+
+	return errs
+}
+
+// bookAtomically books every transfer of the group in a single unit of work.
+// When one of them fails nothing is booked, all of them are recorded as
+// FAILED and the error that caused the rollback is returned along with the
+// error of every transfer by position.
+func (tr *appTransactionImpl) bookAtomically(ctx context.Context, transactions []*entity.Transaction, group string) (map[int]error, error) {
+	failed := -1
+	fees := make([]*entity.Transaction, len(transactions))
+	err := tr.db.UnitOfWork.Do(ctx, func(tx *database.Container) error {
+		// Locking every account up front, in ID order, keeps groups sharing
+		// accounts from deadlocking each other.
+		_, err := lockAccounts(ctx, tx, transactionAccountIds(transactions)...)
+		if err != nil {
+			log.Println("Error app.Transaction.bookAtomically.lockAccounts: ", err.Error())
+			return err
+		}
+
+		for i, transaction := range transactions {
+			failed = i
+
+			err := createTransaction(ctx, tx, transaction)
+			if err != nil {
+				log.Println("Error app.Transaction.bookAtomically.createTransaction: ", err.Error())
+				return err
+			}
+
+			fees[i], err = bookTransfer(ctx, tx, transaction, "transfer booked in "+group)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err == nil {
+		for i, transaction := range transactions {
+			transaction.Fee = fees[i]
+		}
+
+		return nil, nil
+	}
+
+	rolledBack := fmt.Errorf("%s was rolled back", group)
+
+	errs := make(map[int]error, len(transactions))
+	for i, transaction := range transactions {
+		cause := rolledBack
+		if failed < 0 || i == failed {
+			cause = err
+		}
+
+		tr.registerFailedTransaction(ctx, transaction, cause)
+		errs[i] = cause
+	}
+
+	return errs, err
+}
+
+func transactionAccountIds(transactions []*entity.Transaction) []string {
+	accountIds := make([]string, 0, len(transactions)*2)
+	for _, transaction := range transactions {
+		accountIds = append(accountIds, partyIds(transaction)...)
+	}
+
+	return accountIds
+}
+
+func (tr *appTransactionImpl) ReadBatch(ctx context.Context, id string) (*entity.Batch, error) {
+	batch, err := tr.db.Batch.ReadOneById(ctx, id)
+	if err != nil {
+		log.Println("Error app.Transaction.ReadBatch.db.Batch.ReadOneById: ", err.Error())
+		return nil, err
+	}
+
+	batch.Items, err = tr.db.Batch.ReadItems(ctx, id)
+	if err != nil {
+		log.Println("Error app.Transaction.ReadBatch.db.Batch.ReadItems: ", err.Error())
+		return nil, err
+	}
+
+	batch.StateString = batch.State.String()
+	for i := range batch.Items {
+		batch.Items[i].StateString = batch.Items[i].State.String()
+	}
+
+	return batch, nil
+}
+
+// CreateSplit divides the payment among the destinations of the split and
+// books every share in a single unit of work, so either all of them are paid
+// or none is.
+func (tr *appTransactionImpl) CreateSplit(ctx context.Context, split *entity.Split) (*entity.Split, error) {
+	err := split.Allocate()
+	if err != nil {
+		return nil, echo.NewHTTPError(echo.ErrBadRequest.Code, err.Error())
+	}
+
+	for i, transaction := range split.Transactions {
+		if transaction.SourceId == transaction.DestinationId {
+			return nil, echo.NewHTTPError(echo.ErrBadRequest.Code, fmt.Sprintf("Rule %d: source and destination accounts must be different", i))
+		}
+
+		if namesSystemAccount(transaction) {
+			return nil, echo.NewHTTPError(echo.ErrBadRequest.Code, fmt.Sprintf("Rule %d: system accounts can't be the source or destination of a transfer", i))
+		}
+	}
+
+	_, err = tr.bookAtomically(ctx, split.Transactions, "split "+split.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, transaction := range split.Transactions {
+		transaction.KindString = transaction.Kind.String()
+		transaction.StateString = transaction.State.String()
+	}
+
+	return split, nil
+}
+
+func (tr *appTransactionImpl) ReadSplit(ctx context.Context, id string) (*entity.Split, error) {
+	transactions, err := tr.db.Transaction.ReadAllByParent(ctx, id)
+	if err != nil {
+		log.Println("Error app.Transaction.ReadSplit.db.ReadAllByParent: ", err.Error())
+		return nil, err
+	}
+
+	if len(transactions) == 0 {
+		return nil, echo.ErrNotFound
+	}
+
+	for i := range transactions {
+		transactions[i].KindString = transactions[i].Kind.String()
+		transactions[i].StateString = transactions[i].State.String()
+	}
+
+	return entity.NewSplitFromTransactions(id, transactions), nil
+}
+
+// AccrueInterest charges a day of interest on every negative balance as an
+// INTEREST transaction. Each account is charged in its own unit of work and
+// re-read under lock, so however many times the job runs in a day, or on how
+// many instances, an account is charged at most once for it.
+func (tr *appTransactionImpl) AccrueInterest(ctx context.Context) error {
+	today := entity.StartOfDay(time.Now())
+
+	overdrawn, err := tr.db.Account.ReadOverdrawn(ctx, today)
+	if err != nil {
+		log.Println("Error app.Transaction.AccrueInterest.db.ReadOverdrawn: ", err.Error())
+		return err
+	}
+
+	for _, overdrawnAccount := range overdrawn {
+		err := tr.db.UnitOfWork.Do(ctx, func(tx *database.Container) error {
+			account, err := tx.Account.ReadOneByIdForUpdate(ctx, overdrawnAccount.ID)
+			if err != nil {
+				return err
+			}
+
+			if account.InterestAccruedOn != nil && !account.InterestAccruedOn.Before(today) {
+				return nil
+			}
+
+			interest := account.DailyInterest()
+			if interest.IsPositive() {
+				transaction := entity.NewInterest(account.ID, interest)
+				err = createTransaction(ctx, tx, transaction)
+				if err != nil {
+					return err
+				}
+
+				err = book(ctx, tx, transaction, "interest of "+today.Format("2006-01-02")+" charged")
+				if err != nil {
+					return err
+				}
+			}
+
+			return tx.Account.UpdateInterestAccruedOn(ctx, account.ID, today)
+		})
+		if err != nil {
+			log.Println("Error app.Transaction.AccrueInterest: ", overdrawnAccount.ID, err.Error())
+		}
+	}
+
+	return nil
+}
@@ -2,189 +2,373 @@ package transaction
 
 import (
 	"context"
-	"sync"
 	"testing"
 	"time"
 
 	"github.com/garoque/backend-code-challenge-snapfi/internal/api/dto"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/app/audit"
 	"github.com/garoque/backend-code-challenge-snapfi/internal/database"
 	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
 	"github.com/garoque/backend-code-challenge-snapfi/internal/mocks"
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/money"
 	"github.com/golang/mock/gomock"
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/labstack/echo/v4"
 )
 
+type databaseMocks struct {
+	Transaction  *mocks.MockDabataseTransactionInterface
+	Account      *mocks.MockDabataseAccountInterface
+	Ledger       *mocks.MockDabataseLedgerInterface
+	StateHistory *mocks.MockDabataseStateHistoryInterface
+	Batch        *mocks.MockDabataseBatchInterface
+	Fee          *mocks.MockDabataseFeeInterface
+	Limit        *mocks.MockDabataseLimitInterface
+	Outbox       *mocks.MockDabataseOutboxInterface
+	Audit        *mocks.MockDabataseAuditInterface
+
+	// Audited holds the entries appended to the audit log, in order.
+	Audited []entity.AuditEntry
+}
+
+// newDatabaseContainer returns a container backed by mocks whose unit of work
+// runs the given function against the same mocks, and whose audit log keeps
+// the entries in Audited.
+func newDatabaseContainer(ctrl *gomock.Controller) (*database.Container, *databaseMocks) {
+	db := &databaseMocks{
+		Transaction:  mocks.NewMockDabataseTransactionInterface(ctrl),
+		Account:      mocks.NewMockDabataseAccountInterface(ctrl),
+		Ledger:       mocks.NewMockDabataseLedgerInterface(ctrl),
+		StateHistory: mocks.NewMockDabataseStateHistoryInterface(ctrl),
+		Batch:        mocks.NewMockDabataseBatchInterface(ctrl),
+		Fee:          mocks.NewMockDabataseFeeInterface(ctrl),
+		Limit:        mocks.NewMockDabataseLimitInterface(ctrl),
+		Outbox:       mocks.NewMockDabataseOutboxInterface(ctrl),
+		Audit:        mocks.NewMockDabataseAuditInterface(ctrl),
+	}
+	mockUnitOfWork := mocks.NewMockUnitOfWorkInterface(ctrl)
+
+	container := &database.Container{
+		Transaction:  db.Transaction,
+		Account:      db.Account,
+		Ledger:       db.Ledger,
+		StateHistory: db.StateHistory,
+		Batch:        db.Batch,
+		Fee:          db.Fee,
+		Limit:        db.Limit,
+		Outbox:       db.Outbox,
+		Audit:        db.Audit,
+		UnitOfWork:   mockUnitOfWork,
+	}
+	mockUnitOfWork.EXPECT().Do(gomock.Any(), gomock.Any()).AnyTimes().
+		DoAndReturn(func(ctx context.Context, fn func(tx *database.Container) error) error {
+			return fn(container)
+		})
+	db.Audit.EXPECT().Create(gomock.Any(), gomock.Any()).AnyTimes().
+		DoAndReturn(func(ctx context.Context, entry *entity.AuditEntry) error {
+			db.Audited = append(db.Audited, *entry)
+			return nil
+		})
+
+	return container, db
+}
+
 func TestCreate(t *testing.T) {
 	sourceUserId := "source-user-id"
-	sourceUserId2 := "source-user-id-2"
 	destinationUserId := "destination-user-id"
-	transaction := entity.NewTransaction(dto.CreateTransaction{
-		SourceUserId:      sourceUserId,
-		DestinationUserId: destinationUserId,
-		Amount:            100.10,
-	})
 
-	bookedTransaction := entity.Transaction{
-		ID:            transaction.ID,
-		SourceId:      transaction.SourceId,
-		DestinationId: transaction.DestinationId,
-		Amount:        transaction.Amount,
-		State:         entity.BOOKED,
-		StateString:   entity.BOOKED.String(),
+	transaction := entity.Transaction{
+		ID:            "transaction-id",
+		SourceId:      sourceUserId,
+		DestinationId: destinationUserId,
+		Amount:        money.New(10010),
 	}
 
-	failedTransaction := entity.NewTransaction(dto.CreateTransaction{
-		SourceUserId:      sourceUserId,
-		DestinationUserId: destinationUserId,
-		Amount:            250.10,
-	})
+	bookedTransaction := transaction
+	bookedTransaction.State = entity.BOOKED
+	bookedTransaction.StateString = entity.BOOKED.String()
+	bookedTransaction.KindString = entity.TRANSFER.String()
 
-	sourceUser := entity.User{
-		ID:        sourceUserId,
-		Name:      "Gabriel",
-		Balance:   200.0,
-		Mutex:     &sync.Mutex{},
-		CreatedAt: time.Now(),
-	}
+	failedTransaction := transaction
+	failedTransaction.State = entity.FAILED
+	failedTransaction.StateString = entity.FAILED.String()
 
-	sourceUser2 := entity.User{
-		ID:        sourceUserId2,
-		Name:      "Gabriel",
-		Balance:   2000.0,
-		Mutex:     &sync.Mutex{},
-		CreatedAt: time.Now(),
+	selfTransaction := transaction
+	selfTransaction.DestinationId = sourceUserId
+
+	sourceAccount := entity.Account{
+		ID:               sourceUserId,
+		Name:             "Gabriel",
+		Balance:          money.New(20000),
+		AvailableBalance: money.New(20000),
+		CreatedAt:        time.Now(),
 	}
 
-	destinationUser := entity.User{
-		ID:        destinationUserId,
-		Name:      "João",
-		Balance:   0,
-		Mutex:     &sync.Mutex{},
-		CreatedAt: time.Now(),
+	poorSourceAccount := entity.Account{
+		ID:               sourceUserId,
+		Name:             "Gabriel",
+		Balance:          money.New(5000),
+		AvailableBalance: money.New(5000),
+		CreatedAt:        time.Now(),
 	}
 
-	destinationUser2 := entity.User{
-		ID:        destinationUserId,
-		Name:      "João",
-		Balance:   0,
-		Mutex:     &sync.Mutex{},
-		CreatedAt: time.Now(),
+	overdraftSourceUser := poorSourceAccount
+	overdraftSourceUser.CreditLimit = money.New(5010)
+
+	destinationAccount := entity.Account{
+		ID:               destinationUserId,
+		Name:             "João",
+		Balance:          money.New(0),
+		AvailableBalance: money.New(0),
+		CreatedAt:        time.Now(),
 	}
 
-	sourceUserBalanceUpdated := sourceUser.Balance - transaction.Amount
-	destinationUserBalanceUpdated := destinationUser.Balance + transaction.Amount
+	frozenSourceUser := sourceAccount
+	frozenSourceUser.Status = entity.FROZEN
+
+	closedDestinationUser := destinationAccount
+	closedDestinationUser.Status = entity.CLOSED
+
+	dollarDestinationAccount := destinationAccount
+	dollarDestinationAccount.Currency = "USD"
 
-	sourceUserFailedTrBalanceUpdated := sourceUser2.Balance - failedTransaction.Amount
-	destinationUserFailedTrBalanceUpdated := destinationUser2.Balance + failedTransaction.Amount
+	sourceUserBalanceUpdated, _ := sourceAccount.Balance.Sub(transaction.Amount)
+	destinationUserBalanceUpdated, _ := destinationAccount.Balance.Add(transaction.Amount)
 
 	cases := map[string]struct {
-		InputTransaction *entity.Transaction
+		InputTransaction entity.Transaction
 		ExpectedResult   *entity.Transaction
 		ExpectedErr      error
-		PrepareMock      func(mockTransactionDb *mocks.MockDabataseTransactionInterface, mockUserDb *mocks.MockDabataseUserInterface)
+		PrepareMock      func(db *databaseMocks)
 	}{
 		"deve retornar sucesso": {
 			InputTransaction: transaction,
 			ExpectedResult:   &bookedTransaction,
 			ExpectedErr:      nil,
-			PrepareMock: func(mockTransactionDb *mocks.MockDabataseTransactionInterface, mockUserDb *mocks.MockDabataseUserInterface) {
-				mockTransactionDb.EXPECT().Create(gomock.Any(), transaction).Times(1).Return(nil)
-				mockUserDb.EXPECT().ReadOneById(gomock.Any(), transaction.SourceId).Times(1).Return(&sourceUser, nil)
-				mockUserDb.EXPECT().ReadOneById(gomock.Any(), transaction.DestinationId).Times(1).Return(&destinationUser, nil)
-
-				mockTransactionDb.EXPECT().UpdateBalanceUser(gomock.Any(), sourceUser.ID, sourceUserBalanceUpdated).
-					Times(1).Return(nil)
-
-				mockTransactionDb.EXPECT().UpdateBalanceUser(gomock.Any(), destinationUser.ID, destinationUserBalanceUpdated).
-					Times(1).Return(nil)
-
-				mockTransactionDb.EXPECT().UpdateState(gomock.Any(), bookedTransaction.State, transaction.ID).Times(1).Return(nil)
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.Transaction.EXPECT().Create(gomock.Any(), &transaction).Times(1).Return(nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), destinationUserId).Times(1).Return(&destinationAccount, nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), sourceUserId).Times(1).Return(&sourceAccount, nil),
+					db.Account.EXPECT().UpdateBalance(gomock.Any(), sourceUserId, sourceUserBalanceUpdated).Times(1).Return(nil),
+					db.Account.EXPECT().UpdateBalance(gomock.Any(), destinationUserId, destinationUserBalanceUpdated).Times(1).Return(nil),
+					db.Ledger.EXPECT().CreatePostings(gomock.Any(), entity.NewPostings(&transaction)).Times(1).Return(nil),
+					db.Transaction.EXPECT().UpdateState(gomock.Any(), entity.BOOKED, transaction.ID).Times(1).Return(nil),
+					db.StateHistory.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Outbox.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Account.EXPECT().ReadOneById(gomock.Any(), gomock.Any()).Times(1).Return(&entity.Account{UserId: "user-id"}, nil),
+					db.Limit.EXPECT().ReadWithDefault(gomock.Any(), gomock.Any()).Times(1).Return([]entity.Limits{}, nil),
+					db.Fee.EXPECT().ReadActive(gomock.Any()).Times(1).Return([]entity.FeeRule{}, nil),
+				)
 			},
 		},
-		"deve retornar erro: ao registrar transaction": {
+		"deve retornar sucesso: usando o limite de credito": {
 			InputTransaction: transaction,
-			ExpectedResult:   nil,
-			ExpectedErr:      echo.ErrInternalServerError,
-			PrepareMock: func(mockTransactionDb *mocks.MockDabataseTransactionInterface, mockUserDb *mocks.MockDabataseUserInterface) {
-				mockTransactionDb.EXPECT().Create(gomock.Any(), transaction).Times(1).Return(echo.ErrInternalServerError)
+			ExpectedResult:   &bookedTransaction,
+			ExpectedErr:      nil,
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.Transaction.EXPECT().Create(gomock.Any(), &transaction).Times(1).Return(nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), destinationUserId).Times(1).Return(&destinationAccount, nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), sourceUserId).Times(1).Return(&overdraftSourceUser, nil),
+					db.Account.EXPECT().UpdateBalance(gomock.Any(), sourceUserId, money.New(-5010)).Times(1).Return(nil),
+					db.Account.EXPECT().UpdateBalance(gomock.Any(), destinationUserId, destinationUserBalanceUpdated).Times(1).Return(nil),
+					db.Ledger.EXPECT().CreatePostings(gomock.Any(), entity.NewPostings(&transaction)).Times(1).Return(nil),
+					db.Transaction.EXPECT().UpdateState(gomock.Any(), entity.BOOKED, transaction.ID).Times(1).Return(nil),
+					db.StateHistory.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Outbox.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Account.EXPECT().ReadOneById(gomock.Any(), gomock.Any()).Times(1).Return(&entity.Account{UserId: "user-id"}, nil),
+					db.Limit.EXPECT().ReadWithDefault(gomock.Any(), gomock.Any()).Times(1).Return([]entity.Limits{}, nil),
+					db.Fee.EXPECT().ReadActive(gomock.Any()).Times(1).Return([]entity.FeeRule{}, nil),
+				)
 			},
 		},
-		"deve retornar erro: ao ler source user": {
+		"deve retornar erro: ao gravar o evento": {
 			InputTransaction: transaction,
-			ExpectedResult:   transaction,
+			ExpectedResult:   &failedTransaction,
 			ExpectedErr:      echo.ErrInternalServerError,
-			PrepareMock: func(mockTransactionDb *mocks.MockDabataseTransactionInterface, mockUserDb *mocks.MockDabataseUserInterface) {
-				mockTransactionDb.EXPECT().Create(gomock.Any(), transaction).Times(1).Return(nil)
-				mockUserDb.EXPECT().ReadOneById(gomock.Any(), transaction.SourceId).Times(1).Return(nil, echo.ErrInternalServerError)
-
-				mockTransactionDb.EXPECT().UpdateState(gomock.Any(), entity.FAILED, transaction.ID).Times(1).Return(nil)
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.Transaction.EXPECT().Create(gomock.Any(), &transaction).Times(1).Return(nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), destinationUserId).Times(1).Return(&destinationAccount, nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), sourceUserId).Times(1).Return(&sourceAccount, nil),
+					db.Account.EXPECT().UpdateBalance(gomock.Any(), sourceUserId, sourceUserBalanceUpdated).Times(1).Return(nil),
+					db.Account.EXPECT().UpdateBalance(gomock.Any(), destinationUserId, destinationUserBalanceUpdated).Times(1).Return(nil),
+					db.Ledger.EXPECT().CreatePostings(gomock.Any(), entity.NewPostings(&transaction)).Times(1).Return(nil),
+					db.Transaction.EXPECT().UpdateState(gomock.Any(), entity.BOOKED, transaction.ID).Times(1).Return(nil),
+					db.StateHistory.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Outbox.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(echo.ErrInternalServerError),
+					db.Transaction.EXPECT().Create(gomock.Any(), &failedTransaction).Times(1).Return(nil),
+					db.StateHistory.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Outbox.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+				)
+			},
+		},
+		"deve retornar erro: source e destination iguais": {
+			InputTransaction: selfTransaction,
+			ExpectedResult:   nil,
+			ExpectedErr:      echo.NewHTTPError(echo.ErrBadRequest.Code, "Source and destination accounts must be different"),
+			PrepareMock: func(db *databaseMocks) {
 			},
 		},
-		"deve retornar erro: ao ler destination user": {
+		"deve retornar erro: conta de sistema": {
+			InputTransaction: entity.Transaction{SourceId: entity.FeeAccountId, DestinationId: destinationUserId, Amount: transaction.Amount},
+			ExpectedResult:   nil,
+			ExpectedErr:      echo.NewHTTPError(echo.ErrBadRequest.Code, "System accounts can't be the source or destination of a transfer"),
+			PrepareMock:      func(db *databaseMocks) {},
+		},
+		"deve retornar erro: ao registrar transaction": {
 			InputTransaction: transaction,
-			ExpectedResult:   transaction,
+			ExpectedResult:   &failedTransaction,
 			ExpectedErr:      echo.ErrInternalServerError,
-			PrepareMock: func(mockTransactionDb *mocks.MockDabataseTransactionInterface, mockUserDb *mocks.MockDabataseUserInterface) {
-				mockTransactionDb.EXPECT().Create(gomock.Any(), transaction).Times(1).Return(nil)
-				mockUserDb.EXPECT().ReadOneById(gomock.Any(), transaction.SourceId).Times(1).Return(&sourceUser, nil)
-				mockUserDb.EXPECT().ReadOneById(gomock.Any(), transaction.DestinationId).Times(1).Return(nil, echo.ErrInternalServerError)
-
-				mockTransactionDb.EXPECT().UpdateState(gomock.Any(), entity.FAILED, transaction.ID).Times(1).Return(nil)
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.Transaction.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(echo.ErrInternalServerError),
+					db.Transaction.EXPECT().Create(gomock.Any(), &failedTransaction).Times(1).Return(nil),
+					db.StateHistory.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Outbox.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+				)
+			},
+		},
+		"deve retornar erro: ao ler destination account": {
+			InputTransaction: transaction,
+			ExpectedResult:   &failedTransaction,
+			ExpectedErr:      echo.ErrNotFound,
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.Transaction.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), destinationUserId).Times(1).Return(nil, echo.ErrNotFound),
+					db.Transaction.EXPECT().Create(gomock.Any(), &failedTransaction).Times(1).Return(nil),
+					db.StateHistory.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Outbox.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+				)
+			},
+		},
+		"deve retornar erro: ao ler source account": {
+			InputTransaction: transaction,
+			ExpectedResult:   &failedTransaction,
+			ExpectedErr:      echo.ErrNotFound,
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.Transaction.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), destinationUserId).Times(1).Return(&destinationAccount, nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), sourceUserId).Times(1).Return(nil, echo.ErrNotFound),
+					db.Transaction.EXPECT().Create(gomock.Any(), &failedTransaction).Times(1).Return(nil),
+					db.StateHistory.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Outbox.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+				)
 			},
 		},
 		"deve retornar erro: 'Insufficient balance'": {
-			InputTransaction: failedTransaction,
-			ExpectedResult:   failedTransaction,
+			InputTransaction: transaction,
+			ExpectedResult:   &failedTransaction,
 			ExpectedErr:      echo.NewHTTPError(echo.ErrBadRequest.Code, "Insufficient balance"),
-			PrepareMock: func(mockTransactionDb *mocks.MockDabataseTransactionInterface, mockUserDb *mocks.MockDabataseUserInterface) {
-				mockTransactionDb.EXPECT().Create(gomock.Any(), failedTransaction).Times(1).Return(nil)
-				mockUserDb.EXPECT().ReadOneById(gomock.Any(), failedTransaction.SourceId).Times(1).Return(&sourceUser, nil)
-				mockUserDb.EXPECT().ReadOneById(gomock.Any(), failedTransaction.DestinationId).Times(1).Return(&destinationUser, nil)
-
-				mockTransactionDb.EXPECT().UpdateState(gomock.Any(), entity.FAILED, failedTransaction.ID).Times(1).Return(nil)
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.Transaction.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), destinationUserId).Times(1).Return(&destinationAccount, nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), sourceUserId).Times(1).Return(&poorSourceAccount, nil),
+					db.Transaction.EXPECT().Create(gomock.Any(), &failedTransaction).Times(1).Return(nil),
+					db.StateHistory.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Outbox.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+				)
 			},
 		},
-		"deve retornar erro: ao atualizar saldo source user": {
-			InputTransaction: failedTransaction,
-			ExpectedResult:   failedTransaction,
-			ExpectedErr:      echo.ErrInternalServerError,
-			PrepareMock: func(mockTransactionDb *mocks.MockDabataseTransactionInterface, mockUserDb *mocks.MockDabataseUserInterface) {
-				mockTransactionDb.EXPECT().Create(gomock.Any(), failedTransaction).Times(1).Return(nil)
-				mockUserDb.EXPECT().ReadOneById(gomock.Any(), failedTransaction.SourceId).Times(1).Return(&sourceUser2, nil)
-				mockUserDb.EXPECT().ReadOneById(gomock.Any(), failedTransaction.DestinationId).Times(1).Return(&destinationUser, nil)
-
-				mockTransactionDb.EXPECT().UpdateBalanceUser(gomock.Any(), sourceUser2.ID, sourceUserFailedTrBalanceUpdated).
-					Times(1).Return(echo.ErrInternalServerError)
-
-				mockTransactionDb.EXPECT().UpdateBalanceUser(gomock.Any(), sourceUser2.ID, sourceUser2.Balance).
-					Times(1).Return(nil)
-
-				mockTransactionDb.EXPECT().UpdateState(gomock.Any(), entity.FAILED, failedTransaction.ID).Times(1).Return(nil)
+		"deve retornar erro: source user congelado": {
+			InputTransaction: transaction,
+			ExpectedResult:   &failedTransaction,
+			ExpectedErr:      ErrUserFrozen,
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.Transaction.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), destinationUserId).Times(1).Return(&destinationAccount, nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), sourceUserId).Times(1).Return(&frozenSourceUser, nil),
+					db.Transaction.EXPECT().Create(gomock.Any(), &failedTransaction).Times(1).Return(nil),
+					db.StateHistory.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Outbox.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+				)
+			},
+		},
+		"deve retornar erro: destination user encerrado": {
+			InputTransaction: transaction,
+			ExpectedResult:   &failedTransaction,
+			ExpectedErr:      ErrUserClosed,
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.Transaction.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), destinationUserId).Times(1).Return(&closedDestinationUser, nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), sourceUserId).Times(1).Return(&sourceAccount, nil),
+					db.Transaction.EXPECT().Create(gomock.Any(), &failedTransaction).Times(1).Return(nil),
+					db.StateHistory.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Outbox.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+				)
 			},
 		},
-		"deve retornar erro: ao atualizar saldo destination user": {
-			InputTransaction: failedTransaction,
-			ExpectedResult:   failedTransaction,
+		"deve retornar erro: accounts com moedas diferentes": {
+			InputTransaction: transaction,
+			ExpectedResult:   &failedTransaction,
+			ExpectedErr:      ErrCurrencyMismatch,
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.Transaction.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), destinationUserId).Times(1).Return(&dollarDestinationAccount, nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), sourceUserId).Times(1).Return(&sourceAccount, nil),
+					db.Transaction.EXPECT().Create(gomock.Any(), &failedTransaction).Times(1).Return(nil),
+					db.StateHistory.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Outbox.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+				)
+			},
+		},
+		"deve retornar erro: ao atualizar saldo source account": {
+			InputTransaction: transaction,
+			ExpectedResult:   &failedTransaction,
+			ExpectedErr:      echo.ErrNotFound,
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.Transaction.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), destinationUserId).Times(1).Return(&destinationAccount, nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), sourceUserId).Times(1).Return(&sourceAccount, nil),
+					db.Account.EXPECT().UpdateBalance(gomock.Any(), sourceUserId, sourceUserBalanceUpdated).Times(1).Return(echo.ErrNotFound),
+					db.Transaction.EXPECT().Create(gomock.Any(), &failedTransaction).Times(1).Return(nil),
+					db.StateHistory.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Outbox.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+				)
+			},
+		},
+		"deve retornar erro: ao registrar postings": {
+			InputTransaction: transaction,
+			ExpectedResult:   &failedTransaction,
 			ExpectedErr:      echo.ErrInternalServerError,
-			PrepareMock: func(mockTransactionDb *mocks.MockDabataseTransactionInterface, mockUserDb *mocks.MockDabataseUserInterface) {
-				mockTransactionDb.EXPECT().Create(gomock.Any(), failedTransaction).Times(1).Return(nil)
-				mockUserDb.EXPECT().ReadOneById(gomock.Any(), failedTransaction.SourceId).Times(1).Return(&sourceUser2, nil)
-				mockUserDb.EXPECT().ReadOneById(gomock.Any(), failedTransaction.DestinationId).Times(1).Return(&destinationUser2, nil)
-
-				mockTransactionDb.EXPECT().UpdateBalanceUser(gomock.Any(), sourceUser2.ID, sourceUserFailedTrBalanceUpdated).
-					Times(1).Return(nil)
-
-				mockTransactionDb.EXPECT().UpdateBalanceUser(gomock.Any(), destinationUser2.ID, destinationUserFailedTrBalanceUpdated).
-					Times(1).Return(echo.ErrInternalServerError)
-
-				mockTransactionDb.EXPECT().UpdateBalanceUser(gomock.Any(), destinationUser2.ID, destinationUser2.Balance).
-					Times(1).Return(nil)
-
-				mockTransactionDb.EXPECT().UpdateBalanceUser(gomock.Any(), sourceUser2.ID, sourceUser2.Balance).
-					Times(1).Return(nil)
-
-				mockTransactionDb.EXPECT().UpdateState(gomock.Any(), entity.FAILED, failedTransaction.ID).Times(1).Return(nil)
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.Transaction.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), destinationUserId).Times(1).Return(&destinationAccount, nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), sourceUserId).Times(1).Return(&sourceAccount, nil),
+					db.Account.EXPECT().UpdateBalance(gomock.Any(), sourceUserId, sourceUserBalanceUpdated).Times(1).Return(nil),
+					db.Account.EXPECT().UpdateBalance(gomock.Any(), destinationUserId, destinationUserBalanceUpdated).Times(1).Return(nil),
+					db.Ledger.EXPECT().CreatePostings(gomock.Any(), gomock.Any()).Times(1).Return(echo.ErrInternalServerError),
+					db.Transaction.EXPECT().Create(gomock.Any(), &failedTransaction).Times(1).Return(nil),
+					db.StateHistory.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Outbox.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+				)
+			},
+		},
+		"deve retornar erro: ao atualizar saldo destination account": {
+			InputTransaction: transaction,
+			ExpectedResult:   &failedTransaction,
+			ExpectedErr:      echo.ErrNotFound,
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.Transaction.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), destinationUserId).Times(1).Return(&destinationAccount, nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), sourceUserId).Times(1).Return(&sourceAccount, nil),
+					db.Account.EXPECT().UpdateBalance(gomock.Any(), sourceUserId, sourceUserBalanceUpdated).Times(1).Return(nil),
+					db.Account.EXPECT().UpdateBalance(gomock.Any(), destinationUserId, destinationUserBalanceUpdated).Times(1).Return(echo.ErrNotFound),
+					db.Transaction.EXPECT().Create(gomock.Any(), &failedTransaction).Times(1).Return(nil),
+					db.StateHistory.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Outbox.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+				)
 			},
 		},
 	}
@@ -193,13 +377,13 @@ func TestCreate(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			ctrl, ctx := gomock.WithContext(context.Background(), t)
 
-			mockTransactionDb := mocks.NewMockDabataseTransactionInterface(ctrl)
-			mockUserDb := mocks.NewMockDabataseUserInterface(ctrl)
-			cs.PrepareMock(mockTransactionDb, mockUserDb)
+			container, db := newDatabaseContainer(ctrl)
+			cs.PrepareMock(db)
 
-			app := NewAppTransaction(&database.Container{Transaction: mockTransactionDb, User: mockUserDb})
+			app := NewAppTransaction(container)
 
-			transaction, err := app.Create(ctx, cs.InputTransaction)
+			input := cs.InputTransaction
+			transaction, err := app.Create(ctx, &input)
 			if diff := cmp.Diff(transaction, cs.ExpectedResult); diff != "" {
 				t.Error(diff)
 			}
@@ -213,120 +397,139 @@ func TestCreate(t *testing.T) {
 
 func TestIncreaseBalanceUser(t *testing.T) {
 	destinationUserId := "destination-user-id"
-	balance := entity.NewIncreaseBalanceUser(dto.IncreaseBalanceUser{
-		UserId: destinationUserId,
-		Value:  110.0,
-	})
-	transaction := &entity.Transaction{
+	balance := &entity.TransactionIncreaseBalanceUser{
+		ID:        "transaction-id",
+		AccountId: destinationUserId,
+		Value:     money.New(11000),
+	}
+
+	transaction := entity.Transaction{
 		ID:            balance.ID,
-		DestinationId: balance.UserId,
+		SourceId:      entity.FundingAccountId,
+		DestinationId: balance.AccountId,
 		Amount:        balance.Value,
+		Kind:          entity.DEPOSIT,
 	}
 
-	bookedTransaction := entity.Transaction{
-		ID:            transaction.ID,
-		SourceId:      transaction.SourceId,
-		DestinationId: transaction.DestinationId,
-		Amount:        transaction.Amount,
-		State:         entity.BOOKED,
-		StateString:   entity.BOOKED.String(),
-	}
+	failedTransaction := transaction
+	failedTransaction.State = entity.FAILED
+	failedTransaction.StateString = entity.FAILED.String()
 
-	destinationUser := &entity.User{
-		ID:        destinationUserId,
-		Name:      "Gabriel",
-		Balance:   200.0,
-		Mutex:     &sync.Mutex{},
-		CreatedAt: time.Now(),
+	destinationAccount := &entity.Account{
+		ID:               destinationUserId,
+		Name:             "Gabriel",
+		Balance:          money.New(20000),
+		AvailableBalance: money.New(20000),
+		CreatedAt:        time.Now(),
 	}
 
-	destinationUser2 := &entity.User{
-		ID:        destinationUserId,
-		Name:      "Gabriel",
-		Balance:   200.0,
-		Mutex:     &sync.Mutex{},
-		CreatedAt: time.Now(),
-	}
+	frozenAccount := *destinationAccount
+	frozenAccount.Status = entity.FROZEN
 
-	destinationUser3 := &entity.User{
-		ID:        destinationUserId,
-		Name:      "Gabriel",
-		Balance:   200.0,
-		Mutex:     &sync.Mutex{},
-		CreatedAt: time.Now(),
-	}
+	closedAccount := *destinationAccount
+	closedAccount.Status = entity.CLOSED
 
-	balanceUserUpdated := destinationUser.Balance + transaction.Amount
-	balanceUser := destinationUser2.Balance + transaction.Amount
+	balanceUserUpdated, _ := destinationAccount.Balance.Add(transaction.Amount)
 
 	cases := map[string]struct {
 		InputBalance   *entity.TransactionIncreaseBalanceUser
-		ExpectedResult float64
+		ExpectedResult money.Money
 		ExpectedErr    error
-		PrepareMock    func(mockTransactionDb *mocks.MockDabataseTransactionInterface, mockUserDb *mocks.MockDabataseUserInterface)
+		ExpectedAudit  []string
+		PrepareMock    func(db *databaseMocks)
 	}{
 		"deve retornar sucesso": {
 			InputBalance:   balance,
 			ExpectedResult: balanceUserUpdated,
 			ExpectedErr:    nil,
-			PrepareMock: func(mockTransactionDb *mocks.MockDabataseTransactionInterface, mockUserDb *mocks.MockDabataseUserInterface) {
-				mockTransactionDb.EXPECT().Create(gomock.Any(), transaction).Times(1).Return(nil)
-				mockUserDb.EXPECT().ReadOneById(gomock.Any(), balance.UserId).Times(1).Return(destinationUser, nil)
-				mockTransactionDb.EXPECT().UpdateBalanceUser(gomock.Any(), destinationUser.ID, balanceUserUpdated).
-					Times(1).Return(nil)
-
-				mockTransactionDb.EXPECT().UpdateState(gomock.Any(), bookedTransaction.State, transaction.ID).Times(1).Return(nil)
-				mockTransactionDb.EXPECT().ReadBalance(gomock.Any(), destinationUser.ID).Times(1).Return(balanceUserUpdated, nil)
+			ExpectedAudit:  []string{"transaction.created", "account.balance_updated", "transaction.booked"},
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.Transaction.EXPECT().Create(gomock.Any(), &transaction).Times(1).Return(nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), balance.AccountId).Times(1).Return(destinationAccount, nil),
+					db.Account.EXPECT().UpdateBalance(gomock.Any(), destinationAccount.ID, balanceUserUpdated).Times(1).Return(nil),
+					db.Ledger.EXPECT().CreatePostings(gomock.Any(), entity.NewPostings(&transaction)).Times(1).Return(nil),
+					db.Transaction.EXPECT().UpdateState(gomock.Any(), entity.BOOKED, transaction.ID).Times(1).Return(nil),
+					db.StateHistory.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Outbox.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Fee.EXPECT().ReadActive(gomock.Any()).Times(1).Return([]entity.FeeRule{}, nil),
+				)
 			},
 		},
 		"deve retornar erro: ao registrar transaction": {
 			InputBalance:   balance,
-			ExpectedResult: 0,
+			ExpectedResult: money.Money{},
 			ExpectedErr:    echo.ErrInternalServerError,
-			PrepareMock: func(mockTransactionDb *mocks.MockDabataseTransactionInterface, mockUserDb *mocks.MockDabataseUserInterface) {
-				mockTransactionDb.EXPECT().Create(gomock.Any(), transaction).Times(1).Return(echo.ErrInternalServerError)
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.Transaction.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(echo.ErrInternalServerError),
+					db.Transaction.EXPECT().Create(gomock.Any(), &failedTransaction).Times(1).Return(nil),
+					db.StateHistory.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Outbox.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+				)
 			},
 		},
-		"deve retornar erro: ao ler destination user": {
+		"deve retornar erro: ao ler destination account": {
 			InputBalance:   balance,
-			ExpectedResult: 0,
-			ExpectedErr:    echo.ErrInternalServerError,
-			PrepareMock: func(mockTransactionDb *mocks.MockDabataseTransactionInterface, mockUserDb *mocks.MockDabataseUserInterface) {
-				mockTransactionDb.EXPECT().Create(gomock.Any(), transaction).Times(1).Return(nil)
-				mockUserDb.EXPECT().ReadOneById(gomock.Any(), balance.UserId).Times(1).Return(nil, echo.ErrInternalServerError)
-
-				mockTransactionDb.EXPECT().UpdateState(gomock.Any(), entity.FAILED, transaction.ID).Times(1).Return(nil)
+			ExpectedResult: money.Money{},
+			ExpectedErr:    echo.ErrNotFound,
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.Transaction.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), balance.AccountId).Times(1).Return(nil, echo.ErrNotFound),
+					db.Transaction.EXPECT().Create(gomock.Any(), &failedTransaction).Times(1).Return(nil),
+					db.StateHistory.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Outbox.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+				)
 			},
 		},
-		"deve retornar erro: ao atualizar saldo destination user": {
+		"deve retornar erro: conta de sistema": {
+			InputBalance:   &entity.TransactionIncreaseBalanceUser{ID: "transaction-id", AccountId: entity.FeeAccountId, Value: money.New(11000)},
+			ExpectedResult: money.Money{},
+			ExpectedErr:    echo.NewHTTPError(echo.ErrBadRequest.Code, "System accounts can't receive deposits"),
+			PrepareMock:    func(db *databaseMocks) {},
+		},
+		"deve retornar erro: user congelado": {
 			InputBalance:   balance,
-			ExpectedResult: 0,
-			ExpectedErr:    echo.ErrInternalServerError,
-			PrepareMock: func(mockTransactionDb *mocks.MockDabataseTransactionInterface, mockUserDb *mocks.MockDabataseUserInterface) {
-				mockTransactionDb.EXPECT().Create(gomock.Any(), transaction).Times(1).Return(nil)
-				mockUserDb.EXPECT().ReadOneById(gomock.Any(), balance.UserId).Times(1).Return(destinationUser2, nil)
-
-				mockTransactionDb.EXPECT().UpdateBalanceUser(gomock.Any(), destinationUser2.ID, balanceUser).
-					Times(1).Return(echo.ErrInternalServerError)
-
-				mockTransactionDb.EXPECT().UpdateBalanceUser(gomock.Any(), destinationUser2.ID, destinationUser2.Balance).
-					Times(1).Return(nil)
-
-				mockTransactionDb.EXPECT().UpdateState(gomock.Any(), entity.FAILED, transaction.ID).Times(1).Return(nil)
+			ExpectedResult: money.Money{},
+			ExpectedErr:    ErrUserFrozen,
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.Transaction.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), balance.AccountId).Times(1).Return(&frozenAccount, nil),
+					db.Transaction.EXPECT().Create(gomock.Any(), &failedTransaction).Times(1).Return(nil),
+					db.StateHistory.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Outbox.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+				)
 			},
 		},
-		"deve retornar erro: ao ler o saldo": {
+		"deve retornar erro: user encerrado": {
 			InputBalance:   balance,
-			ExpectedResult: 0,
-			ExpectedErr:    echo.ErrInternalServerError,
-			PrepareMock: func(mockTransactionDb *mocks.MockDabataseTransactionInterface, mockUserDb *mocks.MockDabataseUserInterface) {
-				mockTransactionDb.EXPECT().Create(gomock.Any(), transaction).Times(1).Return(nil)
-				mockUserDb.EXPECT().ReadOneById(gomock.Any(), balance.UserId).Times(1).Return(destinationUser3, nil)
-				mockTransactionDb.EXPECT().UpdateBalanceUser(gomock.Any(), destinationUser3.ID, balanceUserUpdated).
-					Times(1).Return(nil)
-
-				mockTransactionDb.EXPECT().UpdateState(gomock.Any(), bookedTransaction.State, transaction.ID).Times(1).Return(nil)
-				mockTransactionDb.EXPECT().ReadBalance(gomock.Any(), destinationUser3.ID).Times(1).Return(0.0, echo.ErrInternalServerError)
+			ExpectedResult: money.Money{},
+			ExpectedErr:    ErrUserClosed,
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.Transaction.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), balance.AccountId).Times(1).Return(&closedAccount, nil),
+					db.Transaction.EXPECT().Create(gomock.Any(), &failedTransaction).Times(1).Return(nil),
+					db.StateHistory.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Outbox.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+				)
+			},
+		},
+		"deve retornar erro: ao atualizar saldo destination account": {
+			InputBalance:   balance,
+			ExpectedResult: money.Money{},
+			ExpectedErr:    echo.ErrNotFound,
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.Transaction.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), balance.AccountId).Times(1).Return(destinationAccount, nil),
+					db.Account.EXPECT().UpdateBalance(gomock.Any(), destinationAccount.ID, balanceUserUpdated).Times(1).Return(echo.ErrNotFound),
+					db.Transaction.EXPECT().Create(gomock.Any(), &failedTransaction).Times(1).Return(nil),
+					db.StateHistory.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Outbox.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+				)
 			},
 		},
 	}
@@ -334,12 +537,12 @@ func TestIncreaseBalanceUser(t *testing.T) {
 	for name, cs := range cases {
 		t.Run(name, func(t *testing.T) {
 			ctrl, ctx := gomock.WithContext(context.Background(), t)
+			ctx = audit.WithRequest(ctx, entity.AuditRequest{Actor: "jane.doe", RequestId: "request-id", ClientIP: "203.0.113.7"})
 
-			mockTransactionDb := mocks.NewMockDabataseTransactionInterface(ctrl)
-			mockUserDb := mocks.NewMockDabataseUserInterface(ctrl)
-			cs.PrepareMock(mockTransactionDb, mockUserDb)
+			container, db := newDatabaseContainer(ctrl)
+			cs.PrepareMock(db)
 
-			app := NewAppTransaction(&database.Container{Transaction: mockTransactionDb, User: mockUserDb})
+			app := NewAppTransaction(container)
 
 			balance, err := app.IncreaseBalanceUser(ctx, cs.InputBalance)
 			if diff := cmp.Diff(balance, cs.ExpectedResult); diff != "" {
@@ -349,33 +552,167 @@ func TestIncreaseBalanceUser(t *testing.T) {
 			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
 				t.Error(diff)
 			}
+
+			if cs.ExpectedAudit != nil {
+				operations := make([]string, 0, len(db.Audited))
+				for _, entry := range db.Audited {
+					operations = append(operations, entry.Operation)
+					if entry.Actor != "jane.doe" || entry.RequestId != "request-id" || entry.ClientIP != "203.0.113.7" {
+						t.Errorf("unexpected audit request %+v", entry)
+					}
+				}
+
+				if diff := cmp.Diff(operations, cs.ExpectedAudit); diff != "" {
+					t.Error(diff)
+				}
+
+				balanceEntry := db.Audited[1]
+				if string(*balanceEntry.Before) != `{"balance":"200.00","heldBalance":"0.00"}` || string(*balanceEntry.After) != `{"balance":"310.00","heldBalance":"0.00"}` {
+					t.Errorf("unexpected balance audit %s -> %s", *balanceEntry.Before, *balanceEntry.After)
+				}
+			}
 		})
 	}
 }
 
-func TestReadAll(t *testing.T) {
-	transaction := entity.NewTransaction(dto.CreateTransaction{
-		SourceUserId:      "source-user-id",
-		DestinationUserId: "destination-user-id",
-		Amount:            100.10,
-	})
-	transactions := []entity.Transaction{{
-		ID:            transaction.ID,
-		SourceId:      transaction.SourceId,
-		DestinationId: transaction.DestinationId,
-		Amount:        transaction.Amount,
-	}}
+func TestWithdraw(t *testing.T) {
+	userId := "user-id"
+
+	transaction := entity.Transaction{
+		ID:            "transaction-id",
+		SourceId:      userId,
+		DestinationId: entity.WithdrawalAccountId,
+		Amount:        money.New(10010),
+		Kind:          entity.WITHDRAWAL,
+	}
+
+	bookedTransaction := transaction
+	bookedTransaction.State = entity.BOOKED
+	bookedTransaction.StateString = entity.BOOKED.String()
+	bookedTransaction.KindString = entity.WITHDRAWAL.String()
+
+	failedTransaction := transaction
+	failedTransaction.State = entity.FAILED
+	failedTransaction.StateString = entity.FAILED.String()
+
+	user := &entity.Account{
+		ID:               userId,
+		Name:             "Gabriel",
+		Balance:          money.New(20000),
+		AvailableBalance: money.New(20000),
+		CreatedAt:        time.Now(),
+	}
+
+	poorAccount := &entity.Account{
+		ID:               userId,
+		Name:             "Gabriel",
+		Balance:          money.New(5000),
+		AvailableBalance: money.New(5000),
+		CreatedAt:        time.Now(),
+	}
+
+	userBalanceUpdated, _ := user.Balance.Sub(transaction.Amount)
 
 	cases := map[string]struct {
-		ExpectedResult []entity.Transaction
-		ExpectedErr    error
-		PrepareMock    func(mockTransactionDb *mocks.MockDabataseTransactionInterface, mockUserDb *mocks.MockDabataseUserInterface)
+		InputTransaction entity.Transaction
+		ExpectedResult   *entity.Transaction
+		ExpectedErr      error
+		PrepareMock      func(db *databaseMocks)
 	}{
 		"deve retornar sucesso": {
-			ExpectedResult: transactions,
-			ExpectedErr:    nil,
-			PrepareMock: func(mockTransactionDb *mocks.MockDabataseTransactionInterface, mockUserDb *mocks.MockDabataseUserInterface) {
-				mockTransactionDb.EXPECT().ReadAll(gomock.Any()).Times(1).Return(transactions, nil)
+			InputTransaction: transaction,
+			ExpectedResult:   &bookedTransaction,
+			ExpectedErr:      nil,
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.Transaction.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), userId).Times(1).Return(user, nil),
+					db.Account.EXPECT().UpdateBalance(gomock.Any(), userId, userBalanceUpdated).Times(1).Return(nil),
+					db.Ledger.EXPECT().CreatePostings(gomock.Any(), entity.NewPostings(&transaction)).Times(1).Return(nil),
+					db.Transaction.EXPECT().UpdateState(gomock.Any(), entity.BOOKED, transaction.ID).Times(1).Return(nil),
+					db.StateHistory.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Outbox.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Account.EXPECT().ReadOneById(gomock.Any(), gomock.Any()).Times(1).Return(&entity.Account{UserId: "user-id"}, nil),
+					db.Limit.EXPECT().ReadWithDefault(gomock.Any(), gomock.Any()).Times(1).Return([]entity.Limits{}, nil),
+				)
+			},
+		},
+		"deve retornar erro: conta de sistema": {
+			InputTransaction: entity.Transaction{SourceId: entity.FundingAccountId, DestinationId: entity.WithdrawalAccountId, Amount: money.New(10010), Kind: entity.WITHDRAWAL},
+			ExpectedResult:   nil,
+			ExpectedErr:      echo.NewHTTPError(echo.ErrBadRequest.Code, "System accounts can't withdraw"),
+			PrepareMock:      func(db *databaseMocks) {},
+		},
+		"deve retornar erro: ao registrar transaction": {
+			InputTransaction: transaction,
+			ExpectedResult:   &failedTransaction,
+			ExpectedErr:      echo.ErrInternalServerError,
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.Transaction.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(echo.ErrInternalServerError),
+					db.Transaction.EXPECT().Create(gomock.Any(), &failedTransaction).Times(1).Return(nil),
+					db.StateHistory.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Outbox.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+				)
+			},
+		},
+		"deve retornar erro: ao ler account": {
+			InputTransaction: transaction,
+			ExpectedResult:   &failedTransaction,
+			ExpectedErr:      echo.ErrNotFound,
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.Transaction.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), userId).Times(1).Return(nil, echo.ErrNotFound),
+					db.Transaction.EXPECT().Create(gomock.Any(), &failedTransaction).Times(1).Return(nil),
+					db.StateHistory.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Outbox.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+				)
+			},
+		},
+		"deve retornar erro: Insufficient balance": {
+			InputTransaction: transaction,
+			ExpectedResult:   &failedTransaction,
+			ExpectedErr:      echo.NewHTTPError(echo.ErrBadRequest.Code, "Insufficient balance"),
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.Transaction.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), userId).Times(1).Return(poorAccount, nil),
+					db.Transaction.EXPECT().Create(gomock.Any(), &failedTransaction).Times(1).Return(nil),
+					db.StateHistory.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Outbox.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+				)
+			},
+		},
+		"deve retornar erro: ao atualizar saldo": {
+			InputTransaction: transaction,
+			ExpectedResult:   &failedTransaction,
+			ExpectedErr:      echo.ErrNotFound,
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.Transaction.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), userId).Times(1).Return(user, nil),
+					db.Account.EXPECT().UpdateBalance(gomock.Any(), userId, userBalanceUpdated).Times(1).Return(echo.ErrNotFound),
+					db.Transaction.EXPECT().Create(gomock.Any(), &failedTransaction).Times(1).Return(nil),
+					db.StateHistory.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Outbox.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+				)
+			},
+		},
+		"deve retornar erro: ao registrar postings": {
+			InputTransaction: transaction,
+			ExpectedResult:   &failedTransaction,
+			ExpectedErr:      echo.ErrInternalServerError,
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.Transaction.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), userId).Times(1).Return(user, nil),
+					db.Account.EXPECT().UpdateBalance(gomock.Any(), userId, userBalanceUpdated).Times(1).Return(nil),
+					db.Ledger.EXPECT().CreatePostings(gomock.Any(), gomock.Any()).Times(1).Return(echo.ErrInternalServerError),
+					db.Transaction.EXPECT().Create(gomock.Any(), &failedTransaction).Times(1).Return(nil),
+					db.StateHistory.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Outbox.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+				)
 			},
 		},
 	}
@@ -384,17 +721,2239 @@ func TestReadAll(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			ctrl, ctx := gomock.WithContext(context.Background(), t)
 
-			mockTransactionDb := mocks.NewMockDabataseTransactionInterface(ctrl)
-			mockUserDb := mocks.NewMockDabataseUserInterface(ctrl)
-			cs.PrepareMock(mockTransactionDb, mockUserDb)
+			container, db := newDatabaseContainer(ctrl)
+			cs.PrepareMock(db)
 
-			app := NewAppTransaction(&database.Container{Transaction: mockTransactionDb, User: mockUserDb})
+			app := NewAppTransaction(container)
 
-			transactions, err := app.ReadAll(ctx)
-			if diff := cmp.Diff(transactions, cs.ExpectedResult); diff != "" {
+			input := cs.InputTransaction
+			transaction, err := app.Withdraw(ctx, &input)
+			if diff := cmp.Diff(transaction, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestCheckStatus(t *testing.T) {
+	accounts := func(source, destination entity.StatusUser) map[string]*entity.Account {
+		return map[string]*entity.Account{
+			"source-user-id":      {ID: "source-user-id", Status: source},
+			"destination-user-id": {ID: "destination-user-id", Status: destination},
+		}
+	}
+
+	cases := map[string]struct {
+		Kind        entity.KindTransaction
+		Accounts    map[string]*entity.Account
+		ExpectedErr error
+	}{
+		"transfer entre users ativos":  {entity.TRANSFER, accounts(entity.ACTIVE, entity.ACTIVE), nil},
+		"transfer de user congelado":   {entity.TRANSFER, accounts(entity.FROZEN, entity.ACTIVE), ErrUserFrozen},
+		"transfer para user congelado": {entity.TRANSFER, accounts(entity.ACTIVE, entity.FROZEN), ErrUserFrozen},
+		"transfer para user encerrado": {entity.TRANSFER, accounts(entity.ACTIVE, entity.CLOSED), ErrUserClosed},
+		"reversal de user congelado":   {entity.REVERSAL, accounts(entity.FROZEN, entity.ACTIVE), nil},
+		"reversal para user encerrado": {entity.REVERSAL, accounts(entity.ACTIVE, entity.CLOSED), ErrUserClosed},
+		"interest de user congelado":   {entity.INTEREST, accounts(entity.FROZEN, entity.ACTIVE), nil},
+		"sweep de user congelado":      {entity.SWEEP, accounts(entity.FROZEN, entity.ACTIVE), nil},
+		"sweep para user congelado":    {entity.SWEEP, accounts(entity.ACTIVE, entity.FROZEN), ErrUserFrozen},
+		"sweep de user encerrado":      {entity.SWEEP, accounts(entity.CLOSED, entity.ACTIVE), ErrUserClosed},
+		"withdrawal sem destination":   {entity.WITHDRAWAL, map[string]*entity.Account{"source-user-id": {Status: entity.ACTIVE}}, nil},
+		"withdrawal de user congelado": {entity.WITHDRAWAL, map[string]*entity.Account{"source-user-id": {Status: entity.FROZEN}}, ErrUserFrozen},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			transaction := &entity.Transaction{SourceId: "source-user-id", DestinationId: "destination-user-id", Kind: cs.Kind}
+
+			err := checkStatus(transaction, cs.Accounts)
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestCheckCurrency(t *testing.T) {
+	cases := map[string]struct {
+		Accounts    map[string]*entity.Account
+		ExpectedErr error
+	}{
+		"mesma moeda": {map[string]*entity.Account{
+			"source-account-id":      {Currency: "BRL"},
+			"destination-account-id": {Currency: "BRL"},
+		}, nil},
+		"moedas diferentes": {map[string]*entity.Account{
+			"source-account-id":      {Currency: "BRL"},
+			"destination-account-id": {Currency: "USD"},
+		}, ErrCurrencyMismatch},
+		"conta do sistema": {map[string]*entity.Account{
+			"source-account-id": {Currency: "USD"},
+		}, nil},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			transaction := &entity.Transaction{SourceId: "source-account-id", DestinationId: "destination-account-id"}
+
+			err := checkCurrency(transaction, cs.Accounts)
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestSweep(t *testing.T) {
+	userId := "user-id"
+	destinationUserId := "destination-user-id"
+
+	user := &entity.Account{
+		ID:               userId,
+		Status:           entity.FROZEN,
+		Balance:          money.New(2550),
+		AvailableBalance: money.New(2550),
+	}
+
+	emptyAccount := &entity.Account{ID: userId, Balance: money.New(0), AvailableBalance: money.New(0)}
+
+	destinationAccount := &entity.Account{
+		ID:               destinationUserId,
+		Balance:          money.New(1000),
+		AvailableBalance: money.New(1000),
+	}
+
+	frozenDestinationAccount := *destinationAccount
+	frozenDestinationAccount.Status = entity.FROZEN
+
+	cases := map[string]struct {
+		DestinationId  string
+		ExpectedAmount *money.Money
+		ExpectedErr    error
+		PrepareMock    func(db *databaseMocks)
+	}{
+		"deve retornar sucesso": {
+			DestinationId:  destinationUserId,
+			ExpectedAmount: &user.Balance,
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), destinationUserId).Times(1).Return(destinationAccount, nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), userId).Times(1).Return(user, nil),
+					db.Transaction.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), destinationUserId).Times(1).Return(destinationAccount, nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), userId).Times(1).Return(user, nil),
+					db.Account.EXPECT().UpdateBalance(gomock.Any(), userId, money.New(0)).Times(1).Return(nil),
+					db.Account.EXPECT().UpdateBalance(gomock.Any(), destinationUserId, money.New(3550)).Times(1).Return(nil),
+					db.Ledger.EXPECT().CreatePostings(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Transaction.EXPECT().UpdateState(gomock.Any(), entity.BOOKED, gomock.Any()).Times(1).Return(nil),
+					db.StateHistory.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Outbox.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+				)
+			},
+		},
+		"deve retornar sucesso: para conta do sistema": {
+			DestinationId:  entity.WithdrawalAccountId,
+			ExpectedAmount: &user.Balance,
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), userId).Times(1).Return(user, nil),
+					db.Transaction.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), userId).Times(1).Return(user, nil),
+					db.Account.EXPECT().UpdateBalance(gomock.Any(), userId, money.New(0)).Times(1).Return(nil),
+					db.Ledger.EXPECT().CreatePostings(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Transaction.EXPECT().UpdateState(gomock.Any(), entity.BOOKED, gomock.Any()).Times(1).Return(nil),
+					db.StateHistory.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Outbox.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+				)
+			},
+		},
+		"deve retornar sucesso: sem saldo": {
+			DestinationId: destinationUserId,
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), destinationUserId).Times(1).Return(destinationAccount, nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), userId).Times(1).Return(emptyAccount, nil),
+				)
+			},
+		},
+		"deve retornar erro: para a propria account": {
+			DestinationId: userId,
+			ExpectedErr:   echo.NewHTTPError(echo.ErrBadRequest.Code, "The balance must be swept to another account"),
+			PrepareMock:   func(db *databaseMocks) {},
+		},
+		"deve retornar erro: destination user congelado": {
+			DestinationId: destinationUserId,
+			ExpectedErr:   ErrUserFrozen,
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), destinationUserId).Times(1).Return(&frozenDestinationAccount, nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), userId).Times(1).Return(user, nil),
+					db.Transaction.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), destinationUserId).Times(1).Return(&frozenDestinationAccount, nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), userId).Times(1).Return(user, nil),
+				)
+			},
+		},
+		"deve retornar erro: ao ler destination account": {
+			DestinationId: destinationUserId,
+			ExpectedErr:   echo.ErrNotFound,
+			PrepareMock: func(db *databaseMocks) {
+				db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), destinationUserId).Times(1).Return(nil, echo.ErrNotFound)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			container, db := newDatabaseContainer(ctrl)
+			cs.PrepareMock(db)
+
+			sweep, err := Sweep(ctx, container, userId, cs.DestinationId)
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
 				t.Error(diff)
 			}
 
+			if cs.ExpectedAmount == nil {
+				if sweep != nil {
+					t.Errorf("unexpected sweep %+v", sweep)
+				}
+				return
+			}
+
+			if sweep.Kind != entity.SWEEP || sweep.SourceId != userId || sweep.DestinationId != cs.DestinationId || sweep.Amount != *cs.ExpectedAmount || sweep.State != entity.BOOKED {
+				t.Errorf("unexpected sweep %+v", sweep)
+			}
+		})
+	}
+}
+
+func TestReverse(t *testing.T) {
+	sourceUserId := "source-user-id"
+	destinationUserId := "destination-user-id"
+
+	original := &entity.Transaction{
+		ID:            "transaction-id",
+		SourceId:      sourceUserId,
+		DestinationId: destinationUserId,
+		Amount:        money.New(10000),
+		Kind:          entity.TRANSFER,
+		State:         entity.BOOKED,
+	}
+
+	// The reversal moves the original to a new state, so every case reads its
+	// own copy.
+	readOriginal := func() *entity.Transaction {
+		transaction := *original
+		return &transaction
+	}
+
+	reversedOriginal := *original
+	reversedOriginal.State = entity.REVERSED
+
+	reversalOriginal := *original
+	reversalOriginal.Kind = entity.REVERSAL
+
+	sourceAccount := &entity.Account{
+		ID:               sourceUserId,
+		Name:             "Gabriel",
+		Balance:          money.New(0),
+		AvailableBalance: money.New(0),
+	}
+
+	destinationAccount := &entity.Account{
+		ID:               destinationUserId,
+		Name:             "João",
+		Balance:          money.New(10000),
+		AvailableBalance: money.New(10000),
+	}
+
+	poorDestinationAccount := &entity.Account{
+		ID:               destinationUserId,
+		Name:             "João",
+		Balance:          money.New(1000),
+		AvailableBalance: money.New(1000),
+	}
+
+	newReversal := func(amount money.Money, state entity.StatesTransaction) *entity.Transaction {
+		reversal := entity.NewReversal(original, amount)
+		reversal.State = state
+		reversal.StateString = state.String()
+		if state == entity.BOOKED {
+			reversal.KindString = reversal.Kind.String()
+		}
+		return reversal
+	}
+
+	cases := map[string]struct {
+		InputAmount    money.Money
+		ExpectedResult *entity.Transaction
+		ExpectedErr    error
+		PrepareMock    func(db *databaseMocks)
+	}{
+		"deve retornar sucesso: estorno total": {
+			InputAmount:    money.Money{},
+			ExpectedResult: newReversal(money.New(10000), entity.BOOKED),
+			ExpectedErr:    nil,
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.Transaction.EXPECT().ReadOneByIdForUpdate(gomock.Any(), original.ID).Times(1).Return(readOriginal(), nil),
+					db.Transaction.EXPECT().ReadReversedAmount(gomock.Any(), original.ID).Times(1).Return(money.New(0), nil),
+					db.Transaction.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), destinationUserId).Times(1).Return(destinationAccount, nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), sourceUserId).Times(1).Return(sourceAccount, nil),
+					db.Account.EXPECT().UpdateBalance(gomock.Any(), destinationUserId, money.New(0)).Times(1).Return(nil),
+					db.Account.EXPECT().UpdateBalance(gomock.Any(), sourceUserId, money.New(10000)).Times(1).Return(nil),
+					db.Ledger.EXPECT().CreatePostings(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Transaction.EXPECT().UpdateState(gomock.Any(), entity.BOOKED, gomock.Any()).Times(1).Return(nil),
+					db.StateHistory.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Outbox.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Transaction.EXPECT().UpdateState(gomock.Any(), entity.REVERSED, original.ID).Times(1).Return(nil),
+					db.StateHistory.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Outbox.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+				)
+			},
+		},
+		"deve retornar sucesso: estorno parcial": {
+			InputAmount:    money.New(3000),
+			ExpectedResult: newReversal(money.New(3000), entity.BOOKED),
+			ExpectedErr:    nil,
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.Transaction.EXPECT().ReadOneByIdForUpdate(gomock.Any(), original.ID).Times(1).Return(readOriginal(), nil),
+					db.Transaction.EXPECT().ReadReversedAmount(gomock.Any(), original.ID).Times(1).Return(money.New(5000), nil),
+					db.Transaction.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), destinationUserId).Times(1).Return(destinationAccount, nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), sourceUserId).Times(1).Return(sourceAccount, nil),
+					db.Account.EXPECT().UpdateBalance(gomock.Any(), destinationUserId, money.New(7000)).Times(1).Return(nil),
+					db.Account.EXPECT().UpdateBalance(gomock.Any(), sourceUserId, money.New(3000)).Times(1).Return(nil),
+					db.Ledger.EXPECT().CreatePostings(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Transaction.EXPECT().UpdateState(gomock.Any(), entity.BOOKED, gomock.Any()).Times(1).Return(nil),
+					db.StateHistory.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Outbox.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Transaction.EXPECT().UpdateState(gomock.Any(), entity.PARTIALLY_REVERSED, original.ID).Times(1).Return(nil),
+					db.StateHistory.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Outbox.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+				)
+			},
+		},
+		"deve retornar erro: transaction nao encontrada": {
+			InputAmount:    money.Money{},
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrNotFound,
+			PrepareMock: func(db *databaseMocks) {
+				db.Transaction.EXPECT().ReadOneByIdForUpdate(gomock.Any(), original.ID).Times(1).Return(nil, echo.ErrNotFound)
+			},
+		},
+		"deve retornar erro: transaction ja estornada": {
+			InputAmount:    money.Money{},
+			ExpectedResult: nil,
+			ExpectedErr:    echo.NewHTTPError(echo.ErrConflict.Code, "Transaction can't be reversed"),
+			PrepareMock: func(db *databaseMocks) {
+				db.Transaction.EXPECT().ReadOneByIdForUpdate(gomock.Any(), original.ID).Times(1).Return(&reversedOriginal, nil)
+			},
+		},
+		"deve retornar erro: estorno de estorno": {
+			InputAmount:    money.Money{},
+			ExpectedResult: nil,
+			ExpectedErr:    echo.NewHTTPError(echo.ErrConflict.Code, "Transaction can't be reversed"),
+			PrepareMock: func(db *databaseMocks) {
+				db.Transaction.EXPECT().ReadOneByIdForUpdate(gomock.Any(), original.ID).Times(1).Return(&reversalOriginal, nil)
+			},
+		},
+		"deve retornar erro: valor maior que o disponivel": {
+			InputAmount:    money.New(6000),
+			ExpectedResult: nil,
+			ExpectedErr:    echo.NewHTTPError(echo.ErrBadRequest.Code, "Amount exceeds the reversible amount"),
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.Transaction.EXPECT().ReadOneByIdForUpdate(gomock.Any(), original.ID).Times(1).Return(readOriginal(), nil),
+					db.Transaction.EXPECT().ReadReversedAmount(gomock.Any(), original.ID).Times(1).Return(money.New(5000), nil),
+				)
+			},
+		},
+		"deve retornar erro: Insufficient balance": {
+			InputAmount:    money.Money{},
+			ExpectedResult: nil,
+			ExpectedErr:    echo.NewHTTPError(echo.ErrBadRequest.Code, "Insufficient balance"),
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.Transaction.EXPECT().ReadOneByIdForUpdate(gomock.Any(), original.ID).Times(1).Return(readOriginal(), nil),
+					db.Transaction.EXPECT().ReadReversedAmount(gomock.Any(), original.ID).Times(1).Return(money.New(0), nil),
+					db.Transaction.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), destinationUserId).Times(1).Return(poorDestinationAccount, nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), sourceUserId).Times(1).Return(sourceAccount, nil),
+					db.Transaction.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).
+						Do(func(ctx context.Context, transaction *entity.Transaction) {
+							if transaction.State != entity.FAILED {
+								t.Errorf("expected FAILED reversal, got %s", transaction.State)
+							}
+						}).Return(nil),
+					db.StateHistory.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Outbox.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+				)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			container, db := newDatabaseContainer(ctrl)
+			cs.PrepareMock(db)
+
+			app := NewAppTransaction(container)
+
+			reversal, err := app.Reverse(ctx, original.ID, cs.InputAmount)
+			if diff := cmp.Diff(reversal, cs.ExpectedResult, cmpopts.IgnoreFields(entity.Transaction{}, "ID")); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestReadAll(t *testing.T) {
+	transaction := entity.NewTransaction(dto.CreateTransaction{
+		SourceUserId:      "source-user-id",
+		DestinationUserId: "destination-user-id",
+		Amount:            money.New(10010),
+	})
+	transactions := []entity.Transaction{{
+		ID:            transaction.ID,
+		SourceId:      transaction.SourceId,
+		DestinationId: transaction.DestinationId,
+		Amount:        transaction.Amount,
+	}}
+
+	cases := map[string]struct {
+		ExpectedResult []entity.Transaction
+		ExpectedErr    error
+		PrepareMock    func(db *databaseMocks)
+	}{
+		"deve retornar sucesso": {
+			ExpectedResult: transactions,
+			ExpectedErr:    nil,
+			PrepareMock: func(db *databaseMocks) {
+				db.Transaction.EXPECT().ReadAll(gomock.Any()).Times(1).Return(transactions, nil)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			container, db := newDatabaseContainer(ctrl)
+			cs.PrepareMock(db)
+
+			app := NewAppTransaction(container)
+
+			transactions, err := app.ReadAll(ctx)
+			if diff := cmp.Diff(transactions, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestReadHistory(t *testing.T) {
+	transaction := &entity.Transaction{
+		ID:    "transaction-id",
+		State: entity.BOOKED,
+	}
+
+	history := []entity.StateTransition{{
+		ID:            1,
+		TransactionId: transaction.ID,
+		From:          entity.PENDING,
+		To:            entity.BOOKED,
+		Reason:        "transfer booked",
+	}}
+
+	expectedHistory := []entity.StateTransition{{
+		ID:            1,
+		TransactionId: transaction.ID,
+		From:          entity.PENDING,
+		FromString:    "PENDING",
+		To:            entity.BOOKED,
+		ToString:      "BOOKED",
+		Reason:        "transfer booked",
+	}}
+
+	cases := map[string]struct {
+		ExpectedResult []entity.StateTransition
+		ExpectedErr    error
+		PrepareMock    func(db *databaseMocks)
+	}{
+		"deve retornar sucesso": {
+			ExpectedResult: expectedHistory,
+			ExpectedErr:    nil,
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.Transaction.EXPECT().ReadOneById(gomock.Any(), transaction.ID).Times(1).Return(transaction, nil),
+					db.StateHistory.EXPECT().ReadByTransactionId(gomock.Any(), transaction.ID).Times(1).Return(append([]entity.StateTransition(nil), history...), nil),
+				)
+			},
+		},
+		"deve retornar erro: transaction nao encontrada": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrNotFound,
+			PrepareMock: func(db *databaseMocks) {
+				db.Transaction.EXPECT().ReadOneById(gomock.Any(), transaction.ID).Times(1).Return(nil, echo.ErrNotFound)
+			},
+		},
+		"deve retornar erro: ao ler historico": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrInternalServerError,
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.Transaction.EXPECT().ReadOneById(gomock.Any(), transaction.ID).Times(1).Return(transaction, nil),
+					db.StateHistory.EXPECT().ReadByTransactionId(gomock.Any(), transaction.ID).Times(1).Return(nil, echo.ErrInternalServerError),
+				)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			container, db := newDatabaseContainer(ctrl)
+			cs.PrepareMock(db)
+
+			app := NewAppTransaction(container)
+
+			history, err := app.ReadHistory(ctx, transaction.ID)
+			if diff := cmp.Diff(history, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestStateError(t *testing.T) {
+	err := stateError(&entity.ErrInvalidTransition{From: entity.FAILED, To: entity.BOOKED})
+	if diff := cmp.Diff(err, echo.NewHTTPError(echo.ErrConflict.Code, "transaction can't move from FAILED to BOOKED")); diff != "" {
+		t.Error(diff)
+	}
+
+	err = stateError(echo.ErrInternalServerError)
+	if diff := cmp.Diff(err, echo.ErrInternalServerError); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	sourceUserId := "source-user-id"
+	destinationUserId := "destination-user-id"
+	expiresAt := time.Now().Add(time.Hour)
+
+	transaction := entity.Transaction{
+		ID:            "transaction-id",
+		SourceId:      sourceUserId,
+		DestinationId: destinationUserId,
+		Amount:        money.New(10000),
+		Kind:          entity.TRANSFER,
+		ExpiresAt:     &expiresAt,
+	}
+
+	authorizedTransaction := transaction
+	authorizedTransaction.State = entity.AUTHORIZED
+	authorizedTransaction.StateString = entity.AUTHORIZED.String()
+	authorizedTransaction.KindString = entity.TRANSFER.String()
+
+	failedTransaction := transaction
+	failedTransaction.State = entity.FAILED
+	failedTransaction.StateString = entity.FAILED.String()
+
+	expiredTransaction := transaction
+	expiredAt := time.Now().Add(-time.Hour)
+	expiredTransaction.ExpiresAt = &expiredAt
+
+	sourceAccount := &entity.Account{
+		ID:               sourceUserId,
+		Balance:          money.New(20000),
+		HeldBalance:      money.New(5000),
+		AvailableBalance: money.New(15000),
+	}
+
+	poorSourceAccount := &entity.Account{
+		ID:               sourceUserId,
+		Balance:          money.New(20000),
+		HeldBalance:      money.New(15000),
+		AvailableBalance: money.New(5000),
+	}
+
+	destinationAccount := &entity.Account{
+		ID:               destinationUserId,
+		Balance:          money.New(0),
+		AvailableBalance: money.New(0),
+	}
+
+	cases := map[string]struct {
+		InputTransaction entity.Transaction
+		ExpectedResult   *entity.Transaction
+		ExpectedErr      error
+		PrepareMock      func(db *databaseMocks)
+	}{
+		"deve retornar sucesso": {
+			InputTransaction: transaction,
+			ExpectedResult:   &authorizedTransaction,
+			ExpectedErr:      nil,
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.Transaction.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), destinationUserId).Times(1).Return(destinationAccount, nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), sourceUserId).Times(1).Return(sourceAccount, nil),
+					db.Account.EXPECT().UpdateHeldBalance(gomock.Any(), sourceUserId, money.New(15000)).Times(1).Return(nil),
+					db.Transaction.EXPECT().UpdateState(gomock.Any(), entity.AUTHORIZED, transaction.ID).Times(1).Return(nil),
+					db.StateHistory.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Outbox.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Account.EXPECT().ReadOneById(gomock.Any(), sourceUserId).Times(1).Return(&entity.Account{ID: sourceUserId, UserId: sourceUserId}, nil),
+					db.Limit.EXPECT().ReadWithDefault(gomock.Any(), sourceUserId).Times(1).Return([]entity.Limits{}, nil),
+				)
+			},
+		},
+		"deve retornar erro: limite por transacao": {
+			InputTransaction: transaction,
+			ExpectedResult:   &failedTransaction,
+			ExpectedErr:      echo.NewHTTPError(echo.ErrBadRequest.Code, "PER_TRANSACTION limit of 50.00 exceeded, 50.00 left"),
+			PrepareMock: func(db *databaseMocks) {
+				perTransaction := money.New(5000)
+
+				gomock.InOrder(
+					db.Transaction.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), destinationUserId).Times(1).Return(destinationAccount, nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), sourceUserId).Times(1).Return(sourceAccount, nil),
+					db.Account.EXPECT().UpdateHeldBalance(gomock.Any(), sourceUserId, money.New(15000)).Times(1).Return(nil),
+					db.Transaction.EXPECT().UpdateState(gomock.Any(), entity.AUTHORIZED, transaction.ID).Times(1).Return(nil),
+					db.StateHistory.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Outbox.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Account.EXPECT().ReadOneById(gomock.Any(), sourceUserId).Times(1).Return(&entity.Account{ID: sourceUserId, UserId: sourceUserId}, nil),
+					db.Limit.EXPECT().ReadWithDefault(gomock.Any(), sourceUserId).Times(1).Return([]entity.Limits{
+						{UserId: entity.DefaultLimitsId, PerTransaction: &perTransaction},
+					}, nil),
+					db.Transaction.EXPECT().Create(gomock.Any(), &failedTransaction).Times(1).Return(nil),
+					db.StateHistory.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Outbox.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+				)
+			},
+		},
+		"deve retornar erro: source e destination iguais": {
+			InputTransaction: entity.Transaction{SourceId: sourceUserId, DestinationId: sourceUserId},
+			ExpectedResult:   nil,
+			ExpectedErr:      echo.NewHTTPError(echo.ErrBadRequest.Code, "Source and destination accounts must be different"),
+			PrepareMock:      func(db *databaseMocks) {},
+		},
+		"deve retornar erro: conta de sistema": {
+			InputTransaction: entity.Transaction{SourceId: sourceUserId, DestinationId: entity.WithdrawalAccountId},
+			ExpectedResult:   nil,
+			ExpectedErr:      echo.NewHTTPError(echo.ErrBadRequest.Code, "System accounts can't be the source or destination of an authorization"),
+			PrepareMock:      func(db *databaseMocks) {},
+		},
+		"deve retornar erro: expiracao no passado": {
+			InputTransaction: expiredTransaction,
+			ExpectedResult:   nil,
+			ExpectedErr:      echo.NewHTTPError(echo.ErrBadRequest.Code, "Authorization must expire in the future"),
+			PrepareMock:      func(db *databaseMocks) {},
+		},
+		"deve retornar erro: Insufficient balance": {
+			InputTransaction: transaction,
+			ExpectedResult:   &failedTransaction,
+			ExpectedErr:      echo.NewHTTPError(echo.ErrBadRequest.Code, "Insufficient balance"),
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.Transaction.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), destinationUserId).Times(1).Return(destinationAccount, nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), sourceUserId).Times(1).Return(poorSourceAccount, nil),
+					db.Transaction.EXPECT().Create(gomock.Any(), &failedTransaction).Times(1).Return(nil),
+					db.StateHistory.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Outbox.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+				)
+			},
+		},
+		"deve retornar erro: ao atualizar saldo retido": {
+			InputTransaction: transaction,
+			ExpectedResult:   &failedTransaction,
+			ExpectedErr:      echo.ErrNotFound,
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.Transaction.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), destinationUserId).Times(1).Return(destinationAccount, nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), sourceUserId).Times(1).Return(sourceAccount, nil),
+					db.Account.EXPECT().UpdateHeldBalance(gomock.Any(), sourceUserId, money.New(15000)).Times(1).Return(echo.ErrNotFound),
+					db.Transaction.EXPECT().Create(gomock.Any(), &failedTransaction).Times(1).Return(nil),
+					db.StateHistory.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Outbox.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+				)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			container, db := newDatabaseContainer(ctrl)
+			cs.PrepareMock(db)
+
+			app := NewAppTransaction(container)
+
+			input := cs.InputTransaction
+			transaction, err := app.Authorize(ctx, &input)
+			if diff := cmp.Diff(transaction, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestCapture(t *testing.T) {
+	sourceUserId := "source-user-id"
+	destinationUserId := "destination-user-id"
+	expiresAt := time.Now().Add(time.Hour)
+	expiredAt := time.Now().Add(-time.Hour)
+
+	authorization := entity.Transaction{
+		ID:            "transaction-id",
+		SourceId:      sourceUserId,
+		DestinationId: destinationUserId,
+		Amount:        money.New(10000),
+		Kind:          entity.TRANSFER,
+		State:         entity.AUTHORIZED,
+		ExpiresAt:     &expiresAt,
+	}
+
+	// Every case reads its own copy, since capturing changes the transaction.
+	readAuthorization := func(state entity.StatesTransaction, expiresAt *time.Time) *entity.Transaction {
+		transaction := authorization
+		transaction.State = state
+		transaction.ExpiresAt = expiresAt
+		return &transaction
+	}
+
+	captured := func(amount money.Money) *entity.Transaction {
+		transaction := authorization
+		transaction.Amount = amount
+		transaction.State = entity.BOOKED
+		transaction.StateString = entity.BOOKED.String()
+		transaction.KindString = entity.TRANSFER.String()
+		return &transaction
+	}
+
+	heldSourceAccount := &entity.Account{
+		ID:               sourceUserId,
+		Balance:          money.New(20000),
+		HeldBalance:      money.New(10000),
+		AvailableBalance: money.New(10000),
+	}
+
+	releasedSourceAccount := &entity.Account{
+		ID:               sourceUserId,
+		Balance:          money.New(20000),
+		HeldBalance:      money.New(0),
+		AvailableBalance: money.New(20000),
+	}
+
+	destinationAccount := &entity.Account{
+		ID:               destinationUserId,
+		Balance:          money.New(0),
+		AvailableBalance: money.New(0),
+	}
+
+	cases := map[string]struct {
+		InputAmount    money.Money
+		ExpectedResult *entity.Transaction
+		ExpectedErr    error
+		PrepareMock    func(db *databaseMocks)
+	}{
+		"deve retornar sucesso: captura total": {
+			InputAmount:    money.Money{},
+			ExpectedResult: captured(money.New(10000)),
+			ExpectedErr:    nil,
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.Transaction.EXPECT().ReadOneByIdForUpdate(gomock.Any(), authorization.ID).Times(1).Return(readAuthorization(entity.AUTHORIZED, &expiresAt), nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), destinationUserId).Times(1).Return(destinationAccount, nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), sourceUserId).Times(1).Return(heldSourceAccount, nil),
+					db.Account.EXPECT().UpdateHeldBalance(gomock.Any(), sourceUserId, money.New(0)).Times(1).Return(nil),
+					db.Transaction.EXPECT().UpdateAmount(gomock.Any(), authorization.ID, money.New(10000)).Times(1).Return(nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), destinationUserId).Times(1).Return(destinationAccount, nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), sourceUserId).Times(1).Return(releasedSourceAccount, nil),
+					db.Account.EXPECT().UpdateBalance(gomock.Any(), sourceUserId, money.New(10000)).Times(1).Return(nil),
+					db.Account.EXPECT().UpdateBalance(gomock.Any(), destinationUserId, money.New(10000)).Times(1).Return(nil),
+					db.Ledger.EXPECT().CreatePostings(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Transaction.EXPECT().UpdateState(gomock.Any(), entity.BOOKED, authorization.ID).Times(1).Return(nil),
+					db.StateHistory.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Outbox.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+				)
+			},
+		},
+		"deve retornar sucesso: captura parcial": {
+			InputAmount:    money.New(3000),
+			ExpectedResult: captured(money.New(3000)),
+			ExpectedErr:    nil,
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.Transaction.EXPECT().ReadOneByIdForUpdate(gomock.Any(), authorization.ID).Times(1).Return(readAuthorization(entity.AUTHORIZED, &expiresAt), nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), destinationUserId).Times(1).Return(destinationAccount, nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), sourceUserId).Times(1).Return(heldSourceAccount, nil),
+					db.Account.EXPECT().UpdateHeldBalance(gomock.Any(), sourceUserId, money.New(0)).Times(1).Return(nil),
+					db.Transaction.EXPECT().UpdateAmount(gomock.Any(), authorization.ID, money.New(3000)).Times(1).Return(nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), destinationUserId).Times(1).Return(destinationAccount, nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), sourceUserId).Times(1).Return(releasedSourceAccount, nil),
+					db.Account.EXPECT().UpdateBalance(gomock.Any(), sourceUserId, money.New(17000)).Times(1).Return(nil),
+					db.Account.EXPECT().UpdateBalance(gomock.Any(), destinationUserId, money.New(3000)).Times(1).Return(nil),
+					db.Ledger.EXPECT().CreatePostings(gomock.Any(), entity.NewPostings(captured(money.New(3000)))).Times(1).Return(nil),
+					db.Transaction.EXPECT().UpdateState(gomock.Any(), entity.BOOKED, authorization.ID).Times(1).Return(nil),
+					db.StateHistory.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Outbox.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+				)
+			},
+		},
+		"deve retornar erro: transaction nao encontrada": {
+			InputAmount:    money.Money{},
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrNotFound,
+			PrepareMock: func(db *databaseMocks) {
+				db.Transaction.EXPECT().ReadOneByIdForUpdate(gomock.Any(), authorization.ID).Times(1).Return(nil, echo.ErrNotFound)
+			},
+		},
+		"deve retornar erro: transaction nao autorizada": {
+			InputAmount:    money.Money{},
+			ExpectedResult: nil,
+			ExpectedErr:    echo.NewHTTPError(echo.ErrConflict.Code, "Transaction is not authorized"),
+			PrepareMock: func(db *databaseMocks) {
+				db.Transaction.EXPECT().ReadOneByIdForUpdate(gomock.Any(), authorization.ID).Times(1).Return(readAuthorization(entity.CANCELLED, &expiresAt), nil)
+			},
+		},
+		"deve retornar erro: autorizacao expirada": {
+			InputAmount:    money.Money{},
+			ExpectedResult: nil,
+			ExpectedErr:    echo.NewHTTPError(echo.ErrConflict.Code, "Authorization expired"),
+			PrepareMock: func(db *databaseMocks) {
+				db.Transaction.EXPECT().ReadOneByIdForUpdate(gomock.Any(), authorization.ID).Times(1).Return(readAuthorization(entity.AUTHORIZED, &expiredAt), nil)
+			},
+		},
+		"deve retornar erro: valor maior que o autorizado": {
+			InputAmount:    money.New(10001),
+			ExpectedResult: nil,
+			ExpectedErr:    echo.NewHTTPError(echo.ErrBadRequest.Code, "Amount exceeds the authorized amount"),
+			PrepareMock: func(db *databaseMocks) {
+				db.Transaction.EXPECT().ReadOneByIdForUpdate(gomock.Any(), authorization.ID).Times(1).Return(readAuthorization(entity.AUTHORIZED, &expiresAt), nil)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			container, db := newDatabaseContainer(ctrl)
+			cs.PrepareMock(db)
+
+			app := NewAppTransaction(container)
+
+			transaction, err := app.Capture(ctx, authorization.ID, cs.InputAmount)
+			if diff := cmp.Diff(transaction, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestVoid(t *testing.T) {
+	sourceUserId := "source-user-id"
+	expiresAt := time.Now().Add(time.Hour)
+
+	authorization := entity.Transaction{
+		ID:            "transaction-id",
+		SourceId:      sourceUserId,
+		DestinationId: "destination-user-id",
+		Amount:        money.New(10000),
+		Kind:          entity.TRANSFER,
+		State:         entity.AUTHORIZED,
+		ExpiresAt:     &expiresAt,
+	}
+
+	readAuthorization := func(state entity.StatesTransaction) *entity.Transaction {
+		transaction := authorization
+		transaction.State = state
+		return &transaction
+	}
+
+	voided := authorization
+	voided.State = entity.CANCELLED
+	voided.StateString = entity.CANCELLED.String()
+	voided.KindString = entity.TRANSFER.String()
+
+	sourceAccount := &entity.Account{
+		ID:               sourceUserId,
+		Balance:          money.New(20000),
+		HeldBalance:      money.New(12000),
+		AvailableBalance: money.New(8000),
+	}
+
+	cases := map[string]struct {
+		ExpectedResult *entity.Transaction
+		ExpectedErr    error
+		PrepareMock    func(db *databaseMocks)
+	}{
+		"deve retornar sucesso": {
+			ExpectedResult: &voided,
+			ExpectedErr:    nil,
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.Transaction.EXPECT().ReadOneByIdForUpdate(gomock.Any(), authorization.ID).Times(1).Return(readAuthorization(entity.AUTHORIZED), nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), sourceUserId).Times(1).Return(sourceAccount, nil),
+					db.Account.EXPECT().UpdateHeldBalance(gomock.Any(), sourceUserId, money.New(2000)).Times(1).Return(nil),
+					db.Transaction.EXPECT().UpdateState(gomock.Any(), entity.CANCELLED, authorization.ID).Times(1).Return(nil),
+					db.StateHistory.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Outbox.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+				)
+			},
+		},
+		"deve retornar erro: transaction nao autorizada": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.NewHTTPError(echo.ErrConflict.Code, "Transaction is not authorized"),
+			PrepareMock: func(db *databaseMocks) {
+				db.Transaction.EXPECT().ReadOneByIdForUpdate(gomock.Any(), authorization.ID).Times(1).Return(readAuthorization(entity.BOOKED), nil)
+			},
+		},
+		"deve retornar erro: ao atualizar saldo retido": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrNotFound,
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.Transaction.EXPECT().ReadOneByIdForUpdate(gomock.Any(), authorization.ID).Times(1).Return(readAuthorization(entity.AUTHORIZED), nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), sourceUserId).Times(1).Return(sourceAccount, nil),
+					db.Account.EXPECT().UpdateHeldBalance(gomock.Any(), sourceUserId, money.New(2000)).Times(1).Return(echo.ErrNotFound),
+				)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			container, db := newDatabaseContainer(ctrl)
+			cs.PrepareMock(db)
+
+			app := NewAppTransaction(container)
+
+			transaction, err := app.Void(ctx, authorization.ID)
+			if diff := cmp.Diff(transaction, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestExpireAuthorizations(t *testing.T) {
+	sourceUserId := "source-user-id"
+	expiredAt := time.Now().Add(-time.Hour)
+
+	authorization := entity.Transaction{
+		ID:            "transaction-id",
+		SourceId:      sourceUserId,
+		DestinationId: "destination-user-id",
+		Amount:        money.New(10000),
+		Kind:          entity.TRANSFER,
+		State:         entity.AUTHORIZED,
+		ExpiresAt:     &expiredAt,
+	}
+
+	readAuthorization := func(state entity.StatesTransaction) *entity.Transaction {
+		transaction := authorization
+		transaction.State = state
+		return &transaction
+	}
+
+	sourceAccount := &entity.Account{
+		ID:               sourceUserId,
+		Balance:          money.New(20000),
+		HeldBalance:      money.New(10000),
+		AvailableBalance: money.New(10000),
+	}
+
+	cases := map[string]struct {
+		ExpectedErr error
+		PrepareMock func(db *databaseMocks)
+	}{
+		"deve retornar sucesso": {
+			ExpectedErr: nil,
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.Transaction.EXPECT().ReadExpiredAuthorizations(gomock.Any(), gomock.Any()).Times(1).Return([]entity.Transaction{authorization}, nil),
+					db.Transaction.EXPECT().ReadOneByIdForUpdate(gomock.Any(), authorization.ID).Times(1).Return(readAuthorization(entity.AUTHORIZED), nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), sourceUserId).Times(1).Return(sourceAccount, nil),
+					db.Account.EXPECT().UpdateHeldBalance(gomock.Any(), sourceUserId, money.New(0)).Times(1).Return(nil),
+					db.Transaction.EXPECT().UpdateState(gomock.Any(), entity.CANCELLED, authorization.ID).Times(1).Return(nil),
+					db.StateHistory.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Outbox.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+				)
+			},
+		},
+		"deve ignorar autorizacao ja capturada": {
+			ExpectedErr: nil,
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.Transaction.EXPECT().ReadExpiredAuthorizations(gomock.Any(), gomock.Any()).Times(1).Return([]entity.Transaction{authorization}, nil),
+					db.Transaction.EXPECT().ReadOneByIdForUpdate(gomock.Any(), authorization.ID).Times(1).Return(readAuthorization(entity.BOOKED), nil),
+				)
+			},
+		},
+		"deve retornar erro": {
+			ExpectedErr: echo.ErrInternalServerError,
+			PrepareMock: func(db *databaseMocks) {
+				db.Transaction.EXPECT().ReadExpiredAuthorizations(gomock.Any(), gomock.Any()).Times(1).Return(nil, echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			container, db := newDatabaseContainer(ctrl)
+			cs.PrepareMock(db)
+
+			app := NewAppTransaction(container)
+
+			err := app.ExpireAuthorizations(ctx)
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestCreateScheduled(t *testing.T) {
+	sourceUserId := "source-user-id"
+	destinationUserId := "destination-user-id"
+	executeAt := time.Now().Add(time.Hour)
+	executedAt := time.Now().Add(-time.Hour)
+
+	transaction := entity.Transaction{
+		ID:            "transaction-id",
+		SourceId:      sourceUserId,
+		DestinationId: destinationUserId,
+		Amount:        money.New(10010),
+		ExecuteAt:     &executeAt,
+	}
+
+	pastTransaction := transaction
+	pastTransaction.ExecuteAt = &executedAt
+
+	scheduledTransaction := transaction
+	scheduledTransaction.State = entity.SCHEDULED
+	scheduledTransaction.StateString = entity.SCHEDULED.String()
+	scheduledTransaction.KindString = entity.TRANSFER.String()
+
+	cases := map[string]struct {
+		InputTransaction entity.Transaction
+		ExpectedResult   *entity.Transaction
+		ExpectedErr      error
+		PrepareMock      func(db *databaseMocks)
+	}{
+		"deve retornar sucesso": {
+			InputTransaction: transaction,
+			ExpectedResult:   &scheduledTransaction,
+			ExpectedErr:      nil,
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.Account.EXPECT().ReadOneById(gomock.Any(), sourceUserId).Times(1).Return(&entity.Account{ID: sourceUserId}, nil),
+					db.Account.EXPECT().ReadOneById(gomock.Any(), destinationUserId).Times(1).Return(&entity.Account{ID: destinationUserId}, nil),
+					db.Transaction.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Transaction.EXPECT().UpdateState(gomock.Any(), entity.SCHEDULED, transaction.ID).Times(1).Return(nil),
+					db.StateHistory.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Outbox.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+				)
+			},
+		},
+		"deve retornar erro: data de execucao no passado": {
+			InputTransaction: pastTransaction,
+			ExpectedResult:   nil,
+			ExpectedErr:      echo.NewHTTPError(echo.ErrBadRequest.Code, "The execution date must be in the future"),
+			PrepareMock: func(db *databaseMocks) {
+			},
+		},
+		"deve retornar erro: ao ler destination account": {
+			InputTransaction: transaction,
+			ExpectedResult:   nil,
+			ExpectedErr:      echo.ErrNotFound,
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.Account.EXPECT().ReadOneById(gomock.Any(), sourceUserId).Times(1).Return(&entity.Account{ID: sourceUserId}, nil),
+					db.Account.EXPECT().ReadOneById(gomock.Any(), destinationUserId).Times(1).Return(nil, echo.ErrNotFound),
+				)
+			},
+		},
+		"deve retornar erro: ao registrar transaction": {
+			InputTransaction: transaction,
+			ExpectedResult:   nil,
+			ExpectedErr:      echo.ErrInternalServerError,
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.Account.EXPECT().ReadOneById(gomock.Any(), sourceUserId).Times(1).Return(&entity.Account{ID: sourceUserId}, nil),
+					db.Account.EXPECT().ReadOneById(gomock.Any(), destinationUserId).Times(1).Return(&entity.Account{ID: destinationUserId}, nil),
+					db.Transaction.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(echo.ErrInternalServerError),
+				)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			container, db := newDatabaseContainer(ctrl)
+			cs.PrepareMock(db)
+
+			app := NewAppTransaction(container)
+
+			input := cs.InputTransaction
+			transaction, err := app.Create(ctx, &input)
+			if diff := cmp.Diff(transaction, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestReadScheduled(t *testing.T) {
+	executeAt := time.Now().Add(time.Hour)
+
+	transactions := []entity.Transaction{{
+		ID:        "transaction-id",
+		Amount:    money.New(10010),
+		State:     entity.SCHEDULED,
+		ExecuteAt: &executeAt,
+	}}
+
+	expected := []entity.Transaction{transactions[0]}
+	expected[0].StateString = entity.SCHEDULED.String()
+	expected[0].KindString = entity.TRANSFER.String()
+
+	cases := map[string]struct {
+		ExpectedResult []entity.Transaction
+		ExpectedErr    error
+		PrepareMock    func(db *databaseMocks)
+	}{
+		"deve retornar sucesso": {
+			ExpectedResult: expected,
+			ExpectedErr:    nil,
+			PrepareMock: func(db *databaseMocks) {
+				db.Transaction.EXPECT().ReadAllByState(gomock.Any(), entity.SCHEDULED).Times(1).
+					Return(append([]entity.Transaction(nil), transactions...), nil)
+			},
+		},
+		"deve retornar erro": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrInternalServerError,
+			PrepareMock: func(db *databaseMocks) {
+				db.Transaction.EXPECT().ReadAllByState(gomock.Any(), entity.SCHEDULED).Times(1).Return(nil, echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			container, db := newDatabaseContainer(ctrl)
+			cs.PrepareMock(db)
+
+			app := NewAppTransaction(container)
+
+			transactions, err := app.ReadScheduled(ctx)
+			if diff := cmp.Diff(transactions, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestCancelScheduled(t *testing.T) {
+	executeAt := time.Now().Add(time.Hour)
+
+	scheduled := entity.Transaction{
+		ID:            "transaction-id",
+		SourceId:      "source-user-id",
+		DestinationId: "destination-user-id",
+		Amount:        money.New(10000),
+		Kind:          entity.TRANSFER,
+		State:         entity.SCHEDULED,
+		ExecuteAt:     &executeAt,
+	}
+
+	readScheduled := func(state entity.StatesTransaction) *entity.Transaction {
+		transaction := scheduled
+		transaction.State = state
+		return &transaction
+	}
+
+	cancelled := scheduled
+	cancelled.State = entity.CANCELLED
+	cancelled.StateString = entity.CANCELLED.String()
+	cancelled.KindString = entity.TRANSFER.String()
+
+	cases := map[string]struct {
+		ExpectedResult *entity.Transaction
+		ExpectedErr    error
+		PrepareMock    func(db *databaseMocks)
+	}{
+		"deve retornar sucesso": {
+			ExpectedResult: &cancelled,
+			ExpectedErr:    nil,
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.Transaction.EXPECT().ReadOneByIdForUpdate(gomock.Any(), scheduled.ID).Times(1).Return(readScheduled(entity.SCHEDULED), nil),
+					db.Transaction.EXPECT().UpdateState(gomock.Any(), entity.CANCELLED, scheduled.ID).Times(1).Return(nil),
+					db.StateHistory.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Outbox.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+				)
+			},
+		},
+		"deve retornar erro: transaction nao agendada": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.NewHTTPError(echo.ErrConflict.Code, "Transaction is not scheduled"),
+			PrepareMock: func(db *databaseMocks) {
+				db.Transaction.EXPECT().ReadOneByIdForUpdate(gomock.Any(), scheduled.ID).Times(1).Return(readScheduled(entity.BOOKED), nil)
+			},
+		},
+		"deve retornar erro: transaction nao encontrada": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrNotFound,
+			PrepareMock: func(db *databaseMocks) {
+				db.Transaction.EXPECT().ReadOneByIdForUpdate(gomock.Any(), scheduled.ID).Times(1).Return(nil, echo.ErrNotFound)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			container, db := newDatabaseContainer(ctrl)
+			cs.PrepareMock(db)
+
+			app := NewAppTransaction(container)
+
+			transaction, err := app.CancelScheduled(ctx, scheduled.ID)
+			if diff := cmp.Diff(transaction, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestExecuteScheduled(t *testing.T) {
+	sourceUserId := "source-user-id"
+	destinationUserId := "destination-user-id"
+	executeAt := time.Now().Add(-time.Minute)
+
+	scheduled := entity.Transaction{
+		ID:            "transaction-id",
+		SourceId:      sourceUserId,
+		DestinationId: destinationUserId,
+		Amount:        money.New(10000),
+		Kind:          entity.TRANSFER,
+		State:         entity.SCHEDULED,
+		ExecuteAt:     &executeAt,
+	}
+
+	readScheduled := func(state entity.StatesTransaction) *entity.Transaction {
+		transaction := scheduled
+		transaction.State = state
+		return &transaction
+	}
+
+	readSourceUser := func(balance int64) *entity.Account {
+		return &entity.Account{
+			ID:               sourceUserId,
+			Balance:          money.New(balance),
+			AvailableBalance: money.New(balance),
+		}
+	}
+
+	destinationAccount := &entity.Account{
+		ID:               destinationUserId,
+		Balance:          money.New(0),
+		AvailableBalance: money.New(0),
+	}
+
+	cases := map[string]struct {
+		ExpectedErr error
+		PrepareMock func(db *databaseMocks)
+	}{
+		"deve retornar sucesso": {
+			ExpectedErr: nil,
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.Transaction.EXPECT().ReadDueScheduled(gomock.Any(), gomock.Any()).Times(1).Return([]entity.Transaction{scheduled}, nil),
+					db.Transaction.EXPECT().ReadOneByIdForUpdate(gomock.Any(), scheduled.ID).Times(1).Return(readScheduled(entity.SCHEDULED), nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), destinationUserId).Times(1).Return(destinationAccount, nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), sourceUserId).Times(1).Return(readSourceUser(20000), nil),
+					db.Account.EXPECT().UpdateBalance(gomock.Any(), sourceUserId, money.New(10000)).Times(1).Return(nil),
+					db.Account.EXPECT().UpdateBalance(gomock.Any(), destinationUserId, money.New(10000)).Times(1).Return(nil),
+					db.Ledger.EXPECT().CreatePostings(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Transaction.EXPECT().UpdateState(gomock.Any(), entity.BOOKED, scheduled.ID).Times(1).Return(nil),
+					db.StateHistory.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Outbox.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Account.EXPECT().ReadOneById(gomock.Any(), gomock.Any()).Times(1).Return(&entity.Account{UserId: "user-id"}, nil),
+					db.Limit.EXPECT().ReadWithDefault(gomock.Any(), gomock.Any()).Times(1).Return([]entity.Limits{}, nil),
+					db.Fee.EXPECT().ReadActive(gomock.Any()).Times(1).Return([]entity.FeeRule{}, nil),
+				)
+			},
+		},
+		"deve ignorar transaction ja executada": {
+			ExpectedErr: nil,
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.Transaction.EXPECT().ReadDueScheduled(gomock.Any(), gomock.Any()).Times(1).Return([]entity.Transaction{scheduled}, nil),
+					db.Transaction.EXPECT().ReadOneByIdForUpdate(gomock.Any(), scheduled.ID).Times(1).Return(readScheduled(entity.BOOKED), nil),
+				)
+			},
+		},
+		"deve marcar como falha: 'Insufficient balance'": {
+			ExpectedErr: nil,
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.Transaction.EXPECT().ReadDueScheduled(gomock.Any(), gomock.Any()).Times(1).Return([]entity.Transaction{scheduled}, nil),
+					db.Transaction.EXPECT().ReadOneByIdForUpdate(gomock.Any(), scheduled.ID).Times(1).Return(readScheduled(entity.SCHEDULED), nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), destinationUserId).Times(1).Return(destinationAccount, nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), sourceUserId).Times(1).Return(readSourceUser(5000), nil),
+					db.Transaction.EXPECT().ReadOneByIdForUpdate(gomock.Any(), scheduled.ID).Times(1).Return(readScheduled(entity.SCHEDULED), nil),
+					db.Transaction.EXPECT().UpdateState(gomock.Any(), entity.FAILED, scheduled.ID).Times(1).Return(nil),
+					db.StateHistory.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Outbox.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+				)
+			},
+		},
+		"deve retornar erro": {
+			ExpectedErr: echo.ErrInternalServerError,
+			PrepareMock: func(db *databaseMocks) {
+				db.Transaction.EXPECT().ReadDueScheduled(gomock.Any(), gomock.Any()).Times(1).Return(nil, echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			container, db := newDatabaseContainer(ctrl)
+			cs.PrepareMock(db)
+
+			app := NewAppTransaction(container)
+
+			err := app.ExecuteScheduled(ctx)
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestCreateBatch(t *testing.T) {
+	sourceUserId := "source-user-id"
+	firstUserId := "destination-user-id-1"
+	secondUserId := "destination-user-id-2"
+
+	newBatch := func(atomic bool) *entity.Batch {
+		return &entity.Batch{
+			ID:     "batch-id",
+			Atomic: atomic,
+			State:  entity.BATCH_PROCESSING,
+			Transactions: []*entity.Transaction{
+				{ID: "transaction-id-1", SourceId: sourceUserId, DestinationId: firstUserId, Amount: money.New(10000)},
+				{ID: "transaction-id-2", SourceId: sourceUserId, DestinationId: secondUserId, Amount: money.New(10000)},
+			},
+		}
+	}
+
+	readUser := func(id string, balance int64) *entity.Account {
+		return &entity.Account{ID: id, Balance: money.New(balance), AvailableBalance: money.New(balance)}
+	}
+
+	expectItems := func(t *testing.T, expected ...entity.BatchItem) func(ctx context.Context, items []entity.BatchItem) error {
+		return func(ctx context.Context, items []entity.BatchItem) error {
+			if diff := cmp.Diff(items, expected); diff != "" {
+				t.Error(diff)
+			}
+			return nil
+		}
+	}
+
+	cases := map[string]struct {
+		InputBatch  *entity.Batch
+		ExpectedErr error
+		PrepareMock func(t *testing.T, db *databaseMocks)
+	}{
+		"deve retornar sucesso: atomico": {
+			InputBatch:  newBatch(true),
+			ExpectedErr: nil,
+			PrepareMock: func(t *testing.T, db *databaseMocks) {
+				gomock.InOrder(
+					db.Batch.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), firstUserId).Times(1).Return(readUser(firstUserId, 0), nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), secondUserId).Times(1).Return(readUser(secondUserId, 0), nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), sourceUserId).Times(1).Return(readUser(sourceUserId, 20000), nil),
+					db.Transaction.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), firstUserId).Times(1).Return(readUser(firstUserId, 0), nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), sourceUserId).Times(1).Return(readUser(sourceUserId, 20000), nil),
+					db.Account.EXPECT().UpdateBalance(gomock.Any(), sourceUserId, money.New(10000)).Times(1).Return(nil),
+					db.Account.EXPECT().UpdateBalance(gomock.Any(), firstUserId, money.New(10000)).Times(1).Return(nil),
+					db.Ledger.EXPECT().CreatePostings(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Transaction.EXPECT().UpdateState(gomock.Any(), entity.BOOKED, "transaction-id-1").Times(1).Return(nil),
+					db.StateHistory.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Outbox.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Account.EXPECT().ReadOneById(gomock.Any(), gomock.Any()).Times(1).Return(&entity.Account{UserId: "user-id"}, nil),
+					db.Limit.EXPECT().ReadWithDefault(gomock.Any(), gomock.Any()).Times(1).Return([]entity.Limits{}, nil),
+					db.Fee.EXPECT().ReadActive(gomock.Any()).Times(1).Return([]entity.FeeRule{}, nil),
+					db.Transaction.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), secondUserId).Times(1).Return(readUser(secondUserId, 0), nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), sourceUserId).Times(1).Return(readUser(sourceUserId, 10000), nil),
+					db.Account.EXPECT().UpdateBalance(gomock.Any(), sourceUserId, money.New(0)).Times(1).Return(nil),
+					db.Account.EXPECT().UpdateBalance(gomock.Any(), secondUserId, money.New(10000)).Times(1).Return(nil),
+					db.Ledger.EXPECT().CreatePostings(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Transaction.EXPECT().UpdateState(gomock.Any(), entity.BOOKED, "transaction-id-2").Times(1).Return(nil),
+					db.StateHistory.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Outbox.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Account.EXPECT().ReadOneById(gomock.Any(), gomock.Any()).Times(1).Return(&entity.Account{UserId: "user-id"}, nil),
+					db.Limit.EXPECT().ReadWithDefault(gomock.Any(), gomock.Any()).Times(1).Return([]entity.Limits{}, nil),
+					db.Fee.EXPECT().ReadActive(gomock.Any()).Times(1).Return([]entity.FeeRule{}, nil),
+					db.Batch.EXPECT().CreateItems(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(expectItems(t,
+						entity.BatchItem{BatchId: "batch-id", Position: 0, TransactionId: "transaction-id-1", State: entity.BOOKED, StateString: "BOOKED"},
+						entity.BatchItem{BatchId: "batch-id", Position: 1, TransactionId: "transaction-id-2", State: entity.BOOKED, StateString: "BOOKED"},
+					)),
+					db.Batch.EXPECT().UpdateState(gomock.Any(), entity.BATCH_BOOKED, "batch-id").Times(1).Return(nil),
+				)
+			},
+		},
+		"deve desfazer tudo: atomico com saldo insuficiente": {
+			InputBatch:  newBatch(true),
+			ExpectedErr: nil,
+			PrepareMock: func(t *testing.T, db *databaseMocks) {
+				gomock.InOrder(
+					db.Batch.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), firstUserId).Times(1).Return(readUser(firstUserId, 0), nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), secondUserId).Times(1).Return(readUser(secondUserId, 0), nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), sourceUserId).Times(1).Return(readUser(sourceUserId, 15000), nil),
+					db.Transaction.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), firstUserId).Times(1).Return(readUser(firstUserId, 0), nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), sourceUserId).Times(1).Return(readUser(sourceUserId, 15000), nil),
+					db.Account.EXPECT().UpdateBalance(gomock.Any(), sourceUserId, money.New(5000)).Times(1).Return(nil),
+					db.Account.EXPECT().UpdateBalance(gomock.Any(), firstUserId, money.New(10000)).Times(1).Return(nil),
+					db.Ledger.EXPECT().CreatePostings(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Transaction.EXPECT().UpdateState(gomock.Any(), entity.BOOKED, "transaction-id-1").Times(1).Return(nil),
+					db.StateHistory.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Outbox.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Account.EXPECT().ReadOneById(gomock.Any(), gomock.Any()).Times(1).Return(&entity.Account{UserId: "user-id"}, nil),
+					db.Limit.EXPECT().ReadWithDefault(gomock.Any(), gomock.Any()).Times(1).Return([]entity.Limits{}, nil),
+					db.Fee.EXPECT().ReadActive(gomock.Any()).Times(1).Return([]entity.FeeRule{}, nil),
+					db.Transaction.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), secondUserId).Times(1).Return(readUser(secondUserId, 0), nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), sourceUserId).Times(1).Return(readUser(sourceUserId, 5000), nil),
+					db.Transaction.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.StateHistory.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Outbox.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Transaction.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.StateHistory.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Outbox.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Batch.EXPECT().CreateItems(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(expectItems(t,
+						entity.BatchItem{BatchId: "batch-id", Position: 0, TransactionId: "transaction-id-1", State: entity.FAILED, StateString: "FAILED", Error: "batch batch-id was rolled back"},
+						entity.BatchItem{BatchId: "batch-id", Position: 1, TransactionId: "transaction-id-2", State: entity.FAILED, StateString: "FAILED", Error: ErrInsufficientBalance.Error()},
+					)),
+					db.Batch.EXPECT().UpdateState(gomock.Any(), entity.BATCH_FAILED, "batch-id").Times(1).Return(nil),
+				)
+			},
+		},
+		"deve retornar sucesso parcial: sem atomicidade": {
+			InputBatch:  newBatch(false),
+			ExpectedErr: nil,
+			PrepareMock: func(t *testing.T, db *databaseMocks) {
+				gomock.InOrder(
+					db.Batch.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Transaction.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), firstUserId).Times(1).Return(readUser(firstUserId, 0), nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), sourceUserId).Times(1).Return(readUser(sourceUserId, 15000), nil),
+					db.Account.EXPECT().UpdateBalance(gomock.Any(), sourceUserId, money.New(5000)).Times(1).Return(nil),
+					db.Account.EXPECT().UpdateBalance(gomock.Any(), firstUserId, money.New(10000)).Times(1).Return(nil),
+					db.Ledger.EXPECT().CreatePostings(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Transaction.EXPECT().UpdateState(gomock.Any(), entity.BOOKED, "transaction-id-1").Times(1).Return(nil),
+					db.StateHistory.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Outbox.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Account.EXPECT().ReadOneById(gomock.Any(), gomock.Any()).Times(1).Return(&entity.Account{UserId: "user-id"}, nil),
+					db.Limit.EXPECT().ReadWithDefault(gomock.Any(), gomock.Any()).Times(1).Return([]entity.Limits{}, nil),
+					db.Fee.EXPECT().ReadActive(gomock.Any()).Times(1).Return([]entity.FeeRule{}, nil),
+					db.Transaction.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), secondUserId).Times(1).Return(readUser(secondUserId, 0), nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), sourceUserId).Times(1).Return(readUser(sourceUserId, 5000), nil),
+					db.Transaction.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.StateHistory.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Outbox.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Batch.EXPECT().CreateItems(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(expectItems(t,
+						entity.BatchItem{BatchId: "batch-id", Position: 0, TransactionId: "transaction-id-1", State: entity.BOOKED, StateString: "BOOKED"},
+						entity.BatchItem{BatchId: "batch-id", Position: 1, TransactionId: "transaction-id-2", State: entity.FAILED, StateString: "FAILED", Error: ErrInsufficientBalance.Error()},
+					)),
+					db.Batch.EXPECT().UpdateState(gomock.Any(), entity.BATCH_PARTIALLY_BOOKED, "batch-id").Times(1).Return(nil),
+				)
+			},
+		},
+		"deve retornar erro: source e destination iguais": {
+			InputBatch: func() *entity.Batch {
+				batch := newBatch(false)
+				batch.Transactions[1].DestinationId = sourceUserId
+				return batch
+			}(),
+			ExpectedErr: echo.NewHTTPError(echo.ErrBadRequest.Code, "Transaction 1: source and destination accounts must be different"),
+			PrepareMock: func(t *testing.T, db *databaseMocks) {},
+		},
+		"deve retornar erro: conta de sistema": {
+			InputBatch: func() *entity.Batch {
+				batch := newBatch(false)
+				batch.Transactions[1].SourceId = entity.FundingAccountId
+				return batch
+			}(),
+			ExpectedErr: echo.NewHTTPError(echo.ErrBadRequest.Code, "Transaction 1: system accounts can't be the source or destination of a transfer"),
+			PrepareMock: func(t *testing.T, db *databaseMocks) {},
+		},
+		"deve retornar erro: transferencia agendada": {
+			InputBatch: func() *entity.Batch {
+				batch := newBatch(false)
+				executeAt := time.Now().Add(time.Hour)
+				batch.Transactions[0].ExecuteAt = &executeAt
+				return batch
+			}(),
+			ExpectedErr: echo.NewHTTPError(echo.ErrBadRequest.Code, "Transaction 0: batch transfers can't be scheduled"),
+			PrepareMock: func(t *testing.T, db *databaseMocks) {},
+		},
+		"deve retornar erro: ao registrar batch": {
+			InputBatch:  newBatch(false),
+			ExpectedErr: echo.ErrInternalServerError,
+			PrepareMock: func(t *testing.T, db *databaseMocks) {
+				db.Batch.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			container, db := newDatabaseContainer(ctrl)
+			cs.PrepareMock(t, db)
+
+			app := NewAppTransaction(container)
+
+			batch, err := app.CreateBatch(ctx, cs.InputBatch)
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+
+			if err == nil && batch != cs.InputBatch {
+				t.Error("expected the processed batch to be returned")
+			}
+		})
+	}
+}
+
+func TestReadBatch(t *testing.T) {
+	items := []entity.BatchItem{{BatchId: "batch-id", Position: 0, TransactionId: "transaction-id", State: entity.BOOKED}}
+
+	cases := map[string]struct {
+		ExpectedResult *entity.Batch
+		ExpectedErr    error
+		PrepareMock    func(db *databaseMocks)
+	}{
+		"deve retornar sucesso": {
+			ExpectedResult: &entity.Batch{
+				ID:          "batch-id",
+				State:       entity.BATCH_BOOKED,
+				StateString: "BOOKED",
+				Items:       []entity.BatchItem{{BatchId: "batch-id", Position: 0, TransactionId: "transaction-id", State: entity.BOOKED, StateString: "BOOKED"}},
+			},
+			ExpectedErr: nil,
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.Batch.EXPECT().ReadOneById(gomock.Any(), "batch-id").Times(1).Return(&entity.Batch{ID: "batch-id", State: entity.BATCH_BOOKED}, nil),
+					db.Batch.EXPECT().ReadItems(gomock.Any(), "batch-id").Times(1).Return(append([]entity.BatchItem(nil), items...), nil),
+				)
+			},
+		},
+		"deve retornar erro: batch nao encontrado": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrNotFound,
+			PrepareMock: func(db *databaseMocks) {
+				db.Batch.EXPECT().ReadOneById(gomock.Any(), "batch-id").Times(1).Return(nil, echo.ErrNotFound)
+			},
+		},
+		"deve retornar erro: ao ler itens": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrInternalServerError,
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.Batch.EXPECT().ReadOneById(gomock.Any(), "batch-id").Times(1).Return(&entity.Batch{ID: "batch-id"}, nil),
+					db.Batch.EXPECT().ReadItems(gomock.Any(), "batch-id").Times(1).Return(nil, echo.ErrInternalServerError),
+				)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			container, db := newDatabaseContainer(ctrl)
+			cs.PrepareMock(db)
+
+			app := NewAppTransaction(container)
+
+			batch, err := app.ReadBatch(ctx, "batch-id")
+			if diff := cmp.Diff(batch, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestCreateSplit(t *testing.T) {
+	sellerId := "seller-id"
+	platformId := "platform-id"
+	sourceUserId := "source-user-id"
+
+	newSplit := func(destinationIds ...string) *entity.Split {
+		split := &entity.Split{ID: "split-id", SourceId: sourceUserId, Amount: money.New(10001)}
+		for i, destinationId := range destinationIds {
+			basisPoints := []int64{9000, 1000}[i]
+			split.Rules = append(split.Rules, entity.SplitRule{DestinationId: destinationId, BasisPoints: &basisPoints})
+		}
+		return split
+	}
+
+	readUser := func(id string, balance int64) *entity.Account {
+		return &entity.Account{ID: id, Balance: money.New(balance), AvailableBalance: money.New(balance)}
+	}
+
+	cases := map[string]struct {
+		InputSplit      *entity.Split
+		ExpectedAmounts []int64
+		ExpectedErr     error
+		PrepareMock     func(db *databaseMocks)
+	}{
+		"deve retornar sucesso": {
+			InputSplit:      newSplit(sellerId, platformId),
+			ExpectedAmounts: []int64{9001, 1000},
+			ExpectedErr:     nil,
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), platformId).Times(1).Return(readUser(platformId, 0), nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), sellerId).Times(1).Return(readUser(sellerId, 0), nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), sourceUserId).Times(1).Return(readUser(sourceUserId, 10001), nil),
+					db.Transaction.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), sellerId).Times(1).Return(readUser(sellerId, 0), nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), sourceUserId).Times(1).Return(readUser(sourceUserId, 10001), nil),
+					db.Account.EXPECT().UpdateBalance(gomock.Any(), sourceUserId, money.New(1000)).Times(1).Return(nil),
+					db.Account.EXPECT().UpdateBalance(gomock.Any(), sellerId, money.New(9001)).Times(1).Return(nil),
+					db.Ledger.EXPECT().CreatePostings(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Transaction.EXPECT().UpdateState(gomock.Any(), entity.BOOKED, gomock.Any()).Times(1).Return(nil),
+					db.StateHistory.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Outbox.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Account.EXPECT().ReadOneById(gomock.Any(), gomock.Any()).Times(1).Return(&entity.Account{UserId: "user-id"}, nil),
+					db.Limit.EXPECT().ReadWithDefault(gomock.Any(), gomock.Any()).Times(1).Return([]entity.Limits{}, nil),
+					db.Fee.EXPECT().ReadActive(gomock.Any()).Times(1).Return([]entity.FeeRule{}, nil),
+					db.Transaction.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), platformId).Times(1).Return(readUser(platformId, 0), nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), sourceUserId).Times(1).Return(readUser(sourceUserId, 1000), nil),
+					db.Account.EXPECT().UpdateBalance(gomock.Any(), sourceUserId, money.New(0)).Times(1).Return(nil),
+					db.Account.EXPECT().UpdateBalance(gomock.Any(), platformId, money.New(1000)).Times(1).Return(nil),
+					db.Ledger.EXPECT().CreatePostings(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Transaction.EXPECT().UpdateState(gomock.Any(), entity.BOOKED, gomock.Any()).Times(1).Return(nil),
+					db.StateHistory.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Outbox.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Account.EXPECT().ReadOneById(gomock.Any(), gomock.Any()).Times(1).Return(&entity.Account{UserId: "user-id"}, nil),
+					db.Limit.EXPECT().ReadWithDefault(gomock.Any(), gomock.Any()).Times(1).Return([]entity.Limits{}, nil),
+					db.Fee.EXPECT().ReadActive(gomock.Any()).Times(1).Return([]entity.FeeRule{}, nil),
+				)
+			},
+		},
+		"deve retornar erro: saldo insuficiente": {
+			InputSplit:  newSplit(sellerId, platformId),
+			ExpectedErr: ErrInsufficientBalance,
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), platformId).Times(1).Return(readUser(platformId, 0), nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), sellerId).Times(1).Return(readUser(sellerId, 0), nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), sourceUserId).Times(1).Return(readUser(sourceUserId, 100), nil),
+					db.Transaction.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), sellerId).Times(1).Return(readUser(sellerId, 0), nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), sourceUserId).Times(1).Return(readUser(sourceUserId, 100), nil),
+					db.Transaction.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.StateHistory.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Outbox.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Transaction.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.StateHistory.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Outbox.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+				)
+			},
+		},
+		"deve retornar erro: regras invalidas": {
+			InputSplit: func() *entity.Split {
+				split := newSplit(sellerId, platformId)
+				split.Rules = split.Rules[:1]
+				return split
+			}(),
+			ExpectedErr: echo.NewHTTPError(echo.ErrBadRequest.Code, entity.ErrSplitPercentages.Error()),
+			PrepareMock: func(db *databaseMocks) {},
+		},
+		"deve retornar erro: source e destination iguais": {
+			InputSplit:  newSplit(sellerId, sourceUserId),
+			ExpectedErr: echo.NewHTTPError(echo.ErrBadRequest.Code, "Rule 1: source and destination accounts must be different"),
+			PrepareMock: func(db *databaseMocks) {},
+		},
+		"deve retornar erro: conta de sistema": {
+			InputSplit:  newSplit(sellerId, entity.FeeAccountId),
+			ExpectedErr: echo.NewHTTPError(echo.ErrBadRequest.Code, "Rule 1: system accounts can't be the source or destination of a transfer"),
+			PrepareMock: func(db *databaseMocks) {},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			container, db := newDatabaseContainer(ctrl)
+			cs.PrepareMock(db)
+
+			app := NewAppTransaction(container)
+
+			split, err := app.CreateSplit(ctx, cs.InputSplit)
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+
+			if err == nil {
+				amounts := make([]int64, 0, len(split.Transactions))
+				for _, transaction := range split.Transactions {
+					amounts = append(amounts, transaction.Amount.Amount)
+					if transaction.StateString != "BOOKED" || *transaction.ParentId != "split-id" {
+						t.Errorf("unexpected transaction %+v", transaction)
+					}
+				}
+
+				if diff := cmp.Diff(amounts, cs.ExpectedAmounts); diff != "" {
+					t.Error(diff)
+				}
+			}
+		})
+	}
+}
+
+func TestReadSplit(t *testing.T) {
+	parentId := "split-id"
+
+	cases := map[string]struct {
+		ExpectedResult *entity.Split
+		ExpectedErr    error
+		PrepareMock    func(db *databaseMocks)
+	}{
+		"deve retornar sucesso": {
+			ExpectedResult: &entity.Split{
+				ID:       parentId,
+				SourceId: "source-user-id",
+				Amount:   money.New(10000),
+				Transactions: []*entity.Transaction{
+					{ID: "transaction-id-1", SourceId: "source-user-id", DestinationId: "seller-id", Amount: money.New(9000), ParentId: &parentId, State: entity.BOOKED, StateString: "BOOKED", KindString: "TRANSFER"},
+					{ID: "transaction-id-2", SourceId: "source-user-id", DestinationId: "platform-id", Amount: money.New(1000), ParentId: &parentId, State: entity.BOOKED, StateString: "BOOKED", KindString: "TRANSFER"},
+				},
+			},
+			ExpectedErr: nil,
+			PrepareMock: func(db *databaseMocks) {
+				db.Transaction.EXPECT().ReadAllByParent(gomock.Any(), parentId).Times(1).Return([]entity.Transaction{
+					{ID: "transaction-id-1", SourceId: "source-user-id", DestinationId: "seller-id", Amount: money.New(9000), ParentId: &parentId, State: entity.BOOKED},
+					{ID: "transaction-id-2", SourceId: "source-user-id", DestinationId: "platform-id", Amount: money.New(1000), ParentId: &parentId, State: entity.BOOKED},
+				}, nil)
+			},
+		},
+		"deve retornar erro: split nao encontrado": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrNotFound,
+			PrepareMock: func(db *databaseMocks) {
+				db.Transaction.EXPECT().ReadAllByParent(gomock.Any(), parentId).Times(1).Return([]entity.Transaction{}, nil)
+			},
+		},
+		"deve retornar erro": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrInternalServerError,
+			PrepareMock: func(db *databaseMocks) {
+				db.Transaction.EXPECT().ReadAllByParent(gomock.Any(), parentId).Times(1).Return(nil, echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			container, db := newDatabaseContainer(ctrl)
+			cs.PrepareMock(db)
+
+			app := NewAppTransaction(container)
+
+			split, err := app.ReadSplit(ctx, parentId)
+			if diff := cmp.Diff(split, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestCreateWithFee(t *testing.T) {
+	sourceUserId := "source-user-id"
+	destinationUserId := "destination-user-id"
+
+	transfer := entity.TRANSFER
+	deposit := entity.DEPOSIT
+	basisPoints := int64(100)
+	transferRule := entity.FeeRule{ID: "rule-id", Name: "transfers", Type: entity.FEE_PERCENTAGE, Kind: &transfer, BasisPoints: &basisPoints, Active: true}
+	depositRule := entity.FeeRule{ID: "rule-id", Name: "deposits", Type: entity.FEE_PERCENTAGE, Kind: &deposit, BasisPoints: &basisPoints, Active: true}
+
+	readUser := func(id string, balance int64) *entity.Account {
+		return &entity.Account{ID: id, Balance: money.New(balance), AvailableBalance: money.New(balance)}
+	}
+
+	bookTransfer := func(db *databaseMocks, sourceBalance int64) []*gomock.Call {
+		return []*gomock.Call{
+			db.Transaction.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+			db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), destinationUserId).Times(1).Return(readUser(destinationUserId, 0), nil),
+			db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), sourceUserId).Times(1).Return(readUser(sourceUserId, sourceBalance), nil),
+			db.Account.EXPECT().UpdateBalance(gomock.Any(), sourceUserId, money.New(sourceBalance-10010)).Times(1).Return(nil),
+			db.Account.EXPECT().UpdateBalance(gomock.Any(), destinationUserId, money.New(10010)).Times(1).Return(nil),
+			db.Ledger.EXPECT().CreatePostings(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+			db.Transaction.EXPECT().UpdateState(gomock.Any(), entity.BOOKED, "transaction-id").Times(1).Return(nil),
+			db.StateHistory.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+			db.Outbox.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+			db.Account.EXPECT().ReadOneById(gomock.Any(), gomock.Any()).Times(1).Return(&entity.Account{UserId: "user-id"}, nil),
+			db.Limit.EXPECT().ReadWithDefault(gomock.Any(), gomock.Any()).Times(1).Return([]entity.Limits{}, nil),
+		}
+	}
+
+	cases := map[string]struct {
+		ExpectedFee *money.Money
+		ExpectedErr error
+		PrepareMock func(db *databaseMocks)
+	}{
+		"deve cobrar a tarifa": {
+			ExpectedFee: func() *money.Money { fee := money.New(100); return &fee }(),
+			ExpectedErr: nil,
+			PrepareMock: func(db *databaseMocks) {
+				calls := append(bookTransfer(db, 20000),
+					db.Fee.EXPECT().ReadActive(gomock.Any()).Times(1).Return([]entity.FeeRule{depositRule, transferRule}, nil),
+					db.Account.EXPECT().ReadOneById(gomock.Any(), sourceUserId).Times(1).Return(readUser(sourceUserId, 9990), nil),
+					db.Transaction.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), sourceUserId).Times(1).Return(readUser(sourceUserId, 9990), nil),
+					db.Account.EXPECT().UpdateBalance(gomock.Any(), sourceUserId, money.New(9890)).Times(1).Return(nil),
+					db.Ledger.EXPECT().CreatePostings(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Transaction.EXPECT().UpdateState(gomock.Any(), entity.BOOKED, gomock.Any()).Times(1).Return(nil),
+					db.StateHistory.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Outbox.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+				)
+				gomock.InOrder(calls...)
+			},
+		},
+		"nao deve cobrar tarifa: nenhuma regra aplicavel": {
+			ExpectedFee: nil,
+			ExpectedErr: nil,
+			PrepareMock: func(db *databaseMocks) {
+				calls := append(bookTransfer(db, 20000),
+					db.Fee.EXPECT().ReadActive(gomock.Any()).Times(1).Return([]entity.FeeRule{depositRule}, nil),
+					db.Account.EXPECT().ReadOneById(gomock.Any(), sourceUserId).Times(1).Return(readUser(sourceUserId, 9990), nil),
+				)
+				gomock.InOrder(calls...)
+			},
+		},
+		"deve retornar erro: saldo insuficiente para a tarifa": {
+			ExpectedFee: nil,
+			ExpectedErr: ErrInsufficientBalance,
+			PrepareMock: func(db *databaseMocks) {
+				calls := append(bookTransfer(db, 10010),
+					db.Fee.EXPECT().ReadActive(gomock.Any()).Times(1).Return([]entity.FeeRule{transferRule}, nil),
+					db.Account.EXPECT().ReadOneById(gomock.Any(), sourceUserId).Times(1).Return(readUser(sourceUserId, 0), nil),
+					db.Transaction.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), sourceUserId).Times(1).Return(readUser(sourceUserId, 0), nil),
+					db.Transaction.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.StateHistory.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Outbox.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+				)
+				gomock.InOrder(calls...)
+			},
+		},
+		"deve retornar erro: ao ler regras": {
+			ExpectedFee: nil,
+			ExpectedErr: echo.ErrInternalServerError,
+			PrepareMock: func(db *databaseMocks) {
+				calls := append(bookTransfer(db, 20000),
+					db.Fee.EXPECT().ReadActive(gomock.Any()).Times(1).Return(nil, echo.ErrInternalServerError),
+					db.Transaction.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.StateHistory.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Outbox.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+				)
+				gomock.InOrder(calls...)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			container, db := newDatabaseContainer(ctrl)
+			cs.PrepareMock(db)
+
+			app := NewAppTransaction(container)
+
+			transaction, err := app.Create(ctx, &entity.Transaction{
+				ID:            "transaction-id",
+				SourceId:      sourceUserId,
+				DestinationId: destinationUserId,
+				Amount:        money.New(10010),
+			})
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+
+			if cs.ExpectedFee == nil {
+				if transaction.Fee != nil {
+					t.Errorf("expected no fee, got %+v", transaction.Fee)
+				}
+				return
+			}
+
+			fee := transaction.Fee
+			if fee == nil {
+				t.Fatal("expected a fee")
+			}
+
+			if diff := cmp.Diff(fee.Amount, *cs.ExpectedFee); diff != "" {
+				t.Error(diff)
+			}
+
+			if fee.Kind != entity.FEE || fee.KindString != "FEE" || fee.SourceId != sourceUserId || fee.DestinationId != entity.FeeAccountId || *fee.ParentId != "transaction-id" || fee.State != entity.BOOKED {
+				t.Errorf("unexpected fee %+v", fee)
+			}
+		})
+	}
+}
+
+func TestIncreaseBalanceUserWithFee(t *testing.T) {
+	userId := "user-id"
+
+	business := entity.BUSINESS
+	flatAmount := money.New(200)
+	rule := entity.FeeRule{ID: "rule-id", Name: "business", Type: entity.FEE_FLAT, AccountType: &business, FlatAmount: &flatAmount, Active: true}
+
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	container, db := newDatabaseContainer(ctrl)
+	gomock.InOrder(
+		db.Transaction.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+		db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), userId).Times(1).Return(&entity.Account{ID: userId, Balance: money.New(1000)}, nil),
+		db.Account.EXPECT().UpdateBalance(gomock.Any(), userId, money.New(11000)).Times(1).Return(nil),
+		db.Ledger.EXPECT().CreatePostings(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+		db.Transaction.EXPECT().UpdateState(gomock.Any(), entity.BOOKED, "transaction-id").Times(1).Return(nil),
+		db.StateHistory.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+		db.Outbox.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+		db.Fee.EXPECT().ReadActive(gomock.Any()).Times(1).Return([]entity.FeeRule{rule}, nil),
+		db.Account.EXPECT().ReadOneById(gomock.Any(), userId).Times(1).Return(&entity.Account{ID: userId, AccountType: entity.BUSINESS}, nil),
+		db.Transaction.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(func(ctx context.Context, fee *entity.Transaction) error {
+			if fee.Kind != entity.FEE || fee.SourceId != userId || fee.Amount != money.New(200) {
+				t.Errorf("unexpected fee %+v", fee)
+			}
+			return nil
+		}),
+		db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), userId).Times(1).Return(&entity.Account{ID: userId, Balance: money.New(11000), AvailableBalance: money.New(11000)}, nil),
+		db.Account.EXPECT().UpdateBalance(gomock.Any(), userId, money.New(10800)).Times(1).Return(nil),
+		db.Ledger.EXPECT().CreatePostings(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+		db.Transaction.EXPECT().UpdateState(gomock.Any(), entity.BOOKED, gomock.Any()).Times(1).Return(nil),
+		db.StateHistory.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+		db.Outbox.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+	)
+
+	app := NewAppTransaction(container)
+
+	balance, err := app.IncreaseBalanceUser(ctx, &entity.TransactionIncreaseBalanceUser{ID: "transaction-id", AccountId: userId, Value: money.New(10000)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff(balance, money.New(10800)); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestCheckLimits(t *testing.T) {
+	amount := func(cents int64) *money.Money {
+		m := money.New(cents)
+		return &m
+	}
+
+	noon := time.Date(2023, 5, 11, 12, 0, 0, 0, time.UTC)
+	night := time.Date(2023, 5, 11, 23, 0, 0, 0, time.UTC)
+
+	transaction := &entity.Transaction{ID: "transaction-id", SourceId: "account-id", Amount: money.New(1000), Kind: entity.TRANSFER}
+
+	cases := map[string]struct {
+		Now         time.Time
+		ExpectedErr error
+		PrepareMock func(db *databaseMocks)
+	}{
+		"deve retornar sucesso: sem limites": {
+			Now:         noon,
+			ExpectedErr: nil,
+			PrepareMock: func(db *databaseMocks) {
+				db.Limit.EXPECT().ReadWithDefault(gomock.Any(), "user-id").Times(1).Return([]entity.Limits{}, nil)
+			},
+		},
+		"deve retornar sucesso: dentro dos limites": {
+			Now:         noon,
+			ExpectedErr: nil,
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.Limit.EXPECT().ReadWithDefault(gomock.Any(), "user-id").Times(1).Return([]entity.Limits{
+						{UserId: entity.DefaultLimitsId, PerTransaction: amount(1000), Daily: amount(1500), Monthly: amount(10000)},
+					}, nil),
+					db.Transaction.EXPECT().ReadSentAmount(gomock.Any(), "user-id", entity.StartOfDay(noon)).Times(1).Return(money.New(1500), nil),
+					db.Transaction.EXPECT().ReadSentAmount(gomock.Any(), "user-id", entity.StartOfMonth(noon)).Times(1).Return(money.New(10000), nil),
+				)
+			},
+		},
+		"deve retornar erro: limite por transacao": {
+			Now:         noon,
+			ExpectedErr: echo.NewHTTPError(echo.ErrBadRequest.Code, "PER_TRANSACTION limit of 5.00 exceeded, 5.00 left"),
+			PrepareMock: func(db *databaseMocks) {
+				db.Limit.EXPECT().ReadWithDefault(gomock.Any(), "user-id").Times(1).Return([]entity.Limits{
+					{UserId: entity.DefaultLimitsId, PerTransaction: amount(500)},
+				}, nil)
+			},
+		},
+		"deve retornar erro: limite diario": {
+			Now:         noon,
+			ExpectedErr: echo.NewHTTPError(echo.ErrBadRequest.Code, "DAILY limit of 15.00 exceeded, 7.00 left"),
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.Limit.EXPECT().ReadWithDefault(gomock.Any(), "user-id").Times(1).Return([]entity.Limits{
+						{UserId: entity.DefaultLimitsId, Daily: amount(1500)},
+					}, nil),
+					db.Transaction.EXPECT().ReadSentAmount(gomock.Any(), "user-id", entity.StartOfDay(noon)).Times(1).Return(money.New(1800), nil),
+				)
+			},
+		},
+		"deve retornar sucesso: limite diario do usuario": {
+			Now:         noon,
+			ExpectedErr: nil,
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.Limit.EXPECT().ReadWithDefault(gomock.Any(), "user-id").Times(1).Return([]entity.Limits{
+						{UserId: entity.DefaultLimitsId, Daily: amount(1500)},
+						{UserId: "user-id", Daily: amount(5000)},
+					}, nil),
+					db.Transaction.EXPECT().ReadSentAmount(gomock.Any(), "user-id", entity.StartOfDay(noon)).Times(1).Return(money.New(1800), nil),
+				)
+			},
+		},
+		"deve retornar erro: limite mensal": {
+			Now:         noon,
+			ExpectedErr: echo.NewHTTPError(echo.ErrBadRequest.Code, "MONTHLY limit of 100.00 exceeded, 0.00 left"),
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.Limit.EXPECT().ReadWithDefault(gomock.Any(), "user-id").Times(1).Return([]entity.Limits{
+						{UserId: entity.DefaultLimitsId, Monthly: amount(10000)},
+					}, nil),
+					db.Transaction.EXPECT().ReadSentAmount(gomock.Any(), "user-id", entity.StartOfMonth(noon)).Times(1).Return(money.New(11000), nil),
+				)
+			},
+		},
+		"deve retornar erro: limite noturno": {
+			Now:         night,
+			ExpectedErr: echo.NewHTTPError(echo.ErrBadRequest.Code, "NIGHT_PER_TRANSACTION limit of 5.00 exceeded, 5.00 left"),
+			PrepareMock: func(db *databaseMocks) {
+				db.Limit.EXPECT().ReadWithDefault(gomock.Any(), "user-id").Times(1).Return([]entity.Limits{
+					{UserId: entity.DefaultLimitsId, NightPerTransaction: amount(500)},
+				}, nil)
+			},
+		},
+		"deve retornar sucesso: limite noturno durante o dia": {
+			Now:         noon,
+			ExpectedErr: nil,
+			PrepareMock: func(db *databaseMocks) {
+				db.Limit.EXPECT().ReadWithDefault(gomock.Any(), "user-id").Times(1).Return([]entity.Limits{
+					{UserId: entity.DefaultLimitsId, NightPerTransaction: amount(500)},
+				}, nil)
+			},
+		},
+		"deve retornar erro: ao ler limites": {
+			Now:         noon,
+			ExpectedErr: echo.ErrInternalServerError,
+			PrepareMock: func(db *databaseMocks) {
+				db.Limit.EXPECT().ReadWithDefault(gomock.Any(), "user-id").Times(1).Return(nil, echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			container, db := newDatabaseContainer(ctrl)
+			db.Account.EXPECT().ReadOneById(gomock.Any(), "account-id").Times(1).Return(&entity.Account{ID: "account-id", UserId: "user-id"}, nil)
+			cs.PrepareMock(db)
+
+			err := checkLimits(ctx, container, transaction, cs.Now)
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestAccrueInterest(t *testing.T) {
+	userId := "user-id"
+	yesterday := entity.StartOfDay(time.Now()).AddDate(0, 0, -1)
+	today := entity.StartOfDay(time.Now())
+
+	overdrawnAccount := entity.Account{
+		ID:                userId,
+		Balance:           money.New(-100000),
+		AvailableBalance:  money.New(-100000),
+		CreditLimit:       money.New(100000),
+		InterestRate:      900,
+		InterestAccruedOn: &yesterday,
+	}
+
+	chargedUser := overdrawnAccount
+	chargedUser.InterestAccruedOn = &today
+
+	cases := map[string]struct {
+		ExpectedErr error
+		PrepareMock func(db *databaseMocks)
+	}{
+		"deve retornar sucesso": {
+			ExpectedErr: nil,
+			PrepareMock: func(db *databaseMocks) {
+				user := overdrawnAccount
+				gomock.InOrder(
+					db.Account.EXPECT().ReadOverdrawn(gomock.Any(), today).Times(1).Return([]entity.Account{overdrawnAccount}, nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), userId).Times(1).Return(&user, nil),
+					db.Transaction.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(func(ctx context.Context, transaction *entity.Transaction) error {
+						if transaction.Kind != entity.INTEREST || transaction.SourceId != userId || transaction.DestinationId != entity.InterestAccountId || transaction.Amount != money.New(300) {
+							t.Errorf("unexpected interest %+v", transaction)
+						}
+						return nil
+					}),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), userId).Times(1).Return(&user, nil),
+					db.Account.EXPECT().UpdateBalance(gomock.Any(), userId, money.New(-100300)).Times(1).Return(nil),
+					db.Ledger.EXPECT().CreatePostings(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Transaction.EXPECT().UpdateState(gomock.Any(), entity.BOOKED, gomock.Any()).Times(1).Return(nil),
+					db.StateHistory.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Outbox.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Account.EXPECT().UpdateInterestAccruedOn(gomock.Any(), userId, today).Times(1).Return(nil),
+				)
+			},
+		},
+		"deve ignorar usuario ja cobrado hoje": {
+			ExpectedErr: nil,
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.Account.EXPECT().ReadOverdrawn(gomock.Any(), today).Times(1).Return([]entity.Account{overdrawnAccount}, nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), userId).Times(1).Return(&chargedUser, nil),
+				)
+			},
+		},
+		"deve continuar apos falha de um usuario": {
+			ExpectedErr: nil,
+			PrepareMock: func(db *databaseMocks) {
+				other := overdrawnAccount
+				other.ID = "other-user-id"
+				other.Balance = money.New(-10)
+				gomock.InOrder(
+					db.Account.EXPECT().ReadOverdrawn(gomock.Any(), today).Times(1).Return([]entity.Account{overdrawnAccount, other}, nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), userId).Times(1).Return(nil, echo.ErrNotFound),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), "other-user-id").Times(1).Return(&other, nil),
+					db.Account.EXPECT().UpdateInterestAccruedOn(gomock.Any(), "other-user-id", today).Times(1).Return(nil),
+				)
+			},
+		},
+		"deve retornar erro": {
+			ExpectedErr: echo.ErrInternalServerError,
+			PrepareMock: func(db *databaseMocks) {
+				db.Account.EXPECT().ReadOverdrawn(gomock.Any(), today).Times(1).Return(nil, echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			container, db := newDatabaseContainer(ctrl)
+			cs.PrepareMock(db)
+
+			app := NewAppTransaction(container)
+
+			err := app.AccrueInterest(ctx)
 			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
 				t.Error(diff)
 			}
@@ -2,16 +2,27 @@ package user
 
 import (
 	"context"
+	"errors"
 	"log"
+	"net/http"
 
+	"github.com/garoque/backend-code-challenge-snapfi/internal/api/dto"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/app/audit"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/app/transaction"
 	"github.com/garoque/backend-code-challenge-snapfi/internal/database"
 	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	"github.com/labstack/echo/v4"
 )
 
 type AppUserInterface interface {
 	Create(ctx context.Context, user entity.User) error
 	ReadOneById(ctx context.Context, userId string) (*entity.User, error)
 	ReadAll(ctx context.Context) ([]entity.User, error)
+	UpdateCreditLine(ctx context.Context, userId string, creditLine dto.UpdateCreditLine) (*entity.User, error)
+	Freeze(ctx context.Context, userId string, request dto.UpdateUserStatus) (*entity.User, error)
+	Unfreeze(ctx context.Context, userId string, request dto.UpdateUserStatus) (*entity.User, error)
+	Close(ctx context.Context, userId string, request dto.CloseUser) (*entity.User, error)
+	ReadStatusHistory(ctx context.Context, userId string) ([]entity.StatusChange, error)
 }
 
 type appUserImpl struct {
@@ -22,14 +33,50 @@ func NewAppUser(db *database.Container) AppUserInterface {
 	return &appUserImpl{db}
 }
 
+// Create stores the user along with their default account, and writes the
+// user.created event to the outbox and the audit log in the same database
+// transaction.
 func (u *appUserImpl) Create(ctx context.Context, user entity.User) error {
-	err := u.db.User.Create(ctx, user)
+	event, err := entity.NewUserCreatedEvent(&user)
 	if err != nil {
-		log.Println("Error app.user.Create.db.Create: ", err.Error())
+		log.Println("Error app.user.Create.NewUserCreatedEvent: ", err.Error())
 		return err
 	}
 
-	return nil
+	return u.db.UnitOfWork.Do(ctx, func(tx *database.Container) error {
+		err := tx.User.Create(ctx, user)
+		if err != nil {
+			log.Println("Error app.user.Create.db.Create: ", err.Error())
+			return err
+		}
+
+		account := entity.NewDefaultAccount(&user)
+		err = tx.Account.Create(ctx, *account)
+		if err != nil {
+			log.Println("Error app.user.Create.db.Account.Create: ", err.Error())
+			return err
+		}
+
+		err = tx.Outbox.Create(ctx, event)
+		if err != nil {
+			log.Println("Error app.user.Create.db.Outbox.Create: ", err.Error())
+			return err
+		}
+
+		err = audit.Record(ctx, tx, entity.AUDIT_USER_CREATED, entity.AGGREGATE_USER, user.ID, nil, user)
+		if err != nil {
+			log.Println("Error app.user.Create.audit.Record: ", err.Error())
+			return err
+		}
+
+		err = audit.Record(ctx, tx, entity.AUDIT_ACCOUNT_CREATED, entity.AUDIT_ACCOUNT, account.ID, nil, account)
+		if err != nil {
+			log.Println("Error app.user.Create.audit.Record: ", err.Error())
+			return err
+		}
+
+		return nil
+	})
 }
 
 func (u *appUserImpl) ReadOneById(ctx context.Context, userId string) (*entity.User, error) {
@@ -39,7 +86,10 @@ func (u *appUserImpl) ReadOneById(ctx context.Context, userId string) (*entity.U
 		return nil, err
 	}
 
-	return user, nil
+	user.AccountTypeString = user.AccountType.String()
+	user.StatusString = user.Status.String()
+
+	return user.SetCredit(), nil
 }
 
 func (u *appUserImpl) ReadAll(ctx context.Context) ([]entity.User, error) {
@@ -49,5 +99,231 @@ func (u *appUserImpl) ReadAll(ctx context.Context) ([]entity.User, error) {
 		return nil, err
 	}
 
+	for i := range users {
+		users[i].AccountTypeString = users[i].AccountType.String()
+		users[i].StatusString = users[i].Status.String()
+		users[i].SetCredit()
+	}
+
 	return users, nil
 }
+
+// UpdateCreditLine sets the credit limit and the monthly interest rate of the
+// default account of the user. Lowering the limit below the credit in use
+// doesn't claw anything back, it only stops the user from spending further.
+func (u *appUserImpl) UpdateCreditLine(ctx context.Context, userId string, creditLine dto.UpdateCreditLine) (*entity.User, error) {
+	var user *entity.User
+	err := u.db.UnitOfWork.Do(ctx, func(tx *database.Container) error {
+		var err error
+		user, err = tx.User.ReadOneByIdForUpdate(ctx, userId)
+		if err != nil {
+			log.Println("Error app.user.UpdateCreditLine.db.ReadOneByIdForUpdate: ", err.Error())
+			return err
+		}
+
+		err = tx.Account.UpdateCreditLine(ctx, userId, creditLine.CreditLimit, creditLine.InterestRate)
+		if err != nil {
+			log.Println("Error app.user.UpdateCreditLine.db.Account.UpdateCreditLine: ", err.Error())
+			return err
+		}
+
+		before := dto.UpdateCreditLine{CreditLimit: user.CreditLimit, InterestRate: user.InterestRate}
+		err = audit.Record(ctx, tx, entity.AUDIT_USER_CREDIT_LINE_UPDATED, entity.AGGREGATE_USER, userId, before, creditLine)
+		if err != nil {
+			log.Println("Error app.user.UpdateCreditLine.audit.Record: ", err.Error())
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	user.CreditLimit = creditLine.CreditLimit
+	user.InterestRate = creditLine.InterestRate
+	user.AccountTypeString = user.AccountType.String()
+	user.StatusString = user.Status.String()
+
+	return user.SetCredit(), nil
+}
+
+// Freeze blocks the user from sending, receiving and depositing money until
+// they are unfrozen.
+func (u *appUserImpl) Freeze(ctx context.Context, userId string, request dto.UpdateUserStatus) (*entity.User, error) {
+	return u.updateStatus(ctx, userId, entity.FROZEN, request.Reason)
+}
+
+// Unfreeze lets a frozen user move money again.
+func (u *appUserImpl) Unfreeze(ctx context.Context, userId string, request dto.UpdateUserStatus) (*entity.User, error) {
+	return u.updateStatus(ctx, userId, entity.ACTIVE, request.Reason)
+}
+
+func (u *appUserImpl) updateStatus(ctx context.Context, userId string, next entity.StatusUser, reason string) (*entity.User, error) {
+	var user *entity.User
+	err := u.db.UnitOfWork.Do(ctx, func(tx *database.Container) error {
+		var err error
+		user, err = tx.User.ReadOneByIdForUpdate(ctx, userId)
+		if err != nil {
+			log.Println("Error app.user.updateStatus.db.ReadOneByIdForUpdate: ", err.Error())
+			return err
+		}
+
+		return changeStatus(ctx, tx, user, next, reason)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	user.AccountTypeString = user.AccountType.String()
+
+	return user.SetCredit(), nil
+}
+
+// Close closes the user and their accounts for good. Every balance must be
+// zero, unless SweepToUserId names the user whose default account the
+// remaining balances are swept to, and no funds may be held by
+// authorizations. A negative balance has to be settled first.
+func (u *appUserImpl) Close(ctx context.Context, userId string, request dto.CloseUser) (*entity.User, error) {
+	var user *entity.User
+	err := u.db.UnitOfWork.Do(ctx, func(tx *database.Container) error {
+		accounts, err := tx.Account.ReadAllByUserId(ctx, userId)
+		if err != nil {
+			log.Println("Error app.user.Close.db.Account.ReadAllByUserId: ", err.Error())
+			return err
+		}
+
+		if request.SweepToUserId != "" {
+			for _, account := range accounts {
+				_, err := transaction.Sweep(ctx, tx, account.ID, request.SweepToUserId)
+				if err != nil {
+					log.Println("Error app.user.Close.transaction.Sweep: ", err.Error())
+					return err
+				}
+			}
+		}
+
+		user, err = tx.User.ReadOneByIdForUpdate(ctx, userId)
+		if err != nil {
+			log.Println("Error app.user.Close.db.ReadOneByIdForUpdate: ", err.Error())
+			return err
+		}
+
+		for _, account := range accounts {
+			locked, err := tx.Account.ReadOneByIdForUpdate(ctx, account.ID)
+			if err != nil {
+				log.Println("Error app.user.Close.db.Account.ReadOneByIdForUpdate: ", err.Error())
+				return err
+			}
+
+			err = checkClosable(locked)
+			if err != nil {
+				return err
+			}
+		}
+
+		return changeStatus(ctx, tx, user, entity.CLOSED, request.Reason)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	user.AccountTypeString = user.AccountType.String()
+
+	return user.SetCredit(), nil
+}
+
+// checkClosable fails when the locked account still holds or owes money.
+func checkClosable(account *entity.Account) error {
+	if !account.HeldBalance.IsZero() {
+		return echo.NewHTTPError(http.StatusConflict, "The user has funds held by authorizations, capture or void them before closing the account")
+	}
+
+	if account.Balance.IsNegative() {
+		return echo.NewHTTPError(http.StatusConflict, "The user owes "+account.Balance.Neg().String()+", settle it before closing the account")
+	}
+
+	if account.Balance.IsPositive() {
+		return echo.NewHTTPError(http.StatusConflict, "The balance must be zero to close the account, or swept to another account with sweepToUserId")
+	}
+
+	return nil
+}
+
+// changeStatus moves the locked user to the next status, records the change
+// in their status history and the audit log, and writes the
+// user.status_changed event to the outbox.
+func changeStatus(ctx context.Context, tx *database.Container, user *entity.User, next entity.StatusUser, reason string) error {
+	change, err := user.ChangeStatus(next, reason)
+	if err != nil {
+		log.Println("Error app.user.changeStatus.ChangeStatus: ", err.Error())
+		return statusError(err)
+	}
+
+	err = tx.User.UpdateStatus(ctx, user.ID, next)
+	if err != nil {
+		log.Println("Error app.user.changeStatus.db.UpdateStatus: ", err.Error())
+		return err
+	}
+
+	err = tx.User.CreateStatusChange(ctx, change)
+	if err != nil {
+		log.Println("Error app.user.changeStatus.db.CreateStatusChange: ", err.Error())
+		return err
+	}
+
+	event, err := entity.NewUserStatusEvent(change)
+	if err != nil {
+		log.Println("Error app.user.changeStatus.NewUserStatusEvent: ", err.Error())
+		return err
+	}
+
+	err = tx.Outbox.Create(ctx, event)
+	if err != nil {
+		log.Println("Error app.user.changeStatus.db.Outbox.Create: ", err.Error())
+		return err
+	}
+
+	before := entity.AuditState{State: change.From.String()}
+	after := entity.AuditState{State: change.To.String(), Reason: reason}
+	err = audit.Record(ctx, tx, entity.AUDIT_USER_STATUS_UPDATED, entity.AGGREGATE_USER, user.ID, before, after)
+	if err != nil {
+		log.Println("Error app.user.changeStatus.audit.Record: ", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// statusError reports an illegal status change as a conflict with the
+// current status of the user.
+func statusError(err error) error {
+	var invalidChange *entity.ErrInvalidStatusChange
+	if errors.As(err, &invalidChange) {
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+
+	return err
+}
+
+// ReadStatusHistory lists the status changes of the user, oldest first.
+func (u *appUserImpl) ReadStatusHistory(ctx context.Context, userId string) ([]entity.StatusChange, error) {
+	_, err := u.db.User.ReadOneById(ctx, userId)
+	if err != nil {
+		log.Println("Error app.user.ReadStatusHistory.db.ReadOneById: ", err.Error())
+		return nil, err
+	}
+
+	changes, err := u.db.User.ReadStatusHistory(ctx, userId)
+	if err != nil {
+		log.Println("Error app.user.ReadStatusHistory.db.ReadStatusHistory: ", err.Error())
+		return nil, err
+	}
+
+	for i := range changes {
+		changes[i].FromString = changes[i].From.String()
+		changes[i].ToString = changes[i].To.String()
+	}
+
+	return changes, nil
+}
@@ -2,12 +2,15 @@ package user
 
 import (
 	"context"
+	"net/http"
 	"testing"
 
 	"github.com/garoque/backend-code-challenge-snapfi/internal/api/dto"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/app/transaction"
 	"github.com/garoque/backend-code-challenge-snapfi/internal/database"
 	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
 	"github.com/garoque/backend-code-challenge-snapfi/internal/mocks"
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/money"
 	"github.com/garoque/backend-code-challenge-snapfi/pkg/uuid"
 	"github.com/golang/mock/gomock"
 	"github.com/google/go-cmp/cmp"
@@ -20,19 +23,73 @@ func TestCreate(t *testing.T) {
 	cases := map[string]struct {
 		InputUser   entity.User
 		ExpectedErr error
-		PrepareMock func(mockUserDb *mocks.MockDabataseUserInterface)
+		PrepareMock func(mockUserDb *mocks.MockDabataseUserInterface, mockAccountDb *mocks.MockDabataseAccountInterface, mockOutboxDb *mocks.MockDabataseOutboxInterface, mockAuditDb *mocks.MockDabataseAuditInterface)
 	}{
 		"deve retornar sucesso": {
 			InputUser:   user,
 			ExpectedErr: nil,
-			PrepareMock: func(mockUserDb *mocks.MockDabataseUserInterface) {
-				mockUserDb.EXPECT().Create(gomock.Any(), user).Times(1).Return(nil)
+			PrepareMock: func(mockUserDb *mocks.MockDabataseUserInterface, mockAccountDb *mocks.MockDabataseAccountInterface, mockOutboxDb *mocks.MockDabataseOutboxInterface, mockAuditDb *mocks.MockDabataseAuditInterface) {
+				gomock.InOrder(
+					mockUserDb.EXPECT().Create(gomock.Any(), user).Times(1).Return(nil),
+					mockAccountDb.EXPECT().Create(gomock.Any(), *entity.NewDefaultAccount(&user)).Times(1).Return(nil),
+					mockOutboxDb.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).
+						Do(func(ctx context.Context, event *entity.Event) {
+							if event.Type != entity.EVENT_USER_CREATED || event.AggregateId != user.ID {
+								t.Errorf("unexpected event %+v", event)
+							}
+						}).Return(nil),
+					mockAuditDb.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).
+						Do(func(ctx context.Context, entry *entity.AuditEntry) {
+							if entry.Operation != entity.AUDIT_USER_CREATED || entry.EntityId != user.ID || entry.Actor != entity.AUDIT_SYSTEM_ACTOR || entry.Before != nil {
+								t.Errorf("unexpected audit entry %+v", entry)
+							}
+						}).Return(nil),
+					mockAuditDb.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).
+						Do(func(ctx context.Context, entry *entity.AuditEntry) {
+							if entry.Operation != entity.AUDIT_ACCOUNT_CREATED || entry.EntityType != entity.AUDIT_ACCOUNT || entry.EntityId != user.ID {
+								t.Errorf("unexpected audit entry %+v", entry)
+							}
+						}).Return(nil),
+				)
+			},
+		},
+		"deve retornar erro: ao gravar a auditoria": {
+			InputUser:   user,
+			ExpectedErr: echo.ErrInternalServerError,
+			PrepareMock: func(mockUserDb *mocks.MockDabataseUserInterface, mockAccountDb *mocks.MockDabataseAccountInterface, mockOutboxDb *mocks.MockDabataseOutboxInterface, mockAuditDb *mocks.MockDabataseAuditInterface) {
+				gomock.InOrder(
+					mockUserDb.EXPECT().Create(gomock.Any(), user).Times(1).Return(nil),
+					mockAccountDb.EXPECT().Create(gomock.Any(), *entity.NewDefaultAccount(&user)).Times(1).Return(nil),
+					mockOutboxDb.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					mockAuditDb.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(echo.ErrInternalServerError),
+				)
+			},
+		},
+		"deve retornar erro: ao gravar o evento": {
+			InputUser:   user,
+			ExpectedErr: echo.ErrInternalServerError,
+			PrepareMock: func(mockUserDb *mocks.MockDabataseUserInterface, mockAccountDb *mocks.MockDabataseAccountInterface, mockOutboxDb *mocks.MockDabataseOutboxInterface, mockAuditDb *mocks.MockDabataseAuditInterface) {
+				gomock.InOrder(
+					mockUserDb.EXPECT().Create(gomock.Any(), user).Times(1).Return(nil),
+					mockAccountDb.EXPECT().Create(gomock.Any(), *entity.NewDefaultAccount(&user)).Times(1).Return(nil),
+					mockOutboxDb.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(echo.ErrInternalServerError),
+				)
+			},
+		},
+		"deve retornar erro: ao criar a conta padrao": {
+			InputUser:   user,
+			ExpectedErr: echo.ErrInternalServerError,
+			PrepareMock: func(mockUserDb *mocks.MockDabataseUserInterface, mockAccountDb *mocks.MockDabataseAccountInterface, mockOutboxDb *mocks.MockDabataseOutboxInterface, mockAuditDb *mocks.MockDabataseAuditInterface) {
+				gomock.InOrder(
+					mockUserDb.EXPECT().Create(gomock.Any(), user).Times(1).Return(nil),
+					mockAccountDb.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(echo.ErrInternalServerError),
+				)
 			},
 		},
 		"deve retornar erro": {
 			InputUser:   user,
 			ExpectedErr: echo.ErrInternalServerError,
-			PrepareMock: func(mockUserDb *mocks.MockDabataseUserInterface) {
+			PrepareMock: func(mockUserDb *mocks.MockDabataseUserInterface, mockAccountDb *mocks.MockDabataseAccountInterface, mockOutboxDb *mocks.MockDabataseOutboxInterface, mockAuditDb *mocks.MockDabataseAuditInterface) {
 				mockUserDb.EXPECT().Create(gomock.Any(), user).Times(1).Return(echo.ErrInternalServerError)
 			},
 		},
@@ -43,9 +100,19 @@ func TestCreate(t *testing.T) {
 			ctrl, ctx := gomock.WithContext(context.Background(), t)
 
 			mockUserDb := mocks.NewMockDabataseUserInterface(ctrl)
-			cs.PrepareMock(mockUserDb)
+			mockAccountDb := mocks.NewMockDabataseAccountInterface(ctrl)
+			mockOutboxDb := mocks.NewMockDabataseOutboxInterface(ctrl)
+			mockAuditDb := mocks.NewMockDabataseAuditInterface(ctrl)
+			cs.PrepareMock(mockUserDb, mockAccountDb, mockOutboxDb, mockAuditDb)
 
-			app := NewAppUser(&database.Container{User: mockUserDb})
+			mockUnitOfWork := mocks.NewMockUnitOfWorkInterface(ctrl)
+			container := &database.Container{User: mockUserDb, Account: mockAccountDb, Outbox: mockOutboxDb, Audit: mockAuditDb, UnitOfWork: mockUnitOfWork}
+			mockUnitOfWork.EXPECT().Do(gomock.Any(), gomock.Any()).Times(1).
+				DoAndReturn(func(ctx context.Context, fn func(tx *database.Container) error) error {
+					return fn(container)
+				})
+
+			app := NewAppUser(container)
 
 			err := app.Create(ctx, cs.InputUser)
 			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
@@ -154,3 +221,497 @@ func TestReadAll(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdateCreditLine(t *testing.T) {
+	userId := uuid.NewId()
+	creditLine := dto.UpdateCreditLine{CreditLimit: money.New(50000), InterestRate: 800}
+
+	cases := map[string]struct {
+		ExpectedResult *entity.User
+		ExpectedErr    error
+		PrepareMock    func(mockUserDb *mocks.MockDabataseUserInterface, mockAccountDb *mocks.MockDabataseAccountInterface, mockAuditDb *mocks.MockDabataseAuditInterface)
+	}{
+		"deve retornar sucesso": {
+			ExpectedResult: &entity.User{
+				ID:                userId,
+				AccountTypeString: "PERSONAL",
+				StatusString:      "ACTIVE",
+				Balance:           money.New(-10000),
+				AvailableBalance:  money.New(-10000),
+				CreditLimit:       money.New(50000),
+				UsedCredit:        money.New(10000),
+				AvailableCredit:   money.New(40000),
+				InterestRate:      800,
+			},
+			ExpectedErr: nil,
+			PrepareMock: func(mockUserDb *mocks.MockDabataseUserInterface, mockAccountDb *mocks.MockDabataseAccountInterface, mockAuditDb *mocks.MockDabataseAuditInterface) {
+				gomock.InOrder(
+					mockUserDb.EXPECT().ReadOneByIdForUpdate(gomock.Any(), userId).Times(1).Return(&entity.User{
+						ID:               userId,
+						Balance:          money.New(-10000),
+						AvailableBalance: money.New(-10000),
+						CreditLimit:      money.New(10000),
+					}, nil),
+					mockAccountDb.EXPECT().UpdateCreditLine(gomock.Any(), userId, money.New(50000), int64(800)).Times(1).Return(nil),
+					mockAuditDb.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).
+						Do(func(ctx context.Context, entry *entity.AuditEntry) {
+							if string(*entry.Before) != `{"creditLimit":"100.00","interestRate":0}` || string(*entry.After) != `{"creditLimit":"500.00","interestRate":800}` {
+								t.Errorf("unexpected audit entry %s -> %s", *entry.Before, *entry.After)
+							}
+						}).Return(nil),
+				)
+			},
+		},
+		"deve retornar erro: usuario nao encontrado": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrNotFound,
+			PrepareMock: func(mockUserDb *mocks.MockDabataseUserInterface, mockAccountDb *mocks.MockDabataseAccountInterface, mockAuditDb *mocks.MockDabataseAuditInterface) {
+				mockUserDb.EXPECT().ReadOneByIdForUpdate(gomock.Any(), userId).Times(1).Return(nil, echo.ErrNotFound)
+			},
+		},
+		"deve retornar erro": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrInternalServerError,
+			PrepareMock: func(mockUserDb *mocks.MockDabataseUserInterface, mockAccountDb *mocks.MockDabataseAccountInterface, mockAuditDb *mocks.MockDabataseAuditInterface) {
+				gomock.InOrder(
+					mockUserDb.EXPECT().ReadOneByIdForUpdate(gomock.Any(), userId).Times(1).Return(&entity.User{ID: userId}, nil),
+					mockAccountDb.EXPECT().UpdateCreditLine(gomock.Any(), userId, money.New(50000), int64(800)).Times(1).Return(echo.ErrInternalServerError),
+				)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			mockUserDb := mocks.NewMockDabataseUserInterface(ctrl)
+			mockAccountDb := mocks.NewMockDabataseAccountInterface(ctrl)
+			mockAuditDb := mocks.NewMockDabataseAuditInterface(ctrl)
+			cs.PrepareMock(mockUserDb, mockAccountDb, mockAuditDb)
+
+			mockUnitOfWork := mocks.NewMockUnitOfWorkInterface(ctrl)
+			container := &database.Container{User: mockUserDb, Account: mockAccountDb, Audit: mockAuditDb, UnitOfWork: mockUnitOfWork}
+			mockUnitOfWork.EXPECT().Do(gomock.Any(), gomock.Any()).Times(1).
+				DoAndReturn(func(ctx context.Context, fn func(tx *database.Container) error) error {
+					return fn(container)
+				})
+
+			app := NewAppUser(container)
+
+			user, err := app.UpdateCreditLine(ctx, userId, creditLine)
+			if diff := cmp.Diff(user, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+type databaseMocks struct {
+	User         *mocks.MockDabataseUserInterface
+	Account      *mocks.MockDabataseAccountInterface
+	Transaction  *mocks.MockDabataseTransactionInterface
+	Ledger       *mocks.MockDabataseLedgerInterface
+	StateHistory *mocks.MockDabataseStateHistoryInterface
+	Outbox       *mocks.MockDabataseOutboxInterface
+	Audit        *mocks.MockDabataseAuditInterface
+}
+
+// newDatabaseContainer returns a container backed by mocks whose unit of work
+// runs the given function against the same mocks.
+func newDatabaseContainer(ctrl *gomock.Controller) (*database.Container, *databaseMocks) {
+	db := &databaseMocks{
+		User:         mocks.NewMockDabataseUserInterface(ctrl),
+		Account:      mocks.NewMockDabataseAccountInterface(ctrl),
+		Transaction:  mocks.NewMockDabataseTransactionInterface(ctrl),
+		Ledger:       mocks.NewMockDabataseLedgerInterface(ctrl),
+		StateHistory: mocks.NewMockDabataseStateHistoryInterface(ctrl),
+		Outbox:       mocks.NewMockDabataseOutboxInterface(ctrl),
+		Audit:        mocks.NewMockDabataseAuditInterface(ctrl),
+	}
+	mockUnitOfWork := mocks.NewMockUnitOfWorkInterface(ctrl)
+
+	container := &database.Container{
+		User:         db.User,
+		Account:      db.Account,
+		Transaction:  db.Transaction,
+		Ledger:       db.Ledger,
+		StateHistory: db.StateHistory,
+		Outbox:       db.Outbox,
+		Audit:        db.Audit,
+		UnitOfWork:   mockUnitOfWork,
+	}
+	mockUnitOfWork.EXPECT().Do(gomock.Any(), gomock.Any()).AnyTimes().
+		DoAndReturn(func(ctx context.Context, fn func(tx *database.Container) error) error {
+			return fn(container)
+		})
+
+	return container, db
+}
+
+func TestUpdateStatus(t *testing.T) {
+	userId := uuid.NewId()
+	request := dto.UpdateUserStatus{Reason: "suspected account takeover"}
+
+	cases := map[string]struct {
+		Freeze         bool
+		ExpectedResult *entity.User
+		ExpectedErr    error
+		PrepareMock    func(db *databaseMocks)
+	}{
+		"deve retornar sucesso: ao congelar": {
+			Freeze: true,
+			ExpectedResult: &entity.User{
+				ID:                userId,
+				AccountTypeString: "PERSONAL",
+				Status:            entity.FROZEN,
+				StatusString:      "FROZEN",
+				Balance:           money.New(1000),
+				AvailableBalance:  money.New(1000),
+				UsedCredit:        money.New(0),
+				AvailableCredit:   money.New(0),
+			},
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.User.EXPECT().ReadOneByIdForUpdate(gomock.Any(), userId).Times(1).Return(&entity.User{
+						ID:               userId,
+						Balance:          money.New(1000),
+						AvailableBalance: money.New(1000),
+					}, nil),
+					db.User.EXPECT().UpdateStatus(gomock.Any(), userId, entity.FROZEN).Times(1).Return(nil),
+					db.User.EXPECT().CreateStatusChange(gomock.Any(), &entity.StatusChange{
+						UserId: userId,
+						From:   entity.ACTIVE,
+						To:     entity.FROZEN,
+						Reason: "suspected account takeover",
+					}).Times(1).Return(nil),
+					db.Outbox.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).
+						Do(func(ctx context.Context, event *entity.Event) {
+							if event.Type != entity.EVENT_USER_STATUS_CHANGED || event.AggregateId != userId {
+								t.Errorf("unexpected event %+v", event)
+							}
+						}).Return(nil),
+					db.Audit.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).
+						Do(func(ctx context.Context, entry *entity.AuditEntry) {
+							if entry.Operation != entity.AUDIT_USER_STATUS_UPDATED || string(*entry.Before) != `{"state":"ACTIVE"}` || string(*entry.After) != `{"state":"FROZEN","reason":"suspected account takeover"}` {
+								t.Errorf("unexpected audit entry %+v", entry)
+							}
+						}).Return(nil),
+				)
+			},
+		},
+		"deve retornar sucesso: ao descongelar": {
+			Freeze: false,
+			ExpectedResult: &entity.User{
+				ID:                userId,
+				AccountTypeString: "PERSONAL",
+				Status:            entity.ACTIVE,
+				StatusString:      "ACTIVE",
+				UsedCredit:        money.New(0),
+				AvailableCredit:   money.New(0),
+			},
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.User.EXPECT().ReadOneByIdForUpdate(gomock.Any(), userId).Times(1).Return(&entity.User{ID: userId, Status: entity.FROZEN}, nil),
+					db.User.EXPECT().UpdateStatus(gomock.Any(), userId, entity.ACTIVE).Times(1).Return(nil),
+					db.User.EXPECT().CreateStatusChange(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Outbox.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Audit.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+				)
+			},
+		},
+		"deve retornar erro: user ja congelado": {
+			Freeze:      true,
+			ExpectedErr: echo.NewHTTPError(http.StatusConflict, "user can't move from FROZEN to FROZEN"),
+			PrepareMock: func(db *databaseMocks) {
+				db.User.EXPECT().ReadOneByIdForUpdate(gomock.Any(), userId).Times(1).Return(&entity.User{ID: userId, Status: entity.FROZEN}, nil)
+			},
+		},
+		"deve retornar erro: user encerrado": {
+			Freeze:      false,
+			ExpectedErr: echo.NewHTTPError(http.StatusConflict, "user can't move from CLOSED to ACTIVE"),
+			PrepareMock: func(db *databaseMocks) {
+				db.User.EXPECT().ReadOneByIdForUpdate(gomock.Any(), userId).Times(1).Return(&entity.User{ID: userId, Status: entity.CLOSED}, nil)
+			},
+		},
+		"deve retornar erro: usuario nao encontrado": {
+			Freeze:      true,
+			ExpectedErr: echo.ErrNotFound,
+			PrepareMock: func(db *databaseMocks) {
+				db.User.EXPECT().ReadOneByIdForUpdate(gomock.Any(), userId).Times(1).Return(nil, echo.ErrNotFound)
+			},
+		},
+		"deve retornar erro: ao atualizar o status": {
+			Freeze:      true,
+			ExpectedErr: echo.ErrInternalServerError,
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.User.EXPECT().ReadOneByIdForUpdate(gomock.Any(), userId).Times(1).Return(&entity.User{ID: userId}, nil),
+					db.User.EXPECT().UpdateStatus(gomock.Any(), userId, entity.FROZEN).Times(1).Return(echo.ErrInternalServerError),
+				)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			container, db := newDatabaseContainer(ctrl)
+			cs.PrepareMock(db)
+
+			app := NewAppUser(container)
+
+			var user *entity.User
+			var err error
+			if cs.Freeze {
+				user, err = app.Freeze(ctx, userId, request)
+			} else {
+				user, err = app.Unfreeze(ctx, userId, request)
+			}
+
+			if diff := cmp.Diff(user, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestClose(t *testing.T) {
+	userId := "user-id"
+	savingsId := "savings-id"
+	destinationUserId := "destination-user-id"
+	accounts := []entity.Account{{ID: userId, UserId: userId}, {ID: savingsId, UserId: userId}}
+
+	cases := map[string]struct {
+		InputRequest   dto.CloseUser
+		ExpectedStatus string
+		ExpectedErr    error
+		PrepareMock    func(db *databaseMocks)
+	}{
+		"deve retornar sucesso: saldo zerado": {
+			InputRequest:   dto.CloseUser{Reason: "customer request"},
+			ExpectedStatus: "CLOSED",
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.Account.EXPECT().ReadAllByUserId(gomock.Any(), userId).Times(1).Return(accounts, nil),
+					db.User.EXPECT().ReadOneByIdForUpdate(gomock.Any(), userId).Times(1).Return(&entity.User{ID: userId}, nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), userId).Times(1).Return(&entity.Account{ID: userId, UserId: userId}, nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), savingsId).Times(1).Return(&entity.Account{ID: savingsId, UserId: userId}, nil),
+					db.User.EXPECT().UpdateStatus(gomock.Any(), userId, entity.CLOSED).Times(1).Return(nil),
+					db.User.EXPECT().CreateStatusChange(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Outbox.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Audit.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+				)
+			},
+		},
+		"deve retornar sucesso: transferindo o saldo": {
+			InputRequest:   dto.CloseUser{Reason: "customer request", SweepToUserId: destinationUserId},
+			ExpectedStatus: "CLOSED",
+			PrepareMock: func(db *databaseMocks) {
+				account := &entity.Account{ID: userId, UserId: userId, Status: entity.FROZEN, Balance: money.New(2550), AvailableBalance: money.New(2550)}
+				savings := &entity.Account{ID: savingsId, UserId: userId, Status: entity.FROZEN}
+				destination := &entity.Account{ID: destinationUserId, UserId: destinationUserId}
+
+				db.Audit.EXPECT().Create(gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
+				db.Outbox.EXPECT().Create(gomock.Any(), gomock.Any()).Times(2).Return(nil)
+				gomock.InOrder(
+					db.Account.EXPECT().ReadAllByUserId(gomock.Any(), userId).Times(1).Return(accounts, nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), destinationUserId).Times(1).Return(destination, nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), userId).Times(1).Return(account, nil),
+					db.Transaction.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).
+						Do(func(ctx context.Context, transaction *entity.Transaction) {
+							if transaction.Kind != entity.SWEEP || transaction.DestinationId != destinationUserId || transaction.Amount != money.New(2550) {
+								t.Errorf("unexpected sweep %+v", transaction)
+							}
+						}).Return(nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), destinationUserId).Times(1).Return(destination, nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), userId).Times(1).Return(account, nil),
+					db.Account.EXPECT().UpdateBalance(gomock.Any(), userId, money.New(0)).Times(1).Return(nil),
+					db.Account.EXPECT().UpdateBalance(gomock.Any(), destinationUserId, money.New(2550)).Times(1).Return(nil),
+					db.Ledger.EXPECT().CreatePostings(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Transaction.EXPECT().UpdateState(gomock.Any(), entity.BOOKED, gomock.Any()).Times(1).Return(nil),
+					db.StateHistory.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), destinationUserId).Times(1).Return(destination, nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), savingsId).Times(1).Return(savings, nil),
+					db.User.EXPECT().ReadOneByIdForUpdate(gomock.Any(), userId).Times(1).Return(&entity.User{ID: userId, Status: entity.FROZEN}, nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), userId).Times(1).Return(&entity.Account{ID: userId, UserId: userId}, nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), savingsId).Times(1).Return(savings, nil),
+					db.User.EXPECT().UpdateStatus(gomock.Any(), userId, entity.CLOSED).Times(1).Return(nil),
+					db.User.EXPECT().CreateStatusChange(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+				)
+			},
+		},
+		"deve retornar erro: saldo positivo em outra conta": {
+			InputRequest: dto.CloseUser{Reason: "customer request"},
+			ExpectedErr:  echo.NewHTTPError(http.StatusConflict, "The balance must be zero to close the account, or swept to another account with sweepToUserId"),
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.Account.EXPECT().ReadAllByUserId(gomock.Any(), userId).Times(1).Return(accounts, nil),
+					db.User.EXPECT().ReadOneByIdForUpdate(gomock.Any(), userId).Times(1).Return(&entity.User{ID: userId}, nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), userId).Times(1).Return(&entity.Account{ID: userId, UserId: userId}, nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), savingsId).Times(1).Return(&entity.Account{ID: savingsId, UserId: userId, Balance: money.New(100)}, nil),
+				)
+			},
+		},
+		"deve retornar erro: saldo negativo": {
+			InputRequest: dto.CloseUser{Reason: "customer request"},
+			ExpectedErr:  echo.NewHTTPError(http.StatusConflict, "The user owes 1.00, settle it before closing the account"),
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.Account.EXPECT().ReadAllByUserId(gomock.Any(), userId).Times(1).Return(accounts[:1], nil),
+					db.User.EXPECT().ReadOneByIdForUpdate(gomock.Any(), userId).Times(1).Return(&entity.User{ID: userId, Balance: money.New(-100)}, nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), userId).Times(1).Return(&entity.Account{ID: userId, UserId: userId, Balance: money.New(-100)}, nil),
+				)
+			},
+		},
+		"deve retornar erro: saldo retido": {
+			InputRequest: dto.CloseUser{Reason: "customer request"},
+			ExpectedErr:  echo.NewHTTPError(http.StatusConflict, "The user has funds held by authorizations, capture or void them before closing the account"),
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.Account.EXPECT().ReadAllByUserId(gomock.Any(), userId).Times(1).Return(accounts[:1], nil),
+					db.User.EXPECT().ReadOneByIdForUpdate(gomock.Any(), userId).Times(1).Return(&entity.User{ID: userId}, nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), userId).Times(1).Return(&entity.Account{ID: userId, UserId: userId, Balance: money.New(100), HeldBalance: money.New(100)}, nil),
+				)
+			},
+		},
+		"deve retornar erro: user ja encerrado": {
+			InputRequest: dto.CloseUser{Reason: "customer request"},
+			ExpectedErr:  echo.NewHTTPError(http.StatusConflict, "user can't move from CLOSED to CLOSED"),
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.Account.EXPECT().ReadAllByUserId(gomock.Any(), userId).Times(1).Return(accounts[:1], nil),
+					db.User.EXPECT().ReadOneByIdForUpdate(gomock.Any(), userId).Times(1).Return(&entity.User{ID: userId, Status: entity.CLOSED}, nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), userId).Times(1).Return(&entity.Account{ID: userId, UserId: userId, Status: entity.CLOSED}, nil),
+				)
+			},
+		},
+		"deve retornar erro: usuario nao encontrado": {
+			InputRequest: dto.CloseUser{Reason: "customer request"},
+			ExpectedErr:  echo.ErrNotFound,
+			PrepareMock: func(db *databaseMocks) {
+				gomock.InOrder(
+					db.Account.EXPECT().ReadAllByUserId(gomock.Any(), userId).Times(1).Return([]entity.Account{}, nil),
+					db.User.EXPECT().ReadOneByIdForUpdate(gomock.Any(), userId).Times(1).Return(nil, echo.ErrNotFound),
+				)
+			},
+		},
+		"deve retornar erro: ao ler as contas": {
+			InputRequest: dto.CloseUser{Reason: "customer request"},
+			ExpectedErr:  echo.ErrInternalServerError,
+			PrepareMock: func(db *databaseMocks) {
+				db.Account.EXPECT().ReadAllByUserId(gomock.Any(), userId).Times(1).Return(nil, echo.ErrInternalServerError)
+			},
+		},
+		"deve retornar erro: transferindo para user congelado": {
+			InputRequest: dto.CloseUser{Reason: "customer request", SweepToUserId: destinationUserId},
+			ExpectedErr:  transaction.ErrUserFrozen,
+			PrepareMock: func(db *databaseMocks) {
+				account := &entity.Account{ID: userId, UserId: userId, Balance: money.New(2550), AvailableBalance: money.New(2550)}
+				destination := &entity.Account{ID: destinationUserId, UserId: destinationUserId, Status: entity.FROZEN}
+
+				db.Audit.EXPECT().Create(gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
+				gomock.InOrder(
+					db.Account.EXPECT().ReadAllByUserId(gomock.Any(), userId).Times(1).Return(accounts, nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), destinationUserId).Times(1).Return(destination, nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), userId).Times(1).Return(account, nil),
+					db.Transaction.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), destinationUserId).Times(1).Return(destination, nil),
+					db.Account.EXPECT().ReadOneByIdForUpdate(gomock.Any(), userId).Times(1).Return(account, nil),
+				)
+			},
+		},
+		"deve retornar erro: transferindo para o proprio user": {
+			InputRequest: dto.CloseUser{Reason: "customer request", SweepToUserId: userId},
+			ExpectedErr:  echo.NewHTTPError(echo.ErrBadRequest.Code, "The balance must be swept to another account"),
+			PrepareMock: func(db *databaseMocks) {
+				db.Account.EXPECT().ReadAllByUserId(gomock.Any(), userId).Times(1).Return(accounts, nil)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			container, db := newDatabaseContainer(ctrl)
+			cs.PrepareMock(db)
+
+			app := NewAppUser(container)
+
+			user, err := app.Close(ctx, userId, cs.InputRequest)
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+
+			if cs.ExpectedStatus != "" && (user == nil || user.StatusString != cs.ExpectedStatus) {
+				t.Errorf("unexpected user %+v", user)
+			}
+		})
+	}
+}
+
+func TestReadStatusHistory(t *testing.T) {
+	userId := uuid.NewId()
+	changes := []entity.StatusChange{{ID: 1, UserId: userId, From: entity.ACTIVE, To: entity.FROZEN, Reason: "suspected account takeover"}}
+
+	cases := map[string]struct {
+		ExpectedResult []entity.StatusChange
+		ExpectedErr    error
+		PrepareMock    func(mockUserDb *mocks.MockDabataseUserInterface)
+	}{
+		"deve retornar sucesso": {
+			ExpectedResult: []entity.StatusChange{{ID: 1, UserId: userId, From: entity.ACTIVE, FromString: "ACTIVE", To: entity.FROZEN, ToString: "FROZEN", Reason: "suspected account takeover"}},
+			PrepareMock: func(mockUserDb *mocks.MockDabataseUserInterface) {
+				gomock.InOrder(
+					mockUserDb.EXPECT().ReadOneById(gomock.Any(), userId).Times(1).Return(&entity.User{ID: userId}, nil),
+					mockUserDb.EXPECT().ReadStatusHistory(gomock.Any(), userId).Times(1).Return(changes, nil),
+				)
+			},
+		},
+		"deve retornar erro: usuario nao encontrado": {
+			ExpectedErr: echo.ErrNotFound,
+			PrepareMock: func(mockUserDb *mocks.MockDabataseUserInterface) {
+				mockUserDb.EXPECT().ReadOneById(gomock.Any(), userId).Times(1).Return(nil, echo.ErrNotFound)
+			},
+		},
+		"deve retornar erro": {
+			ExpectedErr: echo.ErrInternalServerError,
+			PrepareMock: func(mockUserDb *mocks.MockDabataseUserInterface) {
+				gomock.InOrder(
+					mockUserDb.EXPECT().ReadOneById(gomock.Any(), userId).Times(1).Return(&entity.User{ID: userId}, nil),
+					mockUserDb.EXPECT().ReadStatusHistory(gomock.Any(), userId).Times(1).Return(nil, echo.ErrInternalServerError),
+				)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			mockUserDb := mocks.NewMockDabataseUserInterface(ctrl)
+			cs.PrepareMock(mockUserDb)
+
+			app := NewAppUser(&database.Container{User: mockUserDb})
+
+			changes, err := app.ReadStatusHistory(ctx, userId)
+			if diff := cmp.Diff(changes, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,379 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/garoque/backend-code-challenge-snapfi/internal/app/audit"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/database"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	"github.com/labstack/echo/v4"
+)
+
+// The headers every delivery is sent with. The signature is prefixed with its
+// version, so the scheme can change without breaking receivers.
+const (
+	HeaderEvent     = "X-Snapfi-Event"
+	HeaderDelivery  = "X-Snapfi-Delivery"
+	HeaderTimestamp = "X-Snapfi-Timestamp"
+	HeaderSignature = "X-Snapfi-Signature"
+)
+
+const (
+	// DeliveryBatchSize is how many due deliveries are claimed at a time.
+	DeliveryBatchSize = 20
+	// DeliveryLease is how long a claimed delivery is left to the worker that
+	// claimed it before another one may attempt it again.
+	DeliveryLease = 10 * time.Minute
+)
+
+// RetryPolicy says how many times a delivery is attempted before it is dead,
+// and how long apart. The delay doubles after every failed attempt, from
+// BaseDelay up to MaxDelay.
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+var DefaultRetryPolicy = RetryPolicy{MaxAttempts: 10, BaseDelay: 30 * time.Second, MaxDelay: 6 * time.Hour}
+
+// next returns when to attempt again after the failed attempts, or nil when
+// none is left.
+func (p RetryPolicy) next(attempts int, now time.Time) *time.Time {
+	if attempts >= p.MaxAttempts {
+		return nil
+	}
+
+	delay := p.BaseDelay
+	for i := 1; i < attempts && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+
+	if delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+
+	at := now.Add(delay)
+	return &at
+}
+
+type AppWebhookInterface interface {
+	Create(ctx context.Context, subscription *entity.WebhookSubscription) (*entity.WebhookSubscription, error)
+	ReadAll(ctx context.Context) ([]entity.WebhookSubscription, error)
+	ReadOneById(ctx context.Context, id string) (*entity.WebhookSubscription, error)
+	Delete(ctx context.Context, id string) (*entity.WebhookSubscription, error)
+	ReadDeliveries(ctx context.Context, id string) ([]entity.WebhookDelivery, error)
+	Redeliver(ctx context.Context, id, deliveryId string) (*entity.WebhookDelivery, error)
+	Enqueue(ctx context.Context, event entity.Event) error
+	Deliver(ctx context.Context) error
+}
+
+type appWebhookImpl struct {
+	db     *database.Container
+	client *http.Client
+	retry  RetryPolicy
+}
+
+func NewAppWebhook(db *database.Container, client *http.Client, retry RetryPolicy) AppWebhookInterface {
+	return &appWebhookImpl{db, client, retry}
+}
+
+func (w *appWebhookImpl) Create(ctx context.Context, subscription *entity.WebhookSubscription) (*entity.WebhookSubscription, error) {
+	err := w.db.UnitOfWork.Do(ctx, func(tx *database.Container) error {
+		err := tx.Webhook.CreateSubscription(ctx, subscription)
+		if err != nil {
+			return err
+		}
+
+		created := *subscription
+		return audit.Record(ctx, tx, entity.AUDIT_WEBHOOK_CREATED, entity.AUDIT_WEBHOOK, subscription.ID, nil, withoutSecret(&created))
+	})
+	if err != nil {
+		log.Println("Error app.Webhook.Create.db.CreateSubscription: ", err.Error())
+		return nil, err
+	}
+
+	return subscription, nil
+}
+
+func (w *appWebhookImpl) ReadAll(ctx context.Context) ([]entity.WebhookSubscription, error) {
+	subscriptions, err := w.db.Webhook.ReadSubscriptions(ctx)
+	if err != nil {
+		log.Println("Error app.Webhook.ReadAll.db.ReadSubscriptions: ", err.Error())
+		return nil, err
+	}
+
+	for i := range subscriptions {
+		withoutSecret(&subscriptions[i])
+	}
+
+	return subscriptions, nil
+}
+
+func (w *appWebhookImpl) ReadOneById(ctx context.Context, id string) (*entity.WebhookSubscription, error) {
+	subscription, err := w.db.Webhook.ReadSubscriptionById(ctx, id)
+	if err != nil {
+		log.Println("Error app.Webhook.ReadOneById.db.ReadSubscriptionById: ", err.Error())
+		return nil, err
+	}
+
+	return withoutSecret(subscription), nil
+}
+
+// Delete stops sending events to the subscription. It is kept, with its
+// deliveries, so the delivery log can still be read.
+func (w *appWebhookImpl) Delete(ctx context.Context, id string) (*entity.WebhookSubscription, error) {
+	var subscription *entity.WebhookSubscription
+	err := w.db.UnitOfWork.Do(ctx, func(tx *database.Container) error {
+		var err error
+		subscription, err = tx.Webhook.ReadSubscriptionById(ctx, id)
+		if err != nil {
+			log.Println("Error app.Webhook.Delete.db.ReadSubscriptionById: ", err.Error())
+			return err
+		}
+
+		err = tx.Webhook.DeactivateSubscription(ctx, id)
+		if err != nil {
+			log.Println("Error app.Webhook.Delete.db.DeactivateSubscription: ", err.Error())
+			return err
+		}
+
+		before := *withoutSecret(subscription)
+		subscription.Active = false
+
+		err = audit.Record(ctx, tx, entity.AUDIT_WEBHOOK_DELETED, entity.AUDIT_WEBHOOK, id, before, subscription)
+		if err != nil {
+			log.Println("Error app.Webhook.Delete.audit.Record: ", err.Error())
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return subscription, nil
+}
+
+func (w *appWebhookImpl) ReadDeliveries(ctx context.Context, id string) ([]entity.WebhookDelivery, error) {
+	_, err := w.db.Webhook.ReadSubscriptionById(ctx, id)
+	if err != nil {
+		log.Println("Error app.Webhook.ReadDeliveries.db.ReadSubscriptionById: ", err.Error())
+		return nil, err
+	}
+
+	deliveries, err := w.db.Webhook.ReadDeliveries(ctx, id)
+	if err != nil {
+		log.Println("Error app.Webhook.ReadDeliveries.db.ReadDeliveries: ", err.Error())
+		return nil, err
+	}
+
+	for i := range deliveries {
+		withStrings(&deliveries[i])
+	}
+
+	return deliveries, nil
+}
+
+// Redeliver sends a delivered or dead delivery again, with every attempt
+// anew, on the next run of Deliver.
+func (w *appWebhookImpl) Redeliver(ctx context.Context, id, deliveryId string) (*entity.WebhookDelivery, error) {
+	subscription, err := w.db.Webhook.ReadSubscriptionById(ctx, id)
+	if err != nil {
+		log.Println("Error app.Webhook.Redeliver.db.ReadSubscriptionById: ", err.Error())
+		return nil, err
+	}
+
+	if !subscription.Active {
+		return nil, echo.NewHTTPError(http.StatusConflict, "The webhook subscription is not active")
+	}
+
+	delivery, err := w.db.Webhook.ReadDeliveryById(ctx, deliveryId)
+	if err != nil {
+		log.Println("Error app.Webhook.Redeliver.db.ReadDeliveryById: ", err.Error())
+		return nil, err
+	}
+
+	if delivery.SubscriptionId != id {
+		return nil, echo.ErrNotFound
+	}
+
+	if delivery.State == entity.DELIVERY_PENDING {
+		return nil, echo.NewHTTPError(http.StatusConflict, "The delivery is still pending")
+	}
+
+	before := *withStrings(delivery)
+	delivery.Redeliver(time.Now())
+
+	err = w.db.UnitOfWork.Do(ctx, func(tx *database.Container) error {
+		err := tx.Webhook.UpdateDelivery(ctx, delivery)
+		if err != nil {
+			return err
+		}
+
+		return audit.Record(ctx, tx, entity.AUDIT_WEBHOOK_REDELIVERED, entity.AUDIT_WEBHOOK, id, before, withStrings(delivery))
+	})
+	if err != nil {
+		log.Println("Error app.Webhook.Redeliver.db.UpdateDelivery: ", err.Error())
+		return nil, err
+	}
+
+	return delivery, nil
+}
+
+// Enqueue records a delivery of the event to every active subscription to
+// it. It is subscribed to the events relayed from the outbox, which may relay
+// an event again, so a delivery already recorded is left as it is.
+func (w *appWebhookImpl) Enqueue(ctx context.Context, event entity.Event) error {
+	now := time.Now()
+
+	for _, eventType := range entity.WebhookTypes(event) {
+		subscriptions, err := w.db.Webhook.ReadSubscriptionsByEventType(ctx, eventType)
+		if err != nil {
+			log.Println("Error app.Webhook.Enqueue.db.ReadSubscriptionsByEventType: ", err.Error())
+			return err
+		}
+
+		for _, subscription := range subscriptions {
+			delivery, err := entity.NewWebhookDelivery(subscription, event, eventType, now)
+			if err != nil {
+				log.Println("Error app.Webhook.Enqueue.NewWebhookDelivery: ", err.Error())
+				return err
+			}
+
+			err = w.db.Webhook.CreateDelivery(ctx, delivery)
+			if err != nil {
+				log.Println("Error app.Webhook.Enqueue.db.CreateDelivery: ", err.Error())
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Deliver attempts the due deliveries until none is left. A batch is claimed
+// by pushing its next attempt DeliveryLease ahead, in a transaction of its
+// own, so the requests are sent without holding locks and a worker that
+// stops halfway leaves its deliveries to be attempted again after the lease.
+func (w *appWebhookImpl) Deliver(ctx context.Context) error {
+	for {
+		var deliveries []entity.WebhookDelivery
+		err := w.db.UnitOfWork.Do(ctx, func(tx *database.Container) error {
+			now := time.Now()
+
+			var err error
+			deliveries, err = tx.Webhook.ReadDueDeliveries(ctx, now, DeliveryBatchSize)
+			if err != nil {
+				log.Println("Error app.Webhook.Deliver.db.ReadDueDeliveries: ", err.Error())
+				return err
+			}
+
+			if len(deliveries) == 0 {
+				return nil
+			}
+
+			ids := make([]string, 0, len(deliveries))
+			for _, delivery := range deliveries {
+				ids = append(ids, delivery.ID)
+			}
+
+			err = tx.Webhook.UpdateNextAttemptAt(ctx, ids, now.Add(DeliveryLease))
+			if err != nil {
+				log.Println("Error app.Webhook.Deliver.db.UpdateNextAttemptAt: ", err.Error())
+				return err
+			}
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for i := range deliveries {
+			err := w.attempt(ctx, &deliveries[i])
+			if err != nil {
+				log.Println("Error app.Webhook.Deliver.attempt: ", deliveries[i].ID, err.Error())
+			}
+		}
+
+		if len(deliveries) < DeliveryBatchSize {
+			return nil
+		}
+	}
+}
+
+// attempt sends the delivery and records the outcome. Deliveries of a
+// subscription deleted since they were recorded are dead.
+func (w *appWebhookImpl) attempt(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	subscription, err := w.db.Webhook.ReadSubscriptionById(ctx, delivery.SubscriptionId)
+	if err != nil {
+		return err
+	}
+
+	if !subscription.Active {
+		delivery.Fail(nil, "The webhook subscription is not active", nil)
+	} else {
+		statusCode, err := w.send(ctx, subscription, delivery)
+		now := time.Now()
+		if err != nil {
+			delivery.Fail(statusCode, err.Error(), w.retry.next(delivery.Attempts+1, now))
+		} else {
+			delivery.Succeed(*statusCode, now)
+		}
+	}
+
+	return w.db.Webhook.UpdateDelivery(ctx, delivery)
+}
+
+// send posts the payload of the delivery to the subscription, signed with its
+// secret. Any 2xx response means it was received, and the status code is
+// returned whenever there was a response.
+func (w *appWebhookImpl) send(ctx context.Context, subscription *entity.WebhookSubscription, delivery *entity.WebhookDelivery) (*int, error) {
+	timestamp := time.Now().Unix()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return nil, err
+	}
+
+	request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	request.Header.Set(HeaderEvent, delivery.EventType)
+	request.Header.Set(HeaderDelivery, delivery.ID)
+	request.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	request.Header.Set(HeaderSignature, "v1="+entity.SignWebhook(subscription.Secret, timestamp, delivery.Payload))
+
+	response, err := w.client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(io.Discard, response.Body)
+
+	statusCode := response.StatusCode
+	if statusCode < 200 || statusCode > 299 {
+		return &statusCode, fmt.Errorf("unexpected status %d", statusCode)
+	}
+
+	return &statusCode, nil
+}
+
+func withoutSecret(subscription *entity.WebhookSubscription) *entity.WebhookSubscription {
+	subscription.Secret = ""
+
+	return subscription
+}
+
+func withStrings(delivery *entity.WebhookDelivery) *entity.WebhookDelivery {
+	delivery.StateString = delivery.State.String()
+
+	return delivery
+}
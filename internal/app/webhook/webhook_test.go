@@ -0,0 +1,461 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/garoque/backend-code-challenge-snapfi/internal/database"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func newSubscription(url string) *entity.WebhookSubscription {
+	return &entity.WebhookSubscription{
+		ID:         "subscription-id",
+		URL:        url,
+		EventTypes: entity.WebhookEventTypes{entity.WEBHOOK_TRANSACTION_BOOKED},
+		Secret:     "whsec_test",
+		Active:     true,
+	}
+}
+
+func newDelivery(state entity.StatesWebhookDelivery, attempts int) *entity.WebhookDelivery {
+	return &entity.WebhookDelivery{
+		ID:             "delivery-id",
+		SubscriptionId: "subscription-id",
+		EventId:        "event-id",
+		EventType:      entity.WEBHOOK_TRANSACTION_BOOKED,
+		Payload:        json.RawMessage(`{"id":"event-id"}`),
+		State:          state,
+		Attempts:       attempts,
+	}
+}
+
+func TestRetryPolicyNext(t *testing.T) {
+	now := time.Date(2023, 5, 21, 9, 0, 0, 0, time.UTC)
+	policy := RetryPolicy{MaxAttempts: 5, BaseDelay: time.Minute, MaxDelay: 5 * time.Minute}
+
+	assert.Equal(t, now.Add(time.Minute), *policy.next(1, now))
+	assert.Equal(t, now.Add(2*time.Minute), *policy.next(2, now))
+	assert.Equal(t, now.Add(4*time.Minute), *policy.next(3, now))
+	assert.Equal(t, now.Add(5*time.Minute), *policy.next(4, now))
+	assert.Nil(t, policy.next(5, now))
+}
+
+func TestReadOneById(t *testing.T) {
+	cases := map[string]struct {
+		ExpectedResult *entity.WebhookSubscription
+		ExpectedErr    error
+		PrepareMock    func(mockWebhookDb *mocks.MockDabataseWebhookInterface)
+	}{
+		"deve retornar sucesso": {
+			ExpectedResult: withoutSecret(newSubscription("https://partner.example.com")),
+			ExpectedErr:    nil,
+			PrepareMock: func(mockWebhookDb *mocks.MockDabataseWebhookInterface) {
+				mockWebhookDb.EXPECT().ReadSubscriptionById(gomock.Any(), "subscription-id").Times(1).Return(newSubscription("https://partner.example.com"), nil)
+			},
+		},
+		"deve retornar erro": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrNotFound,
+			PrepareMock: func(mockWebhookDb *mocks.MockDabataseWebhookInterface) {
+				mockWebhookDb.EXPECT().ReadSubscriptionById(gomock.Any(), "subscription-id").Times(1).Return(nil, echo.ErrNotFound)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			mockWebhookDb := mocks.NewMockDabataseWebhookInterface(ctrl)
+			cs.PrepareMock(mockWebhookDb)
+
+			app := NewAppWebhook(&database.Container{Webhook: mockWebhookDb}, http.DefaultClient, DefaultRetryPolicy)
+
+			subscription, err := app.ReadOneById(ctx, "subscription-id")
+			if diff := cmp.Diff(subscription, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	deleted := withoutSecret(newSubscription("https://partner.example.com"))
+	deleted.Active = false
+
+	cases := map[string]struct {
+		ExpectedResult *entity.WebhookSubscription
+		ExpectedErr    error
+		PrepareMock    func(mockWebhookDb *mocks.MockDabataseWebhookInterface, mockAuditDb *mocks.MockDabataseAuditInterface)
+	}{
+		"deve retornar sucesso": {
+			ExpectedResult: deleted,
+			ExpectedErr:    nil,
+			PrepareMock: func(mockWebhookDb *mocks.MockDabataseWebhookInterface, mockAuditDb *mocks.MockDabataseAuditInterface) {
+				gomock.InOrder(
+					mockWebhookDb.EXPECT().ReadSubscriptionById(gomock.Any(), "subscription-id").Times(1).Return(newSubscription("https://partner.example.com"), nil),
+					mockWebhookDb.EXPECT().DeactivateSubscription(gomock.Any(), "subscription-id").Times(1).Return(nil),
+					mockAuditDb.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).
+						DoAndReturn(func(ctx context.Context, entry *entity.AuditEntry) error {
+							assert.Equal(t, entity.AUDIT_WEBHOOK_DELETED, entry.Operation)
+							assert.NotContains(t, string(*entry.Before), "whsec_")
+							assert.Contains(t, string(*entry.After), `"active":false`)
+							return nil
+						}),
+				)
+			},
+		},
+		"deve retornar erro: assinatura nao encontrada": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrNotFound,
+			PrepareMock: func(mockWebhookDb *mocks.MockDabataseWebhookInterface, mockAuditDb *mocks.MockDabataseAuditInterface) {
+				mockWebhookDb.EXPECT().ReadSubscriptionById(gomock.Any(), "subscription-id").Times(1).Return(nil, echo.ErrNotFound)
+			},
+		},
+		"deve retornar erro": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrInternalServerError,
+			PrepareMock: func(mockWebhookDb *mocks.MockDabataseWebhookInterface, mockAuditDb *mocks.MockDabataseAuditInterface) {
+				gomock.InOrder(
+					mockWebhookDb.EXPECT().ReadSubscriptionById(gomock.Any(), "subscription-id").Times(1).Return(newSubscription("https://partner.example.com"), nil),
+					mockWebhookDb.EXPECT().DeactivateSubscription(gomock.Any(), "subscription-id").Times(1).Return(echo.ErrInternalServerError),
+				)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			mockWebhookDb := mocks.NewMockDabataseWebhookInterface(ctrl)
+			mockAuditDb := mocks.NewMockDabataseAuditInterface(ctrl)
+			cs.PrepareMock(mockWebhookDb, mockAuditDb)
+
+			mockUnitOfWork := mocks.NewMockUnitOfWorkInterface(ctrl)
+			container := &database.Container{Webhook: mockWebhookDb, Audit: mockAuditDb, UnitOfWork: mockUnitOfWork}
+			mockUnitOfWork.EXPECT().Do(gomock.Any(), gomock.Any()).AnyTimes().
+				DoAndReturn(func(ctx context.Context, fn func(tx *database.Container) error) error {
+					return fn(container)
+				})
+
+			app := NewAppWebhook(container, http.DefaultClient, DefaultRetryPolicy)
+
+			subscription, err := app.Delete(ctx, "subscription-id")
+			if diff := cmp.Diff(subscription, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestRedeliver(t *testing.T) {
+	inactive := newSubscription("https://partner.example.com")
+	inactive.Active = false
+
+	otherDelivery := newDelivery(entity.DELIVERY_DEAD, 10)
+	otherDelivery.SubscriptionId = "other-subscription-id"
+
+	cases := map[string]struct {
+		ExpectedState string
+		ExpectedErr   error
+		PrepareMock   func(mockWebhookDb *mocks.MockDabataseWebhookInterface, mockAuditDb *mocks.MockDabataseAuditInterface)
+	}{
+		"deve retornar sucesso": {
+			ExpectedState: "PENDING",
+			ExpectedErr:   nil,
+			PrepareMock: func(mockWebhookDb *mocks.MockDabataseWebhookInterface, mockAuditDb *mocks.MockDabataseAuditInterface) {
+				gomock.InOrder(
+					mockWebhookDb.EXPECT().ReadSubscriptionById(gomock.Any(), "subscription-id").Times(1).Return(newSubscription("https://partner.example.com"), nil),
+					mockWebhookDb.EXPECT().ReadDeliveryById(gomock.Any(), "delivery-id").Times(1).Return(newDelivery(entity.DELIVERY_DEAD, 10), nil),
+					mockWebhookDb.EXPECT().UpdateDelivery(gomock.Any(), gomock.Any()).Times(1).
+						DoAndReturn(func(ctx context.Context, delivery *entity.WebhookDelivery) error {
+							assert.Equal(t, entity.DELIVERY_PENDING, delivery.State)
+							assert.Equal(t, 0, delivery.Attempts)
+							assert.NotNil(t, delivery.NextAttemptAt)
+							return nil
+						}),
+					mockAuditDb.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).
+						DoAndReturn(func(ctx context.Context, entry *entity.AuditEntry) error {
+							assert.Equal(t, entity.AUDIT_WEBHOOK_REDELIVERED, entry.Operation)
+							assert.Equal(t, "subscription-id", entry.EntityId)
+							return nil
+						}),
+				)
+			},
+		},
+		"deve retornar erro: assinatura inativa": {
+			ExpectedErr: echo.NewHTTPError(http.StatusConflict, "The webhook subscription is not active"),
+			PrepareMock: func(mockWebhookDb *mocks.MockDabataseWebhookInterface, mockAuditDb *mocks.MockDabataseAuditInterface) {
+				mockWebhookDb.EXPECT().ReadSubscriptionById(gomock.Any(), "subscription-id").Times(1).Return(inactive, nil)
+			},
+		},
+		"deve retornar erro: entrega de outra assinatura": {
+			ExpectedErr: echo.ErrNotFound,
+			PrepareMock: func(mockWebhookDb *mocks.MockDabataseWebhookInterface, mockAuditDb *mocks.MockDabataseAuditInterface) {
+				gomock.InOrder(
+					mockWebhookDb.EXPECT().ReadSubscriptionById(gomock.Any(), "subscription-id").Times(1).Return(newSubscription("https://partner.example.com"), nil),
+					mockWebhookDb.EXPECT().ReadDeliveryById(gomock.Any(), "delivery-id").Times(1).Return(otherDelivery, nil),
+				)
+			},
+		},
+		"deve retornar erro: entrega pendente": {
+			ExpectedErr: echo.NewHTTPError(http.StatusConflict, "The delivery is still pending"),
+			PrepareMock: func(mockWebhookDb *mocks.MockDabataseWebhookInterface, mockAuditDb *mocks.MockDabataseAuditInterface) {
+				gomock.InOrder(
+					mockWebhookDb.EXPECT().ReadSubscriptionById(gomock.Any(), "subscription-id").Times(1).Return(newSubscription("https://partner.example.com"), nil),
+					mockWebhookDb.EXPECT().ReadDeliveryById(gomock.Any(), "delivery-id").Times(1).Return(newDelivery(entity.DELIVERY_PENDING, 2), nil),
+				)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			mockWebhookDb := mocks.NewMockDabataseWebhookInterface(ctrl)
+			mockAuditDb := mocks.NewMockDabataseAuditInterface(ctrl)
+			cs.PrepareMock(mockWebhookDb, mockAuditDb)
+
+			mockUnitOfWork := mocks.NewMockUnitOfWorkInterface(ctrl)
+			container := &database.Container{Webhook: mockWebhookDb, Audit: mockAuditDb, UnitOfWork: mockUnitOfWork}
+			mockUnitOfWork.EXPECT().Do(gomock.Any(), gomock.Any()).AnyTimes().
+				DoAndReturn(func(ctx context.Context, fn func(tx *database.Container) error) error {
+					return fn(container)
+				})
+
+			app := NewAppWebhook(container, http.DefaultClient, DefaultRetryPolicy)
+
+			delivery, err := app.Redeliver(ctx, "subscription-id", "delivery-id")
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+
+			if delivery != nil {
+				assert.Equal(t, cs.ExpectedState, delivery.StateString)
+			}
+		})
+	}
+}
+
+func TestEnqueue(t *testing.T) {
+	deposit, _ := json.Marshal(entity.TransactionEventPayload{Transaction: entity.Transaction{KindString: "DEPOSIT"}})
+	event := entity.Event{ID: "event-id", Type: entity.WEBHOOK_TRANSACTION_BOOKED, Payload: deposit}
+
+	cases := map[string]struct {
+		Event       entity.Event
+		ExpectedErr error
+		PrepareMock func(mockWebhookDb *mocks.MockDabataseWebhookInterface)
+	}{
+		"deve retornar sucesso": {
+			Event:       event,
+			ExpectedErr: nil,
+			PrepareMock: func(mockWebhookDb *mocks.MockDabataseWebhookInterface) {
+				deposits := newSubscription("https://deposits.example.com")
+				deposits.ID = "deposits-subscription-id"
+
+				gomock.InOrder(
+					mockWebhookDb.EXPECT().ReadSubscriptionsByEventType(gomock.Any(), "transaction.booked").Times(1).Return([]entity.WebhookSubscription{*newSubscription("https://partner.example.com")}, nil),
+					mockWebhookDb.EXPECT().CreateDelivery(gomock.Any(), gomock.Any()).Times(1).
+						DoAndReturn(func(ctx context.Context, delivery *entity.WebhookDelivery) error {
+							assert.Equal(t, "subscription-id", delivery.SubscriptionId)
+							assert.Equal(t, "transaction.booked", delivery.EventType)
+							return nil
+						}),
+					mockWebhookDb.EXPECT().ReadSubscriptionsByEventType(gomock.Any(), "deposit.booked").Times(1).Return([]entity.WebhookSubscription{*deposits}, nil),
+					mockWebhookDb.EXPECT().CreateDelivery(gomock.Any(), gomock.Any()).Times(1).
+						DoAndReturn(func(ctx context.Context, delivery *entity.WebhookDelivery) error {
+							assert.Equal(t, "deposits-subscription-id", delivery.SubscriptionId)
+							assert.Equal(t, "deposit.booked", delivery.EventType)
+							return nil
+						}),
+				)
+			},
+		},
+		"deve retornar sucesso: evento sem webhook": {
+			Event:       entity.Event{ID: "event-id", Type: entity.EVENT_USER_CREATED},
+			ExpectedErr: nil,
+			PrepareMock: func(mockWebhookDb *mocks.MockDabataseWebhookInterface) {},
+		},
+		"deve retornar erro": {
+			Event:       event,
+			ExpectedErr: echo.ErrInternalServerError,
+			PrepareMock: func(mockWebhookDb *mocks.MockDabataseWebhookInterface) {
+				gomock.InOrder(
+					mockWebhookDb.EXPECT().ReadSubscriptionsByEventType(gomock.Any(), "transaction.booked").Times(1).Return([]entity.WebhookSubscription{*newSubscription("https://partner.example.com")}, nil),
+					mockWebhookDb.EXPECT().CreateDelivery(gomock.Any(), gomock.Any()).Times(1).Return(echo.ErrInternalServerError),
+				)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+			mockWebhookDb := mocks.NewMockDabataseWebhookInterface(ctrl)
+			cs.PrepareMock(mockWebhookDb)
+
+			app := NewAppWebhook(&database.Container{Webhook: mockWebhookDb}, http.DefaultClient, DefaultRetryPolicy)
+
+			err := app.Enqueue(ctx, cs.Event)
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestDeliver(t *testing.T) {
+	var received *http.Request
+	var receivedBody []byte
+	statusCode := http.StatusNoContent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		receivedBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(statusCode)
+	}))
+	defer server.Close()
+
+	inactive := newSubscription(server.URL)
+	inactive.Active = false
+
+	cases := map[string]struct {
+		StatusCode        int
+		Subscription      *entity.WebhookSubscription
+		Delivery          *entity.WebhookDelivery
+		ExpectedSent      bool
+		ExpectedDelivery  *entity.WebhookDelivery
+		ExpectedRetryWait time.Duration
+	}{
+		"deve retornar sucesso": {
+			StatusCode:   http.StatusNoContent,
+			Subscription: newSubscription(server.URL),
+			Delivery:     newDelivery(entity.DELIVERY_PENDING, 0),
+			ExpectedSent: true,
+			ExpectedDelivery: func() *entity.WebhookDelivery {
+				delivery := newDelivery(entity.DELIVERY_DELIVERED, 1)
+				code := http.StatusNoContent
+				delivery.LastStatusCode = &code
+				return delivery
+			}(),
+		},
+		"deve retornar sucesso: nova tentativa": {
+			StatusCode:   http.StatusInternalServerError,
+			Subscription: newSubscription(server.URL),
+			Delivery:     newDelivery(entity.DELIVERY_PENDING, 2),
+			ExpectedSent: true,
+			ExpectedDelivery: func() *entity.WebhookDelivery {
+				delivery := newDelivery(entity.DELIVERY_PENDING, 3)
+				code := http.StatusInternalServerError
+				cause := "unexpected status 500"
+				delivery.LastStatusCode = &code
+				delivery.LastError = &cause
+				return delivery
+			}(),
+			ExpectedRetryWait: 4 * DefaultRetryPolicy.BaseDelay,
+		},
+		"deve retornar sucesso: tentativas esgotadas": {
+			StatusCode:   http.StatusInternalServerError,
+			Subscription: newSubscription(server.URL),
+			Delivery:     newDelivery(entity.DELIVERY_PENDING, DefaultRetryPolicy.MaxAttempts-1),
+			ExpectedSent: true,
+			ExpectedDelivery: func() *entity.WebhookDelivery {
+				delivery := newDelivery(entity.DELIVERY_DEAD, DefaultRetryPolicy.MaxAttempts)
+				code := http.StatusInternalServerError
+				cause := "unexpected status 500"
+				delivery.LastStatusCode = &code
+				delivery.LastError = &cause
+				return delivery
+			}(),
+		},
+		"deve retornar sucesso: assinatura inativa": {
+			Subscription: inactive,
+			Delivery:     newDelivery(entity.DELIVERY_PENDING, 0),
+			ExpectedSent: false,
+			ExpectedDelivery: func() *entity.WebhookDelivery {
+				delivery := newDelivery(entity.DELIVERY_DEAD, 1)
+				cause := "The webhook subscription is not active"
+				delivery.LastError = &cause
+				return delivery
+			}(),
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctrl, ctx := gomock.WithContext(context.Background(), t)
+			received, receivedBody, statusCode = nil, nil, cs.StatusCode
+
+			mockWebhookDb := mocks.NewMockDabataseWebhookInterface(ctrl)
+			mockUnitOfWork := mocks.NewMockUnitOfWorkInterface(ctrl)
+			container := &database.Container{Webhook: mockWebhookDb, UnitOfWork: mockUnitOfWork}
+			mockUnitOfWork.EXPECT().Do(gomock.Any(), gomock.Any()).AnyTimes().
+				DoAndReturn(func(ctx context.Context, fn func(tx *database.Container) error) error {
+					return fn(container)
+				})
+
+			var updated *entity.WebhookDelivery
+			gomock.InOrder(
+				mockWebhookDb.EXPECT().ReadDueDeliveries(gomock.Any(), gomock.Any(), DeliveryBatchSize).Times(1).Return([]entity.WebhookDelivery{*cs.Delivery}, nil),
+				mockWebhookDb.EXPECT().UpdateNextAttemptAt(gomock.Any(), []string{"delivery-id"}, gomock.Any()).Times(1).Return(nil),
+				mockWebhookDb.EXPECT().ReadSubscriptionById(gomock.Any(), "subscription-id").Times(1).Return(cs.Subscription, nil),
+				mockWebhookDb.EXPECT().UpdateDelivery(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(ctx context.Context, delivery *entity.WebhookDelivery) error {
+						updated = delivery
+						return nil
+					}),
+			)
+
+			app := NewAppWebhook(container, server.Client(), DefaultRetryPolicy)
+
+			before := time.Now()
+			err := app.Deliver(ctx)
+			assert.NoError(t, err)
+
+			if diff := cmp.Diff(updated, cs.ExpectedDelivery, cmpopts.IgnoreFields(entity.WebhookDelivery{}, "NextAttemptAt", "DeliveredAt")); diff != "" {
+				t.Error(diff)
+			}
+
+			if cs.ExpectedRetryWait > 0 {
+				assert.WithinDuration(t, before.Add(cs.ExpectedRetryWait), *updated.NextAttemptAt, 5*time.Second)
+			} else {
+				assert.Nil(t, updated.NextAttemptAt)
+			}
+
+			if !cs.ExpectedSent {
+				assert.Nil(t, received)
+				return
+			}
+
+			timestamp, err := strconv.ParseInt(received.Header.Get(HeaderTimestamp), 10, 64)
+			assert.NoError(t, err)
+			assert.Equal(t, "v1="+entity.SignWebhook("whsec_test", timestamp, receivedBody), received.Header.Get(HeaderSignature))
+			assert.Equal(t, "transaction.booked", received.Header.Get(HeaderEvent))
+			assert.Equal(t, "delivery-id", received.Header.Get(HeaderDelivery))
+			assert.JSONEq(t, `{"id":"event-id"}`, string(receivedBody))
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package account
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/money"
+	"github.com/jmoiron/sqlx"
+	"github.com/labstack/echo/v4"
+)
+
+type DabataseAccountInterface interface {
+	Create(ctx context.Context, account entity.Account) error
+	ReadOneById(ctx context.Context, id string) (*entity.Account, error)
+	ReadOneByIdForUpdate(ctx context.Context, id string) (*entity.Account, error)
+	ReadAllByUserId(ctx context.Context, userId string) ([]entity.Account, error)
+	ReadOverdrawn(ctx context.Context, day time.Time) ([]entity.Account, error)
+	UpdateBalance(ctx context.Context, id string, value money.Money) error
+	UpdateHeldBalance(ctx context.Context, id string, value money.Money) error
+	UpdateCreditLine(ctx context.Context, id string, creditLimit money.Money, interestRate int64) error
+	UpdateInterestAccruedOn(ctx context.Context, id string, day time.Time) error
+}
+
+// The status of an account is the one of its owner.
+const selectQuery = "SELECT a.id, a.user_id, a.name, a.account_type, a.currency, u.status, a.balance, a.held_balance, a.balance - a.held_balance AS available_balance, " +
+	"a.credit_limit, a.interest_rate, a.interest_accrued_on, a.created_at, a.updated_at FROM accounts a JOIN users u ON u.id = a.user_id"
+
+type dbImpl struct {
+	dbConn sqlx.ExtContext
+}
+
+func NewDatabaseAccount(dbConn sqlx.ExtContext) DabataseAccountInterface {
+	return &dbImpl{dbConn}
+}
+
+func (a *dbImpl) Create(ctx context.Context, account entity.Account) error {
+	query := "INSERT INTO accounts (id, user_id, name, account_type, currency, balance) VALUES (?, ?, ?, ?, ?, ?)"
+
+	_, err := a.dbConn.ExecContext(ctx, query,
+		account.ID,
+		account.UserId,
+		account.Name,
+		account.AccountType,
+		account.Currency,
+		account.Balance,
+	)
+	if err != nil {
+		log.Println("Error create account: ", err.Error())
+		return echo.ErrInternalServerError
+	}
+
+	return nil
+}
+
+func (a *dbImpl) ReadOneById(ctx context.Context, id string) (*entity.Account, error) {
+	account := new(entity.Account)
+	query := selectQuery + " WHERE a.id = ?"
+
+	err := sqlx.GetContext(ctx, a.dbConn, account, query, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, echo.ErrNotFound
+	}
+
+	if err != nil {
+		log.Println("Error ReadOneById account: ", err.Error())
+		return nil, echo.ErrInternalServerError
+	}
+
+	return account, nil
+}
+
+// ReadOneByIdForUpdate locks the account row until the surrounding
+// transaction ends. Only the account is locked, not the row of its owner, so
+// callers locking accounts in ID order can't deadlock on the users behind
+// them; closing a user locks each of their accounts instead. It must be called
+// from a Container bound to a unit of work.
+func (a *dbImpl) ReadOneByIdForUpdate(ctx context.Context, id string) (*entity.Account, error) {
+	account := new(entity.Account)
+	query := selectQuery + " WHERE a.id = ? FOR UPDATE OF a"
+
+	err := sqlx.GetContext(ctx, a.dbConn, account, query, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, echo.ErrNotFound
+	}
+
+	if err != nil {
+		log.Println("Error ReadOneByIdForUpdate account: ", err.Error())
+		return nil, echo.ErrInternalServerError
+	}
+
+	return account, nil
+}
+
+// ReadAllByUserId lists the accounts of the user, the default one first.
+func (a *dbImpl) ReadAllByUserId(ctx context.Context, userId string) ([]entity.Account, error) {
+	accounts := make([]entity.Account, 0)
+	query := selectQuery + " WHERE a.user_id = ? ORDER BY a.id <> a.user_id, a.created_at, a.id"
+
+	err := sqlx.SelectContext(ctx, a.dbConn, &accounts, query, userId)
+	if err != nil {
+		log.Println("Error ReadAllByUserId account: ", err.Error())
+		return nil, echo.ErrInternalServerError
+	}
+
+	return accounts, nil
+}
+
+// ReadOverdrawn lists the accounts with a negative balance and an interest
+// rate which haven't been charged interest for the given day yet.
+func (a *dbImpl) ReadOverdrawn(ctx context.Context, day time.Time) ([]entity.Account, error) {
+	accounts := make([]entity.Account, 0)
+	query := selectQuery + " WHERE a.balance < 0 AND a.interest_rate > 0 AND (a.interest_accrued_on IS NULL OR a.interest_accrued_on < ?)"
+
+	err := sqlx.SelectContext(ctx, a.dbConn, &accounts, query, day)
+	if err != nil {
+		log.Println("Error ReadOverdrawn account: ", err.Error())
+		return nil, echo.ErrInternalServerError
+	}
+
+	return accounts, nil
+}
+
+func (a *dbImpl) UpdateBalance(ctx context.Context, id string, value money.Money) error {
+	query := "UPDATE accounts SET balance = ? WHERE id = ?"
+
+	_, err := a.dbConn.ExecContext(ctx, query, value, id)
+	if err != nil {
+		log.Println("Error update balance: ", err.Error())
+		return echo.ErrNotFound
+	}
+
+	return nil
+}
+
+func (a *dbImpl) UpdateHeldBalance(ctx context.Context, id string, value money.Money) error {
+	query := "UPDATE accounts SET held_balance = ? WHERE id = ?"
+
+	_, err := a.dbConn.ExecContext(ctx, query, value, id)
+	if err != nil {
+		log.Println("Error update held balance: ", err.Error())
+		return echo.ErrNotFound
+	}
+
+	return nil
+}
+
+func (a *dbImpl) UpdateCreditLine(ctx context.Context, id string, creditLimit money.Money, interestRate int64) error {
+	query := "UPDATE accounts SET credit_limit = ?, interest_rate = ? WHERE id = ?"
+
+	_, err := a.dbConn.ExecContext(ctx, query, creditLimit, interestRate, id)
+	if err != nil {
+		log.Println("Error update credit line: ", err.Error())
+		return echo.ErrInternalServerError
+	}
+
+	return nil
+}
+
+func (a *dbImpl) UpdateInterestAccruedOn(ctx context.Context, id string, day time.Time) error {
+	query := "UPDATE accounts SET interest_accrued_on = ? WHERE id = ?"
+
+	_, err := a.dbConn.ExecContext(ctx, query, day, id)
+	if err != nil {
+		log.Println("Error update interest accrued on: ", err.Error())
+		return echo.ErrInternalServerError
+	}
+
+	return nil
+}
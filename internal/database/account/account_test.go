@@ -0,0 +1,507 @@
+package account
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/api/dto"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/test"
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/money"
+	"github.com/google/go-cmp/cmp"
+	"github.com/labstack/echo/v4"
+)
+
+func TestCreate(t *testing.T) {
+	query := "INSERT INTO accounts (id, user_id, name, account_type, currency, balance) VALUES (?, ?, ?, ?, ?, ?)"
+
+	account := entity.NewAccount(dto.CreateAccount{UserId: "user-id", Name: "MEI", AccountType: "BUSINESS"})
+
+	cases := map[string]struct {
+		ExpectedErr error
+		PrepareMock func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			ExpectedErr: nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(query).
+					WithArgs(account.ID, "user-id", "MEI", entity.BUSINESS, "BRL", account.Balance).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+		},
+		"deve retornar erro: ao criar account": {
+			ExpectedErr: echo.ErrInternalServerError,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(query).
+					WithArgs(account.ID, "user-id", "MEI", entity.BUSINESS, "BRL", account.Balance).
+					WillReturnError(echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseAccount(dbConn)
+			ctx := context.Background()
+
+			err := db.Create(ctx, *account)
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestReadOneById(t *testing.T) {
+	query := "SELECT a.id, a.user_id, a.name, a.account_type, a.currency, u.status, a.balance, a.held_balance, a.balance - a.held_balance AS available_balance, a.credit_limit, a.interest_rate, a.interest_accrued_on, a.created_at, a.updated_at FROM accounts a JOIN users u ON u.id = a.user_id WHERE a.id = ?"
+
+	account := &entity.Account{
+		ID:               "account-id",
+		UserId:           "user-id",
+		Name:             "MEI",
+		AccountType:      entity.BUSINESS,
+		Currency:         "BRL",
+		Balance:          money.New(10000),
+		HeldBalance:      money.New(0),
+		AvailableBalance: money.New(10000),
+		CreditLimit:      money.New(0),
+	}
+
+	cases := map[string]struct {
+		ExpectedResult *entity.Account
+		ExpectedErr    error
+		PrepareMock    func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			ExpectedResult: account,
+			ExpectedErr:    nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs(account.ID).
+					WillReturnRows(
+						test.NewRows("id", "user_id", "name", "account_type", "currency", "status", "balance", "held_balance", "available_balance", "credit_limit", "interest_rate", "interest_accrued_on", "created_at", "updated_at").
+							AddRow(account.ID, account.UserId, account.Name, account.AccountType, account.Currency, entity.ACTIVE, 10000, 0, 10000, 0, 0, nil, account.CreatedAt, nil),
+					)
+			},
+		},
+		"deve retornar erro: conta nao encontrada": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrNotFound,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs(account.ID).
+					WillReturnError(sql.ErrNoRows)
+			},
+		},
+		"deve retornar erro": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrInternalServerError,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs(account.ID).
+					WillReturnError(echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseAccount(dbConn)
+			ctx := context.Background()
+
+			account, err := db.ReadOneById(ctx, "account-id")
+			if diff := cmp.Diff(account, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestReadOneByIdForUpdate(t *testing.T) {
+	query := "SELECT a.id, a.user_id, a.name, a.account_type, a.currency, u.status, a.balance, a.held_balance, a.balance - a.held_balance AS available_balance, a.credit_limit, a.interest_rate, a.interest_accrued_on, a.created_at, a.updated_at FROM accounts a JOIN users u ON u.id = a.user_id WHERE a.id = ? FOR UPDATE OF a"
+
+	account := &entity.Account{
+		ID:               "account-id",
+		UserId:           "user-id",
+		Name:             "MEI",
+		AccountType:      entity.BUSINESS,
+		Currency:         "BRL",
+		Balance:          money.New(10000),
+		HeldBalance:      money.New(0),
+		AvailableBalance: money.New(10000),
+		CreditLimit:      money.New(0),
+	}
+
+	cases := map[string]struct {
+		ExpectedResult *entity.Account
+		ExpectedErr    error
+		PrepareMock    func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			ExpectedResult: account,
+			ExpectedErr:    nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs(account.ID).
+					WillReturnRows(
+						test.NewRows("id", "user_id", "name", "account_type", "currency", "status", "balance", "held_balance", "available_balance", "credit_limit", "interest_rate", "interest_accrued_on", "created_at", "updated_at").
+							AddRow(account.ID, account.UserId, account.Name, account.AccountType, account.Currency, entity.ACTIVE, 10000, 0, 10000, 0, 0, nil, account.CreatedAt, nil),
+					)
+			},
+		},
+		"deve retornar erro: conta nao encontrada": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrNotFound,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs(account.ID).
+					WillReturnError(sql.ErrNoRows)
+			},
+		},
+		"deve retornar erro": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrInternalServerError,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs(account.ID).
+					WillReturnError(echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseAccount(dbConn)
+			ctx := context.Background()
+
+			account, err := db.ReadOneByIdForUpdate(ctx, "account-id")
+			if diff := cmp.Diff(account, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestReadAllByUserId(t *testing.T) {
+	query := "SELECT a.id, a.user_id, a.name, a.account_type, a.currency, u.status, a.balance, a.held_balance, a.balance - a.held_balance AS available_balance, a.credit_limit, a.interest_rate, a.interest_accrued_on, a.created_at, a.updated_at FROM accounts a JOIN users u ON u.id = a.user_id WHERE a.user_id = ? ORDER BY a.id <> a.user_id, a.created_at, a.id"
+
+	accounts := []entity.Account{
+		{ID: "user-id", UserId: "user-id", Currency: "BRL", Balance: money.New(10000), HeldBalance: money.New(0), AvailableBalance: money.New(10000), CreditLimit: money.New(0)},
+		{ID: "account-id", UserId: "user-id", Name: "MEI", AccountType: entity.BUSINESS, Currency: "BRL", Balance: money.New(0), HeldBalance: money.New(0), AvailableBalance: money.New(0), CreditLimit: money.New(0)},
+	}
+
+	cases := map[string]struct {
+		ExpectedResult []entity.Account
+		ExpectedErr    error
+		PrepareMock    func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			ExpectedResult: accounts,
+			ExpectedErr:    nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs("user-id").
+					WillReturnRows(
+						test.NewRows("id", "user_id", "name", "account_type", "currency", "status", "balance", "held_balance", "available_balance", "credit_limit", "interest_rate", "interest_accrued_on", "created_at", "updated_at").
+							AddRow("user-id", "user-id", "", entity.PERSONAL, "BRL", entity.ACTIVE, 10000, 0, 10000, 0, 0, nil, time.Time{}, nil).
+							AddRow("account-id", "user-id", "MEI", entity.BUSINESS, "BRL", entity.ACTIVE, 0, 0, 0, 0, 0, nil, time.Time{}, nil),
+					)
+			},
+		},
+		"deve retornar erro": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrInternalServerError,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs("account-id").
+					WillReturnError(echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseAccount(dbConn)
+			ctx := context.Background()
+
+			accounts, err := db.ReadAllByUserId(ctx, "user-id")
+			if diff := cmp.Diff(accounts, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestUpdateBalance(t *testing.T) {
+	query := "UPDATE accounts SET balance = ? WHERE id = ?"
+
+	value := money.New(10010)
+	accountId := "account-id"
+
+	cases := map[string]struct {
+		InputValue     money.Money
+		InputAccountId string
+		ExpectedErr    error
+		PrepareMock    func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			InputValue:     value,
+			InputAccountId: accountId,
+			ExpectedErr:    nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(query).
+					WithArgs(value, accountId).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+		},
+		"deve retornar erro: ao atualizar saldo": {
+			InputValue:     value,
+			InputAccountId: accountId,
+			ExpectedErr:    echo.ErrNotFound,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(query).
+					WithArgs(value, accountId).
+					WillReturnError(echo.ErrNotFound)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseAccount(dbConn)
+			ctx := context.Background()
+
+			err := db.UpdateBalance(ctx, cs.InputAccountId, cs.InputValue)
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestUpdateHeldBalance(t *testing.T) {
+	query := "UPDATE accounts SET held_balance = ? WHERE id = ?"
+
+	value := money.New(10010)
+	accountId := "account-id"
+
+	cases := map[string]struct {
+		InputValue     money.Money
+		InputAccountId string
+		ExpectedErr    error
+		PrepareMock    func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			InputValue:     value,
+			InputAccountId: accountId,
+			ExpectedErr:    nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(query).
+					WithArgs(value, accountId).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+		},
+		"deve retornar erro: ao atualizar saldo": {
+			InputValue:     value,
+			InputAccountId: accountId,
+			ExpectedErr:    echo.ErrNotFound,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(query).
+					WithArgs(value, accountId).
+					WillReturnError(echo.ErrNotFound)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseAccount(dbConn)
+			ctx := context.Background()
+
+			err := db.UpdateHeldBalance(ctx, cs.InputAccountId, cs.InputValue)
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestReadOverdrawn(t *testing.T) {
+	query := "SELECT a.id, a.user_id, a.name, a.account_type, a.currency, u.status, a.balance, a.held_balance, a.balance - a.held_balance AS available_balance, a.credit_limit, a.interest_rate, a.interest_accrued_on, a.created_at, a.updated_at FROM accounts a JOIN users u ON u.id = a.user_id WHERE a.balance < 0 AND a.interest_rate > 0 AND (a.interest_accrued_on IS NULL OR a.interest_accrued_on < ?)"
+
+	day := time.Date(2023, 5, 13, 0, 0, 0, 0, time.UTC)
+	account := entity.Account{
+		ID:               "user-id",
+		UserId:           "user-id",
+		Currency:         "BRL",
+		Balance:          money.New(-10000),
+		HeldBalance:      money.New(0),
+		AvailableBalance: money.New(-10000),
+		CreditLimit:      money.New(50000),
+		InterestRate:     800,
+	}
+
+	cases := map[string]struct {
+		ExpectedResult []entity.Account
+		ExpectedErr    error
+		PrepareMock    func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			ExpectedResult: []entity.Account{account},
+			ExpectedErr:    nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs(day).
+					WillReturnRows(
+						test.NewRows("id", "user_id", "name", "account_type", "currency", "status", "balance", "held_balance", "available_balance", "credit_limit", "interest_rate", "interest_accrued_on", "created_at", "updated_at").
+							AddRow(account.ID, account.UserId, account.Name, account.AccountType, account.Currency, entity.ACTIVE, -10000, 0, -10000, 50000, 800, nil, account.CreatedAt, nil),
+					)
+			},
+		},
+		"deve retornar erro": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrInternalServerError,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs(day).
+					WillReturnError(echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseAccount(dbConn)
+			ctx := context.Background()
+
+			accounts, err := db.ReadOverdrawn(ctx, day)
+			if diff := cmp.Diff(accounts, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestUpdateCreditLine(t *testing.T) {
+	query := "UPDATE accounts SET credit_limit = ?, interest_rate = ? WHERE id = ?"
+
+	cases := map[string]struct {
+		ExpectedErr error
+		PrepareMock func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			ExpectedErr: nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(query).
+					WithArgs(50000, 800, "account-id").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+		},
+		"deve retornar erro": {
+			ExpectedErr: echo.ErrInternalServerError,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(query).
+					WithArgs(50000, 800, "account-id").
+					WillReturnError(echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseAccount(dbConn)
+			ctx := context.Background()
+
+			err := db.UpdateCreditLine(ctx, "account-id", money.New(50000), 800)
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestUpdateInterestAccruedOn(t *testing.T) {
+	query := "UPDATE accounts SET interest_accrued_on = ? WHERE id = ?"
+
+	day := time.Date(2023, 5, 13, 0, 0, 0, 0, time.UTC)
+
+	cases := map[string]struct {
+		ExpectedErr error
+		PrepareMock func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			ExpectedErr: nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(query).
+					WithArgs(day, "account-id").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+		},
+		"deve retornar erro": {
+			ExpectedErr: echo.ErrInternalServerError,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(query).
+					WithArgs(day, "account-id").
+					WillReturnError(echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseAccount(dbConn)
+			ctx := context.Background()
+
+			err := db.UpdateInterestAccruedOn(ctx, "account-id", day)
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package audit
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	"github.com/jmoiron/sqlx"
+	"github.com/labstack/echo/v4"
+)
+
+// DabataseAuditInterface only appends to the audit log and reads it, entries
+// are never updated nor deleted.
+type DabataseAuditInterface interface {
+	Create(ctx context.Context, entry *entity.AuditEntry) error
+	ReadAll(ctx context.Context, filter entity.AuditFilter) ([]entity.AuditEntry, error)
+}
+
+type dbImpl struct {
+	dbConn sqlx.ExtContext
+}
+
+func NewDatabaseAudit(dbConn sqlx.ExtContext) DabataseAuditInterface {
+	return &dbImpl{dbConn}
+}
+
+func (a *dbImpl) Create(ctx context.Context, entry *entity.AuditEntry) error {
+	query := "INSERT INTO audit_log (id, actor, request_id, client_ip, operation, entity_type, entity_id, `before`, `after`, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+
+	_, err := a.dbConn.ExecContext(ctx, query,
+		entry.ID,
+		entry.Actor,
+		entry.RequestId,
+		entry.ClientIP,
+		entry.Operation,
+		entry.EntityType,
+		entry.EntityId,
+		entry.Before,
+		entry.After,
+		entry.CreatedAt,
+	)
+	if err != nil {
+		log.Println("Error create audit entry: ", err.Error())
+		return echo.ErrInternalServerError
+	}
+
+	return nil
+}
+
+// ReadAll lists the entries matching the filter, newest first.
+func (a *dbImpl) ReadAll(ctx context.Context, filter entity.AuditFilter) ([]entity.AuditEntry, error) {
+	conditions := make([]string, 0, 5)
+	args := make([]interface{}, 0, 6)
+	if filter.Actor != "" {
+		conditions = append(conditions, "actor = ?")
+		args = append(args, filter.Actor)
+	}
+	if filter.EntityType != "" {
+		conditions = append(conditions, "entity_type = ?")
+		args = append(args, filter.EntityType)
+	}
+	if filter.EntityId != "" {
+		conditions = append(conditions, "entity_id = ?")
+		args = append(args, filter.EntityId)
+	}
+	if filter.From != nil {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, *filter.From)
+	}
+	if filter.To != nil {
+		conditions = append(conditions, "created_at < ?")
+		args = append(args, *filter.To)
+	}
+	args = append(args, filter.Limit)
+
+	query := "SELECT id, actor, request_id, client_ip, operation, entity_type, entity_id, `before`, `after`, created_at FROM audit_log"
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY created_at DESC, id LIMIT ?"
+
+	entries := make([]entity.AuditEntry, 0)
+	err := sqlx.SelectContext(ctx, a.dbConn, &entries, query, args...)
+	if err != nil {
+		log.Println("Error ReadAll audit entries: ", err.Error())
+		return nil, echo.ErrInternalServerError
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,174 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/labstack/echo/v4"
+)
+
+var (
+	entryColumns = []string{"id", "actor", "request_id", "client_ip", "operation", "entity_type", "entity_id", "before", "after", "created_at"}
+	createdAt    = time.Date(2023, 5, 23, 9, 0, 0, 0, time.UTC)
+)
+
+func rawJSON(value string) *json.RawMessage {
+	raw := json.RawMessage(value)
+	return &raw
+}
+
+func TestCreate(t *testing.T) {
+	query := "INSERT INTO audit_log (id, actor, request_id, client_ip, operation, entity_type, entity_id, `before`, `after`, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+
+	entry := &entity.AuditEntry{
+		ID:         "entry-id",
+		Actor:      "jane.doe",
+		RequestId:  "request-id",
+		ClientIP:   "203.0.113.7",
+		Operation:  entity.AUDIT_USER_CREATED,
+		EntityType: entity.AGGREGATE_USER,
+		EntityId:   "user-id",
+		After:      rawJSON(`{"id":"user-id"}`),
+		CreatedAt:  createdAt,
+	}
+
+	cases := map[string]struct {
+		ExpectedErr error
+		PrepareMock func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			ExpectedErr: nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(query).
+					WithArgs("entry-id", "jane.doe", "request-id", "203.0.113.7", "user.created", "user", "user-id", nil, []byte(`{"id":"user-id"}`), createdAt).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+		},
+		"deve retornar erro": {
+			ExpectedErr: echo.ErrInternalServerError,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(query).
+					WithArgs("entry-id", "jane.doe", "request-id", "203.0.113.7", "user.created", "user", "user-id", nil, []byte(`{"id":"user-id"}`), createdAt).
+					WillReturnError(echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseAudit(dbConn)
+			ctx := context.Background()
+
+			err := db.Create(ctx, entry)
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestReadAll(t *testing.T) {
+	columns := "SELECT id, actor, request_id, client_ip, operation, entity_type, entity_id, `before`, `after`, created_at FROM audit_log"
+	from := createdAt.Add(-time.Hour)
+	to := createdAt.Add(time.Hour)
+
+	entry := entity.AuditEntry{
+		ID:         "entry-id",
+		Actor:      "jane.doe",
+		RequestId:  "request-id",
+		ClientIP:   "203.0.113.7",
+		Operation:  entity.AUDIT_ACCOUNT_BALANCE_UPDATED,
+		EntityType: entity.AUDIT_ACCOUNT,
+		EntityId:   "account-id",
+		Before:     rawJSON(`{"balance":"10.00","heldBalance":"0.00"}`),
+		After:      rawJSON(`{"balance":"15.00","heldBalance":"0.00"}`),
+		CreatedAt:  createdAt,
+	}
+
+	cases := map[string]struct {
+		Filter         entity.AuditFilter
+		ExpectedResult []entity.AuditEntry
+		ExpectedErr    error
+		PrepareMock    func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso sem filtros": {
+			Filter:         entity.AuditFilter{Limit: 100},
+			ExpectedResult: []entity.AuditEntry{entry},
+			ExpectedErr:    nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(columns + " ORDER BY created_at DESC, id LIMIT ?").
+					WithArgs(100).
+					WillReturnRows(test.NewRows(entryColumns...).AddRow("entry-id", "jane.doe", "request-id", "203.0.113.7", "account.balance_updated", "account", "account-id", []byte(`{"balance":"10.00","heldBalance":"0.00"}`), []byte(`{"balance":"15.00","heldBalance":"0.00"}`), createdAt))
+			},
+		},
+		"deve retornar sucesso com filtros": {
+			Filter: entity.AuditFilter{
+				Actor:      "jane.doe",
+				EntityType: entity.AGGREGATE_USER,
+				EntityId:   "user-id",
+				From:       &from,
+				To:         &to,
+				Limit:      10,
+			},
+			ExpectedResult: []entity.AuditEntry{{
+				ID:         "entry-id",
+				Actor:      "jane.doe",
+				RequestId:  "request-id",
+				ClientIP:   "203.0.113.7",
+				Operation:  entity.AUDIT_USER_CREATED,
+				EntityType: entity.AGGREGATE_USER,
+				EntityId:   "user-id",
+				After:      rawJSON(`{"id":"user-id"}`),
+				CreatedAt:  createdAt,
+			}},
+			ExpectedErr: nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(columns+" WHERE actor = ? AND entity_type = ? AND entity_id = ? AND created_at >= ? AND created_at < ? ORDER BY created_at DESC, id LIMIT ?").
+					WithArgs("jane.doe", "user", "user-id", from, to, 10).
+					WillReturnRows(test.NewRows(entryColumns...).AddRow("entry-id", "jane.doe", "request-id", "203.0.113.7", "user.created", "user", "user-id", nil, []byte(`{"id":"user-id"}`), createdAt))
+			},
+		},
+		"deve retornar erro": {
+			Filter:         entity.AuditFilter{Actor: "jane.doe", Limit: 100},
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrInternalServerError,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(columns+" WHERE actor = ? ORDER BY created_at DESC, id LIMIT ?").
+					WithArgs("jane.doe", 100).
+					WillReturnError(echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseAudit(dbConn)
+			ctx := context.Background()
+
+			entries, err := db.ReadAll(ctx, cs.Filter)
+			if diff := cmp.Diff(entries, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
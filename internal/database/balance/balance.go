@@ -0,0 +1,85 @@
+package balance
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	"github.com/jmoiron/sqlx"
+	"github.com/labstack/echo/v4"
+)
+
+type DabataseBalanceInterface interface {
+	CreateSnapshots(ctx context.Context, previous *time.Time, takenAt time.Time) error
+	ReadLatestSnapshot(ctx context.Context, accountId string, at time.Time) (*entity.BalanceSnapshot, error)
+	ReadLastSnapshotAt(ctx context.Context) (*time.Time, error)
+}
+
+type dbImpl struct {
+	dbConn sqlx.ExtContext
+}
+
+func NewDatabaseBalance(dbConn sqlx.ExtContext) DabataseBalanceInterface {
+	return &dbImpl{dbConn}
+}
+
+// CreateSnapshots snapshots the balance of every account at takenAt, carrying
+// the snapshots taken at previous forward with the postings made since, or
+// summing every posting when previous is nil. Snapshots already taken are
+// kept as they are.
+func (b *dbImpl) CreateSnapshots(ctx context.Context, previous *time.Time, takenAt time.Time) error {
+	query := "INSERT IGNORE INTO balance_snapshots (account_id, taken_at, balance) " +
+		"SELECT account_id, ?, SUM(amount) FROM postings WHERE created_at < ? GROUP BY account_id"
+	args := []interface{}{takenAt, takenAt}
+	if previous != nil {
+		query = "INSERT IGNORE INTO balance_snapshots (account_id, taken_at, balance) " +
+			"SELECT account_id, ?, SUM(amount) FROM (" +
+			"SELECT account_id, balance AS amount FROM balance_snapshots WHERE taken_at = ? " +
+			"UNION ALL SELECT account_id, amount FROM postings WHERE created_at >= ? AND created_at < ?" +
+			") movements GROUP BY account_id"
+		args = []interface{}{takenAt, *previous, *previous, takenAt}
+	}
+
+	_, err := b.dbConn.ExecContext(ctx, query, args...)
+	if err != nil {
+		log.Println("Error create balance snapshots: ", err.Error())
+		return echo.ErrInternalServerError
+	}
+
+	return nil
+}
+
+// ReadLatestSnapshot reads the last snapshot of the account taken at or before
+// at, or nil when there's none.
+func (b *dbImpl) ReadLatestSnapshot(ctx context.Context, accountId string, at time.Time) (*entity.BalanceSnapshot, error) {
+	snapshots := make([]entity.BalanceSnapshot, 0, 1)
+	query := "SELECT account_id, taken_at, balance FROM balance_snapshots WHERE account_id = ? AND taken_at <= ? ORDER BY taken_at DESC LIMIT 1"
+
+	err := sqlx.SelectContext(ctx, b.dbConn, &snapshots, query, accountId, at)
+	if err != nil {
+		log.Println("Error ReadLatestSnapshot balance snapshots: ", err.Error())
+		return nil, echo.ErrInternalServerError
+	}
+
+	if len(snapshots) == 0 {
+		return nil, nil
+	}
+
+	return &snapshots[0], nil
+}
+
+// ReadLastSnapshotAt reads when the last snapshots were taken, or nil when none
+// were taken yet.
+func (b *dbImpl) ReadLastSnapshotAt(ctx context.Context) (*time.Time, error) {
+	var takenAt *time.Time
+	query := "SELECT MAX(taken_at) FROM balance_snapshots"
+
+	err := sqlx.GetContext(ctx, b.dbConn, &takenAt, query)
+	if err != nil {
+		log.Println("Error ReadLastSnapshotAt balance snapshots: ", err.Error())
+		return nil, echo.ErrInternalServerError
+	}
+
+	return takenAt, nil
+}
@@ -0,0 +1,188 @@
+package balance
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/test"
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/money"
+	"github.com/google/go-cmp/cmp"
+	"github.com/labstack/echo/v4"
+)
+
+func TestCreateSnapshots(t *testing.T) {
+	previous := time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC)
+	takenAt := time.Date(2023, 5, 2, 0, 0, 0, 0, time.UTC)
+
+	cases := map[string]struct {
+		InputPrevious *time.Time
+		ExpectedErr   error
+		PrepareMock   func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			InputPrevious: &previous,
+			ExpectedErr:   nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("INSERT IGNORE INTO balance_snapshots (account_id, taken_at, balance) "+
+					"SELECT account_id, ?, SUM(amount) FROM ("+
+					"SELECT account_id, balance AS amount FROM balance_snapshots WHERE taken_at = ? "+
+					"UNION ALL SELECT account_id, amount FROM postings WHERE created_at >= ? AND created_at < ?"+
+					") movements GROUP BY account_id").
+					WithArgs(takenAt, previous, previous, takenAt).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+			},
+		},
+		"deve retornar sucesso: primeiro snapshot": {
+			InputPrevious: nil,
+			ExpectedErr:   nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("INSERT IGNORE INTO balance_snapshots (account_id, taken_at, balance) "+
+					"SELECT account_id, ?, SUM(amount) FROM postings WHERE created_at < ? GROUP BY account_id").
+					WithArgs(takenAt, takenAt).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+			},
+		},
+		"deve retornar erro": {
+			InputPrevious: nil,
+			ExpectedErr:   echo.ErrInternalServerError,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("INSERT IGNORE INTO balance_snapshots (account_id, taken_at, balance) "+
+					"SELECT account_id, ?, SUM(amount) FROM postings WHERE created_at < ? GROUP BY account_id").
+					WithArgs(takenAt, takenAt).
+					WillReturnError(echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseBalance(dbConn)
+			ctx := context.Background()
+
+			err := db.CreateSnapshots(ctx, cs.InputPrevious, takenAt)
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestReadLatestSnapshot(t *testing.T) {
+	query := "SELECT account_id, taken_at, balance FROM balance_snapshots WHERE account_id = ? AND taken_at <= ? ORDER BY taken_at DESC LIMIT 1"
+	at := time.Date(2023, 5, 2, 12, 0, 0, 0, time.UTC)
+	takenAt := time.Date(2023, 5, 2, 0, 0, 0, 0, time.UTC)
+
+	cases := map[string]struct {
+		ExpectedResult *entity.BalanceSnapshot
+		ExpectedErr    error
+		PrepareMock    func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			ExpectedResult: &entity.BalanceSnapshot{AccountId: "user-id", TakenAt: takenAt, Balance: money.New(10000)},
+			ExpectedErr:    nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs("user-id", at).
+					WillReturnRows(test.NewRows("account_id", "taken_at", "balance").AddRow("user-id", takenAt, int64(10000)))
+			},
+		},
+		"deve retornar sucesso: sem snapshot": {
+			ExpectedResult: nil,
+			ExpectedErr:    nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs("user-id", at).
+					WillReturnRows(test.NewRows("account_id", "taken_at", "balance"))
+			},
+		},
+		"deve retornar erro": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrInternalServerError,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs("user-id", at).
+					WillReturnError(echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseBalance(dbConn)
+			ctx := context.Background()
+
+			snapshot, err := db.ReadLatestSnapshot(ctx, "user-id", at)
+			if diff := cmp.Diff(snapshot, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestReadLastSnapshotAt(t *testing.T) {
+	query := "SELECT MAX(taken_at) FROM balance_snapshots"
+	takenAt := time.Date(2023, 5, 2, 0, 0, 0, 0, time.UTC)
+
+	cases := map[string]struct {
+		ExpectedResult *time.Time
+		ExpectedErr    error
+		PrepareMock    func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			ExpectedResult: &takenAt,
+			ExpectedErr:    nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WillReturnRows(test.NewRows("taken_at").AddRow(takenAt))
+			},
+		},
+		"deve retornar sucesso: sem snapshots": {
+			ExpectedResult: nil,
+			ExpectedErr:    nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WillReturnRows(test.NewRows("taken_at").AddRow(nil))
+			},
+		},
+		"deve retornar erro": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrInternalServerError,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WillReturnError(echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseBalance(dbConn)
+			ctx := context.Background()
+
+			takenAt, err := db.ReadLastSnapshotAt(ctx)
+			if diff := cmp.Diff(takenAt, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
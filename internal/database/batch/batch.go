@@ -0,0 +1,96 @@
+package batch
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	"github.com/jmoiron/sqlx"
+	"github.com/labstack/echo/v4"
+)
+
+type DabataseBatchInterface interface {
+	Create(ctx context.Context, batch *entity.Batch) error
+	UpdateState(ctx context.Context, state entity.StatesBatch, id string) error
+	CreateItems(ctx context.Context, items []entity.BatchItem) error
+	ReadOneById(ctx context.Context, id string) (*entity.Batch, error)
+	ReadItems(ctx context.Context, batchId string) ([]entity.BatchItem, error)
+}
+
+type dbImpl struct {
+	dbConn sqlx.ExtContext
+}
+
+func NewDatabaseBatch(dbConn sqlx.ExtContext) DabataseBatchInterface {
+	return &dbImpl{dbConn}
+}
+
+func (b *dbImpl) Create(ctx context.Context, batch *entity.Batch) error {
+	query := "INSERT INTO batches (id, atomic, state) VALUES (?, ?, ?)"
+
+	_, err := b.dbConn.ExecContext(ctx, query, batch.ID, batch.Atomic, batch.State)
+	if err != nil {
+		log.Println("Error create batch: ", err.Error())
+		return echo.ErrInternalServerError
+	}
+
+	return nil
+}
+
+func (b *dbImpl) UpdateState(ctx context.Context, state entity.StatesBatch, id string) error {
+	query := "UPDATE batches SET state = ? WHERE id = ?"
+
+	_, err := b.dbConn.ExecContext(ctx, query, state, id)
+	if err != nil {
+		log.Println("Error update state batch: ", err.Error())
+		return echo.ErrInternalServerError
+	}
+
+	return nil
+}
+
+func (b *dbImpl) CreateItems(ctx context.Context, items []entity.BatchItem) error {
+	values := make([]string, 0, len(items))
+	args := make([]interface{}, 0, len(items)*5)
+	for _, item := range items {
+		values = append(values, "(?, ?, ?, ?, ?)")
+		args = append(args, item.BatchId, item.Position, item.TransactionId, item.State, item.Error)
+	}
+
+	query := "INSERT INTO batch_items (batch_id, position, transaction_id, state, error) VALUES " + strings.Join(values, ", ")
+
+	_, err := b.dbConn.ExecContext(ctx, query, args...)
+	if err != nil {
+		log.Println("Error create batch items: ", err.Error())
+		return echo.ErrInternalServerError
+	}
+
+	return nil
+}
+
+func (b *dbImpl) ReadOneById(ctx context.Context, id string) (*entity.Batch, error) {
+	batch := new(entity.Batch)
+	query := "SELECT id, atomic, state, created_at FROM batches WHERE id = ?"
+
+	err := sqlx.GetContext(ctx, b.dbConn, batch, query, id)
+	if err != nil {
+		log.Println("Error ReadOneById batch: ", err.Error())
+		return nil, echo.ErrNotFound
+	}
+
+	return batch, nil
+}
+
+func (b *dbImpl) ReadItems(ctx context.Context, batchId string) ([]entity.BatchItem, error) {
+	items := make([]entity.BatchItem, 0)
+	query := "SELECT batch_id, position, transaction_id, state, error FROM batch_items WHERE batch_id = ? ORDER BY position"
+
+	err := sqlx.SelectContext(ctx, b.dbConn, &items, query, batchId)
+	if err != nil {
+		log.Println("Error ReadItems batch: ", err.Error())
+		return nil, echo.ErrInternalServerError
+	}
+
+	return items, nil
+}
@@ -0,0 +1,242 @@
+package batch
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/labstack/echo/v4"
+)
+
+func TestCreate(t *testing.T) {
+	query := "INSERT INTO batches (id, atomic, state) VALUES (?, ?, ?)"
+
+	batch := &entity.Batch{ID: "batch-id", Atomic: true, State: entity.BATCH_PROCESSING}
+
+	cases := map[string]struct {
+		ExpectedErr error
+		PrepareMock func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			ExpectedErr: nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(query).
+					WithArgs("batch-id", true, entity.BATCH_PROCESSING).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+		},
+		"deve retornar erro": {
+			ExpectedErr: echo.ErrInternalServerError,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(query).
+					WithArgs("batch-id", true, entity.BATCH_PROCESSING).
+					WillReturnError(echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseBatch(dbConn)
+			ctx := context.Background()
+
+			err := db.Create(ctx, batch)
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestUpdateState(t *testing.T) {
+	query := "UPDATE batches SET state = ? WHERE id = ?"
+
+	cases := map[string]struct {
+		ExpectedErr error
+		PrepareMock func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			ExpectedErr: nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(query).
+					WithArgs(entity.BATCH_BOOKED, "batch-id").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+		},
+		"deve retornar erro": {
+			ExpectedErr: echo.ErrInternalServerError,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(query).
+					WithArgs(entity.BATCH_BOOKED, "batch-id").
+					WillReturnError(echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseBatch(dbConn)
+			ctx := context.Background()
+
+			err := db.UpdateState(ctx, entity.BATCH_BOOKED, "batch-id")
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestCreateItems(t *testing.T) {
+	query := "INSERT INTO batch_items (batch_id, position, transaction_id, state, error) VALUES (?, ?, ?, ?, ?), (?, ?, ?, ?, ?)"
+
+	items := []entity.BatchItem{
+		{BatchId: "batch-id", Position: 0, TransactionId: "transaction-id-0", State: entity.BOOKED},
+		{BatchId: "batch-id", Position: 1, TransactionId: "transaction-id-1", State: entity.FAILED, Error: "Insufficient balance"},
+	}
+
+	cases := map[string]struct {
+		ExpectedErr error
+		PrepareMock func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			ExpectedErr: nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(query).
+					WithArgs("batch-id", 0, "transaction-id-0", entity.BOOKED, "", "batch-id", 1, "transaction-id-1", entity.FAILED, "Insufficient balance").
+					WillReturnResult(sqlmock.NewResult(2, 2))
+			},
+		},
+		"deve retornar erro": {
+			ExpectedErr: echo.ErrInternalServerError,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(query).
+					WithArgs("batch-id", 0, "transaction-id-0", entity.BOOKED, "", "batch-id", 1, "transaction-id-1", entity.FAILED, "Insufficient balance").
+					WillReturnError(echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseBatch(dbConn)
+			ctx := context.Background()
+
+			err := db.CreateItems(ctx, items)
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestReadOneById(t *testing.T) {
+	query := "SELECT id, atomic, state, created_at FROM batches WHERE id = ?"
+
+	cases := map[string]struct {
+		ExpectedResult *entity.Batch
+		ExpectedErr    error
+		PrepareMock    func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			ExpectedResult: &entity.Batch{ID: "batch-id", Atomic: true, State: entity.BATCH_FAILED},
+			ExpectedErr:    nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs("batch-id").
+					WillReturnRows(test.NewRows("id", "atomic", "state", "created_at").AddRow("batch-id", true, entity.BATCH_FAILED, nil))
+			},
+		},
+		"deve retornar erro": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrNotFound,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs("batch-id").
+					WillReturnError(echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseBatch(dbConn)
+			ctx := context.Background()
+
+			batch, err := db.ReadOneById(ctx, "batch-id")
+			if diff := cmp.Diff(batch, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestReadItems(t *testing.T) {
+	query := "SELECT batch_id, position, transaction_id, state, error FROM batch_items WHERE batch_id = ? ORDER BY position"
+
+	items := []entity.BatchItem{
+		{BatchId: "batch-id", Position: 0, TransactionId: "transaction-id", State: entity.BOOKED},
+	}
+
+	cases := map[string]struct {
+		ExpectedResult []entity.BatchItem
+		ExpectedErr    error
+		PrepareMock    func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			ExpectedResult: items,
+			ExpectedErr:    nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs("batch-id").
+					WillReturnRows(test.NewRows("batch_id", "position", "transaction_id", "state", "error").AddRow("batch-id", 0, "transaction-id", entity.BOOKED, ""))
+			},
+		},
+		"deve retornar erro": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrInternalServerError,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs("batch-id").
+					WillReturnError(echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseBatch(dbConn)
+			ctx := context.Background()
+
+			items, err := db.ReadItems(ctx, "batch-id")
+			if diff := cmp.Diff(items, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
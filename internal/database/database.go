@@ -1,19 +1,66 @@
 package database
 
 import (
+	"github.com/garoque/backend-code-challenge-snapfi/internal/database/account"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/database/audit"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/database/balance"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/database/batch"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/database/fee"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/database/idempotency"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/database/ledger"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/database/limit"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/database/outbox"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/database/reconciliation"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/database/standingorder"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/database/statehistory"
 	"github.com/garoque/backend-code-challenge-snapfi/internal/database/transaction"
 	"github.com/garoque/backend-code-challenge-snapfi/internal/database/user"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/database/webhook"
 	"github.com/jmoiron/sqlx"
 )
 
 type Container struct {
-	User        user.DabataseUserInterface
-	Transaction transaction.DabataseTransactionInterface
+	User           user.DabataseUserInterface
+	Account        account.DabataseAccountInterface
+	Transaction    transaction.DabataseTransactionInterface
+	Idempotency    idempotency.DabataseIdempotencyInterface
+	Ledger         ledger.DabataseLedgerInterface
+	StateHistory   statehistory.DabataseStateHistoryInterface
+	StandingOrder  standingorder.DabataseStandingOrderInterface
+	Batch          batch.DabataseBatchInterface
+	Fee            fee.DabataseFeeInterface
+	Limit          limit.DabataseLimitInterface
+	Balance        balance.DabataseBalanceInterface
+	Reconciliation reconciliation.DabataseReconciliationInterface
+	Outbox         outbox.DabataseOutboxInterface
+	Webhook        webhook.DabataseWebhookInterface
+	Audit          audit.DabataseAuditInterface
+	UnitOfWork     UnitOfWorkInterface
 }
 
 func New(dbConn *sqlx.DB) *Container {
+	container := newContainer(dbConn)
+	container.UnitOfWork = &unitOfWorkImpl{dbConn}
+
+	return container
+}
+
+func newContainer(dbConn sqlx.ExtContext) *Container {
 	return &Container{
-		User:        user.NewDatabaseUser(dbConn),
-		Transaction: transaction.NewDatabaseTransaction(dbConn),
+		User:           user.NewDatabaseUser(dbConn),
+		Account:        account.NewDatabaseAccount(dbConn),
+		Transaction:    transaction.NewDatabaseTransaction(dbConn),
+		Idempotency:    idempotency.NewDatabaseIdempotency(dbConn),
+		Ledger:         ledger.NewDatabaseLedger(dbConn),
+		StateHistory:   statehistory.NewDatabaseStateHistory(dbConn),
+		StandingOrder:  standingorder.NewDatabaseStandingOrder(dbConn),
+		Batch:          batch.NewDatabaseBatch(dbConn),
+		Fee:            fee.NewDatabaseFee(dbConn),
+		Limit:          limit.NewDatabaseLimit(dbConn),
+		Balance:        balance.NewDatabaseBalance(dbConn),
+		Reconciliation: reconciliation.NewDatabaseReconciliation(dbConn),
+		Outbox:         outbox.NewDatabaseOutbox(dbConn),
+		Webhook:        webhook.NewDatabaseWebhook(dbConn),
+		Audit:          audit.NewDatabaseAudit(dbConn),
 	}
 }
@@ -0,0 +1,103 @@
+package fee
+
+import (
+	"context"
+	"log"
+
+	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	"github.com/jmoiron/sqlx"
+	"github.com/labstack/echo/v4"
+)
+
+type DabataseFeeInterface interface {
+	Create(ctx context.Context, rule *entity.FeeRule) error
+	Deactivate(ctx context.Context, id string) error
+	ReadAll(ctx context.Context) ([]entity.FeeRule, error)
+	ReadActive(ctx context.Context) ([]entity.FeeRule, error)
+	ReadOneById(ctx context.Context, id string) (*entity.FeeRule, error)
+}
+
+type dbImpl struct {
+	dbConn sqlx.ExtContext
+}
+
+func NewDatabaseFee(dbConn sqlx.ExtContext) DabataseFeeInterface {
+	return &dbImpl{dbConn}
+}
+
+func (f *dbImpl) Create(ctx context.Context, rule *entity.FeeRule) error {
+	query := "INSERT INTO fee_rules (id, name, type, kind, account_type, flat_amount, basis_points, tiers, min_amount, max_amount, active) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+
+	_, err := f.dbConn.ExecContext(ctx, query,
+		rule.ID,
+		rule.Name,
+		rule.Type,
+		rule.Kind,
+		rule.AccountType,
+		rule.FlatAmount,
+		rule.BasisPoints,
+		rule.Tiers,
+		rule.MinAmount,
+		rule.MaxAmount,
+		rule.Active,
+	)
+	if err != nil {
+		log.Println("Error create fee rule: ", err.Error())
+		return echo.ErrInternalServerError
+	}
+
+	return nil
+}
+
+func (f *dbImpl) Deactivate(ctx context.Context, id string) error {
+	query := "UPDATE fee_rules SET active = FALSE WHERE id = ?"
+
+	_, err := f.dbConn.ExecContext(ctx, query, id)
+	if err != nil {
+		log.Println("Error deactivate fee rule: ", err.Error())
+		return echo.ErrInternalServerError
+	}
+
+	return nil
+}
+
+func (f *dbImpl) ReadAll(ctx context.Context) ([]entity.FeeRule, error) {
+	rules := make([]entity.FeeRule, 0)
+	query := "SELECT id, name, type, kind, account_type, flat_amount, basis_points, tiers, min_amount, max_amount, active, created_at FROM fee_rules ORDER BY created_at"
+
+	err := sqlx.SelectContext(ctx, f.dbConn, &rules, query)
+	if err != nil {
+		log.Println("Error ReadAll fee rules: ", err.Error())
+		return nil, echo.ErrInternalServerError
+	}
+
+	return rules, nil
+}
+
+// ReadActive lists the rules fees are charged by, oldest first, which is the
+// order SelectFeeRule breaks ties in.
+func (f *dbImpl) ReadActive(ctx context.Context) ([]entity.FeeRule, error) {
+	rules := make([]entity.FeeRule, 0)
+	query := "SELECT id, name, type, kind, account_type, flat_amount, basis_points, tiers, min_amount, max_amount, active, created_at FROM fee_rules WHERE active = TRUE ORDER BY created_at"
+
+	err := sqlx.SelectContext(ctx, f.dbConn, &rules, query)
+	if err != nil {
+		log.Println("Error ReadActive fee rules: ", err.Error())
+		return nil, echo.ErrInternalServerError
+	}
+
+	return rules, nil
+}
+
+func (f *dbImpl) ReadOneById(ctx context.Context, id string) (*entity.FeeRule, error) {
+	rule := new(entity.FeeRule)
+	query := "SELECT id, name, type, kind, account_type, flat_amount, basis_points, tiers, min_amount, max_amount, active, created_at FROM fee_rules WHERE id = ?"
+
+	err := sqlx.GetContext(ctx, f.dbConn, rule, query, id)
+	if err != nil {
+		log.Println("Error ReadOneById fee rule: ", err.Error())
+		return nil, echo.ErrNotFound
+	}
+
+	return rule, nil
+}
@@ -0,0 +1,275 @@
+package fee
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/test"
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/money"
+	"github.com/google/go-cmp/cmp"
+	"github.com/labstack/echo/v4"
+)
+
+func newRule() *entity.FeeRule {
+	kind := entity.TRANSFER
+	basisPoints := int64(150)
+	maxAmount := money.New(1000)
+
+	return &entity.FeeRule{
+		ID:          "rule-id",
+		Name:        "transfers",
+		Type:        entity.FEE_PERCENTAGE,
+		Kind:        &kind,
+		BasisPoints: &basisPoints,
+		MaxAmount:   &maxAmount,
+		Active:      true,
+	}
+}
+
+func TestCreate(t *testing.T) {
+	query := "INSERT INTO fee_rules (id, name, type, kind, account_type, flat_amount, basis_points, tiers, min_amount, max_amount, active) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+
+	rule := newRule()
+
+	cases := map[string]struct {
+		ExpectedErr error
+		PrepareMock func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			ExpectedErr: nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(query).
+					WithArgs("rule-id", "transfers", entity.FEE_PERCENTAGE, entity.TRANSFER, nil, nil, 150, nil, nil, 1000, true).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+		},
+		"deve retornar erro": {
+			ExpectedErr: echo.ErrInternalServerError,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(query).
+					WithArgs("rule-id", "transfers", entity.FEE_PERCENTAGE, entity.TRANSFER, nil, nil, 150, nil, nil, 1000, true).
+					WillReturnError(echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseFee(dbConn)
+			ctx := context.Background()
+
+			err := db.Create(ctx, rule)
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestDeactivate(t *testing.T) {
+	query := "UPDATE fee_rules SET active = FALSE WHERE id = ?"
+
+	cases := map[string]struct {
+		ExpectedErr error
+		PrepareMock func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			ExpectedErr: nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(query).
+					WithArgs("rule-id").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+		},
+		"deve retornar erro": {
+			ExpectedErr: echo.ErrInternalServerError,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(query).
+					WithArgs("rule-id").
+					WillReturnError(echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseFee(dbConn)
+			ctx := context.Background()
+
+			err := db.Deactivate(ctx, "rule-id")
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestReadAll(t *testing.T) {
+	query := "SELECT id, name, type, kind, account_type, flat_amount, basis_points, tiers, min_amount, max_amount, active, created_at FROM fee_rules ORDER BY created_at"
+
+	rule := newRule()
+	rule.Active = false
+
+	cases := map[string]struct {
+		ExpectedResult []entity.FeeRule
+		ExpectedErr    error
+		PrepareMock    func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			ExpectedResult: []entity.FeeRule{*rule},
+			ExpectedErr:    nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WillReturnRows(
+						test.NewRows("id", "name", "type", "kind", "account_type", "flat_amount", "basis_points", "tiers", "min_amount", "max_amount", "active", "created_at").
+							AddRow("rule-id", "transfers", entity.FEE_PERCENTAGE, entity.TRANSFER, nil, nil, 150, nil, nil, 1000, false, nil),
+					)
+			},
+		},
+		"deve retornar erro": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrInternalServerError,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WillReturnError(echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseFee(dbConn)
+			ctx := context.Background()
+
+			rules, err := db.ReadAll(ctx)
+			if diff := cmp.Diff(rules, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestReadActive(t *testing.T) {
+	query := "SELECT id, name, type, kind, account_type, flat_amount, basis_points, tiers, min_amount, max_amount, active, created_at FROM fee_rules WHERE active = TRUE ORDER BY created_at"
+
+	upTo := money.New(10000)
+	tiered := entity.FeeRule{
+		ID:     "tiered-rule-id",
+		Name:   "deposits",
+		Type:   entity.FEE_TIERED,
+		Tiers:  entity.FeeTiers{{UpTo: &upTo, FlatAmount: money.New(100)}, {FlatAmount: money.New(0), BasisPoints: 50}},
+		Active: true,
+	}
+
+	cases := map[string]struct {
+		ExpectedResult []entity.FeeRule
+		ExpectedErr    error
+		PrepareMock    func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			ExpectedResult: []entity.FeeRule{*newRule(), tiered},
+			ExpectedErr:    nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WillReturnRows(
+						test.NewRows("id", "name", "type", "kind", "account_type", "flat_amount", "basis_points", "tiers", "min_amount", "max_amount", "active", "created_at").
+							AddRow("rule-id", "transfers", entity.FEE_PERCENTAGE, entity.TRANSFER, nil, nil, 150, nil, nil, 1000, true, nil).
+							AddRow("tiered-rule-id", "deposits", entity.FEE_TIERED, nil, nil, nil, nil, []byte(`[{"upTo":"100.00","flatAmount":"1.00","basisPoints":0},{"flatAmount":"0.00","basisPoints":50}]`), nil, nil, true, nil),
+					)
+			},
+		},
+		"deve retornar erro": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrInternalServerError,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WillReturnError(echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseFee(dbConn)
+			ctx := context.Background()
+
+			rules, err := db.ReadActive(ctx)
+			if diff := cmp.Diff(rules, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestReadOneById(t *testing.T) {
+	query := "SELECT id, name, type, kind, account_type, flat_amount, basis_points, tiers, min_amount, max_amount, active, created_at FROM fee_rules WHERE id = ?"
+
+	cases := map[string]struct {
+		ExpectedResult *entity.FeeRule
+		ExpectedErr    error
+		PrepareMock    func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			ExpectedResult: newRule(),
+			ExpectedErr:    nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs("rule-id").
+					WillReturnRows(
+						test.NewRows("id", "name", "type", "kind", "account_type", "flat_amount", "basis_points", "tiers", "min_amount", "max_amount", "active", "created_at").
+							AddRow("rule-id", "transfers", entity.FEE_PERCENTAGE, entity.TRANSFER, nil, nil, 150, nil, nil, 1000, true, nil),
+					)
+			},
+		},
+		"deve retornar erro": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrNotFound,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs("rule-id").
+					WillReturnError(echo.ErrNotFound)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseFee(dbConn)
+			ctx := context.Background()
+
+			rule, err := db.ReadOneById(ctx, "rule-id")
+			if diff := cmp.Diff(rule, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
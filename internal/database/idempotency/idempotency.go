@@ -0,0 +1,115 @@
+package idempotency
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+
+	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	"github.com/go-sql-driver/mysql"
+	"github.com/jmoiron/sqlx"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	mysqlErrDuplicateEntry = 1062
+
+	selectQuery = "SELECT scope, idempotency_key, request_hash, status_code, response_body, created_at, expires_at FROM idempotency_keys"
+)
+
+type DabataseIdempotencyInterface interface {
+	Create(ctx context.Context, key *entity.IdempotencyKey) error
+	ReadOne(ctx context.Context, scope, key string) (*entity.IdempotencyKey, error)
+	ReadOneForUpdate(ctx context.Context, scope, key string) (*entity.IdempotencyKey, error)
+	UpdateResponse(ctx context.Context, scope, key string, statusCode int, body []byte) error
+	Delete(ctx context.Context, scope, key string) error
+}
+
+type dbImpl struct {
+	dbConn sqlx.ExtContext
+}
+
+func NewDatabaseIdempotency(dbConn sqlx.ExtContext) DabataseIdempotencyInterface {
+	return &dbImpl{dbConn}
+}
+
+// Create reserves the key. It returns echo.ErrConflict when the key is already
+// stored for the scope.
+func (i *dbImpl) Create(ctx context.Context, key *entity.IdempotencyKey) error {
+	query := "INSERT INTO idempotency_keys (scope, idempotency_key, request_hash, created_at, expires_at) VALUES (?, ?, ?, ?, ?)"
+
+	_, err := i.dbConn.ExecContext(ctx, query, key.Scope, key.Key, key.RequestHash, key.CreatedAt, key.ExpiresAt)
+	if err != nil {
+		var mysqlErr *mysql.MySQLError
+		if errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlErrDuplicateEntry {
+			return echo.ErrConflict
+		}
+
+		log.Println("Error create idempotency key: ", err.Error())
+		return echo.ErrInternalServerError
+	}
+
+	return nil
+}
+
+func (i *dbImpl) ReadOne(ctx context.Context, scope, key string) (*entity.IdempotencyKey, error) {
+	idempotencyKey := new(entity.IdempotencyKey)
+	query := selectQuery + " WHERE scope = ? AND idempotency_key = ?"
+
+	err := sqlx.GetContext(ctx, i.dbConn, idempotencyKey, query, scope, key)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, echo.ErrNotFound
+	}
+
+	if err != nil {
+		log.Println("Error ReadOne idempotency key: ", err.Error())
+		return nil, echo.ErrInternalServerError
+	}
+
+	return idempotencyKey, nil
+}
+
+// ReadOneForUpdate locks the key until the surrounding transaction ends, so
+// only one retry can take over a key whose request was abandoned. It must be
+// called from a Container bound to a unit of work.
+func (i *dbImpl) ReadOneForUpdate(ctx context.Context, scope, key string) (*entity.IdempotencyKey, error) {
+	idempotencyKey := new(entity.IdempotencyKey)
+	query := selectQuery + " WHERE scope = ? AND idempotency_key = ? FOR UPDATE"
+
+	err := sqlx.GetContext(ctx, i.dbConn, idempotencyKey, query, scope, key)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, echo.ErrNotFound
+	}
+
+	if err != nil {
+		log.Println("Error ReadOneForUpdate idempotency key: ", err.Error())
+		return nil, echo.ErrInternalServerError
+	}
+
+	return idempotencyKey, nil
+}
+
+func (i *dbImpl) UpdateResponse(ctx context.Context, scope, key string, statusCode int, body []byte) error {
+	query := "UPDATE idempotency_keys SET status_code = ?, response_body = ? WHERE scope = ? AND idempotency_key = ?"
+
+	_, err := i.dbConn.ExecContext(ctx, query, statusCode, body, scope, key)
+	if err != nil {
+		log.Println("Error update idempotency key response: ", err.Error())
+		return echo.ErrInternalServerError
+	}
+
+	return nil
+}
+
+func (i *dbImpl) Delete(ctx context.Context, scope, key string) error {
+	query := "DELETE FROM idempotency_keys WHERE scope = ? AND idempotency_key = ?"
+
+	_, err := i.dbConn.ExecContext(ctx, query, scope, key)
+	if err != nil {
+		log.Println("Error delete idempotency key: ", err.Error())
+		return echo.ErrInternalServerError
+	}
+
+	return nil
+}
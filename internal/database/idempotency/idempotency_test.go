@@ -0,0 +1,295 @@
+package idempotency
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/test"
+	"github.com/go-sql-driver/mysql"
+	"github.com/google/go-cmp/cmp"
+	"github.com/labstack/echo/v4"
+)
+
+func TestCreate(t *testing.T) {
+	query := "INSERT INTO idempotency_keys (scope, idempotency_key, request_hash, created_at, expires_at) VALUES (?, ?, ?, ?, ?)"
+
+	key := entity.NewIdempotencyKey("POST /v1/transaction", "key", "hash", time.Hour)
+
+	cases := map[string]struct {
+		InputKey    *entity.IdempotencyKey
+		ExpectedErr error
+		PrepareMock func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			InputKey:    key,
+			ExpectedErr: nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(query).
+					WithArgs(key.Scope, key.Key, key.RequestHash, key.CreatedAt, key.ExpiresAt).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+		},
+		"deve retornar erro: chave duplicada": {
+			InputKey:    key,
+			ExpectedErr: echo.ErrConflict,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(query).
+					WithArgs(key.Scope, key.Key, key.RequestHash, key.CreatedAt, key.ExpiresAt).
+					WillReturnError(&mysql.MySQLError{Number: mysqlErrDuplicateEntry})
+			},
+		},
+		"deve retornar erro": {
+			InputKey:    key,
+			ExpectedErr: echo.ErrInternalServerError,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(query).
+					WithArgs(key.Scope, key.Key, key.RequestHash, key.CreatedAt, key.ExpiresAt).
+					WillReturnError(echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseIdempotency(dbConn)
+			ctx := context.Background()
+
+			err := db.Create(ctx, cs.InputKey)
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestReadOne(t *testing.T) {
+	query := "SELECT scope, idempotency_key, request_hash, status_code, response_body, created_at, expires_at FROM idempotency_keys WHERE scope = ? AND idempotency_key = ?"
+
+	statusCode := 201
+	key := &entity.IdempotencyKey{
+		Scope:        "POST /v1/transaction",
+		Key:          "key",
+		RequestHash:  "hash",
+		StatusCode:   &statusCode,
+		ResponseBody: []byte(`{"data":{}}`),
+		CreatedAt:    time.Now(),
+		ExpiresAt:    time.Now().Add(time.Hour),
+	}
+
+	cases := map[string]struct {
+		ExpectedResult *entity.IdempotencyKey
+		ExpectedErr    error
+		PrepareMock    func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			ExpectedResult: key,
+			ExpectedErr:    nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs(key.Scope, key.Key).
+					WillReturnRows(
+						test.NewRows("scope", "idempotency_key", "request_hash", "status_code", "response_body", "created_at", "expires_at").
+							AddRow(key.Scope, key.Key, key.RequestHash, statusCode, key.ResponseBody, key.CreatedAt, key.ExpiresAt),
+					)
+			},
+		},
+		"deve retornar erro: chave não encontrada": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrNotFound,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs(key.Scope, key.Key).
+					WillReturnRows(test.NewRows("scope"))
+			},
+		},
+		"deve retornar erro": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrInternalServerError,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs(key.Scope, key.Key).
+					WillReturnError(echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseIdempotency(dbConn)
+			ctx := context.Background()
+
+			result, err := db.ReadOne(ctx, key.Scope, key.Key)
+			if diff := cmp.Diff(result, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestReadOneForUpdate(t *testing.T) {
+	query := "SELECT scope, idempotency_key, request_hash, status_code, response_body, created_at, expires_at FROM idempotency_keys WHERE scope = ? AND idempotency_key = ? FOR UPDATE"
+
+	statusCode := 201
+	key := &entity.IdempotencyKey{
+		Scope:        "POST /v1/transaction",
+		Key:          "key",
+		RequestHash:  "hash",
+		StatusCode:   &statusCode,
+		ResponseBody: []byte(`{"data":{}}`),
+		CreatedAt:    time.Now(),
+		ExpiresAt:    time.Now().Add(time.Hour),
+	}
+
+	cases := map[string]struct {
+		ExpectedResult *entity.IdempotencyKey
+		ExpectedErr    error
+		PrepareMock    func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			ExpectedResult: key,
+			ExpectedErr:    nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs(key.Scope, key.Key).
+					WillReturnRows(
+						test.NewRows("scope", "idempotency_key", "request_hash", "status_code", "response_body", "created_at", "expires_at").
+							AddRow(key.Scope, key.Key, key.RequestHash, statusCode, key.ResponseBody, key.CreatedAt, key.ExpiresAt),
+					)
+			},
+		},
+		"deve retornar erro: chave não encontrada": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrNotFound,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs(key.Scope, key.Key).
+					WillReturnRows(test.NewRows("scope"))
+			},
+		},
+		"deve retornar erro": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrInternalServerError,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs(key.Scope, key.Key).
+					WillReturnError(echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseIdempotency(dbConn)
+			ctx := context.Background()
+
+			result, err := db.ReadOneForUpdate(ctx, key.Scope, key.Key)
+			if diff := cmp.Diff(result, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestUpdateResponse(t *testing.T) {
+	query := "UPDATE idempotency_keys SET status_code = ?, response_body = ? WHERE scope = ? AND idempotency_key = ?"
+
+	body := []byte(`{"data":{}}`)
+
+	cases := map[string]struct {
+		ExpectedErr error
+		PrepareMock func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			ExpectedErr: nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(query).
+					WithArgs(201, body, "scope", "key").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+		},
+		"deve retornar erro": {
+			ExpectedErr: echo.ErrInternalServerError,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(query).
+					WithArgs(201, body, "scope", "key").
+					WillReturnError(echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseIdempotency(dbConn)
+			ctx := context.Background()
+
+			err := db.UpdateResponse(ctx, "scope", "key", 201, body)
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	query := "DELETE FROM idempotency_keys WHERE scope = ? AND idempotency_key = ?"
+
+	cases := map[string]struct {
+		ExpectedErr error
+		PrepareMock func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			ExpectedErr: nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(query).
+					WithArgs("scope", "key").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+		},
+		"deve retornar erro": {
+			ExpectedErr: echo.ErrInternalServerError,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(query).
+					WithArgs("scope", "key").
+					WillReturnError(echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseIdempotency(dbConn)
+			ctx := context.Background()
+
+			err := db.Delete(ctx, "scope", "key")
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package ledger
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/money"
+	"github.com/jmoiron/sqlx"
+	"github.com/labstack/echo/v4"
+)
+
+type DabataseLedgerInterface interface {
+	CreatePostings(ctx context.Context, postings []entity.Posting) error
+	ReadTotal(ctx context.Context) (money.Money, error)
+	ReadUnbalancedTransactions(ctx context.Context) ([]string, error)
+	ReadBalanceMismatches(ctx context.Context) ([]entity.BalanceMismatch, error)
+	ReadAccountBalance(ctx context.Context, accountId string, since *time.Time, until time.Time) (money.Money, error)
+	ReadAccountPostings(ctx context.Context, accountId string, since, until time.Time) ([]entity.Posting, error)
+	ReadAccountTotals(ctx context.Context, accountId string, since, until time.Time) (money.Money, money.Money, error)
+}
+
+type dbImpl struct {
+	dbConn sqlx.ExtContext
+}
+
+func NewDatabaseLedger(dbConn sqlx.ExtContext) DabataseLedgerInterface {
+	return &dbImpl{dbConn}
+}
+
+func (l *dbImpl) CreatePostings(ctx context.Context, postings []entity.Posting) error {
+	values := make([]string, 0, len(postings))
+	args := make([]interface{}, 0, len(postings)*3)
+	for _, posting := range postings {
+		values = append(values, "(?, ?, ?)")
+		args = append(args, posting.TransactionId, posting.AccountId, posting.Amount)
+	}
+
+	query := "INSERT INTO postings (transaction_id, account_id, amount) VALUES " + strings.Join(values, ", ")
+
+	_, err := l.dbConn.ExecContext(ctx, query, args...)
+	if err != nil {
+		log.Println("Error create postings: ", err.Error())
+		return echo.ErrInternalServerError
+	}
+
+	return nil
+}
+
+func (l *dbImpl) ReadTotal(ctx context.Context) (money.Money, error) {
+	var total money.Money
+	query := "SELECT COALESCE(SUM(amount), 0) FROM postings"
+
+	err := sqlx.GetContext(ctx, l.dbConn, &total, query)
+	if err != nil {
+		log.Println("Error ReadTotal postings: ", err.Error())
+		return money.Money{}, echo.ErrInternalServerError
+	}
+
+	return total, nil
+}
+
+func (l *dbImpl) ReadUnbalancedTransactions(ctx context.Context) ([]string, error) {
+	transactionIds := make([]string, 0)
+	query := "SELECT transaction_id FROM postings GROUP BY transaction_id HAVING SUM(amount) <> 0"
+
+	err := sqlx.SelectContext(ctx, l.dbConn, &transactionIds, query)
+	if err != nil {
+		log.Println("Error ReadUnbalancedTransactions postings: ", err.Error())
+		return nil, echo.ErrInternalServerError
+	}
+
+	return transactionIds, nil
+}
+
+func (l *dbImpl) ReadBalanceMismatches(ctx context.Context) ([]entity.BalanceMismatch, error) {
+	mismatches := make([]entity.BalanceMismatch, 0)
+	query := "SELECT a.id AS account_id, a.balance, COALESCE(SUM(p.amount), 0) AS postings_balance FROM accounts a " +
+		"LEFT JOIN postings p ON p.account_id = a.id GROUP BY a.id, a.balance HAVING a.balance <> postings_balance"
+
+	err := sqlx.SelectContext(ctx, l.dbConn, &mismatches, query)
+	if err != nil {
+		log.Println("Error ReadBalanceMismatches postings: ", err.Error())
+		return nil, echo.ErrInternalServerError
+	}
+
+	return mismatches, nil
+}
+
+// ReadAccountBalance sums the postings of the account made from since, or from
+// the beginning when since is nil, until before until.
+func (l *dbImpl) ReadAccountBalance(ctx context.Context, accountId string, since *time.Time, until time.Time) (money.Money, error) {
+	var balance money.Money
+	query := "SELECT COALESCE(SUM(amount), 0) FROM postings WHERE account_id = ? AND created_at < ?"
+	args := []interface{}{accountId, until}
+	if since != nil {
+		query += " AND created_at >= ?"
+		args = append(args, *since)
+	}
+
+	err := sqlx.GetContext(ctx, l.dbConn, &balance, query, args...)
+	if err != nil {
+		log.Println("Error ReadAccountBalance postings: ", err.Error())
+		return money.Money{}, echo.ErrInternalServerError
+	}
+
+	return balance, nil
+}
+
+// ReadAccountPostings lists the postings of the account made from since until
+// before until, in the order they were made.
+func (l *dbImpl) ReadAccountPostings(ctx context.Context, accountId string, since, until time.Time) ([]entity.Posting, error) {
+	postings := make([]entity.Posting, 0)
+	query := "SELECT id, transaction_id, account_id, amount, created_at FROM postings WHERE account_id = ? AND created_at >= ? AND created_at < ? ORDER BY created_at, id"
+
+	err := sqlx.SelectContext(ctx, l.dbConn, &postings, query, accountId, since, until)
+	if err != nil {
+		log.Println("Error ReadAccountPostings postings: ", err.Error())
+		return nil, echo.ErrInternalServerError
+	}
+
+	return postings, nil
+}
+
+// ReadAccountTotals sums what came into the account and, as a positive amount,
+// what went out of it from since until before until.
+func (l *dbImpl) ReadAccountTotals(ctx context.Context, accountId string, since, until time.Time) (money.Money, money.Money, error) {
+	var totals struct {
+		In  money.Money `db:"total_in"`
+		Out money.Money `db:"total_out"`
+	}
+	query := "SELECT COALESCE(SUM(IF(amount > 0, amount, 0)), 0) AS total_in, COALESCE(SUM(IF(amount < 0, -amount, 0)), 0) AS total_out FROM postings WHERE account_id = ? AND created_at >= ? AND created_at < ?"
+
+	err := sqlx.GetContext(ctx, l.dbConn, &totals, query, accountId, since, until)
+	if err != nil {
+		log.Println("Error ReadAccountTotals postings: ", err.Error())
+		return money.Money{}, money.Money{}, echo.ErrInternalServerError
+	}
+
+	return totals.In, totals.Out, nil
+}
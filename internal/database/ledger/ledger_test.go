@@ -0,0 +1,387 @@
+package ledger
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/test"
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/money"
+	"github.com/google/go-cmp/cmp"
+	"github.com/labstack/echo/v4"
+)
+
+func TestCreatePostings(t *testing.T) {
+	query := "INSERT INTO postings (transaction_id, account_id, amount) VALUES (?, ?, ?), (?, ?, ?)"
+
+	postings := entity.NewPostings(&entity.Transaction{
+		ID:            "transaction-id",
+		SourceId:      "source-user-id",
+		DestinationId: "destination-user-id",
+		Amount:        money.New(10010),
+	})
+
+	cases := map[string]struct {
+		ExpectedErr error
+		PrepareMock func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			ExpectedErr: nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(query).
+					WithArgs("transaction-id", "source-user-id", money.New(-10010), "transaction-id", "destination-user-id", money.New(10010)).
+					WillReturnResult(sqlmock.NewResult(1, 2))
+			},
+		},
+		"deve retornar erro": {
+			ExpectedErr: echo.ErrInternalServerError,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(query).
+					WithArgs("transaction-id", "source-user-id", money.New(-10010), "transaction-id", "destination-user-id", money.New(10010)).
+					WillReturnError(echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseLedger(dbConn)
+			ctx := context.Background()
+
+			err := db.CreatePostings(ctx, postings)
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestReadTotal(t *testing.T) {
+	query := "SELECT COALESCE(SUM(amount), 0) FROM postings"
+
+	cases := map[string]struct {
+		ExpectedResult money.Money
+		ExpectedErr    error
+		PrepareMock    func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			ExpectedResult: money.New(0),
+			ExpectedErr:    nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WillReturnRows(test.NewRows("total").AddRow([]byte("0")))
+			},
+		},
+		"deve retornar erro": {
+			ExpectedResult: money.Money{},
+			ExpectedErr:    echo.ErrInternalServerError,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WillReturnError(echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseLedger(dbConn)
+			ctx := context.Background()
+
+			total, err := db.ReadTotal(ctx)
+			if diff := cmp.Diff(total, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestReadUnbalancedTransactions(t *testing.T) {
+	query := "SELECT transaction_id FROM postings GROUP BY transaction_id HAVING SUM(amount) <> 0"
+
+	cases := map[string]struct {
+		ExpectedResult []string
+		ExpectedErr    error
+		PrepareMock    func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			ExpectedResult: []string{"transaction-id"},
+			ExpectedErr:    nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WillReturnRows(test.NewRows("transaction_id").AddRow("transaction-id"))
+			},
+		},
+		"deve retornar erro": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrInternalServerError,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WillReturnError(echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseLedger(dbConn)
+			ctx := context.Background()
+
+			transactionIds, err := db.ReadUnbalancedTransactions(ctx)
+			if diff := cmp.Diff(transactionIds, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestReadBalanceMismatches(t *testing.T) {
+	query := "SELECT a.id AS account_id, a.balance, COALESCE(SUM(p.amount), 0) AS postings_balance FROM accounts a " +
+		"LEFT JOIN postings p ON p.account_id = a.id GROUP BY a.id, a.balance HAVING a.balance <> postings_balance"
+
+	mismatches := []entity.BalanceMismatch{{
+		AccountId:       "user-id",
+		Balance:         money.New(10010),
+		PostingsBalance: money.New(10000),
+	}}
+
+	cases := map[string]struct {
+		ExpectedResult []entity.BalanceMismatch
+		ExpectedErr    error
+		PrepareMock    func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			ExpectedResult: mismatches,
+			ExpectedErr:    nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WillReturnRows(
+						test.NewRows("account_id", "balance", "postings_balance").
+							AddRow("user-id", int64(10010), []byte("10000")),
+					)
+			},
+		},
+		"deve retornar erro": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrInternalServerError,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WillReturnError(echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseLedger(dbConn)
+			ctx := context.Background()
+
+			result, err := db.ReadBalanceMismatches(ctx)
+			if diff := cmp.Diff(result, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestReadAccountBalance(t *testing.T) {
+	since := time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	cases := map[string]struct {
+		InputSince     *time.Time
+		ExpectedResult money.Money
+		ExpectedErr    error
+		PrepareMock    func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			InputSince:     &since,
+			ExpectedResult: money.New(10000),
+			ExpectedErr:    nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT COALESCE(SUM(amount), 0) FROM postings WHERE account_id = ? AND created_at < ? AND created_at >= ?").
+					WithArgs("user-id", until, since).
+					WillReturnRows(test.NewRows("balance").AddRow([]byte("10000")))
+			},
+		},
+		"deve retornar sucesso: desde o inicio": {
+			InputSince:     nil,
+			ExpectedResult: money.New(10000),
+			ExpectedErr:    nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT COALESCE(SUM(amount), 0) FROM postings WHERE account_id = ? AND created_at < ?").
+					WithArgs("user-id", until).
+					WillReturnRows(test.NewRows("balance").AddRow([]byte("10000")))
+			},
+		},
+		"deve retornar erro": {
+			InputSince:     nil,
+			ExpectedResult: money.Money{},
+			ExpectedErr:    echo.ErrInternalServerError,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT COALESCE(SUM(amount), 0) FROM postings WHERE account_id = ? AND created_at < ?").
+					WithArgs("user-id", until).
+					WillReturnError(echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseLedger(dbConn)
+			ctx := context.Background()
+
+			balance, err := db.ReadAccountBalance(ctx, "user-id", cs.InputSince, until)
+			if diff := cmp.Diff(balance, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestReadAccountPostings(t *testing.T) {
+	query := "SELECT id, transaction_id, account_id, amount, created_at FROM postings WHERE account_id = ? AND created_at >= ? AND created_at < ? ORDER BY created_at, id"
+	since := time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2023, 5, 2, 0, 0, 0, 0, time.UTC)
+	createdAt := time.Date(2023, 5, 1, 9, 0, 0, 0, time.UTC)
+
+	postings := []entity.Posting{
+		{ID: 1, TransactionId: "transaction-id", AccountId: "user-id", Amount: money.New(10000), CreatedAt: &createdAt},
+	}
+
+	cases := map[string]struct {
+		ExpectedResult []entity.Posting
+		ExpectedErr    error
+		PrepareMock    func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			ExpectedResult: postings,
+			ExpectedErr:    nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs("user-id", since, until).
+					WillReturnRows(
+						test.NewRows("id", "transaction_id", "account_id", "amount", "created_at").
+							AddRow(1, "transaction-id", "user-id", int64(10000), createdAt),
+					)
+			},
+		},
+		"deve retornar erro": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrInternalServerError,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs("user-id", since, until).
+					WillReturnError(echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseLedger(dbConn)
+			ctx := context.Background()
+
+			result, err := db.ReadAccountPostings(ctx, "user-id", since, until)
+			if diff := cmp.Diff(result, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestReadAccountTotals(t *testing.T) {
+	query := "SELECT COALESCE(SUM(IF(amount > 0, amount, 0)), 0) AS total_in, COALESCE(SUM(IF(amount < 0, -amount, 0)), 0) AS total_out FROM postings WHERE account_id = ? AND created_at >= ? AND created_at < ?"
+	since := time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	cases := map[string]struct {
+		ExpectedIn  money.Money
+		ExpectedOut money.Money
+		ExpectedErr error
+		PrepareMock func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			ExpectedIn:  money.New(15000),
+			ExpectedOut: money.New(2500),
+			ExpectedErr: nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs("user-id", since, until).
+					WillReturnRows(test.NewRows("total_in", "total_out").AddRow([]byte("15000"), []byte("2500")))
+			},
+		},
+		"deve retornar erro": {
+			ExpectedIn:  money.Money{},
+			ExpectedOut: money.Money{},
+			ExpectedErr: echo.ErrInternalServerError,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs("user-id", since, until).
+					WillReturnError(echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseLedger(dbConn)
+			ctx := context.Background()
+
+			in, out, err := db.ReadAccountTotals(ctx, "user-id", since, until)
+			if diff := cmp.Diff(in, cs.ExpectedIn); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(out, cs.ExpectedOut); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package limit
+
+import (
+	"context"
+	"log"
+
+	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	"github.com/jmoiron/sqlx"
+	"github.com/labstack/echo/v4"
+)
+
+type DabataseLimitInterface interface {
+	Save(ctx context.Context, limits *entity.Limits) error
+	Delete(ctx context.Context, userId string) error
+	ReadOneByUserId(ctx context.Context, userId string) (*entity.Limits, error)
+	ReadWithDefault(ctx context.Context, userId string) ([]entity.Limits, error)
+}
+
+type dbImpl struct {
+	dbConn sqlx.ExtContext
+}
+
+func NewDatabaseLimit(dbConn sqlx.ExtContext) DabataseLimitInterface {
+	return &dbImpl{dbConn}
+}
+
+// Save stores the limits, replacing the ones already stored for the user.
+func (l *dbImpl) Save(ctx context.Context, limits *entity.Limits) error {
+	query := "INSERT INTO transfer_limits (id_user, per_transaction, night_per_transaction, daily, monthly) VALUES (?, ?, ?, ?, ?) ON DUPLICATE KEY UPDATE per_transaction = VALUES(per_transaction), night_per_transaction = VALUES(night_per_transaction), daily = VALUES(daily), monthly = VALUES(monthly)"
+
+	_, err := l.dbConn.ExecContext(ctx, query,
+		limits.UserId,
+		limits.PerTransaction,
+		limits.NightPerTransaction,
+		limits.Daily,
+		limits.Monthly,
+	)
+	if err != nil {
+		log.Println("Error save limits: ", err.Error())
+		return echo.ErrInternalServerError
+	}
+
+	return nil
+}
+
+func (l *dbImpl) Delete(ctx context.Context, userId string) error {
+	query := "DELETE FROM transfer_limits WHERE id_user = ?"
+
+	_, err := l.dbConn.ExecContext(ctx, query, userId)
+	if err != nil {
+		log.Println("Error delete limits: ", err.Error())
+		return echo.ErrInternalServerError
+	}
+
+	return nil
+}
+
+func (l *dbImpl) ReadOneByUserId(ctx context.Context, userId string) (*entity.Limits, error) {
+	limits := new(entity.Limits)
+	query := "SELECT id_user, per_transaction, night_per_transaction, daily, monthly, updated_at FROM transfer_limits WHERE id_user = ?"
+
+	err := sqlx.GetContext(ctx, l.dbConn, limits, query, userId)
+	if err != nil {
+		log.Println("Error ReadOneByUserId limits: ", err.Error())
+		return nil, echo.ErrNotFound
+	}
+
+	return limits, nil
+}
+
+// ReadWithDefault reads the default limits along with the override of the
+// user, whichever of them is stored, for entity.ResolveLimits.
+func (l *dbImpl) ReadWithDefault(ctx context.Context, userId string) ([]entity.Limits, error) {
+	limits := make([]entity.Limits, 0)
+	query := "SELECT id_user, per_transaction, night_per_transaction, daily, monthly, updated_at FROM transfer_limits WHERE id_user IN (?, ?)"
+
+	err := sqlx.SelectContext(ctx, l.dbConn, &limits, query, entity.DefaultLimitsId, userId)
+	if err != nil {
+		log.Println("Error ReadWithDefault limits: ", err.Error())
+		return nil, echo.ErrInternalServerError
+	}
+
+	return limits, nil
+}
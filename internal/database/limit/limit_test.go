@@ -0,0 +1,204 @@
+package limit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/test"
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/money"
+	"github.com/google/go-cmp/cmp"
+	"github.com/labstack/echo/v4"
+)
+
+func newLimits(userId string) *entity.Limits {
+	daily := money.New(100000)
+
+	return &entity.Limits{UserId: userId, Daily: &daily}
+}
+
+func TestSave(t *testing.T) {
+	query := "INSERT INTO transfer_limits (id_user, per_transaction, night_per_transaction, daily, monthly) VALUES (?, ?, ?, ?, ?) ON DUPLICATE KEY UPDATE per_transaction = VALUES(per_transaction), night_per_transaction = VALUES(night_per_transaction), daily = VALUES(daily), monthly = VALUES(monthly)"
+
+	cases := map[string]struct {
+		ExpectedErr error
+		PrepareMock func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			ExpectedErr: nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(query).
+					WithArgs("user-id", nil, nil, 100000, nil).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+		},
+		"deve retornar erro": {
+			ExpectedErr: echo.ErrInternalServerError,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(query).
+					WithArgs("user-id", nil, nil, 100000, nil).
+					WillReturnError(echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseLimit(dbConn)
+			ctx := context.Background()
+
+			err := db.Save(ctx, newLimits("user-id"))
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	query := "DELETE FROM transfer_limits WHERE id_user = ?"
+
+	cases := map[string]struct {
+		ExpectedErr error
+		PrepareMock func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			ExpectedErr: nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(query).
+					WithArgs("user-id").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+		},
+		"deve retornar erro": {
+			ExpectedErr: echo.ErrInternalServerError,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(query).
+					WithArgs("user-id").
+					WillReturnError(echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseLimit(dbConn)
+			ctx := context.Background()
+
+			err := db.Delete(ctx, "user-id")
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestReadOneByUserId(t *testing.T) {
+	query := "SELECT id_user, per_transaction, night_per_transaction, daily, monthly, updated_at FROM transfer_limits WHERE id_user = ?"
+
+	cases := map[string]struct {
+		ExpectedResult *entity.Limits
+		ExpectedErr    error
+		PrepareMock    func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			ExpectedResult: newLimits("user-id"),
+			ExpectedErr:    nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs("user-id").
+					WillReturnRows(
+						test.NewRows("id_user", "per_transaction", "night_per_transaction", "daily", "monthly", "updated_at").
+							AddRow("user-id", nil, nil, 100000, nil, nil),
+					)
+			},
+		},
+		"deve retornar erro": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrNotFound,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs("user-id").
+					WillReturnError(echo.ErrNotFound)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseLimit(dbConn)
+			ctx := context.Background()
+
+			limits, err := db.ReadOneByUserId(ctx, "user-id")
+			if diff := cmp.Diff(limits, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestReadWithDefault(t *testing.T) {
+	query := "SELECT id_user, per_transaction, night_per_transaction, daily, monthly, updated_at FROM transfer_limits WHERE id_user IN (?, ?)"
+
+	cases := map[string]struct {
+		ExpectedResult []entity.Limits
+		ExpectedErr    error
+		PrepareMock    func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			ExpectedResult: []entity.Limits{*newLimits(entity.DefaultLimitsId), *newLimits("user-id")},
+			ExpectedErr:    nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs(entity.DefaultLimitsId, "user-id").
+					WillReturnRows(
+						test.NewRows("id_user", "per_transaction", "night_per_transaction", "daily", "monthly", "updated_at").
+							AddRow(entity.DefaultLimitsId, nil, nil, 100000, nil, nil).
+							AddRow("user-id", nil, nil, 100000, nil, nil),
+					)
+			},
+		},
+		"deve retornar erro": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrInternalServerError,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs(entity.DefaultLimitsId, "user-id").
+					WillReturnError(echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseLimit(dbConn)
+			ctx := context.Background()
+
+			limits, err := db.ReadWithDefault(ctx, "user-id")
+			if diff := cmp.Diff(limits, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package outbox
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	"github.com/jmoiron/sqlx"
+	"github.com/labstack/echo/v4"
+)
+
+type DabataseOutboxInterface interface {
+	Create(ctx context.Context, event *entity.Event) error
+	ReadUnpublished(ctx context.Context, limit int) ([]entity.Event, error)
+	UpdatePublished(ctx context.Context, sequences []int64, publishedAt time.Time) error
+	ReadLastSequence(ctx context.Context) (int64, error)
+	ReadAfter(ctx context.Context, sequence int64, limit int) ([]entity.Event, error)
+	ReadUserEventsAfter(ctx context.Context, userId string, sequence int64, limit int) ([]entity.Event, error)
+}
+
+type dbImpl struct {
+	dbConn sqlx.ExtContext
+}
+
+func NewDatabaseOutbox(dbConn sqlx.ExtContext) DabataseOutboxInterface {
+	return &dbImpl{dbConn}
+}
+
+func (o *dbImpl) Create(ctx context.Context, event *entity.Event) error {
+	query := "INSERT INTO outbox (id, type, aggregate_type, aggregate_id, payload, created_at) VALUES (?, ?, ?, ?, ?, ?)"
+
+	_, err := o.dbConn.ExecContext(ctx, query, event.ID, event.Type, event.AggregateType, event.AggregateId, []byte(event.Payload), event.CreatedAt)
+	if err != nil {
+		log.Println("Error create outbox event: ", err.Error())
+		return echo.ErrInternalServerError
+	}
+
+	return nil
+}
+
+// ReadUnpublished reads the oldest events not yet published and locks them, so
+// a second relay waits for the first instead of publishing them again.
+func (o *dbImpl) ReadUnpublished(ctx context.Context, limit int) ([]entity.Event, error) {
+	events := make([]entity.Event, 0)
+	query := "SELECT sequence, id, type, aggregate_type, aggregate_id, payload, created_at, published_at FROM outbox WHERE published_at IS NULL ORDER BY sequence LIMIT ? FOR UPDATE"
+
+	err := sqlx.SelectContext(ctx, o.dbConn, &events, query, limit)
+	if err != nil {
+		log.Println("Error ReadUnpublished outbox: ", err.Error())
+		return nil, echo.ErrInternalServerError
+	}
+
+	return events, nil
+}
+
+func (o *dbImpl) UpdatePublished(ctx context.Context, sequences []int64, publishedAt time.Time) error {
+	placeholders := make([]string, 0, len(sequences))
+	args := make([]interface{}, 0, len(sequences)+1)
+	args = append(args, publishedAt)
+	for _, sequence := range sequences {
+		placeholders = append(placeholders, "?")
+		args = append(args, sequence)
+	}
+
+	query := "UPDATE outbox SET published_at = ? WHERE sequence IN (" + strings.Join(placeholders, ", ") + ")"
+
+	_, err := o.dbConn.ExecContext(ctx, query, args...)
+	if err != nil {
+		log.Println("Error update published outbox: ", err.Error())
+		return echo.ErrInternalServerError
+	}
+
+	return nil
+}
+
+// ReadLastSequence reads the sequence of the last event written, or zero when
+// there's none.
+func (o *dbImpl) ReadLastSequence(ctx context.Context) (int64, error) {
+	var sequence int64
+	query := "SELECT COALESCE(MAX(sequence), 0) FROM outbox"
+
+	err := sqlx.GetContext(ctx, o.dbConn, &sequence, query)
+	if err != nil {
+		log.Println("Error ReadLastSequence outbox: ", err.Error())
+		return 0, echo.ErrInternalServerError
+	}
+
+	return sequence, nil
+}
+
+// ReadAfter reads the events written after sequence, published or not, in
+// order.
+func (o *dbImpl) ReadAfter(ctx context.Context, sequence int64, limit int) ([]entity.Event, error) {
+	events := make([]entity.Event, 0)
+	query := "SELECT sequence, id, type, aggregate_type, aggregate_id, payload, created_at, published_at FROM outbox WHERE sequence > ? ORDER BY sequence LIMIT ?"
+
+	err := sqlx.SelectContext(ctx, o.dbConn, &events, query, sequence, limit)
+	if err != nil {
+		log.Println("Error ReadAfter outbox: ", err.Error())
+		return nil, echo.ErrInternalServerError
+	}
+
+	return events, nil
+}
+
+// ReadUserEventsAfter reads the events of the transactions any account of the
+// user sent or received written after sequence, in order.
+func (o *dbImpl) ReadUserEventsAfter(ctx context.Context, userId string, sequence int64, limit int) ([]entity.Event, error) {
+	events := make([]entity.Event, 0)
+	query := "SELECT sequence, id, type, aggregate_type, aggregate_id, payload, created_at, published_at FROM outbox " +
+		"WHERE sequence > ? AND aggregate_type = ? " +
+		"AND (JSON_UNQUOTE(JSON_EXTRACT(payload, '$.transaction.senderId')) IN (SELECT id FROM accounts WHERE user_id = ?) " +
+		"OR JSON_UNQUOTE(JSON_EXTRACT(payload, '$.transaction.receiverId')) IN (SELECT id FROM accounts WHERE user_id = ?)) " +
+		"ORDER BY sequence LIMIT ?"
+
+	err := sqlx.SelectContext(ctx, o.dbConn, &events, query, sequence, entity.AGGREGATE_TRANSACTION, userId, userId, limit)
+	if err != nil {
+		log.Println("Error ReadUserEventsAfter outbox: ", err.Error())
+		return nil, echo.ErrInternalServerError
+	}
+
+	return events, nil
+}
@@ -0,0 +1,332 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/labstack/echo/v4"
+)
+
+func TestCreate(t *testing.T) {
+	query := "INSERT INTO outbox (id, type, aggregate_type, aggregate_id, payload, created_at) VALUES (?, ?, ?, ?, ?, ?)"
+	createdAt := time.Date(2023, 5, 19, 9, 0, 0, 0, time.UTC)
+
+	event := &entity.Event{
+		ID:            "event-id",
+		Type:          entity.EVENT_USER_CREATED,
+		AggregateType: entity.AGGREGATE_USER,
+		AggregateId:   "user-id",
+		Payload:       json.RawMessage(`{"id":"user-id"}`),
+		CreatedAt:     createdAt,
+	}
+
+	cases := map[string]struct {
+		ExpectedErr error
+		PrepareMock func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			ExpectedErr: nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(query).
+					WithArgs("event-id", "user.created", "user", "user-id", []byte(`{"id":"user-id"}`), createdAt).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+		},
+		"deve retornar erro": {
+			ExpectedErr: echo.ErrInternalServerError,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(query).
+					WithArgs("event-id", "user.created", "user", "user-id", []byte(`{"id":"user-id"}`), createdAt).
+					WillReturnError(echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseOutbox(dbConn)
+			ctx := context.Background()
+
+			err := db.Create(ctx, event)
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestReadUnpublished(t *testing.T) {
+	query := "SELECT sequence, id, type, aggregate_type, aggregate_id, payload, created_at, published_at FROM outbox WHERE published_at IS NULL ORDER BY sequence LIMIT ? FOR UPDATE"
+	columns := []string{"sequence", "id", "type", "aggregate_type", "aggregate_id", "payload", "created_at", "published_at"}
+	createdAt := time.Date(2023, 5, 19, 9, 0, 0, 0, time.UTC)
+
+	cases := map[string]struct {
+		ExpectedResult []entity.Event
+		ExpectedErr    error
+		PrepareMock    func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			ExpectedResult: []entity.Event{{
+				Sequence:      1,
+				ID:            "event-id",
+				Type:          "transaction.booked",
+				AggregateType: "transaction",
+				AggregateId:   "transaction-id",
+				Payload:       json.RawMessage(`{"to":"BOOKED"}`),
+				CreatedAt:     createdAt,
+			}},
+			ExpectedErr: nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs(100).
+					WillReturnRows(test.NewRows(columns...).AddRow(1, "event-id", "transaction.booked", "transaction", "transaction-id", []byte(`{"to":"BOOKED"}`), createdAt, nil))
+			},
+		},
+		"deve retornar erro": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrInternalServerError,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs(100).
+					WillReturnError(echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseOutbox(dbConn)
+			ctx := context.Background()
+
+			events, err := db.ReadUnpublished(ctx, 100)
+			if diff := cmp.Diff(events, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestUpdatePublished(t *testing.T) {
+	query := "UPDATE outbox SET published_at = ? WHERE sequence IN (?, ?)"
+	publishedAt := time.Date(2023, 5, 19, 9, 0, 1, 0, time.UTC)
+
+	cases := map[string]struct {
+		ExpectedErr error
+		PrepareMock func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			ExpectedErr: nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(query).
+					WithArgs(publishedAt, int64(1), int64(2)).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+			},
+		},
+		"deve retornar erro": {
+			ExpectedErr: echo.ErrInternalServerError,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(query).
+					WithArgs(publishedAt, int64(1), int64(2)).
+					WillReturnError(echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseOutbox(dbConn)
+			ctx := context.Background()
+
+			err := db.UpdatePublished(ctx, []int64{1, 2}, publishedAt)
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestReadLastSequence(t *testing.T) {
+	query := "SELECT COALESCE(MAX(sequence), 0) FROM outbox"
+
+	cases := map[string]struct {
+		ExpectedResult int64
+		ExpectedErr    error
+		PrepareMock    func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			ExpectedResult: 42,
+			ExpectedErr:    nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WillReturnRows(test.NewRows("sequence").AddRow(42))
+			},
+		},
+		"deve retornar erro": {
+			ExpectedResult: 0,
+			ExpectedErr:    echo.ErrInternalServerError,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WillReturnError(echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseOutbox(dbConn)
+			ctx := context.Background()
+
+			sequence, err := db.ReadLastSequence(ctx)
+			if diff := cmp.Diff(sequence, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestReadAfter(t *testing.T) {
+	query := "SELECT sequence, id, type, aggregate_type, aggregate_id, payload, created_at, published_at FROM outbox WHERE sequence > ? ORDER BY sequence LIMIT ?"
+	columns := []string{"sequence", "id", "type", "aggregate_type", "aggregate_id", "payload", "created_at", "published_at"}
+	createdAt := time.Date(2023, 5, 19, 9, 0, 0, 0, time.UTC)
+
+	cases := map[string]struct {
+		ExpectedResult []entity.Event
+		ExpectedErr    error
+		PrepareMock    func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			ExpectedResult: []entity.Event{{
+				Sequence:      11,
+				ID:            "event-id",
+				Type:          "transaction.booked",
+				AggregateType: "transaction",
+				AggregateId:   "transaction-id",
+				Payload:       json.RawMessage(`{"to":"BOOKED"}`),
+				CreatedAt:     createdAt,
+				PublishedAt:   &createdAt,
+			}},
+			ExpectedErr: nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs(int64(10), 100).
+					WillReturnRows(test.NewRows(columns...).AddRow(11, "event-id", "transaction.booked", "transaction", "transaction-id", []byte(`{"to":"BOOKED"}`), createdAt, createdAt))
+			},
+		},
+		"deve retornar erro": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrInternalServerError,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs(int64(10), 100).
+					WillReturnError(echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseOutbox(dbConn)
+			ctx := context.Background()
+
+			events, err := db.ReadAfter(ctx, 10, 100)
+			if diff := cmp.Diff(events, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestReadUserEventsAfter(t *testing.T) {
+	query := "SELECT sequence, id, type, aggregate_type, aggregate_id, payload, created_at, published_at FROM outbox " +
+		"WHERE sequence > ? AND aggregate_type = ? " +
+		"AND (JSON_UNQUOTE(JSON_EXTRACT(payload, '$.transaction.senderId')) IN (SELECT id FROM accounts WHERE user_id = ?) " +
+		"OR JSON_UNQUOTE(JSON_EXTRACT(payload, '$.transaction.receiverId')) IN (SELECT id FROM accounts WHERE user_id = ?)) " +
+		"ORDER BY sequence LIMIT ?"
+	columns := []string{"sequence", "id", "type", "aggregate_type", "aggregate_id", "payload", "created_at", "published_at"}
+	createdAt := time.Date(2023, 5, 19, 9, 0, 0, 0, time.UTC)
+
+	cases := map[string]struct {
+		ExpectedResult []entity.Event
+		ExpectedErr    error
+		PrepareMock    func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			ExpectedResult: []entity.Event{{
+				Sequence:      11,
+				ID:            "event-id",
+				Type:          "transaction.booked",
+				AggregateType: "transaction",
+				AggregateId:   "transaction-id",
+				Payload:       json.RawMessage(`{"transaction":{"senderId":"user-id"}}`),
+				CreatedAt:     createdAt,
+			}},
+			ExpectedErr: nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs(int64(10), "transaction", "user-id", "user-id", 100).
+					WillReturnRows(test.NewRows(columns...).AddRow(11, "event-id", "transaction.booked", "transaction", "transaction-id", []byte(`{"transaction":{"senderId":"user-id"}}`), createdAt, nil))
+			},
+		},
+		"deve retornar erro": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrInternalServerError,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs(int64(10), "transaction", "user-id", "user-id", 100).
+					WillReturnError(echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseOutbox(dbConn)
+			ctx := context.Background()
+
+			events, err := db.ReadUserEventsAfter(ctx, "user-id", 10, 100)
+			if diff := cmp.Diff(events, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
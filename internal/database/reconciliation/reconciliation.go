@@ -0,0 +1,152 @@
+package reconciliation
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/money"
+	"github.com/jmoiron/sqlx"
+	"github.com/labstack/echo/v4"
+)
+
+type DabataseReconciliationInterface interface {
+	ReadDrifts(ctx context.Context) ([]entity.ReconciliationDifference, error)
+	ReadExpectedBalance(ctx context.Context, userId string) (money.Money, error)
+	Create(ctx context.Context, reconciliation *entity.Reconciliation) error
+	ReadAll(ctx context.Context) ([]entity.Reconciliation, error)
+	ReadOneById(ctx context.Context, id string) (*entity.Reconciliation, error)
+	ReadDifferences(ctx context.Context, reconciliationId string) ([]entity.ReconciliationDifference, error)
+	UpdateCorrection(ctx context.Context, id int64, approvedBy string, correctedAt time.Time) error
+}
+
+const (
+	// Balances are recomputed from the postings, the same source the ledger
+	// check holds accounts.balance against, so the two never disagree.
+	driftsQuery = "SELECT a.id AS user_id, a.balance, COALESCE(SUM(p.amount), 0) AS expected_balance FROM accounts a " +
+		"LEFT JOIN postings p ON p.account_id = a.id GROUP BY a.id, a.balance HAVING a.balance <> expected_balance ORDER BY a.id"
+	// The locking read sees the postings committed after the unit of work
+	// began, the same ones the balance of the locked account row reflects.
+	expectedBalanceQuery = "SELECT COALESCE(SUM(amount), 0) FROM postings WHERE account_id = ? LOCK IN SHARE MODE"
+	readQuery            = "SELECT r.id, r.created_at, COUNT(d.id) AS drifted_users FROM reconciliations r " +
+		"LEFT JOIN reconciliation_differences d ON d.reconciliation_id = r.id"
+)
+
+type dbImpl struct {
+	dbConn sqlx.ExtContext
+}
+
+func NewDatabaseReconciliation(dbConn sqlx.ExtContext) DabataseReconciliationInterface {
+	return &dbImpl{dbConn}
+}
+
+// ReadDrifts recomputes the balance of every account from its postings and
+// lists the accounts whose stored balance differs from it.
+func (r *dbImpl) ReadDrifts(ctx context.Context) ([]entity.ReconciliationDifference, error) {
+	differences := make([]entity.ReconciliationDifference, 0)
+
+	err := sqlx.SelectContext(ctx, r.dbConn, &differences, driftsQuery)
+	if err != nil {
+		log.Println("Error ReadDrifts reconciliation: ", err.Error())
+		return nil, echo.ErrInternalServerError
+	}
+
+	return differences, nil
+}
+
+// ReadExpectedBalance recomputes the balance of the account from its postings.
+func (r *dbImpl) ReadExpectedBalance(ctx context.Context, userId string) (money.Money, error) {
+	var balance money.Money
+
+	err := sqlx.GetContext(ctx, r.dbConn, &balance, expectedBalanceQuery, userId)
+	if err != nil {
+		log.Println("Error ReadExpectedBalance reconciliation: ", err.Error())
+		return money.Money{}, echo.ErrInternalServerError
+	}
+
+	return balance, nil
+}
+
+func (r *dbImpl) Create(ctx context.Context, reconciliation *entity.Reconciliation) error {
+	query := "INSERT INTO reconciliations (id, created_at) VALUES (?, ?)"
+
+	_, err := r.dbConn.ExecContext(ctx, query, reconciliation.ID, reconciliation.CreatedAt)
+	if err != nil {
+		log.Println("Error create reconciliation: ", err.Error())
+		return echo.ErrInternalServerError
+	}
+
+	if len(reconciliation.Differences) == 0 {
+		return nil
+	}
+
+	values := make([]string, 0, len(reconciliation.Differences))
+	args := make([]interface{}, 0, len(reconciliation.Differences)*5)
+	for _, difference := range reconciliation.Differences {
+		values = append(values, "(?, ?, ?, ?, ?)")
+		args = append(args, difference.ReconciliationId, difference.UserId, difference.Balance, difference.ExpectedBalance, difference.Difference)
+	}
+
+	query = "INSERT INTO reconciliation_differences (reconciliation_id, user_id, balance, expected_balance, difference) VALUES " + strings.Join(values, ", ")
+
+	_, err = r.dbConn.ExecContext(ctx, query, args...)
+	if err != nil {
+		log.Println("Error create reconciliation differences: ", err.Error())
+		return echo.ErrInternalServerError
+	}
+
+	return nil
+}
+
+func (r *dbImpl) ReadAll(ctx context.Context) ([]entity.Reconciliation, error) {
+	reconciliations := make([]entity.Reconciliation, 0)
+	query := readQuery + " GROUP BY r.id, r.created_at ORDER BY r.created_at DESC"
+
+	err := sqlx.SelectContext(ctx, r.dbConn, &reconciliations, query)
+	if err != nil {
+		log.Println("Error ReadAll reconciliation: ", err.Error())
+		return nil, echo.ErrInternalServerError
+	}
+
+	return reconciliations, nil
+}
+
+func (r *dbImpl) ReadOneById(ctx context.Context, id string) (*entity.Reconciliation, error) {
+	reconciliation := new(entity.Reconciliation)
+	query := readQuery + " WHERE r.id = ? GROUP BY r.id, r.created_at"
+
+	err := sqlx.GetContext(ctx, r.dbConn, reconciliation, query, id)
+	if err != nil {
+		log.Println("Error ReadOneById reconciliation: ", err.Error())
+		return nil, echo.ErrNotFound
+	}
+
+	return reconciliation, nil
+}
+
+func (r *dbImpl) ReadDifferences(ctx context.Context, reconciliationId string) ([]entity.ReconciliationDifference, error) {
+	differences := make([]entity.ReconciliationDifference, 0)
+	query := "SELECT id, reconciliation_id, user_id, balance, expected_balance, difference, approved_by, corrected_at FROM reconciliation_differences WHERE reconciliation_id = ? ORDER BY id"
+
+	err := sqlx.SelectContext(ctx, r.dbConn, &differences, query, reconciliationId)
+	if err != nil {
+		log.Println("Error ReadDifferences reconciliation: ", err.Error())
+		return nil, echo.ErrInternalServerError
+	}
+
+	return differences, nil
+}
+
+func (r *dbImpl) UpdateCorrection(ctx context.Context, id int64, approvedBy string, correctedAt time.Time) error {
+	query := "UPDATE reconciliation_differences SET approved_by = ?, corrected_at = ? WHERE id = ?"
+
+	_, err := r.dbConn.ExecContext(ctx, query, approvedBy, correctedAt, id)
+	if err != nil {
+		log.Println("Error update correction reconciliation: ", err.Error())
+		return echo.ErrInternalServerError
+	}
+
+	return nil
+}
@@ -0,0 +1,339 @@
+package reconciliation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/test"
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/money"
+	"github.com/google/go-cmp/cmp"
+	"github.com/labstack/echo/v4"
+)
+
+func TestReadDrifts(t *testing.T) {
+	query := "SELECT a.id AS user_id, a.balance, COALESCE(SUM(p.amount), 0) AS expected_balance FROM accounts a " +
+		"LEFT JOIN postings p ON p.account_id = a.id GROUP BY a.id, a.balance HAVING a.balance <> expected_balance ORDER BY a.id"
+
+	cases := map[string]struct {
+		ExpectedResult []entity.ReconciliationDifference
+		ExpectedErr    error
+		PrepareMock    func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			ExpectedResult: []entity.ReconciliationDifference{
+				{UserId: "user-id", Balance: money.New(10010), ExpectedBalance: money.New(9010)},
+			},
+			ExpectedErr: nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WillReturnRows(test.NewRows("user_id", "balance", "expected_balance").AddRow("user-id", int64(10010), int64(9010)))
+			},
+		},
+		"deve retornar erro": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrInternalServerError,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WillReturnError(echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseReconciliation(dbConn)
+			ctx := context.Background()
+
+			differences, err := db.ReadDrifts(ctx)
+			if diff := cmp.Diff(differences, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestReadExpectedBalance(t *testing.T) {
+	query := "SELECT COALESCE(SUM(amount), 0) FROM postings WHERE account_id = ? LOCK IN SHARE MODE"
+
+	cases := map[string]struct {
+		ExpectedResult money.Money
+		ExpectedErr    error
+		PrepareMock    func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			ExpectedResult: money.New(9010),
+			ExpectedErr:    nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs("user-id").
+					WillReturnRows(test.NewRows("balance").AddRow(int64(9010)))
+			},
+		},
+		"deve retornar erro": {
+			ExpectedResult: money.Money{},
+			ExpectedErr:    echo.ErrInternalServerError,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs("user-id").
+					WillReturnError(echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseReconciliation(dbConn)
+			ctx := context.Background()
+
+			balance, err := db.ReadExpectedBalance(ctx, "user-id")
+			if diff := cmp.Diff(balance, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	query := "INSERT INTO reconciliations (id, created_at) VALUES (?, ?)"
+	differencesQuery := "INSERT INTO reconciliation_differences (reconciliation_id, user_id, balance, expected_balance, difference) VALUES (?, ?, ?, ?, ?)"
+
+	createdAt := time.Date(2023, 5, 17, 9, 0, 0, 0, time.UTC)
+	reconciliation := &entity.Reconciliation{
+		ID:           "reconciliation-id",
+		DriftedUsers: 1,
+		Differences: []entity.ReconciliationDifference{
+			{ReconciliationId: "reconciliation-id", UserId: "user-id", Balance: money.New(10010), ExpectedBalance: money.New(9010), Difference: money.New(-1000)},
+		},
+		CreatedAt: &createdAt,
+	}
+
+	cases := map[string]struct {
+		Input       *entity.Reconciliation
+		ExpectedErr error
+		PrepareMock func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			Input:       reconciliation,
+			ExpectedErr: nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(query).
+					WithArgs("reconciliation-id", &createdAt).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(differencesQuery).
+					WithArgs("reconciliation-id", "user-id", money.New(10010), money.New(9010), money.New(-1000)).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+		},
+		"deve retornar sucesso: sem diferencas": {
+			Input:       &entity.Reconciliation{ID: "reconciliation-id", CreatedAt: &createdAt},
+			ExpectedErr: nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(query).
+					WithArgs("reconciliation-id", &createdAt).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+		},
+		"deve retornar erro: diferencas": {
+			Input:       reconciliation,
+			ExpectedErr: echo.ErrInternalServerError,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(query).
+					WithArgs("reconciliation-id", &createdAt).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(differencesQuery).
+					WithArgs("reconciliation-id", "user-id", money.New(10010), money.New(9010), money.New(-1000)).
+					WillReturnError(echo.ErrInternalServerError)
+			},
+		},
+		"deve retornar erro": {
+			Input:       reconciliation,
+			ExpectedErr: echo.ErrInternalServerError,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(query).
+					WithArgs("reconciliation-id", &createdAt).
+					WillReturnError(echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseReconciliation(dbConn)
+			ctx := context.Background()
+
+			err := db.Create(ctx, cs.Input)
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestReadOneById(t *testing.T) {
+	query := "SELECT r.id, r.created_at, COUNT(d.id) AS drifted_users FROM reconciliations r " +
+		"LEFT JOIN reconciliation_differences d ON d.reconciliation_id = r.id WHERE r.id = ? GROUP BY r.id, r.created_at"
+	createdAt := time.Date(2023, 5, 17, 9, 0, 0, 0, time.UTC)
+
+	cases := map[string]struct {
+		ExpectedResult *entity.Reconciliation
+		ExpectedErr    error
+		PrepareMock    func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			ExpectedResult: &entity.Reconciliation{ID: "reconciliation-id", DriftedUsers: 2, CreatedAt: &createdAt},
+			ExpectedErr:    nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs("reconciliation-id").
+					WillReturnRows(test.NewRows("id", "created_at", "drifted_users").AddRow("reconciliation-id", createdAt, 2))
+			},
+		},
+		"deve retornar erro": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrNotFound,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs("reconciliation-id").
+					WillReturnError(echo.ErrNotFound)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseReconciliation(dbConn)
+			ctx := context.Background()
+
+			reconciliation, err := db.ReadOneById(ctx, "reconciliation-id")
+			if diff := cmp.Diff(reconciliation, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestReadDifferences(t *testing.T) {
+	query := "SELECT id, reconciliation_id, user_id, balance, expected_balance, difference, approved_by, corrected_at FROM reconciliation_differences WHERE reconciliation_id = ? ORDER BY id"
+	approvedBy := "operator"
+	correctedAt := time.Date(2023, 5, 17, 10, 0, 0, 0, time.UTC)
+
+	cases := map[string]struct {
+		ExpectedResult []entity.ReconciliationDifference
+		ExpectedErr    error
+		PrepareMock    func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			ExpectedResult: []entity.ReconciliationDifference{
+				{ID: 1, ReconciliationId: "reconciliation-id", UserId: "user-id", Balance: money.New(10010), ExpectedBalance: money.New(9010), Difference: money.New(-1000), ApprovedBy: &approvedBy, CorrectedAt: &correctedAt},
+			},
+			ExpectedErr: nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs("reconciliation-id").
+					WillReturnRows(test.NewRows("id", "reconciliation_id", "user_id", "balance", "expected_balance", "difference", "approved_by", "corrected_at").
+						AddRow(1, "reconciliation-id", "user-id", int64(10010), int64(9010), int64(-1000), approvedBy, correctedAt))
+			},
+		},
+		"deve retornar erro": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrInternalServerError,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs("reconciliation-id").
+					WillReturnError(echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseReconciliation(dbConn)
+			ctx := context.Background()
+
+			differences, err := db.ReadDifferences(ctx, "reconciliation-id")
+			if diff := cmp.Diff(differences, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestUpdateCorrection(t *testing.T) {
+	query := "UPDATE reconciliation_differences SET approved_by = ?, corrected_at = ? WHERE id = ?"
+	correctedAt := time.Date(2023, 5, 17, 10, 0, 0, 0, time.UTC)
+
+	cases := map[string]struct {
+		ExpectedErr error
+		PrepareMock func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			ExpectedErr: nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(query).
+					WithArgs("operator", correctedAt, int64(1)).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+		},
+		"deve retornar erro": {
+			ExpectedErr: echo.ErrInternalServerError,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(query).
+					WithArgs("operator", correctedAt, int64(1)).
+					WillReturnError(echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseReconciliation(dbConn)
+			ctx := context.Background()
+
+			err := db.UpdateCorrection(ctx, 1, "operator", correctedAt)
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
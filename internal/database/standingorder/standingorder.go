@@ -0,0 +1,163 @@
+package standingorder
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	"github.com/jmoiron/sqlx"
+	"github.com/labstack/echo/v4"
+)
+
+type DabataseStandingOrderInterface interface {
+	Create(ctx context.Context, order *entity.StandingOrder) error
+	Update(ctx context.Context, order *entity.StandingOrder) error
+	ReadAll(ctx context.Context) ([]entity.StandingOrder, error)
+	ReadOneById(ctx context.Context, id string) (*entity.StandingOrder, error)
+	ReadOneByIdForUpdate(ctx context.Context, id string) (*entity.StandingOrder, error)
+	ReadDue(ctx context.Context, now time.Time) ([]entity.StandingOrder, error)
+	CreateOccurrence(ctx context.Context, occurrence *entity.StandingOrderOccurrence) error
+	ReadOccurrences(ctx context.Context, standingOrderId string) ([]entity.StandingOrderOccurrence, error)
+}
+
+type dbImpl struct {
+	dbConn sqlx.ExtContext
+}
+
+func NewDatabaseStandingOrder(dbConn sqlx.ExtContext) DabataseStandingOrderInterface {
+	return &dbImpl{dbConn}
+}
+
+func (s *dbImpl) Create(ctx context.Context, order *entity.StandingOrder) error {
+	query := "INSERT INTO standing_orders (id, id_source, id_destination, amount, frequency, start_at, end_at, count, next_run_at, state) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+
+	_, err := s.dbConn.ExecContext(ctx, query,
+		order.ID,
+		order.SourceId,
+		order.DestinationId,
+		order.Amount,
+		order.Frequency,
+		order.StartAt,
+		order.EndAt,
+		order.Count,
+		order.NextRunAt,
+		order.State,
+	)
+	if err != nil {
+		log.Println("Error create standing order: ", err.Error())
+		return echo.ErrInternalServerError
+	}
+
+	return nil
+}
+
+func (s *dbImpl) Update(ctx context.Context, order *entity.StandingOrder) error {
+	query := "UPDATE standing_orders SET amount = ?, end_at = ?, count = ?, occurrences = ?, attempts = ?, next_run_at = ?, state = ? WHERE id = ?"
+
+	_, err := s.dbConn.ExecContext(ctx, query,
+		order.Amount,
+		order.EndAt,
+		order.Count,
+		order.Occurrences,
+		order.Attempts,
+		order.NextRunAt,
+		order.State,
+		order.ID,
+	)
+	if err != nil {
+		log.Println("Error update standing order: ", err.Error())
+		return echo.ErrInternalServerError
+	}
+
+	return nil
+}
+
+func (s *dbImpl) ReadAll(ctx context.Context) ([]entity.StandingOrder, error) {
+	orders := make([]entity.StandingOrder, 0)
+	query := "SELECT id, id_source, id_destination, amount, frequency, start_at, end_at, count, occurrences, attempts, next_run_at, state, created_at, updated_at FROM standing_orders ORDER BY created_at DESC"
+
+	err := sqlx.SelectContext(ctx, s.dbConn, &orders, query)
+	if err != nil {
+		log.Println("Error ReadAll standing orders: ", err.Error())
+		return nil, echo.ErrInternalServerError
+	}
+
+	return orders, nil
+}
+
+func (s *dbImpl) ReadOneById(ctx context.Context, id string) (*entity.StandingOrder, error) {
+	order := new(entity.StandingOrder)
+	query := "SELECT id, id_source, id_destination, amount, frequency, start_at, end_at, count, occurrences, attempts, next_run_at, state, created_at, updated_at FROM standing_orders WHERE id = ?"
+
+	err := sqlx.GetContext(ctx, s.dbConn, order, query, id)
+	if err != nil {
+		log.Println("Error ReadOneById standing order: ", err.Error())
+		return nil, echo.ErrNotFound
+	}
+
+	return order, nil
+}
+
+// ReadOneByIdForUpdate locks the standing order row until the surrounding
+// transaction ends. It must be called from a Container bound to a unit of work.
+func (s *dbImpl) ReadOneByIdForUpdate(ctx context.Context, id string) (*entity.StandingOrder, error) {
+	order := new(entity.StandingOrder)
+	query := "SELECT id, id_source, id_destination, amount, frequency, start_at, end_at, count, occurrences, attempts, next_run_at, state, created_at, updated_at FROM standing_orders WHERE id = ? FOR UPDATE"
+
+	err := sqlx.GetContext(ctx, s.dbConn, order, query, id)
+	if err != nil {
+		log.Println("Error ReadOneByIdForUpdate standing order: ", err.Error())
+		return nil, echo.ErrNotFound
+	}
+
+	return order, nil
+}
+
+// ReadDue lists the active standing orders whose next occurrence has been
+// reached, oldest first.
+func (s *dbImpl) ReadDue(ctx context.Context, now time.Time) ([]entity.StandingOrder, error) {
+	orders := make([]entity.StandingOrder, 0)
+	query := "SELECT id, id_source, id_destination, amount, frequency, start_at, end_at, count, occurrences, attempts, next_run_at, state, created_at, updated_at FROM standing_orders WHERE state = ? AND next_run_at <= ? ORDER BY next_run_at"
+
+	err := sqlx.SelectContext(ctx, s.dbConn, &orders, query, entity.ORDER_ACTIVE, now)
+	if err != nil {
+		log.Println("Error ReadDue standing orders: ", err.Error())
+		return nil, echo.ErrInternalServerError
+	}
+
+	return orders, nil
+}
+
+func (s *dbImpl) CreateOccurrence(ctx context.Context, occurrence *entity.StandingOrderOccurrence) error {
+	query := "INSERT INTO standing_order_occurrences (standing_order_id, sequence, transaction_id, state, attempts, reason, scheduled_at) VALUES (?, ?, ?, ?, ?, ?, ?)"
+
+	_, err := s.dbConn.ExecContext(ctx, query,
+		occurrence.StandingOrderId,
+		occurrence.Sequence,
+		occurrence.TransactionId,
+		occurrence.State,
+		occurrence.Attempts,
+		occurrence.Reason,
+		occurrence.ScheduledAt,
+	)
+	if err != nil {
+		log.Println("Error create standing order occurrence: ", err.Error())
+		return echo.ErrInternalServerError
+	}
+
+	return nil
+}
+
+func (s *dbImpl) ReadOccurrences(ctx context.Context, standingOrderId string) ([]entity.StandingOrderOccurrence, error) {
+	occurrences := make([]entity.StandingOrderOccurrence, 0)
+	query := "SELECT id, standing_order_id, sequence, transaction_id, state, attempts, reason, scheduled_at, created_at FROM standing_order_occurrences WHERE standing_order_id = ? ORDER BY sequence"
+
+	err := sqlx.SelectContext(ctx, s.dbConn, &occurrences, query, standingOrderId)
+	if err != nil {
+		log.Println("Error ReadOccurrences standing order: ", err.Error())
+		return nil, echo.ErrInternalServerError
+	}
+
+	return occurrences, nil
+}
@@ -0,0 +1,395 @@
+package standingorder
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/test"
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/money"
+	"github.com/google/go-cmp/cmp"
+	"github.com/labstack/echo/v4"
+)
+
+const columns = "id, id_source, id_destination, amount, frequency, start_at, end_at, count, occurrences, attempts, next_run_at, state, created_at, updated_at"
+
+func newOrder() *entity.StandingOrder {
+	startAt := time.Date(2023, 5, 10, 12, 0, 0, 0, time.UTC)
+	count := 8
+
+	return &entity.StandingOrder{
+		ID:            "standing-order-id",
+		SourceId:      "source-user-id",
+		DestinationId: "destination-user-id",
+		Amount:        money.New(15000),
+		Frequency:     entity.MONTHLY,
+		StartAt:       startAt,
+		Count:         &count,
+		NextRunAt:     &startAt,
+		State:         entity.ORDER_ACTIVE,
+	}
+}
+
+func orderRows(order *entity.StandingOrder) *sqlmock.Rows {
+	return test.NewRows("id", "id_source", "id_destination", "amount", "frequency", "start_at", "end_at", "count", "occurrences", "attempts", "next_run_at", "state", "created_at", "updated_at").
+		AddRow(order.ID, order.SourceId, order.DestinationId, 15000, order.Frequency, order.StartAt, nil, *order.Count, 0, 0, *order.NextRunAt, order.State, nil, nil)
+}
+
+func TestCreate(t *testing.T) {
+	query := "INSERT INTO standing_orders (id, id_source, id_destination, amount, frequency, start_at, end_at, count, next_run_at, state) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+
+	order := newOrder()
+
+	cases := map[string]struct {
+		ExpectedErr error
+		PrepareMock func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			ExpectedErr: nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(query).
+					WithArgs(order.ID, order.SourceId, order.DestinationId, order.Amount, order.Frequency, order.StartAt, order.EndAt, order.Count, order.NextRunAt, order.State).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+		},
+		"deve retornar erro": {
+			ExpectedErr: echo.ErrInternalServerError,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(query).
+					WithArgs(order.ID, order.SourceId, order.DestinationId, order.Amount, order.Frequency, order.StartAt, order.EndAt, order.Count, order.NextRunAt, order.State).
+					WillReturnError(echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseStandingOrder(dbConn)
+			ctx := context.Background()
+
+			err := db.Create(ctx, order)
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	query := "UPDATE standing_orders SET amount = ?, end_at = ?, count = ?, occurrences = ?, attempts = ?, next_run_at = ?, state = ? WHERE id = ?"
+
+	order := newOrder()
+	order.CompleteOccurrence()
+
+	cases := map[string]struct {
+		ExpectedErr error
+		PrepareMock func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			ExpectedErr: nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(query).
+					WithArgs(order.Amount, order.EndAt, order.Count, 1, 0, order.NextRunAt, order.State, order.ID).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+		},
+		"deve retornar erro": {
+			ExpectedErr: echo.ErrInternalServerError,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(query).
+					WithArgs(order.Amount, order.EndAt, order.Count, 1, 0, order.NextRunAt, order.State, order.ID).
+					WillReturnError(echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseStandingOrder(dbConn)
+			ctx := context.Background()
+
+			err := db.Update(ctx, order)
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestReadAll(t *testing.T) {
+	query := "SELECT " + columns + " FROM standing_orders ORDER BY created_at DESC"
+
+	order := newOrder()
+
+	cases := map[string]struct {
+		ExpectedResult []entity.StandingOrder
+		ExpectedErr    error
+		PrepareMock    func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			ExpectedResult: []entity.StandingOrder{*order},
+			ExpectedErr:    nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).WillReturnRows(orderRows(order))
+			},
+		},
+		"deve retornar erro": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrInternalServerError,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).WillReturnError(echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseStandingOrder(dbConn)
+			ctx := context.Background()
+
+			orders, err := db.ReadAll(ctx)
+			if diff := cmp.Diff(orders, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestReadOneById(t *testing.T) {
+	order := newOrder()
+
+	cases := map[string]struct {
+		Query          string
+		ForUpdate      bool
+		ExpectedResult *entity.StandingOrder
+		ExpectedErr    error
+		PrepareMock    func(mock sqlmock.Sqlmock, query string)
+	}{
+		"deve retornar sucesso": {
+			Query:          "SELECT " + columns + " FROM standing_orders WHERE id = ?",
+			ExpectedResult: order,
+			ExpectedErr:    nil,
+			PrepareMock: func(mock sqlmock.Sqlmock, query string) {
+				mock.ExpectQuery(query).WithArgs(order.ID).WillReturnRows(orderRows(order))
+			},
+		},
+		"deve retornar erro": {
+			Query:          "SELECT " + columns + " FROM standing_orders WHERE id = ?",
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrNotFound,
+			PrepareMock: func(mock sqlmock.Sqlmock, query string) {
+				mock.ExpectQuery(query).WithArgs(order.ID).WillReturnError(echo.ErrInternalServerError)
+			},
+		},
+		"deve retornar sucesso: for update": {
+			Query:          "SELECT " + columns + " FROM standing_orders WHERE id = ? FOR UPDATE",
+			ForUpdate:      true,
+			ExpectedResult: order,
+			ExpectedErr:    nil,
+			PrepareMock: func(mock sqlmock.Sqlmock, query string) {
+				mock.ExpectQuery(query).WithArgs(order.ID).WillReturnRows(orderRows(order))
+			},
+		},
+		"deve retornar erro: for update": {
+			Query:          "SELECT " + columns + " FROM standing_orders WHERE id = ? FOR UPDATE",
+			ForUpdate:      true,
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrNotFound,
+			PrepareMock: func(mock sqlmock.Sqlmock, query string) {
+				mock.ExpectQuery(query).WithArgs(order.ID).WillReturnError(echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock, cs.Query)
+
+			db := NewDatabaseStandingOrder(dbConn)
+			ctx := context.Background()
+
+			read := db.ReadOneById
+			if cs.ForUpdate {
+				read = db.ReadOneByIdForUpdate
+			}
+
+			order, err := read(ctx, "standing-order-id")
+			if diff := cmp.Diff(order, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestReadDue(t *testing.T) {
+	query := "SELECT " + columns + " FROM standing_orders WHERE state = ? AND next_run_at <= ? ORDER BY next_run_at"
+
+	now := time.Now()
+	order := newOrder()
+
+	cases := map[string]struct {
+		ExpectedResult []entity.StandingOrder
+		ExpectedErr    error
+		PrepareMock    func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			ExpectedResult: []entity.StandingOrder{*order},
+			ExpectedErr:    nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).WithArgs(entity.ORDER_ACTIVE, now).WillReturnRows(orderRows(order))
+			},
+		},
+		"deve retornar erro": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrInternalServerError,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).WithArgs(entity.ORDER_ACTIVE, now).WillReturnError(echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseStandingOrder(dbConn)
+			ctx := context.Background()
+
+			orders, err := db.ReadDue(ctx, now)
+			if diff := cmp.Diff(orders, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestCreateOccurrence(t *testing.T) {
+	query := "INSERT INTO standing_order_occurrences (standing_order_id, sequence, transaction_id, state, attempts, reason, scheduled_at) VALUES (?, ?, ?, ?, ?, ?, ?)"
+
+	occurrence := newOrder().NewOccurrence(&entity.Transaction{ID: "transaction-id", State: entity.BOOKED}, "")
+
+	cases := map[string]struct {
+		ExpectedErr error
+		PrepareMock func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			ExpectedErr: nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(query).
+					WithArgs("standing-order-id", 1, "transaction-id", entity.BOOKED, 1, "", occurrence.ScheduledAt).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+		},
+		"deve retornar erro": {
+			ExpectedErr: echo.ErrInternalServerError,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(query).
+					WithArgs("standing-order-id", 1, "transaction-id", entity.BOOKED, 1, "", occurrence.ScheduledAt).
+					WillReturnError(echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseStandingOrder(dbConn)
+			ctx := context.Background()
+
+			err := db.CreateOccurrence(ctx, occurrence)
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestReadOccurrences(t *testing.T) {
+	query := "SELECT id, standing_order_id, sequence, transaction_id, state, attempts, reason, scheduled_at, created_at FROM standing_order_occurrences WHERE standing_order_id = ? ORDER BY sequence"
+
+	scheduledAt := time.Date(2023, 5, 10, 12, 0, 0, 0, time.UTC)
+	occurrences := []entity.StandingOrderOccurrence{{
+		ID:              1,
+		StandingOrderId: "standing-order-id",
+		Sequence:        1,
+		TransactionId:   "transaction-id",
+		State:           entity.FAILED,
+		Attempts:        3,
+		Reason:          "Insufficient balance",
+		ScheduledAt:     scheduledAt,
+	}}
+
+	cases := map[string]struct {
+		ExpectedResult []entity.StandingOrderOccurrence
+		ExpectedErr    error
+		PrepareMock    func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			ExpectedResult: occurrences,
+			ExpectedErr:    nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs("standing-order-id").
+					WillReturnRows(
+						test.NewRows("id", "standing_order_id", "sequence", "transaction_id", "state", "attempts", "reason", "scheduled_at", "created_at").
+							AddRow(1, "standing-order-id", 1, "transaction-id", entity.FAILED, 3, "Insufficient balance", scheduledAt, nil),
+					)
+			},
+		},
+		"deve retornar erro": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrInternalServerError,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs("standing-order-id").
+					WillReturnError(echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseStandingOrder(dbConn)
+			ctx := context.Background()
+
+			occurrences, err := db.ReadOccurrences(ctx, "standing-order-id")
+			if diff := cmp.Diff(occurrences, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
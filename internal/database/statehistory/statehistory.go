@@ -0,0 +1,53 @@
+package statehistory
+
+import (
+	"context"
+	"log"
+
+	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	"github.com/jmoiron/sqlx"
+	"github.com/labstack/echo/v4"
+)
+
+type DabataseStateHistoryInterface interface {
+	Create(ctx context.Context, transition *entity.StateTransition) error
+	ReadByTransactionId(ctx context.Context, transactionId string) ([]entity.StateTransition, error)
+}
+
+type dbImpl struct {
+	dbConn sqlx.ExtContext
+}
+
+func NewDatabaseStateHistory(dbConn sqlx.ExtContext) DabataseStateHistoryInterface {
+	return &dbImpl{dbConn}
+}
+
+func (h *dbImpl) Create(ctx context.Context, transition *entity.StateTransition) error {
+	query := "INSERT INTO transaction_state_history (transaction_id, from_state, to_state, reason) VALUES (?, ?, ?, ?)"
+
+	_, err := h.dbConn.ExecContext(ctx, query,
+		transition.TransactionId,
+		transition.From,
+		transition.To,
+		transition.Reason,
+	)
+	if err != nil {
+		log.Println("Error create state history: ", err.Error())
+		return echo.ErrInternalServerError
+	}
+
+	return nil
+}
+
+func (h *dbImpl) ReadByTransactionId(ctx context.Context, transactionId string) ([]entity.StateTransition, error) {
+	transitions := make([]entity.StateTransition, 0)
+	query := "SELECT id, transaction_id, from_state, to_state, reason, created_at FROM transaction_state_history WHERE transaction_id = ? ORDER BY id"
+
+	err := sqlx.SelectContext(ctx, h.dbConn, &transitions, query, transactionId)
+	if err != nil {
+		log.Println("Error ReadByTransactionId state history: ", err.Error())
+		return nil, echo.ErrInternalServerError
+	}
+
+	return transitions, nil
+}
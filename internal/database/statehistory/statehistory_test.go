@@ -0,0 +1,114 @@
+package statehistory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/labstack/echo/v4"
+)
+
+func TestCreate(t *testing.T) {
+	query := "INSERT INTO transaction_state_history (transaction_id, from_state, to_state, reason) VALUES (?, ?, ?, ?)"
+
+	transition, _ := entity.NewStateTransition("transaction-id", entity.PENDING, entity.BOOKED, "transfer booked")
+
+	cases := map[string]struct {
+		ExpectedErr error
+		PrepareMock func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			ExpectedErr: nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(query).
+					WithArgs("transaction-id", entity.PENDING, entity.BOOKED, "transfer booked").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+		},
+		"deve retornar erro": {
+			ExpectedErr: echo.ErrInternalServerError,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(query).
+					WithArgs("transaction-id", entity.PENDING, entity.BOOKED, "transfer booked").
+					WillReturnError(echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseStateHistory(dbConn)
+			ctx := context.Background()
+
+			err := db.Create(ctx, transition)
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestReadByTransactionId(t *testing.T) {
+	query := "SELECT id, transaction_id, from_state, to_state, reason, created_at FROM transaction_state_history WHERE transaction_id = ? ORDER BY id"
+
+	transitions := []entity.StateTransition{{
+		ID:            1,
+		TransactionId: "transaction-id",
+		From:          entity.PENDING,
+		To:            entity.BOOKED,
+		Reason:        "transfer booked",
+	}}
+
+	cases := map[string]struct {
+		ExpectedResult []entity.StateTransition
+		ExpectedErr    error
+		PrepareMock    func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			ExpectedResult: transitions,
+			ExpectedErr:    nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs("transaction-id").
+					WillReturnRows(
+						test.NewRows("id", "transaction_id", "from_state", "to_state", "reason", "created_at").
+							AddRow(1, "transaction-id", entity.PENDING, entity.BOOKED, "transfer booked", nil),
+					)
+			},
+		},
+		"deve retornar erro": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrInternalServerError,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs("transaction-id").
+					WillReturnError(echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseStateHistory(dbConn)
+			ctx := context.Background()
+
+			transitions, err := db.ReadByTransactionId(ctx, "transaction-id")
+			if diff := cmp.Diff(transitions, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
@@ -2,10 +2,11 @@ package transaction
 
 import (
 	"context"
-	"database/sql"
 	"log"
+	"time"
 
 	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/money"
 	"github.com/jmoiron/sqlx"
 	"github.com/labstack/echo/v4"
 )
@@ -13,116 +14,263 @@ import (
 type DabataseTransactionInterface interface {
 	Create(ctx context.Context, transaction *entity.Transaction) error
 	UpdateState(ctx context.Context, state entity.StatesTransaction, id string) error
-	ReadBalance(ctx context.Context, userId string) (float64, error)
-	UpdateBalanceUser(ctx context.Context, userId string, value float64) error
+	UpdateAmount(ctx context.Context, id string, amount money.Money) error
 	ReadAll(ctx context.Context) ([]entity.Transaction, error)
+	ReadOneById(ctx context.Context, id string) (*entity.Transaction, error)
+	ReadOneByIdForUpdate(ctx context.Context, id string) (*entity.Transaction, error)
+	ReadReversedAmount(ctx context.Context, originalId string) (money.Money, error)
+	ReadSentAmount(ctx context.Context, userId string, since time.Time) (money.Money, error)
+	ReadExpiredAuthorizations(ctx context.Context, now time.Time) ([]entity.Transaction, error)
+	ReadDueScheduled(ctx context.Context, now time.Time) ([]entity.Transaction, error)
+	ReadAllByState(ctx context.Context, state entity.StatesTransaction) ([]entity.Transaction, error)
+	ReadAllByParent(ctx context.Context, parentId string) ([]entity.Transaction, error)
+	ReadStatementLines(ctx context.Context, accountId string, start, end time.Time) ([]entity.StatementLine, error)
+	StreamStatementLines(ctx context.Context, accountId string, start, end time.Time, fn func(line entity.StatementLine) error) error
 }
 
 type dbImpl struct {
-	dbConn *sqlx.DB
+	dbConn sqlx.ExtContext
 }
 
-func NewDatabaseTransaction(dbConn *sqlx.DB) DabataseTransactionInterface {
+func NewDatabaseTransaction(dbConn sqlx.ExtContext) DabataseTransactionInterface {
 	return &dbImpl{dbConn}
 }
 
 func (tr *dbImpl) Create(ctx context.Context, transaction *entity.Transaction) error {
-	tx, _ := tr.dbConn.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
-	query := "INSERT INTO transactions (id, id_source, id_destination, amount, state) VALUES (?, ?, ?, ?, ?)"
+	query := "INSERT INTO transactions (id, id_source, id_destination, amount, kind, id_original, id_parent, state, expires_at, execute_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
 
-	_, err := tx.ExecContext(ctx, query,
+	_, err := tr.dbConn.ExecContext(ctx, query,
 		transaction.ID,
 		transaction.SourceId,
 		transaction.DestinationId,
 		transaction.Amount,
+		transaction.Kind,
+		transaction.OriginalId,
+		transaction.ParentId,
 		transaction.State,
+		transaction.ExpiresAt,
+		transaction.ExecuteAt,
 	)
 	if err != nil {
-		tx.Rollback()
 		log.Println("Error create transaction: ", err.Error())
 		return echo.ErrInternalServerError
 	}
 
-	err = tx.Commit()
-	if err != nil {
-		log.Println("Error create transaction tx.Commit: ", err.Error())
-		return echo.ErrInternalServerError
-	}
-
 	return nil
 }
 
 func (tr *dbImpl) UpdateState(ctx context.Context, state entity.StatesTransaction, id string) error {
-	tx, _ := tr.dbConn.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
 	query := "UPDATE transactions SET state = ? WHERE id = ?"
 
-	_, err := tx.ExecContext(ctx, query, state, id)
+	_, err := tr.dbConn.ExecContext(ctx, query, state, id)
 	if err != nil {
-		tx.Rollback()
-		log.Println("Error create transaction: ", err.Error())
+		log.Println("Error update state transaction: ", err.Error())
 		return echo.ErrInternalServerError
 	}
 
-	err = tx.Commit()
+	return nil
+}
+
+func (tr *dbImpl) UpdateAmount(ctx context.Context, id string, amount money.Money) error {
+	query := "UPDATE transactions SET amount = ? WHERE id = ?"
+
+	_, err := tr.dbConn.ExecContext(ctx, query, amount, id)
 	if err != nil {
-		log.Println("Error create transaction tx.Commit: ", err.Error())
+		log.Println("Error update amount transaction: ", err.Error())
 		return echo.ErrInternalServerError
 	}
 
 	return nil
 }
 
-func (tr *dbImpl) ReadBalance(ctx context.Context, userId string) (float64, error) {
-	tx, _ := tr.dbConn.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
-	query := "SELECT balance FROM users WHERE id = ?"
+func (tr *dbImpl) ReadAll(ctx context.Context) ([]entity.Transaction, error) {
+	transactions := make([]entity.Transaction, 0)
+	query := "SELECT id, id_source, id_destination, amount, kind, id_original, id_parent, state, expires_at, execute_at, created_at FROM transactions ORDER BY created_at DESC"
+
+	err := sqlx.SelectContext(ctx, tr.dbConn, &transactions, query)
+	if err != nil {
+		log.Println("Error ReadAll transactions: ", err.Error())
+		return nil, echo.ErrInternalServerError
+	}
+
+	return transactions, nil
+}
 
-	var balance float64
+func (tr *dbImpl) ReadOneById(ctx context.Context, id string) (*entity.Transaction, error) {
+	transaction := new(entity.Transaction)
+	query := "SELECT id, id_source, id_destination, amount, kind, id_original, id_parent, state, expires_at, execute_at, created_at FROM transactions WHERE id = ?"
 
-	err := tx.QueryRowContext(ctx, query, userId).Scan(&balance)
+	err := sqlx.GetContext(ctx, tr.dbConn, transaction, query, id)
 	if err != nil {
-		tx.Rollback()
-		log.Println("Error read balance: ", err.Error())
-		return balance, echo.ErrNotFound
+		log.Println("Error ReadOneById transaction: ", err.Error())
+		return nil, echo.ErrNotFound
 	}
 
-	err = tx.Commit()
+	return transaction, nil
+}
+
+// ReadOneByIdForUpdate locks the transaction row until the surrounding
+// transaction ends. It must be called from a Container bound to a unit of work.
+func (tr *dbImpl) ReadOneByIdForUpdate(ctx context.Context, id string) (*entity.Transaction, error) {
+	transaction := new(entity.Transaction)
+	query := "SELECT id, id_source, id_destination, amount, kind, id_original, id_parent, state, expires_at, execute_at, created_at FROM transactions WHERE id = ? FOR UPDATE"
+
+	err := sqlx.GetContext(ctx, tr.dbConn, transaction, query, id)
 	if err != nil {
-		log.Println("Error read balance tx.Commit: ", err.Error())
-		return balance, echo.ErrInternalServerError
+		log.Println("Error ReadOneByIdForUpdate transaction: ", err.Error())
+		return nil, echo.ErrNotFound
 	}
 
-	return balance, nil
+	return transaction, nil
 }
 
-func (tr *dbImpl) UpdateBalanceUser(ctx context.Context, userId string, value float64) error {
-	tx, _ := tr.dbConn.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
-	query := "UPDATE users SET balance = ? WHERE id = ?"
+// ReadReversedAmount sums the booked reversals of the original transaction.
+func (tr *dbImpl) ReadReversedAmount(ctx context.Context, originalId string) (money.Money, error) {
+	var amount money.Money
+	query := "SELECT COALESCE(SUM(amount), 0) FROM transactions WHERE id_original = ? AND state = ?"
 
-	_, err := tx.ExecContext(ctx, query, value, userId)
+	err := sqlx.GetContext(ctx, tr.dbConn, &amount, query, originalId, entity.BOOKED)
 	if err != nil {
-		tx.Rollback()
-		log.Println("Error increase balance: ", err.Error())
-		return echo.ErrNotFound
+		log.Println("Error ReadReversedAmount transaction: ", err.Error())
+		return money.Money{}, echo.ErrInternalServerError
 	}
 
-	err = tx.Commit()
+	return amount, nil
+}
+
+// ReadSentAmount sums the transfers and withdrawals the accounts of the user
+// made since the given time that took money out of their balance or still
+// hold it, reversed ones included. Booked transfers count from when they were
+// booked, the time of their postings, so a scheduled transfer counts on the
+// day it runs; holds count from when they were authorized.
+func (tr *dbImpl) ReadSentAmount(ctx context.Context, userId string, since time.Time) (money.Money, error) {
+	var amount money.Money
+	query := "SELECT COALESCE(SUM(t.amount), 0) FROM transactions t JOIN accounts a ON a.id = t.id_source WHERE a.user_id = ? AND t.kind IN (?, ?) AND " +
+		"(t.state = ? AND t.created_at >= ? OR t.state IN (?, ?, ?) AND EXISTS (SELECT 1 FROM postings p WHERE p.transaction_id = t.id AND p.created_at >= ?))"
+
+	err := sqlx.GetContext(ctx, tr.dbConn, &amount, query,
+		userId,
+		entity.TRANSFER,
+		entity.WITHDRAWAL,
+		entity.AUTHORIZED,
+		since,
+		entity.BOOKED,
+		entity.PARTIALLY_REVERSED,
+		entity.REVERSED,
+		since,
+	)
 	if err != nil {
-		log.Println("Error increase balance tx.Commit: ", err.Error())
-		return echo.ErrInternalServerError
+		log.Println("Error ReadSentAmount transaction: ", err.Error())
+		return money.Money{}, echo.ErrInternalServerError
 	}
 
-	return nil
+	return amount, nil
 }
 
-func (tr *dbImpl) ReadAll(ctx context.Context) ([]entity.Transaction, error) {
+// ReadExpiredAuthorizations lists the authorizations still holding funds
+// after their expiry.
+func (tr *dbImpl) ReadExpiredAuthorizations(ctx context.Context, now time.Time) ([]entity.Transaction, error) {
 	transactions := make([]entity.Transaction, 0)
-	query := "SELECT id, id_source, id_destination, amount, state, created_at FROM transactions ORDER BY created_at DESC"
+	query := "SELECT id, id_source, id_destination, amount, kind, id_original, id_parent, state, expires_at, execute_at, created_at FROM transactions WHERE state = ? AND expires_at <= ?"
 
-	err := tr.dbConn.SelectContext(ctx, &transactions, query)
+	err := sqlx.SelectContext(ctx, tr.dbConn, &transactions, query, entity.AUTHORIZED, now)
 	if err != nil {
-		log.Println("Error ReadAll transactions: ", err.Error())
+		log.Println("Error ReadExpiredAuthorizations transactions: ", err.Error())
+		return nil, echo.ErrInternalServerError
+	}
+
+	return transactions, nil
+}
+
+// ReadDueScheduled lists the scheduled transfers whose execution date has
+// been reached, oldest first.
+func (tr *dbImpl) ReadDueScheduled(ctx context.Context, now time.Time) ([]entity.Transaction, error) {
+	transactions := make([]entity.Transaction, 0)
+	query := "SELECT id, id_source, id_destination, amount, kind, id_original, id_parent, state, expires_at, execute_at, created_at FROM transactions WHERE state = ? AND execute_at <= ? ORDER BY execute_at"
+
+	err := sqlx.SelectContext(ctx, tr.dbConn, &transactions, query, entity.SCHEDULED, now)
+	if err != nil {
+		log.Println("Error ReadDueScheduled transactions: ", err.Error())
 		return nil, echo.ErrInternalServerError
 	}
 
 	return transactions, nil
 }
+
+func (tr *dbImpl) ReadAllByState(ctx context.Context, state entity.StatesTransaction) ([]entity.Transaction, error) {
+	transactions := make([]entity.Transaction, 0)
+	query := "SELECT id, id_source, id_destination, amount, kind, id_original, id_parent, state, expires_at, execute_at, created_at FROM transactions WHERE state = ? ORDER BY created_at DESC"
+
+	err := sqlx.SelectContext(ctx, tr.dbConn, &transactions, query, state)
+	if err != nil {
+		log.Println("Error ReadAllByState transactions: ", err.Error())
+		return nil, echo.ErrInternalServerError
+	}
+
+	return transactions, nil
+}
+
+// ReadAllByParent lists the transactions linked to the parent, such as the
+// shares of a split payment, in the order they were created.
+func (tr *dbImpl) ReadAllByParent(ctx context.Context, parentId string) ([]entity.Transaction, error) {
+	transactions := make([]entity.Transaction, 0)
+	query := "SELECT id, id_source, id_destination, amount, kind, id_original, id_parent, state, expires_at, execute_at, created_at FROM transactions WHERE id_parent = ? ORDER BY created_at"
+
+	err := sqlx.SelectContext(ctx, tr.dbConn, &transactions, query, parentId)
+	if err != nil {
+		log.Println("Error ReadAllByParent transactions: ", err.Error())
+		return nil, echo.ErrInternalServerError
+	}
+
+	return transactions, nil
+}
+
+const statementLinesQuery = "SELECT t.id AS id_transaction, t.kind, IF(t.id_source = ?, t.id_destination, t.id_source) AS id_counterparty, u.name AS counterparty_name, p.amount, p.created_at AS booked_at " +
+	"FROM postings p JOIN transactions t ON t.id = p.transaction_id " +
+	"LEFT JOIN accounts a ON a.id = IF(t.id_source = ?, t.id_destination, t.id_source) LEFT JOIN users u ON u.id = a.user_id " +
+	"WHERE p.account_id = ? AND p.created_at >= ? AND p.created_at < ? ORDER BY p.created_at, p.id"
+
+// ReadStatementLines lists the movements booked on the account from start
+// until before end, in the order they were booked, along with the other side
+// of each transaction.
+func (tr *dbImpl) ReadStatementLines(ctx context.Context, accountId string, start, end time.Time) ([]entity.StatementLine, error) {
+	lines := make([]entity.StatementLine, 0)
+	err := sqlx.SelectContext(ctx, tr.dbConn, &lines, statementLinesQuery, accountId, accountId, accountId, start, end)
+	if err != nil {
+		log.Println("Error ReadStatementLines transactions: ", err.Error())
+		return nil, echo.ErrInternalServerError
+	}
+
+	return lines, nil
+}
+
+// StreamStatementLines calls fn with each of the lines ReadStatementLines
+// would list, one row at a time instead of loading them all, stopping at the
+// first error fn returns.
+func (tr *dbImpl) StreamStatementLines(ctx context.Context, accountId string, start, end time.Time, fn func(line entity.StatementLine) error) error {
+	rows, err := tr.dbConn.QueryxContext(ctx, statementLinesQuery, accountId, accountId, accountId, start, end)
+	if err != nil {
+		log.Println("Error StreamStatementLines transactions: ", err.Error())
+		return echo.ErrInternalServerError
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var line entity.StatementLine
+		if err := rows.StructScan(&line); err != nil {
+			log.Println("Error StreamStatementLines transactions: ", err.Error())
+			return echo.ErrInternalServerError
+		}
+
+		if err := fn(line); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Println("Error StreamStatementLines transactions: ", err.Error())
+		return echo.ErrInternalServerError
+	}
+
+	return nil
+}
@@ -3,22 +3,24 @@ package transaction
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/garoque/backend-code-challenge-snapfi/internal/api/dto"
 	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
 	"github.com/garoque/backend-code-challenge-snapfi/internal/test"
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/money"
 	"github.com/google/go-cmp/cmp"
 	"github.com/labstack/echo/v4"
 )
 
 func TestCreate(t *testing.T) {
-	query := "INSERT INTO transactions (id, id_source, id_destination, amount, state) VALUES (?, ?, ?, ?, ?)"
+	query := "INSERT INTO transactions (id, id_source, id_destination, amount, kind, id_original, id_parent, state, expires_at, execute_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
 
 	transaction := entity.NewTransaction(dto.CreateTransaction{
 		SourceUserId:      "source-user-id",
 		DestinationUserId: "destination-user-id",
-		Amount:            100,
+		Amount:            money.New(10000),
 	})
 
 	cases := map[string]struct {
@@ -30,33 +32,17 @@ func TestCreate(t *testing.T) {
 			InputTransaction: transaction,
 			ExpectedErr:      nil,
 			PrepareMock: func(mock sqlmock.Sqlmock) {
-				mock.ExpectBegin()
 				mock.ExpectExec(query).
-					WithArgs(transaction.ID, transaction.SourceId, transaction.DestinationId, transaction.Amount, transaction.State).
+					WithArgs(transaction.ID, transaction.SourceId, transaction.DestinationId, transaction.Amount, transaction.Kind, transaction.OriginalId, transaction.ParentId, transaction.State, transaction.ExpiresAt, transaction.ExecuteAt).
 					WillReturnResult(sqlmock.NewResult(1, 1))
-				mock.ExpectCommit()
 			},
 		},
 		"deve retornar erro: ao criar transaction": {
 			InputTransaction: transaction,
 			ExpectedErr:      echo.ErrInternalServerError,
 			PrepareMock: func(mock sqlmock.Sqlmock) {
-				mock.ExpectBegin()
 				mock.ExpectExec(query).
-					WithArgs(transaction.ID, transaction.SourceId, transaction.DestinationId, transaction.Amount, transaction.State).
-					WillReturnError(echo.ErrInternalServerError)
-				mock.ExpectRollback()
-			},
-		},
-		"deve retornar erro: ao comitar a transaction": {
-			InputTransaction: transaction,
-			ExpectedErr:      echo.ErrInternalServerError,
-			PrepareMock: func(mock sqlmock.Sqlmock) {
-				mock.ExpectBegin()
-				mock.ExpectExec(query).
-					WithArgs(transaction.ID, transaction.SourceId, transaction.DestinationId, transaction.Amount, transaction.State).
-					WillReturnResult(sqlmock.NewResult(1, 1))
-				mock.ExpectCommit().
+					WithArgs(transaction.ID, transaction.SourceId, transaction.DestinationId, transaction.Amount, transaction.Kind, transaction.OriginalId, transaction.ParentId, transaction.State, transaction.ExpiresAt, transaction.ExecuteAt).
 					WillReturnError(echo.ErrInternalServerError)
 			},
 		},
@@ -92,11 +78,9 @@ func TestUpdateState(t *testing.T) {
 			InputId:     "transaction-id",
 			ExpectedErr: nil,
 			PrepareMock: func(mock sqlmock.Sqlmock) {
-				mock.ExpectBegin()
 				mock.ExpectExec(query).
 					WithArgs(entity.BOOKED, "transaction-id").
 					WillReturnResult(sqlmock.NewResult(1, 1))
-				mock.ExpectCommit()
 			},
 		},
 		"deve retornar erro: ao criar transaction": {
@@ -104,23 +88,51 @@ func TestUpdateState(t *testing.T) {
 			InputId:     "transaction-id",
 			ExpectedErr: echo.ErrInternalServerError,
 			PrepareMock: func(mock sqlmock.Sqlmock) {
-				mock.ExpectBegin()
 				mock.ExpectExec(query).
 					WithArgs(entity.BOOKED, "transaction-id").
 					WillReturnError(echo.ErrInternalServerError)
-				mock.ExpectRollback()
 			},
 		},
-		"deve retornar erro: ao comitar a transaction": {
-			InputState:  entity.BOOKED,
-			InputId:     "transaction-id",
-			ExpectedErr: echo.ErrInternalServerError,
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseTransaction(dbConn)
+			ctx := context.Background()
+
+			err := db.UpdateState(ctx, cs.InputState, cs.InputId)
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestUpdateAmount(t *testing.T) {
+	query := "UPDATE transactions SET amount = ? WHERE id = ?"
+
+	amount := money.New(3000)
+
+	cases := map[string]struct {
+		ExpectedErr error
+		PrepareMock func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			ExpectedErr: nil,
 			PrepareMock: func(mock sqlmock.Sqlmock) {
-				mock.ExpectBegin()
 				mock.ExpectExec(query).
-					WithArgs(entity.BOOKED.String(), "transaction-id").
+					WithArgs(amount, "transaction-id").
 					WillReturnResult(sqlmock.NewResult(1, 1))
-				mock.ExpectCommit().
+			},
+		},
+		"deve retornar erro": {
+			ExpectedErr: echo.ErrInternalServerError,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(query).
+					WithArgs(amount, "transaction-id").
 					WillReturnError(echo.ErrInternalServerError)
 			},
 		},
@@ -134,7 +146,7 @@ func TestUpdateState(t *testing.T) {
 			db := NewDatabaseTransaction(dbConn)
 			ctx := context.Background()
 
-			err := db.UpdateState(ctx, cs.InputState, cs.InputId)
+			err := db.UpdateAmount(ctx, "transaction-id", amount)
 			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
 				t.Error(diff)
 			}
@@ -142,52 +154,223 @@ func TestUpdateState(t *testing.T) {
 	}
 }
 
-func TestReadBalance(t *testing.T) {
-	query := "SELECT balance FROM users WHERE id = ?"
+func TestReadAll(t *testing.T) {
+	query := "SELECT id, id_source, id_destination, amount, kind, id_original, id_parent, state, expires_at, execute_at, created_at FROM transactions ORDER BY created_at DESC"
 
-	var balance float64
-	userId := "user-id"
+	transaction := entity.NewTransaction(dto.CreateTransaction{
+		SourceUserId:      "source-user-id",
+		DestinationUserId: "destination-user-id",
+		Amount:            money.New(10010),
+	})
+	transactions := []entity.Transaction{{
+		ID:            transaction.ID,
+		SourceId:      transaction.SourceId,
+		DestinationId: transaction.DestinationId,
+		Amount:        transaction.Amount,
+	}}
 
 	cases := map[string]struct {
-		InputUserId    string
-		ExpectedResult float64
+		ExpectedResult []entity.Transaction
 		ExpectedErr    error
 		PrepareMock    func(mock sqlmock.Sqlmock)
 	}{
 		"deve retornar sucesso": {
-			InputUserId:    userId,
-			ExpectedResult: balance,
+			ExpectedResult: transactions,
 			ExpectedErr:    nil,
 			PrepareMock: func(mock sqlmock.Sqlmock) {
-				mock.ExpectBegin()
 				mock.ExpectQuery(query).
-					WithArgs(userId).
-					WillReturnRows(test.NewRows("balance").AddRow(balance))
-				mock.ExpectCommit()
+					WillReturnRows(
+						test.NewRows("id", "id_source", "id_destination", "amount", "kind", "id_original", "id_parent", "state", "expires_at", "execute_at", "created_at").
+							AddRow(transaction.ID, transaction.SourceId, transaction.DestinationId, transaction.Amount, transaction.Kind, nil, nil, transaction.State, nil, nil, nil),
+					)
 			},
 		},
-		"deve retornar erro: ao criar transaction": {
-			InputUserId:    userId,
-			ExpectedResult: balance,
+		"deve retornar erro": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrInternalServerError,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WillReturnError(echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseTransaction(dbConn)
+			ctx := context.Background()
+
+			transactions, err := db.ReadAll(ctx)
+			if diff := cmp.Diff(transactions, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestReadOneById(t *testing.T) {
+	query := "SELECT id, id_source, id_destination, amount, kind, id_original, id_parent, state, expires_at, execute_at, created_at FROM transactions WHERE id = ?"
+
+	original := "original-id"
+	transaction := &entity.Transaction{
+		ID:            "transaction-id",
+		SourceId:      "source-user-id",
+		DestinationId: "destination-user-id",
+		Amount:        money.New(10010),
+		Kind:          entity.REVERSAL,
+		OriginalId:    &original,
+		State:         entity.BOOKED,
+	}
+
+	cases := map[string]struct {
+		InputId        string
+		ExpectedResult *entity.Transaction
+		ExpectedErr    error
+		PrepareMock    func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			InputId:        transaction.ID,
+			ExpectedResult: transaction,
+			ExpectedErr:    nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs(transaction.ID).
+					WillReturnRows(
+						test.NewRows("id", "id_source", "id_destination", "amount", "kind", "id_original", "id_parent", "state", "expires_at", "execute_at", "created_at").
+							AddRow(transaction.ID, transaction.SourceId, transaction.DestinationId, transaction.Amount, transaction.Kind, original, nil, transaction.State, nil, nil, nil),
+					)
+			},
+		},
+		"deve retornar erro": {
+			InputId:        transaction.ID,
+			ExpectedResult: nil,
 			ExpectedErr:    echo.ErrNotFound,
 			PrepareMock: func(mock sqlmock.Sqlmock) {
-				mock.ExpectBegin()
 				mock.ExpectQuery(query).
-					WithArgs(userId).
+					WithArgs(transaction.ID).
 					WillReturnError(echo.ErrNotFound)
-				mock.ExpectRollback()
 			},
 		},
-		"deve retornar erro: ao comitar a transaction": {
-			InputUserId:    userId,
-			ExpectedResult: balance,
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseTransaction(dbConn)
+			ctx := context.Background()
+
+			transaction, err := db.ReadOneById(ctx, cs.InputId)
+			if diff := cmp.Diff(transaction, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestReadOneByIdForUpdate(t *testing.T) {
+	query := "SELECT id, id_source, id_destination, amount, kind, id_original, id_parent, state, expires_at, execute_at, created_at FROM transactions WHERE id = ? FOR UPDATE"
+
+	original := "original-id"
+	transaction := &entity.Transaction{
+		ID:            "transaction-id",
+		SourceId:      "source-user-id",
+		DestinationId: "destination-user-id",
+		Amount:        money.New(10010),
+		Kind:          entity.REVERSAL,
+		OriginalId:    &original,
+		State:         entity.BOOKED,
+	}
+
+	cases := map[string]struct {
+		InputId        string
+		ExpectedResult *entity.Transaction
+		ExpectedErr    error
+		PrepareMock    func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			InputId:        transaction.ID,
+			ExpectedResult: transaction,
+			ExpectedErr:    nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs(transaction.ID).
+					WillReturnRows(
+						test.NewRows("id", "id_source", "id_destination", "amount", "kind", "id_original", "id_parent", "state", "expires_at", "execute_at", "created_at").
+							AddRow(transaction.ID, transaction.SourceId, transaction.DestinationId, transaction.Amount, transaction.Kind, original, nil, transaction.State, nil, nil, nil),
+					)
+			},
+		},
+		"deve retornar erro": {
+			InputId:        transaction.ID,
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrNotFound,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs(transaction.ID).
+					WillReturnError(echo.ErrNotFound)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseTransaction(dbConn)
+			ctx := context.Background()
+
+			transaction, err := db.ReadOneByIdForUpdate(ctx, cs.InputId)
+			if diff := cmp.Diff(transaction, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestReadReversedAmount(t *testing.T) {
+	query := "SELECT COALESCE(SUM(amount), 0) FROM transactions WHERE id_original = ? AND state = ?"
+
+	cases := map[string]struct {
+		InputId        string
+		ExpectedResult money.Money
+		ExpectedErr    error
+		PrepareMock    func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			InputId:        "transaction-id",
+			ExpectedResult: money.New(5000),
+			ExpectedErr:    nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs("transaction-id", entity.BOOKED).
+					WillReturnRows(test.NewRows("amount").AddRow(5000))
+			},
+		},
+		"deve retornar erro": {
+			InputId:        "transaction-id",
+			ExpectedResult: money.Money{},
 			ExpectedErr:    echo.ErrInternalServerError,
 			PrepareMock: func(mock sqlmock.Sqlmock) {
-				mock.ExpectBegin()
 				mock.ExpectQuery(query).
-					WithArgs(userId).
-					WillReturnRows(test.NewRows("balance").AddRow(balance))
-				mock.ExpectCommit().
+					WithArgs("transaction-id", entity.BOOKED).
 					WillReturnError(echo.ErrInternalServerError)
 			},
 		},
@@ -201,8 +384,8 @@ func TestReadBalance(t *testing.T) {
 			db := NewDatabaseTransaction(dbConn)
 			ctx := context.Background()
 
-			balance, err := db.ReadBalance(ctx, cs.InputUserId)
-			if diff := cmp.Diff(balance, cs.ExpectedResult); diff != "" {
+			amount, err := db.ReadReversedAmount(ctx, cs.InputId)
+			if diff := cmp.Diff(amount, cs.ExpectedResult); diff != "" {
 				t.Error(diff)
 			}
 
@@ -213,52 +396,95 @@ func TestReadBalance(t *testing.T) {
 	}
 }
 
-func TestUpdateBalanceUser(t *testing.T) {
-	query := "UPDATE users SET balance = ? WHERE id = ?"
+func TestReadSentAmount(t *testing.T) {
+	query := "SELECT COALESCE(SUM(t.amount), 0) FROM transactions t JOIN accounts a ON a.id = t.id_source WHERE a.user_id = ? AND t.kind IN (?, ?) AND " +
+		"(t.state = ? AND t.created_at >= ? OR t.state IN (?, ?, ?) AND EXISTS (SELECT 1 FROM postings p WHERE p.transaction_id = t.id AND p.created_at >= ?))"
 
-	value := 100.10
-	userId := "user-id"
+	since := time.Date(2023, 5, 11, 0, 0, 0, 0, time.UTC)
 
 	cases := map[string]struct {
-		InputValue  float64
-		InputUserId string
-		ExpectedErr error
-		PrepareMock func(mock sqlmock.Sqlmock)
+		ExpectedResult money.Money
+		ExpectedErr    error
+		PrepareMock    func(mock sqlmock.Sqlmock)
 	}{
 		"deve retornar sucesso": {
-			InputValue:  value,
-			InputUserId: userId,
-			ExpectedErr: nil,
+			ExpectedResult: money.New(25000),
+			ExpectedErr:    nil,
 			PrepareMock: func(mock sqlmock.Sqlmock) {
-				mock.ExpectBegin()
-				mock.ExpectExec(query).
-					WithArgs(value, userId).
-					WillReturnResult(sqlmock.NewResult(1, 1))
-				mock.ExpectCommit()
+				mock.ExpectQuery(query).
+					WithArgs("user-id", entity.TRANSFER, entity.WITHDRAWAL, entity.AUTHORIZED, since, entity.BOOKED, entity.PARTIALLY_REVERSED, entity.REVERSED, since).
+					WillReturnRows(test.NewRows("amount").AddRow(25000))
 			},
 		},
-		"deve retornar erro: ao criar transaction": {
-			InputValue:  value,
-			InputUserId: userId,
-			ExpectedErr: echo.ErrNotFound,
+		"deve retornar erro": {
+			ExpectedResult: money.Money{},
+			ExpectedErr:    echo.ErrInternalServerError,
 			PrepareMock: func(mock sqlmock.Sqlmock) {
-				mock.ExpectBegin()
-				mock.ExpectExec(query).
-					WithArgs(value, userId).
-					WillReturnError(echo.ErrNotFound)
-				mock.ExpectRollback()
+				mock.ExpectQuery(query).
+					WithArgs("user-id", entity.TRANSFER, entity.WITHDRAWAL, entity.AUTHORIZED, since, entity.BOOKED, entity.PARTIALLY_REVERSED, entity.REVERSED, since).
+					WillReturnError(echo.ErrInternalServerError)
 			},
 		},
-		"deve retornar erro: ao comitar a transaction": {
-			InputValue:  value,
-			InputUserId: userId,
-			ExpectedErr: echo.ErrInternalServerError,
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseTransaction(dbConn)
+			ctx := context.Background()
+
+			amount, err := db.ReadSentAmount(ctx, "user-id", since)
+			if diff := cmp.Diff(amount, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestReadExpiredAuthorizations(t *testing.T) {
+	query := "SELECT id, id_source, id_destination, amount, kind, id_original, id_parent, state, expires_at, execute_at, created_at FROM transactions WHERE state = ? AND expires_at <= ?"
+
+	now := time.Now()
+	expiresAt := now.Add(-time.Minute)
+	transactions := []entity.Transaction{{
+		ID:            "transaction-id",
+		SourceId:      "source-user-id",
+		DestinationId: "destination-user-id",
+		Amount:        money.New(10010),
+		Kind:          entity.TRANSFER,
+		State:         entity.AUTHORIZED,
+		ExpiresAt:     &expiresAt,
+	}}
+
+	cases := map[string]struct {
+		ExpectedResult []entity.Transaction
+		ExpectedErr    error
+		PrepareMock    func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			ExpectedResult: transactions,
+			ExpectedErr:    nil,
 			PrepareMock: func(mock sqlmock.Sqlmock) {
-				mock.ExpectBegin()
-				mock.ExpectExec(query).
-					WithArgs(value, userId).
-					WillReturnResult(sqlmock.NewResult(1, 1))
-				mock.ExpectCommit().
+				mock.ExpectQuery(query).
+					WithArgs(entity.AUTHORIZED, now).
+					WillReturnRows(
+						test.NewRows("id", "id_source", "id_destination", "amount", "kind", "id_original", "id_parent", "state", "expires_at", "execute_at", "created_at").
+							AddRow("transaction-id", "source-user-id", "destination-user-id", 10010, entity.TRANSFER, nil, nil, entity.AUTHORIZED, expiresAt, nil, nil),
+					)
+			},
+		},
+		"deve retornar erro": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrInternalServerError,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs(entity.AUTHORIZED, now).
 					WillReturnError(echo.ErrInternalServerError)
 			},
 		},
@@ -272,7 +498,11 @@ func TestUpdateBalanceUser(t *testing.T) {
 			db := NewDatabaseTransaction(dbConn)
 			ctx := context.Background()
 
-			err := db.UpdateBalanceUser(ctx, cs.InputUserId, cs.InputValue)
+			transactions, err := db.ReadExpiredAuthorizations(ctx, now)
+			if diff := cmp.Diff(transactions, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
 			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
 				t.Error(diff)
 			}
@@ -280,19 +510,19 @@ func TestUpdateBalanceUser(t *testing.T) {
 	}
 }
 
-func TestReadAll(t *testing.T) {
-	query := "SELECT id, id_source, id_destination, amount, state, created_at FROM transactions ORDER BY created_at DESC"
+func TestReadDueScheduled(t *testing.T) {
+	query := "SELECT id, id_source, id_destination, amount, kind, id_original, id_parent, state, expires_at, execute_at, created_at FROM transactions WHERE state = ? AND execute_at <= ? ORDER BY execute_at"
 
-	transaction := entity.NewTransaction(dto.CreateTransaction{
-		SourceUserId:      "source-user-id",
-		DestinationUserId: "destination-user-id",
-		Amount:            100.10,
-	})
+	now := time.Now()
+	executeAt := now.Add(-time.Minute)
 	transactions := []entity.Transaction{{
-		ID:            transaction.ID,
-		SourceId:      transaction.SourceId,
-		DestinationId: transaction.DestinationId,
-		Amount:        transaction.Amount,
+		ID:            "transaction-id",
+		SourceId:      "source-user-id",
+		DestinationId: "destination-user-id",
+		Amount:        money.New(10010),
+		Kind:          entity.TRANSFER,
+		State:         entity.SCHEDULED,
+		ExecuteAt:     &executeAt,
 	}}
 
 	cases := map[string]struct {
@@ -305,9 +535,10 @@ func TestReadAll(t *testing.T) {
 			ExpectedErr:    nil,
 			PrepareMock: func(mock sqlmock.Sqlmock) {
 				mock.ExpectQuery(query).
+					WithArgs(entity.SCHEDULED, now).
 					WillReturnRows(
-						test.NewRows("id", "id_source", "id_destination", "amount", "state", "created_at").
-							AddRow(transaction.ID, transaction.SourceId, transaction.DestinationId, transaction.Amount, transaction.State, nil),
+						test.NewRows("id", "id_source", "id_destination", "amount", "kind", "id_original", "id_parent", "state", "expires_at", "execute_at", "created_at").
+							AddRow("transaction-id", "source-user-id", "destination-user-id", 10010, entity.TRANSFER, nil, nil, entity.SCHEDULED, nil, executeAt, nil),
 					)
 			},
 		},
@@ -316,6 +547,7 @@ func TestReadAll(t *testing.T) {
 			ExpectedErr:    echo.ErrInternalServerError,
 			PrepareMock: func(mock sqlmock.Sqlmock) {
 				mock.ExpectQuery(query).
+					WithArgs(entity.SCHEDULED, now).
 					WillReturnError(echo.ErrInternalServerError)
 			},
 		},
@@ -329,7 +561,67 @@ func TestReadAll(t *testing.T) {
 			db := NewDatabaseTransaction(dbConn)
 			ctx := context.Background()
 
-			transactions, err := db.ReadAll(ctx)
+			transactions, err := db.ReadDueScheduled(ctx, now)
+			if diff := cmp.Diff(transactions, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestReadAllByState(t *testing.T) {
+	query := "SELECT id, id_source, id_destination, amount, kind, id_original, id_parent, state, expires_at, execute_at, created_at FROM transactions WHERE state = ? ORDER BY created_at DESC"
+
+	transactions := []entity.Transaction{{
+		ID:            "transaction-id",
+		SourceId:      "source-user-id",
+		DestinationId: "destination-user-id",
+		Amount:        money.New(10010),
+		Kind:          entity.TRANSFER,
+		State:         entity.SCHEDULED,
+	}}
+
+	cases := map[string]struct {
+		ExpectedResult []entity.Transaction
+		ExpectedErr    error
+		PrepareMock    func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			ExpectedResult: transactions,
+			ExpectedErr:    nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs(entity.SCHEDULED).
+					WillReturnRows(
+						test.NewRows("id", "id_source", "id_destination", "amount", "kind", "id_original", "id_parent", "state", "expires_at", "execute_at", "created_at").
+							AddRow("transaction-id", "source-user-id", "destination-user-id", 10010, entity.TRANSFER, nil, nil, entity.SCHEDULED, nil, nil, nil),
+					)
+			},
+		},
+		"deve retornar erro": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrInternalServerError,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs(entity.SCHEDULED).
+					WillReturnError(echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseTransaction(dbConn)
+			ctx := context.Background()
+
+			transactions, err := db.ReadAllByState(ctx, entity.SCHEDULED)
 			if diff := cmp.Diff(transactions, cs.ExpectedResult); diff != "" {
 				t.Error(diff)
 			}
@@ -340,3 +632,207 @@ func TestReadAll(t *testing.T) {
 		})
 	}
 }
+
+func TestReadAllByParent(t *testing.T) {
+	query := "SELECT id, id_source, id_destination, amount, kind, id_original, id_parent, state, expires_at, execute_at, created_at FROM transactions WHERE id_parent = ? ORDER BY created_at"
+
+	parentId := "split-id"
+	transactions := []entity.Transaction{{
+		ID:            "transaction-id",
+		SourceId:      "source-user-id",
+		DestinationId: "destination-user-id",
+		Amount:        money.New(9000),
+		Kind:          entity.TRANSFER,
+		ParentId:      &parentId,
+		State:         entity.BOOKED,
+	}}
+
+	cases := map[string]struct {
+		ExpectedResult []entity.Transaction
+		ExpectedErr    error
+		PrepareMock    func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			ExpectedResult: transactions,
+			ExpectedErr:    nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs(parentId).
+					WillReturnRows(
+						test.NewRows("id", "id_source", "id_destination", "amount", "kind", "id_original", "id_parent", "state", "expires_at", "execute_at", "created_at").
+							AddRow("transaction-id", "source-user-id", "destination-user-id", 9000, entity.TRANSFER, nil, parentId, entity.BOOKED, nil, nil, nil),
+					)
+			},
+		},
+		"deve retornar erro": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrInternalServerError,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs(parentId).
+					WillReturnError(echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseTransaction(dbConn)
+			ctx := context.Background()
+
+			transactions, err := db.ReadAllByParent(ctx, parentId)
+			if diff := cmp.Diff(transactions, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+const statementLinesTestQuery = "SELECT t.id AS id_transaction, t.kind, IF(t.id_source = ?, t.id_destination, t.id_source) AS id_counterparty, u.name AS counterparty_name, p.amount, p.created_at AS booked_at " +
+	"FROM postings p JOIN transactions t ON t.id = p.transaction_id " +
+	"LEFT JOIN accounts a ON a.id = IF(t.id_source = ?, t.id_destination, t.id_source) LEFT JOIN users u ON u.id = a.user_id " +
+	"WHERE p.account_id = ? AND p.created_at >= ? AND p.created_at < ? ORDER BY p.created_at, p.id"
+
+func TestReadStatementLines(t *testing.T) {
+	query := statementLinesTestQuery
+
+	userId := "user-id"
+	start := time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	bookedAt := time.Date(2023, 5, 10, 9, 0, 0, 0, time.UTC)
+	name := "João"
+
+	lines := []entity.StatementLine{
+		{TransactionId: "transfer-id", Kind: entity.TRANSFER, CounterpartyId: "destination-user-id", CounterpartyName: &name, Amount: money.New(-9000), BookedAt: bookedAt},
+		{TransactionId: "fee-id", Kind: entity.FEE, CounterpartyId: entity.FeeAccountId, Amount: money.New(-100), BookedAt: bookedAt},
+	}
+
+	cases := map[string]struct {
+		ExpectedResult []entity.StatementLine
+		ExpectedErr    error
+		PrepareMock    func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			ExpectedResult: lines,
+			ExpectedErr:    nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs(userId, userId, userId, start, end).
+					WillReturnRows(
+						test.NewRows("id_transaction", "kind", "id_counterparty", "counterparty_name", "amount", "booked_at").
+							AddRow("transfer-id", entity.TRANSFER, "destination-user-id", name, -9000, bookedAt).
+							AddRow("fee-id", entity.FEE, entity.FeeAccountId, nil, -100, bookedAt),
+					)
+			},
+		},
+		"deve retornar erro": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrInternalServerError,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs(userId, userId, userId, start, end).
+					WillReturnError(echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseTransaction(dbConn)
+			ctx := context.Background()
+
+			lines, err := db.ReadStatementLines(ctx, userId, start, end)
+			if diff := cmp.Diff(lines, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestStreamStatementLines(t *testing.T) {
+	userId := "user-id"
+	start := time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	bookedAt := time.Date(2023, 5, 10, 9, 0, 0, 0, time.UTC)
+
+	rows := func() *sqlmock.Rows {
+		return test.NewRows("id_transaction", "kind", "id_counterparty", "counterparty_name", "amount", "booked_at").
+			AddRow("deposit-id", entity.DEPOSIT, entity.FundingAccountId, nil, 10000, bookedAt).
+			AddRow("fee-id", entity.FEE, entity.FeeAccountId, nil, -100, bookedAt)
+	}
+
+	cases := map[string]struct {
+		InputErr       error
+		ExpectedResult []string
+		ExpectedErr    error
+		PrepareMock    func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			InputErr:       nil,
+			ExpectedResult: []string{"deposit-id", "fee-id"},
+			ExpectedErr:    nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(statementLinesTestQuery).
+					WithArgs(userId, userId, userId, start, end).
+					WillReturnRows(rows())
+			},
+		},
+		"deve parar no erro do callback": {
+			InputErr:       echo.ErrServiceUnavailable,
+			ExpectedResult: []string{"deposit-id"},
+			ExpectedErr:    echo.ErrServiceUnavailable,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(statementLinesTestQuery).
+					WithArgs(userId, userId, userId, start, end).
+					WillReturnRows(rows())
+			},
+		},
+		"deve retornar erro": {
+			InputErr:       nil,
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrInternalServerError,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(statementLinesTestQuery).
+					WithArgs(userId, userId, userId, start, end).
+					WillReturnError(echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseTransaction(dbConn)
+			ctx := context.Background()
+
+			var streamed []string
+			err := db.StreamStatementLines(ctx, userId, start, end, func(line entity.StatementLine) error {
+				streamed = append(streamed, line.TransactionId)
+				return cs.InputErr
+			})
+			if diff := cmp.Diff(streamed, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package database
+
+import (
+	"context"
+	"log"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/labstack/echo/v4"
+)
+
+// UnitOfWorkInterface runs fn against a Container whose repositories all share
+// a single database transaction. The transaction is committed when fn returns
+// nil and rolled back otherwise.
+type UnitOfWorkInterface interface {
+	Do(ctx context.Context, fn func(tx *Container) error) error
+}
+
+type unitOfWorkImpl struct {
+	dbConn *sqlx.DB
+}
+
+func (u *unitOfWorkImpl) Do(ctx context.Context, fn func(tx *Container) error) error {
+	tx, err := u.dbConn.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Println("Error unitOfWork.Do.BeginTxx: ", err.Error())
+		return echo.ErrInternalServerError
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	container := newContainer(tx)
+	container.UnitOfWork = &txUnitOfWorkImpl{container}
+
+	if err := fn(container); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Println("Error unitOfWork.Do.tx.Commit: ", err.Error())
+		return echo.ErrInternalServerError
+	}
+
+	return nil
+}
+
+// txUnitOfWorkImpl is used by containers that are already bound to a
+// transaction, so nested units of work join the outer one.
+type txUnitOfWorkImpl struct {
+	container *Container
+}
+
+func (u *txUnitOfWorkImpl) Do(ctx context.Context, fn func(tx *Container) error) error {
+	return fn(u.container)
+}
@@ -0,0 +1,100 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/test"
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/money"
+	"github.com/google/go-cmp/cmp"
+	"github.com/labstack/echo/v4"
+)
+
+func TestUnitOfWorkDo(t *testing.T) {
+	query := "UPDATE accounts SET balance = ? WHERE id = ?"
+
+	cases := map[string]struct {
+		InputFn     func(ctx context.Context, tx *Container) error
+		ExpectedErr error
+		PrepareMock func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			InputFn: func(ctx context.Context, tx *Container) error {
+				if err := tx.Account.UpdateBalance(ctx, "source-user-id", money.New(0)); err != nil {
+					return err
+				}
+
+				return tx.UnitOfWork.Do(ctx, func(nested *Container) error {
+					return nested.Account.UpdateBalance(ctx, "destination-user-id", money.New(10000))
+				})
+			},
+			ExpectedErr: nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(query).
+					WithArgs(money.New(0), "source-user-id").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(query).
+					WithArgs(money.New(10000), "destination-user-id").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+		},
+		"deve retornar erro: ao iniciar a transaction": {
+			InputFn: func(ctx context.Context, tx *Container) error {
+				return nil
+			},
+			ExpectedErr: echo.ErrInternalServerError,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin().
+					WillReturnError(echo.ErrInternalServerError)
+			},
+		},
+		"deve retornar erro: e fazer rollback": {
+			InputFn: func(ctx context.Context, tx *Container) error {
+				return tx.Account.UpdateBalance(ctx, "source-user-id", money.New(0))
+			},
+			ExpectedErr: echo.ErrNotFound,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(query).
+					WithArgs(money.New(0), "source-user-id").
+					WillReturnError(echo.ErrInternalServerError)
+				mock.ExpectRollback()
+			},
+		},
+		"deve retornar erro: ao comitar a transaction": {
+			InputFn: func(ctx context.Context, tx *Container) error {
+				return nil
+			},
+			ExpectedErr: echo.ErrInternalServerError,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectCommit().
+					WillReturnError(echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := New(dbConn)
+			ctx := context.Background()
+
+			err := db.UnitOfWork.Do(ctx, func(tx *Container) error {
+				return cs.InputFn(ctx, tx)
+			})
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
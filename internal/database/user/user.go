@@ -2,7 +2,6 @@ package user
 
 import (
 	"context"
-	"database/sql"
 	"log"
 
 	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
@@ -14,41 +13,41 @@ type DabataseUserInterface interface {
 	Create(ctx context.Context, user entity.User) error
 	ReadAll(ctx context.Context) ([]entity.User, error)
 	ReadOneById(ctx context.Context, userId string) (*entity.User, error)
+	ReadOneByIdForUpdate(ctx context.Context, userId string) (*entity.User, error)
+	UpdateStatus(ctx context.Context, userId string, status entity.StatusUser) error
+	CreateStatusChange(ctx context.Context, change *entity.StatusChange) error
+	ReadStatusHistory(ctx context.Context, userId string) ([]entity.StatusChange, error)
 }
 
+// The balance of a user is the one of their default account, which shares
+// their ID.
+const selectQuery = "SELECT u.id, u.name, a.account_type, u.status, a.balance, a.held_balance, a.balance - a.held_balance AS available_balance, " +
+	"a.credit_limit, a.interest_rate, u.created_at, u.updated_at FROM users u JOIN accounts a ON a.id = u.id"
+
 type dbImpl struct {
-	dbConn *sqlx.DB
+	dbConn sqlx.ExtContext
 }
 
-func NewDatabaseUser(dbConn *sqlx.DB) DabataseUserInterface {
+func NewDatabaseUser(dbConn sqlx.ExtContext) DabataseUserInterface {
 	return &dbImpl{dbConn}
 }
 
 func (u *dbImpl) Create(ctx context.Context, user entity.User) error {
-	tx, _ := u.dbConn.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelDefault})
-	query := "INSERT INTO users (id, name, balance) VALUES (?, ?, ?)"
+	query := "INSERT INTO users (id, name) VALUES (?, ?)"
 
-	_, err := tx.ExecContext(ctx, query, user.ID, user.Name, user.Balance)
+	_, err := u.dbConn.ExecContext(ctx, query, user.ID, user.Name)
 	if err != nil {
-		tx.Rollback()
 		log.Println("Error create user: ", err.Error())
 		return echo.ErrInternalServerError
 	}
 
-	err = tx.Commit()
-	if err != nil {
-		log.Println("Error create user tx.Commit: ", err.Error())
-		return echo.ErrInternalServerError
-	}
-
 	return nil
 }
 
 func (u *dbImpl) ReadAll(ctx context.Context) ([]entity.User, error) {
 	users := make([]entity.User, 0)
-	query := "SELECT id, name, balance, created_at, updated_at FROM users"
 
-	err := u.dbConn.SelectContext(ctx, &users, query)
+	err := sqlx.SelectContext(ctx, u.dbConn, &users, selectQuery)
 	if err != nil {
 		log.Println("Error ReadAll user: ", err.Error())
 		return nil, echo.ErrInternalServerError
@@ -59,9 +58,9 @@ func (u *dbImpl) ReadAll(ctx context.Context) ([]entity.User, error) {
 
 func (u *dbImpl) ReadOneById(ctx context.Context, userId string) (*entity.User, error) {
 	user := new(entity.User)
-	query := "SELECT id, name, balance, created_at, updated_at FROM users WHERE id = ?"
+	query := selectQuery + " WHERE u.id = ?"
 
-	err := u.dbConn.GetContext(ctx, user, query, userId)
+	err := sqlx.GetContext(ctx, u.dbConn, user, query, userId)
 	if err != nil {
 		log.Println("Error ReadOneById user: ", err.Error())
 		return nil, echo.ErrNotFound
@@ -69,3 +68,61 @@ func (u *dbImpl) ReadOneById(ctx context.Context, userId string) (*entity.User,
 
 	return user, nil
 }
+
+// ReadOneByIdForUpdate locks the user row, and the one of their default
+// account, until the surrounding transaction ends. It must be called from a
+// Container bound to a unit of work.
+func (u *dbImpl) ReadOneByIdForUpdate(ctx context.Context, userId string) (*entity.User, error) {
+	user := new(entity.User)
+	query := selectQuery + " WHERE u.id = ? FOR UPDATE"
+
+	err := sqlx.GetContext(ctx, u.dbConn, user, query, userId)
+	if err != nil {
+		log.Println("Error ReadOneByIdForUpdate user: ", err.Error())
+		return nil, echo.ErrNotFound
+	}
+
+	return user, nil
+}
+
+func (u *dbImpl) UpdateStatus(ctx context.Context, userId string, status entity.StatusUser) error {
+	query := "UPDATE users SET status = ? WHERE id = ?"
+
+	_, err := u.dbConn.ExecContext(ctx, query, status, userId)
+	if err != nil {
+		log.Println("Error update status: ", err.Error())
+		return echo.ErrInternalServerError
+	}
+
+	return nil
+}
+
+func (u *dbImpl) CreateStatusChange(ctx context.Context, change *entity.StatusChange) error {
+	query := "INSERT INTO user_status_history (user_id, from_status, to_status, reason) VALUES (?, ?, ?, ?)"
+
+	_, err := u.dbConn.ExecContext(ctx, query,
+		change.UserId,
+		change.From,
+		change.To,
+		change.Reason,
+	)
+	if err != nil {
+		log.Println("Error create status change: ", err.Error())
+		return echo.ErrInternalServerError
+	}
+
+	return nil
+}
+
+func (u *dbImpl) ReadStatusHistory(ctx context.Context, userId string) ([]entity.StatusChange, error) {
+	changes := make([]entity.StatusChange, 0)
+	query := "SELECT id, user_id, from_status, to_status, reason, created_at FROM user_status_history WHERE user_id = ? ORDER BY id"
+
+	err := sqlx.SelectContext(ctx, u.dbConn, &changes, query, userId)
+	if err != nil {
+		log.Println("Error ReadStatusHistory user: ", err.Error())
+		return nil, echo.ErrInternalServerError
+	}
+
+	return changes, nil
+}
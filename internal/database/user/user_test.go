@@ -8,13 +8,14 @@ import (
 	"github.com/garoque/backend-code-challenge-snapfi/internal/api/dto"
 	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
 	"github.com/garoque/backend-code-challenge-snapfi/internal/test"
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/money"
 	"github.com/garoque/backend-code-challenge-snapfi/pkg/uuid"
 	"github.com/google/go-cmp/cmp"
 	"github.com/labstack/echo/v4"
 )
 
 func TestCreate(t *testing.T) {
-	query := "INSERT INTO users (id, name, balance) VALUES (?, ?, ?)"
+	query := "INSERT INTO users (id, name) VALUES (?, ?)"
 
 	user := entity.NewUser(dto.CreateUser{Name: "Gabriel"})
 
@@ -27,33 +28,17 @@ func TestCreate(t *testing.T) {
 			InputUser:   *user,
 			ExpectedErr: nil,
 			PrepareMock: func(mock sqlmock.Sqlmock) {
-				mock.ExpectBegin()
 				mock.ExpectExec(query).
-					WithArgs(user.ID, user.Name, user.Balance).
+					WithArgs(user.ID, user.Name).
 					WillReturnResult(sqlmock.NewResult(1, 1))
-				mock.ExpectCommit()
 			},
 		},
 		"deve retornar erro: ao criar user": {
 			InputUser:   *user,
 			ExpectedErr: echo.ErrInternalServerError,
 			PrepareMock: func(mock sqlmock.Sqlmock) {
-				mock.ExpectBegin()
 				mock.ExpectExec(query).
-					WithArgs(user.ID, user.Name, user.Balance).
-					WillReturnError(echo.ErrInternalServerError)
-				mock.ExpectRollback()
-			},
-		},
-		"deve retornar erro: ao comitar a transaction": {
-			InputUser:   *user,
-			ExpectedErr: echo.ErrInternalServerError,
-			PrepareMock: func(mock sqlmock.Sqlmock) {
-				mock.ExpectBegin()
-				mock.ExpectExec(query).
-					WithArgs(user.ID, user.Name, user.Balance).
-					WillReturnResult(sqlmock.NewResult(1, 1))
-				mock.ExpectCommit().
+					WithArgs(user.ID, user.Name).
 					WillReturnError(echo.ErrInternalServerError)
 			},
 		},
@@ -76,15 +61,18 @@ func TestCreate(t *testing.T) {
 }
 
 func TestReadAll(t *testing.T) {
-	query := "SELECT id, name, balance, created_at, updated_at FROM users"
+	query := "SELECT u.id, u.name, a.account_type, u.status, a.balance, a.held_balance, a.balance - a.held_balance AS available_balance, a.credit_limit, a.interest_rate, u.created_at, u.updated_at FROM users u JOIN accounts a ON a.id = u.id"
 
 	user := entity.NewUser(dto.CreateUser{Name: "Gabriel"})
 	users := []entity.User{{
-		ID:        user.ID,
-		Name:      user.Name,
-		Balance:   user.Balance,
-		CreatedAt: user.CreatedAt,
-		UpdatedAt: nil,
+		ID:               user.ID,
+		Name:             user.Name,
+		Balance:          user.Balance,
+		HeldBalance:      user.HeldBalance,
+		AvailableBalance: user.AvailableBalance,
+		CreditLimit:      user.CreditLimit,
+		CreatedAt:        user.CreatedAt,
+		UpdatedAt:        nil,
 	}}
 
 	cases := map[string]struct {
@@ -98,8 +86,8 @@ func TestReadAll(t *testing.T) {
 			PrepareMock: func(mock sqlmock.Sqlmock) {
 				mock.ExpectQuery(query).
 					WillReturnRows(
-						test.NewRows("id", "name", "balance", "created_at", "updated_at").
-							AddRow(user.ID, user.Name, user.Balance, user.CreatedAt, nil),
+						test.NewRows("id", "name", "account_type", "status", "balance", "held_balance", "available_balance", "credit_limit", "interest_rate", "created_at", "updated_at").
+							AddRow(user.ID, user.Name, user.AccountType, user.Status, user.Balance, user.HeldBalance, user.AvailableBalance, user.CreditLimit, user.InterestRate, user.CreatedAt, nil),
 					)
 			},
 		},
@@ -134,11 +122,15 @@ func TestReadAll(t *testing.T) {
 }
 
 func TestReadOneById(t *testing.T) {
-	query := "SELECT id, name, balance, created_at, updated_at FROM users WHERE id = ?"
+	query := "SELECT u.id, u.name, a.account_type, u.status, a.balance, a.held_balance, a.balance - a.held_balance AS available_balance, a.credit_limit, a.interest_rate, u.created_at, u.updated_at FROM users u JOIN accounts a ON a.id = u.id WHERE u.id = ?"
 
 	user := &entity.User{
-		ID:   uuid.NewId(),
-		Name: "Gabriel",
+		ID:               uuid.NewId(),
+		Name:             "Gabriel",
+		Balance:          money.New(0),
+		HeldBalance:      money.New(0),
+		AvailableBalance: money.New(0),
+		CreditLimit:      money.New(0),
 	}
 
 	cases := map[string]struct {
@@ -155,8 +147,8 @@ func TestReadOneById(t *testing.T) {
 				mock.ExpectQuery(query).
 					WithArgs(user.ID).
 					WillReturnRows(
-						test.NewRows("id", "name", "balance", "created_at", "updated_at").
-							AddRow(user.ID, user.Name, user.Balance, user.CreatedAt, nil),
+						test.NewRows("id", "name", "account_type", "status", "balance", "held_balance", "available_balance", "credit_limit", "interest_rate", "created_at", "updated_at").
+							AddRow(user.ID, user.Name, user.AccountType, user.Status, user.Balance, user.HeldBalance, user.AvailableBalance, user.CreditLimit, user.InterestRate, user.CreatedAt, nil),
 					)
 			},
 		},
@@ -193,3 +185,211 @@ func TestReadOneById(t *testing.T) {
 		})
 	}
 }
+
+func TestReadOneByIdForUpdate(t *testing.T) {
+	query := "SELECT u.id, u.name, a.account_type, u.status, a.balance, a.held_balance, a.balance - a.held_balance AS available_balance, a.credit_limit, a.interest_rate, u.created_at, u.updated_at FROM users u JOIN accounts a ON a.id = u.id WHERE u.id = ? FOR UPDATE"
+
+	user := &entity.User{
+		ID:               uuid.NewId(),
+		Name:             "Gabriel",
+		Balance:          money.New(0),
+		HeldBalance:      money.New(0),
+		AvailableBalance: money.New(0),
+		CreditLimit:      money.New(0),
+	}
+
+	cases := map[string]struct {
+		InputUserId    string
+		ExpectedResult *entity.User
+		ExpectedErr    error
+		PrepareMock    func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			InputUserId:    user.ID,
+			ExpectedResult: user,
+			ExpectedErr:    nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs(user.ID).
+					WillReturnRows(
+						test.NewRows("id", "name", "account_type", "status", "balance", "held_balance", "available_balance", "credit_limit", "interest_rate", "created_at", "updated_at").
+							AddRow(user.ID, user.Name, user.AccountType, user.Status, user.Balance, user.HeldBalance, user.AvailableBalance, user.CreditLimit, user.InterestRate, user.CreatedAt, nil),
+					)
+			},
+		},
+		"deve retornar erro": {
+			InputUserId:    user.ID,
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrNotFound,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs(user.ID).
+					WillReturnError(
+						echo.ErrNotFound,
+					)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseUser(dbConn)
+			ctx := context.Background()
+
+			users, err := db.ReadOneByIdForUpdate(ctx, cs.InputUserId)
+			if diff := cmp.Diff(users, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestUpdateStatus(t *testing.T) {
+	query := "UPDATE users SET status = ? WHERE id = ?"
+
+	cases := map[string]struct {
+		ExpectedErr error
+		PrepareMock func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			ExpectedErr: nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(query).
+					WithArgs(entity.FROZEN, "user-id").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+		},
+		"deve retornar erro": {
+			ExpectedErr: echo.ErrInternalServerError,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(query).
+					WithArgs(entity.FROZEN, "user-id").
+					WillReturnError(echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseUser(dbConn)
+			ctx := context.Background()
+
+			err := db.UpdateStatus(ctx, "user-id", entity.FROZEN)
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestCreateStatusChange(t *testing.T) {
+	query := "INSERT INTO user_status_history (user_id, from_status, to_status, reason) VALUES (?, ?, ?, ?)"
+
+	change := &entity.StatusChange{UserId: "user-id", From: entity.ACTIVE, To: entity.FROZEN, Reason: "suspected fraud"}
+
+	cases := map[string]struct {
+		ExpectedErr error
+		PrepareMock func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			ExpectedErr: nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(query).
+					WithArgs("user-id", entity.ACTIVE, entity.FROZEN, "suspected fraud").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+		},
+		"deve retornar erro": {
+			ExpectedErr: echo.ErrInternalServerError,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(query).
+					WithArgs("user-id", entity.ACTIVE, entity.FROZEN, "suspected fraud").
+					WillReturnError(echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseUser(dbConn)
+			ctx := context.Background()
+
+			err := db.CreateStatusChange(ctx, change)
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestReadStatusHistory(t *testing.T) {
+	query := "SELECT id, user_id, from_status, to_status, reason, created_at FROM user_status_history WHERE user_id = ? ORDER BY id"
+
+	changes := []entity.StatusChange{{
+		ID:     1,
+		UserId: "user-id",
+		From:   entity.ACTIVE,
+		To:     entity.FROZEN,
+		Reason: "suspected fraud",
+	}}
+
+	cases := map[string]struct {
+		ExpectedResult []entity.StatusChange
+		ExpectedErr    error
+		PrepareMock    func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			ExpectedResult: changes,
+			ExpectedErr:    nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs("user-id").
+					WillReturnRows(
+						test.NewRows("id", "user_id", "from_status", "to_status", "reason", "created_at").
+							AddRow(1, "user-id", entity.ACTIVE, entity.FROZEN, "suspected fraud", nil),
+					)
+			},
+		},
+		"deve retornar erro": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrInternalServerError,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs("user-id").
+					WillReturnError(echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseUser(dbConn)
+			ctx := context.Background()
+
+			changes, err := db.ReadStatusHistory(ctx, "user-id")
+			if diff := cmp.Diff(changes, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+package webhook
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	"github.com/jmoiron/sqlx"
+	"github.com/labstack/echo/v4"
+)
+
+type DabataseWebhookInterface interface {
+	CreateSubscription(ctx context.Context, subscription *entity.WebhookSubscription) error
+	ReadSubscriptions(ctx context.Context) ([]entity.WebhookSubscription, error)
+	ReadSubscriptionById(ctx context.Context, id string) (*entity.WebhookSubscription, error)
+	ReadSubscriptionsByEventType(ctx context.Context, eventType string) ([]entity.WebhookSubscription, error)
+	DeactivateSubscription(ctx context.Context, id string) error
+	CreateDelivery(ctx context.Context, delivery *entity.WebhookDelivery) error
+	ReadDeliveries(ctx context.Context, subscriptionId string) ([]entity.WebhookDelivery, error)
+	ReadDeliveryById(ctx context.Context, id string) (*entity.WebhookDelivery, error)
+	ReadDueDeliveries(ctx context.Context, now time.Time, limit int) ([]entity.WebhookDelivery, error)
+	UpdateNextAttemptAt(ctx context.Context, ids []string, nextAttemptAt time.Time) error
+	UpdateDelivery(ctx context.Context, delivery *entity.WebhookDelivery) error
+}
+
+type dbImpl struct {
+	dbConn sqlx.ExtContext
+}
+
+func NewDatabaseWebhook(dbConn sqlx.ExtContext) DabataseWebhookInterface {
+	return &dbImpl{dbConn}
+}
+
+func (w *dbImpl) CreateSubscription(ctx context.Context, subscription *entity.WebhookSubscription) error {
+	query := "INSERT INTO webhook_subscriptions (id, url, event_types, secret, active) VALUES (?, ?, ?, ?, ?)"
+
+	_, err := w.dbConn.ExecContext(ctx, query, subscription.ID, subscription.URL, subscription.EventTypes, subscription.Secret, subscription.Active)
+	if err != nil {
+		log.Println("Error create webhook subscription: ", err.Error())
+		return echo.ErrInternalServerError
+	}
+
+	return nil
+}
+
+func (w *dbImpl) ReadSubscriptions(ctx context.Context) ([]entity.WebhookSubscription, error) {
+	subscriptions := make([]entity.WebhookSubscription, 0)
+	query := "SELECT id, url, event_types, secret, active, created_at FROM webhook_subscriptions ORDER BY created_at"
+
+	err := sqlx.SelectContext(ctx, w.dbConn, &subscriptions, query)
+	if err != nil {
+		log.Println("Error ReadSubscriptions webhook subscriptions: ", err.Error())
+		return nil, echo.ErrInternalServerError
+	}
+
+	return subscriptions, nil
+}
+
+func (w *dbImpl) ReadSubscriptionById(ctx context.Context, id string) (*entity.WebhookSubscription, error) {
+	subscription := new(entity.WebhookSubscription)
+	query := "SELECT id, url, event_types, secret, active, created_at FROM webhook_subscriptions WHERE id = ?"
+
+	err := sqlx.GetContext(ctx, w.dbConn, subscription, query, id)
+	if err != nil {
+		log.Println("Error ReadSubscriptionById webhook subscription: ", err.Error())
+		return nil, echo.ErrNotFound
+	}
+
+	return subscription, nil
+}
+
+// ReadSubscriptionsByEventType lists the active subscriptions to the event
+// type.
+func (w *dbImpl) ReadSubscriptionsByEventType(ctx context.Context, eventType string) ([]entity.WebhookSubscription, error) {
+	subscriptions := make([]entity.WebhookSubscription, 0)
+	query := "SELECT id, url, event_types, secret, active, created_at FROM webhook_subscriptions WHERE active = TRUE AND FIND_IN_SET(?, event_types) > 0"
+
+	err := sqlx.SelectContext(ctx, w.dbConn, &subscriptions, query, eventType)
+	if err != nil {
+		log.Println("Error ReadSubscriptionsByEventType webhook subscriptions: ", err.Error())
+		return nil, echo.ErrInternalServerError
+	}
+
+	return subscriptions, nil
+}
+
+func (w *dbImpl) DeactivateSubscription(ctx context.Context, id string) error {
+	query := "UPDATE webhook_subscriptions SET active = FALSE WHERE id = ?"
+
+	_, err := w.dbConn.ExecContext(ctx, query, id)
+	if err != nil {
+		log.Println("Error deactivate webhook subscription: ", err.Error())
+		return echo.ErrInternalServerError
+	}
+
+	return nil
+}
+
+// CreateDelivery ignores a delivery of an event already delivered to the
+// subscription as the same type, so an event relayed again isn't sent twice.
+func (w *dbImpl) CreateDelivery(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	query := "INSERT IGNORE INTO webhook_deliveries (id, subscription_id, event_id, event_type, payload, state, attempts, next_attempt_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)"
+
+	_, err := w.dbConn.ExecContext(ctx, query,
+		delivery.ID,
+		delivery.SubscriptionId,
+		delivery.EventId,
+		delivery.EventType,
+		[]byte(delivery.Payload),
+		delivery.State,
+		delivery.Attempts,
+		delivery.NextAttemptAt,
+	)
+	if err != nil {
+		log.Println("Error create webhook delivery: ", err.Error())
+		return echo.ErrInternalServerError
+	}
+
+	return nil
+}
+
+// ReadDeliveries lists the deliveries of the subscription, newest first.
+func (w *dbImpl) ReadDeliveries(ctx context.Context, subscriptionId string) ([]entity.WebhookDelivery, error) {
+	deliveries := make([]entity.WebhookDelivery, 0)
+	query := "SELECT id, subscription_id, event_id, event_type, payload, state, attempts, next_attempt_at, last_status_code, last_error, delivered_at, created_at FROM webhook_deliveries WHERE subscription_id = ? ORDER BY created_at DESC"
+
+	err := sqlx.SelectContext(ctx, w.dbConn, &deliveries, query, subscriptionId)
+	if err != nil {
+		log.Println("Error ReadDeliveries webhook deliveries: ", err.Error())
+		return nil, echo.ErrInternalServerError
+	}
+
+	return deliveries, nil
+}
+
+func (w *dbImpl) ReadDeliveryById(ctx context.Context, id string) (*entity.WebhookDelivery, error) {
+	delivery := new(entity.WebhookDelivery)
+	query := "SELECT id, subscription_id, event_id, event_type, payload, state, attempts, next_attempt_at, last_status_code, last_error, delivered_at, created_at FROM webhook_deliveries WHERE id = ?"
+
+	err := sqlx.GetContext(ctx, w.dbConn, delivery, query, id)
+	if err != nil {
+		log.Println("Error ReadDeliveryById webhook delivery: ", err.Error())
+		return nil, echo.ErrNotFound
+	}
+
+	return delivery, nil
+}
+
+// ReadDueDeliveries reads the pending deliveries due by now and locks them,
+// skipping the ones another worker has locked.
+func (w *dbImpl) ReadDueDeliveries(ctx context.Context, now time.Time, limit int) ([]entity.WebhookDelivery, error) {
+	deliveries := make([]entity.WebhookDelivery, 0)
+	query := "SELECT id, subscription_id, event_id, event_type, payload, state, attempts, next_attempt_at, last_status_code, last_error, delivered_at, created_at FROM webhook_deliveries WHERE state = ? AND next_attempt_at <= ? ORDER BY next_attempt_at LIMIT ? FOR UPDATE SKIP LOCKED"
+
+	err := sqlx.SelectContext(ctx, w.dbConn, &deliveries, query, entity.DELIVERY_PENDING, now, limit)
+	if err != nil {
+		log.Println("Error ReadDueDeliveries webhook deliveries: ", err.Error())
+		return nil, echo.ErrInternalServerError
+	}
+
+	return deliveries, nil
+}
+
+func (w *dbImpl) UpdateNextAttemptAt(ctx context.Context, ids []string, nextAttemptAt time.Time) error {
+	placeholders := make([]string, 0, len(ids))
+	args := make([]interface{}, 0, len(ids)+1)
+	args = append(args, nextAttemptAt)
+	for _, id := range ids {
+		placeholders = append(placeholders, "?")
+		args = append(args, id)
+	}
+
+	query := "UPDATE webhook_deliveries SET next_attempt_at = ? WHERE id IN (" + strings.Join(placeholders, ", ") + ")"
+
+	_, err := w.dbConn.ExecContext(ctx, query, args...)
+	if err != nil {
+		log.Println("Error update next attempt webhook deliveries: ", err.Error())
+		return echo.ErrInternalServerError
+	}
+
+	return nil
+}
+
+// UpdateDelivery records the outcome of an attempt.
+func (w *dbImpl) UpdateDelivery(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	query := "UPDATE webhook_deliveries SET state = ?, attempts = ?, next_attempt_at = ?, last_status_code = ?, last_error = ?, delivered_at = ? WHERE id = ?"
+
+	_, err := w.dbConn.ExecContext(ctx, query,
+		delivery.State,
+		delivery.Attempts,
+		delivery.NextAttemptAt,
+		delivery.LastStatusCode,
+		delivery.LastError,
+		delivery.DeliveredAt,
+		delivery.ID,
+	)
+	if err != nil {
+		log.Println("Error update webhook delivery: ", err.Error())
+		return echo.ErrInternalServerError
+	}
+
+	return nil
+}
@@ -0,0 +1,591 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	"github.com/garoque/backend-code-challenge-snapfi/internal/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/labstack/echo/v4"
+)
+
+var (
+	subscriptionColumns = []string{"id", "url", "event_types", "secret", "active", "created_at"}
+	deliveryColumns     = []string{"id", "subscription_id", "event_id", "event_type", "payload", "state", "attempts", "next_attempt_at", "last_status_code", "last_error", "delivered_at", "created_at"}
+	createdAt           = time.Date(2023, 5, 21, 9, 0, 0, 0, time.UTC)
+)
+
+func TestCreateSubscription(t *testing.T) {
+	query := "INSERT INTO webhook_subscriptions (id, url, event_types, secret, active) VALUES (?, ?, ?, ?, ?)"
+
+	subscription := &entity.WebhookSubscription{
+		ID:         "subscription-id",
+		URL:        "https://partner.example.com",
+		EventTypes: entity.WebhookEventTypes{"transaction.booked", "deposit.booked"},
+		Secret:     "whsec_test",
+		Active:     true,
+	}
+
+	cases := map[string]struct {
+		ExpectedErr error
+		PrepareMock func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			ExpectedErr: nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(query).
+					WithArgs("subscription-id", "https://partner.example.com", "transaction.booked,deposit.booked", "whsec_test", true).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+		},
+		"deve retornar erro": {
+			ExpectedErr: echo.ErrInternalServerError,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(query).
+					WithArgs("subscription-id", "https://partner.example.com", "transaction.booked,deposit.booked", "whsec_test", true).
+					WillReturnError(echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseWebhook(dbConn)
+			ctx := context.Background()
+
+			err := db.CreateSubscription(ctx, subscription)
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestReadSubscriptions(t *testing.T) {
+	query := "SELECT id, url, event_types, secret, active, created_at FROM webhook_subscriptions ORDER BY created_at"
+
+	cases := map[string]struct {
+		ExpectedResult []entity.WebhookSubscription
+		ExpectedErr    error
+		PrepareMock    func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			ExpectedResult: []entity.WebhookSubscription{{
+				ID:         "subscription-id",
+				URL:        "https://partner.example.com",
+				EventTypes: entity.WebhookEventTypes{"transaction.failed"},
+				Secret:     "whsec_test",
+				Active:     true,
+				CreatedAt:  &createdAt,
+			}},
+			ExpectedErr: nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WillReturnRows(test.NewRows(subscriptionColumns...).AddRow("subscription-id", "https://partner.example.com", "transaction.failed", "whsec_test", true, createdAt))
+			},
+		},
+		"deve retornar erro": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrInternalServerError,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WillReturnError(echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseWebhook(dbConn)
+			ctx := context.Background()
+
+			subscriptions, err := db.ReadSubscriptions(ctx)
+			if diff := cmp.Diff(subscriptions, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestReadSubscriptionById(t *testing.T) {
+	query := "SELECT id, url, event_types, secret, active, created_at FROM webhook_subscriptions WHERE id = ?"
+
+	cases := map[string]struct {
+		ExpectedResult *entity.WebhookSubscription
+		ExpectedErr    error
+		PrepareMock    func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			ExpectedResult: &entity.WebhookSubscription{
+				ID:         "subscription-id",
+				URL:        "https://partner.example.com",
+				EventTypes: entity.WebhookEventTypes{"transaction.booked"},
+				Secret:     "whsec_test",
+				Active:     false,
+				CreatedAt:  &createdAt,
+			},
+			ExpectedErr: nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs("subscription-id").
+					WillReturnRows(test.NewRows(subscriptionColumns...).AddRow("subscription-id", "https://partner.example.com", "transaction.booked", "whsec_test", false, createdAt))
+			},
+		},
+		"deve retornar erro": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrNotFound,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs("subscription-id").
+					WillReturnRows(test.NewRows(subscriptionColumns...))
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseWebhook(dbConn)
+			ctx := context.Background()
+
+			subscription, err := db.ReadSubscriptionById(ctx, "subscription-id")
+			if diff := cmp.Diff(subscription, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestReadSubscriptionsByEventType(t *testing.T) {
+	query := "SELECT id, url, event_types, secret, active, created_at FROM webhook_subscriptions WHERE active = TRUE AND FIND_IN_SET(?, event_types) > 0"
+
+	cases := map[string]struct {
+		ExpectedResult []entity.WebhookSubscription
+		ExpectedErr    error
+		PrepareMock    func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			ExpectedResult: []entity.WebhookSubscription{{
+				ID:         "subscription-id",
+				URL:        "https://partner.example.com",
+				EventTypes: entity.WebhookEventTypes{"transaction.booked", "deposit.booked"},
+				Secret:     "whsec_test",
+				Active:     true,
+				CreatedAt:  &createdAt,
+			}},
+			ExpectedErr: nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs("deposit.booked").
+					WillReturnRows(test.NewRows(subscriptionColumns...).AddRow("subscription-id", "https://partner.example.com", "transaction.booked,deposit.booked", "whsec_test", true, createdAt))
+			},
+		},
+		"deve retornar erro": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrInternalServerError,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs("deposit.booked").
+					WillReturnError(echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseWebhook(dbConn)
+			ctx := context.Background()
+
+			subscriptions, err := db.ReadSubscriptionsByEventType(ctx, "deposit.booked")
+			if diff := cmp.Diff(subscriptions, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestDeactivateSubscription(t *testing.T) {
+	query := "UPDATE webhook_subscriptions SET active = FALSE WHERE id = ?"
+
+	cases := map[string]struct {
+		ExpectedErr error
+		PrepareMock func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			ExpectedErr: nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(query).
+					WithArgs("subscription-id").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		"deve retornar erro": {
+			ExpectedErr: echo.ErrInternalServerError,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(query).
+					WithArgs("subscription-id").
+					WillReturnError(echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseWebhook(dbConn)
+			ctx := context.Background()
+
+			err := db.DeactivateSubscription(ctx, "subscription-id")
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestCreateDelivery(t *testing.T) {
+	query := "INSERT IGNORE INTO webhook_deliveries (id, subscription_id, event_id, event_type, payload, state, attempts, next_attempt_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)"
+
+	delivery := &entity.WebhookDelivery{
+		ID:             "delivery-id",
+		SubscriptionId: "subscription-id",
+		EventId:        "event-id",
+		EventType:      "transaction.booked",
+		Payload:        json.RawMessage(`{"id":"event-id"}`),
+		State:          entity.DELIVERY_PENDING,
+		NextAttemptAt:  &createdAt,
+	}
+
+	cases := map[string]struct {
+		ExpectedErr error
+		PrepareMock func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			ExpectedErr: nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(query).
+					WithArgs("delivery-id", "subscription-id", "event-id", "transaction.booked", []byte(`{"id":"event-id"}`), entity.DELIVERY_PENDING, 0, &createdAt).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+		},
+		"deve retornar erro": {
+			ExpectedErr: echo.ErrInternalServerError,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(query).
+					WithArgs("delivery-id", "subscription-id", "event-id", "transaction.booked", []byte(`{"id":"event-id"}`), entity.DELIVERY_PENDING, 0, &createdAt).
+					WillReturnError(echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseWebhook(dbConn)
+			ctx := context.Background()
+
+			err := db.CreateDelivery(ctx, delivery)
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestReadDeliveries(t *testing.T) {
+	query := "SELECT id, subscription_id, event_id, event_type, payload, state, attempts, next_attempt_at, last_status_code, last_error, delivered_at, created_at FROM webhook_deliveries WHERE subscription_id = ? ORDER BY created_at DESC"
+	statusCode := 500
+	lastError := "unexpected status 500"
+
+	cases := map[string]struct {
+		ExpectedResult []entity.WebhookDelivery
+		ExpectedErr    error
+		PrepareMock    func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			ExpectedResult: []entity.WebhookDelivery{{
+				ID:             "delivery-id",
+				SubscriptionId: "subscription-id",
+				EventId:        "event-id",
+				EventType:      "transaction.failed",
+				Payload:        json.RawMessage(`{"id":"event-id"}`),
+				State:          entity.DELIVERY_DEAD,
+				Attempts:       8,
+				LastStatusCode: &statusCode,
+				LastError:      &lastError,
+				CreatedAt:      &createdAt,
+			}},
+			ExpectedErr: nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs("subscription-id").
+					WillReturnRows(test.NewRows(deliveryColumns...).AddRow("delivery-id", "subscription-id", "event-id", "transaction.failed", []byte(`{"id":"event-id"}`), 2, 8, nil, 500, lastError, nil, createdAt))
+			},
+		},
+		"deve retornar erro": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrInternalServerError,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs("subscription-id").
+					WillReturnError(echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseWebhook(dbConn)
+			ctx := context.Background()
+
+			deliveries, err := db.ReadDeliveries(ctx, "subscription-id")
+			if diff := cmp.Diff(deliveries, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestReadDeliveryById(t *testing.T) {
+	query := "SELECT id, subscription_id, event_id, event_type, payload, state, attempts, next_attempt_at, last_status_code, last_error, delivered_at, created_at FROM webhook_deliveries WHERE id = ?"
+
+	cases := map[string]struct {
+		ExpectedResult *entity.WebhookDelivery
+		ExpectedErr    error
+		PrepareMock    func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			ExpectedResult: &entity.WebhookDelivery{
+				ID:             "delivery-id",
+				SubscriptionId: "subscription-id",
+				EventId:        "event-id",
+				EventType:      "transaction.booked",
+				Payload:        json.RawMessage(`{"id":"event-id"}`),
+				State:          entity.DELIVERY_PENDING,
+				NextAttemptAt:  &createdAt,
+				CreatedAt:      &createdAt,
+			},
+			ExpectedErr: nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs("delivery-id").
+					WillReturnRows(test.NewRows(deliveryColumns...).AddRow("delivery-id", "subscription-id", "event-id", "transaction.booked", []byte(`{"id":"event-id"}`), 0, 0, createdAt, nil, nil, nil, createdAt))
+			},
+		},
+		"deve retornar erro": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrNotFound,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs("delivery-id").
+					WillReturnRows(test.NewRows(deliveryColumns...))
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseWebhook(dbConn)
+			ctx := context.Background()
+
+			delivery, err := db.ReadDeliveryById(ctx, "delivery-id")
+			if diff := cmp.Diff(delivery, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestReadDueDeliveries(t *testing.T) {
+	query := "SELECT id, subscription_id, event_id, event_type, payload, state, attempts, next_attempt_at, last_status_code, last_error, delivered_at, created_at FROM webhook_deliveries WHERE state = ? AND next_attempt_at <= ? ORDER BY next_attempt_at LIMIT ? FOR UPDATE SKIP LOCKED"
+	now := createdAt.Add(time.Minute)
+
+	cases := map[string]struct {
+		ExpectedResult []entity.WebhookDelivery
+		ExpectedErr    error
+		PrepareMock    func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			ExpectedResult: []entity.WebhookDelivery{{
+				ID:             "delivery-id",
+				SubscriptionId: "subscription-id",
+				EventId:        "event-id",
+				EventType:      "transaction.booked",
+				Payload:        json.RawMessage(`{"id":"event-id"}`),
+				State:          entity.DELIVERY_PENDING,
+				NextAttemptAt:  &createdAt,
+				CreatedAt:      &createdAt,
+			}},
+			ExpectedErr: nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs(entity.DELIVERY_PENDING, now, 20).
+					WillReturnRows(test.NewRows(deliveryColumns...).AddRow("delivery-id", "subscription-id", "event-id", "transaction.booked", []byte(`{"id":"event-id"}`), 0, 0, createdAt, nil, nil, nil, createdAt))
+			},
+		},
+		"deve retornar erro": {
+			ExpectedResult: nil,
+			ExpectedErr:    echo.ErrInternalServerError,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).
+					WithArgs(entity.DELIVERY_PENDING, now, 20).
+					WillReturnError(echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseWebhook(dbConn)
+			ctx := context.Background()
+
+			deliveries, err := db.ReadDueDeliveries(ctx, now, 20)
+			if diff := cmp.Diff(deliveries, cs.ExpectedResult); diff != "" {
+				t.Error(diff)
+			}
+
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestUpdateNextAttemptAt(t *testing.T) {
+	query := "UPDATE webhook_deliveries SET next_attempt_at = ? WHERE id IN (?, ?)"
+
+	cases := map[string]struct {
+		ExpectedErr error
+		PrepareMock func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			ExpectedErr: nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(query).
+					WithArgs(createdAt, "first-delivery-id", "second-delivery-id").
+					WillReturnResult(sqlmock.NewResult(0, 2))
+			},
+		},
+		"deve retornar erro": {
+			ExpectedErr: echo.ErrInternalServerError,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(query).
+					WithArgs(createdAt, "first-delivery-id", "second-delivery-id").
+					WillReturnError(echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseWebhook(dbConn)
+			ctx := context.Background()
+
+			err := db.UpdateNextAttemptAt(ctx, []string{"first-delivery-id", "second-delivery-id"}, createdAt)
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestUpdateDelivery(t *testing.T) {
+	query := "UPDATE webhook_deliveries SET state = ?, attempts = ?, next_attempt_at = ?, last_status_code = ?, last_error = ?, delivered_at = ? WHERE id = ?"
+	statusCode := 204
+
+	delivery := &entity.WebhookDelivery{
+		ID:             "delivery-id",
+		State:          entity.DELIVERY_DELIVERED,
+		Attempts:       1,
+		LastStatusCode: &statusCode,
+		DeliveredAt:    &createdAt,
+	}
+
+	cases := map[string]struct {
+		ExpectedErr error
+		PrepareMock func(mock sqlmock.Sqlmock)
+	}{
+		"deve retornar sucesso": {
+			ExpectedErr: nil,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(query).
+					WithArgs(entity.DELIVERY_DELIVERED, 1, nil, &statusCode, nil, &createdAt, "delivery-id").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		"deve retornar erro": {
+			ExpectedErr: echo.ErrInternalServerError,
+			PrepareMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(query).
+					WithArgs(entity.DELIVERY_DELIVERED, 1, nil, &statusCode, nil, &createdAt, "delivery-id").
+					WillReturnError(echo.ErrInternalServerError)
+			},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			dbConn, mock := test.GetDB()
+			cs.PrepareMock(mock)
+
+			db := NewDatabaseWebhook(dbConn)
+			ctx := context.Background()
+
+			err := db.UpdateDelivery(ctx, delivery)
+			if diff := cmp.Diff(err, cs.ExpectedErr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/garoque/backend-code-challenge-snapfi/internal/api/dto"
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/money"
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/uuid"
+)
+
+type AccountType int
+
+// The values are stored in the database, so new types must be appended.
+const (
+	PERSONAL AccountType = iota
+	BUSINESS
+)
+
+var AccountTypeString = []string{
+	"PERSONAL", "BUSINESS",
+}
+
+func (a AccountType) String() string {
+	return AccountTypeString[a]
+}
+
+// ParseAccountType returns the account type named s, reporting whether it
+// exists.
+func ParseAccountType(s string) (AccountType, bool) {
+	for i, name := range AccountTypeString {
+		if name == s {
+			return AccountType(i), true
+		}
+	}
+
+	return 0, false
+}
+
+// Account is a balance owned by a user, so they can keep, say, their personal
+// and business money apart. Every user has a default account sharing their
+// ID, opened along with them, so a user ID still addresses a balance wherever
+// an account ID is expected. Amounts are in the currency of the account, and
+// money only moves between accounts of the same currency.
+//
+// Balances come in two flavours: Balance is the ledger balance, the money the
+// account actually has, while AvailableBalance also discounts the funds
+// reserved by outstanding authorizations and is what can still be spent.
+//
+// Accounts with a credit line can take AvailableBalance below zero, down to
+// minus CreditLimit. UsedCredit and AvailableCredit are filled by SetCredit.
+// InterestRate is the monthly rate charged on a negative Balance, in basis
+// points. Status is the one of the owner, see StatusUser.
+type Account struct {
+	ID                string      `json:"id"`
+	UserId            string      `json:"userId" db:"user_id"`
+	Name              string      `json:"name"`
+	AccountType       AccountType `json:"-" db:"account_type"`
+	AccountTypeString string      `json:"accountType,omitempty"`
+	Currency          string      `json:"currency" example:"BRL"`
+	Status            StatusUser  `json:"-" db:"status"`
+	Balance           money.Money `json:"balance" swaggertype:"string" example:"100.10"`
+	HeldBalance       money.Money `json:"heldBalance" db:"held_balance" swaggertype:"string" example:"0.00"`
+	AvailableBalance  money.Money `json:"availableBalance" db:"available_balance" swaggertype:"string" example:"100.10"`
+	CreditLimit       money.Money `json:"creditLimit" db:"credit_limit" swaggertype:"string" example:"500.00"`
+	UsedCredit        money.Money `json:"usedCredit" db:"-" swaggertype:"string" example:"0.00"`
+	AvailableCredit   money.Money `json:"availableCredit" db:"-" swaggertype:"string" example:"500.00"`
+	InterestRate      int64       `json:"interestRate" db:"interest_rate" example:"800"`
+	InterestAccruedOn *time.Time  `json:"-" db:"interest_accrued_on"`
+	CreatedAt         time.Time   `json:"createdAt" db:"created_at"`
+	UpdatedAt         *time.Time  `json:"updatedAt,omitempty" db:"updated_at"`
+}
+
+func NewAccount(account dto.CreateAccount) *Account {
+	accountType, _ := ParseAccountType(account.AccountType)
+
+	currency := account.Currency
+	if currency == "" {
+		currency = money.DefaultCurrency
+	}
+
+	return &Account{
+		ID:               uuid.NewId(),
+		UserId:           account.UserId,
+		Name:             account.Name,
+		AccountType:      accountType,
+		Currency:         currency,
+		Balance:          money.New(0),
+		HeldBalance:      money.New(0),
+		AvailableBalance: money.New(0),
+		CreditLimit:      money.New(0),
+	}
+}
+
+// NewDefaultAccount returns the default account of the user, in the default
+// currency and of the account type the user was created with.
+func NewDefaultAccount(user *User) *Account {
+	return &Account{
+		ID:               user.ID,
+		UserId:           user.ID,
+		AccountType:      user.AccountType,
+		Currency:         money.DefaultCurrency,
+		Balance:          money.New(0),
+		HeldBalance:      money.New(0),
+		AvailableBalance: money.New(0),
+		CreditLimit:      money.New(0),
+	}
+}
+
+// AccountId returns the account a request addresses: accountId when it is
+// given, or else the default account of userId.
+func AccountId(accountId, userId string) string {
+	if accountId != "" {
+		return accountId
+	}
+
+	return userId
+}
+
+// IsDefault reports whether the account is the default account of its owner.
+func (a *Account) IsDefault() bool {
+	return a.ID == a.UserId
+}
+
+// CanSpend reports whether amount fits in the available balance of the
+// account plus its credit line.
+func (a *Account) CanSpend(amount money.Money) bool {
+	return amount.Amount-a.CreditLimit.Amount <= a.AvailableBalance.Amount
+}
+
+// SetCredit fills UsedCredit, the part of the credit line taken by a negative
+// available balance, and AvailableCredit, what is left of it.
+func (a *Account) SetCredit() *Account {
+	a.UsedCredit, a.AvailableCredit = credit(a.AvailableBalance, a.CreditLimit)
+
+	return a
+}
+
+func credit(availableBalance, creditLimit money.Money) (used, available money.Money) {
+	used = money.New(0)
+	if availableBalance.IsNegative() {
+		used = availableBalance.Neg()
+	}
+
+	available = money.New(0)
+	if used.LessThan(creditLimit) {
+		available = money.New(creditLimit.Amount - used.Amount)
+	}
+
+	return used, available
+}
+
+// DailyInterest returns a day of interest on the negative balance of the
+// account, a thirtieth of the monthly InterestRate, rounded half up.
+func (a *Account) DailyInterest() money.Money {
+	if !a.Balance.IsNegative() || a.InterestRate <= 0 {
+		return money.New(0)
+	}
+
+	// Split the product so large balances can't overflow int64.
+	const divisor = 30 * 10000
+	debt := a.Balance.Neg().Amount
+	return money.New(debt/divisor*a.InterestRate + (debt%divisor*a.InterestRate+divisor/2)/divisor)
+}
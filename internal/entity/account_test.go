@@ -0,0 +1,92 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/garoque/backend-code-challenge-snapfi/internal/api/dto"
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewAccount(t *testing.T) {
+	account := NewAccount(dto.CreateAccount{UserId: "user-id", Name: "MEI"})
+	assert.NotEmpty(t, account.ID)
+	assert.Equal(t, "user-id", account.UserId)
+	assert.Equal(t, "MEI", account.Name)
+	assert.Equal(t, PERSONAL, account.AccountType)
+	assert.Equal(t, "BRL", account.Currency)
+	assert.Equal(t, money.New(0), account.Balance)
+	assert.False(t, account.IsDefault())
+
+	business := NewAccount(dto.CreateAccount{UserId: "user-id", Name: "Loja", AccountType: "BUSINESS", Currency: "USD"})
+	assert.Equal(t, BUSINESS, business.AccountType)
+	assert.Equal(t, "USD", business.Currency)
+}
+
+func TestNewDefaultAccount(t *testing.T) {
+	user := NewUser(dto.CreateUser{Name: "Loja", AccountType: "BUSINESS"})
+
+	account := NewDefaultAccount(user)
+	assert.Equal(t, user.ID, account.ID)
+	assert.Equal(t, user.ID, account.UserId)
+	assert.Equal(t, BUSINESS, account.AccountType)
+	assert.Equal(t, "BRL", account.Currency)
+	assert.True(t, account.IsDefault())
+}
+
+func TestAccountId(t *testing.T) {
+	assert.Equal(t, "account-id", AccountId("account-id", "user-id"))
+	assert.Equal(t, "user-id", AccountId("", "user-id"))
+}
+
+func TestParseAccountType(t *testing.T) {
+	accountType, ok := ParseAccountType("BUSINESS")
+	assert.True(t, ok)
+	assert.Equal(t, BUSINESS, accountType)
+	assert.Equal(t, "BUSINESS", accountType.String())
+
+	_, ok = ParseAccountType("UNKNOWN")
+	assert.False(t, ok)
+}
+
+func TestCanSpend(t *testing.T) {
+	account := &Account{AvailableBalance: money.New(1000), CreditLimit: money.New(500)}
+	assert.True(t, account.CanSpend(money.New(1500)))
+	assert.False(t, account.CanSpend(money.New(1501)))
+
+	overdrawn := &Account{AvailableBalance: money.New(-400), CreditLimit: money.New(500)}
+	assert.True(t, overdrawn.CanSpend(money.New(100)))
+	assert.False(t, overdrawn.CanSpend(money.New(101)))
+
+	noCredit := &Account{AvailableBalance: money.New(1000)}
+	assert.True(t, noCredit.CanSpend(money.New(1000)))
+	assert.False(t, noCredit.CanSpend(money.New(1001)))
+}
+
+func TestSetCreditAccount(t *testing.T) {
+	account := (&Account{AvailableBalance: money.New(-200), CreditLimit: money.New(500)}).SetCredit()
+	assert.Equal(t, money.New(200), account.UsedCredit)
+	assert.Equal(t, money.New(300), account.AvailableCredit)
+}
+
+func TestDailyInterest(t *testing.T) {
+	cases := map[string]struct {
+		Balance      int64
+		InterestRate int64
+		Expected     int64
+	}{
+		"saldo negativo":          {-100000, 900, 300},
+		"arredonda para baixo":    {-10050, 900, 30},
+		"arredonda para cima":     {-10000, 800, 27},
+		"saldo positivo":          {100000, 900, 0},
+		"sem taxa":                {-100000, 0, 0},
+		"saldo alto sem overflow": {-9000000000000000000, 10000, 300000000000000000},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			account := &Account{Balance: money.New(cs.Balance), InterestRate: cs.InterestRate}
+			assert.Equal(t, money.New(cs.Expected), account.DailyInterest())
+		})
+	}
+}
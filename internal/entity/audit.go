@@ -0,0 +1,140 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/money"
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/uuid"
+)
+
+// The entities whose changes are audited, besides users and transactions which
+// share their names with the aggregates of the outbox.
+const (
+	AUDIT_ACCOUNT        = "account"
+	AUDIT_FEE_RULE       = "fee_rule"
+	AUDIT_LIMITS         = "limits"
+	AUDIT_STANDING_ORDER = "standing_order"
+	AUDIT_WEBHOOK        = "webhook"
+	AUDIT_RECONCILIATION = "reconciliation"
+)
+
+// The operations of the audit log, named after the change they record.
+// Transactions changing state are recorded as their event type, such as
+// transaction.booked.
+const (
+	AUDIT_USER_CREATED             = "user.created"
+	AUDIT_USER_CREDIT_LINE_UPDATED = "user.credit_line_updated"
+	AUDIT_USER_STATUS_UPDATED      = "user.status_updated"
+	AUDIT_ACCOUNT_CREATED          = "account.created"
+	AUDIT_ACCOUNT_BALANCE_UPDATED  = "account.balance_updated"
+	AUDIT_TRANSACTION_CREATED      = "transaction.created"
+	AUDIT_FEE_RULE_CREATED         = "fee_rule.created"
+	AUDIT_FEE_RULE_DEACTIVATED     = "fee_rule.deactivated"
+	AUDIT_LIMITS_UPDATED           = "limits.updated"
+	AUDIT_LIMITS_DELETED           = "limits.deleted"
+	AUDIT_STANDING_ORDER_CREATED   = "standing_order.created"
+	AUDIT_STANDING_ORDER_UPDATED   = "standing_order.updated"
+	AUDIT_STANDING_ORDER_CANCELLED = "standing_order.cancelled"
+	AUDIT_WEBHOOK_CREATED          = "webhook.created"
+	AUDIT_WEBHOOK_DELETED          = "webhook.deleted"
+	AUDIT_WEBHOOK_REDELIVERED      = "webhook.redelivered"
+	AUDIT_RECONCILIATION_CREATED   = "reconciliation.created"
+	AUDIT_RECONCILIATION_APPROVED  = "reconciliation.approved"
+)
+
+// The actors of the changes made by the scheduled jobs and by the requests
+// that don't say who sent them.
+const (
+	AUDIT_SYSTEM_ACTOR    = "system"
+	AUDIT_ANONYMOUS_ACTOR = "anonymous"
+)
+
+// AuditRequest tells who asked for a change and where from.
+type AuditRequest struct {
+	Actor     string
+	RequestId string
+	ClientIP  string
+}
+
+// AuditEntry records a change: who made it, in which request, and the entity
+// before and after it. Before is nil for a creation, After for a deletion.
+// Entries are only ever appended.
+type AuditEntry struct {
+	ID         string           `json:"id"`
+	Actor      string           `json:"actor"`
+	RequestId  string           `json:"requestId" db:"request_id"`
+	ClientIP   string           `json:"clientIp" db:"client_ip"`
+	Operation  string           `json:"operation"`
+	EntityType string           `json:"entityType" db:"entity_type"`
+	EntityId   string           `json:"entityId" db:"entity_id"`
+	Before     *json.RawMessage `json:"before,omitempty" swaggertype:"object"`
+	After      *json.RawMessage `json:"after,omitempty" swaggertype:"object"`
+	CreatedAt  time.Time        `json:"createdAt" db:"created_at"`
+}
+
+// AuditFilter selects the entries of the audit log. Empty fields match any
+// entry; the period includes From and excludes To.
+type AuditFilter struct {
+	Actor      string
+	EntityType string
+	EntityId   string
+	From       *time.Time
+	To         *time.Time
+	Limit      int
+}
+
+// AuditBalance is the balance of an account as recorded in the audit log when
+// a transaction moves or holds its funds.
+type AuditBalance struct {
+	Balance     money.Money `json:"balance" swaggertype:"string" example:"100.10"`
+	HeldBalance money.Money `json:"heldBalance" swaggertype:"string" example:"0.00"`
+}
+
+// AuditState is the state of a transaction, or the status of a user, as
+// recorded in the audit log.
+type AuditState struct {
+	State  string `json:"state"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// NewAuditEntry records the change to the entity made for the request. A nil
+// before or after is left out of the entry.
+func NewAuditEntry(request AuditRequest, operation, entityType, entityId string, before, after interface{}) (*AuditEntry, error) {
+	beforeValue, err := auditValue(before)
+	if err != nil {
+		return nil, err
+	}
+
+	afterValue, err := auditValue(after)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AuditEntry{
+		ID:         uuid.NewId(),
+		Actor:      request.Actor,
+		RequestId:  request.RequestId,
+		ClientIP:   request.ClientIP,
+		Operation:  operation,
+		EntityType: entityType,
+		EntityId:   entityId,
+		Before:     beforeValue,
+		After:      afterValue,
+		CreatedAt:  time.Now(),
+	}, nil
+}
+
+func auditValue(value interface{}) (*json.RawMessage, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	if string(data) == "null" {
+		return nil, nil
+	}
+
+	raw := json.RawMessage(data)
+	return &raw, nil
+}
@@ -0,0 +1,36 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewAuditEntry(t *testing.T) {
+	request := AuditRequest{Actor: "jane.doe", RequestId: "request-id", ClientIP: "203.0.113.7"}
+	before := AuditBalance{Balance: money.New(1000)}
+	after := AuditBalance{Balance: money.New(1500), HeldBalance: money.New(200)}
+
+	entry, err := NewAuditEntry(request, AUDIT_ACCOUNT_BALANCE_UPDATED, AUDIT_ACCOUNT, "account-id", before, after)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, entry.ID)
+	assert.Equal(t, "jane.doe", entry.Actor)
+	assert.Equal(t, "request-id", entry.RequestId)
+	assert.Equal(t, "203.0.113.7", entry.ClientIP)
+	assert.Equal(t, "account.balance_updated", entry.Operation)
+	assert.Equal(t, "account", entry.EntityType)
+	assert.Equal(t, "account-id", entry.EntityId)
+	assert.JSONEq(t, `{"balance":"10.00","heldBalance":"0.00"}`, string(*entry.Before))
+	assert.JSONEq(t, `{"balance":"15.00","heldBalance":"2.00"}`, string(*entry.After))
+	assert.False(t, entry.CreatedAt.IsZero())
+
+	var limits *Limits
+	entry, err = NewAuditEntry(request, AUDIT_LIMITS_UPDATED, AUDIT_LIMITS, "user-id", limits, AuditState{State: "PENDING"})
+	assert.NoError(t, err)
+	assert.Nil(t, entry.Before)
+	assert.JSONEq(t, `{"state":"PENDING"}`, string(*entry.After))
+
+	_, err = NewAuditEntry(request, AUDIT_USER_CREATED, AGGREGATE_USER, "user-id", nil, make(chan int))
+	assert.Error(t, err)
+}
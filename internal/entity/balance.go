@@ -0,0 +1,109 @@
+package entity
+
+import (
+	"errors"
+	"time"
+
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/money"
+)
+
+const (
+	BALANCE_DAILY  = "DAILY"
+	BALANCE_HOURLY = "HOURLY"
+)
+
+// MaxBalancePeriods bounds how many periods a balance history can span, a
+// month of hourly balances.
+const MaxBalancePeriods = 31 * 24
+
+// SnapshotDelay is how long after midnight the balances of the previous day
+// are snapshotted, so transactions still committing at midnight are included.
+const SnapshotDelay = time.Hour
+
+var ErrTooManyBalancePeriods = errors.New("the requested range spans too many periods")
+
+// BalanceSnapshot is the balance of an account from all the postings made
+// before TakenAt, always a midnight.
+type BalanceSnapshot struct {
+	AccountId string      `json:"accountId" db:"account_id"`
+	TakenAt   time.Time   `json:"takenAt" db:"taken_at"`
+	Balance   money.Money `json:"balance" swaggertype:"string" example:"100.10"`
+}
+
+// BalanceAt is the ledger balance of a user as of At.
+type BalanceAt struct {
+	UserId    string      `json:"userId"`
+	AccountId string      `json:"accountId"`
+	At        time.Time   `json:"at"`
+	Balance   money.Money `json:"balance" swaggertype:"string" example:"100.10"`
+}
+
+// BalancePeriod holds the balance at the end of the period from Start to End.
+type BalancePeriod struct {
+	Start   time.Time   `json:"start"`
+	End     time.Time   `json:"end"`
+	Balance money.Money `json:"balance" swaggertype:"string" example:"100.10"`
+}
+
+type BalanceHistory struct {
+	UserId    string          `json:"userId"`
+	AccountId string          `json:"accountId"`
+	Interval  string          `json:"interval"`
+	Periods   []BalancePeriod `json:"periods"`
+}
+
+// periodStart returns the start of the daily or hourly period t falls in.
+func periodStart(t time.Time, interval string) time.Time {
+	if interval == BALANCE_HOURLY {
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, t.Location())
+	}
+
+	return StartOfDay(t)
+}
+
+// nextPeriod returns the start of the period after the one starting at t.
+func nextPeriod(t time.Time, interval string) time.Time {
+	if interval == BALANCE_HOURLY {
+		return t.Add(time.Hour)
+	}
+
+	return t.AddDate(0, 0, 1)
+}
+
+// BalancePeriods returns the bounds of the periods covering from and to, both
+// included: the start of the first period and the end of the last one.
+func BalancePeriods(from, to time.Time, interval string) (time.Time, time.Time, error) {
+	start := periodStart(from, interval)
+	end := nextPeriod(periodStart(to, interval), interval)
+
+	periods := 0
+	for t := start; t.Before(end); t = nextPeriod(t, interval) {
+		periods++
+		if periods > MaxBalancePeriods {
+			return time.Time{}, time.Time{}, ErrTooManyBalancePeriods
+		}
+	}
+
+	return start, end, nil
+}
+
+// NewBalanceHistory lays out the balance at the end of every period from start
+// to end, given the opening balance at start and the postings of the user
+// made from then on, in the order they were made.
+func NewBalanceHistory(account *Account, interval string, start, end time.Time, opening money.Money, postings []Posting) *BalanceHistory {
+	history := &BalanceHistory{UserId: account.UserId, AccountId: account.ID, Interval: interval, Periods: make([]BalancePeriod, 0)}
+
+	balance := opening.Amount
+	next := 0
+	for t := start; t.Before(end); t = nextPeriod(t, interval) {
+		periodEnd := nextPeriod(t, interval)
+		for next < len(postings) && postings[next].CreatedAt.Before(periodEnd) {
+			balance += postings[next].Amount.Amount
+			next++
+		}
+
+		history.Periods = append(history.Periods, BalancePeriod{Start: t, End: periodEnd, Balance: money.New(balance)})
+	}
+
+	return history
+}
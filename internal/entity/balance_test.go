@@ -0,0 +1,51 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBalancePeriods(t *testing.T) {
+	from := time.Date(2023, 5, 1, 10, 30, 0, 0, time.UTC)
+
+	start, end, err := BalancePeriods(from, time.Date(2023, 5, 3, 8, 0, 0, 0, time.UTC), BALANCE_DAILY)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, time.Date(2023, 5, 4, 0, 0, 0, 0, time.UTC), end)
+
+	start, end, err = BalancePeriods(from, time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC), BALANCE_HOURLY)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, time.Date(2023, 5, 1, 13, 0, 0, 0, time.UTC), end)
+
+	_, _, err = BalancePeriods(from, from.AddDate(0, 2, 0), BALANCE_HOURLY)
+	assert.Equal(t, ErrTooManyBalancePeriods, err)
+}
+
+func TestNewBalanceHistory(t *testing.T) {
+	at := func(day, hour int) *time.Time {
+		t := time.Date(2023, 5, day, hour, 0, 0, 0, time.UTC)
+		return &t
+	}
+	start := *at(1, 0)
+
+	history := NewBalanceHistory(&Account{ID: "account-id", UserId: "user-id"}, BALANCE_DAILY, start, *at(4, 0), money.New(1000), []Posting{
+		{AccountId: "account-id", Amount: money.New(500), CreatedAt: at(1, 9)},
+		{AccountId: "account-id", Amount: money.New(-200), CreatedAt: at(1, 18)},
+		{AccountId: "account-id", Amount: money.New(-1000), CreatedAt: at(3, 0)},
+	})
+
+	assert.Equal(t, &BalanceHistory{
+		UserId:    "user-id",
+		AccountId: "account-id",
+		Interval:  BALANCE_DAILY,
+		Periods: []BalancePeriod{
+			{Start: *at(1, 0), End: *at(2, 0), Balance: money.New(1300)},
+			{Start: *at(2, 0), End: *at(3, 0), Balance: money.New(1300)},
+			{Start: *at(3, 0), End: *at(4, 0), Balance: money.New(300)},
+		},
+	}, history)
+}
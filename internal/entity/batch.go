@@ -0,0 +1,93 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/garoque/backend-code-challenge-snapfi/internal/api/dto"
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/uuid"
+)
+
+type StatesBatch int
+
+// The values are stored in the database, so new states must be appended.
+const (
+	BATCH_PROCESSING StatesBatch = iota
+	BATCH_BOOKED
+	BATCH_PARTIALLY_BOOKED
+	BATCH_FAILED
+)
+
+var StatesBatchString = []string{
+	"PROCESSING", "BOOKED", "PARTIALLY_BOOKED", "FAILED",
+}
+
+func (st StatesBatch) String() string {
+	return StatesBatchString[st]
+}
+
+// Batch groups transfers sent in a single request. An atomic batch books all
+// of its transfers or none of them, otherwise every transfer is booked on its
+// own and Items tells how each one ended.
+type Batch struct {
+	ID           string         `json:"id"`
+	Atomic       bool           `json:"atomic"`
+	State        StatesBatch    `json:"-" db:"state"`
+	StateString  string         `json:"state,omitempty"`
+	Items        []BatchItem    `json:"items,omitempty"`
+	Transactions []*Transaction `json:"-"`
+	CreatedAt    *time.Time     `json:"createdAt" db:"created_at"`
+}
+
+// BatchItem is the outcome of the transfer at Position in the batch.
+type BatchItem struct {
+	BatchId       string            `json:"-" db:"batch_id"`
+	Position      int               `json:"position"`
+	TransactionId string            `json:"transactionId" db:"transaction_id"`
+	State         StatesTransaction `json:"-" db:"state"`
+	StateString   string            `json:"state,omitempty"`
+	Error         string            `json:"error,omitempty"`
+}
+
+func NewBatch(batch dto.CreateBatch) *Batch {
+	transactions := make([]*Transaction, 0, len(batch.Transactions))
+	for _, transaction := range batch.Transactions {
+		transactions = append(transactions, NewTransaction(transaction))
+	}
+
+	return &Batch{
+		ID:           uuid.NewId(),
+		Atomic:       batch.Atomic,
+		State:        BATCH_PROCESSING,
+		Transactions: transactions,
+	}
+}
+
+// Complete records how every transfer ended, errs holding the error of each
+// failed one by position, and settles the state of the batch.
+func (b *Batch) Complete(errs map[int]error) {
+	b.Items = make([]BatchItem, 0, len(b.Transactions))
+	for i, transaction := range b.Transactions {
+		item := BatchItem{
+			BatchId:       b.ID,
+			Position:      i,
+			TransactionId: transaction.ID,
+			State:         transaction.State,
+			StateString:   transaction.State.String(),
+		}
+		if err := errs[i]; err != nil {
+			item.Error = err.Error()
+		}
+
+		b.Items = append(b.Items, item)
+	}
+
+	switch {
+	case len(errs) == 0:
+		b.State = BATCH_BOOKED
+	case len(errs) == len(b.Transactions) || b.Atomic:
+		b.State = BATCH_FAILED
+	default:
+		b.State = BATCH_PARTIALLY_BOOKED
+	}
+	b.StateString = b.State.String()
+}
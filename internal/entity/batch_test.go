@@ -0,0 +1,89 @@
+package entity
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/garoque/backend-code-challenge-snapfi/internal/api/dto"
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatesBatchString(t *testing.T) {
+	assert.Equal(t, "PROCESSING", BATCH_PROCESSING.String())
+	assert.Equal(t, "BOOKED", BATCH_BOOKED.String())
+	assert.Equal(t, "PARTIALLY_BOOKED", BATCH_PARTIALLY_BOOKED.String())
+	assert.Equal(t, "FAILED", BATCH_FAILED.String())
+}
+
+func TestNewBatch(t *testing.T) {
+	batch := NewBatch(dto.CreateBatch{
+		Atomic: true,
+		Transactions: []dto.CreateTransaction{
+			{SourceUserId: "source-user-id", DestinationUserId: "destination-user-id-1", Amount: money.New(100)},
+			{SourceUserId: "source-user-id", DestinationUserId: "destination-user-id-2", Amount: money.New(200)},
+		},
+	})
+	assert.NotEmpty(t, batch.ID)
+	assert.True(t, batch.Atomic)
+	assert.Equal(t, BATCH_PROCESSING, batch.State)
+	assert.Len(t, batch.Transactions, 2)
+	assert.Equal(t, "destination-user-id-2", batch.Transactions[1].DestinationId)
+	assert.NotEqual(t, batch.Transactions[0].ID, batch.Transactions[1].ID)
+}
+
+func TestComplete(t *testing.T) {
+	newBatch := func(atomic bool, states ...StatesTransaction) *Batch {
+		batch := &Batch{ID: "batch-id", Atomic: atomic}
+		for _, state := range states {
+			batch.Transactions = append(batch.Transactions, &Transaction{ID: "transaction-id", State: state})
+		}
+		return batch
+	}
+
+	cases := map[string]struct {
+		Batch    *Batch
+		Errs     map[int]error
+		Expected StatesBatch
+	}{
+		"todas booked": {
+			Batch:    newBatch(false, BOOKED, BOOKED),
+			Errs:     map[int]error{},
+			Expected: BATCH_BOOKED,
+		},
+		"parcialmente booked": {
+			Batch:    newBatch(false, BOOKED, FAILED),
+			Errs:     map[int]error{1: errors.New("Insufficient balance")},
+			Expected: BATCH_PARTIALLY_BOOKED,
+		},
+		"todas falharam": {
+			Batch:    newBatch(false, FAILED, FAILED),
+			Errs:     map[int]error{0: errors.New("Not Found"), 1: errors.New("Not Found")},
+			Expected: BATCH_FAILED,
+		},
+		"atomica com falha": {
+			Batch:    newBatch(true, FAILED, FAILED),
+			Errs:     map[int]error{0: errors.New("batch was rolled back"), 1: errors.New("Insufficient balance")},
+			Expected: BATCH_FAILED,
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			cs.Batch.Complete(cs.Errs)
+			assert.Equal(t, cs.Expected, cs.Batch.State)
+			assert.Equal(t, cs.Expected.String(), cs.Batch.StateString)
+			assert.Len(t, cs.Batch.Items, len(cs.Batch.Transactions))
+
+			for i, item := range cs.Batch.Items {
+				assert.Equal(t, i, item.Position)
+				assert.Equal(t, "batch-id", item.BatchId)
+				if err := cs.Errs[i]; err != nil {
+					assert.Equal(t, err.Error(), item.Error)
+				} else {
+					assert.Empty(t, item.Error)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package entity
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/uuid"
+)
+
+const (
+	AGGREGATE_TRANSACTION = "transaction"
+	AGGREGATE_USER        = "user"
+
+	EVENT_USER_CREATED        = "user.created"
+	EVENT_USER_STATUS_CHANGED = "user.status_changed"
+)
+
+// Event is a domain event written to the outbox in the same database
+// transaction as the change it describes. Sequence orders the events as they
+// were written, which is the order they are published in.
+type Event struct {
+	Sequence      int64           `json:"-" db:"sequence"`
+	ID            string          `json:"id"`
+	Type          string          `json:"type"`
+	AggregateType string          `json:"aggregateType" db:"aggregate_type"`
+	AggregateId   string          `json:"aggregateId" db:"aggregate_id"`
+	Payload       json.RawMessage `json:"payload" swaggertype:"object"`
+	CreatedAt     time.Time       `json:"createdAt" db:"created_at"`
+	PublishedAt   *time.Time      `json:"-" db:"published_at"`
+}
+
+// TransactionEventPayload is the payload of the events of a transaction, sent
+// every time it changes state.
+type TransactionEventPayload struct {
+	Transaction Transaction `json:"transaction"`
+	From        string      `json:"from"`
+	To          string      `json:"to"`
+	Reason      string      `json:"reason"`
+}
+
+type UserEventPayload struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	AccountType string `json:"accountType"`
+}
+
+// UserStatusEventPayload is the payload of the events of a user changing
+// status.
+type UserStatusEventPayload struct {
+	ID     string `json:"id"`
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Reason string `json:"reason"`
+}
+
+// TransactionEventType names the event of a transaction reaching the state,
+// such as transaction.booked.
+func TransactionEventType(state StatesTransaction) string {
+	return AGGREGATE_TRANSACTION + "." + strings.ToLower(state.String())
+}
+
+func newEvent(eventType, aggregateType, aggregateId string, payload interface{}) (*Event, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Event{
+		ID:            uuid.NewId(),
+		Type:          eventType,
+		AggregateType: aggregateType,
+		AggregateId:   aggregateId,
+		Payload:       data,
+		CreatedAt:     time.Now(),
+	}, nil
+}
+
+// NewTransactionEvent describes the transaction after the state change of the
+// transition.
+func NewTransactionEvent(transaction *Transaction, transition *StateTransition) (*Event, error) {
+	payload := TransactionEventPayload{
+		Transaction: *transaction,
+		From:        transition.From.String(),
+		To:          transition.To.String(),
+		Reason:      transition.Reason,
+	}
+	payload.Transaction.KindString = transaction.Kind.String()
+	payload.Transaction.StateString = transaction.State.String()
+	payload.Transaction.Fee = nil
+
+	return newEvent(TransactionEventType(transition.To), AGGREGATE_TRANSACTION, transaction.ID, payload)
+}
+
+func NewUserCreatedEvent(user *User) (*Event, error) {
+	payload := UserEventPayload{
+		ID:          user.ID,
+		Name:        user.Name,
+		AccountType: user.AccountType.String(),
+	}
+
+	return newEvent(EVENT_USER_CREATED, AGGREGATE_USER, user.ID, payload)
+}
+
+// NewUserStatusEvent describes the status change of the user.
+func NewUserStatusEvent(change *StatusChange) (*Event, error) {
+	payload := UserStatusEventPayload{
+		ID:     change.UserId,
+		From:   change.From.String(),
+		To:     change.To.String(),
+		Reason: change.Reason,
+	}
+
+	return newEvent(EVENT_USER_STATUS_CHANGED, AGGREGATE_USER, change.UserId, payload)
+}
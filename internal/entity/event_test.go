@@ -0,0 +1,49 @@
+package entity
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewTransactionEvent(t *testing.T) {
+	transaction := &Transaction{ID: "transaction-id", SourceId: "source-id", DestinationId: "destination-id", Amount: money.New(10010), Kind: TRANSFER, State: PENDING}
+	transition, err := transaction.TransitionTo(BOOKED, "transfer booked")
+	assert.NoError(t, err)
+
+	event, err := NewTransactionEvent(transaction, transition)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, event.ID)
+	assert.Equal(t, "transaction.booked", event.Type)
+	assert.Equal(t, AGGREGATE_TRANSACTION, event.AggregateType)
+	assert.Equal(t, "transaction-id", event.AggregateId)
+
+	var payload map[string]interface{}
+	assert.NoError(t, json.Unmarshal(event.Payload, &payload))
+	assert.Equal(t, "PENDING", payload["from"])
+	assert.Equal(t, "BOOKED", payload["to"])
+	assert.Equal(t, "transfer booked", payload["reason"])
+	assert.Equal(t, "100.10", payload["transaction"].(map[string]interface{})["amount"])
+	assert.Equal(t, "TRANSFER", payload["transaction"].(map[string]interface{})["kind"])
+
+	assert.Equal(t, "transaction.partially_reversed", TransactionEventType(PARTIALLY_REVERSED))
+}
+
+func TestNewUserCreatedEvent(t *testing.T) {
+	event, err := NewUserCreatedEvent(&User{ID: "user-id", Name: "Gabriel", AccountType: BUSINESS})
+	assert.NoError(t, err)
+	assert.Equal(t, EVENT_USER_CREATED, event.Type)
+	assert.Equal(t, "user-id", event.AggregateId)
+	assert.JSONEq(t, `{"id": "user-id", "name": "Gabriel", "accountType": "BUSINESS"}`, string(event.Payload))
+}
+
+func TestNewUserStatusEvent(t *testing.T) {
+	event, err := NewUserStatusEvent(&StatusChange{UserId: "user-id", From: ACTIVE, To: FROZEN, Reason: "suspected fraud"})
+	assert.NoError(t, err)
+	assert.Equal(t, EVENT_USER_STATUS_CHANGED, event.Type)
+	assert.Equal(t, AGGREGATE_USER, event.AggregateType)
+	assert.Equal(t, "user-id", event.AggregateId)
+	assert.JSONEq(t, `{"id": "user-id", "from": "ACTIVE", "to": "FROZEN", "reason": "suspected fraud"}`, string(event.Payload))
+}
@@ -0,0 +1,247 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/garoque/backend-code-challenge-snapfi/internal/api/dto"
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/money"
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/uuid"
+)
+
+type FeeType int
+
+// The values are stored in the database, so new types must be appended.
+const (
+	FEE_FLAT FeeType = iota
+	FEE_PERCENTAGE
+	FEE_TIERED
+)
+
+var FeeTypeString = []string{
+	"FLAT", "PERCENTAGE", "TIERED",
+}
+
+func (f FeeType) String() string {
+	return FeeTypeString[f]
+}
+
+// ParseFeeType returns the fee type named s, reporting whether it exists.
+func ParseFeeType(s string) (FeeType, bool) {
+	for i, name := range FeeTypeString {
+		if name == s {
+			return FeeType(i), true
+		}
+	}
+
+	return 0, false
+}
+
+var (
+	ErrFeeFlatAmount  = errors.New("a FLAT fee needs a positive flatAmount")
+	ErrFeeBasisPoints = errors.New("a PERCENTAGE fee needs basisPoints")
+	ErrFeeTiers       = errors.New("a TIERED fee needs tiers in ascending upTo order, only the last one without upTo")
+	ErrFeeCaps        = errors.New("minAmount must be zero or positive and not greater than maxAmount")
+)
+
+// FeeRule tells how much is charged on the transactions it applies to. A nil
+// Kind or AccountType matches any transaction kind or account type of the
+// payer.
+type FeeRule struct {
+	ID                string           `json:"id"`
+	Name              string           `json:"name"`
+	Type              FeeType          `json:"-" db:"type"`
+	TypeString        string           `json:"type,omitempty"`
+	Kind              *KindTransaction `json:"-" db:"kind"`
+	KindString        string           `json:"kind,omitempty"`
+	AccountType       *AccountType     `json:"-" db:"account_type"`
+	AccountTypeString string           `json:"accountType,omitempty"`
+	FlatAmount        *money.Money     `json:"flatAmount,omitempty" db:"flat_amount" swaggertype:"string" example:"1.00"`
+	BasisPoints       *int64           `json:"basisPoints,omitempty" db:"basis_points"`
+	Tiers             FeeTiers         `json:"tiers,omitempty"`
+	MinAmount         *money.Money     `json:"minAmount,omitempty" db:"min_amount" swaggertype:"string" example:"0.50"`
+	MaxAmount         *money.Money     `json:"maxAmount,omitempty" db:"max_amount" swaggertype:"string" example:"10.00"`
+	Active            bool             `json:"active"`
+	CreatedAt         *time.Time       `json:"createdAt" db:"created_at"`
+}
+
+// FeeTier charges FlatAmount plus BasisPoints of the amount on transactions
+// up to UpTo. A nil UpTo has no upper bound.
+type FeeTier struct {
+	UpTo        *money.Money `json:"upTo,omitempty" swaggertype:"string" example:"1000.00"`
+	FlatAmount  money.Money  `json:"flatAmount" swaggertype:"string" example:"0.00"`
+	BasisPoints int64        `json:"basisPoints"`
+}
+
+// FeeTiers is stored as a JSON column.
+type FeeTiers []FeeTier
+
+func (t FeeTiers) Value() (driver.Value, error) {
+	if t == nil {
+		return nil, nil
+	}
+
+	return json.Marshal(t)
+}
+
+func (t *FeeTiers) Scan(src interface{}) error {
+	switch value := src.(type) {
+	case nil:
+		*t = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(value, t)
+	case string:
+		return json.Unmarshal([]byte(value), t)
+	default:
+		return fmt.Errorf("cannot scan %T into FeeTiers", src)
+	}
+}
+
+func NewFeeRule(rule dto.CreateFeeRule) *FeeRule {
+	feeType, _ := ParseFeeType(rule.Type)
+
+	feeRule := &FeeRule{
+		ID:          uuid.NewId(),
+		Name:        rule.Name,
+		Type:        feeType,
+		FlatAmount:  rule.FlatAmount,
+		BasisPoints: rule.BasisPoints,
+		MinAmount:   rule.MinAmount,
+		MaxAmount:   rule.MaxAmount,
+		Active:      true,
+	}
+
+	if kind, ok := ParseKindTransaction(rule.Kind); ok {
+		feeRule.Kind = &kind
+	}
+
+	if accountType, ok := ParseAccountType(rule.AccountType); ok {
+		feeRule.AccountType = &accountType
+	}
+
+	for _, tier := range rule.Tiers {
+		feeRule.Tiers = append(feeRule.Tiers, FeeTier{
+			UpTo:        tier.UpTo,
+			FlatAmount:  tier.FlatAmount,
+			BasisPoints: tier.BasisPoints,
+		})
+	}
+
+	return feeRule
+}
+
+// Validate checks the rule has what its type needs to calculate a fee.
+func (r *FeeRule) Validate() error {
+	switch r.Type {
+	case FEE_FLAT:
+		if r.FlatAmount == nil || !r.FlatAmount.IsPositive() {
+			return ErrFeeFlatAmount
+		}
+	case FEE_PERCENTAGE:
+		if r.BasisPoints == nil {
+			return ErrFeeBasisPoints
+		}
+	case FEE_TIERED:
+		if len(r.Tiers) == 0 {
+			return ErrFeeTiers
+		}
+
+		for i, tier := range r.Tiers {
+			last := i == len(r.Tiers)-1
+			if (tier.UpTo == nil) != last || tier.FlatAmount.IsNegative() {
+				return ErrFeeTiers
+			}
+
+			if i > 0 && !last && !r.Tiers[i-1].UpTo.LessThan(*tier.UpTo) {
+				return ErrFeeTiers
+			}
+		}
+	}
+
+	if r.MinAmount != nil && r.MinAmount.IsNegative() {
+		return ErrFeeCaps
+	}
+
+	if r.MinAmount != nil && r.MaxAmount != nil && r.MaxAmount.LessThan(*r.MinAmount) {
+		return ErrFeeCaps
+	}
+
+	return nil
+}
+
+// Matches reports whether the rule applies to a transaction of the given kind
+// paid by an account of the given type.
+func (r *FeeRule) Matches(kind KindTransaction, accountType AccountType) bool {
+	return (r.Kind == nil || *r.Kind == kind) && (r.AccountType == nil || *r.AccountType == accountType)
+}
+
+// specificity ranks rules restricted to a kind and an account type above
+// rules restricted to only one of them, which rank above catch-all rules.
+func (r *FeeRule) specificity() int {
+	specificity := 0
+	if r.Kind != nil {
+		specificity++
+	}
+
+	if r.AccountType != nil {
+		specificity++
+	}
+
+	return specificity
+}
+
+// SelectFeeRule picks the most specific of the rules matching the transaction,
+// the first one listed on a tie, or nil when none of them matches.
+func SelectFeeRule(rules []FeeRule, kind KindTransaction, accountType AccountType) *FeeRule {
+	var selected *FeeRule
+	for i := range rules {
+		if !rules[i].Matches(kind, accountType) {
+			continue
+		}
+
+		if selected == nil || rules[i].specificity() > selected.specificity() {
+			selected = &rules[i]
+		}
+	}
+
+	return selected
+}
+
+// Calculate returns the fee charged on the amount, rounded to the nearest
+// cent and kept within MinAmount and MaxAmount.
+func (r *FeeRule) Calculate(amount money.Money) money.Money {
+	var fee int64
+	switch r.Type {
+	case FEE_FLAT:
+		fee = r.FlatAmount.Amount
+	case FEE_PERCENTAGE:
+		fee = applyBasisPoints(amount.Amount, *r.BasisPoints)
+	case FEE_TIERED:
+		for _, tier := range r.Tiers {
+			if tier.UpTo == nil || !tier.UpTo.LessThan(amount) {
+				fee = tier.FlatAmount.Amount + applyBasisPoints(amount.Amount, tier.BasisPoints)
+				break
+			}
+		}
+	}
+
+	if r.MinAmount != nil && fee < r.MinAmount.Amount {
+		fee = r.MinAmount.Amount
+	}
+
+	if r.MaxAmount != nil && fee > r.MaxAmount.Amount {
+		fee = r.MaxAmount.Amount
+	}
+
+	return money.New(fee)
+}
+
+// applyBasisPoints returns amount * basisPoints / 10000 rounded half up,
+// splitting the product so large amounts can't overflow int64.
+func applyBasisPoints(amount, basisPoints int64) int64 {
+	return amount/10000*basisPoints + (amount%10000*basisPoints+5000)/10000
+}
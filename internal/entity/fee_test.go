@@ -0,0 +1,155 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/garoque/backend-code-challenge-snapfi/internal/api/dto"
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFeeTypeString(t *testing.T) {
+	assert.Equal(t, "FLAT", FEE_FLAT.String())
+	assert.Equal(t, "PERCENTAGE", FEE_PERCENTAGE.String())
+	assert.Equal(t, "TIERED", FEE_TIERED.String())
+
+	feeType, ok := ParseFeeType("TIERED")
+	assert.True(t, ok)
+	assert.Equal(t, FEE_TIERED, feeType)
+
+	_, ok = ParseFeeType("UNKNOWN")
+	assert.False(t, ok)
+}
+
+func TestNewFeeRule(t *testing.T) {
+	basisPoints := int64(150)
+	upTo := money.New(100000)
+
+	rule := NewFeeRule(dto.CreateFeeRule{
+		Name:        "business transfers",
+		Type:        "PERCENTAGE",
+		Kind:        "TRANSFER",
+		AccountType: "BUSINESS",
+		BasisPoints: &basisPoints,
+		Tiers:       []dto.FeeTier{{UpTo: &upTo, BasisPoints: 100}},
+	})
+	assert.NotEmpty(t, rule.ID)
+	assert.True(t, rule.Active)
+	assert.Equal(t, FEE_PERCENTAGE, rule.Type)
+	assert.Equal(t, TRANSFER, *rule.Kind)
+	assert.Equal(t, BUSINESS, *rule.AccountType)
+	assert.Equal(t, FeeTiers{{UpTo: &upTo, BasisPoints: 100}}, rule.Tiers)
+
+	rule = NewFeeRule(dto.CreateFeeRule{Name: "any", Type: "FLAT"})
+	assert.Nil(t, rule.Kind)
+	assert.Nil(t, rule.AccountType)
+}
+
+func TestFeeRuleValidate(t *testing.T) {
+	amount := func(cents int64) *money.Money {
+		m := money.New(cents)
+		return &m
+	}
+	basisPoints := int64(100)
+
+	cases := map[string]struct {
+		Rule     FeeRule
+		Expected error
+	}{
+		"flat":                     {FeeRule{Type: FEE_FLAT, FlatAmount: amount(100)}, nil},
+		"flat sem valor":           {FeeRule{Type: FEE_FLAT}, ErrFeeFlatAmount},
+		"percentual":               {FeeRule{Type: FEE_PERCENTAGE, BasisPoints: &basisPoints}, nil},
+		"percentual sem taxa":      {FeeRule{Type: FEE_PERCENTAGE}, ErrFeeBasisPoints},
+		"faixas":                   {FeeRule{Type: FEE_TIERED, Tiers: FeeTiers{{UpTo: amount(1000)}, {UpTo: amount(5000)}, {}}}, nil},
+		"faixas vazias":            {FeeRule{Type: FEE_TIERED}, ErrFeeTiers},
+		"faixas fora de ordem":     {FeeRule{Type: FEE_TIERED, Tiers: FeeTiers{{UpTo: amount(5000)}, {UpTo: amount(1000)}, {}}}, ErrFeeTiers},
+		"ultima faixa com limite":  {FeeRule{Type: FEE_TIERED, Tiers: FeeTiers{{UpTo: amount(1000)}}}, ErrFeeTiers},
+		"faixa sem limite no meio": {FeeRule{Type: FEE_TIERED, Tiers: FeeTiers{{}, {UpTo: amount(1000)}}}, ErrFeeTiers},
+		"minimo maior que maximo":  {FeeRule{Type: FEE_FLAT, FlatAmount: amount(100), MinAmount: amount(500), MaxAmount: amount(100)}, ErrFeeCaps},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, cs.Expected, cs.Rule.Validate())
+		})
+	}
+}
+
+func TestFeeRuleCalculate(t *testing.T) {
+	amount := func(cents int64) *money.Money {
+		m := money.New(cents)
+		return &m
+	}
+	basisPoints := func(value int64) *int64 {
+		return &value
+	}
+
+	tiered := FeeRule{Type: FEE_TIERED, Tiers: FeeTiers{
+		{UpTo: amount(10000), FlatAmount: money.New(100)},
+		{UpTo: amount(100000), BasisPoints: 100},
+		{FlatAmount: money.New(500), BasisPoints: 50},
+	}}
+
+	cases := map[string]struct {
+		Rule     FeeRule
+		Amount   int64
+		Expected int64
+	}{
+		"flat":                           {FeeRule{Type: FEE_FLAT, FlatAmount: amount(250)}, 10000, 250},
+		"percentual":                     {FeeRule{Type: FEE_PERCENTAGE, BasisPoints: basisPoints(150)}, 10000, 150},
+		"percentual arredonda":           {FeeRule{Type: FEE_PERCENTAGE, BasisPoints: basisPoints(150)}, 1033, 15},
+		"percentual arredonda para cima": {FeeRule{Type: FEE_PERCENTAGE, BasisPoints: basisPoints(150)}, 1100, 17},
+		"percentual com minimo":          {FeeRule{Type: FEE_PERCENTAGE, BasisPoints: basisPoints(100), MinAmount: amount(50)}, 1000, 50},
+		"percentual com maximo":          {FeeRule{Type: FEE_PERCENTAGE, BasisPoints: basisPoints(100), MaxAmount: amount(1000)}, 1000000, 1000},
+		"percentual sem overflow":        {FeeRule{Type: FEE_PERCENTAGE, BasisPoints: basisPoints(10000)}, 9000000000000000000, 9000000000000000000},
+		"primeira faixa":                 {tiered, 10000, 100},
+		"segunda faixa":                  {tiered, 50000, 500},
+		"ultima faixa":                   {tiered, 200000, 1500},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, money.New(cs.Expected), cs.Rule.Calculate(money.New(cs.Amount)))
+		})
+	}
+}
+
+func TestSelectFeeRule(t *testing.T) {
+	transfer := TRANSFER
+	deposit := DEPOSIT
+	business := BUSINESS
+
+	rules := []FeeRule{
+		{ID: "any"},
+		{ID: "transfers", Kind: &transfer},
+		{ID: "business", AccountType: &business},
+		{ID: "business-transfers", Kind: &transfer, AccountType: &business},
+		{ID: "deposits", Kind: &deposit},
+	}
+
+	assert.Equal(t, "business-transfers", SelectFeeRule(rules, TRANSFER, BUSINESS).ID)
+	assert.Equal(t, "transfers", SelectFeeRule(rules, TRANSFER, PERSONAL).ID)
+	assert.Equal(t, "business", SelectFeeRule(rules, DEPOSIT, BUSINESS).ID)
+	assert.Equal(t, "deposits", SelectFeeRule(rules, DEPOSIT, PERSONAL).ID)
+	assert.Equal(t, "any", SelectFeeRule(rules, WITHDRAWAL, PERSONAL).ID)
+	assert.Nil(t, SelectFeeRule(rules[1:2], DEPOSIT, PERSONAL))
+}
+
+func TestFeeTiersValueScan(t *testing.T) {
+	upTo := money.New(1000)
+	tiers := FeeTiers{{UpTo: &upTo, FlatAmount: money.New(10), BasisPoints: 100}, {FlatAmount: money.New(0), BasisPoints: 50}}
+
+	value, err := tiers.Value()
+	assert.NoError(t, err)
+
+	var scanned FeeTiers
+	assert.NoError(t, scanned.Scan(value))
+	assert.Equal(t, tiers, scanned)
+
+	value, err = FeeTiers(nil).Value()
+	assert.NoError(t, err)
+	assert.Nil(t, value)
+
+	assert.NoError(t, scanned.Scan(nil))
+	assert.Nil(t, scanned)
+}
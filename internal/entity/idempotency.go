@@ -0,0 +1,43 @@
+package entity
+
+import "time"
+
+// IdempotencyKey stores the outcome of a request sent with an Idempotency-Key
+// header. StatusCode and ResponseBody stay empty while the request is still
+// being processed.
+type IdempotencyKey struct {
+	Scope        string    `db:"scope"`
+	Key          string    `db:"idempotency_key"`
+	RequestHash  string    `db:"request_hash"`
+	StatusCode   *int      `db:"status_code"`
+	ResponseBody []byte    `db:"response_body"`
+	CreatedAt    time.Time `db:"created_at"`
+	ExpiresAt    time.Time `db:"expires_at"`
+}
+
+func NewIdempotencyKey(scope, key, requestHash string, ttl time.Duration) *IdempotencyKey {
+	now := time.Now()
+
+	return &IdempotencyKey{
+		Scope:       scope,
+		Key:         key,
+		RequestHash: requestHash,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(ttl),
+	}
+}
+
+func (k *IdempotencyKey) IsExpired(now time.Time) bool {
+	return !now.Before(k.ExpiresAt)
+}
+
+func (k *IdempotencyKey) IsCompleted() bool {
+	return k.StatusCode != nil
+}
+
+// IsAbandoned reports whether the request holding the key has been processed
+// for longer than the lease, in which case the server processing it is taken
+// for dead and a retry may take the key over.
+func (k *IdempotencyKey) IsAbandoned(now time.Time, lease time.Duration) bool {
+	return !k.IsCompleted() && !now.Before(k.CreatedAt.Add(lease))
+}
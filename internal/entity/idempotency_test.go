@@ -0,0 +1,26 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewIdempotencyKey(t *testing.T) {
+	key := NewIdempotencyKey("POST /v1/transaction", "key", "hash", time.Hour)
+	assert.NotNil(t, key)
+	assert.Equal(t, "POST /v1/transaction", key.Scope)
+	assert.Equal(t, "key", key.Key)
+	assert.Equal(t, "hash", key.RequestHash)
+	assert.Equal(t, time.Hour, key.ExpiresAt.Sub(key.CreatedAt))
+	assert.False(t, key.IsCompleted())
+	assert.False(t, key.IsExpired(key.CreatedAt))
+	assert.True(t, key.IsExpired(key.ExpiresAt))
+	assert.False(t, key.IsAbandoned(key.CreatedAt.Add(time.Second), time.Minute))
+	assert.True(t, key.IsAbandoned(key.CreatedAt.Add(time.Minute), time.Minute))
+
+	statusCode := 201
+	key.StatusCode = &statusCode
+	assert.False(t, key.IsAbandoned(key.CreatedAt.Add(time.Minute), time.Minute))
+}
@@ -0,0 +1,65 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/money"
+)
+
+const (
+	// FundingAccountId is the system account money comes from when a user's
+	// balance is increased. Its balance is the negative of all money deposited.
+	FundingAccountId = "system:funding"
+	// WithdrawalAccountId is the system account money goes to when it leaves
+	// a user's balance. Its balance is the total withdrawn.
+	WithdrawalAccountId = "system:withdrawals"
+	// FeeAccountId is the platform revenue account fees are paid into. Its
+	// balance is the total charged in fees.
+	FeeAccountId = "system:fees"
+	// InterestAccountId is the platform revenue account the interest on
+	// overdrawn balances is paid into.
+	InterestAccountId = "system:interest"
+)
+
+// IsSystemAccount reports whether the account belongs to the system instead
+// of a user, in which case it has no row in the accounts table.
+func IsSystemAccount(accountId string) bool {
+	return accountId == FundingAccountId || accountId == WithdrawalAccountId || accountId == FeeAccountId || accountId == InterestAccountId
+}
+
+// Posting is one side of a movement in the double-entry ledger. Amounts are
+// signed: a positive amount credits the account and a negative one debits it,
+// and the postings of a transaction always sum to zero. User accounts share
+// the ID of their user.
+type Posting struct {
+	ID            int64       `json:"-"`
+	TransactionId string      `json:"transactionId" db:"transaction_id"`
+	AccountId     string      `json:"accountId" db:"account_id"`
+	Amount        money.Money `json:"amount" swaggertype:"string" example:"100.10"`
+	CreatedAt     *time.Time  `json:"createdAt" db:"created_at"`
+}
+
+// NewPostings moves the transaction amount from its source account to its
+// destination account.
+func NewPostings(transaction *Transaction) []Posting {
+	return []Posting{
+		{TransactionId: transaction.ID, AccountId: transaction.SourceId, Amount: transaction.Amount.Neg()},
+		{TransactionId: transaction.ID, AccountId: transaction.DestinationId, Amount: transaction.Amount},
+	}
+}
+
+type BalanceMismatch struct {
+	AccountId       string      `json:"accountId" db:"account_id"`
+	Balance         money.Money `json:"balance" swaggertype:"string" example:"100.10"`
+	PostingsBalance money.Money `json:"postingsBalance" db:"postings_balance" swaggertype:"string" example:"100.10"`
+}
+
+// LedgerCheck is the result of verifying the ledger invariants: every posting
+// sums to zero, both overall and per transaction, and every user balance
+// matches the sum of its postings.
+type LedgerCheck struct {
+	Total                  money.Money       `json:"total" swaggertype:"string" example:"0.00"`
+	Balanced               bool              `json:"balanced"`
+	UnbalancedTransactions []string          `json:"unbalancedTransactions"`
+	BalanceMismatches      []BalanceMismatch `json:"balanceMismatches"`
+}
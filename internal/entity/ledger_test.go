@@ -0,0 +1,35 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPostings(t *testing.T) {
+	transaction := &Transaction{
+		ID:            "transaction-id",
+		SourceId:      FundingAccountId,
+		DestinationId: "user-id",
+		Amount:        money.New(10010),
+	}
+
+	postings := NewPostings(transaction)
+	assert.Equal(t, []Posting{
+		{TransactionId: "transaction-id", AccountId: FundingAccountId, Amount: money.New(-10010)},
+		{TransactionId: "transaction-id", AccountId: "user-id", Amount: money.New(10010)},
+	}, postings)
+
+	total, err := postings[0].Amount.Add(postings[1].Amount)
+	assert.NoError(t, err)
+	assert.True(t, total.IsZero())
+}
+
+func TestIsSystemAccount(t *testing.T) {
+	assert.True(t, IsSystemAccount(FundingAccountId))
+	assert.True(t, IsSystemAccount(WithdrawalAccountId))
+	assert.True(t, IsSystemAccount(FeeAccountId))
+	assert.True(t, IsSystemAccount(InterestAccountId))
+	assert.False(t, IsSystemAccount("user-id"))
+}
@@ -0,0 +1,150 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/garoque/backend-code-challenge-snapfi/internal/api/dto"
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/money"
+)
+
+// DefaultLimitsId is the id_user of the limits applied to users without an
+// override of their own.
+const DefaultLimitsId = "default"
+
+// The night-time limit applies to transfers made from NightStartHour until
+// NightEndHour, in the server's time zone.
+const (
+	NightStartHour = 20
+	NightEndHour   = 6
+)
+
+const (
+	LIMIT_PER_TRANSACTION       = "PER_TRANSACTION"
+	LIMIT_NIGHT_PER_TRANSACTION = "NIGHT_PER_TRANSACTION"
+	LIMIT_DAILY                 = "DAILY"
+	LIMIT_MONTHLY               = "MONTHLY"
+)
+
+// Limits bounds how much a user can send through transfers and withdrawals.
+// A nil limit doesn't bound anything.
+type Limits struct {
+	UserId              string       `json:"userId" db:"id_user"`
+	PerTransaction      *money.Money `json:"perTransaction,omitempty" db:"per_transaction" swaggertype:"string" example:"5000.00"`
+	NightPerTransaction *money.Money `json:"nightPerTransaction,omitempty" db:"night_per_transaction" swaggertype:"string" example:"1000.00"`
+	Daily               *money.Money `json:"daily,omitempty" db:"daily" swaggertype:"string" example:"10000.00"`
+	Monthly             *money.Money `json:"monthly,omitempty" db:"monthly" swaggertype:"string" example:"50000.00"`
+	UpdatedAt           *time.Time   `json:"updatedAt,omitempty" db:"updated_at"`
+}
+
+func NewLimits(userId string, limits dto.UpdateLimits) *Limits {
+	return &Limits{
+		UserId:              userId,
+		PerTransaction:      limits.PerTransaction,
+		NightPerTransaction: limits.NightPerTransaction,
+		Daily:               limits.Daily,
+		Monthly:             limits.Monthly,
+	}
+}
+
+// ResolveLimits returns the limits of the user: the ones of their override
+// where set, the default ones otherwise. stored holds the default limits and
+// the user's override, in any order, either of them possibly missing.
+func ResolveLimits(userId string, stored []Limits) *Limits {
+	var defaults, override Limits
+	for _, limits := range stored {
+		switch limits.UserId {
+		case DefaultLimitsId:
+			defaults = limits
+		case userId:
+			override = limits
+		}
+	}
+
+	resolved := &Limits{
+		UserId:              userId,
+		PerTransaction:      defaults.PerTransaction,
+		NightPerTransaction: defaults.NightPerTransaction,
+		Daily:               defaults.Daily,
+		Monthly:             defaults.Monthly,
+	}
+
+	if override.PerTransaction != nil {
+		resolved.PerTransaction = override.PerTransaction
+	}
+
+	if override.NightPerTransaction != nil {
+		resolved.NightPerTransaction = override.NightPerTransaction
+	}
+
+	if override.Daily != nil {
+		resolved.Daily = override.Daily
+	}
+
+	if override.Monthly != nil {
+		resolved.Monthly = override.Monthly
+	}
+
+	return resolved
+}
+
+// ErrLimitExceeded is returned when a transfer is larger than one of the
+// limits of its source user allows. Headroom is how much could still be sent
+// under that limit.
+type ErrLimitExceeded struct {
+	Limit    string
+	Amount   money.Money
+	Headroom money.Money
+}
+
+func (e *ErrLimitExceeded) Error() string {
+	return fmt.Sprintf("%s limit of %s exceeded, %s left", e.Limit, e.Amount, e.Headroom)
+}
+
+// IsNight reports whether the night-time limit applies at t.
+func IsNight(t time.Time) bool {
+	return t.Hour() >= NightStartHour || t.Hour() < NightEndHour
+}
+
+// StartOfDay returns the midnight the daily limit counts from.
+func StartOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// StartOfMonth returns the first midnight of the month the monthly limit
+// counts from.
+func StartOfMonth(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
+
+// Check returns the first limit sending amount at t would exceed, given what
+// the user already sent today and this month, or nil when it fits all of them.
+func (l *Limits) Check(amount, sentToday, sentThisMonth money.Money, at time.Time) *ErrLimitExceeded {
+	if IsNight(at) && l.NightPerTransaction != nil && l.NightPerTransaction.LessThan(amount) {
+		return &ErrLimitExceeded{Limit: LIMIT_NIGHT_PER_TRANSACTION, Amount: *l.NightPerTransaction, Headroom: *l.NightPerTransaction}
+	}
+
+	if l.PerTransaction != nil && l.PerTransaction.LessThan(amount) {
+		return &ErrLimitExceeded{Limit: LIMIT_PER_TRANSACTION, Amount: *l.PerTransaction, Headroom: *l.PerTransaction}
+	}
+
+	if l.Daily != nil && l.Daily.Amount-sentToday.Amount < amount.Amount {
+		return &ErrLimitExceeded{Limit: LIMIT_DAILY, Amount: *l.Daily, Headroom: headroom(*l.Daily, sentToday)}
+	}
+
+	if l.Monthly != nil && l.Monthly.Amount-sentThisMonth.Amount < amount.Amount {
+		return &ErrLimitExceeded{Limit: LIMIT_MONTHLY, Amount: *l.Monthly, Headroom: headroom(*l.Monthly, sentThisMonth)}
+	}
+
+	return nil
+}
+
+// headroom returns what is left of the limit once sent is taken from it,
+// never less than zero.
+func headroom(limit, sent money.Money) money.Money {
+	if limit.Amount <= sent.Amount {
+		return money.New(0)
+	}
+
+	return money.New(limit.Amount - sent.Amount)
+}
@@ -0,0 +1,73 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveLimits(t *testing.T) {
+	amount := func(cents int64) *money.Money {
+		m := money.New(cents)
+		return &m
+	}
+
+	defaults := Limits{UserId: DefaultLimitsId, PerTransaction: amount(1000), Daily: amount(5000)}
+	override := Limits{UserId: "user-id", Daily: amount(20000), Monthly: amount(100000)}
+
+	assert.Equal(t, &Limits{UserId: "user-id", PerTransaction: amount(1000), Daily: amount(20000), Monthly: amount(100000)}, ResolveLimits("user-id", []Limits{override, defaults}))
+	assert.Equal(t, &Limits{UserId: "user-id", PerTransaction: amount(1000), Daily: amount(5000)}, ResolveLimits("user-id", []Limits{defaults}))
+	assert.Equal(t, &Limits{UserId: "user-id"}, ResolveLimits("user-id", nil))
+}
+
+func TestLimitsCheck(t *testing.T) {
+	amount := func(cents int64) *money.Money {
+		m := money.New(cents)
+		return &m
+	}
+
+	noon := time.Date(2023, 5, 11, 12, 0, 0, 0, time.UTC)
+	dawn := time.Date(2023, 5, 11, 5, 59, 0, 0, time.UTC)
+
+	limits := &Limits{PerTransaction: amount(1000), NightPerTransaction: amount(300), Daily: amount(2000), Monthly: amount(5000)}
+
+	cases := map[string]struct {
+		Amount        int64
+		SentToday     int64
+		SentThisMonth int64
+		At            time.Time
+		Expected      *ErrLimitExceeded
+	}{
+		"dentro dos limites":     {1000, 1000, 4000, noon, nil},
+		"por transacao":          {1001, 0, 0, noon, &ErrLimitExceeded{LIMIT_PER_TRANSACTION, money.New(1000), money.New(1000)}},
+		"noturno":                {301, 0, 0, dawn, &ErrLimitExceeded{LIMIT_NIGHT_PER_TRANSACTION, money.New(300), money.New(300)}},
+		"diario":                 {600, 1500, 1500, noon, &ErrLimitExceeded{LIMIT_DAILY, money.New(2000), money.New(500)}},
+		"mensal":                 {600, 0, 4500, noon, &ErrLimitExceeded{LIMIT_MONTHLY, money.New(5000), money.New(500)}},
+		"mensal ja ultrapassado": {1, 0, 6000, noon, &ErrLimitExceeded{LIMIT_MONTHLY, money.New(5000), money.New(0)}},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			exceeded := limits.Check(money.New(cs.Amount), money.New(cs.SentToday), money.New(cs.SentThisMonth), cs.At)
+			assert.Equal(t, cs.Expected, exceeded)
+		})
+	}
+
+	assert.Equal(t, "DAILY limit of 20.00 exceeded, 5.00 left", (&ErrLimitExceeded{LIMIT_DAILY, money.New(2000), money.New(500)}).Error())
+}
+
+func TestIsNight(t *testing.T) {
+	assert.True(t, IsNight(time.Date(2023, 5, 11, 20, 0, 0, 0, time.UTC)))
+	assert.True(t, IsNight(time.Date(2023, 5, 11, 5, 59, 0, 0, time.UTC)))
+	assert.False(t, IsNight(time.Date(2023, 5, 11, 6, 0, 0, 0, time.UTC)))
+	assert.False(t, IsNight(time.Date(2023, 5, 11, 19, 59, 0, 0, time.UTC)))
+}
+
+func TestStartOfDayAndMonth(t *testing.T) {
+	at := time.Date(2023, 5, 11, 15, 30, 0, 0, time.UTC)
+
+	assert.Equal(t, time.Date(2023, 5, 11, 0, 0, 0, 0, time.UTC), StartOfDay(at))
+	assert.Equal(t, time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC), StartOfMonth(at))
+}
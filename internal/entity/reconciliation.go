@@ -0,0 +1,56 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/money"
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/uuid"
+)
+
+// Reconciliation is the report of the users whose balance differs from the
+// one recomputed from their postings when it was run.
+type Reconciliation struct {
+	ID           string                     `json:"id"`
+	DriftedUsers int                        `json:"driftedUsers" db:"drifted_users"`
+	Differences  []ReconciliationDifference `json:"differences,omitempty"`
+	CreatedAt    *time.Time                 `json:"createdAt" db:"created_at"`
+}
+
+// ReconciliationDifference is the drift of the balance of an account, whose ID
+// is kept in UserId as it was before users could hold several accounts; for a
+// default account it's the ID of the user. Difference is what the balance is
+// missing to match ExpectedBalance, and correcting it sets the balance to
+// ExpectedBalance.
+type ReconciliationDifference struct {
+	ID               int64       `json:"id"`
+	ReconciliationId string      `json:"-" db:"reconciliation_id"`
+	UserId           string      `json:"userId" db:"user_id"`
+	Balance          money.Money `json:"balance" swaggertype:"string" example:"100.10"`
+	ExpectedBalance  money.Money `json:"expectedBalance" db:"expected_balance" swaggertype:"string" example:"90.10"`
+	Difference       money.Money `json:"difference" swaggertype:"string" example:"-10.00"`
+	ApprovedBy       *string     `json:"approvedBy,omitempty" db:"approved_by"`
+	CorrectedAt      *time.Time  `json:"correctedAt,omitempty" db:"corrected_at"`
+}
+
+func NewReconciliation(differences []ReconciliationDifference) *Reconciliation {
+	now := time.Now()
+	reconciliation := &Reconciliation{
+		ID:           uuid.NewId(),
+		DriftedUsers: len(differences),
+		Differences:  differences,
+		CreatedAt:    &now,
+	}
+
+	for i := range reconciliation.Differences {
+		difference := &reconciliation.Differences[i]
+		difference.ReconciliationId = reconciliation.ID
+		difference.Difference = money.New(difference.ExpectedBalance.Amount - difference.Balance.Amount)
+	}
+
+	return reconciliation
+}
+
+// IsCorrected reports whether the balance of the user was already corrected.
+func (d *ReconciliationDifference) IsCorrected() bool {
+	return d.CorrectedAt != nil
+}
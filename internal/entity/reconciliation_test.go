@@ -0,0 +1,25 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewReconciliation(t *testing.T) {
+	reconciliation := NewReconciliation([]ReconciliationDifference{
+		{UserId: "user-id", Balance: money.New(10010), ExpectedBalance: money.New(9010)},
+		{UserId: "other-user-id", Balance: money.New(-500), ExpectedBalance: money.New(0)},
+	})
+
+	assert.NotEmpty(t, reconciliation.ID)
+	assert.Equal(t, 2, reconciliation.DriftedUsers)
+	assert.Equal(t, reconciliation.ID, reconciliation.Differences[0].ReconciliationId)
+	assert.Equal(t, money.New(-1000), reconciliation.Differences[0].Difference)
+	assert.Equal(t, money.New(500), reconciliation.Differences[1].Difference)
+	assert.False(t, reconciliation.Differences[0].IsCorrected())
+
+	reconciliation = NewReconciliation([]ReconciliationDifference{})
+	assert.Equal(t, 0, reconciliation.DriftedUsers)
+}
@@ -0,0 +1,140 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/garoque/backend-code-challenge-snapfi/internal/api/dto"
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/money"
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/uuid"
+)
+
+var (
+	ErrSplitExceedsAmount = errors.New("the fixed amounts of the split exceed the payment amount")
+	ErrSplitIncomplete    = errors.New("the split rules must distribute the whole payment amount")
+	ErrSplitPercentages   = errors.New("the percentages of the split must add up to 100")
+)
+
+// Split is a payment of the source user divided among several destinations.
+// Every share is booked as its own transaction, all of them linked to the
+// split by their ParentId.
+type Split struct {
+	ID           string         `json:"id"`
+	SourceId     string         `json:"senderId"`
+	Amount       money.Money    `json:"amount" swaggertype:"string" example:"100.00"`
+	Rules        []SplitRule    `json:"-"`
+	Transactions []*Transaction `json:"transactions"`
+}
+
+// SplitRule gives the destination either a fixed Amount or a share, in basis
+// points, of what is left once the fixed amounts are paid.
+type SplitRule struct {
+	DestinationId string
+	Amount        *money.Money
+	BasisPoints   *int64
+}
+
+func NewSplit(split dto.CreateSplit) *Split {
+	rules := make([]SplitRule, 0, len(split.Rules))
+	for _, rule := range split.Rules {
+		splitRule := SplitRule{
+			DestinationId: AccountId(rule.DestinationAccountId, rule.DestinationUserId),
+			Amount:        rule.Amount,
+		}
+		if rule.Percentage != nil {
+			basisPoints := int64(math.Round(*rule.Percentage * 100))
+			splitRule.BasisPoints = &basisPoints
+		}
+
+		rules = append(rules, splitRule)
+	}
+
+	return &Split{
+		ID:       uuid.NewId(),
+		SourceId: AccountId(split.SourceAccountId, split.SourceUserId),
+		Amount:   split.Amount,
+		Rules:    rules,
+	}
+}
+
+// NewSplitFromTransactions rebuilds a stored split from its transactions.
+func NewSplitFromTransactions(id string, transactions []Transaction) *Split {
+	split := &Split{ID: id, Amount: money.New(0)}
+	for i := range transactions {
+		split.SourceId = transactions[i].SourceId
+		split.Amount.Amount += transactions[i].Amount.Amount
+		split.Transactions = append(split.Transactions, &transactions[i])
+	}
+
+	return split
+}
+
+// Allocate turns the rules into one transfer per destination. Fixed amounts
+// are paid first and the rest is divided by percentage, rounding every share
+// down. The cents lost to rounding go to the first percentage rule, so the
+// shares always add up to the payment amount.
+func (s *Split) Allocate() error {
+	amounts := make([]int64, len(s.Rules))
+	remaining := s.Amount.Amount
+	percentages := make([]int, 0, len(s.Rules))
+	var basisPoints int64
+
+	for i, rule := range s.Rules {
+		switch {
+		case rule.Amount != nil && rule.BasisPoints == nil:
+			if !rule.Amount.IsPositive() {
+				return fmt.Errorf("rule %d: the provided value is zero or negative", i)
+			}
+
+			amounts[i] = rule.Amount.Amount
+			remaining -= rule.Amount.Amount
+			if remaining < 0 {
+				return ErrSplitExceedsAmount
+			}
+		case rule.BasisPoints != nil && rule.Amount == nil:
+			percentages = append(percentages, i)
+			basisPoints += *rule.BasisPoints
+		default:
+			return fmt.Errorf("rule %d: provide either an amount or a percentage", i)
+		}
+	}
+
+	if len(percentages) == 0 && remaining != 0 {
+		return ErrSplitIncomplete
+	}
+
+	if len(percentages) > 0 && basisPoints != 10000 {
+		return ErrSplitPercentages
+	}
+
+	distributed := int64(0)
+	for _, i := range percentages {
+		// Split the product so large amounts can't overflow int64.
+		rate := *s.Rules[i].BasisPoints
+		share := remaining/10000*rate + remaining%10000*rate/10000
+		amounts[i] = share
+		distributed += share
+	}
+	if len(percentages) > 0 {
+		amounts[percentages[0]] += remaining - distributed
+	}
+
+	s.Transactions = make([]*Transaction, 0, len(s.Rules))
+	for i, rule := range s.Rules {
+		if amounts[i] == 0 {
+			return fmt.Errorf("rule %d: the share is zero", i)
+		}
+
+		s.Transactions = append(s.Transactions, &Transaction{
+			ID:            uuid.NewId(),
+			SourceId:      s.SourceId,
+			DestinationId: rule.DestinationId,
+			Amount:        money.New(amounts[i]),
+			Kind:          TRANSFER,
+			ParentId:      &s.ID,
+		})
+	}
+
+	return nil
+}
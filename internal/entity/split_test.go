@@ -0,0 +1,137 @@
+package entity
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/garoque/backend-code-challenge-snapfi/internal/api/dto"
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSplit(t *testing.T) {
+	amount := money.New(500)
+	percentage := 12.5
+
+	split := NewSplit(dto.CreateSplit{
+		SourceUserId: "source-user-id",
+		Amount:       money.New(10000),
+		Rules: []dto.SplitRule{
+			{DestinationUserId: "platform-id", Amount: &amount},
+			{DestinationUserId: "seller-id", Percentage: &percentage},
+		},
+	})
+	assert.NotEmpty(t, split.ID)
+	assert.Equal(t, "source-user-id", split.SourceId)
+	assert.Equal(t, &amount, split.Rules[0].Amount)
+	assert.Nil(t, split.Rules[0].BasisPoints)
+	assert.Equal(t, int64(1250), *split.Rules[1].BasisPoints)
+
+	betweenAccounts := NewSplit(dto.CreateSplit{
+		SourceAccountId: "source-account-id",
+		Amount:          money.New(10000),
+		Rules:           []dto.SplitRule{{DestinationAccountId: "seller-account-id", Percentage: &percentage}},
+	})
+	assert.Equal(t, "source-account-id", betweenAccounts.SourceId)
+	assert.Equal(t, "seller-account-id", betweenAccounts.Rules[0].DestinationId)
+}
+
+func TestAllocate(t *testing.T) {
+	fixed := func(cents int64) SplitRule {
+		amount := money.New(cents)
+		return SplitRule{DestinationId: "destination-id", Amount: &amount}
+	}
+	percent := func(basisPoints int64) SplitRule {
+		return SplitRule{DestinationId: "destination-id", BasisPoints: &basisPoints}
+	}
+
+	cases := map[string]struct {
+		Amount      int64
+		Rules       []SplitRule
+		Expected    []int64
+		ExpectedErr error
+	}{
+		"por percentual": {
+			Amount:   10000,
+			Rules:    []SplitRule{percent(9000), percent(1000)},
+			Expected: []int64{9000, 1000},
+		},
+		"sobra do arredondamento vai para a primeira regra percentual": {
+			Amount:   100,
+			Rules:    []SplitRule{percent(3333), percent(3333), percent(3334)},
+			Expected: []int64{34, 33, 33},
+		},
+		"valor fixo e percentual sobre o restante": {
+			Amount:   10001,
+			Rules:    []SplitRule{fixed(1001), percent(5000), percent(5000)},
+			Expected: []int64{1001, 4500, 4500},
+		},
+		"somente valores fixos": {
+			Amount:   10000,
+			Rules:    []SplitRule{fixed(7000), fixed(3000)},
+			Expected: []int64{7000, 3000},
+		},
+		"valor alto sem overflow": {
+			Amount:   9000000000000000000,
+			Rules:    []SplitRule{percent(5000), percent(5000)},
+			Expected: []int64{4500000000000000000, 4500000000000000000},
+		},
+		"deve retornar erro: valores fixos maiores que o pagamento": {
+			Amount:      1000,
+			Rules:       []SplitRule{fixed(700), fixed(400)},
+			ExpectedErr: ErrSplitExceedsAmount,
+		},
+		"deve retornar erro: valores fixos nao cobrem o pagamento": {
+			Amount:      1000,
+			Rules:       []SplitRule{fixed(700)},
+			ExpectedErr: ErrSplitIncomplete,
+		},
+		"deve retornar erro: percentuais nao somam 100": {
+			Amount:      1000,
+			Rules:       []SplitRule{percent(9000), percent(500)},
+			ExpectedErr: ErrSplitPercentages,
+		},
+		"deve retornar erro: regra sem valor nem percentual": {
+			Amount:      1000,
+			Rules:       []SplitRule{{DestinationId: "destination-id"}},
+			ExpectedErr: errors.New("rule 0: provide either an amount or a percentage"),
+		},
+		"deve retornar erro: parcela zerada": {
+			Amount:      1,
+			Rules:       []SplitRule{percent(5000), percent(5000)},
+			ExpectedErr: errors.New("rule 1: the share is zero"),
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			split := &Split{ID: "split-id", SourceId: "source-user-id", Amount: money.New(cs.Amount), Rules: cs.Rules}
+
+			err := split.Allocate()
+			assert.Equal(t, cs.ExpectedErr, err)
+			if err != nil {
+				return
+			}
+
+			amounts := make([]int64, 0, len(split.Transactions))
+			for _, transaction := range split.Transactions {
+				amounts = append(amounts, transaction.Amount.Amount)
+				assert.Equal(t, "split-id", *transaction.ParentId)
+				assert.Equal(t, "source-user-id", transaction.SourceId)
+				assert.Equal(t, TRANSFER, transaction.Kind)
+			}
+			assert.Equal(t, cs.Expected, amounts)
+		})
+	}
+}
+
+func TestNewSplitFromTransactions(t *testing.T) {
+	split := NewSplitFromTransactions("split-id", []Transaction{
+		{ID: "transaction-id-1", SourceId: "source-user-id", Amount: money.New(9000)},
+		{ID: "transaction-id-2", SourceId: "source-user-id", Amount: money.New(1000)},
+	})
+	assert.Equal(t, "split-id", split.ID)
+	assert.Equal(t, "source-user-id", split.SourceId)
+	assert.Equal(t, money.New(10000), split.Amount)
+	assert.Len(t, split.Transactions, 2)
+}
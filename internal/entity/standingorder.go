@@ -0,0 +1,239 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/garoque/backend-code-challenge-snapfi/internal/api/dto"
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/money"
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/uuid"
+)
+
+type Frequency int
+
+// The values are stored in the database, so new frequencies must be appended.
+const (
+	DAILY Frequency = iota
+	WEEKLY
+	MONTHLY
+)
+
+var FrequencyString = []string{
+	"DAILY", "WEEKLY", "MONTHLY",
+}
+
+func (f Frequency) String() string {
+	return FrequencyString[f]
+}
+
+// ParseFrequency returns the frequency named s, reporting whether it exists.
+func ParseFrequency(s string) (Frequency, bool) {
+	for i, name := range FrequencyString {
+		if name == s {
+			return Frequency(i), true
+		}
+	}
+
+	return 0, false
+}
+
+type StatesStandingOrder int
+
+const (
+	ORDER_ACTIVE StatesStandingOrder = iota
+	ORDER_COMPLETED
+	ORDER_CANCELLED
+)
+
+var StatesStandingOrderString = []string{
+	"ACTIVE", "COMPLETED", "CANCELLED",
+}
+
+func (st StatesStandingOrder) String() string {
+	return StatesStandingOrderString[st]
+}
+
+// StandingOrder transfers Amount from the source to the destination user on
+// every occurrence of its recurrence rule: starting at StartAt and repeating
+// with Frequency until EndAt or until Count occurrences were executed,
+// whichever comes first. Without both it repeats until cancelled.
+//
+// Occurrences counts the occurrences already executed, booked or failed, and
+// Attempts the failed attempts of the next one. NextRunAt is nil once the
+// order is no longer active.
+type StandingOrder struct {
+	ID              string              `json:"id"`
+	SourceId        string              `json:"senderId" db:"id_source"`
+	DestinationId   string              `json:"receiverId" db:"id_destination"`
+	Amount          money.Money         `json:"amount" swaggertype:"string" example:"150.00"`
+	Frequency       Frequency           `json:"-" db:"frequency"`
+	FrequencyString string              `json:"frequency,omitempty"`
+	StartAt         time.Time           `json:"startAt" db:"start_at"`
+	EndAt           *time.Time          `json:"endAt,omitempty" db:"end_at"`
+	Count           *int                `json:"count,omitempty" db:"count"`
+	Occurrences     int                 `json:"occurrences" db:"occurrences"`
+	Attempts        int                 `json:"attempts" db:"attempts"`
+	NextRunAt       *time.Time          `json:"nextRunAt,omitempty" db:"next_run_at"`
+	State           StatesStandingOrder `json:"-" db:"state"`
+	StateString     string              `json:"state,omitempty"`
+	CreatedAt       *time.Time          `json:"createdAt" db:"created_at"`
+	UpdatedAt       *time.Time          `json:"updatedAt,omitempty" db:"updated_at"`
+}
+
+func NewStandingOrder(order dto.CreateStandingOrder) *StandingOrder {
+	frequency, _ := ParseFrequency(order.Frequency)
+	nextRunAt := order.StartAt
+
+	return &StandingOrder{
+		ID:            uuid.NewId(),
+		SourceId:      AccountId(order.SourceAccountId, order.SourceUserId),
+		DestinationId: AccountId(order.DestinationAccountId, order.DestinationUserId),
+		Amount:        order.Amount,
+		Frequency:     frequency,
+		StartAt:       order.StartAt,
+		EndAt:         order.EndAt,
+		Count:         order.Count,
+		NextRunAt:     &nextRunAt,
+		State:         ORDER_ACTIVE,
+	}
+}
+
+// OccurrenceAt returns the date of the nth occurrence, counting from zero.
+// Monthly occurrences keep the day of StartAt, falling back to the last day
+// of shorter months.
+func (o *StandingOrder) OccurrenceAt(n int) time.Time {
+	switch o.Frequency {
+	case WEEKLY:
+		return o.StartAt.AddDate(0, 0, 7*n)
+	case MONTHLY:
+		return addMonths(o.StartAt, n)
+	default:
+		return o.StartAt.AddDate(0, 0, n)
+	}
+}
+
+func addMonths(t time.Time, n int) time.Time {
+	year, month, day := t.Date()
+	lastDay := time.Date(year, month+time.Month(n)+1, 0, 0, 0, 0, 0, t.Location()).Day()
+	if day > lastDay {
+		day = lastDay
+	}
+
+	return time.Date(year, month+time.Month(n), day, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+}
+
+// IsDue reports whether the next occurrence should run at now.
+func (o *StandingOrder) IsDue(now time.Time) bool {
+	return o.State == ORDER_ACTIVE && o.NextRunAt != nil && !o.NextRunAt.After(now)
+}
+
+// NewTransaction returns the ordinary transfer executing the next occurrence.
+func (o *StandingOrder) NewTransaction() *Transaction {
+	return NewTransaction(dto.CreateTransaction{
+		SourceUserId:      o.SourceId,
+		DestinationUserId: o.DestinationId,
+		Amount:            o.Amount,
+	})
+}
+
+// Retry postpones the next occurrence after a failed attempt.
+func (o *StandingOrder) Retry(at time.Time) {
+	o.Attempts++
+	o.NextRunAt = &at
+}
+
+// CompleteOccurrence moves on to the following occurrence, completing the
+// order when the recurrence rule has no more of them.
+func (o *StandingOrder) CompleteOccurrence() {
+	o.Occurrences++
+	o.Attempts = 0
+
+	next := o.OccurrenceAt(o.Occurrences)
+	o.NextRunAt = &next
+	o.completeIfFinished()
+}
+
+func (o *StandingOrder) completeIfFinished() {
+	if o.State != ORDER_ACTIVE {
+		return
+	}
+
+	countReached := o.Count != nil && o.Occurrences >= *o.Count
+	endReached := o.EndAt != nil && o.OccurrenceAt(o.Occurrences).After(*o.EndAt)
+	if countReached || endReached {
+		o.State = ORDER_COMPLETED
+		o.StateString = ORDER_COMPLETED.String()
+		o.NextRunAt = nil
+	}
+}
+
+// Cancel stops the order, no further occurrences are executed.
+func (o *StandingOrder) Cancel() {
+	o.State = ORDER_CANCELLED
+	o.StateString = ORDER_CANCELLED.String()
+	o.NextRunAt = nil
+}
+
+// StandingOrderChanges holds the fields of an active order that can still be
+// changed. Nil fields are left as they are.
+type StandingOrderChanges struct {
+	Amount *money.Money
+	EndAt  *time.Time
+	Count  *int
+}
+
+func NewStandingOrderChanges(changes dto.UpdateStandingOrder) *StandingOrderChanges {
+	return &StandingOrderChanges{
+		Amount: changes.Amount,
+		EndAt:  changes.EndAt,
+		Count:  changes.Count,
+	}
+}
+
+// Apply changes the order, completing it when the new end date or count has
+// already been reached.
+func (o *StandingOrder) Apply(changes *StandingOrderChanges) {
+	if changes.Amount != nil {
+		o.Amount = *changes.Amount
+	}
+
+	if changes.EndAt != nil {
+		o.EndAt = changes.EndAt
+	}
+
+	if changes.Count != nil {
+		o.Count = changes.Count
+	}
+
+	o.completeIfFinished()
+}
+
+// StandingOrderOccurrence records how an occurrence of a standing order
+// ended: the transaction that executed it, BOOKED or FAILED, and after how
+// many attempts.
+type StandingOrderOccurrence struct {
+	ID              int64             `json:"-"`
+	StandingOrderId string            `json:"standingOrderId" db:"standing_order_id"`
+	Sequence        int               `json:"sequence"`
+	TransactionId   string            `json:"transactionId" db:"transaction_id"`
+	State           StatesTransaction `json:"-" db:"state"`
+	StateString     string            `json:"state,omitempty"`
+	Attempts        int               `json:"attempts"`
+	Reason          string            `json:"reason,omitempty"`
+	ScheduledAt     time.Time         `json:"scheduledAt" db:"scheduled_at"`
+	CreatedAt       *time.Time        `json:"createdAt" db:"created_at"`
+}
+
+// NewOccurrence records the outcome of the next occurrence of the order,
+// executed by transaction.
+func (o *StandingOrder) NewOccurrence(transaction *Transaction, reason string) *StandingOrderOccurrence {
+	return &StandingOrderOccurrence{
+		StandingOrderId: o.ID,
+		Sequence:        o.Occurrences + 1,
+		TransactionId:   transaction.ID,
+		State:           transaction.State,
+		StateString:     transaction.State.String(),
+		Attempts:        o.Attempts + 1,
+		Reason:          reason,
+		ScheduledAt:     o.OccurrenceAt(o.Occurrences),
+	}
+}
@@ -0,0 +1,178 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/garoque/backend-code-challenge-snapfi/internal/api/dto"
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFrequencyString(t *testing.T) {
+	assert.Equal(t, "DAILY", DAILY.String())
+	assert.Equal(t, "WEEKLY", WEEKLY.String())
+	assert.Equal(t, "MONTHLY", MONTHLY.String())
+
+	frequency, ok := ParseFrequency("WEEKLY")
+	assert.True(t, ok)
+	assert.Equal(t, WEEKLY, frequency)
+
+	_, ok = ParseFrequency("YEARLY")
+	assert.False(t, ok)
+}
+
+func TestNewStandingOrder(t *testing.T) {
+	startAt := time.Date(2023, 5, 10, 12, 0, 0, 0, time.UTC)
+
+	order := NewStandingOrder(dto.CreateStandingOrder{
+		SourceUserId:      "source-user-id",
+		DestinationUserId: "destination-user-id",
+		Amount:            money.New(15000),
+		Frequency:         "MONTHLY",
+		StartAt:           startAt,
+	})
+	assert.NotEmpty(t, order.ID)
+	assert.Equal(t, MONTHLY, order.Frequency)
+	assert.Equal(t, ORDER_ACTIVE, order.State)
+	assert.Equal(t, startAt, *order.NextRunAt)
+
+	transaction := order.NewTransaction()
+	assert.Equal(t, "source-user-id", transaction.SourceId)
+	assert.Equal(t, "destination-user-id", transaction.DestinationId)
+	assert.Equal(t, money.New(15000), transaction.Amount)
+	assert.Equal(t, TRANSFER, transaction.Kind)
+
+	betweenAccounts := NewStandingOrder(dto.CreateStandingOrder{
+		SourceAccountId:   "source-account-id",
+		DestinationUserId: "destination-user-id",
+		Amount:            money.New(15000),
+		Frequency:         "MONTHLY",
+		StartAt:           startAt,
+	})
+	assert.Equal(t, "source-account-id", betweenAccounts.SourceId)
+	assert.Equal(t, "destination-user-id", betweenAccounts.DestinationId)
+}
+
+func TestOccurrenceAt(t *testing.T) {
+	cases := map[string]struct {
+		Frequency Frequency
+		StartAt   time.Time
+		N         int
+		Expected  time.Time
+	}{
+		"daily": {
+			Frequency: DAILY,
+			StartAt:   time.Date(2023, 5, 10, 12, 0, 0, 0, time.UTC),
+			N:         3,
+			Expected:  time.Date(2023, 5, 13, 12, 0, 0, 0, time.UTC),
+		},
+		"weekly": {
+			Frequency: WEEKLY,
+			StartAt:   time.Date(2023, 5, 10, 12, 0, 0, 0, time.UTC),
+			N:         2,
+			Expected:  time.Date(2023, 5, 24, 12, 0, 0, 0, time.UTC),
+		},
+		"monthly": {
+			Frequency: MONTHLY,
+			StartAt:   time.Date(2023, 5, 10, 12, 0, 0, 0, time.UTC),
+			N:         8,
+			Expected:  time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC),
+		},
+		"monthly no fim de um mes mais curto": {
+			Frequency: MONTHLY,
+			StartAt:   time.Date(2023, 1, 31, 12, 0, 0, 0, time.UTC),
+			N:         1,
+			Expected:  time.Date(2023, 2, 28, 12, 0, 0, 0, time.UTC),
+		},
+		"monthly depois de um mes mais curto": {
+			Frequency: MONTHLY,
+			StartAt:   time.Date(2023, 1, 31, 12, 0, 0, 0, time.UTC),
+			N:         2,
+			Expected:  time.Date(2023, 3, 31, 12, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			order := &StandingOrder{Frequency: cs.Frequency, StartAt: cs.StartAt}
+			assert.Equal(t, cs.Expected, order.OccurrenceAt(cs.N))
+		})
+	}
+}
+
+func TestCompleteOccurrence(t *testing.T) {
+	startAt := time.Date(2023, 5, 10, 12, 0, 0, 0, time.UTC)
+	endAt := time.Date(2023, 6, 30, 0, 0, 0, 0, time.UTC)
+	count := 2
+
+	t.Run("ate a data final", func(t *testing.T) {
+		order := &StandingOrder{Frequency: MONTHLY, StartAt: startAt, EndAt: &endAt, NextRunAt: &startAt}
+
+		order.CompleteOccurrence()
+		assert.Equal(t, ORDER_ACTIVE, order.State)
+		assert.Equal(t, time.Date(2023, 6, 10, 12, 0, 0, 0, time.UTC), *order.NextRunAt)
+
+		order.CompleteOccurrence()
+		assert.Equal(t, ORDER_COMPLETED, order.State)
+		assert.Nil(t, order.NextRunAt)
+		assert.Equal(t, 2, order.Occurrences)
+	})
+
+	t.Run("ate o numero de ocorrencias", func(t *testing.T) {
+		order := &StandingOrder{Frequency: DAILY, StartAt: startAt, Count: &count, NextRunAt: &startAt}
+
+		order.Retry(startAt.Add(time.Hour))
+		assert.Equal(t, 1, order.Attempts)
+		assert.Equal(t, startAt.Add(time.Hour), *order.NextRunAt)
+
+		order.CompleteOccurrence()
+		assert.Equal(t, ORDER_ACTIVE, order.State)
+		assert.Equal(t, 0, order.Attempts)
+
+		order.CompleteOccurrence()
+		assert.Equal(t, ORDER_COMPLETED, order.State)
+		assert.Nil(t, order.NextRunAt)
+	})
+}
+
+func TestApply(t *testing.T) {
+	startAt := time.Date(2023, 5, 10, 12, 0, 0, 0, time.UTC)
+	nextRunAt := startAt.AddDate(0, 0, 2)
+	amount := money.New(20000)
+	count := 2
+
+	order := &StandingOrder{Frequency: DAILY, StartAt: startAt, Amount: money.New(15000), Occurrences: 2, NextRunAt: &nextRunAt}
+
+	order.Apply(NewStandingOrderChanges(dto.UpdateStandingOrder{Amount: &amount}))
+	assert.Equal(t, amount, order.Amount)
+	assert.Equal(t, ORDER_ACTIVE, order.State)
+
+	order.Apply(NewStandingOrderChanges(dto.UpdateStandingOrder{Count: &count}))
+	assert.Equal(t, ORDER_COMPLETED, order.State)
+	assert.Nil(t, order.NextRunAt)
+}
+
+func TestNewOccurrence(t *testing.T) {
+	startAt := time.Date(2023, 5, 10, 12, 0, 0, 0, time.UTC)
+	order := &StandingOrder{ID: "standing-order-id", Frequency: WEEKLY, StartAt: startAt, Occurrences: 1, Attempts: 2}
+
+	occurrence := order.NewOccurrence(&Transaction{ID: "transaction-id", State: FAILED}, "Insufficient balance")
+	assert.Equal(t, "standing-order-id", occurrence.StandingOrderId)
+	assert.Equal(t, 2, occurrence.Sequence)
+	assert.Equal(t, "transaction-id", occurrence.TransactionId)
+	assert.Equal(t, FAILED, occurrence.State)
+	assert.Equal(t, 3, occurrence.Attempts)
+	assert.Equal(t, startAt.AddDate(0, 0, 7), occurrence.ScheduledAt)
+}
+
+func TestCancel(t *testing.T) {
+	startAt := time.Now()
+	order := &StandingOrder{State: ORDER_ACTIVE, NextRunAt: &startAt}
+
+	order.Cancel()
+	assert.Equal(t, ORDER_CANCELLED, order.State)
+	assert.Equal(t, "CANCELLED", order.StateString)
+	assert.Nil(t, order.NextRunAt)
+	assert.False(t, order.IsDue(startAt))
+}
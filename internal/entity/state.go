@@ -0,0 +1,98 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+)
+
+type StatesTransaction int
+
+// The values are stored in the database, so new states must be appended.
+const (
+	PENDING StatesTransaction = iota
+	BOOKED
+	FAILED
+	REVERSED
+	PARTIALLY_REVERSED
+	AUTHORIZED
+	CANCELLED
+	SCHEDULED
+)
+
+var StatesTransactionString = []string{
+	"PENDING", "BOOKED", "FAILED", "REVERSED", "PARTIALLY_REVERSED", "AUTHORIZED", "CANCELLED", "SCHEDULED",
+}
+
+func (st StatesTransaction) String() string {
+	return StatesTransactionString[st]
+}
+
+// transitions lists the states each state may move to. States missing from
+// the table are final.
+var transitions = map[StatesTransaction][]StatesTransaction{
+	PENDING:            {AUTHORIZED, SCHEDULED, BOOKED, FAILED, CANCELLED},
+	AUTHORIZED:         {BOOKED, FAILED, CANCELLED},
+	SCHEDULED:          {BOOKED, FAILED, CANCELLED},
+	BOOKED:             {REVERSED, PARTIALLY_REVERSED},
+	PARTIALLY_REVERSED: {PARTIALLY_REVERSED, REVERSED},
+}
+
+func (st StatesTransaction) CanTransitionTo(next StatesTransaction) bool {
+	for _, allowed := range transitions[st] {
+		if allowed == next {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ErrInvalidTransition is returned when a transaction is asked to move to a
+// state the transitions table doesn't allow from its current state.
+type ErrInvalidTransition struct {
+	From StatesTransaction
+	To   StatesTransaction
+}
+
+func (e *ErrInvalidTransition) Error() string {
+	return fmt.Sprintf("transaction can't move from %s to %s", e.From, e.To)
+}
+
+// StateTransition is one entry of the state history of a transaction.
+type StateTransition struct {
+	ID            int64             `json:"-"`
+	TransactionId string            `json:"transactionId" db:"transaction_id"`
+	From          StatesTransaction `json:"-" db:"from_state"`
+	FromString    string            `json:"from,omitempty"`
+	To            StatesTransaction `json:"-" db:"to_state"`
+	ToString      string            `json:"to,omitempty"`
+	Reason        string            `json:"reason"`
+	CreatedAt     *time.Time        `json:"createdAt" db:"created_at"`
+}
+
+func NewStateTransition(transactionId string, from, to StatesTransaction, reason string) (*StateTransition, error) {
+	if !from.CanTransitionTo(to) {
+		return nil, &ErrInvalidTransition{From: from, To: to}
+	}
+
+	return &StateTransition{
+		TransactionId: transactionId,
+		From:          from,
+		To:            to,
+		Reason:        reason,
+	}, nil
+}
+
+// TransitionTo moves the transaction to the next state and returns the
+// history entry describing the change.
+func (t *Transaction) TransitionTo(next StatesTransaction, reason string) (*StateTransition, error) {
+	transition, err := NewStateTransition(t.ID, t.State, next, reason)
+	if err != nil {
+		return nil, err
+	}
+
+	t.State = next
+	t.StateString = next.String()
+
+	return transition, nil
+}
@@ -0,0 +1,68 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatesTransactionString(t *testing.T) {
+	assert.Equal(t, "PENDING", PENDING.String())
+	assert.Equal(t, "BOOKED", BOOKED.String())
+	assert.Equal(t, "FAILED", FAILED.String())
+	assert.Equal(t, "REVERSED", REVERSED.String())
+	assert.Equal(t, "PARTIALLY_REVERSED", PARTIALLY_REVERSED.String())
+	assert.Equal(t, "AUTHORIZED", AUTHORIZED.String())
+	assert.Equal(t, "CANCELLED", CANCELLED.String())
+	assert.Equal(t, "SCHEDULED", SCHEDULED.String())
+}
+
+func TestCanTransitionTo(t *testing.T) {
+	cases := map[string]struct {
+		From     StatesTransaction
+		To       StatesTransaction
+		Expected bool
+	}{
+		"pending to booked":              {PENDING, BOOKED, true},
+		"pending to authorized":          {PENDING, AUTHORIZED, true},
+		"authorized to cancelled":        {AUTHORIZED, CANCELLED, true},
+		"pending to scheduled":           {PENDING, SCHEDULED, true},
+		"scheduled to booked":            {SCHEDULED, BOOKED, true},
+		"scheduled to cancelled":         {SCHEDULED, CANCELLED, true},
+		"booked to reversed":             {BOOKED, REVERSED, true},
+		"partially reversed to reversed": {PARTIALLY_REVERSED, REVERSED, true},
+		"booked to failed":               {BOOKED, FAILED, false},
+		"failed to booked":               {FAILED, BOOKED, false},
+		"reversed to partially reversed": {REVERSED, PARTIALLY_REVERSED, false},
+		"cancelled to booked":            {CANCELLED, BOOKED, false},
+		"pending to partially reversed":  {PENDING, PARTIALLY_REVERSED, false},
+		"scheduled to authorized":        {SCHEDULED, AUTHORIZED, false},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, cs.Expected, cs.From.CanTransitionTo(cs.To))
+		})
+	}
+}
+
+func TestTransitionTo(t *testing.T) {
+	transaction := &Transaction{ID: "transaction-id", State: PENDING}
+
+	transition, err := transaction.TransitionTo(BOOKED, "transfer booked")
+	assert.NoError(t, err)
+	assert.Equal(t, &StateTransition{
+		TransactionId: "transaction-id",
+		From:          PENDING,
+		To:            BOOKED,
+		Reason:        "transfer booked",
+	}, transition)
+	assert.Equal(t, BOOKED, transaction.State)
+	assert.Equal(t, "BOOKED", transaction.StateString)
+
+	transition, err = transaction.TransitionTo(FAILED, "too late")
+	assert.Nil(t, transition)
+	assert.Equal(t, &ErrInvalidTransition{From: BOOKED, To: FAILED}, err)
+	assert.EqualError(t, err, "transaction can't move from BOOKED to FAILED")
+	assert.Equal(t, BOOKED, transaction.State)
+}
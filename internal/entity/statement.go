@@ -0,0 +1,73 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/money"
+)
+
+// StatementLine is a booked movement on the account of a user. Amount is
+// signed, positive when money came in, and Balance is the balance right after
+// the movement. System accounts have no CounterpartyName.
+type StatementLine struct {
+	TransactionId    string          `json:"transactionId" db:"id_transaction"`
+	Kind             KindTransaction `json:"-" db:"kind"`
+	KindString       string          `json:"kind"`
+	CounterpartyId   string          `json:"counterpartyId" db:"id_counterparty"`
+	CounterpartyName *string         `json:"counterpartyName,omitempty" db:"counterparty_name"`
+	Amount           money.Money     `json:"amount" swaggertype:"string" example:"-100.10"`
+	Balance          money.Money     `json:"balance" db:"-" swaggertype:"string" example:"900.00"`
+	BookedAt         time.Time       `json:"bookedAt" db:"booked_at"`
+}
+
+// Statement lists the movements of an account of a user from Start until
+// before End.
+type Statement struct {
+	UserId         string          `json:"userId"`
+	AccountId      string          `json:"accountId"`
+	Currency       string          `json:"currency" example:"BRL"`
+	Name           string          `json:"name"`
+	Start          time.Time       `json:"start"`
+	End            time.Time       `json:"end"`
+	OpeningBalance money.Money     `json:"openingBalance" swaggertype:"string" example:"1000.00"`
+	TotalIn        money.Money     `json:"totalIn" swaggertype:"string" example:"0.00"`
+	TotalOut       money.Money     `json:"totalOut" swaggertype:"string" example:"100.10"`
+	ClosingBalance money.Money     `json:"closingBalance" swaggertype:"string" example:"899.90"`
+	Lines          []StatementLine `json:"lines"`
+}
+
+// NewStatement runs the balance through the lines, in the order they were
+// booked, from the opening balance at start.
+func NewStatement(user *User, account *Account, start, end time.Time, opening money.Money, lines []StatementLine) *Statement {
+	statement := &Statement{
+		UserId:         user.ID,
+		AccountId:      account.ID,
+		Currency:       account.Currency,
+		Name:           user.Name,
+		Start:          start,
+		End:            end,
+		OpeningBalance: opening,
+		Lines:          lines,
+	}
+
+	balance, in, out := opening.Amount, int64(0), int64(0)
+	for i := range statement.Lines {
+		line := &statement.Lines[i]
+		line.KindString = line.Kind.String()
+
+		balance += line.Amount.Amount
+		line.Balance = money.New(balance)
+
+		if line.Amount.IsNegative() {
+			out -= line.Amount.Amount
+		} else {
+			in += line.Amount.Amount
+		}
+	}
+
+	statement.TotalIn = money.New(in)
+	statement.TotalOut = money.New(out)
+	statement.ClosingBalance = money.New(balance)
+
+	return statement
+}
@@ -0,0 +1,40 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewStatement(t *testing.T) {
+	start := time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	name := "João"
+
+	statement := NewStatement(&User{ID: "user-id", Name: "Gabriel"}, &Account{ID: "account-id", UserId: "user-id", Currency: "USD"}, start, end, money.New(10000), []StatementLine{
+		{TransactionId: "deposit-id", Kind: DEPOSIT, CounterpartyId: FundingAccountId, Amount: money.New(5000)},
+		{TransactionId: "transfer-id", Kind: TRANSFER, CounterpartyId: "destination-id", CounterpartyName: &name, Amount: money.New(-12000)},
+		{TransactionId: "fee-id", Kind: FEE, CounterpartyId: FeeAccountId, Amount: money.New(-100)},
+	})
+
+	assert.Equal(t, "Gabriel", statement.Name)
+	assert.Equal(t, "account-id", statement.AccountId)
+	assert.Equal(t, "USD", statement.Currency)
+	assert.Equal(t, money.New(10000), statement.OpeningBalance)
+	assert.Equal(t, money.New(5000), statement.TotalIn)
+	assert.Equal(t, money.New(12100), statement.TotalOut)
+	assert.Equal(t, money.New(2900), statement.ClosingBalance)
+
+	balances := make([]money.Money, 0, len(statement.Lines))
+	for _, line := range statement.Lines {
+		balances = append(balances, line.Balance)
+	}
+	assert.Equal(t, []money.Money{money.New(15000), money.New(3000), money.New(2900)}, balances)
+	assert.Equal(t, "TRANSFER", statement.Lines[1].KindString)
+
+	statement = NewStatement(&User{ID: "user-id"}, &Account{ID: "user-id", UserId: "user-id"}, start, end, money.New(10000), []StatementLine{})
+	assert.Equal(t, money.New(0), statement.TotalIn)
+	assert.Equal(t, money.New(10000), statement.ClosingBalance)
+}
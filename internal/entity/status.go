@@ -0,0 +1,84 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+)
+
+// StatusUser tells whether the account of a user can move money. FROZEN
+// accounts are blocked until unfrozen, CLOSED ones for good.
+type StatusUser int
+
+// The values are stored in the database, so new statuses must be appended.
+const (
+	ACTIVE StatusUser = iota
+	FROZEN
+	CLOSED
+)
+
+var StatusUserString = []string{
+	"ACTIVE", "FROZEN", "CLOSED",
+}
+
+func (s StatusUser) String() string {
+	return StatusUserString[s]
+}
+
+// statusChanges lists the statuses each status may move to. CLOSED is final.
+var statusChanges = map[StatusUser][]StatusUser{
+	ACTIVE: {FROZEN, CLOSED},
+	FROZEN: {ACTIVE, CLOSED},
+}
+
+func (s StatusUser) CanChangeTo(next StatusUser) bool {
+	for _, allowed := range statusChanges[s] {
+		if allowed == next {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ErrInvalidStatusChange is returned when a user is asked to move to a status
+// the table doesn't allow from their current one.
+type ErrInvalidStatusChange struct {
+	From StatusUser
+	To   StatusUser
+}
+
+func (e *ErrInvalidStatusChange) Error() string {
+	return fmt.Sprintf("user can't move from %s to %s", e.From, e.To)
+}
+
+// StatusChange is one entry of the status history of a user.
+type StatusChange struct {
+	ID         int64      `json:"-"`
+	UserId     string     `json:"userId" db:"user_id"`
+	From       StatusUser `json:"-" db:"from_status"`
+	FromString string     `json:"from,omitempty"`
+	To         StatusUser `json:"-" db:"to_status"`
+	ToString   string     `json:"to,omitempty"`
+	Reason     string     `json:"reason"`
+	CreatedAt  *time.Time `json:"createdAt" db:"created_at"`
+}
+
+// ChangeStatus moves the user to the next status and returns the history
+// entry describing the change.
+func (u *User) ChangeStatus(next StatusUser, reason string) (*StatusChange, error) {
+	if !u.Status.CanChangeTo(next) {
+		return nil, &ErrInvalidStatusChange{From: u.Status, To: next}
+	}
+
+	change := &StatusChange{
+		UserId: u.ID,
+		From:   u.Status,
+		To:     next,
+		Reason: reason,
+	}
+
+	u.Status = next
+	u.StatusString = next.String()
+
+	return change, nil
+}
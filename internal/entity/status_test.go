@@ -0,0 +1,58 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusUserString(t *testing.T) {
+	assert.Equal(t, "ACTIVE", ACTIVE.String())
+	assert.Equal(t, "FROZEN", FROZEN.String())
+	assert.Equal(t, "CLOSED", CLOSED.String())
+}
+
+func TestCanChangeTo(t *testing.T) {
+	cases := map[string]struct {
+		From     StatusUser
+		To       StatusUser
+		Expected bool
+	}{
+		"active to frozen": {ACTIVE, FROZEN, true},
+		"active to closed": {ACTIVE, CLOSED, true},
+		"frozen to active": {FROZEN, ACTIVE, true},
+		"frozen to closed": {FROZEN, CLOSED, true},
+		"active to active": {ACTIVE, ACTIVE, false},
+		"frozen to frozen": {FROZEN, FROZEN, false},
+		"closed to active": {CLOSED, ACTIVE, false},
+		"closed to frozen": {CLOSED, FROZEN, false},
+		"closed to closed": {CLOSED, CLOSED, false},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, cs.Expected, cs.From.CanChangeTo(cs.To))
+		})
+	}
+}
+
+func TestChangeStatus(t *testing.T) {
+	user := &User{ID: "user-id", Status: ACTIVE}
+
+	change, err := user.ChangeStatus(FROZEN, "suspected fraud")
+	assert.NoError(t, err)
+	assert.Equal(t, &StatusChange{
+		UserId: "user-id",
+		From:   ACTIVE,
+		To:     FROZEN,
+		Reason: "suspected fraud",
+	}, change)
+	assert.Equal(t, FROZEN, user.Status)
+	assert.Equal(t, "FROZEN", user.StatusString)
+
+	change, err = user.ChangeStatus(FROZEN, "again")
+	assert.Nil(t, change)
+	assert.Equal(t, &ErrInvalidStatusChange{From: FROZEN, To: FROZEN}, err)
+	assert.EqualError(t, err, "user can't move from FROZEN to FROZEN")
+	assert.Equal(t, FROZEN, user.Status)
+}
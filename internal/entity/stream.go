@@ -0,0 +1,66 @@
+package entity
+
+import (
+	"encoding/json"
+
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/money"
+)
+
+// The events of the stream of a user.
+const (
+	STREAM_EVENT_TRANSACTION = "transaction"
+	STREAM_EVENT_BALANCE     = "balance"
+)
+
+// StreamMessage is a message of the event stream of a user. Only the messages
+// of domain events have an ID, their sequence in the outbox, so a client
+// resuming after one gets the events written after it. A message with only a
+// Comment keeps the connection alive.
+type StreamMessage struct {
+	ID      string
+	Event   string
+	Data    interface{}
+	Comment string
+}
+
+// BalanceUpdate is the balance of an account of a user after one of its
+// transactions changed state.
+type BalanceUpdate struct {
+	UserId           string      `json:"userId"`
+	AccountId        string      `json:"accountId"`
+	Balance          money.Money `json:"balance" swaggertype:"string" example:"100.10"`
+	HeldBalance      money.Money `json:"heldBalance" swaggertype:"string" example:"0.00"`
+	AvailableBalance money.Money `json:"availableBalance" swaggertype:"string" example:"100.10"`
+}
+
+func NewBalanceUpdate(account *Account) BalanceUpdate {
+	return BalanceUpdate{
+		UserId:           account.UserId,
+		AccountId:        account.ID,
+		Balance:          account.Balance,
+		HeldBalance:      account.HeldBalance,
+		AvailableBalance: account.AvailableBalance,
+	}
+}
+
+// EventAccountIds returns the accounts a domain event concerns: the sender
+// and the receiver of a transaction, leaving system accounts out.
+func EventAccountIds(event Event) []string {
+	if event.AggregateType != AGGREGATE_TRANSACTION {
+		return nil
+	}
+
+	var payload TransactionEventPayload
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		return nil
+	}
+
+	accountIds := make([]string, 0, 2)
+	for _, id := range []string{payload.Transaction.SourceId, payload.Transaction.DestinationId} {
+		if id != "" && !IsSystemAccount(id) && (len(accountIds) == 0 || accountIds[0] != id) {
+			accountIds = append(accountIds, id)
+		}
+	}
+
+	return accountIds
+}
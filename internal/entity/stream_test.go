@@ -0,0 +1,24 @@
+package entity
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventAccountIds(t *testing.T) {
+	transfer, _ := json.Marshal(TransactionEventPayload{Transaction: Transaction{SourceId: "sender-id", DestinationId: "receiver-id"}})
+	deposit, _ := json.Marshal(TransactionEventPayload{Transaction: Transaction{SourceId: FundingAccountId, DestinationId: "receiver-id"}})
+
+	assert.Equal(t, []string{"sender-id", "receiver-id"}, EventAccountIds(Event{AggregateType: AGGREGATE_TRANSACTION, Payload: transfer}))
+	assert.Equal(t, []string{"receiver-id"}, EventAccountIds(Event{AggregateType: AGGREGATE_TRANSACTION, Payload: deposit}))
+	assert.Nil(t, EventAccountIds(Event{AggregateType: AGGREGATE_USER, Payload: json.RawMessage(`{"id":"user-id"}`)}))
+}
+
+func TestNewBalanceUpdate(t *testing.T) {
+	update := NewBalanceUpdate(&Account{ID: "account-id", UserId: "user-id", Name: "Savings", Balance: money.New(1000), HeldBalance: money.New(300), AvailableBalance: money.New(700)})
+
+	assert.Equal(t, BalanceUpdate{UserId: "user-id", AccountId: "account-id", Balance: money.New(1000), HeldBalance: money.New(300), AvailableBalance: money.New(700)}, update)
+}
@@ -4,54 +4,179 @@ import (
 	"time"
 
 	"github.com/garoque/backend-code-challenge-snapfi/internal/api/dto"
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/money"
 	"github.com/garoque/backend-code-challenge-snapfi/pkg/uuid"
 )
 
-type StatesTransaction int
+type KindTransaction int
 
 const (
-	OPEN StatesTransaction = iota
-	BOOKED
-	FAILED
+	TRANSFER KindTransaction = iota
+	DEPOSIT
+	WITHDRAWAL
+	REVERSAL
+	FEE
+	INTEREST
+	SWEEP
 )
 
-var StatesTransactionString = []string{
-	"OPEN", "BOOKED", "FAILED",
+var KindTransactionString = []string{
+	"TRANSFER", "DEPOSIT", "WITHDRAWAL", "REVERSAL", "FEE", "INTEREST", "SWEEP",
 }
 
-func (st StatesTransaction) String() string {
-	return StatesTransactionString[st]
+func (k KindTransaction) String() string {
+	return KindTransactionString[k]
+}
+
+// ParseKindTransaction returns the kind named s, reporting whether it exists.
+func ParseKindTransaction(s string) (KindTransaction, bool) {
+	for i, name := range KindTransactionString {
+		if name == s {
+			return KindTransaction(i), true
+		}
+	}
+
+	return 0, false
 }
 
 type Transaction struct {
 	ID            string            `json:"id"`
 	SourceId      string            `json:"senderId" db:"id_source"`
 	DestinationId string            `json:"receiverId" db:"id_destination"`
-	Amount        float64           `json:"amount"`
+	Amount        money.Money       `json:"amount" swaggertype:"string" example:"100.10"`
+	Kind          KindTransaction   `json:"-" db:"kind"`
+	KindString    string            `json:"kind,omitempty"`
+	OriginalId    *string           `json:"originalId,omitempty" db:"id_original"`
+	ParentId      *string           `json:"parentId,omitempty" db:"id_parent"`
 	State         StatesTransaction `json:"-" db:"state"`
 	StateString   string            `json:"state,omitempty"`
+	ExpiresAt     *time.Time        `json:"expiresAt,omitempty" db:"expires_at"`
+	ExecuteAt     *time.Time        `json:"executeAt,omitempty" db:"execute_at"`
 	CreatedAt     *time.Time        `json:"createdAt" db:"created_at"`
+	Fee           *Transaction      `json:"fee,omitempty" db:"-"`
 }
 
 func NewTransaction(tr dto.CreateTransaction) *Transaction {
 	return &Transaction{
 		ID:            uuid.NewId(),
-		SourceId:      tr.SourceUserId,
-		DestinationId: tr.DestinationUserId,
+		SourceId:      AccountId(tr.SourceAccountId, tr.SourceUserId),
+		DestinationId: AccountId(tr.DestinationAccountId, tr.DestinationUserId),
 		Amount:        tr.Amount,
+		Kind:          TRANSFER,
+		ExecuteAt:     tr.ExecuteAt,
+	}
+}
+
+// DefaultAuthorizationTTL is how long an authorization holds funds when the
+// request doesn't say when it expires.
+const DefaultAuthorizationTTL = 7 * 24 * time.Hour
+
+// NewAuthorization reserves the amount on the source account until it is
+// captured, voided or expires.
+func NewAuthorization(tr dto.CreateAuthorization) *Transaction {
+	expiresAt := tr.ExpiresAt
+	if expiresAt == nil {
+		defaultExpiresAt := time.Now().Add(DefaultAuthorizationTTL)
+		expiresAt = &defaultExpiresAt
 	}
+
+	return &Transaction{
+		ID:            uuid.NewId(),
+		SourceId:      AccountId(tr.SourceAccountId, tr.SourceUserId),
+		DestinationId: AccountId(tr.DestinationAccountId, tr.DestinationUserId),
+		Amount:        tr.Amount,
+		Kind:          TRANSFER,
+		ExpiresAt:     expiresAt,
+	}
+}
+
+// IsExpired reports whether the transaction has an expiry that has passed.
+func (t *Transaction) IsExpired(now time.Time) bool {
+	return t.ExpiresAt != nil && !now.Before(*t.ExpiresAt)
+}
+
+// NewWithdrawal moves money out of the balance of the account into the system
+// withdrawals account.
+func NewWithdrawal(tr dto.Withdraw) *Transaction {
+	return &Transaction{
+		ID:            uuid.NewId(),
+		SourceId:      AccountId(tr.AccountId, tr.UserId),
+		DestinationId: WithdrawalAccountId,
+		Amount:        tr.Amount,
+		Kind:          WITHDRAWAL,
+	}
+}
+
+// NewReversal compensates the given amount of the original transaction by
+// moving it back from the original destination to the original source.
+func NewReversal(original *Transaction, amount money.Money) *Transaction {
+	return &Transaction{
+		ID:            uuid.NewId(),
+		SourceId:      original.DestinationId,
+		DestinationId: original.SourceId,
+		Amount:        amount,
+		Kind:          REVERSAL,
+		OriginalId:    &original.ID,
+	}
+}
+
+// NewFee charges the payer of the transaction the given fee, paid into the
+// platform fee account and linked to the transaction by its ParentId.
+func NewFee(transaction *Transaction, payerId string, fee money.Money) *Transaction {
+	return &Transaction{
+		ID:            uuid.NewId(),
+		SourceId:      payerId,
+		DestinationId: FeeAccountId,
+		Amount:        fee,
+		Kind:          FEE,
+		ParentId:      &transaction.ID,
+	}
+}
+
+// NewInterest charges interest on the negative balance of the account, paid
+// into the platform interest account.
+func NewInterest(accountId string, interest money.Money) *Transaction {
+	return &Transaction{
+		ID:            uuid.NewId(),
+		SourceId:      accountId,
+		DestinationId: InterestAccountId,
+		Amount:        interest,
+		Kind:          INTEREST,
+	}
+}
+
+// NewSweep moves what is left on the balance of an account whose owner is
+// being closed into the destination account.
+func NewSweep(sourceId, destinationId string, amount money.Money) *Transaction {
+	return &Transaction{
+		ID:            uuid.NewId(),
+		SourceId:      sourceId,
+		DestinationId: destinationId,
+		Amount:        amount,
+		Kind:          SWEEP,
+	}
+}
+
+// IsReversible reports whether money can still be given back on the
+// transaction. Reversals themselves can't be reversed.
+func (t *Transaction) IsReversible() bool {
+	if t.Kind == REVERSAL {
+		return false
+	}
+
+	return t.State.CanTransitionTo(REVERSED)
 }
 
 type TransactionIncreaseBalanceUser struct {
-	ID     string  `json:"-"`
-	UserId string  `json:"userId"`
-	Value  float64 `json:"value"`
+	ID        string      `json:"-"`
+	AccountId string      `json:"accountId"`
+	Value     money.Money `json:"value"`
 }
 
 func NewIncreaseBalanceUser(tr dto.IncreaseBalanceUser) *TransactionIncreaseBalanceUser {
 	return &TransactionIncreaseBalanceUser{
-		ID:     uuid.NewId(),
-		UserId: tr.UserId,
-		Value:  tr.Value,
+		ID:        uuid.NewId(),
+		AccountId: AccountId(tr.AccountId, tr.UserId),
+		Value:     tr.Value,
 	}
 }
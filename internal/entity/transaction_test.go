@@ -2,8 +2,10 @@ package entity
 
 import (
 	"testing"
+	"time"
 
 	"github.com/garoque/backend-code-challenge-snapfi/internal/api/dto"
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/money"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -11,33 +13,190 @@ func TestNewTransaction(t *testing.T) {
 	transaction := NewTransaction(dto.CreateTransaction{
 		SourceUserId:      "source-user-id",
 		DestinationUserId: "destination-user-id",
-		Amount:            100.10,
+		Amount:            money.New(10010),
 	})
 	assert.NotNil(t, transaction)
 	assert.NotEmpty(t, transaction.ID)
-	assert.Equal(t, 100.10, transaction.Amount)
+	assert.Equal(t, money.New(10010), transaction.Amount)
 	assert.Equal(t, "source-user-id", transaction.SourceId)
 	assert.Equal(t, "destination-user-id", transaction.DestinationId)
+	assert.Equal(t, TRANSFER, transaction.Kind)
+	assert.Nil(t, transaction.ExecuteAt)
+
+	executeAt := time.Now().Add(time.Hour)
+	scheduled := NewTransaction(dto.CreateTransaction{
+		SourceUserId:      "source-user-id",
+		DestinationUserId: "destination-user-id",
+		Amount:            money.New(10010),
+		ExecuteAt:         &executeAt,
+	})
+	assert.Equal(t, &executeAt, scheduled.ExecuteAt)
+
+	betweenAccounts := NewTransaction(dto.CreateTransaction{
+		SourceAccountId:   "source-account-id",
+		DestinationUserId: "destination-user-id",
+		Amount:            money.New(10010),
+	})
+	assert.Equal(t, "source-account-id", betweenAccounts.SourceId)
+	assert.Equal(t, "destination-user-id", betweenAccounts.DestinationId)
+}
+
+func TestNewWithdrawal(t *testing.T) {
+	transaction := NewWithdrawal(dto.Withdraw{
+		UserId: "user-id",
+		Amount: money.New(10010),
+	})
+	assert.NotNil(t, transaction)
+	assert.NotEmpty(t, transaction.ID)
+	assert.Equal(t, money.New(10010), transaction.Amount)
+	assert.Equal(t, "user-id", transaction.SourceId)
+	assert.Equal(t, WithdrawalAccountId, transaction.DestinationId)
+	assert.Equal(t, WITHDRAWAL, transaction.Kind)
+
+	fromAccount := NewWithdrawal(dto.Withdraw{
+		AccountId: "account-id",
+		Amount:    money.New(10010),
+	})
+	assert.Equal(t, "account-id", fromAccount.SourceId)
 }
 
 func TestNewIncreaseBalanceUser(t *testing.T) {
 	transaction := NewIncreaseBalanceUser(dto.IncreaseBalanceUser{
 		UserId: "user-id",
-		Value:  100.10,
+		Value:  money.New(10010),
 	})
 	assert.NotNil(t, transaction)
 	assert.NotEmpty(t, transaction.ID)
-	assert.Equal(t, 100.10, transaction.Value)
-	assert.Equal(t, "user-id", transaction.UserId)
+	assert.Equal(t, money.New(10010), transaction.Value)
+	assert.Equal(t, "user-id", transaction.AccountId)
+
+	intoAccount := NewIncreaseBalanceUser(dto.IncreaseBalanceUser{
+		AccountId: "account-id",
+		Value:     money.New(10010),
+	})
+	assert.Equal(t, "account-id", intoAccount.AccountId)
+}
+
+func TestKindTransactionString(t *testing.T) {
+	assert.Equal(t, "TRANSFER", TRANSFER.String())
+	assert.Equal(t, "DEPOSIT", DEPOSIT.String())
+	assert.Equal(t, "WITHDRAWAL", WITHDRAWAL.String())
+	assert.Equal(t, "REVERSAL", REVERSAL.String())
+	assert.Equal(t, "FEE", FEE.String())
+	assert.Equal(t, "SWEEP", SWEEP.String())
+
+	kind, ok := ParseKindTransaction("WITHDRAWAL")
+	assert.True(t, ok)
+	assert.Equal(t, WITHDRAWAL, kind)
+
+	_, ok = ParseKindTransaction("UNKNOWN")
+	assert.False(t, ok)
+}
+
+func TestNewFee(t *testing.T) {
+	transaction := &Transaction{ID: "transaction-id", SourceId: "source-user-id", Amount: money.New(10000)}
+
+	fee := NewFee(transaction, "source-user-id", money.New(150))
+	assert.NotEmpty(t, fee.ID)
+	assert.Equal(t, "source-user-id", fee.SourceId)
+	assert.Equal(t, FeeAccountId, fee.DestinationId)
+	assert.Equal(t, money.New(150), fee.Amount)
+	assert.Equal(t, FEE, fee.Kind)
+	assert.Equal(t, &transaction.ID, fee.ParentId)
+}
+
+func TestNewInterest(t *testing.T) {
+	interest := NewInterest("user-id", money.New(27))
+	assert.NotEmpty(t, interest.ID)
+	assert.Equal(t, "user-id", interest.SourceId)
+	assert.Equal(t, InterestAccountId, interest.DestinationId)
+	assert.Equal(t, money.New(27), interest.Amount)
+	assert.Equal(t, INTEREST, interest.Kind)
+}
+
+func TestNewSweep(t *testing.T) {
+	sweep := NewSweep("user-id", "destination-user-id", money.New(2550))
+	assert.NotEmpty(t, sweep.ID)
+	assert.Equal(t, "user-id", sweep.SourceId)
+	assert.Equal(t, "destination-user-id", sweep.DestinationId)
+	assert.Equal(t, money.New(2550), sweep.Amount)
+	assert.Equal(t, SWEEP, sweep.Kind)
+}
+
+func TestNewReversal(t *testing.T) {
+	original := &Transaction{
+		ID:            "transaction-id",
+		SourceId:      "source-user-id",
+		DestinationId: "destination-user-id",
+		Amount:        money.New(10010),
+		Kind:          TRANSFER,
+		State:         BOOKED,
+	}
+
+	reversal := NewReversal(original, money.New(5000))
+	assert.NotEmpty(t, reversal.ID)
+	assert.NotEqual(t, original.ID, reversal.ID)
+	assert.Equal(t, "destination-user-id", reversal.SourceId)
+	assert.Equal(t, "source-user-id", reversal.DestinationId)
+	assert.Equal(t, money.New(5000), reversal.Amount)
+	assert.Equal(t, REVERSAL, reversal.Kind)
+	assert.Equal(t, &original.ID, reversal.OriginalId)
+}
+
+func TestIsReversible(t *testing.T) {
+	cases := map[string]struct {
+		Transaction Transaction
+		Expected    bool
+	}{
+		"booked":             {Transaction{Kind: TRANSFER, State: BOOKED}, true},
+		"partially reversed": {Transaction{Kind: DEPOSIT, State: PARTIALLY_REVERSED}, true},
+		"reversed":           {Transaction{Kind: TRANSFER, State: REVERSED}, false},
+		"failed":             {Transaction{Kind: TRANSFER, State: FAILED}, false},
+		"reversal":           {Transaction{Kind: REVERSAL, State: BOOKED}, false},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, cs.Expected, cs.Transaction.IsReversible())
+		})
+	}
 }
 
-func TestStatesTransactionString(t *testing.T) {
-	stateOpened := OPEN.String()
-	assert.Equal(t, "OPEN", stateOpened)
+func TestNewAuthorization(t *testing.T) {
+	expiresAt := time.Now().Add(time.Hour)
+	transaction := NewAuthorization(dto.CreateAuthorization{
+		SourceUserId:      "source-user-id",
+		DestinationUserId: "destination-user-id",
+		Amount:            money.New(10010),
+		ExpiresAt:         &expiresAt,
+	})
+	assert.NotEmpty(t, transaction.ID)
+	assert.Equal(t, "source-user-id", transaction.SourceId)
+	assert.Equal(t, "destination-user-id", transaction.DestinationId)
+	assert.Equal(t, money.New(10010), transaction.Amount)
+	assert.Equal(t, TRANSFER, transaction.Kind)
+	assert.Equal(t, &expiresAt, transaction.ExpiresAt)
+
+	betweenAccounts := NewAuthorization(dto.CreateAuthorization{
+		SourceAccountId:      "source-account-id",
+		DestinationAccountId: "destination-account-id",
+		Amount:               money.New(10010),
+	})
+	assert.Equal(t, "source-account-id", betweenAccounts.SourceId)
+	assert.Equal(t, "destination-account-id", betweenAccounts.DestinationId)
+
+	transaction = NewAuthorization(dto.CreateAuthorization{Amount: money.New(10010)})
+	assert.NotNil(t, transaction.ExpiresAt)
+	assert.WithinDuration(t, time.Now().Add(DefaultAuthorizationTTL), *transaction.ExpiresAt, time.Minute)
+}
 
-	stateBooked := BOOKED.String()
-	assert.Equal(t, "BOOKED", stateBooked)
+func TestIsExpired(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Second)
+	future := now.Add(time.Second)
 
-	stateFailed := FAILED.String()
-	assert.Equal(t, "FAILED", stateFailed)
+	assert.False(t, (&Transaction{}).IsExpired(now))
+	assert.True(t, (&Transaction{ExpiresAt: &past}).IsExpired(now))
+	assert.True(t, (&Transaction{ExpiresAt: &now}).IsExpired(now))
+	assert.False(t, (&Transaction{ExpiresAt: &future}).IsExpired(now))
 }
@@ -1,26 +1,53 @@
 package entity
 
 import (
-	"sync"
 	"time"
 
 	"github.com/garoque/backend-code-challenge-snapfi/internal/api/dto"
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/money"
 	"github.com/garoque/backend-code-challenge-snapfi/pkg/uuid"
 )
 
+// User is a customer of the platform. The balance fields are those of their
+// default account, which shares their ID, so the users read before accounts
+// existed keep their shape; see Account for what they mean. Only ACTIVE users
+// can send or receive money, see StatusUser.
 type User struct {
-	ID        string      `json:"id"`
-	Name      string      `json:"name"`
-	Balance   float64     `json:"balance"`
-	Mutex     *sync.Mutex `json:"-"`
-	CreatedAt time.Time   `json:"createdAt" db:"created_at"`
-	UpdatedAt *time.Time  `json:"updatedAt,omitempty" db:"updated_at"`
+	ID                string      `json:"id"`
+	Name              string      `json:"name"`
+	AccountType       AccountType `json:"-" db:"account_type"`
+	AccountTypeString string      `json:"accountType,omitempty"`
+	Status            StatusUser  `json:"-" db:"status"`
+	StatusString      string      `json:"status,omitempty"`
+	Balance           money.Money `json:"balance" swaggertype:"string" example:"100.10"`
+	HeldBalance       money.Money `json:"heldBalance" db:"held_balance" swaggertype:"string" example:"0.00"`
+	AvailableBalance  money.Money `json:"availableBalance" db:"available_balance" swaggertype:"string" example:"100.10"`
+	CreditLimit       money.Money `json:"creditLimit" db:"credit_limit" swaggertype:"string" example:"500.00"`
+	UsedCredit        money.Money `json:"usedCredit" db:"-" swaggertype:"string" example:"0.00"`
+	AvailableCredit   money.Money `json:"availableCredit" db:"-" swaggertype:"string" example:"500.00"`
+	InterestRate      int64       `json:"interestRate" db:"interest_rate" example:"800"`
+	CreatedAt         time.Time   `json:"createdAt" db:"created_at"`
+	UpdatedAt         *time.Time  `json:"updatedAt,omitempty" db:"updated_at"`
 }
 
 func NewUser(user dto.CreateUser) *User {
+	accountType, _ := ParseAccountType(user.AccountType)
+
 	return &User{
-		ID:    uuid.NewId(),
-		Name:  user.Name,
-		Mutex: &sync.Mutex{},
+		ID:               uuid.NewId(),
+		Name:             user.Name,
+		AccountType:      accountType,
+		Balance:          money.New(0),
+		HeldBalance:      money.New(0),
+		AvailableBalance: money.New(0),
+		CreditLimit:      money.New(0),
 	}
 }
+
+// SetCredit fills UsedCredit, the part of the credit line taken by a negative
+// available balance, and AvailableCredit, what is left of it.
+func (u *User) SetCredit() *User {
+	u.UsedCredit, u.AvailableCredit = credit(u.AvailableBalance, u.CreditLimit)
+
+	return u
+}
@@ -4,12 +4,42 @@ import (
 	"testing"
 
 	"github.com/garoque/backend-code-challenge-snapfi/internal/api/dto"
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/money"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestNewUser(t *testing.T) {
 	user := NewUser(dto.CreateUser{Name: "Gabriel"})
 	assert.NotNil(t, user)
-	assert.NotNil(t, user.Mutex)
+	assert.NotEmpty(t, user.ID)
 	assert.Equal(t, "Gabriel", user.Name)
+	assert.Equal(t, money.New(0), user.Balance)
+	assert.Equal(t, money.New(0), user.HeldBalance)
+	assert.Equal(t, money.New(0), user.AvailableBalance)
+	assert.Equal(t, money.New(0), user.CreditLimit)
+	assert.Equal(t, PERSONAL, user.AccountType)
+
+	business := NewUser(dto.CreateUser{Name: "Loja", AccountType: "BUSINESS"})
+	assert.Equal(t, BUSINESS, business.AccountType)
+}
+
+func TestSetCredit(t *testing.T) {
+	cases := map[string]struct {
+		User              User
+		ExpectedUsed      int64
+		ExpectedAvailable int64
+	}{
+		"saldo positivo":         {User{AvailableBalance: money.New(1000), CreditLimit: money.New(500)}, 0, 500},
+		"saldo negativo":         {User{AvailableBalance: money.New(-200), CreditLimit: money.New(500)}, 200, 300},
+		"limite reduzido abaixo": {User{AvailableBalance: money.New(-700), CreditLimit: money.New(500)}, 700, 0},
+		"sem limite de credito":  {User{AvailableBalance: money.New(0)}, 0, 0},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			user := cs.User.SetCredit()
+			assert.Equal(t, money.New(cs.ExpectedUsed), user.UsedCredit)
+			assert.Equal(t, money.New(cs.ExpectedAvailable), user.AvailableCredit)
+		})
+	}
 }
@@ -0,0 +1,210 @@
+package entity
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql/driver"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/garoque/backend-code-challenge-snapfi/internal/api/dto"
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/uuid"
+)
+
+// The event types partners can subscribe webhooks to. A deposit landing is a
+// transaction.booked as well.
+const (
+	WEBHOOK_TRANSACTION_BOOKED             = "transaction.booked"
+	WEBHOOK_TRANSACTION_FAILED             = "transaction.failed"
+	WEBHOOK_TRANSACTION_REVERSED           = "transaction.reversed"
+	WEBHOOK_TRANSACTION_PARTIALLY_REVERSED = "transaction.partially_reversed"
+	WEBHOOK_DEPOSIT_BOOKED                 = "deposit.booked"
+)
+
+// WebhookEventTypes lists the types in a single column, separated by commas.
+type WebhookEventTypes []string
+
+func (t WebhookEventTypes) Value() (driver.Value, error) {
+	return strings.Join(t, ","), nil
+}
+
+func (t *WebhookEventTypes) Scan(src interface{}) error {
+	var value string
+	switch src := src.(type) {
+	case []byte:
+		value = string(src)
+	case string:
+		value = src
+	default:
+		return errors.New("webhook event types must be a string")
+	}
+
+	*t = strings.Split(value, ",")
+	return nil
+}
+
+// WebhookSubscription sends the events of EventTypes to URL, signed with
+// Secret. The secret is only shown when the subscription is created.
+type WebhookSubscription struct {
+	ID         string            `json:"id"`
+	URL        string            `json:"url"`
+	EventTypes WebhookEventTypes `json:"eventTypes" db:"event_types" swaggertype:"array,string" example:"transaction.booked"`
+	Secret     string            `json:"secret,omitempty"`
+	Active     bool              `json:"active"`
+	CreatedAt  *time.Time        `json:"createdAt" db:"created_at"`
+}
+
+// NewWebhookSubscription uses the secret of the request or, without one,
+// generates a random one.
+func NewWebhookSubscription(subscription dto.CreateWebhook) (*WebhookSubscription, error) {
+	secret := subscription.Secret
+	if secret == "" {
+		random := make([]byte, 32)
+		if _, err := rand.Read(random); err != nil {
+			return nil, err
+		}
+
+		secret = "whsec_" + hex.EncodeToString(random)
+	}
+
+	return &WebhookSubscription{
+		ID:         uuid.NewId(),
+		URL:        subscription.URL,
+		EventTypes: subscription.EventTypes,
+		Secret:     secret,
+		Active:     true,
+	}, nil
+}
+
+// WebhookTypes returns the webhook event types the domain event is sent as,
+// none when partners can't subscribe to it.
+func WebhookTypes(event Event) []string {
+	switch event.Type {
+	case WEBHOOK_TRANSACTION_FAILED, WEBHOOK_TRANSACTION_REVERSED, WEBHOOK_TRANSACTION_PARTIALLY_REVERSED:
+		return []string{event.Type}
+	case WEBHOOK_TRANSACTION_BOOKED:
+		var payload TransactionEventPayload
+		if err := json.Unmarshal(event.Payload, &payload); err == nil && payload.Transaction.KindString == DEPOSIT.String() {
+			return []string{event.Type, WEBHOOK_DEPOSIT_BOOKED}
+		}
+
+		return []string{event.Type}
+	}
+
+	return nil
+}
+
+type StatesWebhookDelivery int
+
+// The values are stored in the database, so new states must be appended.
+const (
+	DELIVERY_PENDING StatesWebhookDelivery = iota
+	DELIVERY_DELIVERED
+	DELIVERY_DEAD
+)
+
+var StatesWebhookDeliveryString = []string{
+	"PENDING", "DELIVERED", "DEAD",
+}
+
+func (st StatesWebhookDelivery) String() string {
+	return StatesWebhookDeliveryString[st]
+}
+
+// WebhookDelivery is the sending of an event to a subscription and the log of
+// its attempts. A PENDING delivery is attempted at NextAttemptAt, and it is
+// DEAD once it ran out of attempts.
+type WebhookDelivery struct {
+	ID             string                `json:"id"`
+	SubscriptionId string                `json:"subscriptionId" db:"subscription_id"`
+	EventId        string                `json:"eventId" db:"event_id"`
+	EventType      string                `json:"eventType" db:"event_type"`
+	Payload        json.RawMessage       `json:"payload" swaggertype:"object"`
+	State          StatesWebhookDelivery `json:"-" db:"state"`
+	StateString    string                `json:"state,omitempty"`
+	Attempts       int                   `json:"attempts"`
+	NextAttemptAt  *time.Time            `json:"nextAttemptAt,omitempty" db:"next_attempt_at"`
+	LastStatusCode *int                  `json:"lastStatusCode,omitempty" db:"last_status_code"`
+	LastError      *string               `json:"lastError,omitempty" db:"last_error"`
+	DeliveredAt    *time.Time            `json:"deliveredAt,omitempty" db:"delivered_at"`
+	CreatedAt      *time.Time            `json:"createdAt" db:"created_at"`
+}
+
+// WebhookBody is what is posted to the URL of a subscription.
+type WebhookBody struct {
+	ID        string          `json:"id"`
+	Type      string          `json:"type"`
+	CreatedAt time.Time       `json:"createdAt"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// NewWebhookDelivery sends the event to the subscription as eventType, right
+// away.
+func NewWebhookDelivery(subscription WebhookSubscription, event Event, eventType string, now time.Time) (*WebhookDelivery, error) {
+	payload, err := json.Marshal(WebhookBody{ID: event.ID, Type: eventType, CreatedAt: event.CreatedAt, Data: event.Payload})
+	if err != nil {
+		return nil, err
+	}
+
+	return &WebhookDelivery{
+		ID:             uuid.NewId(),
+		SubscriptionId: subscription.ID,
+		EventId:        event.ID,
+		EventType:      eventType,
+		Payload:        payload,
+		State:          DELIVERY_PENDING,
+		NextAttemptAt:  &now,
+	}, nil
+}
+
+// MaxWebhookErrorLength is as much of the cause of a failed attempt as is kept.
+const MaxWebhookErrorLength = 255
+
+// Fail records a failed attempt, scheduling the next one at retryAt or giving
+// up when it is nil.
+func (d *WebhookDelivery) Fail(statusCode *int, cause string, retryAt *time.Time) {
+	if len(cause) > MaxWebhookErrorLength {
+		cause = cause[:MaxWebhookErrorLength]
+	}
+
+	d.Attempts++
+	d.LastStatusCode = statusCode
+	d.LastError = &cause
+	d.NextAttemptAt = retryAt
+	if retryAt == nil {
+		d.State = DELIVERY_DEAD
+	}
+}
+
+func (d *WebhookDelivery) Succeed(statusCode int, now time.Time) {
+	d.Attempts++
+	d.LastStatusCode = &statusCode
+	d.LastError = nil
+	d.NextAttemptAt = nil
+	d.DeliveredAt = &now
+	d.State = DELIVERY_DELIVERED
+}
+
+// Redeliver sends the delivery again right away, with every attempt anew.
+func (d *WebhookDelivery) Redeliver(now time.Time) {
+	d.State = DELIVERY_PENDING
+	d.Attempts = 0
+	d.NextAttemptAt = &now
+}
+
+// SignWebhook signs the body sent at timestamp, in Unix seconds, as the
+// hex-encoded HMAC-SHA256 of "<timestamp>.<body>" keyed with the secret.
+// Receivers recompute it and reject old timestamps to stop replays.
+func SignWebhook(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,82 @@
+package entity
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/garoque/backend-code-challenge-snapfi/internal/api/dto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewWebhookSubscription(t *testing.T) {
+	subscription, err := NewWebhookSubscription(dto.CreateWebhook{URL: "https://partner.example.com", EventTypes: []string{WEBHOOK_TRANSACTION_BOOKED}})
+	assert.NoError(t, err)
+	assert.True(t, subscription.Active)
+	assert.Len(t, subscription.Secret, len("whsec_")+64)
+
+	subscription, err = NewWebhookSubscription(dto.CreateWebhook{URL: "https://partner.example.com", Secret: "my-very-secret-key"})
+	assert.NoError(t, err)
+	assert.Equal(t, "my-very-secret-key", subscription.Secret)
+}
+
+func TestWebhookEventTypes(t *testing.T) {
+	types := WebhookEventTypes{WEBHOOK_TRANSACTION_BOOKED, WEBHOOK_DEPOSIT_BOOKED}
+
+	value, err := types.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "transaction.booked,deposit.booked", value)
+
+	var scanned WebhookEventTypes
+	assert.NoError(t, scanned.Scan([]byte("transaction.booked,deposit.booked")))
+	assert.Equal(t, types, scanned)
+}
+
+func TestWebhookTypes(t *testing.T) {
+	deposit, _ := json.Marshal(TransactionEventPayload{Transaction: Transaction{KindString: "DEPOSIT"}})
+	transfer, _ := json.Marshal(TransactionEventPayload{Transaction: Transaction{KindString: "TRANSFER"}})
+
+	assert.Equal(t, []string{"transaction.booked", "deposit.booked"}, WebhookTypes(Event{Type: "transaction.booked", Payload: deposit}))
+	assert.Equal(t, []string{"transaction.booked"}, WebhookTypes(Event{Type: "transaction.booked", Payload: transfer}))
+	assert.Equal(t, []string{"transaction.failed"}, WebhookTypes(Event{Type: "transaction.failed", Payload: transfer}))
+	assert.Nil(t, WebhookTypes(Event{Type: "transaction.authorized", Payload: transfer}))
+	assert.Nil(t, WebhookTypes(Event{Type: EVENT_USER_CREATED}))
+}
+
+func TestWebhookDelivery(t *testing.T) {
+	now := time.Date(2023, 5, 20, 9, 0, 0, 0, time.UTC)
+	event := Event{ID: "event-id", Type: "transaction.booked", Payload: json.RawMessage(`{"to":"BOOKED"}`), CreatedAt: now}
+
+	delivery, err := NewWebhookDelivery(WebhookSubscription{ID: "subscription-id"}, event, WEBHOOK_DEPOSIT_BOOKED, now)
+	assert.NoError(t, err)
+	assert.Equal(t, DELIVERY_PENDING, delivery.State)
+	assert.Equal(t, &now, delivery.NextAttemptAt)
+	assert.JSONEq(t, `{"id":"event-id","type":"deposit.booked","createdAt":"2023-05-20T09:00:00Z","data":{"to":"BOOKED"}}`, string(delivery.Payload))
+
+	statusCode := 500
+	retryAt := now.Add(time.Minute)
+	delivery.Fail(&statusCode, "unexpected status 500", &retryAt)
+	assert.Equal(t, 1, delivery.Attempts)
+	assert.Equal(t, DELIVERY_PENDING, delivery.State)
+	assert.Equal(t, &retryAt, delivery.NextAttemptAt)
+
+	delivery.Fail(nil, "connection refused", nil)
+	assert.Equal(t, 2, delivery.Attempts)
+	assert.Equal(t, DELIVERY_DEAD, delivery.State)
+	assert.Nil(t, delivery.LastStatusCode)
+
+	delivery.Redeliver(now)
+	assert.Equal(t, DELIVERY_PENDING, delivery.State)
+	assert.Equal(t, 0, delivery.Attempts)
+
+	delivery.Succeed(204, now)
+	assert.Equal(t, DELIVERY_DELIVERED, delivery.State)
+	assert.Nil(t, delivery.LastError)
+	assert.Nil(t, delivery.NextAttemptAt)
+}
+
+func TestSignWebhook(t *testing.T) {
+	// echo -n '1684573200.{"id":"event-id"}' | openssl dgst -sha256 -hmac whsec_test
+	signature := SignWebhook("whsec_test", 1684573200, []byte(`{"id":"event-id"}`))
+	assert.Equal(t, "3db2040bdd5310c410a3a92b9d31c599e3b7020384624d88dcb711384b8d98c8", signature)
+}
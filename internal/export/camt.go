@@ -0,0 +1,202 @@
+package export
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/money"
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/uuid"
+)
+
+// CAMTNamespace is the version of the ISO 20022 bank to customer statement
+// the files follow.
+const CAMTNamespace = "urn:iso:std:iso:20022:tech:xsd:camt.053.001.02"
+
+type camtAmount struct {
+	Currency string `xml:"Ccy,attr"`
+	Value    string `xml:",chardata"`
+}
+
+type camtDate struct {
+	DateTime string `xml:"DtTm"`
+}
+
+type camtGroupHeader struct {
+	XMLName   xml.Name `xml:"GrpHdr"`
+	MessageId string   `xml:"MsgId"`
+	CreatedAt string   `xml:"CreDtTm"`
+}
+
+type camtAccount struct {
+	XMLName  xml.Name `xml:"Acct"`
+	Id       string   `xml:"Id>Othr>Id"`
+	Currency string   `xml:"Ccy"`
+	Owner    string   `xml:"Ownr>Nm"`
+}
+
+type camtBalance struct {
+	XMLName xml.Name   `xml:"Bal"`
+	Type    string     `xml:"Tp>CdOrPrtry>Cd"`
+	Amount  camtAmount `xml:"Amt"`
+	Sign    string     `xml:"CdtDbtInd"`
+	Date    camtDate   `xml:"Dt"`
+}
+
+type camtSummary struct {
+	XMLName xml.Name `xml:"TxsSummry"`
+	In      string   `xml:"TtlCdtNtries>Sum"`
+	Out     string   `xml:"TtlDbtNtries>Sum"`
+}
+
+type camtParty struct {
+	Name string `xml:"Nm"`
+}
+
+type camtEntry struct {
+	XMLName     xml.Name   `xml:"Ntry"`
+	Reference   string     `xml:"NtryRef"`
+	Amount      camtAmount `xml:"Amt"`
+	Sign        string     `xml:"CdtDbtInd"`
+	Status      string     `xml:"Sts"`
+	BookedAt    camtDate   `xml:"BookgDt"`
+	ValueAt     camtDate   `xml:"ValDt"`
+	Code        string     `xml:"BkTxCd>Prtry>Cd"`
+	EndToEndId  string     `xml:"NtryDtls>TxDtls>Refs>EndToEndId"`
+	Debtor      *camtParty `xml:"NtryDtls>TxDtls>RltdPties>Dbtr,omitempty"`
+	Creditor    *camtParty `xml:"NtryDtls>TxDtls>RltdPties>Cdtr,omitempty"`
+	Information string     `xml:"AddtlNtryInf"`
+}
+
+type camtWriter struct {
+	enc      *xml.Encoder
+	currency string
+}
+
+// NewCAMTWriter writes an ISO 20022 camt.053 bank to customer statement.
+func NewCAMTWriter(w io.Writer) Writer {
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+
+	return &camtWriter{enc: enc}
+}
+
+func (c *camtWriter) WriteHeader(statement *entity.Statement) error {
+	now := camtTime(time.Now())
+
+	tokens := []xml.Token{
+		xml.ProcInst{Target: "xml", Inst: []byte(`version="1.0" encoding="UTF-8"`)},
+		start("Document", xml.Attr{Name: xml.Name{Local: "xmlns"}, Value: CAMTNamespace}),
+		start("BkToCstmrStmt"),
+	}
+	if err := encodeTokens(c.enc, tokens...); err != nil {
+		return err
+	}
+
+	if err := c.enc.Encode(camtGroupHeader{MessageId: isoId(uuid.NewId()), CreatedAt: now}); err != nil {
+		return err
+	}
+
+	if err := encodeTokens(c.enc, start("Stmt")); err != nil {
+		return err
+	}
+
+	if err := c.enc.EncodeElement(isoId(uuid.NewId()), start("Id")); err != nil {
+		return err
+	}
+
+	if err := c.enc.EncodeElement(now, start("CreDtTm")); err != nil {
+		return err
+	}
+
+	period := struct {
+		From string `xml:"FrDtTm"`
+		To   string `xml:"ToDtTm"`
+	}{camtTime(statement.Start), camtTime(statement.End.Add(-time.Second))}
+	if err := c.enc.EncodeElement(period, start("FrToDt")); err != nil {
+		return err
+	}
+
+	c.currency = statement.Currency
+	account := camtAccount{Id: isoId(statement.AccountId), Currency: statement.Currency, Owner: truncate(statement.Name, 140)}
+	if err := c.enc.Encode(account); err != nil {
+		return err
+	}
+
+	balances := []camtBalance{
+		newCAMTBalance("OPBD", statement.OpeningBalance, statement.Currency, statement.Start),
+		newCAMTBalance("CLBD", statement.ClosingBalance, statement.Currency, statement.End.Add(-time.Second)),
+	}
+	for _, balance := range balances {
+		if err := c.enc.Encode(balance); err != nil {
+			return err
+		}
+	}
+
+	return c.enc.Encode(camtSummary{In: statement.TotalIn.String(), Out: statement.TotalOut.String()})
+}
+
+func (c *camtWriter) WriteLine(line entity.StatementLine) error {
+	amount, sign := camtSigned(line.Amount, c.currency)
+
+	counterparty := &camtParty{Name: line.CounterpartyId}
+	if line.CounterpartyName != nil {
+		counterparty.Name = truncate(*line.CounterpartyName, 140)
+	}
+
+	entry := camtEntry{
+		Reference:   isoId(line.TransactionId),
+		Amount:      amount,
+		Sign:        sign,
+		Status:      "BOOK",
+		BookedAt:    camtDate{camtTime(line.BookedAt)},
+		ValueAt:     camtDate{camtTime(line.BookedAt)},
+		Code:        line.Kind.String(),
+		EndToEndId:  isoId(line.TransactionId),
+		Information: line.TransactionId,
+	}
+
+	// Money out goes to a creditor and money in comes from a debtor.
+	if sign == "DBIT" {
+		entry.Creditor = counterparty
+	} else {
+		entry.Debtor = counterparty
+	}
+
+	return c.enc.Encode(entry)
+}
+
+func (c *camtWriter) Close() error {
+	if err := encodeTokens(c.enc, end("Stmt"), end("BkToCstmrStmt"), end("Document")); err != nil {
+		return err
+	}
+
+	return c.enc.Flush()
+}
+
+func newCAMTBalance(code string, balance money.Money, currency string, at time.Time) camtBalance {
+	amount, sign := camtSigned(balance, currency)
+
+	return camtBalance{Type: code, Amount: amount, Sign: sign, Date: camtDate{camtTime(at)}}
+}
+
+// camtSigned splits the amount into its absolute value and its CdtDbtInd.
+func camtSigned(m money.Money, currency string) (camtAmount, string) {
+	if m.IsNegative() {
+		return camtAmount{Currency: currency, Value: m.Neg().String()}, "DBIT"
+	}
+
+	return camtAmount{Currency: currency, Value: m.String()}, "CRDT"
+}
+
+func camtTime(t time.Time) string {
+	return t.Format(time.RFC3339)
+}
+
+// isoId fits a UUID in the 35 characters ISO 20022 identifiers are limited to
+// by dropping its dashes.
+func isoId(id string) string {
+	return truncate(strings.ReplaceAll(id, "-", ""), 35)
+}
@@ -0,0 +1,81 @@
+package export
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCAMTWriter(t *testing.T) {
+	var buf bytes.Buffer
+	write(t, NewCAMTWriter(&buf))
+
+	type amount struct {
+		Currency string `xml:"Ccy,attr"`
+		Value    string `xml:",chardata"`
+	}
+
+	var camt struct {
+		XMLName   xml.Name
+		Statement struct {
+			From      string `xml:"FrToDt>FrDtTm"`
+			To        string `xml:"FrToDt>ToDtTm"`
+			AccountId string `xml:"Acct>Id>Othr>Id"`
+			Owner     string `xml:"Acct>Ownr>Nm"`
+			Balances  []struct {
+				Type   string `xml:"Tp>CdOrPrtry>Cd"`
+				Amount amount `xml:"Amt"`
+				Sign   string `xml:"CdtDbtInd"`
+			} `xml:"Bal"`
+			TotalIn  string `xml:"TxsSummry>TtlCdtNtries>Sum"`
+			TotalOut string `xml:"TxsSummry>TtlDbtNtries>Sum"`
+			Entries  []struct {
+				Reference string `xml:"NtryRef"`
+				Amount    amount `xml:"Amt"`
+				Sign      string `xml:"CdtDbtInd"`
+				BookedAt  string `xml:"BookgDt>DtTm"`
+				Code      string `xml:"BkTxCd>Prtry>Cd"`
+				Debtor    string `xml:"NtryDtls>TxDtls>RltdPties>Dbtr>Nm"`
+				Creditor  string `xml:"NtryDtls>TxDtls>RltdPties>Cdtr>Nm"`
+			} `xml:"Ntry"`
+		} `xml:"BkToCstmrStmt>Stmt"`
+	}
+	assert.NoError(t, xml.Unmarshal(buf.Bytes(), &camt))
+
+	assert.Equal(t, CAMTNamespace, camt.XMLName.Space)
+	statement := camt.Statement
+	assert.Equal(t, "2023-05-01T00:00:00-03:00", statement.From)
+	assert.Equal(t, "2023-05-31T23:59:59-03:00", statement.To)
+	assert.Equal(t, "a1b2c3d4e5f67890abcdef1234567890", statement.AccountId)
+	assert.Equal(t, "Gabriel", statement.Owner)
+	assert.Len(t, statement.Balances, 2)
+	assert.Equal(t, "OPBD", statement.Balances[0].Type)
+	assert.Equal(t, amount{"BRL", "100.00"}, statement.Balances[0].Amount)
+	assert.Equal(t, "CLBD", statement.Balances[1].Type)
+	assert.Equal(t, amount{"BRL", "125.00"}, statement.Balances[1].Amount)
+	assert.Equal(t, "50.00", statement.TotalIn)
+	assert.Equal(t, "25.00", statement.TotalOut)
+
+	assert.Len(t, statement.Entries, 2)
+	assert.Equal(t, "CRDT", statement.Entries[0].Sign)
+	assert.Equal(t, "system:funding", statement.Entries[0].Debtor)
+	assert.Equal(t, "transferid", statement.Entries[1].Reference)
+	assert.Equal(t, amount{"BRL", "25.00"}, statement.Entries[1].Amount)
+	assert.Equal(t, "DBIT", statement.Entries[1].Sign)
+	assert.Equal(t, "2023-05-10T18:30:00-03:00", statement.Entries[1].BookedAt)
+	assert.Equal(t, "TRANSFER", statement.Entries[1].Code)
+	assert.Equal(t, "João & Cia", statement.Entries[1].Creditor)
+	assert.Empty(t, statement.Entries[1].Debtor)
+}
+
+func TestCAMTSigned(t *testing.T) {
+	amount, sign := camtSigned(money.New(-150), "BRL")
+	assert.Equal(t, camtAmount{Currency: "BRL", Value: "1.50"}, amount)
+	assert.Equal(t, "DBIT", sign)
+
+	_, sign = camtSigned(money.New(0), "BRL")
+	assert.Equal(t, "CRDT", sign)
+}
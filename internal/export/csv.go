@@ -0,0 +1,45 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"time"
+
+	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+)
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+// NewCSVWriter writes a row per line under a header row. The balances of the
+// period are left out so spreadsheets can import the rows as they are.
+func NewCSVWriter(w io.Writer) Writer {
+	return &csvWriter{csv.NewWriter(w)}
+}
+
+func (c *csvWriter) WriteHeader(statement *entity.Statement) error {
+	return c.w.Write([]string{"bookedAt", "transactionId", "kind", "counterpartyId", "counterpartyName", "amount", "balance"})
+}
+
+func (c *csvWriter) WriteLine(line entity.StatementLine) error {
+	counterpartyName := ""
+	if line.CounterpartyName != nil {
+		counterpartyName = *line.CounterpartyName
+	}
+
+	return c.w.Write([]string{
+		line.BookedAt.Format(time.RFC3339),
+		line.TransactionId,
+		line.Kind.String(),
+		line.CounterpartyId,
+		counterpartyName,
+		line.Amount.String(),
+		line.Balance.String(),
+	})
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
@@ -0,0 +1,17 @@
+package export
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCSVWriter(t *testing.T) {
+	var buf bytes.Buffer
+	write(t, NewCSVWriter(&buf))
+
+	assert.Equal(t, "bookedAt,transactionId,kind,counterpartyId,counterpartyName,amount,balance\n"+
+		"2023-05-02T09:00:00-03:00,deposit-id,DEPOSIT,system:funding,,50.00,150.00\n"+
+		"2023-05-10T18:30:00-03:00,transfer-id,TRANSFER,destination-id,João & Cia,-25.00,125.00\n", buf.String())
+}
@@ -0,0 +1,65 @@
+package export
+
+import (
+	"io"
+	"strings"
+
+	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+)
+
+// Writer encodes a statement as it is read, so it never has to be held in
+// memory: WriteHeader once, with the balances and totals of the whole period,
+// then WriteLine for every line in the order they were booked, then Close.
+type Writer interface {
+	WriteHeader(statement *entity.Statement) error
+	WriteLine(line entity.StatementLine) error
+	Close() error
+}
+
+// Format is a file format statements can be exported to. The first of its
+// media types is the Content-Type of the file.
+type Format struct {
+	Name       string
+	MediaTypes []string
+	Extension  string
+	NewWriter  func(w io.Writer) Writer
+}
+
+func (f *Format) ContentType() string {
+	return f.MediaTypes[0]
+}
+
+var Formats = []Format{
+	{Name: "csv", MediaTypes: []string{"text/csv"}, Extension: "csv", NewWriter: NewCSVWriter},
+	{Name: "ofx", MediaTypes: []string{"application/x-ofx"}, Extension: "ofx", NewWriter: NewOFXWriter},
+	{Name: "camt053", MediaTypes: []string{"application/vnd.iso20022.camt.053+xml", "application/xml", "text/xml"}, Extension: "xml", NewWriter: NewCAMTWriter},
+}
+
+// FormatByName returns the format named name, reporting whether it exists.
+func FormatByName(name string) (*Format, bool) {
+	for i := range Formats {
+		if Formats[i].Name == strings.ToLower(name) {
+			return &Formats[i], true
+		}
+	}
+
+	return nil, false
+}
+
+// FormatByAccept returns the format of the first media type listed in the
+// Accept header that one of the formats has, reporting whether there's one.
+// Quality values are ignored.
+func FormatByAccept(accept string) (*Format, bool) {
+	for _, mediaType := range strings.Split(accept, ",") {
+		mediaType = strings.TrimSpace(strings.SplitN(mediaType, ";", 2)[0])
+		for i := range Formats {
+			for _, candidate := range Formats[i].MediaTypes {
+				if strings.EqualFold(candidate, mediaType) {
+					return &Formats[i], true
+				}
+			}
+		}
+	}
+
+	return nil, false
+}
@@ -0,0 +1,67 @@
+package export
+
+import (
+	"testing"
+	"time"
+
+	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	"github.com/garoque/backend-code-challenge-snapfi/pkg/money"
+	"github.com/stretchr/testify/assert"
+)
+
+var brt = time.FixedZone("BRT", -3*60*60)
+
+func newStatement() (*entity.Statement, []entity.StatementLine) {
+	name := "João & Cia"
+	statement := &entity.Statement{
+		UserId:         "user-id",
+		AccountId:      "a1b2c3d4-e5f6-7890-abcd-ef1234567890",
+		Currency:       "BRL",
+		Name:           "Gabriel",
+		Start:          time.Date(2023, 5, 1, 0, 0, 0, 0, brt),
+		End:            time.Date(2023, 6, 1, 0, 0, 0, 0, brt),
+		OpeningBalance: money.New(10000),
+		TotalIn:        money.New(5000),
+		TotalOut:       money.New(2500),
+		ClosingBalance: money.New(12500),
+	}
+
+	lines := []entity.StatementLine{
+		{TransactionId: "deposit-id", Kind: entity.DEPOSIT, CounterpartyId: entity.FundingAccountId, Amount: money.New(5000), Balance: money.New(15000), BookedAt: time.Date(2023, 5, 2, 9, 0, 0, 0, brt)},
+		{TransactionId: "transfer-id", Kind: entity.TRANSFER, CounterpartyId: "destination-id", CounterpartyName: &name, Amount: money.New(-2500), Balance: money.New(12500), BookedAt: time.Date(2023, 5, 10, 18, 30, 0, 0, brt)},
+	}
+
+	return statement, lines
+}
+
+func write(t *testing.T, writer Writer) {
+	statement, lines := newStatement()
+
+	assert.NoError(t, writer.WriteHeader(statement))
+	for _, line := range lines {
+		assert.NoError(t, writer.WriteLine(line))
+	}
+	assert.NoError(t, writer.Close())
+}
+
+func TestFormatByName(t *testing.T) {
+	format, ok := FormatByName("OFX")
+	assert.True(t, ok)
+	assert.Equal(t, "application/x-ofx", format.ContentType())
+
+	_, ok = FormatByName("pdf")
+	assert.False(t, ok)
+}
+
+func TestFormatByAccept(t *testing.T) {
+	format, ok := FormatByAccept("application/json;q=0.5, text/csv; charset=utf-8")
+	assert.True(t, ok)
+	assert.Equal(t, "csv", format.Name)
+
+	format, ok = FormatByAccept("application/xml")
+	assert.True(t, ok)
+	assert.Equal(t, "camt053", format.Name)
+
+	_, ok = FormatByAccept("application/json, */*")
+	assert.False(t, ok)
+}
@@ -0,0 +1,174 @@
+package export
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+)
+
+// OFXBankId identifies the API as the bank in OFX files.
+const OFXBankId = "SNAPFI"
+
+type ofxStatus struct {
+	Code     int    `xml:"CODE"`
+	Severity string `xml:"SEVERITY"`
+}
+
+type ofxSignOn struct {
+	XMLName  xml.Name  `xml:"SIGNONMSGSRSV1"`
+	Status   ofxStatus `xml:"SONRS>STATUS"`
+	Server   string    `xml:"SONRS>DTSERVER"`
+	Language string    `xml:"SONRS>LANGUAGE"`
+}
+
+type ofxAccount struct {
+	XMLName     xml.Name `xml:"BANKACCTFROM"`
+	BankId      string   `xml:"BANKID"`
+	AccountId   string   `xml:"ACCTID"`
+	AccountType string   `xml:"ACCTTYPE"`
+}
+
+type ofxTransaction struct {
+	XMLName xml.Name `xml:"STMTTRN"`
+	Type    string   `xml:"TRNTYPE"`
+	Posted  string   `xml:"DTPOSTED"`
+	Amount  string   `xml:"TRNAMT"`
+	FitId   string   `xml:"FITID"`
+	Name    string   `xml:"NAME,omitempty"`
+	Memo    string   `xml:"MEMO"`
+}
+
+type ofxBalance struct {
+	XMLName xml.Name `xml:"LEDGERBAL"`
+	Amount  string   `xml:"BALAMT"`
+	AsOf    string   `xml:"DTASOF"`
+}
+
+type ofxWriter struct {
+	enc       *xml.Encoder
+	statement *entity.Statement
+}
+
+// NewOFXWriter writes an OFX 2.2 bank statement response.
+func NewOFXWriter(w io.Writer) Writer {
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+
+	return &ofxWriter{enc: enc}
+}
+
+func (o *ofxWriter) WriteHeader(statement *entity.Statement) error {
+	o.statement = statement
+
+	tokens := []xml.Token{
+		xml.ProcInst{Target: "xml", Inst: []byte(`version="1.0" encoding="UTF-8" standalone="no"`)},
+		xml.ProcInst{Target: "OFX", Inst: []byte(`OFXHEADER="200" VERSION="220" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"`)},
+		start("OFX"),
+	}
+	if err := encodeTokens(o.enc, tokens...); err != nil {
+		return err
+	}
+
+	err := o.enc.Encode(ofxSignOn{Status: ofxStatus{Severity: "INFO"}, Server: ofxTime(time.Now()), Language: "POR"})
+	if err != nil {
+		return err
+	}
+
+	if err := encodeTokens(o.enc, start("BANKMSGSRSV1"), start("STMTTRNRS")); err != nil {
+		return err
+	}
+
+	if err := o.enc.EncodeElement("1", start("TRNUID")); err != nil {
+		return err
+	}
+
+	if err := o.enc.EncodeElement(ofxStatus{Severity: "INFO"}, start("STATUS")); err != nil {
+		return err
+	}
+
+	if err := encodeTokens(o.enc, start("STMTRS")); err != nil {
+		return err
+	}
+
+	if err := o.enc.EncodeElement(statement.Currency, start("CURDEF")); err != nil {
+		return err
+	}
+
+	if err := o.enc.Encode(ofxAccount{BankId: OFXBankId, AccountId: statement.AccountId, AccountType: "CHECKING"}); err != nil {
+		return err
+	}
+
+	if err := encodeTokens(o.enc, start("BANKTRANLIST")); err != nil {
+		return err
+	}
+
+	if err := o.enc.EncodeElement(ofxTime(statement.Start), start("DTSTART")); err != nil {
+		return err
+	}
+
+	return o.enc.EncodeElement(ofxTime(statement.End), start("DTEND"))
+}
+
+func (o *ofxWriter) WriteLine(line entity.StatementLine) error {
+	transaction := ofxTransaction{
+		Type:   ofxTransactionType(line),
+		Posted: ofxTime(line.BookedAt),
+		Amount: line.Amount.String(),
+		FitId:  line.TransactionId,
+		Memo:   line.Kind.String(),
+	}
+
+	if line.CounterpartyName != nil {
+		transaction.Name = truncate(*line.CounterpartyName, 32)
+	}
+
+	return o.enc.Encode(transaction)
+}
+
+func (o *ofxWriter) Close() error {
+	if err := encodeTokens(o.enc, end("BANKTRANLIST")); err != nil {
+		return err
+	}
+
+	err := o.enc.Encode(ofxBalance{Amount: o.statement.ClosingBalance.String(), AsOf: ofxTime(o.statement.End)})
+	if err != nil {
+		return err
+	}
+
+	if err := encodeTokens(o.enc, end("STMTRS"), end("STMTTRNRS"), end("BANKMSGSRSV1"), end("OFX")); err != nil {
+		return err
+	}
+
+	return o.enc.Flush()
+}
+
+// ofxTransactionType maps the kind of the transaction to an OFX TRNTYPE,
+// falling back to a plain credit or debit.
+func ofxTransactionType(line entity.StatementLine) string {
+	switch line.Kind {
+	case entity.TRANSFER:
+		return "XFER"
+	case entity.DEPOSIT:
+		return "DEP"
+	case entity.FEE:
+		return "FEE"
+	case entity.INTEREST:
+		return "INT"
+	}
+
+	if line.Amount.IsNegative() {
+		return "DEBIT"
+	}
+
+	return "CREDIT"
+}
+
+// ofxTime formats t as an OFX datetime with its offset from UTC, such as
+// 20230510090000[-3:BRT].
+func ofxTime(t time.Time) string {
+	name, offset := t.Zone()
+	return fmt.Sprintf("%s[%d:%s]", t.Format("20060102150405"), offset/3600, name)
+}
@@ -0,0 +1,48 @@
+package export
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOFXWriter(t *testing.T) {
+	var buf bytes.Buffer
+	write(t, NewOFXWriter(&buf))
+
+	var ofx struct {
+		Statement struct {
+			Currency  string `xml:"CURDEF"`
+			AccountId string `xml:"BANKACCTFROM>ACCTID"`
+			Start     string `xml:"BANKTRANLIST>DTSTART"`
+			End       string `xml:"BANKTRANLIST>DTEND"`
+			Lines     []struct {
+				Type   string `xml:"TRNTYPE"`
+				Posted string `xml:"DTPOSTED"`
+				Amount string `xml:"TRNAMT"`
+				FitId  string `xml:"FITID"`
+				Name   string `xml:"NAME"`
+			} `xml:"BANKTRANLIST>STMTTRN"`
+			Balance string `xml:"LEDGERBAL>BALAMT"`
+		} `xml:"BANKMSGSRSV1>STMTTRNRS>STMTRS"`
+	}
+	assert.NoError(t, xml.Unmarshal(buf.Bytes(), &ofx))
+	assert.Contains(t, buf.String(), `<?OFX OFXHEADER="200" VERSION="220"`)
+
+	statement := ofx.Statement
+	assert.Equal(t, "BRL", statement.Currency)
+	assert.Equal(t, "a1b2c3d4-e5f6-7890-abcd-ef1234567890", statement.AccountId)
+	assert.Equal(t, "20230501000000[-3:BRT]", statement.Start)
+	assert.Equal(t, "20230601000000[-3:BRT]", statement.End)
+	assert.Equal(t, "125.00", statement.Balance)
+	assert.Len(t, statement.Lines, 2)
+	assert.Equal(t, "DEP", statement.Lines[0].Type)
+	assert.Equal(t, "50.00", statement.Lines[0].Amount)
+	assert.Equal(t, "XFER", statement.Lines[1].Type)
+	assert.Equal(t, "20230510183000[-3:BRT]", statement.Lines[1].Posted)
+	assert.Equal(t, "-25.00", statement.Lines[1].Amount)
+	assert.Equal(t, "transfer-id", statement.Lines[1].FitId)
+	assert.Equal(t, "João & Cia", statement.Lines[1].Name)
+}
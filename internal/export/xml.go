@@ -0,0 +1,32 @@
+package export
+
+import "encoding/xml"
+
+func start(name string, attrs ...xml.Attr) xml.StartElement {
+	return xml.StartElement{Name: xml.Name{Local: name}, Attr: attrs}
+}
+
+func end(name string) xml.EndElement {
+	return xml.EndElement{Name: xml.Name{Local: name}}
+}
+
+func encodeTokens(enc *xml.Encoder, tokens ...xml.Token) error {
+	for _, token := range tokens {
+		if err := enc.EncodeToken(token); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// truncate cuts s down to at most max characters, for the fields the formats
+// bound in length.
+func truncate(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+
+	return string(runes[:max])
+}
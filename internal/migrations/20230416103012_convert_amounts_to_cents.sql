@@ -0,0 +1,39 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE snapfi.users ADD COLUMN balance_cents BIGINT NOT NULL DEFAULT 0 AFTER balance;
+-- +goose StatementEnd
+-- +goose StatementBegin
+UPDATE snapfi.users SET balance_cents = ROUND(balance * 100);
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE snapfi.users DROP COLUMN balance, RENAME COLUMN balance_cents TO balance;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE snapfi.transactions ADD COLUMN amount_cents BIGINT NOT NULL DEFAULT 0 AFTER amount;
+-- +goose StatementEnd
+-- +goose StatementBegin
+UPDATE snapfi.transactions SET amount_cents = ROUND(amount * 100);
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE snapfi.transactions DROP COLUMN amount, RENAME COLUMN amount_cents TO amount;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE snapfi.users ADD COLUMN balance_decimal DECIMAL(9, 2) NOT NULL DEFAULT 0 AFTER balance;
+-- +goose StatementEnd
+-- +goose StatementBegin
+UPDATE snapfi.users SET balance_decimal = balance / 100;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE snapfi.users DROP COLUMN balance, RENAME COLUMN balance_decimal TO balance;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE snapfi.transactions ADD COLUMN amount_decimal DECIMAL(9, 2) NOT NULL DEFAULT 0 AFTER amount;
+-- +goose StatementEnd
+-- +goose StatementBegin
+UPDATE snapfi.transactions SET amount_decimal = amount / 100;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE snapfi.transactions DROP COLUMN amount, RENAME COLUMN amount_decimal TO amount;
+-- +goose StatementEnd
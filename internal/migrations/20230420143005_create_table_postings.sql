@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE snapfi.postings(
+    id BIGINT NOT NULL AUTO_INCREMENT,
+    transaction_id VARCHAR(36) NOT NULL,
+    account_id VARCHAR(36) NOT NULL,
+    amount BIGINT NOT NULL,
+    created_at datetime NOT NULL DEFAULT CURRENT_TIMESTAMP(),
+    PRIMARY KEY (id),
+    INDEX idx_postings_transaction_id (transaction_id),
+    INDEX idx_postings_account_id (account_id)
+);
+-- +goose StatementEnd
+-- +goose StatementBegin
+UPDATE snapfi.transactions SET id_source = 'system:funding' WHERE id_source = '';
+-- +goose StatementEnd
+-- +goose StatementBegin
+INSERT INTO snapfi.postings (transaction_id, account_id, amount, created_at)
+SELECT id, id_source, -amount, created_at FROM snapfi.transactions WHERE state = 1;
+-- +goose StatementEnd
+-- +goose StatementBegin
+INSERT INTO snapfi.postings (transaction_id, account_id, amount, created_at)
+SELECT id, id_destination, amount, created_at FROM snapfi.transactions WHERE state = 1;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+UPDATE snapfi.transactions SET id_source = '' WHERE id_source = 'system:funding';
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP TABLE snapfi.postings;
+-- +goose StatementEnd
@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE snapfi.transactions ADD COLUMN kind SMALLINT NOT NULL DEFAULT 0 AFTER amount;
+-- +goose StatementEnd
+-- +goose StatementBegin
+UPDATE snapfi.transactions SET kind = 1 WHERE id_source = 'system:funding';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE snapfi.transactions DROP COLUMN kind;
+-- +goose StatementEnd
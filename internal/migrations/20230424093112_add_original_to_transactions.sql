@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE snapfi.transactions ADD COLUMN id_original VARCHAR(36) NULL AFTER kind;
+-- +goose StatementEnd
+-- +goose StatementBegin
+CREATE INDEX idx_transactions_id_original ON snapfi.transactions (id_original);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX idx_transactions_id_original ON snapfi.transactions;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE snapfi.transactions DROP COLUMN id_original;
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE snapfi.transaction_state_history(
+    id BIGINT NOT NULL AUTO_INCREMENT,
+    transaction_id VARCHAR(36) NOT NULL,
+    from_state SMALLINT NOT NULL,
+    to_state SMALLINT NOT NULL,
+    reason VARCHAR(255) NOT NULL DEFAULT "",
+    created_at datetime NOT NULL DEFAULT CURRENT_TIMESTAMP(),
+    PRIMARY KEY (id),
+    INDEX idx_transaction_state_history_transaction_id (transaction_id)
+);
+-- +goose StatementEnd
+-- +goose StatementBegin
+INSERT INTO snapfi.transaction_state_history (transaction_id, from_state, to_state, reason, created_at)
+SELECT id, 0, state, 'recorded before state history', created_at FROM snapfi.transactions WHERE state <> 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE snapfi.transaction_state_history;
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE snapfi.users ADD COLUMN held_balance BIGINT NOT NULL DEFAULT 0 AFTER balance;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE snapfi.transactions ADD COLUMN expires_at datetime NULL AFTER state;
+-- +goose StatementEnd
+-- +goose StatementBegin
+CREATE INDEX idx_transactions_state_expires_at ON snapfi.transactions (state, expires_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX idx_transactions_state_expires_at ON snapfi.transactions;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE snapfi.transactions DROP COLUMN expires_at;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE snapfi.users DROP COLUMN held_balance;
+-- +goose StatementEnd
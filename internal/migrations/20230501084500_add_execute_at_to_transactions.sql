@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE snapfi.transactions ADD COLUMN execute_at datetime NULL AFTER expires_at;
+-- +goose StatementEnd
+-- +goose StatementBegin
+CREATE INDEX idx_transactions_state_execute_at ON snapfi.transactions (state, execute_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX idx_transactions_state_execute_at ON snapfi.transactions;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE snapfi.transactions DROP COLUMN execute_at;
+-- +goose StatementEnd
@@ -0,0 +1,44 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE snapfi.standing_orders(
+    id VARCHAR(36) NOT NULL UNIQUE,
+    id_source VARCHAR(36) NOT NULL,
+    id_destination VARCHAR(36) NOT NULL,
+    amount BIGINT NOT NULL,
+    frequency SMALLINT NOT NULL,
+    start_at datetime NOT NULL,
+    end_at datetime NULL,
+    count INT NULL,
+    occurrences INT NOT NULL DEFAULT 0,
+    attempts INT NOT NULL DEFAULT 0,
+    next_run_at datetime NULL,
+    state SMALLINT NOT NULL,
+    created_at datetime NOT NULL DEFAULT CURRENT_TIMESTAMP(),
+    updated_at datetime NULL ON UPDATE CURRENT_TIMESTAMP(),
+    PRIMARY KEY (id),
+    INDEX idx_standing_orders_state_next_run_at (state, next_run_at)
+);
+-- +goose StatementEnd
+-- +goose StatementBegin
+CREATE TABLE snapfi.standing_order_occurrences(
+    id BIGINT NOT NULL AUTO_INCREMENT,
+    standing_order_id VARCHAR(36) NOT NULL,
+    sequence INT NOT NULL,
+    transaction_id VARCHAR(36) NOT NULL,
+    state SMALLINT NOT NULL,
+    attempts INT NOT NULL,
+    reason VARCHAR(255) NOT NULL DEFAULT "",
+    scheduled_at datetime NOT NULL,
+    created_at datetime NOT NULL DEFAULT CURRENT_TIMESTAMP(),
+    PRIMARY KEY (id),
+    UNIQUE INDEX idx_standing_order_occurrences_sequence (standing_order_id, sequence)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE snapfi.standing_order_occurrences;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP TABLE snapfi.standing_orders;
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE snapfi.batches(
+    id VARCHAR(36) NOT NULL UNIQUE,
+    atomic BOOLEAN NOT NULL,
+    state SMALLINT NOT NULL,
+    created_at datetime NOT NULL DEFAULT CURRENT_TIMESTAMP(),
+    PRIMARY KEY (id)
+);
+-- +goose StatementEnd
+-- +goose StatementBegin
+CREATE TABLE snapfi.batch_items(
+    batch_id VARCHAR(36) NOT NULL,
+    position INT NOT NULL,
+    transaction_id VARCHAR(36) NOT NULL,
+    state SMALLINT NOT NULL,
+    error VARCHAR(255) NOT NULL DEFAULT "",
+    PRIMARY KEY (batch_id, position)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE snapfi.batch_items;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP TABLE snapfi.batches;
+-- +goose StatementEnd
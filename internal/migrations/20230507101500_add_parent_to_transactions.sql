@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE snapfi.transactions ADD COLUMN id_parent VARCHAR(36) NULL AFTER id_original;
+-- +goose StatementEnd
+-- +goose StatementBegin
+CREATE INDEX idx_transactions_id_parent ON snapfi.transactions (id_parent);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX idx_transactions_id_parent ON snapfi.transactions;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE snapfi.transactions DROP COLUMN id_parent;
+-- +goose StatementEnd
@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE snapfi.users ADD COLUMN account_type SMALLINT NOT NULL DEFAULT 0 AFTER name;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE snapfi.users DROP COLUMN account_type;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE snapfi.fee_rules(
+    id VARCHAR(36) NOT NULL UNIQUE,
+    name VARCHAR(255) NOT NULL,
+    type SMALLINT NOT NULL,
+    kind SMALLINT NULL,
+    account_type SMALLINT NULL,
+    flat_amount BIGINT NULL,
+    basis_points BIGINT NULL,
+    tiers JSON NULL,
+    min_amount BIGINT NULL,
+    max_amount BIGINT NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at datetime NOT NULL DEFAULT CURRENT_TIMESTAMP(),
+    PRIMARY KEY (id)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE snapfi.fee_rules;
+-- +goose StatementEnd
@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE snapfi.transfer_limits(
+    id_user VARCHAR(36) NOT NULL UNIQUE,
+    per_transaction BIGINT NULL,
+    night_per_transaction BIGINT NULL,
+    daily BIGINT NULL,
+    monthly BIGINT NULL,
+    updated_at datetime NOT NULL DEFAULT CURRENT_TIMESTAMP() ON UPDATE CURRENT_TIMESTAMP(),
+    PRIMARY KEY (id_user)
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+INSERT INTO snapfi.transfer_limits (id_user) VALUES ('default');
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_transactions_id_source_created_at ON snapfi.transactions (id_source, created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX idx_transactions_id_source_created_at ON snapfi.transactions;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE snapfi.transfer_limits;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE snapfi.users
+    ADD COLUMN credit_limit BIGINT NOT NULL DEFAULT 0 AFTER held_balance,
+    ADD COLUMN interest_rate BIGINT NOT NULL DEFAULT 0 AFTER credit_limit,
+    ADD COLUMN interest_accrued_on DATE NULL AFTER interest_rate;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE snapfi.users
+    DROP COLUMN credit_limit,
+    DROP COLUMN interest_rate,
+    DROP COLUMN interest_accrued_on;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE snapfi.balance_snapshots(
+    account_id VARCHAR(36) NOT NULL,
+    taken_at datetime NOT NULL,
+    balance BIGINT NOT NULL,
+    PRIMARY KEY (account_id, taken_at),
+    INDEX idx_balance_snapshots_taken_at (taken_at)
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_postings_account_id_created_at ON snapfi.postings (account_id, created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX idx_postings_account_id_created_at ON snapfi.postings;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE snapfi.balance_snapshots;
+-- +goose StatementEnd
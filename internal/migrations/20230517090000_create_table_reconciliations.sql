@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE snapfi.reconciliations(
+    id VARCHAR(36) NOT NULL UNIQUE,
+    created_at datetime NOT NULL DEFAULT CURRENT_TIMESTAMP(),
+    PRIMARY KEY (id)
+);
+-- +goose StatementEnd
+-- +goose StatementBegin
+CREATE TABLE snapfi.reconciliation_differences(
+    id BIGINT NOT NULL AUTO_INCREMENT,
+    reconciliation_id VARCHAR(36) NOT NULL,
+    user_id VARCHAR(36) NOT NULL,
+    balance BIGINT NOT NULL,
+    expected_balance BIGINT NOT NULL,
+    difference BIGINT NOT NULL,
+    approved_by VARCHAR(255) NULL,
+    corrected_at datetime NULL,
+    PRIMARY KEY (id),
+    INDEX idx_reconciliation_differences_reconciliation_id (reconciliation_id)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE snapfi.reconciliation_differences;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP TABLE snapfi.reconciliations;
+-- +goose StatementEnd
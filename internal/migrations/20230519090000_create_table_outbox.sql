@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE snapfi.outbox(
+    sequence BIGINT NOT NULL AUTO_INCREMENT,
+    id VARCHAR(36) NOT NULL UNIQUE,
+    type VARCHAR(64) NOT NULL,
+    aggregate_type VARCHAR(32) NOT NULL,
+    aggregate_id VARCHAR(36) NOT NULL,
+    payload JSON NOT NULL,
+    created_at datetime NOT NULL,
+    published_at datetime NULL,
+    PRIMARY KEY (sequence),
+    INDEX idx_outbox_published_at (published_at)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE snapfi.outbox;
+-- +goose StatementEnd
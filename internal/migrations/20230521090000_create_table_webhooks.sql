@@ -0,0 +1,41 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE snapfi.webhook_subscriptions(
+    id VARCHAR(36) NOT NULL,
+    url VARCHAR(2048) NOT NULL,
+    event_types VARCHAR(255) NOT NULL,
+    secret VARCHAR(255) NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at datetime NOT NULL DEFAULT CURRENT_TIMESTAMP(),
+    PRIMARY KEY (id)
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE snapfi.webhook_deliveries(
+    id VARCHAR(36) NOT NULL,
+    subscription_id VARCHAR(36) NOT NULL,
+    event_id VARCHAR(36) NOT NULL,
+    event_type VARCHAR(64) NOT NULL,
+    payload JSON NOT NULL,
+    state SMALLINT NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at datetime NULL,
+    last_status_code INT NULL,
+    last_error VARCHAR(255) NULL,
+    delivered_at datetime NULL,
+    created_at datetime NOT NULL DEFAULT CURRENT_TIMESTAMP(),
+    PRIMARY KEY (id),
+    UNIQUE KEY uq_webhook_deliveries_event (subscription_id, event_id, event_type),
+    INDEX idx_webhook_deliveries_due (state, next_attempt_at)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE snapfi.webhook_deliveries;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE snapfi.webhook_subscriptions;
+-- +goose StatementEnd
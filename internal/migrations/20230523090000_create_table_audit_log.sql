@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE snapfi.audit_log(
+    id VARCHAR(36) NOT NULL,
+    actor VARCHAR(255) NOT NULL,
+    request_id VARCHAR(255) NOT NULL,
+    client_ip VARCHAR(45) NOT NULL,
+    operation VARCHAR(64) NOT NULL,
+    entity_type VARCHAR(32) NOT NULL,
+    entity_id VARCHAR(36) NOT NULL,
+    `before` JSON NULL,
+    `after` JSON NULL,
+    created_at datetime NOT NULL DEFAULT CURRENT_TIMESTAMP(),
+    PRIMARY KEY (id),
+    INDEX idx_audit_log_actor (actor, created_at),
+    INDEX idx_audit_log_entity (entity_type, entity_id, created_at),
+    INDEX idx_audit_log_created_at (created_at)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE snapfi.audit_log;
+-- +goose StatementEnd
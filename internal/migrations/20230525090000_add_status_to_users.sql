@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE snapfi.users ADD COLUMN status SMALLINT NOT NULL DEFAULT 0 AFTER account_type;
+-- +goose StatementEnd
+-- +goose StatementBegin
+CREATE TABLE snapfi.user_status_history(
+    id BIGINT NOT NULL AUTO_INCREMENT,
+    user_id VARCHAR(36) NOT NULL,
+    from_status SMALLINT NOT NULL,
+    to_status SMALLINT NOT NULL,
+    reason VARCHAR(255) NOT NULL DEFAULT "",
+    created_at datetime NOT NULL DEFAULT CURRENT_TIMESTAMP(),
+    PRIMARY KEY (id),
+    INDEX idx_user_status_history_user_id (user_id)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE snapfi.user_status_history;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE snapfi.users DROP COLUMN status;
+-- +goose StatementEnd
@@ -0,0 +1,55 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE snapfi.accounts(
+    id VARCHAR(36) NOT NULL,
+    user_id VARCHAR(36) NOT NULL,
+    name VARCHAR(80) NOT NULL DEFAULT "",
+    account_type SMALLINT NOT NULL DEFAULT 0,
+    currency CHAR(3) NOT NULL DEFAULT "BRL",
+    balance BIGINT NOT NULL DEFAULT 0,
+    held_balance BIGINT NOT NULL DEFAULT 0,
+    credit_limit BIGINT NOT NULL DEFAULT 0,
+    interest_rate BIGINT NOT NULL DEFAULT 0,
+    interest_accrued_on DATE NULL,
+    created_at datetime NOT NULL DEFAULT CURRENT_TIMESTAMP(),
+    updated_at datetime DEFAULT NULL ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    INDEX idx_accounts_user_id (user_id)
+);
+-- +goose StatementEnd
+-- +goose StatementBegin
+-- The default account of a user shares their ID, so the transactions,
+-- postings and limits already stored for the user now belong to it.
+INSERT INTO snapfi.accounts (id, user_id, account_type, balance, held_balance, credit_limit, interest_rate, interest_accrued_on, created_at)
+SELECT id, id, account_type, balance, held_balance, credit_limit, interest_rate, interest_accrued_on, created_at FROM snapfi.users;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE snapfi.users
+    DROP COLUMN account_type,
+    DROP COLUMN balance,
+    DROP COLUMN held_balance,
+    DROP COLUMN credit_limit,
+    DROP COLUMN interest_rate,
+    DROP COLUMN interest_accrued_on;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE snapfi.users
+    ADD COLUMN account_type SMALLINT NOT NULL DEFAULT 0 AFTER name,
+    ADD COLUMN balance BIGINT NOT NULL DEFAULT 0 AFTER status,
+    ADD COLUMN held_balance BIGINT NOT NULL DEFAULT 0 AFTER balance,
+    ADD COLUMN credit_limit BIGINT NOT NULL DEFAULT 0 AFTER held_balance,
+    ADD COLUMN interest_rate BIGINT NOT NULL DEFAULT 0 AFTER credit_limit,
+    ADD COLUMN interest_accrued_on DATE NULL AFTER interest_rate;
+-- +goose StatementEnd
+-- +goose StatementBegin
+-- Only the default accounts fit back in the users; the other accounts are
+-- dropped along with their balances.
+UPDATE snapfi.users u JOIN snapfi.accounts a ON a.id = u.id
+SET u.account_type = a.account_type, u.balance = a.balance, u.held_balance = a.held_balance,
+    u.credit_limit = a.credit_limit, u.interest_rate = a.interest_rate, u.interest_accrued_on = a.interest_accrued_on;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP TABLE snapfi.accounts;
+-- +goose StatementEnd
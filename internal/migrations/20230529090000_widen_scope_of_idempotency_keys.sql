@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE snapfi.idempotency_keys MODIFY scope VARCHAR(255) NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE snapfi.idempotency_keys MODIFY scope VARCHAR(100) NOT NULL;
+-- +goose StatementEnd
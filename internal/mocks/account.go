@@ -0,0 +1,168 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/database/account/account.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	entity "github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	money "github.com/garoque/backend-code-challenge-snapfi/pkg/money"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockDabataseAccountInterface is a mock of DabataseAccountInterface interface.
+type MockDabataseAccountInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockDabataseAccountInterfaceMockRecorder
+}
+
+// MockDabataseAccountInterfaceMockRecorder is the mock recorder for MockDabataseAccountInterface.
+type MockDabataseAccountInterfaceMockRecorder struct {
+	mock *MockDabataseAccountInterface
+}
+
+// NewMockDabataseAccountInterface creates a new mock instance.
+func NewMockDabataseAccountInterface(ctrl *gomock.Controller) *MockDabataseAccountInterface {
+	mock := &MockDabataseAccountInterface{ctrl: ctrl}
+	mock.recorder = &MockDabataseAccountInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDabataseAccountInterface) EXPECT() *MockDabataseAccountInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockDabataseAccountInterface) Create(ctx context.Context, account entity.Account) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, account)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockDabataseAccountInterfaceMockRecorder) Create(ctx, account interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockDabataseAccountInterface)(nil).Create), ctx, account)
+}
+
+// ReadAllByUserId mocks base method.
+func (m *MockDabataseAccountInterface) ReadAllByUserId(ctx context.Context, userId string) ([]entity.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadAllByUserId", ctx, userId)
+	ret0, _ := ret[0].([]entity.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadAllByUserId indicates an expected call of ReadAllByUserId.
+func (mr *MockDabataseAccountInterfaceMockRecorder) ReadAllByUserId(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadAllByUserId", reflect.TypeOf((*MockDabataseAccountInterface)(nil).ReadAllByUserId), ctx, userId)
+}
+
+// ReadOneById mocks base method.
+func (m *MockDabataseAccountInterface) ReadOneById(ctx context.Context, id string) (*entity.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadOneById", ctx, id)
+	ret0, _ := ret[0].(*entity.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadOneById indicates an expected call of ReadOneById.
+func (mr *MockDabataseAccountInterfaceMockRecorder) ReadOneById(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadOneById", reflect.TypeOf((*MockDabataseAccountInterface)(nil).ReadOneById), ctx, id)
+}
+
+// ReadOneByIdForUpdate mocks base method.
+func (m *MockDabataseAccountInterface) ReadOneByIdForUpdate(ctx context.Context, id string) (*entity.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadOneByIdForUpdate", ctx, id)
+	ret0, _ := ret[0].(*entity.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadOneByIdForUpdate indicates an expected call of ReadOneByIdForUpdate.
+func (mr *MockDabataseAccountInterfaceMockRecorder) ReadOneByIdForUpdate(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadOneByIdForUpdate", reflect.TypeOf((*MockDabataseAccountInterface)(nil).ReadOneByIdForUpdate), ctx, id)
+}
+
+// ReadOverdrawn mocks base method.
+func (m *MockDabataseAccountInterface) ReadOverdrawn(ctx context.Context, day time.Time) ([]entity.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadOverdrawn", ctx, day)
+	ret0, _ := ret[0].([]entity.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadOverdrawn indicates an expected call of ReadOverdrawn.
+func (mr *MockDabataseAccountInterfaceMockRecorder) ReadOverdrawn(ctx, day interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadOverdrawn", reflect.TypeOf((*MockDabataseAccountInterface)(nil).ReadOverdrawn), ctx, day)
+}
+
+// UpdateBalance mocks base method.
+func (m *MockDabataseAccountInterface) UpdateBalance(ctx context.Context, id string, value money.Money) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateBalance", ctx, id, value)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateBalance indicates an expected call of UpdateBalance.
+func (mr *MockDabataseAccountInterfaceMockRecorder) UpdateBalance(ctx, id, value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBalance", reflect.TypeOf((*MockDabataseAccountInterface)(nil).UpdateBalance), ctx, id, value)
+}
+
+// UpdateCreditLine mocks base method.
+func (m *MockDabataseAccountInterface) UpdateCreditLine(ctx context.Context, id string, creditLimit money.Money, interestRate int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCreditLine", ctx, id, creditLimit, interestRate)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateCreditLine indicates an expected call of UpdateCreditLine.
+func (mr *MockDabataseAccountInterfaceMockRecorder) UpdateCreditLine(ctx, id, creditLimit, interestRate interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCreditLine", reflect.TypeOf((*MockDabataseAccountInterface)(nil).UpdateCreditLine), ctx, id, creditLimit, interestRate)
+}
+
+// UpdateHeldBalance mocks base method.
+func (m *MockDabataseAccountInterface) UpdateHeldBalance(ctx context.Context, id string, value money.Money) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateHeldBalance", ctx, id, value)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateHeldBalance indicates an expected call of UpdateHeldBalance.
+func (mr *MockDabataseAccountInterfaceMockRecorder) UpdateHeldBalance(ctx, id, value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateHeldBalance", reflect.TypeOf((*MockDabataseAccountInterface)(nil).UpdateHeldBalance), ctx, id, value)
+}
+
+// UpdateInterestAccruedOn mocks base method.
+func (m *MockDabataseAccountInterface) UpdateInterestAccruedOn(ctx context.Context, id string, day time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateInterestAccruedOn", ctx, id, day)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateInterestAccruedOn indicates an expected call of UpdateInterestAccruedOn.
+func (mr *MockDabataseAccountInterfaceMockRecorder) UpdateInterestAccruedOn(ctx, id, day interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateInterestAccruedOn", reflect.TypeOf((*MockDabataseAccountInterface)(nil).UpdateInterestAccruedOn), ctx, id, day)
+}
@@ -0,0 +1,81 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/app/account/account.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockAppAccountInterface is a mock of AppAccountInterface interface.
+type MockAppAccountInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockAppAccountInterfaceMockRecorder
+}
+
+// MockAppAccountInterfaceMockRecorder is the mock recorder for MockAppAccountInterface.
+type MockAppAccountInterfaceMockRecorder struct {
+	mock *MockAppAccountInterface
+}
+
+// NewMockAppAccountInterface creates a new mock instance.
+func NewMockAppAccountInterface(ctrl *gomock.Controller) *MockAppAccountInterface {
+	mock := &MockAppAccountInterface{ctrl: ctrl}
+	mock.recorder = &MockAppAccountInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAppAccountInterface) EXPECT() *MockAppAccountInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockAppAccountInterface) Create(ctx context.Context, account entity.Account) (*entity.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, account)
+	ret0, _ := ret[0].(*entity.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAppAccountInterfaceMockRecorder) Create(ctx, account interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAppAccountInterface)(nil).Create), ctx, account)
+}
+
+// ReadAllByUserId mocks base method.
+func (m *MockAppAccountInterface) ReadAllByUserId(ctx context.Context, userId string) ([]entity.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadAllByUserId", ctx, userId)
+	ret0, _ := ret[0].([]entity.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadAllByUserId indicates an expected call of ReadAllByUserId.
+func (mr *MockAppAccountInterfaceMockRecorder) ReadAllByUserId(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadAllByUserId", reflect.TypeOf((*MockAppAccountInterface)(nil).ReadAllByUserId), ctx, userId)
+}
+
+// ReadOneById mocks base method.
+func (m *MockAppAccountInterface) ReadOneById(ctx context.Context, id string) (*entity.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadOneById", ctx, id)
+	ret0, _ := ret[0].(*entity.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadOneById indicates an expected call of ReadOneById.
+func (mr *MockAppAccountInterfaceMockRecorder) ReadOneById(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadOneById", reflect.TypeOf((*MockAppAccountInterface)(nil).ReadOneById), ctx, id)
+}
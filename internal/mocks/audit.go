@@ -0,0 +1,65 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/database/audit/audit.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockDabataseAuditInterface is a mock of DabataseAuditInterface interface.
+type MockDabataseAuditInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockDabataseAuditInterfaceMockRecorder
+}
+
+// MockDabataseAuditInterfaceMockRecorder is the mock recorder for MockDabataseAuditInterface.
+type MockDabataseAuditInterfaceMockRecorder struct {
+	mock *MockDabataseAuditInterface
+}
+
+// NewMockDabataseAuditInterface creates a new mock instance.
+func NewMockDabataseAuditInterface(ctrl *gomock.Controller) *MockDabataseAuditInterface {
+	mock := &MockDabataseAuditInterface{ctrl: ctrl}
+	mock.recorder = &MockDabataseAuditInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDabataseAuditInterface) EXPECT() *MockDabataseAuditInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockDabataseAuditInterface) Create(ctx context.Context, entry *entity.AuditEntry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, entry)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockDabataseAuditInterfaceMockRecorder) Create(ctx, entry interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockDabataseAuditInterface)(nil).Create), ctx, entry)
+}
+
+// ReadAll mocks base method.
+func (m *MockDabataseAuditInterface) ReadAll(ctx context.Context, filter entity.AuditFilter) ([]entity.AuditEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadAll", ctx, filter)
+	ret0, _ := ret[0].([]entity.AuditEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadAll indicates an expected call of ReadAll.
+func (mr *MockDabataseAuditInterfaceMockRecorder) ReadAll(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadAll", reflect.TypeOf((*MockDabataseAuditInterface)(nil).ReadAll), ctx, filter)
+}
@@ -0,0 +1,51 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/app/audit/audit.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockAppAuditInterface is a mock of AppAuditInterface interface.
+type MockAppAuditInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockAppAuditInterfaceMockRecorder
+}
+
+// MockAppAuditInterfaceMockRecorder is the mock recorder for MockAppAuditInterface.
+type MockAppAuditInterfaceMockRecorder struct {
+	mock *MockAppAuditInterface
+}
+
+// NewMockAppAuditInterface creates a new mock instance.
+func NewMockAppAuditInterface(ctrl *gomock.Controller) *MockAppAuditInterface {
+	mock := &MockAppAuditInterface{ctrl: ctrl}
+	mock.recorder = &MockAppAuditInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAppAuditInterface) EXPECT() *MockAppAuditInterfaceMockRecorder {
+	return m.recorder
+}
+
+// ReadAll mocks base method.
+func (m *MockAppAuditInterface) ReadAll(ctx context.Context, filter entity.AuditFilter) ([]entity.AuditEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadAll", ctx, filter)
+	ret0, _ := ret[0].([]entity.AuditEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadAll indicates an expected call of ReadAll.
+func (mr *MockAppAuditInterfaceMockRecorder) ReadAll(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadAll", reflect.TypeOf((*MockAppAuditInterface)(nil).ReadAll), ctx, filter)
+}
@@ -0,0 +1,81 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/database/balance/balance.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	entity "github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockDabataseBalanceInterface is a mock of DabataseBalanceInterface interface.
+type MockDabataseBalanceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockDabataseBalanceInterfaceMockRecorder
+}
+
+// MockDabataseBalanceInterfaceMockRecorder is the mock recorder for MockDabataseBalanceInterface.
+type MockDabataseBalanceInterfaceMockRecorder struct {
+	mock *MockDabataseBalanceInterface
+}
+
+// NewMockDabataseBalanceInterface creates a new mock instance.
+func NewMockDabataseBalanceInterface(ctrl *gomock.Controller) *MockDabataseBalanceInterface {
+	mock := &MockDabataseBalanceInterface{ctrl: ctrl}
+	mock.recorder = &MockDabataseBalanceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDabataseBalanceInterface) EXPECT() *MockDabataseBalanceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// CreateSnapshots mocks base method.
+func (m *MockDabataseBalanceInterface) CreateSnapshots(ctx context.Context, previous *time.Time, takenAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSnapshots", ctx, previous, takenAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateSnapshots indicates an expected call of CreateSnapshots.
+func (mr *MockDabataseBalanceInterfaceMockRecorder) CreateSnapshots(ctx, previous, takenAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSnapshots", reflect.TypeOf((*MockDabataseBalanceInterface)(nil).CreateSnapshots), ctx, previous, takenAt)
+}
+
+// ReadLastSnapshotAt mocks base method.
+func (m *MockDabataseBalanceInterface) ReadLastSnapshotAt(ctx context.Context) (*time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadLastSnapshotAt", ctx)
+	ret0, _ := ret[0].(*time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadLastSnapshotAt indicates an expected call of ReadLastSnapshotAt.
+func (mr *MockDabataseBalanceInterfaceMockRecorder) ReadLastSnapshotAt(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadLastSnapshotAt", reflect.TypeOf((*MockDabataseBalanceInterface)(nil).ReadLastSnapshotAt), ctx)
+}
+
+// ReadLatestSnapshot mocks base method.
+func (m *MockDabataseBalanceInterface) ReadLatestSnapshot(ctx context.Context, accountId string, at time.Time) (*entity.BalanceSnapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadLatestSnapshot", ctx, accountId, at)
+	ret0, _ := ret[0].(*entity.BalanceSnapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadLatestSnapshot indicates an expected call of ReadLatestSnapshot.
+func (mr *MockDabataseBalanceInterfaceMockRecorder) ReadLatestSnapshot(ctx, accountId, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadLatestSnapshot", reflect.TypeOf((*MockDabataseBalanceInterface)(nil).ReadLatestSnapshot), ctx, accountId, at)
+}
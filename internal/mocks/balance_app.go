@@ -0,0 +1,111 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/app/balance/balance.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	entity "github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	export "github.com/garoque/backend-code-challenge-snapfi/internal/export"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockAppBalanceInterface is a mock of AppBalanceInterface interface.
+type MockAppBalanceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockAppBalanceInterfaceMockRecorder
+}
+
+// MockAppBalanceInterfaceMockRecorder is the mock recorder for MockAppBalanceInterface.
+type MockAppBalanceInterfaceMockRecorder struct {
+	mock *MockAppBalanceInterface
+}
+
+// NewMockAppBalanceInterface creates a new mock instance.
+func NewMockAppBalanceInterface(ctrl *gomock.Controller) *MockAppBalanceInterface {
+	mock := &MockAppBalanceInterface{ctrl: ctrl}
+	mock.recorder = &MockAppBalanceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAppBalanceInterface) EXPECT() *MockAppBalanceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// ExportStatement mocks base method.
+func (m *MockAppBalanceInterface) ExportStatement(ctx context.Context, userId, accountId string, start, end time.Time, writer export.Writer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportStatement", ctx, userId, accountId, start, end, writer)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportStatement indicates an expected call of ExportStatement.
+func (mr *MockAppBalanceInterfaceMockRecorder) ExportStatement(ctx, userId, accountId, start, end, writer interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportStatement", reflect.TypeOf((*MockAppBalanceInterface)(nil).ExportStatement), ctx, userId, accountId, start, end, writer)
+}
+
+// ReadAt mocks base method.
+func (m *MockAppBalanceInterface) ReadAt(ctx context.Context, userId, accountId string, at time.Time) (*entity.BalanceAt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadAt", ctx, userId, accountId, at)
+	ret0, _ := ret[0].(*entity.BalanceAt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadAt indicates an expected call of ReadAt.
+func (mr *MockAppBalanceInterfaceMockRecorder) ReadAt(ctx, userId, accountId, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadAt", reflect.TypeOf((*MockAppBalanceInterface)(nil).ReadAt), ctx, userId, accountId, at)
+}
+
+// ReadHistory mocks base method.
+func (m *MockAppBalanceInterface) ReadHistory(ctx context.Context, userId, accountId string, from, to time.Time, interval string) (*entity.BalanceHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadHistory", ctx, userId, accountId, from, to, interval)
+	ret0, _ := ret[0].(*entity.BalanceHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadHistory indicates an expected call of ReadHistory.
+func (mr *MockAppBalanceInterfaceMockRecorder) ReadHistory(ctx, userId, accountId, from, to, interval interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadHistory", reflect.TypeOf((*MockAppBalanceInterface)(nil).ReadHistory), ctx, userId, accountId, from, to, interval)
+}
+
+// ReadStatement mocks base method.
+func (m *MockAppBalanceInterface) ReadStatement(ctx context.Context, userId, accountId string, start, end time.Time) (*entity.Statement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadStatement", ctx, userId, accountId, start, end)
+	ret0, _ := ret[0].(*entity.Statement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadStatement indicates an expected call of ReadStatement.
+func (mr *MockAppBalanceInterfaceMockRecorder) ReadStatement(ctx, userId, accountId, start, end interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadStatement", reflect.TypeOf((*MockAppBalanceInterface)(nil).ReadStatement), ctx, userId, accountId, start, end)
+}
+
+// TakeSnapshots mocks base method.
+func (m *MockAppBalanceInterface) TakeSnapshots(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TakeSnapshots", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TakeSnapshots indicates an expected call of TakeSnapshots.
+func (mr *MockAppBalanceInterfaceMockRecorder) TakeSnapshots(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TakeSnapshots", reflect.TypeOf((*MockAppBalanceInterface)(nil).TakeSnapshots), ctx)
+}
@@ -0,0 +1,108 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/database/batch/batch.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockDabataseBatchInterface is a mock of DabataseBatchInterface interface.
+type MockDabataseBatchInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockDabataseBatchInterfaceMockRecorder
+}
+
+// MockDabataseBatchInterfaceMockRecorder is the mock recorder for MockDabataseBatchInterface.
+type MockDabataseBatchInterfaceMockRecorder struct {
+	mock *MockDabataseBatchInterface
+}
+
+// NewMockDabataseBatchInterface creates a new mock instance.
+func NewMockDabataseBatchInterface(ctrl *gomock.Controller) *MockDabataseBatchInterface {
+	mock := &MockDabataseBatchInterface{ctrl: ctrl}
+	mock.recorder = &MockDabataseBatchInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDabataseBatchInterface) EXPECT() *MockDabataseBatchInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockDabataseBatchInterface) Create(ctx context.Context, batch *entity.Batch) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, batch)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockDabataseBatchInterfaceMockRecorder) Create(ctx, batch interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockDabataseBatchInterface)(nil).Create), ctx, batch)
+}
+
+// CreateItems mocks base method.
+func (m *MockDabataseBatchInterface) CreateItems(ctx context.Context, items []entity.BatchItem) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateItems", ctx, items)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateItems indicates an expected call of CreateItems.
+func (mr *MockDabataseBatchInterfaceMockRecorder) CreateItems(ctx, items interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateItems", reflect.TypeOf((*MockDabataseBatchInterface)(nil).CreateItems), ctx, items)
+}
+
+// ReadItems mocks base method.
+func (m *MockDabataseBatchInterface) ReadItems(ctx context.Context, batchId string) ([]entity.BatchItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadItems", ctx, batchId)
+	ret0, _ := ret[0].([]entity.BatchItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadItems indicates an expected call of ReadItems.
+func (mr *MockDabataseBatchInterfaceMockRecorder) ReadItems(ctx, batchId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadItems", reflect.TypeOf((*MockDabataseBatchInterface)(nil).ReadItems), ctx, batchId)
+}
+
+// ReadOneById mocks base method.
+func (m *MockDabataseBatchInterface) ReadOneById(ctx context.Context, id string) (*entity.Batch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadOneById", ctx, id)
+	ret0, _ := ret[0].(*entity.Batch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadOneById indicates an expected call of ReadOneById.
+func (mr *MockDabataseBatchInterfaceMockRecorder) ReadOneById(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadOneById", reflect.TypeOf((*MockDabataseBatchInterface)(nil).ReadOneById), ctx, id)
+}
+
+// UpdateState mocks base method.
+func (m *MockDabataseBatchInterface) UpdateState(ctx context.Context, state entity.StatesBatch, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateState", ctx, state, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateState indicates an expected call of UpdateState.
+func (mr *MockDabataseBatchInterfaceMockRecorder) UpdateState(ctx, state, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateState", reflect.TypeOf((*MockDabataseBatchInterface)(nil).UpdateState), ctx, state, id)
+}
@@ -0,0 +1,109 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/database/fee/fee.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockDabataseFeeInterface is a mock of DabataseFeeInterface interface.
+type MockDabataseFeeInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockDabataseFeeInterfaceMockRecorder
+}
+
+// MockDabataseFeeInterfaceMockRecorder is the mock recorder for MockDabataseFeeInterface.
+type MockDabataseFeeInterfaceMockRecorder struct {
+	mock *MockDabataseFeeInterface
+}
+
+// NewMockDabataseFeeInterface creates a new mock instance.
+func NewMockDabataseFeeInterface(ctrl *gomock.Controller) *MockDabataseFeeInterface {
+	mock := &MockDabataseFeeInterface{ctrl: ctrl}
+	mock.recorder = &MockDabataseFeeInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDabataseFeeInterface) EXPECT() *MockDabataseFeeInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockDabataseFeeInterface) Create(ctx context.Context, rule *entity.FeeRule) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, rule)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockDabataseFeeInterfaceMockRecorder) Create(ctx, rule interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockDabataseFeeInterface)(nil).Create), ctx, rule)
+}
+
+// Deactivate mocks base method.
+func (m *MockDabataseFeeInterface) Deactivate(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Deactivate", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Deactivate indicates an expected call of Deactivate.
+func (mr *MockDabataseFeeInterfaceMockRecorder) Deactivate(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Deactivate", reflect.TypeOf((*MockDabataseFeeInterface)(nil).Deactivate), ctx, id)
+}
+
+// ReadActive mocks base method.
+func (m *MockDabataseFeeInterface) ReadActive(ctx context.Context) ([]entity.FeeRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadActive", ctx)
+	ret0, _ := ret[0].([]entity.FeeRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadActive indicates an expected call of ReadActive.
+func (mr *MockDabataseFeeInterfaceMockRecorder) ReadActive(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadActive", reflect.TypeOf((*MockDabataseFeeInterface)(nil).ReadActive), ctx)
+}
+
+// ReadAll mocks base method.
+func (m *MockDabataseFeeInterface) ReadAll(ctx context.Context) ([]entity.FeeRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadAll", ctx)
+	ret0, _ := ret[0].([]entity.FeeRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadAll indicates an expected call of ReadAll.
+func (mr *MockDabataseFeeInterfaceMockRecorder) ReadAll(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadAll", reflect.TypeOf((*MockDabataseFeeInterface)(nil).ReadAll), ctx)
+}
+
+// ReadOneById mocks base method.
+func (m *MockDabataseFeeInterface) ReadOneById(ctx context.Context, id string) (*entity.FeeRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadOneById", ctx, id)
+	ret0, _ := ret[0].(*entity.FeeRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadOneById indicates an expected call of ReadOneById.
+func (mr *MockDabataseFeeInterfaceMockRecorder) ReadOneById(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadOneById", reflect.TypeOf((*MockDabataseFeeInterface)(nil).ReadOneById), ctx, id)
+}
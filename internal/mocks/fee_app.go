@@ -0,0 +1,96 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/app/fee/fee.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockAppFeeInterface is a mock of AppFeeInterface interface.
+type MockAppFeeInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockAppFeeInterfaceMockRecorder
+}
+
+// MockAppFeeInterfaceMockRecorder is the mock recorder for MockAppFeeInterface.
+type MockAppFeeInterfaceMockRecorder struct {
+	mock *MockAppFeeInterface
+}
+
+// NewMockAppFeeInterface creates a new mock instance.
+func NewMockAppFeeInterface(ctrl *gomock.Controller) *MockAppFeeInterface {
+	mock := &MockAppFeeInterface{ctrl: ctrl}
+	mock.recorder = &MockAppFeeInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAppFeeInterface) EXPECT() *MockAppFeeInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockAppFeeInterface) Create(ctx context.Context, rule *entity.FeeRule) (*entity.FeeRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, rule)
+	ret0, _ := ret[0].(*entity.FeeRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAppFeeInterfaceMockRecorder) Create(ctx, rule interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAppFeeInterface)(nil).Create), ctx, rule)
+}
+
+// Deactivate mocks base method.
+func (m *MockAppFeeInterface) Deactivate(ctx context.Context, id string) (*entity.FeeRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Deactivate", ctx, id)
+	ret0, _ := ret[0].(*entity.FeeRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Deactivate indicates an expected call of Deactivate.
+func (mr *MockAppFeeInterfaceMockRecorder) Deactivate(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Deactivate", reflect.TypeOf((*MockAppFeeInterface)(nil).Deactivate), ctx, id)
+}
+
+// ReadAll mocks base method.
+func (m *MockAppFeeInterface) ReadAll(ctx context.Context) ([]entity.FeeRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadAll", ctx)
+	ret0, _ := ret[0].([]entity.FeeRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadAll indicates an expected call of ReadAll.
+func (mr *MockAppFeeInterfaceMockRecorder) ReadAll(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadAll", reflect.TypeOf((*MockAppFeeInterface)(nil).ReadAll), ctx)
+}
+
+// ReadOneById mocks base method.
+func (m *MockAppFeeInterface) ReadOneById(ctx context.Context, id string) (*entity.FeeRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadOneById", ctx, id)
+	ret0, _ := ret[0].(*entity.FeeRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadOneById indicates an expected call of ReadOneById.
+func (mr *MockAppFeeInterfaceMockRecorder) ReadOneById(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadOneById", reflect.TypeOf((*MockAppFeeInterface)(nil).ReadOneById), ctx, id)
+}
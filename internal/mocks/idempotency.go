@@ -0,0 +1,108 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/database/idempotency/idempotency.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockDabataseIdempotencyInterface is a mock of DabataseIdempotencyInterface interface.
+type MockDabataseIdempotencyInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockDabataseIdempotencyInterfaceMockRecorder
+}
+
+// MockDabataseIdempotencyInterfaceMockRecorder is the mock recorder for MockDabataseIdempotencyInterface.
+type MockDabataseIdempotencyInterfaceMockRecorder struct {
+	mock *MockDabataseIdempotencyInterface
+}
+
+// NewMockDabataseIdempotencyInterface creates a new mock instance.
+func NewMockDabataseIdempotencyInterface(ctrl *gomock.Controller) *MockDabataseIdempotencyInterface {
+	mock := &MockDabataseIdempotencyInterface{ctrl: ctrl}
+	mock.recorder = &MockDabataseIdempotencyInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDabataseIdempotencyInterface) EXPECT() *MockDabataseIdempotencyInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockDabataseIdempotencyInterface) Create(ctx context.Context, key *entity.IdempotencyKey) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockDabataseIdempotencyInterfaceMockRecorder) Create(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockDabataseIdempotencyInterface)(nil).Create), ctx, key)
+}
+
+// Delete mocks base method.
+func (m *MockDabataseIdempotencyInterface) Delete(ctx context.Context, scope, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, scope, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockDabataseIdempotencyInterfaceMockRecorder) Delete(ctx, scope, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockDabataseIdempotencyInterface)(nil).Delete), ctx, scope, key)
+}
+
+// ReadOne mocks base method.
+func (m *MockDabataseIdempotencyInterface) ReadOne(ctx context.Context, scope, key string) (*entity.IdempotencyKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadOne", ctx, scope, key)
+	ret0, _ := ret[0].(*entity.IdempotencyKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadOne indicates an expected call of ReadOne.
+func (mr *MockDabataseIdempotencyInterfaceMockRecorder) ReadOne(ctx, scope, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadOne", reflect.TypeOf((*MockDabataseIdempotencyInterface)(nil).ReadOne), ctx, scope, key)
+}
+
+// ReadOneForUpdate mocks base method.
+func (m *MockDabataseIdempotencyInterface) ReadOneForUpdate(ctx context.Context, scope, key string) (*entity.IdempotencyKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadOneForUpdate", ctx, scope, key)
+	ret0, _ := ret[0].(*entity.IdempotencyKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadOneForUpdate indicates an expected call of ReadOneForUpdate.
+func (mr *MockDabataseIdempotencyInterfaceMockRecorder) ReadOneForUpdate(ctx, scope, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadOneForUpdate", reflect.TypeOf((*MockDabataseIdempotencyInterface)(nil).ReadOneForUpdate), ctx, scope, key)
+}
+
+// UpdateResponse mocks base method.
+func (m *MockDabataseIdempotencyInterface) UpdateResponse(ctx context.Context, scope, key string, statusCode int, body []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateResponse", ctx, scope, key, statusCode, body)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateResponse indicates an expected call of UpdateResponse.
+func (mr *MockDabataseIdempotencyInterfaceMockRecorder) UpdateResponse(ctx, scope, key, statusCode, body interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateResponse", reflect.TypeOf((*MockDabataseIdempotencyInterface)(nil).UpdateResponse), ctx, scope, key, statusCode, body)
+}
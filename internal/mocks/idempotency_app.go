@@ -0,0 +1,79 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/app/idempotency/idempotency.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockAppIdempotencyInterface is a mock of AppIdempotencyInterface interface.
+type MockAppIdempotencyInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockAppIdempotencyInterfaceMockRecorder
+}
+
+// MockAppIdempotencyInterfaceMockRecorder is the mock recorder for MockAppIdempotencyInterface.
+type MockAppIdempotencyInterfaceMockRecorder struct {
+	mock *MockAppIdempotencyInterface
+}
+
+// NewMockAppIdempotencyInterface creates a new mock instance.
+func NewMockAppIdempotencyInterface(ctrl *gomock.Controller) *MockAppIdempotencyInterface {
+	mock := &MockAppIdempotencyInterface{ctrl: ctrl}
+	mock.recorder = &MockAppIdempotencyInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAppIdempotencyInterface) EXPECT() *MockAppIdempotencyInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Begin mocks base method.
+func (m *MockAppIdempotencyInterface) Begin(ctx context.Context, scope, key, requestHash string) (*entity.IdempotencyKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Begin", ctx, scope, key, requestHash)
+	ret0, _ := ret[0].(*entity.IdempotencyKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Begin indicates an expected call of Begin.
+func (mr *MockAppIdempotencyInterfaceMockRecorder) Begin(ctx, scope, key, requestHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Begin", reflect.TypeOf((*MockAppIdempotencyInterface)(nil).Begin), ctx, scope, key, requestHash)
+}
+
+// Complete mocks base method.
+func (m *MockAppIdempotencyInterface) Complete(ctx context.Context, scope, key string, statusCode int, body []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Complete", ctx, scope, key, statusCode, body)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Complete indicates an expected call of Complete.
+func (mr *MockAppIdempotencyInterfaceMockRecorder) Complete(ctx, scope, key, statusCode, body interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Complete", reflect.TypeOf((*MockAppIdempotencyInterface)(nil).Complete), ctx, scope, key, statusCode, body)
+}
+
+// Release mocks base method.
+func (m *MockAppIdempotencyInterface) Release(ctx context.Context, scope, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release", ctx, scope, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockAppIdempotencyInterfaceMockRecorder) Release(ctx, scope, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockAppIdempotencyInterface)(nil).Release), ctx, scope, key)
+}
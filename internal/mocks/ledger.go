@@ -0,0 +1,143 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/database/ledger/ledger.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	entity "github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	money "github.com/garoque/backend-code-challenge-snapfi/pkg/money"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockDabataseLedgerInterface is a mock of DabataseLedgerInterface interface.
+type MockDabataseLedgerInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockDabataseLedgerInterfaceMockRecorder
+}
+
+// MockDabataseLedgerInterfaceMockRecorder is the mock recorder for MockDabataseLedgerInterface.
+type MockDabataseLedgerInterfaceMockRecorder struct {
+	mock *MockDabataseLedgerInterface
+}
+
+// NewMockDabataseLedgerInterface creates a new mock instance.
+func NewMockDabataseLedgerInterface(ctrl *gomock.Controller) *MockDabataseLedgerInterface {
+	mock := &MockDabataseLedgerInterface{ctrl: ctrl}
+	mock.recorder = &MockDabataseLedgerInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDabataseLedgerInterface) EXPECT() *MockDabataseLedgerInterfaceMockRecorder {
+	return m.recorder
+}
+
+// CreatePostings mocks base method.
+func (m *MockDabataseLedgerInterface) CreatePostings(ctx context.Context, postings []entity.Posting) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePostings", ctx, postings)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreatePostings indicates an expected call of CreatePostings.
+func (mr *MockDabataseLedgerInterfaceMockRecorder) CreatePostings(ctx, postings interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePostings", reflect.TypeOf((*MockDabataseLedgerInterface)(nil).CreatePostings), ctx, postings)
+}
+
+// ReadAccountBalance mocks base method.
+func (m *MockDabataseLedgerInterface) ReadAccountBalance(ctx context.Context, accountId string, since *time.Time, until time.Time) (money.Money, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadAccountBalance", ctx, accountId, since, until)
+	ret0, _ := ret[0].(money.Money)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadAccountBalance indicates an expected call of ReadAccountBalance.
+func (mr *MockDabataseLedgerInterfaceMockRecorder) ReadAccountBalance(ctx, accountId, since, until interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadAccountBalance", reflect.TypeOf((*MockDabataseLedgerInterface)(nil).ReadAccountBalance), ctx, accountId, since, until)
+}
+
+// ReadAccountPostings mocks base method.
+func (m *MockDabataseLedgerInterface) ReadAccountPostings(ctx context.Context, accountId string, since, until time.Time) ([]entity.Posting, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadAccountPostings", ctx, accountId, since, until)
+	ret0, _ := ret[0].([]entity.Posting)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadAccountPostings indicates an expected call of ReadAccountPostings.
+func (mr *MockDabataseLedgerInterfaceMockRecorder) ReadAccountPostings(ctx, accountId, since, until interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadAccountPostings", reflect.TypeOf((*MockDabataseLedgerInterface)(nil).ReadAccountPostings), ctx, accountId, since, until)
+}
+
+// ReadAccountTotals mocks base method.
+func (m *MockDabataseLedgerInterface) ReadAccountTotals(ctx context.Context, accountId string, since, until time.Time) (money.Money, money.Money, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadAccountTotals", ctx, accountId, since, until)
+	ret0, _ := ret[0].(money.Money)
+	ret1, _ := ret[1].(money.Money)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ReadAccountTotals indicates an expected call of ReadAccountTotals.
+func (mr *MockDabataseLedgerInterfaceMockRecorder) ReadAccountTotals(ctx, accountId, since, until interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadAccountTotals", reflect.TypeOf((*MockDabataseLedgerInterface)(nil).ReadAccountTotals), ctx, accountId, since, until)
+}
+
+// ReadBalanceMismatches mocks base method.
+func (m *MockDabataseLedgerInterface) ReadBalanceMismatches(ctx context.Context) ([]entity.BalanceMismatch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadBalanceMismatches", ctx)
+	ret0, _ := ret[0].([]entity.BalanceMismatch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadBalanceMismatches indicates an expected call of ReadBalanceMismatches.
+func (mr *MockDabataseLedgerInterfaceMockRecorder) ReadBalanceMismatches(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadBalanceMismatches", reflect.TypeOf((*MockDabataseLedgerInterface)(nil).ReadBalanceMismatches), ctx)
+}
+
+// ReadTotal mocks base method.
+func (m *MockDabataseLedgerInterface) ReadTotal(ctx context.Context) (money.Money, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadTotal", ctx)
+	ret0, _ := ret[0].(money.Money)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadTotal indicates an expected call of ReadTotal.
+func (mr *MockDabataseLedgerInterfaceMockRecorder) ReadTotal(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadTotal", reflect.TypeOf((*MockDabataseLedgerInterface)(nil).ReadTotal), ctx)
+}
+
+// ReadUnbalancedTransactions mocks base method.
+func (m *MockDabataseLedgerInterface) ReadUnbalancedTransactions(ctx context.Context) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadUnbalancedTransactions", ctx)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadUnbalancedTransactions indicates an expected call of ReadUnbalancedTransactions.
+func (mr *MockDabataseLedgerInterfaceMockRecorder) ReadUnbalancedTransactions(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadUnbalancedTransactions", reflect.TypeOf((*MockDabataseLedgerInterface)(nil).ReadUnbalancedTransactions), ctx)
+}
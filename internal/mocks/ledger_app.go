@@ -0,0 +1,112 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/app/ledger/ledger.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	dto "github.com/garoque/backend-code-challenge-snapfi/internal/api/dto"
+	entity "github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockAppLedgerInterface is a mock of AppLedgerInterface interface.
+type MockAppLedgerInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockAppLedgerInterfaceMockRecorder
+}
+
+// MockAppLedgerInterfaceMockRecorder is the mock recorder for MockAppLedgerInterface.
+type MockAppLedgerInterfaceMockRecorder struct {
+	mock *MockAppLedgerInterface
+}
+
+// NewMockAppLedgerInterface creates a new mock instance.
+func NewMockAppLedgerInterface(ctrl *gomock.Controller) *MockAppLedgerInterface {
+	mock := &MockAppLedgerInterface{ctrl: ctrl}
+	mock.recorder = &MockAppLedgerInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAppLedgerInterface) EXPECT() *MockAppLedgerInterfaceMockRecorder {
+	return m.recorder
+}
+
+// ApproveCorrections mocks base method.
+func (m *MockAppLedgerInterface) ApproveCorrections(ctx context.Context, id string, approval dto.ApproveCorrections) (*entity.Reconciliation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApproveCorrections", ctx, id, approval)
+	ret0, _ := ret[0].(*entity.Reconciliation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApproveCorrections indicates an expected call of ApproveCorrections.
+func (mr *MockAppLedgerInterfaceMockRecorder) ApproveCorrections(ctx, id, approval interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApproveCorrections", reflect.TypeOf((*MockAppLedgerInterface)(nil).ApproveCorrections), ctx, id, approval)
+}
+
+// Check mocks base method.
+func (m *MockAppLedgerInterface) Check(ctx context.Context) (*entity.LedgerCheck, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Check", ctx)
+	ret0, _ := ret[0].(*entity.LedgerCheck)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Check indicates an expected call of Check.
+func (mr *MockAppLedgerInterfaceMockRecorder) Check(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Check", reflect.TypeOf((*MockAppLedgerInterface)(nil).Check), ctx)
+}
+
+// ReadReconciliation mocks base method.
+func (m *MockAppLedgerInterface) ReadReconciliation(ctx context.Context, id string) (*entity.Reconciliation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadReconciliation", ctx, id)
+	ret0, _ := ret[0].(*entity.Reconciliation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadReconciliation indicates an expected call of ReadReconciliation.
+func (mr *MockAppLedgerInterfaceMockRecorder) ReadReconciliation(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadReconciliation", reflect.TypeOf((*MockAppLedgerInterface)(nil).ReadReconciliation), ctx, id)
+}
+
+// ReadReconciliations mocks base method.
+func (m *MockAppLedgerInterface) ReadReconciliations(ctx context.Context) ([]entity.Reconciliation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadReconciliations", ctx)
+	ret0, _ := ret[0].([]entity.Reconciliation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadReconciliations indicates an expected call of ReadReconciliations.
+func (mr *MockAppLedgerInterfaceMockRecorder) ReadReconciliations(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadReconciliations", reflect.TypeOf((*MockAppLedgerInterface)(nil).ReadReconciliations), ctx)
+}
+
+// Reconcile mocks base method.
+func (m *MockAppLedgerInterface) Reconcile(ctx context.Context) (*entity.Reconciliation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reconcile", ctx)
+	ret0, _ := ret[0].(*entity.Reconciliation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reconcile indicates an expected call of Reconcile.
+func (mr *MockAppLedgerInterfaceMockRecorder) Reconcile(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reconcile", reflect.TypeOf((*MockAppLedgerInterface)(nil).Reconcile), ctx)
+}
@@ -0,0 +1,94 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/database/limit/limit.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockDabataseLimitInterface is a mock of DabataseLimitInterface interface.
+type MockDabataseLimitInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockDabataseLimitInterfaceMockRecorder
+}
+
+// MockDabataseLimitInterfaceMockRecorder is the mock recorder for MockDabataseLimitInterface.
+type MockDabataseLimitInterfaceMockRecorder struct {
+	mock *MockDabataseLimitInterface
+}
+
+// NewMockDabataseLimitInterface creates a new mock instance.
+func NewMockDabataseLimitInterface(ctrl *gomock.Controller) *MockDabataseLimitInterface {
+	mock := &MockDabataseLimitInterface{ctrl: ctrl}
+	mock.recorder = &MockDabataseLimitInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDabataseLimitInterface) EXPECT() *MockDabataseLimitInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockDabataseLimitInterface) Delete(ctx context.Context, userId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockDabataseLimitInterfaceMockRecorder) Delete(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockDabataseLimitInterface)(nil).Delete), ctx, userId)
+}
+
+// ReadOneByUserId mocks base method.
+func (m *MockDabataseLimitInterface) ReadOneByUserId(ctx context.Context, userId string) (*entity.Limits, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadOneByUserId", ctx, userId)
+	ret0, _ := ret[0].(*entity.Limits)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadOneByUserId indicates an expected call of ReadOneByUserId.
+func (mr *MockDabataseLimitInterfaceMockRecorder) ReadOneByUserId(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadOneByUserId", reflect.TypeOf((*MockDabataseLimitInterface)(nil).ReadOneByUserId), ctx, userId)
+}
+
+// ReadWithDefault mocks base method.
+func (m *MockDabataseLimitInterface) ReadWithDefault(ctx context.Context, userId string) ([]entity.Limits, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadWithDefault", ctx, userId)
+	ret0, _ := ret[0].([]entity.Limits)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadWithDefault indicates an expected call of ReadWithDefault.
+func (mr *MockDabataseLimitInterfaceMockRecorder) ReadWithDefault(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadWithDefault", reflect.TypeOf((*MockDabataseLimitInterface)(nil).ReadWithDefault), ctx, userId)
+}
+
+// Save mocks base method.
+func (m *MockDabataseLimitInterface) Save(ctx context.Context, limits *entity.Limits) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, limits)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockDabataseLimitInterfaceMockRecorder) Save(ctx, limits interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockDabataseLimitInterface)(nil).Save), ctx, limits)
+}
@@ -0,0 +1,111 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/app/limit/limit.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockAppLimitInterface is a mock of AppLimitInterface interface.
+type MockAppLimitInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockAppLimitInterfaceMockRecorder
+}
+
+// MockAppLimitInterfaceMockRecorder is the mock recorder for MockAppLimitInterface.
+type MockAppLimitInterfaceMockRecorder struct {
+	mock *MockAppLimitInterface
+}
+
+// NewMockAppLimitInterface creates a new mock instance.
+func NewMockAppLimitInterface(ctrl *gomock.Controller) *MockAppLimitInterface {
+	mock := &MockAppLimitInterface{ctrl: ctrl}
+	mock.recorder = &MockAppLimitInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAppLimitInterface) EXPECT() *MockAppLimitInterfaceMockRecorder {
+	return m.recorder
+}
+
+// DeleteUser mocks base method.
+func (m *MockAppLimitInterface) DeleteUser(ctx context.Context, userId string) (*entity.Limits, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUser", ctx, userId)
+	ret0, _ := ret[0].(*entity.Limits)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteUser indicates an expected call of DeleteUser.
+func (mr *MockAppLimitInterfaceMockRecorder) DeleteUser(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockAppLimitInterface)(nil).DeleteUser), ctx, userId)
+}
+
+// ReadByUser mocks base method.
+func (m *MockAppLimitInterface) ReadByUser(ctx context.Context, userId string) (*entity.Limits, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadByUser", ctx, userId)
+	ret0, _ := ret[0].(*entity.Limits)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadByUser indicates an expected call of ReadByUser.
+func (mr *MockAppLimitInterfaceMockRecorder) ReadByUser(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadByUser", reflect.TypeOf((*MockAppLimitInterface)(nil).ReadByUser), ctx, userId)
+}
+
+// ReadDefault mocks base method.
+func (m *MockAppLimitInterface) ReadDefault(ctx context.Context) (*entity.Limits, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadDefault", ctx)
+	ret0, _ := ret[0].(*entity.Limits)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadDefault indicates an expected call of ReadDefault.
+func (mr *MockAppLimitInterfaceMockRecorder) ReadDefault(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadDefault", reflect.TypeOf((*MockAppLimitInterface)(nil).ReadDefault), ctx)
+}
+
+// UpdateDefault mocks base method.
+func (m *MockAppLimitInterface) UpdateDefault(ctx context.Context, limits *entity.Limits) (*entity.Limits, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateDefault", ctx, limits)
+	ret0, _ := ret[0].(*entity.Limits)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateDefault indicates an expected call of UpdateDefault.
+func (mr *MockAppLimitInterfaceMockRecorder) UpdateDefault(ctx, limits interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDefault", reflect.TypeOf((*MockAppLimitInterface)(nil).UpdateDefault), ctx, limits)
+}
+
+// UpdateUser mocks base method.
+func (m *MockAppLimitInterface) UpdateUser(ctx context.Context, limits *entity.Limits) (*entity.Limits, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUser", ctx, limits)
+	ret0, _ := ret[0].(*entity.Limits)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUser indicates an expected call of UpdateUser.
+func (mr *MockAppLimitInterfaceMockRecorder) UpdateUser(ctx, limits interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUser", reflect.TypeOf((*MockAppLimitInterface)(nil).UpdateUser), ctx, limits)
+}
@@ -0,0 +1,125 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/database/outbox/outbox.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	entity "github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockDabataseOutboxInterface is a mock of DabataseOutboxInterface interface.
+type MockDabataseOutboxInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockDabataseOutboxInterfaceMockRecorder
+}
+
+// MockDabataseOutboxInterfaceMockRecorder is the mock recorder for MockDabataseOutboxInterface.
+type MockDabataseOutboxInterfaceMockRecorder struct {
+	mock *MockDabataseOutboxInterface
+}
+
+// NewMockDabataseOutboxInterface creates a new mock instance.
+func NewMockDabataseOutboxInterface(ctrl *gomock.Controller) *MockDabataseOutboxInterface {
+	mock := &MockDabataseOutboxInterface{ctrl: ctrl}
+	mock.recorder = &MockDabataseOutboxInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDabataseOutboxInterface) EXPECT() *MockDabataseOutboxInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockDabataseOutboxInterface) Create(ctx context.Context, event *entity.Event) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockDabataseOutboxInterfaceMockRecorder) Create(ctx, event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockDabataseOutboxInterface)(nil).Create), ctx, event)
+}
+
+// ReadAfter mocks base method.
+func (m *MockDabataseOutboxInterface) ReadAfter(ctx context.Context, sequence int64, limit int) ([]entity.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadAfter", ctx, sequence, limit)
+	ret0, _ := ret[0].([]entity.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadAfter indicates an expected call of ReadAfter.
+func (mr *MockDabataseOutboxInterfaceMockRecorder) ReadAfter(ctx, sequence, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadAfter", reflect.TypeOf((*MockDabataseOutboxInterface)(nil).ReadAfter), ctx, sequence, limit)
+}
+
+// ReadLastSequence mocks base method.
+func (m *MockDabataseOutboxInterface) ReadLastSequence(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadLastSequence", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadLastSequence indicates an expected call of ReadLastSequence.
+func (mr *MockDabataseOutboxInterfaceMockRecorder) ReadLastSequence(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadLastSequence", reflect.TypeOf((*MockDabataseOutboxInterface)(nil).ReadLastSequence), ctx)
+}
+
+// ReadUnpublished mocks base method.
+func (m *MockDabataseOutboxInterface) ReadUnpublished(ctx context.Context, limit int) ([]entity.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadUnpublished", ctx, limit)
+	ret0, _ := ret[0].([]entity.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadUnpublished indicates an expected call of ReadUnpublished.
+func (mr *MockDabataseOutboxInterfaceMockRecorder) ReadUnpublished(ctx, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadUnpublished", reflect.TypeOf((*MockDabataseOutboxInterface)(nil).ReadUnpublished), ctx, limit)
+}
+
+// ReadUserEventsAfter mocks base method.
+func (m *MockDabataseOutboxInterface) ReadUserEventsAfter(ctx context.Context, userId string, sequence int64, limit int) ([]entity.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadUserEventsAfter", ctx, userId, sequence, limit)
+	ret0, _ := ret[0].([]entity.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadUserEventsAfter indicates an expected call of ReadUserEventsAfter.
+func (mr *MockDabataseOutboxInterfaceMockRecorder) ReadUserEventsAfter(ctx, userId, sequence, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadUserEventsAfter", reflect.TypeOf((*MockDabataseOutboxInterface)(nil).ReadUserEventsAfter), ctx, userId, sequence, limit)
+}
+
+// UpdatePublished mocks base method.
+func (m *MockDabataseOutboxInterface) UpdatePublished(ctx context.Context, sequences []int64, publishedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePublished", ctx, sequences, publishedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePublished indicates an expected call of UpdatePublished.
+func (mr *MockDabataseOutboxInterfaceMockRecorder) UpdatePublished(ctx, sequences, publishedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePublished", reflect.TypeOf((*MockDabataseOutboxInterface)(nil).UpdatePublished), ctx, sequences, publishedAt)
+}
@@ -0,0 +1,49 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/app/outbox/outbox.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockAppOutboxInterface is a mock of AppOutboxInterface interface.
+type MockAppOutboxInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockAppOutboxInterfaceMockRecorder
+}
+
+// MockAppOutboxInterfaceMockRecorder is the mock recorder for MockAppOutboxInterface.
+type MockAppOutboxInterfaceMockRecorder struct {
+	mock *MockAppOutboxInterface
+}
+
+// NewMockAppOutboxInterface creates a new mock instance.
+func NewMockAppOutboxInterface(ctrl *gomock.Controller) *MockAppOutboxInterface {
+	mock := &MockAppOutboxInterface{ctrl: ctrl}
+	mock.recorder = &MockAppOutboxInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAppOutboxInterface) EXPECT() *MockAppOutboxInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Relay mocks base method.
+func (m *MockAppOutboxInterface) Relay(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Relay", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Relay indicates an expected call of Relay.
+func (mr *MockAppOutboxInterfaceMockRecorder) Relay(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Relay", reflect.TypeOf((*MockAppOutboxInterface)(nil).Relay), ctx)
+}
@@ -0,0 +1,141 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/database/reconciliation/reconciliation.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	entity "github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	money "github.com/garoque/backend-code-challenge-snapfi/pkg/money"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockDabataseReconciliationInterface is a mock of DabataseReconciliationInterface interface.
+type MockDabataseReconciliationInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockDabataseReconciliationInterfaceMockRecorder
+}
+
+// MockDabataseReconciliationInterfaceMockRecorder is the mock recorder for MockDabataseReconciliationInterface.
+type MockDabataseReconciliationInterfaceMockRecorder struct {
+	mock *MockDabataseReconciliationInterface
+}
+
+// NewMockDabataseReconciliationInterface creates a new mock instance.
+func NewMockDabataseReconciliationInterface(ctrl *gomock.Controller) *MockDabataseReconciliationInterface {
+	mock := &MockDabataseReconciliationInterface{ctrl: ctrl}
+	mock.recorder = &MockDabataseReconciliationInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDabataseReconciliationInterface) EXPECT() *MockDabataseReconciliationInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockDabataseReconciliationInterface) Create(ctx context.Context, reconciliation *entity.Reconciliation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, reconciliation)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockDabataseReconciliationInterfaceMockRecorder) Create(ctx, reconciliation interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockDabataseReconciliationInterface)(nil).Create), ctx, reconciliation)
+}
+
+// ReadAll mocks base method.
+func (m *MockDabataseReconciliationInterface) ReadAll(ctx context.Context) ([]entity.Reconciliation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadAll", ctx)
+	ret0, _ := ret[0].([]entity.Reconciliation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadAll indicates an expected call of ReadAll.
+func (mr *MockDabataseReconciliationInterfaceMockRecorder) ReadAll(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadAll", reflect.TypeOf((*MockDabataseReconciliationInterface)(nil).ReadAll), ctx)
+}
+
+// ReadDifferences mocks base method.
+func (m *MockDabataseReconciliationInterface) ReadDifferences(ctx context.Context, reconciliationId string) ([]entity.ReconciliationDifference, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadDifferences", ctx, reconciliationId)
+	ret0, _ := ret[0].([]entity.ReconciliationDifference)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadDifferences indicates an expected call of ReadDifferences.
+func (mr *MockDabataseReconciliationInterfaceMockRecorder) ReadDifferences(ctx, reconciliationId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadDifferences", reflect.TypeOf((*MockDabataseReconciliationInterface)(nil).ReadDifferences), ctx, reconciliationId)
+}
+
+// ReadDrifts mocks base method.
+func (m *MockDabataseReconciliationInterface) ReadDrifts(ctx context.Context) ([]entity.ReconciliationDifference, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadDrifts", ctx)
+	ret0, _ := ret[0].([]entity.ReconciliationDifference)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadDrifts indicates an expected call of ReadDrifts.
+func (mr *MockDabataseReconciliationInterfaceMockRecorder) ReadDrifts(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadDrifts", reflect.TypeOf((*MockDabataseReconciliationInterface)(nil).ReadDrifts), ctx)
+}
+
+// ReadExpectedBalance mocks base method.
+func (m *MockDabataseReconciliationInterface) ReadExpectedBalance(ctx context.Context, userId string) (money.Money, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadExpectedBalance", ctx, userId)
+	ret0, _ := ret[0].(money.Money)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadExpectedBalance indicates an expected call of ReadExpectedBalance.
+func (mr *MockDabataseReconciliationInterfaceMockRecorder) ReadExpectedBalance(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadExpectedBalance", reflect.TypeOf((*MockDabataseReconciliationInterface)(nil).ReadExpectedBalance), ctx, userId)
+}
+
+// ReadOneById mocks base method.
+func (m *MockDabataseReconciliationInterface) ReadOneById(ctx context.Context, id string) (*entity.Reconciliation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadOneById", ctx, id)
+	ret0, _ := ret[0].(*entity.Reconciliation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadOneById indicates an expected call of ReadOneById.
+func (mr *MockDabataseReconciliationInterfaceMockRecorder) ReadOneById(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadOneById", reflect.TypeOf((*MockDabataseReconciliationInterface)(nil).ReadOneById), ctx, id)
+}
+
+// UpdateCorrection mocks base method.
+func (m *MockDabataseReconciliationInterface) UpdateCorrection(ctx context.Context, id int64, approvedBy string, correctedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCorrection", ctx, id, approvedBy, correctedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateCorrection indicates an expected call of UpdateCorrection.
+func (mr *MockDabataseReconciliationInterfaceMockRecorder) UpdateCorrection(ctx, id, approvedBy, correctedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCorrection", reflect.TypeOf((*MockDabataseReconciliationInterface)(nil).UpdateCorrection), ctx, id, approvedBy, correctedAt)
+}
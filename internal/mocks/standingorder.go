@@ -0,0 +1,154 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/database/standingorder/standingorder.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	entity "github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockDabataseStandingOrderInterface is a mock of DabataseStandingOrderInterface interface.
+type MockDabataseStandingOrderInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockDabataseStandingOrderInterfaceMockRecorder
+}
+
+// MockDabataseStandingOrderInterfaceMockRecorder is the mock recorder for MockDabataseStandingOrderInterface.
+type MockDabataseStandingOrderInterfaceMockRecorder struct {
+	mock *MockDabataseStandingOrderInterface
+}
+
+// NewMockDabataseStandingOrderInterface creates a new mock instance.
+func NewMockDabataseStandingOrderInterface(ctrl *gomock.Controller) *MockDabataseStandingOrderInterface {
+	mock := &MockDabataseStandingOrderInterface{ctrl: ctrl}
+	mock.recorder = &MockDabataseStandingOrderInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDabataseStandingOrderInterface) EXPECT() *MockDabataseStandingOrderInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockDabataseStandingOrderInterface) Create(ctx context.Context, order *entity.StandingOrder) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, order)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockDabataseStandingOrderInterfaceMockRecorder) Create(ctx, order interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockDabataseStandingOrderInterface)(nil).Create), ctx, order)
+}
+
+// CreateOccurrence mocks base method.
+func (m *MockDabataseStandingOrderInterface) CreateOccurrence(ctx context.Context, occurrence *entity.StandingOrderOccurrence) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOccurrence", ctx, occurrence)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateOccurrence indicates an expected call of CreateOccurrence.
+func (mr *MockDabataseStandingOrderInterfaceMockRecorder) CreateOccurrence(ctx, occurrence interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOccurrence", reflect.TypeOf((*MockDabataseStandingOrderInterface)(nil).CreateOccurrence), ctx, occurrence)
+}
+
+// ReadAll mocks base method.
+func (m *MockDabataseStandingOrderInterface) ReadAll(ctx context.Context) ([]entity.StandingOrder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadAll", ctx)
+	ret0, _ := ret[0].([]entity.StandingOrder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadAll indicates an expected call of ReadAll.
+func (mr *MockDabataseStandingOrderInterfaceMockRecorder) ReadAll(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadAll", reflect.TypeOf((*MockDabataseStandingOrderInterface)(nil).ReadAll), ctx)
+}
+
+// ReadDue mocks base method.
+func (m *MockDabataseStandingOrderInterface) ReadDue(ctx context.Context, now time.Time) ([]entity.StandingOrder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadDue", ctx, now)
+	ret0, _ := ret[0].([]entity.StandingOrder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadDue indicates an expected call of ReadDue.
+func (mr *MockDabataseStandingOrderInterfaceMockRecorder) ReadDue(ctx, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadDue", reflect.TypeOf((*MockDabataseStandingOrderInterface)(nil).ReadDue), ctx, now)
+}
+
+// ReadOccurrences mocks base method.
+func (m *MockDabataseStandingOrderInterface) ReadOccurrences(ctx context.Context, standingOrderId string) ([]entity.StandingOrderOccurrence, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadOccurrences", ctx, standingOrderId)
+	ret0, _ := ret[0].([]entity.StandingOrderOccurrence)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadOccurrences indicates an expected call of ReadOccurrences.
+func (mr *MockDabataseStandingOrderInterfaceMockRecorder) ReadOccurrences(ctx, standingOrderId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadOccurrences", reflect.TypeOf((*MockDabataseStandingOrderInterface)(nil).ReadOccurrences), ctx, standingOrderId)
+}
+
+// ReadOneById mocks base method.
+func (m *MockDabataseStandingOrderInterface) ReadOneById(ctx context.Context, id string) (*entity.StandingOrder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadOneById", ctx, id)
+	ret0, _ := ret[0].(*entity.StandingOrder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadOneById indicates an expected call of ReadOneById.
+func (mr *MockDabataseStandingOrderInterfaceMockRecorder) ReadOneById(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadOneById", reflect.TypeOf((*MockDabataseStandingOrderInterface)(nil).ReadOneById), ctx, id)
+}
+
+// ReadOneByIdForUpdate mocks base method.
+func (m *MockDabataseStandingOrderInterface) ReadOneByIdForUpdate(ctx context.Context, id string) (*entity.StandingOrder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadOneByIdForUpdate", ctx, id)
+	ret0, _ := ret[0].(*entity.StandingOrder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadOneByIdForUpdate indicates an expected call of ReadOneByIdForUpdate.
+func (mr *MockDabataseStandingOrderInterfaceMockRecorder) ReadOneByIdForUpdate(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadOneByIdForUpdate", reflect.TypeOf((*MockDabataseStandingOrderInterface)(nil).ReadOneByIdForUpdate), ctx, id)
+}
+
+// Update mocks base method.
+func (m *MockDabataseStandingOrderInterface) Update(ctx context.Context, order *entity.StandingOrder) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, order)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockDabataseStandingOrderInterfaceMockRecorder) Update(ctx, order interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockDabataseStandingOrderInterface)(nil).Update), ctx, order)
+}
@@ -0,0 +1,140 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/app/standingorder/standingorder.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockAppStandingOrderInterface is a mock of AppStandingOrderInterface interface.
+type MockAppStandingOrderInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockAppStandingOrderInterfaceMockRecorder
+}
+
+// MockAppStandingOrderInterfaceMockRecorder is the mock recorder for MockAppStandingOrderInterface.
+type MockAppStandingOrderInterfaceMockRecorder struct {
+	mock *MockAppStandingOrderInterface
+}
+
+// NewMockAppStandingOrderInterface creates a new mock instance.
+func NewMockAppStandingOrderInterface(ctrl *gomock.Controller) *MockAppStandingOrderInterface {
+	mock := &MockAppStandingOrderInterface{ctrl: ctrl}
+	mock.recorder = &MockAppStandingOrderInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAppStandingOrderInterface) EXPECT() *MockAppStandingOrderInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Cancel mocks base method.
+func (m *MockAppStandingOrderInterface) Cancel(ctx context.Context, id string) (*entity.StandingOrder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Cancel", ctx, id)
+	ret0, _ := ret[0].(*entity.StandingOrder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Cancel indicates an expected call of Cancel.
+func (mr *MockAppStandingOrderInterfaceMockRecorder) Cancel(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cancel", reflect.TypeOf((*MockAppStandingOrderInterface)(nil).Cancel), ctx, id)
+}
+
+// Create mocks base method.
+func (m *MockAppStandingOrderInterface) Create(ctx context.Context, order *entity.StandingOrder) (*entity.StandingOrder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, order)
+	ret0, _ := ret[0].(*entity.StandingOrder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAppStandingOrderInterfaceMockRecorder) Create(ctx, order interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAppStandingOrderInterface)(nil).Create), ctx, order)
+}
+
+// Execute mocks base method.
+func (m *MockAppStandingOrderInterface) Execute(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Execute", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Execute indicates an expected call of Execute.
+func (mr *MockAppStandingOrderInterfaceMockRecorder) Execute(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Execute", reflect.TypeOf((*MockAppStandingOrderInterface)(nil).Execute), ctx)
+}
+
+// ReadAll mocks base method.
+func (m *MockAppStandingOrderInterface) ReadAll(ctx context.Context) ([]entity.StandingOrder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadAll", ctx)
+	ret0, _ := ret[0].([]entity.StandingOrder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadAll indicates an expected call of ReadAll.
+func (mr *MockAppStandingOrderInterfaceMockRecorder) ReadAll(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadAll", reflect.TypeOf((*MockAppStandingOrderInterface)(nil).ReadAll), ctx)
+}
+
+// ReadOccurrences mocks base method.
+func (m *MockAppStandingOrderInterface) ReadOccurrences(ctx context.Context, id string) ([]entity.StandingOrderOccurrence, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadOccurrences", ctx, id)
+	ret0, _ := ret[0].([]entity.StandingOrderOccurrence)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadOccurrences indicates an expected call of ReadOccurrences.
+func (mr *MockAppStandingOrderInterfaceMockRecorder) ReadOccurrences(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadOccurrences", reflect.TypeOf((*MockAppStandingOrderInterface)(nil).ReadOccurrences), ctx, id)
+}
+
+// ReadOneById mocks base method.
+func (m *MockAppStandingOrderInterface) ReadOneById(ctx context.Context, id string) (*entity.StandingOrder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadOneById", ctx, id)
+	ret0, _ := ret[0].(*entity.StandingOrder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadOneById indicates an expected call of ReadOneById.
+func (mr *MockAppStandingOrderInterfaceMockRecorder) ReadOneById(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadOneById", reflect.TypeOf((*MockAppStandingOrderInterface)(nil).ReadOneById), ctx, id)
+}
+
+// Update mocks base method.
+func (m *MockAppStandingOrderInterface) Update(ctx context.Context, id string, changes *entity.StandingOrderChanges) (*entity.StandingOrder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, id, changes)
+	ret0, _ := ret[0].(*entity.StandingOrder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockAppStandingOrderInterfaceMockRecorder) Update(ctx, id, changes interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockAppStandingOrderInterface)(nil).Update), ctx, id, changes)
+}
@@ -0,0 +1,65 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/database/statehistory/statehistory.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockDabataseStateHistoryInterface is a mock of DabataseStateHistoryInterface interface.
+type MockDabataseStateHistoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockDabataseStateHistoryInterfaceMockRecorder
+}
+
+// MockDabataseStateHistoryInterfaceMockRecorder is the mock recorder for MockDabataseStateHistoryInterface.
+type MockDabataseStateHistoryInterfaceMockRecorder struct {
+	mock *MockDabataseStateHistoryInterface
+}
+
+// NewMockDabataseStateHistoryInterface creates a new mock instance.
+func NewMockDabataseStateHistoryInterface(ctrl *gomock.Controller) *MockDabataseStateHistoryInterface {
+	mock := &MockDabataseStateHistoryInterface{ctrl: ctrl}
+	mock.recorder = &MockDabataseStateHistoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDabataseStateHistoryInterface) EXPECT() *MockDabataseStateHistoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockDabataseStateHistoryInterface) Create(ctx context.Context, transition *entity.StateTransition) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, transition)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockDabataseStateHistoryInterfaceMockRecorder) Create(ctx, transition interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockDabataseStateHistoryInterface)(nil).Create), ctx, transition)
+}
+
+// ReadByTransactionId mocks base method.
+func (m *MockDabataseStateHistoryInterface) ReadByTransactionId(ctx context.Context, transactionId string) ([]entity.StateTransition, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadByTransactionId", ctx, transactionId)
+	ret0, _ := ret[0].([]entity.StateTransition)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadByTransactionId indicates an expected call of ReadByTransactionId.
+func (mr *MockDabataseStateHistoryInterfaceMockRecorder) ReadByTransactionId(ctx, transactionId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadByTransactionId", reflect.TypeOf((*MockDabataseStateHistoryInterface)(nil).ReadByTransactionId), ctx, transactionId)
+}
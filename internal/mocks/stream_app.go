@@ -0,0 +1,64 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/app/stream/stream.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockAppStreamInterface is a mock of AppStreamInterface interface.
+type MockAppStreamInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockAppStreamInterfaceMockRecorder
+}
+
+// MockAppStreamInterfaceMockRecorder is the mock recorder for MockAppStreamInterface.
+type MockAppStreamInterfaceMockRecorder struct {
+	mock *MockAppStreamInterface
+}
+
+// NewMockAppStreamInterface creates a new mock instance.
+func NewMockAppStreamInterface(ctrl *gomock.Controller) *MockAppStreamInterface {
+	mock := &MockAppStreamInterface{ctrl: ctrl}
+	mock.recorder = &MockAppStreamInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAppStreamInterface) EXPECT() *MockAppStreamInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Poll mocks base method.
+func (m *MockAppStreamInterface) Poll(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Poll", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Poll indicates an expected call of Poll.
+func (mr *MockAppStreamInterfaceMockRecorder) Poll(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Poll", reflect.TypeOf((*MockAppStreamInterface)(nil).Poll), ctx)
+}
+
+// Stream mocks base method.
+func (m *MockAppStreamInterface) Stream(ctx context.Context, userId string, lastEventId *int64, send func(entity.StreamMessage) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stream", ctx, userId, lastEventId, send)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Stream indicates an expected call of Stream.
+func (mr *MockAppStreamInterfaceMockRecorder) Stream(ctx, userId, lastEventId, send interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stream", reflect.TypeOf((*MockAppStreamInterface)(nil).Stream), ctx, userId, lastEventId, send)
+}
@@ -7,8 +7,10 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	entity "github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	money "github.com/garoque/backend-code-challenge-snapfi/pkg/money"
 	gomock "github.com/golang/mock/gomock"
 )
 
@@ -64,33 +66,167 @@ func (mr *MockDabataseTransactionInterfaceMockRecorder) ReadAll(ctx interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadAll", reflect.TypeOf((*MockDabataseTransactionInterface)(nil).ReadAll), ctx)
 }
 
-// ReadBalance mocks base method.
-func (m *MockDabataseTransactionInterface) ReadBalance(ctx context.Context, userId string) (float64, error) {
+// ReadAllByParent mocks base method.
+func (m *MockDabataseTransactionInterface) ReadAllByParent(ctx context.Context, parentId string) ([]entity.Transaction, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ReadBalance", ctx, userId)
-	ret0, _ := ret[0].(float64)
+	ret := m.ctrl.Call(m, "ReadAllByParent", ctx, parentId)
+	ret0, _ := ret[0].([]entity.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadAllByParent indicates an expected call of ReadAllByParent.
+func (mr *MockDabataseTransactionInterfaceMockRecorder) ReadAllByParent(ctx, parentId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadAllByParent", reflect.TypeOf((*MockDabataseTransactionInterface)(nil).ReadAllByParent), ctx, parentId)
+}
+
+// ReadAllByState mocks base method.
+func (m *MockDabataseTransactionInterface) ReadAllByState(ctx context.Context, state entity.StatesTransaction) ([]entity.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadAllByState", ctx, state)
+	ret0, _ := ret[0].([]entity.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadAllByState indicates an expected call of ReadAllByState.
+func (mr *MockDabataseTransactionInterfaceMockRecorder) ReadAllByState(ctx, state interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadAllByState", reflect.TypeOf((*MockDabataseTransactionInterface)(nil).ReadAllByState), ctx, state)
+}
+
+// ReadDueScheduled mocks base method.
+func (m *MockDabataseTransactionInterface) ReadDueScheduled(ctx context.Context, now time.Time) ([]entity.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadDueScheduled", ctx, now)
+	ret0, _ := ret[0].([]entity.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadDueScheduled indicates an expected call of ReadDueScheduled.
+func (mr *MockDabataseTransactionInterfaceMockRecorder) ReadDueScheduled(ctx, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadDueScheduled", reflect.TypeOf((*MockDabataseTransactionInterface)(nil).ReadDueScheduled), ctx, now)
+}
+
+// ReadExpiredAuthorizations mocks base method.
+func (m *MockDabataseTransactionInterface) ReadExpiredAuthorizations(ctx context.Context, now time.Time) ([]entity.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadExpiredAuthorizations", ctx, now)
+	ret0, _ := ret[0].([]entity.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadExpiredAuthorizations indicates an expected call of ReadExpiredAuthorizations.
+func (mr *MockDabataseTransactionInterfaceMockRecorder) ReadExpiredAuthorizations(ctx, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadExpiredAuthorizations", reflect.TypeOf((*MockDabataseTransactionInterface)(nil).ReadExpiredAuthorizations), ctx, now)
+}
+
+// ReadOneById mocks base method.
+func (m *MockDabataseTransactionInterface) ReadOneById(ctx context.Context, id string) (*entity.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadOneById", ctx, id)
+	ret0, _ := ret[0].(*entity.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadOneById indicates an expected call of ReadOneById.
+func (mr *MockDabataseTransactionInterfaceMockRecorder) ReadOneById(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadOneById", reflect.TypeOf((*MockDabataseTransactionInterface)(nil).ReadOneById), ctx, id)
+}
+
+// ReadOneByIdForUpdate mocks base method.
+func (m *MockDabataseTransactionInterface) ReadOneByIdForUpdate(ctx context.Context, id string) (*entity.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadOneByIdForUpdate", ctx, id)
+	ret0, _ := ret[0].(*entity.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadOneByIdForUpdate indicates an expected call of ReadOneByIdForUpdate.
+func (mr *MockDabataseTransactionInterfaceMockRecorder) ReadOneByIdForUpdate(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadOneByIdForUpdate", reflect.TypeOf((*MockDabataseTransactionInterface)(nil).ReadOneByIdForUpdate), ctx, id)
+}
+
+// ReadReversedAmount mocks base method.
+func (m *MockDabataseTransactionInterface) ReadReversedAmount(ctx context.Context, originalId string) (money.Money, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadReversedAmount", ctx, originalId)
+	ret0, _ := ret[0].(money.Money)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadReversedAmount indicates an expected call of ReadReversedAmount.
+func (mr *MockDabataseTransactionInterfaceMockRecorder) ReadReversedAmount(ctx, originalId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadReversedAmount", reflect.TypeOf((*MockDabataseTransactionInterface)(nil).ReadReversedAmount), ctx, originalId)
+}
+
+// ReadSentAmount mocks base method.
+func (m *MockDabataseTransactionInterface) ReadSentAmount(ctx context.Context, userId string, since time.Time) (money.Money, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadSentAmount", ctx, userId, since)
+	ret0, _ := ret[0].(money.Money)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ReadBalance indicates an expected call of ReadBalance.
-func (mr *MockDabataseTransactionInterfaceMockRecorder) ReadBalance(ctx, userId interface{}) *gomock.Call {
+// ReadSentAmount indicates an expected call of ReadSentAmount.
+func (mr *MockDabataseTransactionInterfaceMockRecorder) ReadSentAmount(ctx, userId, since interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadSentAmount", reflect.TypeOf((*MockDabataseTransactionInterface)(nil).ReadSentAmount), ctx, userId, since)
+}
+
+// ReadStatementLines mocks base method.
+func (m *MockDabataseTransactionInterface) ReadStatementLines(ctx context.Context, accountId string, start, end time.Time) ([]entity.StatementLine, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadStatementLines", ctx, accountId, start, end)
+	ret0, _ := ret[0].([]entity.StatementLine)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadStatementLines indicates an expected call of ReadStatementLines.
+func (mr *MockDabataseTransactionInterfaceMockRecorder) ReadStatementLines(ctx, accountId, start, end interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadStatementLines", reflect.TypeOf((*MockDabataseTransactionInterface)(nil).ReadStatementLines), ctx, accountId, start, end)
+}
+
+// StreamStatementLines mocks base method.
+func (m *MockDabataseTransactionInterface) StreamStatementLines(ctx context.Context, accountId string, start, end time.Time, fn func(entity.StatementLine) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamStatementLines", ctx, accountId, start, end, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StreamStatementLines indicates an expected call of StreamStatementLines.
+func (mr *MockDabataseTransactionInterfaceMockRecorder) StreamStatementLines(ctx, accountId, start, end, fn interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadBalance", reflect.TypeOf((*MockDabataseTransactionInterface)(nil).ReadBalance), ctx, userId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamStatementLines", reflect.TypeOf((*MockDabataseTransactionInterface)(nil).StreamStatementLines), ctx, accountId, start, end, fn)
 }
 
-// UpdateBalanceUser mocks base method.
-func (m *MockDabataseTransactionInterface) UpdateBalanceUser(ctx context.Context, userId string, value float64) error {
+// UpdateAmount mocks base method.
+func (m *MockDabataseTransactionInterface) UpdateAmount(ctx context.Context, id string, amount money.Money) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateBalanceUser", ctx, userId, value)
+	ret := m.ctrl.Call(m, "UpdateAmount", ctx, id, amount)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// UpdateBalanceUser indicates an expected call of UpdateBalanceUser.
-func (mr *MockDabataseTransactionInterfaceMockRecorder) UpdateBalanceUser(ctx, userId, value interface{}) *gomock.Call {
+// UpdateAmount indicates an expected call of UpdateAmount.
+func (mr *MockDabataseTransactionInterfaceMockRecorder) UpdateAmount(ctx, id, amount interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBalanceUser", reflect.TypeOf((*MockDabataseTransactionInterface)(nil).UpdateBalanceUser), ctx, userId, value)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAmount", reflect.TypeOf((*MockDabataseTransactionInterface)(nil).UpdateAmount), ctx, id, amount)
 }
 
 // UpdateState mocks base method.
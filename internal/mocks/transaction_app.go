@@ -9,6 +9,7 @@ import (
 	reflect "reflect"
 
 	entity "github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	money "github.com/garoque/backend-code-challenge-snapfi/pkg/money"
 	gomock "github.com/golang/mock/gomock"
 )
 
@@ -35,6 +36,65 @@ func (m *MockAppTransactionInterface) EXPECT() *MockAppTransactionInterfaceMockR
 	return m.recorder
 }
 
+// AccrueInterest mocks base method.
+func (m *MockAppTransactionInterface) AccrueInterest(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AccrueInterest", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AccrueInterest indicates an expected call of AccrueInterest.
+func (mr *MockAppTransactionInterfaceMockRecorder) AccrueInterest(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccrueInterest", reflect.TypeOf((*MockAppTransactionInterface)(nil).AccrueInterest), ctx)
+}
+
+// Authorize mocks base method.
+func (m *MockAppTransactionInterface) Authorize(ctx context.Context, transaction *entity.Transaction) (*entity.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authorize", ctx, transaction)
+	ret0, _ := ret[0].(*entity.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Authorize indicates an expected call of Authorize.
+func (mr *MockAppTransactionInterfaceMockRecorder) Authorize(ctx, transaction interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authorize", reflect.TypeOf((*MockAppTransactionInterface)(nil).Authorize), ctx, transaction)
+}
+
+// CancelScheduled mocks base method.
+func (m *MockAppTransactionInterface) CancelScheduled(ctx context.Context, id string) (*entity.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelScheduled", ctx, id)
+	ret0, _ := ret[0].(*entity.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelScheduled indicates an expected call of CancelScheduled.
+func (mr *MockAppTransactionInterfaceMockRecorder) CancelScheduled(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelScheduled", reflect.TypeOf((*MockAppTransactionInterface)(nil).CancelScheduled), ctx, id)
+}
+
+// Capture mocks base method.
+func (m *MockAppTransactionInterface) Capture(ctx context.Context, id string, amount money.Money) (*entity.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Capture", ctx, id, amount)
+	ret0, _ := ret[0].(*entity.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Capture indicates an expected call of Capture.
+func (mr *MockAppTransactionInterfaceMockRecorder) Capture(ctx, id, amount interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Capture", reflect.TypeOf((*MockAppTransactionInterface)(nil).Capture), ctx, id, amount)
+}
+
 // Create mocks base method.
 func (m *MockAppTransactionInterface) Create(ctx context.Context, transaction *entity.Transaction) (*entity.Transaction, error) {
 	m.ctrl.T.Helper()
@@ -50,11 +110,69 @@ func (mr *MockAppTransactionInterfaceMockRecorder) Create(ctx, transaction inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAppTransactionInterface)(nil).Create), ctx, transaction)
 }
 
+// CreateBatch mocks base method.
+func (m *MockAppTransactionInterface) CreateBatch(ctx context.Context, batch *entity.Batch) (*entity.Batch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBatch", ctx, batch)
+	ret0, _ := ret[0].(*entity.Batch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateBatch indicates an expected call of CreateBatch.
+func (mr *MockAppTransactionInterfaceMockRecorder) CreateBatch(ctx, batch interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBatch", reflect.TypeOf((*MockAppTransactionInterface)(nil).CreateBatch), ctx, batch)
+}
+
+// CreateSplit mocks base method.
+func (m *MockAppTransactionInterface) CreateSplit(ctx context.Context, split *entity.Split) (*entity.Split, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSplit", ctx, split)
+	ret0, _ := ret[0].(*entity.Split)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSplit indicates an expected call of CreateSplit.
+func (mr *MockAppTransactionInterfaceMockRecorder) CreateSplit(ctx, split interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSplit", reflect.TypeOf((*MockAppTransactionInterface)(nil).CreateSplit), ctx, split)
+}
+
+// ExecuteScheduled mocks base method.
+func (m *MockAppTransactionInterface) ExecuteScheduled(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExecuteScheduled", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExecuteScheduled indicates an expected call of ExecuteScheduled.
+func (mr *MockAppTransactionInterfaceMockRecorder) ExecuteScheduled(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteScheduled", reflect.TypeOf((*MockAppTransactionInterface)(nil).ExecuteScheduled), ctx)
+}
+
+// ExpireAuthorizations mocks base method.
+func (m *MockAppTransactionInterface) ExpireAuthorizations(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireAuthorizations", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExpireAuthorizations indicates an expected call of ExpireAuthorizations.
+func (mr *MockAppTransactionInterfaceMockRecorder) ExpireAuthorizations(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireAuthorizations", reflect.TypeOf((*MockAppTransactionInterface)(nil).ExpireAuthorizations), ctx)
+}
+
 // IncreaseBalanceUser mocks base method.
-func (m *MockAppTransactionInterface) IncreaseBalanceUser(ctx context.Context, transaction *entity.TransactionIncreaseBalanceUser) (float64, error) {
+func (m *MockAppTransactionInterface) IncreaseBalanceUser(ctx context.Context, transaction *entity.TransactionIncreaseBalanceUser) (money.Money, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "IncreaseBalanceUser", ctx, transaction)
-	ret0, _ := ret[0].(float64)
+	ret0, _ := ret[0].(money.Money)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -79,3 +197,108 @@ func (mr *MockAppTransactionInterfaceMockRecorder) ReadAll(ctx interface{}) *gom
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadAll", reflect.TypeOf((*MockAppTransactionInterface)(nil).ReadAll), ctx)
 }
+
+// ReadBatch mocks base method.
+func (m *MockAppTransactionInterface) ReadBatch(ctx context.Context, id string) (*entity.Batch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadBatch", ctx, id)
+	ret0, _ := ret[0].(*entity.Batch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadBatch indicates an expected call of ReadBatch.
+func (mr *MockAppTransactionInterfaceMockRecorder) ReadBatch(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadBatch", reflect.TypeOf((*MockAppTransactionInterface)(nil).ReadBatch), ctx, id)
+}
+
+// ReadHistory mocks base method.
+func (m *MockAppTransactionInterface) ReadHistory(ctx context.Context, id string) ([]entity.StateTransition, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadHistory", ctx, id)
+	ret0, _ := ret[0].([]entity.StateTransition)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadHistory indicates an expected call of ReadHistory.
+func (mr *MockAppTransactionInterfaceMockRecorder) ReadHistory(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadHistory", reflect.TypeOf((*MockAppTransactionInterface)(nil).ReadHistory), ctx, id)
+}
+
+// ReadScheduled mocks base method.
+func (m *MockAppTransactionInterface) ReadScheduled(ctx context.Context) ([]entity.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadScheduled", ctx)
+	ret0, _ := ret[0].([]entity.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadScheduled indicates an expected call of ReadScheduled.
+func (mr *MockAppTransactionInterfaceMockRecorder) ReadScheduled(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadScheduled", reflect.TypeOf((*MockAppTransactionInterface)(nil).ReadScheduled), ctx)
+}
+
+// ReadSplit mocks base method.
+func (m *MockAppTransactionInterface) ReadSplit(ctx context.Context, id string) (*entity.Split, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadSplit", ctx, id)
+	ret0, _ := ret[0].(*entity.Split)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadSplit indicates an expected call of ReadSplit.
+func (mr *MockAppTransactionInterfaceMockRecorder) ReadSplit(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadSplit", reflect.TypeOf((*MockAppTransactionInterface)(nil).ReadSplit), ctx, id)
+}
+
+// Reverse mocks base method.
+func (m *MockAppTransactionInterface) Reverse(ctx context.Context, id string, amount money.Money) (*entity.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reverse", ctx, id, amount)
+	ret0, _ := ret[0].(*entity.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reverse indicates an expected call of Reverse.
+func (mr *MockAppTransactionInterfaceMockRecorder) Reverse(ctx, id, amount interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reverse", reflect.TypeOf((*MockAppTransactionInterface)(nil).Reverse), ctx, id, amount)
+}
+
+// Void mocks base method.
+func (m *MockAppTransactionInterface) Void(ctx context.Context, id string) (*entity.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Void", ctx, id)
+	ret0, _ := ret[0].(*entity.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Void indicates an expected call of Void.
+func (mr *MockAppTransactionInterfaceMockRecorder) Void(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Void", reflect.TypeOf((*MockAppTransactionInterface)(nil).Void), ctx, id)
+}
+
+// Withdraw mocks base method.
+func (m *MockAppTransactionInterface) Withdraw(ctx context.Context, transaction *entity.Transaction) (*entity.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Withdraw", ctx, transaction)
+	ret0, _ := ret[0].(*entity.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Withdraw indicates an expected call of Withdraw.
+func (mr *MockAppTransactionInterfaceMockRecorder) Withdraw(ctx, transaction interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Withdraw", reflect.TypeOf((*MockAppTransactionInterface)(nil).Withdraw), ctx, transaction)
+}
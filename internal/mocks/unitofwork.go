@@ -0,0 +1,50 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/database/unitofwork.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	database "github.com/garoque/backend-code-challenge-snapfi/internal/database"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockUnitOfWorkInterface is a mock of UnitOfWorkInterface interface.
+type MockUnitOfWorkInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockUnitOfWorkInterfaceMockRecorder
+}
+
+// MockUnitOfWorkInterfaceMockRecorder is the mock recorder for MockUnitOfWorkInterface.
+type MockUnitOfWorkInterfaceMockRecorder struct {
+	mock *MockUnitOfWorkInterface
+}
+
+// NewMockUnitOfWorkInterface creates a new mock instance.
+func NewMockUnitOfWorkInterface(ctrl *gomock.Controller) *MockUnitOfWorkInterface {
+	mock := &MockUnitOfWorkInterface{ctrl: ctrl}
+	mock.recorder = &MockUnitOfWorkInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUnitOfWorkInterface) EXPECT() *MockUnitOfWorkInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Do mocks base method.
+func (m *MockUnitOfWorkInterface) Do(ctx context.Context, fn func(*database.Container) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Do", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Do indicates an expected call of Do.
+func (mr *MockUnitOfWorkInterfaceMockRecorder) Do(ctx, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Do", reflect.TypeOf((*MockUnitOfWorkInterface)(nil).Do), ctx, fn)
+}
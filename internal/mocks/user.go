@@ -49,6 +49,20 @@ func (mr *MockDabataseUserInterfaceMockRecorder) Create(ctx, user interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockDabataseUserInterface)(nil).Create), ctx, user)
 }
 
+// CreateStatusChange mocks base method.
+func (m *MockDabataseUserInterface) CreateStatusChange(ctx context.Context, change *entity.StatusChange) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateStatusChange", ctx, change)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateStatusChange indicates an expected call of CreateStatusChange.
+func (mr *MockDabataseUserInterfaceMockRecorder) CreateStatusChange(ctx, change interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateStatusChange", reflect.TypeOf((*MockDabataseUserInterface)(nil).CreateStatusChange), ctx, change)
+}
+
 // ReadAll mocks base method.
 func (m *MockDabataseUserInterface) ReadAll(ctx context.Context) ([]entity.User, error) {
 	m.ctrl.T.Helper()
@@ -78,3 +92,47 @@ func (mr *MockDabataseUserInterfaceMockRecorder) ReadOneById(ctx, userId interfa
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadOneById", reflect.TypeOf((*MockDabataseUserInterface)(nil).ReadOneById), ctx, userId)
 }
+
+// ReadOneByIdForUpdate mocks base method.
+func (m *MockDabataseUserInterface) ReadOneByIdForUpdate(ctx context.Context, userId string) (*entity.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadOneByIdForUpdate", ctx, userId)
+	ret0, _ := ret[0].(*entity.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadOneByIdForUpdate indicates an expected call of ReadOneByIdForUpdate.
+func (mr *MockDabataseUserInterfaceMockRecorder) ReadOneByIdForUpdate(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadOneByIdForUpdate", reflect.TypeOf((*MockDabataseUserInterface)(nil).ReadOneByIdForUpdate), ctx, userId)
+}
+
+// ReadStatusHistory mocks base method.
+func (m *MockDabataseUserInterface) ReadStatusHistory(ctx context.Context, userId string) ([]entity.StatusChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadStatusHistory", ctx, userId)
+	ret0, _ := ret[0].([]entity.StatusChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadStatusHistory indicates an expected call of ReadStatusHistory.
+func (mr *MockDabataseUserInterfaceMockRecorder) ReadStatusHistory(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadStatusHistory", reflect.TypeOf((*MockDabataseUserInterface)(nil).ReadStatusHistory), ctx, userId)
+}
+
+// UpdateStatus mocks base method.
+func (m *MockDabataseUserInterface) UpdateStatus(ctx context.Context, userId string, status entity.StatusUser) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStatus", ctx, userId, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateStatus indicates an expected call of UpdateStatus.
+func (mr *MockDabataseUserInterfaceMockRecorder) UpdateStatus(ctx, userId, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockDabataseUserInterface)(nil).UpdateStatus), ctx, userId, status)
+}
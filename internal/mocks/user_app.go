@@ -8,6 +8,7 @@ import (
 	context "context"
 	reflect "reflect"
 
+	dto "github.com/garoque/backend-code-challenge-snapfi/internal/api/dto"
 	entity "github.com/garoque/backend-code-challenge-snapfi/internal/entity"
 	gomock "github.com/golang/mock/gomock"
 )
@@ -35,6 +36,21 @@ func (m *MockAppUserInterface) EXPECT() *MockAppUserInterfaceMockRecorder {
 	return m.recorder
 }
 
+// Close mocks base method.
+func (m *MockAppUserInterface) Close(ctx context.Context, userId string, request dto.CloseUser) (*entity.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close", ctx, userId, request)
+	ret0, _ := ret[0].(*entity.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockAppUserInterfaceMockRecorder) Close(ctx, userId, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockAppUserInterface)(nil).Close), ctx, userId, request)
+}
+
 // Create mocks base method.
 func (m *MockAppUserInterface) Create(ctx context.Context, user entity.User) error {
 	m.ctrl.T.Helper()
@@ -49,6 +65,21 @@ func (mr *MockAppUserInterfaceMockRecorder) Create(ctx, user interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAppUserInterface)(nil).Create), ctx, user)
 }
 
+// Freeze mocks base method.
+func (m *MockAppUserInterface) Freeze(ctx context.Context, userId string, request dto.UpdateUserStatus) (*entity.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Freeze", ctx, userId, request)
+	ret0, _ := ret[0].(*entity.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Freeze indicates an expected call of Freeze.
+func (mr *MockAppUserInterfaceMockRecorder) Freeze(ctx, userId, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Freeze", reflect.TypeOf((*MockAppUserInterface)(nil).Freeze), ctx, userId, request)
+}
+
 // ReadAll mocks base method.
 func (m *MockAppUserInterface) ReadAll(ctx context.Context) ([]entity.User, error) {
 	m.ctrl.T.Helper()
@@ -78,3 +109,48 @@ func (mr *MockAppUserInterfaceMockRecorder) ReadOneById(ctx, userId interface{})
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadOneById", reflect.TypeOf((*MockAppUserInterface)(nil).ReadOneById), ctx, userId)
 }
+
+// ReadStatusHistory mocks base method.
+func (m *MockAppUserInterface) ReadStatusHistory(ctx context.Context, userId string) ([]entity.StatusChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadStatusHistory", ctx, userId)
+	ret0, _ := ret[0].([]entity.StatusChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadStatusHistory indicates an expected call of ReadStatusHistory.
+func (mr *MockAppUserInterfaceMockRecorder) ReadStatusHistory(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadStatusHistory", reflect.TypeOf((*MockAppUserInterface)(nil).ReadStatusHistory), ctx, userId)
+}
+
+// Unfreeze mocks base method.
+func (m *MockAppUserInterface) Unfreeze(ctx context.Context, userId string, request dto.UpdateUserStatus) (*entity.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unfreeze", ctx, userId, request)
+	ret0, _ := ret[0].(*entity.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Unfreeze indicates an expected call of Unfreeze.
+func (mr *MockAppUserInterfaceMockRecorder) Unfreeze(ctx, userId, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unfreeze", reflect.TypeOf((*MockAppUserInterface)(nil).Unfreeze), ctx, userId, request)
+}
+
+// UpdateCreditLine mocks base method.
+func (m *MockAppUserInterface) UpdateCreditLine(ctx context.Context, userId string, creditLine dto.UpdateCreditLine) (*entity.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCreditLine", ctx, userId, creditLine)
+	ret0, _ := ret[0].(*entity.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCreditLine indicates an expected call of UpdateCreditLine.
+func (mr *MockAppUserInterfaceMockRecorder) UpdateCreditLine(ctx, userId, creditLine interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCreditLine", reflect.TypeOf((*MockAppUserInterface)(nil).UpdateCreditLine), ctx, userId, creditLine)
+}
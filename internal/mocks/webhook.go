@@ -0,0 +1,197 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/database/webhook/webhook.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	entity "github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockDabataseWebhookInterface is a mock of DabataseWebhookInterface interface.
+type MockDabataseWebhookInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockDabataseWebhookInterfaceMockRecorder
+}
+
+// MockDabataseWebhookInterfaceMockRecorder is the mock recorder for MockDabataseWebhookInterface.
+type MockDabataseWebhookInterfaceMockRecorder struct {
+	mock *MockDabataseWebhookInterface
+}
+
+// NewMockDabataseWebhookInterface creates a new mock instance.
+func NewMockDabataseWebhookInterface(ctrl *gomock.Controller) *MockDabataseWebhookInterface {
+	mock := &MockDabataseWebhookInterface{ctrl: ctrl}
+	mock.recorder = &MockDabataseWebhookInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDabataseWebhookInterface) EXPECT() *MockDabataseWebhookInterfaceMockRecorder {
+	return m.recorder
+}
+
+// CreateDelivery mocks base method.
+func (m *MockDabataseWebhookInterface) CreateDelivery(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDelivery", ctx, delivery)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateDelivery indicates an expected call of CreateDelivery.
+func (mr *MockDabataseWebhookInterfaceMockRecorder) CreateDelivery(ctx, delivery interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDelivery", reflect.TypeOf((*MockDabataseWebhookInterface)(nil).CreateDelivery), ctx, delivery)
+}
+
+// CreateSubscription mocks base method.
+func (m *MockDabataseWebhookInterface) CreateSubscription(ctx context.Context, subscription *entity.WebhookSubscription) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSubscription", ctx, subscription)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateSubscription indicates an expected call of CreateSubscription.
+func (mr *MockDabataseWebhookInterfaceMockRecorder) CreateSubscription(ctx, subscription interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSubscription", reflect.TypeOf((*MockDabataseWebhookInterface)(nil).CreateSubscription), ctx, subscription)
+}
+
+// DeactivateSubscription mocks base method.
+func (m *MockDabataseWebhookInterface) DeactivateSubscription(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeactivateSubscription", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeactivateSubscription indicates an expected call of DeactivateSubscription.
+func (mr *MockDabataseWebhookInterfaceMockRecorder) DeactivateSubscription(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeactivateSubscription", reflect.TypeOf((*MockDabataseWebhookInterface)(nil).DeactivateSubscription), ctx, id)
+}
+
+// ReadDeliveries mocks base method.
+func (m *MockDabataseWebhookInterface) ReadDeliveries(ctx context.Context, subscriptionId string) ([]entity.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadDeliveries", ctx, subscriptionId)
+	ret0, _ := ret[0].([]entity.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadDeliveries indicates an expected call of ReadDeliveries.
+func (mr *MockDabataseWebhookInterfaceMockRecorder) ReadDeliveries(ctx, subscriptionId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadDeliveries", reflect.TypeOf((*MockDabataseWebhookInterface)(nil).ReadDeliveries), ctx, subscriptionId)
+}
+
+// ReadDeliveryById mocks base method.
+func (m *MockDabataseWebhookInterface) ReadDeliveryById(ctx context.Context, id string) (*entity.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadDeliveryById", ctx, id)
+	ret0, _ := ret[0].(*entity.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadDeliveryById indicates an expected call of ReadDeliveryById.
+func (mr *MockDabataseWebhookInterfaceMockRecorder) ReadDeliveryById(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadDeliveryById", reflect.TypeOf((*MockDabataseWebhookInterface)(nil).ReadDeliveryById), ctx, id)
+}
+
+// ReadDueDeliveries mocks base method.
+func (m *MockDabataseWebhookInterface) ReadDueDeliveries(ctx context.Context, now time.Time, limit int) ([]entity.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadDueDeliveries", ctx, now, limit)
+	ret0, _ := ret[0].([]entity.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadDueDeliveries indicates an expected call of ReadDueDeliveries.
+func (mr *MockDabataseWebhookInterfaceMockRecorder) ReadDueDeliveries(ctx, now, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadDueDeliveries", reflect.TypeOf((*MockDabataseWebhookInterface)(nil).ReadDueDeliveries), ctx, now, limit)
+}
+
+// ReadSubscriptionById mocks base method.
+func (m *MockDabataseWebhookInterface) ReadSubscriptionById(ctx context.Context, id string) (*entity.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadSubscriptionById", ctx, id)
+	ret0, _ := ret[0].(*entity.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadSubscriptionById indicates an expected call of ReadSubscriptionById.
+func (mr *MockDabataseWebhookInterfaceMockRecorder) ReadSubscriptionById(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadSubscriptionById", reflect.TypeOf((*MockDabataseWebhookInterface)(nil).ReadSubscriptionById), ctx, id)
+}
+
+// ReadSubscriptions mocks base method.
+func (m *MockDabataseWebhookInterface) ReadSubscriptions(ctx context.Context) ([]entity.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadSubscriptions", ctx)
+	ret0, _ := ret[0].([]entity.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadSubscriptions indicates an expected call of ReadSubscriptions.
+func (mr *MockDabataseWebhookInterfaceMockRecorder) ReadSubscriptions(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadSubscriptions", reflect.TypeOf((*MockDabataseWebhookInterface)(nil).ReadSubscriptions), ctx)
+}
+
+// ReadSubscriptionsByEventType mocks base method.
+func (m *MockDabataseWebhookInterface) ReadSubscriptionsByEventType(ctx context.Context, eventType string) ([]entity.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadSubscriptionsByEventType", ctx, eventType)
+	ret0, _ := ret[0].([]entity.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadSubscriptionsByEventType indicates an expected call of ReadSubscriptionsByEventType.
+func (mr *MockDabataseWebhookInterfaceMockRecorder) ReadSubscriptionsByEventType(ctx, eventType interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadSubscriptionsByEventType", reflect.TypeOf((*MockDabataseWebhookInterface)(nil).ReadSubscriptionsByEventType), ctx, eventType)
+}
+
+// UpdateDelivery mocks base method.
+func (m *MockDabataseWebhookInterface) UpdateDelivery(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateDelivery", ctx, delivery)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateDelivery indicates an expected call of UpdateDelivery.
+func (mr *MockDabataseWebhookInterfaceMockRecorder) UpdateDelivery(ctx, delivery interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDelivery", reflect.TypeOf((*MockDabataseWebhookInterface)(nil).UpdateDelivery), ctx, delivery)
+}
+
+// UpdateNextAttemptAt mocks base method.
+func (m *MockDabataseWebhookInterface) UpdateNextAttemptAt(ctx context.Context, ids []string, nextAttemptAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateNextAttemptAt", ctx, ids, nextAttemptAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateNextAttemptAt indicates an expected call of UpdateNextAttemptAt.
+func (mr *MockDabataseWebhookInterfaceMockRecorder) UpdateNextAttemptAt(ctx, ids, nextAttemptAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNextAttemptAt", reflect.TypeOf((*MockDabataseWebhookInterface)(nil).UpdateNextAttemptAt), ctx, ids, nextAttemptAt)
+}
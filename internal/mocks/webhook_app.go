@@ -0,0 +1,154 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/app/webhook/webhook.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockAppWebhookInterface is a mock of AppWebhookInterface interface.
+type MockAppWebhookInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockAppWebhookInterfaceMockRecorder
+}
+
+// MockAppWebhookInterfaceMockRecorder is the mock recorder for MockAppWebhookInterface.
+type MockAppWebhookInterfaceMockRecorder struct {
+	mock *MockAppWebhookInterface
+}
+
+// NewMockAppWebhookInterface creates a new mock instance.
+func NewMockAppWebhookInterface(ctrl *gomock.Controller) *MockAppWebhookInterface {
+	mock := &MockAppWebhookInterface{ctrl: ctrl}
+	mock.recorder = &MockAppWebhookInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAppWebhookInterface) EXPECT() *MockAppWebhookInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockAppWebhookInterface) Create(ctx context.Context, subscription *entity.WebhookSubscription) (*entity.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, subscription)
+	ret0, _ := ret[0].(*entity.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAppWebhookInterfaceMockRecorder) Create(ctx, subscription interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAppWebhookInterface)(nil).Create), ctx, subscription)
+}
+
+// Delete mocks base method.
+func (m *MockAppWebhookInterface) Delete(ctx context.Context, id string) (*entity.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(*entity.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockAppWebhookInterfaceMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockAppWebhookInterface)(nil).Delete), ctx, id)
+}
+
+// Deliver mocks base method.
+func (m *MockAppWebhookInterface) Deliver(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Deliver", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Deliver indicates an expected call of Deliver.
+func (mr *MockAppWebhookInterfaceMockRecorder) Deliver(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Deliver", reflect.TypeOf((*MockAppWebhookInterface)(nil).Deliver), ctx)
+}
+
+// Enqueue mocks base method.
+func (m *MockAppWebhookInterface) Enqueue(ctx context.Context, event entity.Event) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Enqueue", ctx, event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Enqueue indicates an expected call of Enqueue.
+func (mr *MockAppWebhookInterfaceMockRecorder) Enqueue(ctx, event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enqueue", reflect.TypeOf((*MockAppWebhookInterface)(nil).Enqueue), ctx, event)
+}
+
+// ReadAll mocks base method.
+func (m *MockAppWebhookInterface) ReadAll(ctx context.Context) ([]entity.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadAll", ctx)
+	ret0, _ := ret[0].([]entity.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadAll indicates an expected call of ReadAll.
+func (mr *MockAppWebhookInterfaceMockRecorder) ReadAll(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadAll", reflect.TypeOf((*MockAppWebhookInterface)(nil).ReadAll), ctx)
+}
+
+// ReadDeliveries mocks base method.
+func (m *MockAppWebhookInterface) ReadDeliveries(ctx context.Context, id string) ([]entity.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadDeliveries", ctx, id)
+	ret0, _ := ret[0].([]entity.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadDeliveries indicates an expected call of ReadDeliveries.
+func (mr *MockAppWebhookInterfaceMockRecorder) ReadDeliveries(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadDeliveries", reflect.TypeOf((*MockAppWebhookInterface)(nil).ReadDeliveries), ctx, id)
+}
+
+// ReadOneById mocks base method.
+func (m *MockAppWebhookInterface) ReadOneById(ctx context.Context, id string) (*entity.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadOneById", ctx, id)
+	ret0, _ := ret[0].(*entity.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadOneById indicates an expected call of ReadOneById.
+func (mr *MockAppWebhookInterfaceMockRecorder) ReadOneById(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadOneById", reflect.TypeOf((*MockAppWebhookInterface)(nil).ReadOneById), ctx, id)
+}
+
+// Redeliver mocks base method.
+func (m *MockAppWebhookInterface) Redeliver(ctx context.Context, id, deliveryId string) (*entity.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Redeliver", ctx, id, deliveryId)
+	ret0, _ := ret[0].(*entity.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Redeliver indicates an expected call of Redeliver.
+func (mr *MockAppWebhookInterfaceMockRecorder) Redeliver(ctx, id, deliveryId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Redeliver", reflect.TypeOf((*MockAppWebhookInterface)(nil).Redeliver), ctx, id, deliveryId)
+}
@@ -0,0 +1,42 @@
+package publisher
+
+import (
+	"context"
+	"sync"
+
+	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+)
+
+type Handler func(ctx context.Context, event entity.Event) error
+
+// Memory hands the events to the handlers subscribed in the same process, in
+// the order they subscribed. It stops at the first handler that fails, so the
+// event is relayed again, to every handler.
+type Memory struct {
+	mu       sync.RWMutex
+	handlers []Handler
+}
+
+func NewMemory() *Memory {
+	return &Memory{}
+}
+
+func (m *Memory) Subscribe(handler Handler) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.handlers = append(m.handlers, handler)
+}
+
+func (m *Memory) Publish(ctx context.Context, event entity.Event) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, handler := range m.handlers {
+		if err := handler(ctx, event); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,48 @@
+package publisher
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+)
+
+// NATS writes the events as messages of the NATS client protocol, HPUB
+// frames with the event as a JSON body and its ID in the Nats-Msg-Id header,
+// which JetStream uses to drop redeliveries. The writer can be a file, whose
+// frames are replayed into NATS later, or a connection to a NATS server.
+type NATS struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func NewNATS(w io.Writer) *NATS {
+	return &NATS{w: w}
+}
+
+func (n *NATS) Publish(ctx context.Context, event entity.Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	header := "NATS/1.0\r\nNats-Msg-Id: " + event.ID + "\r\n\r\n"
+	frame := fmt.Sprintf("HPUB %s %d %d\r\n%s%s\r\n", Subject(event), len(header), len(header)+len(body), header, body)
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if _, err := io.WriteString(n.w, frame); err != nil {
+		return err
+	}
+
+	// A file must reach the disk before the event is recorded as published.
+	if syncer, ok := n.w.(interface{ Sync() error }); ok {
+		return syncer.Sync()
+	}
+
+	return nil
+}
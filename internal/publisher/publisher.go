@@ -0,0 +1,25 @@
+// Package publisher delivers the domain events relayed from the outbox to the
+// services reacting to them.
+package publisher
+
+import (
+	"context"
+
+	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+)
+
+// SubjectPrefix comes before the type of the event in the subject it is
+// published on.
+const SubjectPrefix = "snapfi."
+
+// Publisher publishes a domain event. Events may be published more than once,
+// when the relay fails before recording them as published, so consumers must
+// deduplicate them by ID.
+type Publisher interface {
+	Publish(ctx context.Context, event entity.Event) error
+}
+
+// Subject is where the event is published, such as snapfi.transaction.booked.
+func Subject(event entity.Event) string {
+	return SubjectPrefix + event.Type
+}
@@ -0,0 +1,61 @@
+package publisher
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/garoque/backend-code-challenge-snapfi/internal/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func newEvent() entity.Event {
+	return entity.Event{
+		Sequence:      1,
+		ID:            "event-id",
+		Type:          "transaction.booked",
+		AggregateType: entity.AGGREGATE_TRANSACTION,
+		AggregateId:   "transaction-id",
+		Payload:       json.RawMessage(`{"to":"BOOKED"}`),
+		CreatedAt:     time.Date(2023, 5, 19, 9, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestMemory(t *testing.T) {
+	memory := NewMemory()
+
+	received := make([]string, 0)
+	memory.Subscribe(func(ctx context.Context, event entity.Event) error {
+		received = append(received, "first:"+event.ID)
+		return nil
+	})
+	memory.Subscribe(func(ctx context.Context, event entity.Event) error {
+		received = append(received, "second:"+event.ID)
+		return nil
+	})
+
+	assert.NoError(t, memory.Publish(context.Background(), newEvent()))
+	assert.Equal(t, []string{"first:event-id", "second:event-id"}, received)
+
+	failure := errors.New("handler failed")
+	memory.Subscribe(func(ctx context.Context, event entity.Event) error {
+		return failure
+	})
+	assert.Equal(t, failure, memory.Publish(context.Background(), newEvent()))
+}
+
+func TestNATS(t *testing.T) {
+	var buffer bytes.Buffer
+
+	err := NewNATS(&buffer).Publish(context.Background(), newEvent())
+	assert.NoError(t, err)
+
+	body := `{"id":"event-id","type":"transaction.booked","aggregateType":"transaction","aggregateId":"transaction-id","payload":{"to":"BOOKED"},"createdAt":"2023-05-19T09:00:00Z"}`
+	header := "NATS/1.0\r\nNats-Msg-Id: event-id\r\n\r\n"
+	assert.Equal(t, "HPUB snapfi.transaction.booked 35 202\r\n"+header+body+"\r\n", buffer.String())
+	assert.Equal(t, 35, len(header))
+	assert.Equal(t, 202, len(header)+len(body))
+}
@@ -0,0 +1,48 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduler(t *testing.T) {
+	var runs, failures int32
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	s := New(
+		Job{
+			Name:     "ok",
+			Interval: time.Millisecond,
+			Run: func(ctx context.Context) error {
+				atomic.AddInt32(&runs, 1)
+				return nil
+			},
+		},
+		Job{
+			Name:     "failing",
+			Interval: time.Millisecond,
+			Run: func(ctx context.Context) error {
+				atomic.AddInt32(&failures, 1)
+				return errors.New("failed")
+			},
+		},
+	)
+	s.Start(ctx)
+
+	assert.Eventually(t, func() bool {
+		return atomic.LoadInt32(&runs) >= 2 && atomic.LoadInt32(&failures) >= 2
+	}, time.Second, time.Millisecond)
+
+	cancel()
+	s.Wait()
+
+	stoppedAt := atomic.LoadInt32(&runs)
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, stoppedAt, atomic.LoadInt32(&runs))
+}
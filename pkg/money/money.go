@@ -0,0 +1,211 @@
+package money
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DefaultCurrency is the currency of every amount stored by the API.
+const DefaultCurrency = "BRL"
+
+var (
+	ErrInvalidAmount    = errors.New("invalid amount, expected a decimal such as \"100.10\"")
+	ErrTooManyDecimals  = errors.New("amount has more than two decimal places")
+	ErrOverflow         = errors.New("amount is out of the supported range")
+	ErrCurrencyMismatch = errors.New("amounts have different currencies")
+)
+
+// Money is an amount in minor units (cents) of a currency. It is encoded as a
+// decimal string in JSON and as an integer number of cents in the database.
+type Money struct {
+	Amount   int64
+	Currency string
+}
+
+// New returns an amount of cents in the default currency.
+func New(cents int64) Money {
+	return Money{Amount: cents, Currency: DefaultCurrency}
+}
+
+// Parse reads a decimal amount such as "100.10" or "-3.5" in the default
+// currency. Amounts with more than two decimal places are rejected.
+func Parse(value string) (Money, error) {
+	s := value
+	negative := strings.HasPrefix(s, "-")
+	if negative {
+		s = s[1:]
+	}
+
+	units, fraction, hasFraction := strings.Cut(s, ".")
+	if units == "" || (hasFraction && fraction == "") || !isDigits(units) || !isDigits(fraction) {
+		return Money{}, ErrInvalidAmount
+	}
+
+	if len(fraction) > 2 {
+		return Money{}, ErrTooManyDecimals
+	}
+
+	fraction += strings.Repeat("0", 2-len(fraction))
+
+	cents, err := strconv.ParseInt(units+fraction, 10, 64)
+	if err != nil {
+		return Money{}, ErrOverflow
+	}
+
+	if negative {
+		cents = -cents
+	}
+
+	return New(cents), nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (m Money) currency() string {
+	if m.Currency == "" {
+		return DefaultCurrency
+	}
+
+	return m.Currency
+}
+
+func (m Money) checkCurrency(other Money) error {
+	if m.currency() != other.currency() {
+		return ErrCurrencyMismatch
+	}
+
+	return nil
+}
+
+// Add returns m + other, or ErrOverflow if the result doesn't fit.
+func (m Money) Add(other Money) (Money, error) {
+	if err := m.checkCurrency(other); err != nil {
+		return Money{}, err
+	}
+
+	sum := m.Amount + other.Amount
+	if (other.Amount > 0 && sum < m.Amount) || (other.Amount < 0 && sum > m.Amount) {
+		return Money{}, ErrOverflow
+	}
+
+	return Money{Amount: sum, Currency: m.currency()}, nil
+}
+
+// Sub returns m - other, or ErrOverflow if the result doesn't fit.
+func (m Money) Sub(other Money) (Money, error) {
+	if err := m.checkCurrency(other); err != nil {
+		return Money{}, err
+	}
+
+	diff := m.Amount - other.Amount
+	if (other.Amount > 0 && diff > m.Amount) || (other.Amount < 0 && diff < m.Amount) {
+		return Money{}, ErrOverflow
+	}
+
+	return Money{Amount: diff, Currency: m.currency()}, nil
+}
+
+// Neg returns -m.
+func (m Money) Neg() Money {
+	return Money{Amount: -m.Amount, Currency: m.currency()}
+}
+
+func (m Money) LessThan(other Money) bool {
+	return m.Amount < other.Amount
+}
+
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+func (m Money) IsPositive() bool {
+	return m.Amount > 0
+}
+
+func (m Money) IsNegative() bool {
+	return m.Amount < 0
+}
+
+// String formats the amount as a decimal with two places, e.g. "-0.05".
+func (m Money) String() string {
+	sign := ""
+	cents := uint64(m.Amount)
+	if m.Amount < 0 {
+		sign = "-"
+		cents = uint64(-(m.Amount + 1)) + 1
+	}
+
+	return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(m.String())), nil
+}
+
+// UnmarshalJSON accepts both decimal strings and plain JSON numbers. Numbers
+// are parsed from their literal text, so they never go through float64.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	value := string(data)
+	if strings.HasPrefix(value, `"`) {
+		unquoted, err := strconv.Unquote(value)
+		if err != nil {
+			return ErrInvalidAmount
+		}
+		value = unquoted
+	}
+
+	parsed, err := Parse(value)
+	if err != nil {
+		return err
+	}
+
+	*m = parsed
+	return nil
+}
+
+func (m Money) Value() (driver.Value, error) {
+	return m.Amount, nil
+}
+
+func (m *Money) Scan(src interface{}) error {
+	switch value := src.(type) {
+	case int64:
+		*m = New(value)
+	case []byte:
+		return m.scanString(string(value))
+	case string:
+		return m.scanString(value)
+	case nil:
+		*m = New(0)
+	default:
+		return fmt.Errorf("money: cannot scan %T", src)
+	}
+
+	return nil
+}
+
+func (m *Money) scanString(value string) error {
+	cents, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return fmt.Errorf("money: cannot scan %q: %w", value, err)
+	}
+
+	*m = New(cents)
+	return nil
+}
@@ -0,0 +1,99 @@
+package money
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	cases := map[string]struct {
+		Input          string
+		ExpectedResult Money
+		ExpectedErr    error
+	}{
+		"deve retornar sucesso: com duas casas":    {Input: "100.10", ExpectedResult: New(10010)},
+		"deve retornar sucesso: com uma casa":      {Input: "3.5", ExpectedResult: New(350)},
+		"deve retornar sucesso: sem casas":         {Input: "42", ExpectedResult: New(4200)},
+		"deve retornar sucesso: negativo":          {Input: "-0.05", ExpectedResult: New(-5)},
+		"deve retornar erro: mais de duas casas":   {Input: "1.001", ExpectedErr: ErrTooManyDecimals},
+		"deve retornar erro: vazio":                {Input: "", ExpectedErr: ErrInvalidAmount},
+		"deve retornar erro: notação científica":   {Input: "1e3", ExpectedErr: ErrInvalidAmount},
+		"deve retornar erro: ponto sem casas":      {Input: "10.", ExpectedErr: ErrInvalidAmount},
+		"deve retornar erro: fora do intervalo":    {Input: "92233720368547758.08", ExpectedErr: ErrOverflow},
+		"deve retornar erro: caracteres inválidos": {Input: "1,50", ExpectedErr: ErrInvalidAmount},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			result, err := Parse(cs.Input)
+			assert.Equal(t, cs.ExpectedErr, err)
+			assert.Equal(t, cs.ExpectedResult, result)
+		})
+	}
+}
+
+func TestAddSub(t *testing.T) {
+	sum, err := New(150).Add(New(50))
+	assert.NoError(t, err)
+	assert.Equal(t, New(200), sum)
+
+	diff, err := New(150).Sub(New(200))
+	assert.NoError(t, err)
+	assert.Equal(t, New(-50), diff)
+
+	_, err = New(math.MaxInt64).Add(New(1))
+	assert.Equal(t, ErrOverflow, err)
+
+	_, err = New(math.MinInt64).Sub(New(1))
+	assert.Equal(t, ErrOverflow, err)
+
+	_, err = New(100).Add(Money{Amount: 100, Currency: "USD"})
+	assert.Equal(t, ErrCurrencyMismatch, err)
+}
+
+func TestString(t *testing.T) {
+	assert.Equal(t, "100.10", New(10010).String())
+	assert.Equal(t, "0.05", New(5).String())
+	assert.Equal(t, "-0.05", New(-5).String())
+	assert.Equal(t, "-92233720368547758.08", New(math.MinInt64).String())
+}
+
+func TestJSON(t *testing.T) {
+	var request struct {
+		Amount Money `json:"amount"`
+	}
+
+	err := json.Unmarshal([]byte(`{"amount": "100.10"}`), &request)
+	assert.NoError(t, err)
+	assert.Equal(t, New(10010), request.Amount)
+
+	err = json.Unmarshal([]byte(`{"amount": 0.1}`), &request)
+	assert.NoError(t, err)
+	assert.Equal(t, New(10), request.Amount)
+
+	err = json.Unmarshal([]byte(`{"amount": "0.001"}`), &request)
+	assert.ErrorIs(t, err, ErrTooManyDecimals)
+
+	result, err := json.Marshal(request)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"amount":"0.10"}`, string(result))
+}
+
+func TestScan(t *testing.T) {
+	var m Money
+
+	assert.NoError(t, m.Scan(int64(10010)))
+	assert.Equal(t, New(10010), m)
+
+	assert.NoError(t, m.Scan([]byte("-250")))
+	assert.Equal(t, New(-250), m)
+
+	assert.Error(t, m.Scan([]byte("2.50")))
+
+	value, err := New(10010).Value()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(10010), value)
+}